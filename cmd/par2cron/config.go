@@ -27,6 +27,36 @@ type configFile struct {
 }
 
 func (cfg *configFile) Validate() error {
+	type sectionArgs struct {
+		args    *[]string
+		allowed *[]string
+	}
+
+	var par2Args []sectionArgs
+	if cfg.Create != nil {
+		par2Args = append(par2Args, sectionArgs{cfg.Create.Par2Args, cfg.Create.AllowedArgs})
+	}
+	if cfg.Verify != nil {
+		par2Args = append(par2Args, sectionArgs{cfg.Verify.Par2Args, cfg.Verify.AllowedArgs})
+	}
+	if cfg.Repair != nil {
+		par2Args = append(par2Args, sectionArgs{cfg.Repair.Par2Args, cfg.Repair.AllowedArgs})
+	}
+	for _, sa := range par2Args {
+		allowed := slices.Clone(util.DefaultAllowedPar2Args)
+		if sa.allowed != nil {
+			if err := util.ValidateAllowedPar2Args(*sa.allowed); err != nil {
+				return fmt.Errorf("allowed-args: %w", err)
+			}
+			allowed = append(allowed, *sa.allowed...)
+		}
+		if sa.args != nil {
+			if err := util.CheckPar2Args(*sa.args, allowed); err != nil {
+				return fmt.Errorf("args: %w", err)
+			}
+		}
+	}
+
 	if cfg.Create != nil && cfg.Create.Par2Glob != nil {
 		if ok := doublestar.ValidatePattern(*cfg.Create.Par2Glob); !ok {
 			return fmt.Errorf("glob: %w", doublestar.ErrBadPattern)
@@ -41,6 +71,17 @@ func (cfg *configFile) Validate() error {
 		}
 	}
 
+	if cfg.Create != nil && cfg.Create.GlobExclude != nil {
+		for _, pattern := range *cfg.Create.GlobExclude {
+			if ok := doublestar.ValidatePattern(pattern); !ok || pattern == "" {
+				return fmt.Errorf("glob-exclude: %w", doublestar.ErrBadPattern)
+			}
+			if cfg.Create.Par2Mode != nil && cfg.Create.Par2Mode.Value == schema.CreateRecursiveMode && util.IsGlobRecursive(pattern) {
+				return schema.ErrUnsupportedGlob
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -71,29 +112,79 @@ func parseConfigFile(fsys afero.Fs, path string) (*configFile, error) {
 }
 
 type configFileCreate struct {
-	Par2Args *[]string `yaml:"args"`
-
-	Par2Glob    *string           `yaml:"glob"`
-	Par2Verify  *bool             `yaml:"verify"`
-	Par2Mode    *flags.CreateMode `yaml:"mode"`
-	MaxDuration *flags.Duration   `yaml:"duration"`
-	HideFiles   *bool             `yaml:"hidden"`
-	Bundle      *bool             `yaml:"bundle"`
-
-	Cgroup   *string         `yaml:"cgroup"`
-	LogLevel *flags.LogLevel `yaml:"log-level"`
-	SeqURL   *string         `yaml:"seq-url"`
-	SeqKey   *string         `yaml:"seq-key"`
-	WantJSON *bool           `yaml:"json"`
+	Par2Args    *[]string `yaml:"args"`
+	AllowedArgs *[]string `yaml:"allowed-args"`
+
+	Par2Glob      *string           `yaml:"glob"`
+	GlobExclude   *[]string         `yaml:"glob-exclude"`
+	Par2Verify    *bool             `yaml:"verify"`
+	Par2Mode      *flags.CreateMode `yaml:"mode"`
+	MaxDuration   *flags.Duration   `yaml:"duration"`
+	Limit         *int              `yaml:"limit"`
+	MaxErrors     *int              `yaml:"max-errors"`
+	HideFiles     *bool             `yaml:"hidden"`
+	Bundle        *bool             `yaml:"bundle"`
+	ExcludeEmpty  *bool             `yaml:"exclude-empty"`
+	AdoptExisting *bool             `yaml:"adopt-existing"`
+	StrictGlob    *bool             `yaml:"strict-glob"`
+	WriteStamp    *bool             `yaml:"write-stamp"`
+	StampFile     *string           `yaml:"stamp-file"`
+	WriteFileList *bool             `yaml:"write-file-list"`
+
+	ProtectCreationManifest *bool           `yaml:"protect-creation-manifest"`
+	TargetBlockSize         *flags.ByteSize `yaml:"target-block-size"`
+	MaxSetSize              *flags.ByteSize `yaml:"max-set-size"`
+	RetrySingleThreaded     *bool           `yaml:"retry-single-threaded"`
+	RecordMetadata          *bool           `yaml:"record-metadata"`
+	FolderFingerprint       *bool           `yaml:"folder-fingerprint"`
+
+	MinPar2Version     *flags.Version `yaml:"min-par2-version"`
+	RequirePar2Version *bool          `yaml:"require-par2-version"`
+	AllowRoot          *bool          `yaml:"allow-root"`
+	RefuseRoot         *bool          `yaml:"refuse-root"`
+	WarningsAsErrors   *bool          `yaml:"warnings-as-errors"`
+
+	Cgroup          *string              `yaml:"cgroup"`
+	IOThrottle      *flags.IOThrottle    `yaml:"io-throttle"`
+	Par2Env         *flags.EnvVars       `yaml:"par2-env"`
+	Par2Flavor      *flags.Par2Flavor    `yaml:"par2-flavor"`
+	TempDir         *string              `yaml:"temp-dir"`
+	SummaryFile     *string              `yaml:"summary-file"`
+	MetricsFile     *string              `yaml:"metrics-file"`
+	PauseFile       *string              `yaml:"pause-file"`
+	IgnoreFile      *string              `yaml:"ignore-file"`
+	IgnoreAllFile   *string              `yaml:"ignore-all-file"`
+	ManifestSuffix  *string              `yaml:"manifest-suffix"`
+	LockSuffix      *string              `yaml:"lock-suffix"`
+	HiddenSidecars  *bool                `yaml:"hidden-sidecars"`
+	MaxManifestSize *flags.ByteSize      `yaml:"max-manifest-size"`
+	MaxDepth        *flags.MaxDepth      `yaml:"max-depth"`
+	LogLevel        *flags.LogLevel      `yaml:"log-level"`
+	SeqURL          *string              `yaml:"seq-url"`
+	SeqKey          *string              `yaml:"seq-key"`
+	LogFile         *string              `yaml:"log-file"`
+	LogFileSize     *int                 `yaml:"log-file-size"`
+	LogFileKeep     *int                 `yaml:"log-file-keep"`
+	WantJSON        *bool                `yaml:"json"`
+	FlattenLogs     *bool                `yaml:"flatten-logs"`
+	Color           *flags.Color         `yaml:"color"`
+	ProgressBar     *bool                `yaml:"progress-bar"`
+	PathPrefixMap   *flags.PathPrefixMap `yaml:"path-prefix-map"`
 }
 
 func (yamlCfg *configFileCreate) Merge(cfg *create.Options, global *globalOptions, hasExternalArgs bool, setFlags map[string]bool) {
 	if yamlCfg.Par2Args != nil && !hasExternalArgs {
 		cfg.Par2Args = slices.Clone(*yamlCfg.Par2Args)
 	}
+	if yamlCfg.AllowedArgs != nil {
+		global.allowedPar2Args = slices.Clone(*yamlCfg.AllowedArgs)
+	}
 	if yamlCfg.Par2Glob != nil && !setFlags["glob"] {
 		cfg.Par2Glob = *yamlCfg.Par2Glob
 	}
+	if yamlCfg.GlobExclude != nil && !setFlags["glob-exclude"] {
+		cfg.Par2GlobExclude = slices.Clone(*yamlCfg.GlobExclude)
+	}
 	if yamlCfg.Par2Verify != nil && !setFlags["verify"] {
 		cfg.Par2Verify = *yamlCfg.Par2Verify
 	}
@@ -103,15 +194,114 @@ func (yamlCfg *configFileCreate) Merge(cfg *create.Options, global *globalOption
 	if yamlCfg.MaxDuration != nil && !setFlags["duration"] {
 		cfg.MaxDuration = *yamlCfg.MaxDuration
 	}
+	if yamlCfg.Limit != nil && !setFlags["limit"] {
+		cfg.Limit = *yamlCfg.Limit
+	}
+	if yamlCfg.MaxErrors != nil && !setFlags["max-errors"] {
+		cfg.MaxErrors = *yamlCfg.MaxErrors
+	}
 	if yamlCfg.HideFiles != nil && !setFlags["hidden"] {
 		cfg.HideFiles = *yamlCfg.HideFiles
 	}
 	if yamlCfg.Bundle != nil && !setFlags["bundle"] {
 		cfg.Bundle = *yamlCfg.Bundle
 	}
+	if yamlCfg.ExcludeEmpty != nil && !setFlags["exclude-empty"] {
+		cfg.ExcludeEmpty = *yamlCfg.ExcludeEmpty
+	}
+	if yamlCfg.AdoptExisting != nil && !setFlags["adopt-existing"] {
+		cfg.AdoptExisting = *yamlCfg.AdoptExisting
+	}
+	if yamlCfg.StrictGlob != nil && !setFlags["strict-glob"] {
+		cfg.StrictGlob = *yamlCfg.StrictGlob
+	}
+	if yamlCfg.WriteStamp != nil && !setFlags["write-stamp"] {
+		cfg.WriteStamp = *yamlCfg.WriteStamp
+	}
+	if yamlCfg.WriteFileList != nil && !setFlags["write-file-list"] {
+		cfg.WriteFileList = *yamlCfg.WriteFileList
+	}
+	if yamlCfg.ProtectCreationManifest != nil && !setFlags["protect-creation-manifest"] {
+		cfg.ProtectCreationManifest = *yamlCfg.ProtectCreationManifest
+	}
+	if yamlCfg.TargetBlockSize != nil && !setFlags["target-block-size"] {
+		cfg.TargetBlockSize = *yamlCfg.TargetBlockSize
+	}
+	if yamlCfg.MaxSetSize != nil && !setFlags["max-set-size"] {
+		cfg.MaxSetSize = *yamlCfg.MaxSetSize
+	}
+	if yamlCfg.RetrySingleThreaded != nil && !setFlags["retry-single-threaded"] {
+		cfg.RetrySingleThreaded = *yamlCfg.RetrySingleThreaded
+	}
+	if yamlCfg.RecordMetadata != nil && !setFlags["record-metadata"] {
+		cfg.RecordMetadata = *yamlCfg.RecordMetadata
+	}
+	if yamlCfg.FolderFingerprint != nil && !setFlags["folder-fingerprint"] {
+		cfg.FolderFingerprint = *yamlCfg.FolderFingerprint
+	}
+	if yamlCfg.StampFile != nil && !setFlags["stamp-file"] {
+		cfg.StampFile = *yamlCfg.StampFile
+	}
 	if yamlCfg.Cgroup != nil && !setFlags["cgroup"] {
 		global.cgroupPath = *yamlCfg.Cgroup
 	}
+	if yamlCfg.IOThrottle != nil && !setFlags["io-throttle"] {
+		global.ioThrottle = *yamlCfg.IOThrottle
+	}
+	if yamlCfg.Par2Env != nil && !setFlags["par2-env"] {
+		global.par2Env = *yamlCfg.Par2Env
+	}
+	if yamlCfg.Par2Flavor != nil && !setFlags["par2-flavor"] {
+		global.par2Flavor = *yamlCfg.Par2Flavor
+	}
+	if yamlCfg.MinPar2Version != nil && !setFlags["min-par2-version"] {
+		global.minPar2Version = *yamlCfg.MinPar2Version
+	}
+	if yamlCfg.RequirePar2Version != nil && !setFlags["require-par2-version"] {
+		global.requirePar2Version = *yamlCfg.RequirePar2Version
+	}
+	if yamlCfg.AllowRoot != nil && !setFlags["allow-root"] {
+		global.allowRoot = *yamlCfg.AllowRoot
+	}
+	if yamlCfg.RefuseRoot != nil && !setFlags["refuse-root"] {
+		global.refuseRoot = *yamlCfg.RefuseRoot
+	}
+	if yamlCfg.WarningsAsErrors != nil && !setFlags["warnings-as-errors"] {
+		global.warningsAsErrors = *yamlCfg.WarningsAsErrors
+	}
+	if yamlCfg.TempDir != nil && !setFlags["temp-dir"] {
+		global.tempDir = *yamlCfg.TempDir
+	}
+	if yamlCfg.SummaryFile != nil && !setFlags["summary-file"] {
+		global.summaryFile = *yamlCfg.SummaryFile
+	}
+	if yamlCfg.MetricsFile != nil && !setFlags["metrics-file"] {
+		global.metricsFile = *yamlCfg.MetricsFile
+	}
+	if yamlCfg.PauseFile != nil && !setFlags["pause-file"] {
+		global.pauseFile = *yamlCfg.PauseFile
+	}
+	if yamlCfg.IgnoreFile != nil && !setFlags["ignore-file"] {
+		global.ignoreNames.File = *yamlCfg.IgnoreFile
+	}
+	if yamlCfg.IgnoreAllFile != nil && !setFlags["ignore-all-file"] {
+		global.ignoreNames.AllFile = *yamlCfg.IgnoreAllFile
+	}
+	if yamlCfg.ManifestSuffix != nil && !setFlags["manifest-suffix"] {
+		global.sidecarNames.ManifestSuffix = *yamlCfg.ManifestSuffix
+	}
+	if yamlCfg.LockSuffix != nil && !setFlags["lock-suffix"] {
+		global.sidecarNames.LockSuffix = *yamlCfg.LockSuffix
+	}
+	if yamlCfg.HiddenSidecars != nil && !setFlags["hidden-sidecars"] {
+		global.sidecarNames.Hidden = *yamlCfg.HiddenSidecars
+	}
+	if yamlCfg.MaxManifestSize != nil && !setFlags["max-manifest-size"] {
+		global.maxManifestSize = *yamlCfg.MaxManifestSize
+	}
+	if yamlCfg.MaxDepth != nil && !setFlags["max-depth"] {
+		global.maxDepth = *yamlCfg.MaxDepth
+	}
 	if yamlCfg.LogLevel != nil && !setFlags["log-level"] {
 		global.logOptions.LogLevel = *yamlCfg.LogLevel
 	}
@@ -121,53 +311,302 @@ func (yamlCfg *configFileCreate) Merge(cfg *create.Options, global *globalOption
 	if yamlCfg.SeqKey != nil && !setFlags["seq-key"] {
 		global.logOptions.SeqKey = *yamlCfg.SeqKey
 	}
+	if yamlCfg.LogFile != nil && !setFlags["log-file"] {
+		global.logOptions.LogFile = *yamlCfg.LogFile
+	}
+	if yamlCfg.LogFileSize != nil && !setFlags["log-file-size"] {
+		global.logOptions.LogFileSize = *yamlCfg.LogFileSize
+	}
+	if yamlCfg.LogFileKeep != nil && !setFlags["log-file-keep"] {
+		global.logOptions.LogFileKeep = *yamlCfg.LogFileKeep
+	}
 	if yamlCfg.WantJSON != nil && !setFlags["json"] {
 		global.logOptions.WantJSON = *yamlCfg.WantJSON
 	}
+	if yamlCfg.FlattenLogs != nil && !setFlags["flatten-logs"] {
+		global.logOptions.FlattenLogs = *yamlCfg.FlattenLogs
+	}
+	if yamlCfg.Color != nil && !setFlags["color"] {
+		global.logOptions.Color = *yamlCfg.Color
+	}
+	if yamlCfg.ProgressBar != nil && !setFlags["progress-bar"] {
+		global.logOptions.ProgressBar = *yamlCfg.ProgressBar
+	}
+	if yamlCfg.PathPrefixMap != nil && !setFlags["path-prefix-map"] {
+		global.logOptions.PathPrefixMap = *yamlCfg.PathPrefixMap
+	}
 }
 
 type configFileVerify struct {
-	Par2Args *[]string `yaml:"args"`
-
-	CacheDir        *string         `yaml:"cache"`
-	MaxDuration     *flags.Duration `yaml:"duration"`
-	MinAge          *flags.Duration `yaml:"age"`
-	RunInterval     *flags.Duration `yaml:"calc-run-interval"`
-	IncludeExternal *bool           `yaml:"include-external"`
-	SkipNotCreated  *bool           `yaml:"skip-not-created"`
-
-	Cgroup   *string         `yaml:"cgroup"`
-	LogLevel *flags.LogLevel `yaml:"log-level"`
-	SeqURL   *string         `yaml:"seq-url"`
-	SeqKey   *string         `yaml:"seq-key"`
-	WantJSON *bool           `yaml:"json"`
+	Par2Args    *[]string `yaml:"args"`
+	AllowedArgs *[]string `yaml:"allowed-args"`
+
+	CacheDir            *string              `yaml:"cache"`
+	MaxDuration         *flags.Duration      `yaml:"duration"`
+	Limit               *int                 `yaml:"limit"`
+	MaxErrors           *int                 `yaml:"max-errors"`
+	Jobs                *int                 `yaml:"jobs"`
+	IOConcurrency       *int                 `yaml:"io-concurrency"`
+	MinAge              *flags.Duration      `yaml:"age"`
+	ChangedSince        *string              `yaml:"changed-since"`
+	RunInterval         *flags.Duration      `yaml:"calc-run-interval"`
+	MinRunInterval      *flags.Duration      `yaml:"min-run-interval"`
+	IncludeExternal     *bool                `yaml:"include-external"`
+	SkipNotCreated      *bool                `yaml:"skip-not-created"`
+	CleanOrphans        *bool                `yaml:"clean-orphans"`
+	Tags                *flags.Tags          `yaml:"tag"`
+	CreatedBefore       *flags.Date          `yaml:"created-before"`
+	CreatedAfter        *flags.Date          `yaml:"created-after"`
+	IncludeNotCreated   *bool                `yaml:"include-not-created"`
+	MirrorDir           *string              `yaml:"mirror"`
+	NoManifestUpdate    *bool                `yaml:"no-manifest-update"`
+	OnlyNeedingRepair   *bool                `yaml:"only-needing-repair"`
+	StrictPar2          *bool                `yaml:"strict-par2"`
+	Par2Quiet           *bool                `yaml:"par2-quiet"`
+	Par2Verbose         *bool                `yaml:"par2-verbose"`
+	Par2ExitCodes       *flags.Par2ExitCodes `yaml:"par2-exit-code"`
+	RefreshStamp        *bool                `yaml:"refresh-stamp"`
+	StampFile           *string              `yaml:"stamp-file"`
+	Order               *flags.VerifyOrder   `yaml:"order"`
+	NameMismatch        *flags.NameMismatch  `yaml:"name-mismatch"`
+	Checkpoint          *string              `yaml:"checkpoint"`
+	Resume              *bool                `yaml:"resume"`
+	Quick               *bool                `yaml:"quick"`
+	DropCaches          *bool                `yaml:"drop-caches"`
+	BasePath            *flags.BasePath      `yaml:"basepath"`
+	RetrySingleThreaded *bool                `yaml:"retry-single-threaded"`
+	DereferenceManifest *bool                `yaml:"dereference-manifest"`
+	FileStatus          *bool                `yaml:"file-status"`
+	FindRelocated       *bool                `yaml:"find-relocated"`
+	Relocate            *bool                `yaml:"relocate"`
+	VerifyPasses        *int                 `yaml:"verify-passes"`
+	Classifier          *string              `yaml:"classifier"`
+	CrossCheck          *string              `yaml:"cross-check"`
+
+	MinPar2Version     *flags.Version `yaml:"min-par2-version"`
+	RequirePar2Version *bool          `yaml:"require-par2-version"`
+	AllowRoot          *bool          `yaml:"allow-root"`
+	RefuseRoot         *bool          `yaml:"refuse-root"`
+	WarningsAsErrors   *bool          `yaml:"warnings-as-errors"`
+
+	Cgroup          *string              `yaml:"cgroup"`
+	IOThrottle      *flags.IOThrottle    `yaml:"io-throttle"`
+	Par2Env         *flags.EnvVars       `yaml:"par2-env"`
+	Par2Flavor      *flags.Par2Flavor    `yaml:"par2-flavor"`
+	TempDir         *string              `yaml:"temp-dir"`
+	SummaryFile     *string              `yaml:"summary-file"`
+	MetricsFile     *string              `yaml:"metrics-file"`
+	PauseFile       *string              `yaml:"pause-file"`
+	IgnoreFile      *string              `yaml:"ignore-file"`
+	IgnoreAllFile   *string              `yaml:"ignore-all-file"`
+	ManifestSuffix  *string              `yaml:"manifest-suffix"`
+	LockSuffix      *string              `yaml:"lock-suffix"`
+	HiddenSidecars  *bool                `yaml:"hidden-sidecars"`
+	MaxManifestSize *flags.ByteSize      `yaml:"max-manifest-size"`
+	MaxDepth        *flags.MaxDepth      `yaml:"max-depth"`
+	LogLevel        *flags.LogLevel      `yaml:"log-level"`
+	SeqURL          *string              `yaml:"seq-url"`
+	SeqKey          *string              `yaml:"seq-key"`
+	LogFile         *string              `yaml:"log-file"`
+	LogFileSize     *int                 `yaml:"log-file-size"`
+	LogFileKeep     *int                 `yaml:"log-file-keep"`
+	WantJSON        *bool                `yaml:"json"`
+	FlattenLogs     *bool                `yaml:"flatten-logs"`
+	Color           *flags.Color         `yaml:"color"`
+	ProgressBar     *bool                `yaml:"progress-bar"`
+	PathPrefixMap   *flags.PathPrefixMap `yaml:"path-prefix-map"`
 }
 
 func (yamlCfg *configFileVerify) Merge(cfg *verify.Options, global *globalOptions, hasExternalArgs bool, setFlags map[string]bool) {
 	if yamlCfg.Par2Args != nil && !hasExternalArgs {
 		cfg.Par2Args = slices.Clone(*yamlCfg.Par2Args)
 	}
+	if yamlCfg.AllowedArgs != nil {
+		global.allowedPar2Args = slices.Clone(*yamlCfg.AllowedArgs)
+	}
 	if yamlCfg.CacheDir != nil && !setFlags["cache"] {
 		cfg.CacheDir = *yamlCfg.CacheDir
 	}
 	if yamlCfg.MaxDuration != nil && !setFlags["duration"] {
 		cfg.MaxDuration = *yamlCfg.MaxDuration
 	}
+	if yamlCfg.Limit != nil && !setFlags["limit"] {
+		cfg.Limit = *yamlCfg.Limit
+	}
+	if yamlCfg.MaxErrors != nil && !setFlags["max-errors"] {
+		cfg.MaxErrors = *yamlCfg.MaxErrors
+	}
+	if yamlCfg.Jobs != nil && !setFlags["jobs"] {
+		cfg.Jobs = *yamlCfg.Jobs
+	}
+	if yamlCfg.IOConcurrency != nil && !setFlags["io-concurrency"] {
+		cfg.IOConcurrency = *yamlCfg.IOConcurrency
+	}
 	if yamlCfg.MinAge != nil && !setFlags["age"] {
 		cfg.MinAge = *yamlCfg.MinAge
 	}
+	if yamlCfg.ChangedSince != nil && !setFlags["changed-since"] {
+		cfg.ChangedSince = *yamlCfg.ChangedSince
+	}
 	if yamlCfg.RunInterval != nil && !setFlags["calc-run-interval"] {
 		cfg.RunInterval = *yamlCfg.RunInterval
 	}
+	if yamlCfg.MinRunInterval != nil && !setFlags["min-run-interval"] {
+		cfg.MinRunInterval = *yamlCfg.MinRunInterval
+	}
 	if yamlCfg.IncludeExternal != nil && !setFlags["include-external"] {
 		cfg.IncludeExternal = *yamlCfg.IncludeExternal
 	}
 	if yamlCfg.SkipNotCreated != nil && !setFlags["skip-not-created"] {
 		cfg.SkipNotCreated = *yamlCfg.SkipNotCreated
 	}
+	if yamlCfg.CleanOrphans != nil && !setFlags["clean-orphans"] {
+		cfg.CleanOrphans = *yamlCfg.CleanOrphans
+	}
+	if yamlCfg.Tags != nil && !setFlags["tag"] {
+		cfg.Tags = *yamlCfg.Tags
+	}
+	if yamlCfg.CreatedBefore != nil && !setFlags["created-before"] {
+		cfg.CreatedBefore = *yamlCfg.CreatedBefore
+	}
+	if yamlCfg.CreatedAfter != nil && !setFlags["created-after"] {
+		cfg.CreatedAfter = *yamlCfg.CreatedAfter
+	}
+	if yamlCfg.IncludeNotCreated != nil && !setFlags["include-not-created"] {
+		cfg.IncludeNotCreated = *yamlCfg.IncludeNotCreated
+	}
+	if yamlCfg.MirrorDir != nil && !setFlags["mirror"] {
+		cfg.MirrorDir = *yamlCfg.MirrorDir
+	}
+	if yamlCfg.NoManifestUpdate != nil && !setFlags["no-manifest-update"] {
+		cfg.NoManifestUpdate = *yamlCfg.NoManifestUpdate
+	}
+	if yamlCfg.OnlyNeedingRepair != nil && !setFlags["only-needing-repair"] {
+		cfg.OnlyNeedingRepair = *yamlCfg.OnlyNeedingRepair
+	}
+	if yamlCfg.StrictPar2 != nil && !setFlags["strict-par2"] {
+		cfg.StrictPar2 = *yamlCfg.StrictPar2
+	}
+	if yamlCfg.Par2Quiet != nil && !setFlags["par2-quiet"] {
+		cfg.Par2Quiet = *yamlCfg.Par2Quiet
+	}
+	if yamlCfg.Par2Verbose != nil && !setFlags["par2-verbose"] {
+		cfg.Par2Verbose = *yamlCfg.Par2Verbose
+	}
+	if yamlCfg.Par2ExitCodes != nil && !setFlags["par2-exit-code"] {
+		cfg.Par2ExitCodes = *yamlCfg.Par2ExitCodes
+	}
+	if yamlCfg.RefreshStamp != nil && !setFlags["refresh-stamp"] {
+		cfg.RefreshStamp = *yamlCfg.RefreshStamp
+	}
+	if yamlCfg.StampFile != nil && !setFlags["stamp-file"] {
+		cfg.StampFile = *yamlCfg.StampFile
+	}
+	if yamlCfg.Order != nil && !setFlags["order"] {
+		cfg.Order = *yamlCfg.Order
+	}
+	if yamlCfg.NameMismatch != nil && !setFlags["name-mismatch"] {
+		cfg.NameMismatch = *yamlCfg.NameMismatch
+	}
+	if yamlCfg.Checkpoint != nil && !setFlags["checkpoint"] {
+		cfg.Checkpoint = *yamlCfg.Checkpoint
+	}
+	if yamlCfg.Resume != nil && !setFlags["resume"] {
+		cfg.Resume = *yamlCfg.Resume
+	}
+	if yamlCfg.Quick != nil && !setFlags["quick"] {
+		cfg.Quick = *yamlCfg.Quick
+	}
+	if yamlCfg.DropCaches != nil && !setFlags["drop-caches"] {
+		cfg.DropCaches = *yamlCfg.DropCaches
+	}
+	if yamlCfg.BasePath != nil && !setFlags["basepath"] {
+		cfg.BasePath = *yamlCfg.BasePath
+	}
+	if yamlCfg.RetrySingleThreaded != nil && !setFlags["retry-single-threaded"] {
+		cfg.RetrySingleThreaded = *yamlCfg.RetrySingleThreaded
+	}
+	if yamlCfg.DereferenceManifest != nil && !setFlags["dereference-manifest"] {
+		cfg.DereferenceManifest = *yamlCfg.DereferenceManifest
+	}
+	if yamlCfg.FileStatus != nil && !setFlags["file-status"] {
+		cfg.FileStatus = *yamlCfg.FileStatus
+	}
+	if yamlCfg.FindRelocated != nil && !setFlags["find-relocated"] {
+		cfg.FindRelocated = *yamlCfg.FindRelocated
+	}
+	if yamlCfg.Relocate != nil && !setFlags["relocate"] {
+		cfg.Relocate = *yamlCfg.Relocate
+	}
+	if yamlCfg.VerifyPasses != nil && !setFlags["verify-passes"] {
+		cfg.VerifyPasses = *yamlCfg.VerifyPasses
+	}
+	if yamlCfg.Classifier != nil && !setFlags["classifier"] {
+		cfg.Classifier = *yamlCfg.Classifier
+	}
+	if yamlCfg.CrossCheck != nil && !setFlags["cross-check"] {
+		cfg.CrossCheck = *yamlCfg.CrossCheck
+	}
 	if yamlCfg.Cgroup != nil && !setFlags["cgroup"] {
 		global.cgroupPath = *yamlCfg.Cgroup
 	}
+	if yamlCfg.IOThrottle != nil && !setFlags["io-throttle"] {
+		global.ioThrottle = *yamlCfg.IOThrottle
+	}
+	if yamlCfg.Par2Env != nil && !setFlags["par2-env"] {
+		global.par2Env = *yamlCfg.Par2Env
+	}
+	if yamlCfg.Par2Flavor != nil && !setFlags["par2-flavor"] {
+		global.par2Flavor = *yamlCfg.Par2Flavor
+	}
+	if yamlCfg.MinPar2Version != nil && !setFlags["min-par2-version"] {
+		global.minPar2Version = *yamlCfg.MinPar2Version
+	}
+	if yamlCfg.RequirePar2Version != nil && !setFlags["require-par2-version"] {
+		global.requirePar2Version = *yamlCfg.RequirePar2Version
+	}
+	if yamlCfg.AllowRoot != nil && !setFlags["allow-root"] {
+		global.allowRoot = *yamlCfg.AllowRoot
+	}
+	if yamlCfg.RefuseRoot != nil && !setFlags["refuse-root"] {
+		global.refuseRoot = *yamlCfg.RefuseRoot
+	}
+	if yamlCfg.WarningsAsErrors != nil && !setFlags["warnings-as-errors"] {
+		global.warningsAsErrors = *yamlCfg.WarningsAsErrors
+	}
+	if yamlCfg.TempDir != nil && !setFlags["temp-dir"] {
+		global.tempDir = *yamlCfg.TempDir
+	}
+	if yamlCfg.SummaryFile != nil && !setFlags["summary-file"] {
+		global.summaryFile = *yamlCfg.SummaryFile
+	}
+	if yamlCfg.MetricsFile != nil && !setFlags["metrics-file"] {
+		global.metricsFile = *yamlCfg.MetricsFile
+	}
+	if yamlCfg.PauseFile != nil && !setFlags["pause-file"] {
+		global.pauseFile = *yamlCfg.PauseFile
+	}
+	if yamlCfg.IgnoreFile != nil && !setFlags["ignore-file"] {
+		global.ignoreNames.File = *yamlCfg.IgnoreFile
+	}
+	if yamlCfg.IgnoreAllFile != nil && !setFlags["ignore-all-file"] {
+		global.ignoreNames.AllFile = *yamlCfg.IgnoreAllFile
+	}
+	if yamlCfg.ManifestSuffix != nil && !setFlags["manifest-suffix"] {
+		global.sidecarNames.ManifestSuffix = *yamlCfg.ManifestSuffix
+	}
+	if yamlCfg.LockSuffix != nil && !setFlags["lock-suffix"] {
+		global.sidecarNames.LockSuffix = *yamlCfg.LockSuffix
+	}
+	if yamlCfg.HiddenSidecars != nil && !setFlags["hidden-sidecars"] {
+		global.sidecarNames.Hidden = *yamlCfg.HiddenSidecars
+	}
+	if yamlCfg.MaxManifestSize != nil && !setFlags["max-manifest-size"] {
+		global.maxManifestSize = *yamlCfg.MaxManifestSize
+	}
+	if yamlCfg.MaxDepth != nil && !setFlags["max-depth"] {
+		global.maxDepth = *yamlCfg.MaxDepth
+	}
 	if yamlCfg.LogLevel != nil && !setFlags["log-level"] {
 		global.logOptions.LogLevel = *yamlCfg.LogLevel
 	}
@@ -177,34 +616,98 @@ func (yamlCfg *configFileVerify) Merge(cfg *verify.Options, global *globalOption
 	if yamlCfg.SeqKey != nil && !setFlags["seq-key"] {
 		global.logOptions.SeqKey = *yamlCfg.SeqKey
 	}
+	if yamlCfg.LogFile != nil && !setFlags["log-file"] {
+		global.logOptions.LogFile = *yamlCfg.LogFile
+	}
+	if yamlCfg.LogFileSize != nil && !setFlags["log-file-size"] {
+		global.logOptions.LogFileSize = *yamlCfg.LogFileSize
+	}
+	if yamlCfg.LogFileKeep != nil && !setFlags["log-file-keep"] {
+		global.logOptions.LogFileKeep = *yamlCfg.LogFileKeep
+	}
 	if yamlCfg.WantJSON != nil && !setFlags["json"] {
 		global.logOptions.WantJSON = *yamlCfg.WantJSON
 	}
+	if yamlCfg.FlattenLogs != nil && !setFlags["flatten-logs"] {
+		global.logOptions.FlattenLogs = *yamlCfg.FlattenLogs
+	}
+	if yamlCfg.Color != nil && !setFlags["color"] {
+		global.logOptions.Color = *yamlCfg.Color
+	}
+	if yamlCfg.ProgressBar != nil && !setFlags["progress-bar"] {
+		global.logOptions.ProgressBar = *yamlCfg.ProgressBar
+	}
+	if yamlCfg.PathPrefixMap != nil && !setFlags["path-prefix-map"] {
+		global.logOptions.PathPrefixMap = *yamlCfg.PathPrefixMap
+	}
 }
 
 type configFileRepair struct {
-	Par2Args   *[]string `yaml:"args"`
-	Par2Verify *bool     `yaml:"verify"`
-
-	CacheDir             *string         `yaml:"cache"`
-	MaxDuration          *flags.Duration `yaml:"duration"`
-	MinTestedCount       *int            `yaml:"min-tested"`
-	SkipNotCreated       *bool           `yaml:"skip-not-created"`
-	AttemptUnrepairables *bool           `yaml:"attempt-unrepairables"`
-	PurgeBackups         *bool           `yaml:"purge-backups"`
-	RestoreBackups       *bool           `yaml:"restore-backups"`
-
-	Cgroup   *string         `yaml:"cgroup"`
-	LogLevel *flags.LogLevel `yaml:"log-level"`
-	SeqURL   *string         `yaml:"seq-url"`
-	SeqKey   *string         `yaml:"seq-key"`
-	WantJSON *bool           `yaml:"json"`
+	Par2Args    *[]string `yaml:"args"`
+	AllowedArgs *[]string `yaml:"allowed-args"`
+	Par2Verify  *bool     `yaml:"verify"`
+
+	CacheDir             *string             `yaml:"cache"`
+	MaxDuration          *flags.Duration     `yaml:"duration"`
+	Limit                *int                `yaml:"limit"`
+	MaxErrors            *int                `yaml:"max-errors"`
+	MinTestedCount       *int                `yaml:"min-tested"`
+	SkipNotCreated       *bool               `yaml:"skip-not-created"`
+	CleanOrphans         *bool               `yaml:"clean-orphans"`
+	Tags                 *flags.Tags         `yaml:"tag"`
+	AttemptUnrepairables *bool               `yaml:"attempt-unrepairables"`
+	PurgeBackups         *bool               `yaml:"purge-backups"`
+	RestoreBackups       *bool               `yaml:"restore-backups"`
+	Rebaseline           *bool               `yaml:"rebaseline"`
+	SkipReadOnly         *bool               `yaml:"skip-read-only"`
+	NameMismatch         *flags.NameMismatch `yaml:"name-mismatch"`
+	BasePath             *flags.BasePath     `yaml:"basepath"`
+	RetrySingleThreaded  *bool               `yaml:"retry-single-threaded"`
+	DereferenceManifest  *bool               `yaml:"dereference-manifest"`
+	Par2Quiet            *bool               `yaml:"par2-quiet"`
+	Par2Verbose          *bool               `yaml:"par2-verbose"`
+
+	MinPar2Version     *flags.Version `yaml:"min-par2-version"`
+	RequirePar2Version *bool          `yaml:"require-par2-version"`
+	AllowRoot          *bool          `yaml:"allow-root"`
+	RefuseRoot         *bool          `yaml:"refuse-root"`
+	WarningsAsErrors   *bool          `yaml:"warnings-as-errors"`
+
+	Cgroup          *string              `yaml:"cgroup"`
+	IOThrottle      *flags.IOThrottle    `yaml:"io-throttle"`
+	Par2Env         *flags.EnvVars       `yaml:"par2-env"`
+	Par2Flavor      *flags.Par2Flavor    `yaml:"par2-flavor"`
+	TempDir         *string              `yaml:"temp-dir"`
+	SummaryFile     *string              `yaml:"summary-file"`
+	MetricsFile     *string              `yaml:"metrics-file"`
+	PauseFile       *string              `yaml:"pause-file"`
+	IgnoreFile      *string              `yaml:"ignore-file"`
+	IgnoreAllFile   *string              `yaml:"ignore-all-file"`
+	ManifestSuffix  *string              `yaml:"manifest-suffix"`
+	LockSuffix      *string              `yaml:"lock-suffix"`
+	HiddenSidecars  *bool                `yaml:"hidden-sidecars"`
+	MaxManifestSize *flags.ByteSize      `yaml:"max-manifest-size"`
+	MaxDepth        *flags.MaxDepth      `yaml:"max-depth"`
+	LogLevel        *flags.LogLevel      `yaml:"log-level"`
+	SeqURL          *string              `yaml:"seq-url"`
+	SeqKey          *string              `yaml:"seq-key"`
+	LogFile         *string              `yaml:"log-file"`
+	LogFileSize     *int                 `yaml:"log-file-size"`
+	LogFileKeep     *int                 `yaml:"log-file-keep"`
+	WantJSON        *bool                `yaml:"json"`
+	FlattenLogs     *bool                `yaml:"flatten-logs"`
+	Color           *flags.Color         `yaml:"color"`
+	ProgressBar     *bool                `yaml:"progress-bar"`
+	PathPrefixMap   *flags.PathPrefixMap `yaml:"path-prefix-map"`
 }
 
 func (yamlCfg *configFileRepair) Merge(cfg *repair.Options, global *globalOptions, hasExternalArgs bool, setFlags map[string]bool) {
 	if yamlCfg.Par2Args != nil && !hasExternalArgs {
 		cfg.Par2Args = slices.Clone(*yamlCfg.Par2Args)
 	}
+	if yamlCfg.AllowedArgs != nil {
+		global.allowedPar2Args = slices.Clone(*yamlCfg.AllowedArgs)
+	}
 	if yamlCfg.Par2Verify != nil && !setFlags["verify"] {
 		cfg.Par2Verify = *yamlCfg.Par2Verify
 	}
@@ -214,12 +717,24 @@ func (yamlCfg *configFileRepair) Merge(cfg *repair.Options, global *globalOption
 	if yamlCfg.MaxDuration != nil && !setFlags["duration"] {
 		cfg.MaxDuration = *yamlCfg.MaxDuration
 	}
+	if yamlCfg.Limit != nil && !setFlags["limit"] {
+		cfg.Limit = *yamlCfg.Limit
+	}
+	if yamlCfg.MaxErrors != nil && !setFlags["max-errors"] {
+		cfg.MaxErrors = *yamlCfg.MaxErrors
+	}
 	if yamlCfg.MinTestedCount != nil && !setFlags["min-tested"] {
 		cfg.MinTestedCount = *yamlCfg.MinTestedCount
 	}
 	if yamlCfg.SkipNotCreated != nil && !setFlags["skip-not-created"] {
 		cfg.SkipNotCreated = *yamlCfg.SkipNotCreated
 	}
+	if yamlCfg.CleanOrphans != nil && !setFlags["clean-orphans"] {
+		cfg.CleanOrphans = *yamlCfg.CleanOrphans
+	}
+	if yamlCfg.Tags != nil && !setFlags["tag"] {
+		cfg.Tags = *yamlCfg.Tags
+	}
 	if yamlCfg.AttemptUnrepairables != nil && !setFlags["attempt-unrepairables"] {
 		cfg.AttemptUnrepairables = *yamlCfg.AttemptUnrepairables
 	}
@@ -229,9 +744,90 @@ func (yamlCfg *configFileRepair) Merge(cfg *repair.Options, global *globalOption
 	if yamlCfg.RestoreBackups != nil && !setFlags["restore-backups"] {
 		cfg.RestoreBackups = *yamlCfg.RestoreBackups
 	}
+	if yamlCfg.Rebaseline != nil && !setFlags["rebaseline"] {
+		cfg.Rebaseline = *yamlCfg.Rebaseline
+	}
+	if yamlCfg.SkipReadOnly != nil && !setFlags["skip-read-only"] {
+		cfg.SkipReadOnly = *yamlCfg.SkipReadOnly
+	}
+	if yamlCfg.NameMismatch != nil && !setFlags["name-mismatch"] {
+		cfg.NameMismatch = *yamlCfg.NameMismatch
+	}
+	if yamlCfg.BasePath != nil && !setFlags["basepath"] {
+		cfg.BasePath = *yamlCfg.BasePath
+	}
+	if yamlCfg.RetrySingleThreaded != nil && !setFlags["retry-single-threaded"] {
+		cfg.RetrySingleThreaded = *yamlCfg.RetrySingleThreaded
+	}
+	if yamlCfg.DereferenceManifest != nil && !setFlags["dereference-manifest"] {
+		cfg.DereferenceManifest = *yamlCfg.DereferenceManifest
+	}
+	if yamlCfg.Par2Quiet != nil && !setFlags["par2-quiet"] {
+		cfg.Par2Quiet = *yamlCfg.Par2Quiet
+	}
+	if yamlCfg.Par2Verbose != nil && !setFlags["par2-verbose"] {
+		cfg.Par2Verbose = *yamlCfg.Par2Verbose
+	}
 	if yamlCfg.Cgroup != nil && !setFlags["cgroup"] {
 		global.cgroupPath = *yamlCfg.Cgroup
 	}
+	if yamlCfg.IOThrottle != nil && !setFlags["io-throttle"] {
+		global.ioThrottle = *yamlCfg.IOThrottle
+	}
+	if yamlCfg.Par2Env != nil && !setFlags["par2-env"] {
+		global.par2Env = *yamlCfg.Par2Env
+	}
+	if yamlCfg.Par2Flavor != nil && !setFlags["par2-flavor"] {
+		global.par2Flavor = *yamlCfg.Par2Flavor
+	}
+	if yamlCfg.MinPar2Version != nil && !setFlags["min-par2-version"] {
+		global.minPar2Version = *yamlCfg.MinPar2Version
+	}
+	if yamlCfg.RequirePar2Version != nil && !setFlags["require-par2-version"] {
+		global.requirePar2Version = *yamlCfg.RequirePar2Version
+	}
+	if yamlCfg.AllowRoot != nil && !setFlags["allow-root"] {
+		global.allowRoot = *yamlCfg.AllowRoot
+	}
+	if yamlCfg.RefuseRoot != nil && !setFlags["refuse-root"] {
+		global.refuseRoot = *yamlCfg.RefuseRoot
+	}
+	if yamlCfg.WarningsAsErrors != nil && !setFlags["warnings-as-errors"] {
+		global.warningsAsErrors = *yamlCfg.WarningsAsErrors
+	}
+	if yamlCfg.TempDir != nil && !setFlags["temp-dir"] {
+		global.tempDir = *yamlCfg.TempDir
+	}
+	if yamlCfg.SummaryFile != nil && !setFlags["summary-file"] {
+		global.summaryFile = *yamlCfg.SummaryFile
+	}
+	if yamlCfg.MetricsFile != nil && !setFlags["metrics-file"] {
+		global.metricsFile = *yamlCfg.MetricsFile
+	}
+	if yamlCfg.PauseFile != nil && !setFlags["pause-file"] {
+		global.pauseFile = *yamlCfg.PauseFile
+	}
+	if yamlCfg.IgnoreFile != nil && !setFlags["ignore-file"] {
+		global.ignoreNames.File = *yamlCfg.IgnoreFile
+	}
+	if yamlCfg.IgnoreAllFile != nil && !setFlags["ignore-all-file"] {
+		global.ignoreNames.AllFile = *yamlCfg.IgnoreAllFile
+	}
+	if yamlCfg.ManifestSuffix != nil && !setFlags["manifest-suffix"] {
+		global.sidecarNames.ManifestSuffix = *yamlCfg.ManifestSuffix
+	}
+	if yamlCfg.LockSuffix != nil && !setFlags["lock-suffix"] {
+		global.sidecarNames.LockSuffix = *yamlCfg.LockSuffix
+	}
+	if yamlCfg.HiddenSidecars != nil && !setFlags["hidden-sidecars"] {
+		global.sidecarNames.Hidden = *yamlCfg.HiddenSidecars
+	}
+	if yamlCfg.MaxManifestSize != nil && !setFlags["max-manifest-size"] {
+		global.maxManifestSize = *yamlCfg.MaxManifestSize
+	}
+	if yamlCfg.MaxDepth != nil && !setFlags["max-depth"] {
+		global.maxDepth = *yamlCfg.MaxDepth
+	}
 	if yamlCfg.LogLevel != nil && !setFlags["log-level"] {
 		global.logOptions.LogLevel = *yamlCfg.LogLevel
 	}
@@ -241,24 +837,69 @@ func (yamlCfg *configFileRepair) Merge(cfg *repair.Options, global *globalOption
 	if yamlCfg.SeqKey != nil && !setFlags["seq-key"] {
 		global.logOptions.SeqKey = *yamlCfg.SeqKey
 	}
+	if yamlCfg.LogFile != nil && !setFlags["log-file"] {
+		global.logOptions.LogFile = *yamlCfg.LogFile
+	}
+	if yamlCfg.LogFileSize != nil && !setFlags["log-file-size"] {
+		global.logOptions.LogFileSize = *yamlCfg.LogFileSize
+	}
+	if yamlCfg.LogFileKeep != nil && !setFlags["log-file-keep"] {
+		global.logOptions.LogFileKeep = *yamlCfg.LogFileKeep
+	}
 	if yamlCfg.WantJSON != nil && !setFlags["json"] {
 		global.logOptions.WantJSON = *yamlCfg.WantJSON
 	}
+	if yamlCfg.FlattenLogs != nil && !setFlags["flatten-logs"] {
+		global.logOptions.FlattenLogs = *yamlCfg.FlattenLogs
+	}
+	if yamlCfg.Color != nil && !setFlags["color"] {
+		global.logOptions.Color = *yamlCfg.Color
+	}
+	if yamlCfg.ProgressBar != nil && !setFlags["progress-bar"] {
+		global.logOptions.ProgressBar = *yamlCfg.ProgressBar
+	}
+	if yamlCfg.PathPrefixMap != nil && !setFlags["path-prefix-map"] {
+		global.logOptions.PathPrefixMap = *yamlCfg.PathPrefixMap
+	}
 }
 
 type configFileInfo struct {
-	CacheDir        *string         `yaml:"cache"`
-	MaxDuration     *flags.Duration `yaml:"duration"`
-	MinAge          *flags.Duration `yaml:"age"`
-	RunInterval     *flags.Duration `yaml:"calc-run-interval"`
-	IncludeExternal *bool           `yaml:"include-external"`
-	SkipNotCreated  *bool           `yaml:"skip-not-created"`
-
-	Cgroup   *string         `yaml:"cgroup"`
-	LogLevel *flags.LogLevel `yaml:"log-level"`
-	SeqURL   *string         `yaml:"seq-url"`
-	SeqKey   *string         `yaml:"seq-key"`
-	WantJSON *bool           `yaml:"json"`
+	CacheDir         *string         `yaml:"cache"`
+	MaxDuration      *flags.Duration `yaml:"duration"`
+	MinAge           *flags.Duration `yaml:"age"`
+	RunInterval      *flags.Duration `yaml:"calc-run-interval"`
+	IncludeExternal  *bool           `yaml:"include-external"`
+	SkipNotCreated   *bool           `yaml:"skip-not-created"`
+	Tags             *flags.Tags     `yaml:"tag"`
+	DetectDuplicates *bool           `yaml:"detect-duplicates"`
+	Stats            *bool           `yaml:"stats"`
+	Health           *bool           `yaml:"health"`
+	Ignores          *bool           `yaml:"ignores"`
+
+	TargetRedundancy *flags.Redundancy `yaml:"target-redundancy"`
+
+	Cgroup          *string              `yaml:"cgroup"`
+	IOThrottle      *flags.IOThrottle    `yaml:"io-throttle"`
+	Par2Env         *flags.EnvVars       `yaml:"par2-env"`
+	Par2Flavor      *flags.Par2Flavor    `yaml:"par2-flavor"`
+	TempDir         *string              `yaml:"temp-dir"`
+	IgnoreFile      *string              `yaml:"ignore-file"`
+	IgnoreAllFile   *string              `yaml:"ignore-all-file"`
+	ManifestSuffix  *string              `yaml:"manifest-suffix"`
+	LockSuffix      *string              `yaml:"lock-suffix"`
+	HiddenSidecars  *bool                `yaml:"hidden-sidecars"`
+	MaxManifestSize *flags.ByteSize      `yaml:"max-manifest-size"`
+	MaxDepth        *flags.MaxDepth      `yaml:"max-depth"`
+	LogLevel        *flags.LogLevel      `yaml:"log-level"`
+	SeqURL          *string              `yaml:"seq-url"`
+	SeqKey          *string              `yaml:"seq-key"`
+	LogFile         *string              `yaml:"log-file"`
+	LogFileSize     *int                 `yaml:"log-file-size"`
+	LogFileKeep     *int                 `yaml:"log-file-keep"`
+	WantJSON        *bool                `yaml:"json"`
+	FlattenLogs     *bool                `yaml:"flatten-logs"`
+	Color           *flags.Color         `yaml:"color"`
+	PathPrefixMap   *flags.PathPrefixMap `yaml:"path-prefix-map"`
 }
 
 func (yamlCfg *configFileInfo) Merge(cfg *info.Options, global *globalOptions, _ bool, setFlags map[string]bool) {
@@ -280,9 +921,60 @@ func (yamlCfg *configFileInfo) Merge(cfg *info.Options, global *globalOptions, _
 	if yamlCfg.SkipNotCreated != nil && !setFlags["skip-not-created"] {
 		cfg.SkipNotCreated = *yamlCfg.SkipNotCreated
 	}
+	if yamlCfg.Tags != nil && !setFlags["tag"] {
+		cfg.Tags = *yamlCfg.Tags
+	}
+	if yamlCfg.DetectDuplicates != nil && !setFlags["detect-duplicates"] {
+		cfg.DetectDuplicates = *yamlCfg.DetectDuplicates
+	}
+	if yamlCfg.Stats != nil && !setFlags["stats"] {
+		cfg.Stats = *yamlCfg.Stats
+	}
+	if yamlCfg.Health != nil && !setFlags["health"] {
+		cfg.Health = *yamlCfg.Health
+	}
+	if yamlCfg.TargetRedundancy != nil && !setFlags["target-redundancy"] {
+		cfg.TargetRedundancy = *yamlCfg.TargetRedundancy
+	}
+	if yamlCfg.Ignores != nil && !setFlags["ignores"] {
+		cfg.Ignores = *yamlCfg.Ignores
+	}
 	if yamlCfg.Cgroup != nil && !setFlags["cgroup"] {
 		global.cgroupPath = *yamlCfg.Cgroup
 	}
+	if yamlCfg.IOThrottle != nil && !setFlags["io-throttle"] {
+		global.ioThrottle = *yamlCfg.IOThrottle
+	}
+	if yamlCfg.Par2Env != nil && !setFlags["par2-env"] {
+		global.par2Env = *yamlCfg.Par2Env
+	}
+	if yamlCfg.Par2Flavor != nil && !setFlags["par2-flavor"] {
+		global.par2Flavor = *yamlCfg.Par2Flavor
+	}
+	if yamlCfg.TempDir != nil && !setFlags["temp-dir"] {
+		global.tempDir = *yamlCfg.TempDir
+	}
+	if yamlCfg.IgnoreFile != nil && !setFlags["ignore-file"] {
+		global.ignoreNames.File = *yamlCfg.IgnoreFile
+	}
+	if yamlCfg.IgnoreAllFile != nil && !setFlags["ignore-all-file"] {
+		global.ignoreNames.AllFile = *yamlCfg.IgnoreAllFile
+	}
+	if yamlCfg.ManifestSuffix != nil && !setFlags["manifest-suffix"] {
+		global.sidecarNames.ManifestSuffix = *yamlCfg.ManifestSuffix
+	}
+	if yamlCfg.LockSuffix != nil && !setFlags["lock-suffix"] {
+		global.sidecarNames.LockSuffix = *yamlCfg.LockSuffix
+	}
+	if yamlCfg.HiddenSidecars != nil && !setFlags["hidden-sidecars"] {
+		global.sidecarNames.Hidden = *yamlCfg.HiddenSidecars
+	}
+	if yamlCfg.MaxManifestSize != nil && !setFlags["max-manifest-size"] {
+		global.maxManifestSize = *yamlCfg.MaxManifestSize
+	}
+	if yamlCfg.MaxDepth != nil && !setFlags["max-depth"] {
+		global.maxDepth = *yamlCfg.MaxDepth
+	}
 	if yamlCfg.LogLevel != nil && !setFlags["log-level"] {
 		global.logOptions.LogLevel = *yamlCfg.LogLevel
 	}
@@ -292,7 +984,25 @@ func (yamlCfg *configFileInfo) Merge(cfg *info.Options, global *globalOptions, _
 	if yamlCfg.SeqKey != nil && !setFlags["seq-key"] {
 		global.logOptions.SeqKey = *yamlCfg.SeqKey
 	}
+	if yamlCfg.LogFile != nil && !setFlags["log-file"] {
+		global.logOptions.LogFile = *yamlCfg.LogFile
+	}
+	if yamlCfg.LogFileSize != nil && !setFlags["log-file-size"] {
+		global.logOptions.LogFileSize = *yamlCfg.LogFileSize
+	}
+	if yamlCfg.LogFileKeep != nil && !setFlags["log-file-keep"] {
+		global.logOptions.LogFileKeep = *yamlCfg.LogFileKeep
+	}
 	if yamlCfg.WantJSON != nil && !setFlags["json"] {
 		global.logOptions.WantJSON = *yamlCfg.WantJSON
 	}
+	if yamlCfg.FlattenLogs != nil && !setFlags["flatten-logs"] {
+		global.logOptions.FlattenLogs = *yamlCfg.FlattenLogs
+	}
+	if yamlCfg.Color != nil && !setFlags["color"] {
+		global.logOptions.Color = *yamlCfg.Color
+	}
+	if yamlCfg.PathPrefixMap != nil && !setFlags["path-prefix-map"] {
+		global.logOptions.PathPrefixMap = *yamlCfg.PathPrefixMap
+	}
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/desertwitch/par2cron/internal/logging"
 	"github.com/desertwitch/par2cron/internal/repair"
 	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
 	"github.com/desertwitch/par2cron/internal/verify"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
@@ -95,6 +96,36 @@ func Test_configFile_Validate_InvalidGlobPattern_Error(t *testing.T) {
 	require.ErrorIs(t, cfg.Validate(), doublestar.ErrBadPattern)
 }
 
+// Expectation: Validation should fail on an invalid exclude, and on a deep exclude in recursive mode.
+func Test_configFile_Validate_GlobExclude_Error(t *testing.T) {
+	t.Parallel()
+
+	cfg := &configFile{
+		Create: &configFileCreate{
+			GlobExclude: &[]string{"*.tmp", "{unclosed"},
+		},
+	}
+	require.ErrorIs(t, cfg.Validate(), doublestar.ErrBadPattern)
+
+	cfg.Create.GlobExclude = &[]string{"**/*.tmp"}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Create.Par2Mode = &flags.CreateMode{Value: schema.CreateRecursiveMode}
+	require.ErrorIs(t, cfg.Validate(), schema.ErrUnsupportedGlob)
+}
+
+func Test_configFile_Validate_InvalidAllowedArgs_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, cfg := range []*configFile{
+		{Create: &configFileCreate{AllowedArgs: &[]string{"-B", "/data"}}},
+		{Verify: &configFileVerify{AllowedArgs: &[]string{"--"}}},
+		{Repair: &configFileRepair{AllowedArgs: &[]string{"-"}}},
+	} {
+		require.ErrorIs(t, cfg.Validate(), schema.ErrInvalidAllowedArgs)
+	}
+}
+
 // Expectation: parseConfigFile should reject a config with recursive mode and deep glob.
 func Test_parseConfigFile_RecursiveDeepGlob_Error(t *testing.T) {
 	t.Parallel()
@@ -131,6 +162,7 @@ func Test_parseConfigFile_ValidConfig_Success(t *testing.T) {
   cgroup: "/sys/fs/cgroup/par2limit"
 verify:
   args: ["-B"]
+  allowed-args: ["-B"]
   duration: "2h"
   age: "7d"
   calc-run-interval: "12h"
@@ -144,6 +176,7 @@ verify:
   cgroup: "/sys/fs/cgroup/par2limit"
 repair:
   args: ["-C"]
+  allowed-args: ["-C"]
   verify: true
   duration: "2h"
   min-tested: 3
@@ -263,19 +296,45 @@ func Test_parseConfigFile_EmptyConfig_Success(t *testing.T) {
 func Test_configFileCreate_Merge_AllFields_Success(t *testing.T) {
 	t.Parallel()
 
+	par2Flavor := flags.Par2Flavor{}
+	_ = par2Flavor.Set("turbo")
+
 	yamlCfg := &configFileCreate{
-		Par2Args:    &[]string{"-r20", "-n5"},
-		Par2Glob:    new("*.mp4"),
-		Par2Verify:  new(true),
-		Par2Mode:    &flags.CreateMode{Value: schema.CreateFileMode},
-		MaxDuration: &flags.Duration{Value: 5 * time.Minute},
-		LogLevel:    &flags.LogLevel{},
-		WantJSON:    new(true),
-		HideFiles:   new(true),
-		Bundle:      new(true),
-		SeqURL:      new("url"),
-		SeqKey:      new("key"),
-		Cgroup:      new("/sys/fs/cgroup/par2limit"),
+		Par2Args:        &[]string{"-r20", "-n5"},
+		Par2Glob:        new("*.mp4"),
+		Par2Verify:      new(true),
+		Par2Mode:        &flags.CreateMode{Value: schema.CreateFileMode},
+		MaxDuration:     &flags.Duration{Value: 5 * time.Minute},
+		Limit:           new(25),
+		LogLevel:        &flags.LogLevel{},
+		WantJSON:        new(true),
+		FlattenLogs:     new(true),
+		ProgressBar:     new(true),
+		HideFiles:       new(true),
+		Bundle:          new(true),
+		ExcludeEmpty:    new(true),
+		AdoptExisting:   new(true),
+		StrictGlob:      new(true),
+		SeqURL:          new("url"),
+		SeqKey:          new("key"),
+		Cgroup:          new("/sys/fs/cgroup/par2limit"),
+		IOThrottle:      &flags.IOThrottle{Raw: "idle", Value: schema.IOThrottleIdle},
+		Par2Env:         &flags.EnvVars{Raw: []string{"OMP_NUM_THREADS=2"}, Value: []string{"OMP_NUM_THREADS=2"}},
+		Par2Flavor:      &par2Flavor,
+		MaxManifestSize: &flags.ByteSize{Raw: "64M", Value: 64 << 20},
+		MetricsFile:     new("/var/lib/node_exporter/par2cron.prom"),
+		TempDir:         new("/mnt/cache/tmp"),
+		PauseFile:       new("/mnt/cache/par2cron.pause"),
+		IgnoreFile:      new(".par2cronignore"),
+		IgnoreAllFile:   new(".par2cronignore-all"),
+		MaxDepth:        &flags.MaxDepth{Raw: "2", Value: 2},
+
+		TargetBlockSize:     &flags.ByteSize{Raw: "4M", Value: 4 << 20},
+		MaxSetSize:          &flags.ByteSize{Raw: "500G", Value: 500 << 30},
+		RetrySingleThreaded: new(true),
+		RecordMetadata:      new(true),
+		FolderFingerprint:   new(true),
+		GlobExclude:         &[]string{"*.tmp", "*.log"},
 	}
 	_ = yamlCfg.LogLevel.Set("debug")
 
@@ -301,13 +360,36 @@ func Test_configFileCreate_Merge_AllFields_Success(t *testing.T) {
 	require.True(t, cfg.Par2Verify)
 	require.Equal(t, schema.CreateFileMode, cfg.Par2Mode.Value)
 	require.Equal(t, 5*time.Minute, cfg.MaxDuration.Value)
+	require.Equal(t, 25, cfg.Limit)
 	require.Equal(t, slog.LevelDebug, logs.LogLevel.Value)
 	require.True(t, logs.WantJSON)
+	require.True(t, logs.FlattenLogs)
+	require.True(t, logs.ProgressBar)
 	require.True(t, cfg.HideFiles)
 	require.True(t, cfg.Bundle)
+	require.True(t, cfg.ExcludeEmpty)
+	require.True(t, cfg.AdoptExisting)
+	require.True(t, cfg.StrictGlob)
+	require.Equal(t, int64(4<<20), cfg.TargetBlockSize.Value)
+	require.Equal(t, int64(500<<30), cfg.MaxSetSize.Value)
+	require.True(t, cfg.RetrySingleThreaded)
+	require.True(t, cfg.RecordMetadata)
+	require.True(t, cfg.FolderFingerprint)
+	require.Equal(t, []string{"*.tmp", "*.log"}, cfg.Par2GlobExclude)
 	require.Equal(t, "url", logs.SeqURL)
 	require.Equal(t, "key", logs.SeqKey)
 	require.Equal(t, "/sys/fs/cgroup/par2limit", global.cgroupPath)
+	require.Equal(t, schema.IOThrottleIdle, global.ioThrottle.Value)
+	require.Equal(t, []string{"OMP_NUM_THREADS=2"}, global.par2Env.Value)
+	require.Equal(t, schema.Par2FlavorTurbo, global.par2Flavor.Value)
+	require.Equal(t, int64(64<<20), global.maxManifestSize.Value)
+	require.Equal(t, "/var/lib/node_exporter/par2cron.prom", global.metricsFile)
+	require.Equal(t, "/mnt/cache/tmp", global.tempDir)
+	require.Equal(t, "/mnt/cache/par2cron.pause", global.pauseFile)
+	require.Equal(t, ".par2cronignore", global.ignoreNames.File)
+	require.Equal(t, ".par2cronignore-all", global.ignoreNames.AllFile)
+	require.Equal(t, 2, global.maxDepth.Value)
+	require.False(t, global.maxDepth.Unlimited())
 }
 
 // Expectation: External args should take precedence over YAML config.
@@ -334,6 +416,34 @@ func Test_configFileCreate_Merge_ExternalArgsPrecedence_Success(t *testing.T) {
 	require.Equal(t, []string{"-r30", "-n10"}, cfg.Par2Args)
 }
 
+// Expectation: The allowed par2 arguments should be kept to extend the default allowlist.
+func Test_configFileCreate_Merge_AllowedArgs_Success(t *testing.T) {
+	t.Parallel()
+
+	yamlCfg := &configFileCreate{
+		AllowedArgs: &[]string{"-B/data"},
+	}
+
+	var cfg create.Options
+
+	logs := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = logs.LogLevel.Set("info")
+
+	global := &globalOptions{logOptions: &logs}
+	yamlCfg.Merge(&cfg, global, true, map[string]bool{})
+
+	require.Equal(t, []string{"-B/data"}, global.allowedPar2Args)
+
+	allowed := par2ArgsAllowlist(global)
+	require.Contains(t, allowed, "-B/data")
+	require.Subset(t, allowed, util.DefaultAllowedPar2Args)
+	require.Len(t, allowed, len(util.DefaultAllowedPar2Args)+1)
+}
+
 // Expectation: CLI flags should take precedence over YAML config.
 func Test_configFileCreate_Merge_CLIFlagsPrecedence_Success(t *testing.T) {
 	t.Parallel()
@@ -350,6 +460,8 @@ func Test_configFileCreate_Merge_CLIFlagsPrecedence_Success(t *testing.T) {
 		SeqURL:      new("url"),
 		SeqKey:      new("key"),
 		Cgroup:      new("/sys/fs/cgroup/par2limit"),
+		IOThrottle:  &flags.IOThrottle{Raw: "idle", Value: schema.IOThrottleIdle},
+		Par2Env:     &flags.EnvVars{Raw: []string{"OMP_NUM_THREADS=2"}, Value: []string{"OMP_NUM_THREADS=2"}},
 	}
 	_ = yamlCfg.LogLevel.Set("debug")
 
@@ -367,17 +479,19 @@ func Test_configFileCreate_Merge_CLIFlagsPrecedence_Success(t *testing.T) {
 	_ = logs.LogLevel.Set("warn")
 
 	setFlags := map[string]bool{
-		"glob":      true,
-		"verify":    true,
-		"mode":      true,
-		"log-level": true,
-		"json":      true,
-		"duration":  true,
-		"hidden":    true,
-		"bundle":    true,
-		"seq-url":   true,
-		"seq-key":   true,
-		"cgroup":    true,
+		"glob":        true,
+		"verify":      true,
+		"mode":        true,
+		"log-level":   true,
+		"json":        true,
+		"duration":    true,
+		"hidden":      true,
+		"bundle":      true,
+		"seq-url":     true,
+		"seq-key":     true,
+		"cgroup":      true,
+		"io-throttle": true,
+		"par2-env":    true,
 	}
 
 	global := &globalOptions{logOptions: &logs}
@@ -394,6 +508,8 @@ func Test_configFileCreate_Merge_CLIFlagsPrecedence_Success(t *testing.T) {
 	require.Empty(t, logs.SeqURL)
 	require.Empty(t, logs.SeqKey)
 	require.Empty(t, global.cgroupPath)
+	require.Empty(t, global.ioThrottle.Value)
+	require.Empty(t, global.par2Env.Value)
 }
 
 // Expectation: Nil fields in YAML config should not override existing values.
@@ -469,19 +585,65 @@ func Test_configFileVerify_Merge_AllFields_Success(t *testing.T) {
 	LogLevel := flags.LogLevel{}
 	_ = LogLevel.Set("debug")
 
+	par2Flavor := flags.Par2Flavor{}
+	_ = par2Flavor.Set("turbo")
+
 	yamlCfg := &configFileVerify{
-		Par2Args:        &[]string{"-B"},
-		MaxDuration:     &maxDur,
-		MinAge:          &minAge,
-		RunInterval:     &RunInterval,
-		IncludeExternal: new(true),
-		SkipNotCreated:  new(true),
-		LogLevel:        &LogLevel,
-		WantJSON:        new(true),
-		CacheDir:        new("/tmp/cache"),
-		SeqURL:          new("url"),
-		SeqKey:          new("key"),
-		Cgroup:          new("/sys/fs/cgroup/par2limit"),
+		Par2Args:            &[]string{"-B"},
+		MaxDuration:         &maxDur,
+		Limit:               new(25),
+		MinAge:              &minAge,
+		ChangedSince:        new("/var/lib/par2cron/last-ingest"),
+		RunInterval:         &RunInterval,
+		MinRunInterval:      &flags.Duration{Raw: "30m", Value: 30 * time.Minute},
+		IncludeExternal:     new(true),
+		SkipNotCreated:      new(true),
+		CleanOrphans:        new(true),
+		MirrorDir:           new("/mnt/backup"),
+		NoManifestUpdate:    new(true),
+		OnlyNeedingRepair:   new(true),
+		StrictPar2:          new(true),
+		Order:               &flags.VerifyOrder{Raw: schema.VerifyOrderNewest, Value: schema.VerifyOrderNewest},
+		NameMismatch:        &flags.NameMismatch{Raw: schema.NameMismatchSkip, Value: schema.NameMismatchSkip},
+		Checkpoint:          new("/mnt/cache/verify.checkpoint"),
+		Resume:              new(true),
+		Quick:               new(true),
+		DropCaches:          new(true),
+		BasePath:            &flags.BasePath{Raw: schema.BasePathSetDir, Value: schema.BasePathSetDir},
+		RetrySingleThreaded: new(true),
+		DereferenceManifest: new(true),
+		FileStatus:          new(true),
+		FindRelocated:       new(true),
+		Relocate:            new(true),
+		VerifyPasses:        new(3),
+		Classifier:          new("/usr/local/bin/classify"),
+		CrossCheck:          new("/usr/local/bin/checksum-check"),
+		Tags:                &flags.Tags{Raw: []string{"tier:critical"}, Value: []string{"tier:critical"}},
+		CreatedBefore:       &flags.Date{Raw: "2025-01-01", Value: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		CreatedAfter:        &flags.Date{Raw: "2024-01-01", Value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		IncludeNotCreated:   new(true),
+		LogLevel:            &LogLevel,
+		WantJSON:            new(true),
+		FlattenLogs:         new(true),
+		ProgressBar:         new(true),
+		CacheDir:            new("/tmp/cache"),
+		SeqURL:              new("url"),
+		SeqKey:              new("key"),
+		Cgroup:              new("/sys/fs/cgroup/par2limit"),
+		Par2Flavor:          &par2Flavor,
+		MaxManifestSize:     &flags.ByteSize{Raw: "64M", Value: 64 << 20},
+		MetricsFile:         new("/var/lib/node_exporter/par2cron.prom"),
+		TempDir:             new("/mnt/cache/tmp"),
+		PauseFile:           new("/mnt/cache/par2cron.pause"),
+		IgnoreFile:          new(".par2cronignore"),
+		IgnoreAllFile:       new(".par2cronignore-all"),
+		MaxDepth:            &flags.MaxDepth{Raw: "2", Value: 2},
+
+		MinPar2Version:     &flags.Version{Raw: "0.8.1", Value: [3]int{0, 8, 1}},
+		RequirePar2Version: new(true),
+		AllowRoot:          new(true),
+		RefuseRoot:         new(true),
+		WarningsAsErrors:   new(true),
 	}
 
 	cfg := verify.Options{
@@ -501,16 +663,59 @@ func Test_configFileVerify_Merge_AllFields_Success(t *testing.T) {
 
 	require.Equal(t, []string{"-B"}, cfg.Par2Args)
 	require.Equal(t, "2h0m0s", cfg.MaxDuration.Value.String())
+	require.Equal(t, 25, cfg.Limit)
 	require.Equal(t, "168h0m0s", cfg.MinAge.Value.String())
+	require.Equal(t, "/var/lib/par2cron/last-ingest", cfg.ChangedSince)
 	require.Equal(t, "12h0m0s", cfg.RunInterval.Value.String())
+	require.Equal(t, 30*time.Minute, cfg.MinRunInterval.Value)
 	require.True(t, cfg.IncludeExternal)
 	require.True(t, cfg.SkipNotCreated)
+	require.True(t, cfg.CleanOrphans)
+	require.Equal(t, "/mnt/backup", cfg.MirrorDir)
+	require.True(t, cfg.NoManifestUpdate)
+	require.True(t, cfg.OnlyNeedingRepair)
+	require.True(t, cfg.StrictPar2)
+	require.Equal(t, schema.VerifyOrderNewest, cfg.Order.Value)
+	require.Equal(t, schema.NameMismatchSkip, cfg.NameMismatch.Value)
+	require.Equal(t, "/mnt/cache/verify.checkpoint", cfg.Checkpoint)
+	require.True(t, cfg.Resume)
+	require.True(t, cfg.Quick)
+	require.True(t, cfg.DropCaches)
+	require.Equal(t, schema.BasePathSetDir, cfg.BasePath.Value)
+	require.True(t, cfg.RetrySingleThreaded)
+	require.True(t, cfg.FileStatus)
+	require.True(t, cfg.FindRelocated)
+	require.True(t, cfg.Relocate)
+	require.True(t, cfg.DereferenceManifest)
+	require.Equal(t, 3, cfg.VerifyPasses)
+	require.Equal(t, "/usr/local/bin/classify", cfg.Classifier)
+	require.Equal(t, "/usr/local/bin/checksum-check", cfg.CrossCheck)
+	require.Equal(t, []string{"tier:critical"}, cfg.Tags.Value)
+	require.Equal(t, "2025-01-01", cfg.CreatedBefore.Raw)
+	require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), cfg.CreatedAfter.Value)
+	require.True(t, cfg.IncludeNotCreated)
 	require.Equal(t, slog.LevelDebug, logs.LogLevel.Value)
 	require.True(t, logs.WantJSON)
+	require.True(t, logs.FlattenLogs)
+	require.True(t, logs.ProgressBar)
 	require.Equal(t, "/tmp/cache", cfg.CacheDir)
 	require.Equal(t, "url", logs.SeqURL)
 	require.Equal(t, "key", logs.SeqKey)
 	require.Equal(t, "/sys/fs/cgroup/par2limit", global.cgroupPath)
+	require.Equal(t, schema.Par2FlavorTurbo, global.par2Flavor.Value)
+	require.Equal(t, int64(64<<20), global.maxManifestSize.Value)
+	require.Equal(t, "/var/lib/node_exporter/par2cron.prom", global.metricsFile)
+	require.Equal(t, [3]int{0, 8, 1}, global.minPar2Version.Value)
+	require.True(t, global.requirePar2Version)
+	require.True(t, global.allowRoot)
+	require.True(t, global.refuseRoot)
+	require.True(t, global.warningsAsErrors)
+	require.Equal(t, "/mnt/cache/tmp", global.tempDir)
+	require.Equal(t, "/mnt/cache/par2cron.pause", global.pauseFile)
+	require.Equal(t, ".par2cronignore", global.ignoreNames.File)
+	require.Equal(t, ".par2cronignore-all", global.ignoreNames.AllFile)
+	require.Equal(t, 2, global.maxDepth.Value)
+	require.False(t, global.maxDepth.Unlimited())
 }
 
 // Expectation: External args should take precedence over YAML config for verify.
@@ -546,14 +751,20 @@ func Test_configFileVerify_Merge_CLIFlagsPrecedence_Success(t *testing.T) {
 	_ = minAge.Set("7d")
 
 	yamlCfg := &configFileVerify{
-		MaxDuration:     &maxDur,
-		MinAge:          &minAge,
-		IncludeExternal: new(true),
-		SkipNotCreated:  new(true),
-		CacheDir:        new("/tmp/cache"),
-		SeqURL:          new("url"),
-		SeqKey:          new("key"),
-		Cgroup:          new("/sys/fs/cgroup/par2limit"),
+		MaxDuration:       &maxDur,
+		MinAge:            &minAge,
+		IncludeExternal:   new(true),
+		SkipNotCreated:    new(true),
+		CleanOrphans:      new(true),
+		MirrorDir:         new("/mnt/backup"),
+		NoManifestUpdate:  new(true),
+		OnlyNeedingRepair: new(true),
+		Order:             &flags.VerifyOrder{Raw: schema.VerifyOrderNewest, Value: schema.VerifyOrderNewest},
+		Tags:              &flags.Tags{Raw: []string{"tier:critical"}, Value: []string{"tier:critical"}},
+		CacheDir:          new("/tmp/cache"),
+		SeqURL:            new("url"),
+		SeqKey:            new("key"),
+		Cgroup:            new("/sys/fs/cgroup/par2limit"),
 	}
 
 	cfg := verify.Options{}
@@ -567,14 +778,20 @@ func Test_configFileVerify_Merge_CLIFlagsPrecedence_Success(t *testing.T) {
 	}
 
 	setFlags := map[string]bool{
-		"duration":         true,
-		"age":              true,
-		"include-external": true,
-		"skip-not-created": true,
-		"cache":            true,
-		"seq-url":          true,
-		"seq-key":          true,
-		"cgroup":           true,
+		"duration":            true,
+		"age":                 true,
+		"include-external":    true,
+		"skip-not-created":    true,
+		"clean-orphans":       true,
+		"mirror":              true,
+		"no-manifest-update":  true,
+		"only-needing-repair": true,
+		"order":               true,
+		"tag":                 true,
+		"cache":               true,
+		"seq-url":             true,
+		"seq-key":             true,
+		"cgroup":              true,
 	}
 
 	global := &globalOptions{logOptions: &logs}
@@ -584,6 +801,12 @@ func Test_configFileVerify_Merge_CLIFlagsPrecedence_Success(t *testing.T) {
 	require.Equal(t, "72h0m0s", cfg.MinAge.Value.String())
 	require.False(t, cfg.IncludeExternal)
 	require.False(t, cfg.SkipNotCreated)
+	require.False(t, cfg.CleanOrphans)
+	require.Empty(t, cfg.MirrorDir)
+	require.False(t, cfg.NoManifestUpdate)
+	require.False(t, cfg.OnlyNeedingRepair)
+	require.Empty(t, cfg.Order.Value)
+	require.Empty(t, cfg.Tags.Value)
 	require.Empty(t, cfg.CacheDir)
 	require.Empty(t, logs.SeqURL)
 	require.Empty(t, logs.SeqKey)
@@ -666,21 +889,47 @@ func Test_configFileRepair_Merge_AllFields_Success(t *testing.T) {
 	LogLevel := flags.LogLevel{}
 	_ = LogLevel.Set("debug")
 
+	par2Flavor := flags.Par2Flavor{}
+	_ = par2Flavor.Set("turbo")
+
 	yamlCfg := &configFileRepair{
 		Par2Args:             &[]string{"-B", "-q"},
 		MaxDuration:          &maxDur,
+		Limit:                new(25),
 		MinTestedCount:       new(5),
 		SkipNotCreated:       new(true),
+		CleanOrphans:         new(true),
+		Tags:                 &flags.Tags{Raw: []string{"tier:critical"}, Value: []string{"tier:critical"}},
 		LogLevel:             &LogLevel,
 		WantJSON:             new(true),
+		FlattenLogs:          new(true),
+		ProgressBar:          new(true),
 		AttemptUnrepairables: new(true),
 		PurgeBackups:         new(true),
 		RestoreBackups:       new(true),
+		Rebaseline:           new(true),
 		Par2Verify:           new(true),
+		NameMismatch:         &flags.NameMismatch{Raw: schema.NameMismatchSkip, Value: schema.NameMismatchSkip},
+		BasePath:             &flags.BasePath{Raw: schema.BasePathScanRoot, Value: schema.BasePathScanRoot},
+		RetrySingleThreaded:  new(true),
+		DereferenceManifest:  new(true),
 		CacheDir:             new("/tmp/cache"),
 		SeqURL:               new("url"),
 		SeqKey:               new("key"),
 		Cgroup:               new("/sys/fs/cgroup/par2limit"),
+		Par2Flavor:           &par2Flavor,
+		MaxManifestSize:      &flags.ByteSize{Raw: "64M", Value: 64 << 20},
+		MetricsFile:          new("/var/lib/node_exporter/par2cron.prom"),
+		TempDir:              new("/mnt/cache/tmp"),
+		PauseFile:            new("/mnt/cache/par2cron.pause"),
+		IgnoreFile:           new(".par2cronignore"),
+		IgnoreAllFile:        new(".par2cronignore-all"),
+		MaxDepth:             &flags.MaxDepth{Raw: "2", Value: 2},
+		LogFile:              new("/var/log/par2cron.log"),
+		LogFileSize:          new(20),
+		LogFileKeep:          new(0),
+		Color:                &flags.Color{Raw: "never", Value: "never"},
+		PathPrefixMap:        &flags.PathPrefixMap{Raw: []string{"/data=/mnt/data"}, Value: []flags.PathPrefix{{From: "/data", To: "/mnt/data"}}},
 	}
 
 	cfg := repair.Options{
@@ -702,18 +951,42 @@ func Test_configFileRepair_Merge_AllFields_Success(t *testing.T) {
 
 	require.Equal(t, []string{"-B", "-q"}, cfg.Par2Args)
 	require.Equal(t, "2h0m0s", cfg.MaxDuration.Value.String())
+	require.Equal(t, 25, cfg.Limit)
 	require.Equal(t, 5, cfg.MinTestedCount)
 	require.True(t, cfg.SkipNotCreated)
+	require.True(t, cfg.CleanOrphans)
+	require.Equal(t, []string{"tier:critical"}, cfg.Tags.Value)
 	require.Equal(t, slog.LevelDebug, logs.LogLevel.Value)
 	require.True(t, logs.WantJSON)
+	require.True(t, logs.FlattenLogs)
+	require.True(t, logs.ProgressBar)
 	require.True(t, cfg.AttemptUnrepairables)
 	require.True(t, cfg.Par2Verify)
 	require.True(t, cfg.PurgeBackups)
 	require.True(t, cfg.RestoreBackups)
+	require.True(t, cfg.Rebaseline)
+	require.Equal(t, schema.NameMismatchSkip, cfg.NameMismatch.Value)
+	require.Equal(t, schema.BasePathScanRoot, cfg.BasePath.Value)
+	require.True(t, cfg.RetrySingleThreaded)
+	require.True(t, cfg.DereferenceManifest)
 	require.Equal(t, "/tmp/cache", cfg.CacheDir)
 	require.Equal(t, "url", logs.SeqURL)
 	require.Equal(t, "key", logs.SeqKey)
 	require.Equal(t, "/sys/fs/cgroup/par2limit", global.cgroupPath)
+	require.Equal(t, schema.Par2FlavorTurbo, global.par2Flavor.Value)
+	require.Equal(t, int64(64<<20), global.maxManifestSize.Value)
+	require.Equal(t, "/var/lib/node_exporter/par2cron.prom", global.metricsFile)
+	require.Equal(t, "/mnt/cache/tmp", global.tempDir)
+	require.Equal(t, "/mnt/cache/par2cron.pause", global.pauseFile)
+	require.Equal(t, ".par2cronignore", global.ignoreNames.File)
+	require.Equal(t, ".par2cronignore-all", global.ignoreNames.AllFile)
+	require.Equal(t, 2, global.maxDepth.Value)
+	require.False(t, global.maxDepth.Unlimited())
+	require.Equal(t, "/var/log/par2cron.log", logs.LogFile)
+	require.Equal(t, 20, logs.LogFileSize)
+	require.Zero(t, logs.LogFileKeep)
+	require.Equal(t, schema.ColorNever, logs.Color.Value)
+	require.Equal(t, "/mnt/data/a.par2", logs.PathPrefixMap.Map("/data/a.par2"))
 }
 
 // Expectation: External args should take precedence over YAML config for repair.
@@ -757,6 +1030,7 @@ func Test_configFileRepair_Merge_CLIFlagsPrecedence_Success(t *testing.T) {
 		AttemptUnrepairables: new(true),
 		PurgeBackups:         new(true),
 		RestoreBackups:       new(true),
+		Rebaseline:           new(true),
 		Par2Verify:           new(true),
 		CacheDir:             new("/tmp/cache"),
 		SeqURL:               new("url"),
@@ -787,6 +1061,7 @@ func Test_configFileRepair_Merge_CLIFlagsPrecedence_Success(t *testing.T) {
 		"attempt-unrepairables": true,
 		"purge-backups":         true,
 		"restore-backups":       true,
+		"rebaseline":            true,
 		"cache":                 true,
 		"seq-url":               true,
 		"seq-key":               true,
@@ -805,6 +1080,7 @@ func Test_configFileRepair_Merge_CLIFlagsPrecedence_Success(t *testing.T) {
 	require.False(t, cfg.Par2Verify)
 	require.False(t, cfg.PurgeBackups)
 	require.False(t, cfg.RestoreBackups)
+	require.False(t, cfg.Rebaseline)
 	require.Empty(t, cfg.CacheDir)
 	require.Empty(t, logs.SeqURL)
 	require.Empty(t, logs.SeqKey)
@@ -888,18 +1164,34 @@ func Test_configFileInfo_Merge_AllFields_Success(t *testing.T) {
 	LogLevel := flags.LogLevel{}
 	_ = LogLevel.Set("error")
 
+	par2Flavor := flags.Par2Flavor{}
+	_ = par2Flavor.Set("turbo")
+
 	yamlCfg := &configFileInfo{
-		MaxDuration:     &maxDur,
-		MinAge:          &minAge,
-		RunInterval:     &RunInterval,
-		LogLevel:        &LogLevel,
-		IncludeExternal: new(true),
-		SkipNotCreated:  new(true),
-		WantJSON:        new(true),
-		CacheDir:        new("/tmp/cache"),
-		SeqURL:          new("url"),
-		SeqKey:          new("key"),
-		Cgroup:          new("/sys/fs/cgroup/par2limit"),
+		MaxDuration:      &maxDur,
+		MinAge:           &minAge,
+		RunInterval:      &RunInterval,
+		LogLevel:         &LogLevel,
+		IncludeExternal:  new(true),
+		SkipNotCreated:   new(true),
+		DetectDuplicates: new(true),
+		Stats:            new(true),
+		Health:           new(true),
+		Ignores:          new(true),
+		TargetRedundancy: &flags.Redundancy{Raw: "20%", Value: 0.2},
+		Tags:             &flags.Tags{Raw: []string{"tier:critical"}, Value: []string{"tier:critical"}},
+		WantJSON:         new(true),
+		FlattenLogs:      new(true),
+		CacheDir:         new("/tmp/cache"),
+		SeqURL:           new("url"),
+		SeqKey:           new("key"),
+		Cgroup:           new("/sys/fs/cgroup/par2limit"),
+		Par2Flavor:       &par2Flavor,
+		MaxManifestSize:  &flags.ByteSize{Raw: "64M", Value: 64 << 20},
+		TempDir:          new("/mnt/cache/tmp"),
+		IgnoreFile:       new(".par2cronignore"),
+		IgnoreAllFile:    new(".par2cronignore-all"),
+		MaxDepth:         &flags.MaxDepth{Raw: "2", Value: 2},
 	}
 
 	cfg := info.Options{}
@@ -921,11 +1213,25 @@ func Test_configFileInfo_Merge_AllFields_Success(t *testing.T) {
 	require.Equal(t, slog.LevelError, logs.LogLevel.Value)
 	require.True(t, cfg.IncludeExternal)
 	require.True(t, cfg.SkipNotCreated)
+	require.True(t, cfg.DetectDuplicates)
+	require.True(t, cfg.Stats)
+	require.True(t, cfg.Health)
+	require.True(t, cfg.Ignores)
+	require.InDelta(t, 0.2, cfg.TargetRedundancy.Value, 1e-9)
+	require.Equal(t, []string{"tier:critical"}, cfg.Tags.Value)
 	require.True(t, logs.WantJSON)
+	require.True(t, logs.FlattenLogs)
 	require.Equal(t, "/tmp/cache", cfg.CacheDir)
 	require.Equal(t, "url", logs.SeqURL)
 	require.Equal(t, "key", logs.SeqKey)
 	require.Equal(t, "/sys/fs/cgroup/par2limit", global.cgroupPath)
+	require.Equal(t, schema.Par2FlavorTurbo, global.par2Flavor.Value)
+	require.Equal(t, int64(64<<20), global.maxManifestSize.Value)
+	require.Equal(t, "/mnt/cache/tmp", global.tempDir)
+	require.Equal(t, ".par2cronignore", global.ignoreNames.File)
+	require.Equal(t, ".par2cronignore-all", global.ignoreNames.AllFile)
+	require.Equal(t, 2, global.maxDepth.Value)
+	require.False(t, global.maxDepth.Unlimited())
 }
 
 // Expectation: CLI flags should take precedence over YAML config for info.
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/desertwitch/par2cron/internal/flags"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	effectiveSourceFlag    = "flag"
+	effectiveSourceConfig  = "config"
+	effectiveSourceDefault = "default"
+)
+
+// effectiveOption is an option of a command as it is effective after merging
+// the command-line flags over the configuration file over the defaults.
+type effectiveOption struct {
+	Key    string
+	Value  any
+	Source string
+}
+
+// effectiveOptions returns the effective options for all keys of the given
+// configuration file section (a typed, but possibly nil pointer). The values
+// are read back from the flags, as the configuration file is merged into the
+// same options, so the section is only needed to tell apart their sources.
+func effectiveOptions(flagSet *pflag.FlagSet, section any, externalArgs []string) []effectiveOption {
+	sv := reflect.ValueOf(section)
+	st := sv.Type().Elem()
+
+	opts := make([]effectiveOption, 0, st.NumField())
+	for i := range st.NumField() {
+		key, _, _ := strings.Cut(st.Field(i).Tag.Get("yaml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+
+		var configured reflect.Value
+		if !sv.IsNil() {
+			configured = sv.Elem().Field(i)
+		}
+		inConfig := configured.IsValid() && !configured.IsNil()
+
+		opt := effectiveOption{Key: key, Source: effectiveSourceDefault}
+		if inConfig {
+			opt.Source = effectiveSourceConfig
+		}
+
+		switch flag := flagSet.Lookup(key); {
+		case key == "args" && externalArgs != nil:
+			opt.Value = externalArgs
+			opt.Source = effectiveSourceFlag
+
+		case flag != nil:
+			opt.Value = flagValue(flag)
+			if flag.Changed {
+				opt.Source = effectiveSourceFlag
+			}
+
+		case inConfig:
+			opt.Value = configured.Elem().Interface()
+
+		default:
+			opt.Value = reflect.Zero(st.Field(i).Type.Elem()).Interface()
+		}
+
+		opts = append(opts, opt)
+	}
+
+	return opts
+}
+
+// flagValue returns the value of a flag as it would be written in the
+// configuration file, falling back to its string representation.
+func flagValue(flag *pflag.Flag) any {
+	switch v := flag.Value.(type) {
+	case *flags.Tags:
+		return append([]string{}, v.Raw...)
+	case *flags.EnvVars:
+		return append([]string{}, v.Raw...)
+	case *flags.PathPrefixMap:
+		return append([]string{}, v.Raw...)
+	case *flags.Par2ExitCodes:
+		return append([]string{}, v.Raw...)
+	case pflag.SliceValue:
+		return v.GetSlice()
+	}
+
+	switch flag.Value.Type() {
+	case "bool":
+		if b, err := strconv.ParseBool(flag.Value.String()); err == nil {
+			return b
+		}
+	case "int":
+		if n, err := strconv.Atoi(flag.Value.String()); err == nil {
+			return n
+		}
+	}
+
+	return flag.Value.String()
+}
+
+// dumpEffectiveConfig writes the effective options of a command, either as a
+// section of the configuration file (with their sources as line comments),
+// or as JSON object (with their sources as a separate object).
+func dumpEffectiveConfig(w io.Writer, command string, opts []effectiveOption, asJSON bool) error {
+	if asJSON {
+		values := make(map[string]any, len(opts))
+		sources := make(map[string]string, len(opts))
+		for _, opt := range opts {
+			values[opt.Key] = opt.Value
+			sources[opt.Key] = opt.Source
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]any{"command": command, "options": values, "sources": sources}); err != nil {
+			return fmt.Errorf("failed to encode json: %w", err)
+		}
+
+		return nil
+	}
+
+	section := &yaml.Node{Kind: yaml.MappingNode}
+	for _, opt := range opts {
+		value := &yaml.Node{}
+		if err := value.Encode(opt.Value); err != nil {
+			return fmt.Errorf("failed to encode %q: %w", opt.Key, err)
+		}
+		if value.Kind == yaml.SequenceNode {
+			value.Style = yaml.FlowStyle
+		}
+		value.LineComment = opt.Source
+
+		section.Content = append(section.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: opt.Key}, value)
+	}
+
+	doc := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: command}, section,
+	}}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2) //nolint:mnd
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode yaml: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode yaml: %w", err)
+	}
+
+	return nil
+}
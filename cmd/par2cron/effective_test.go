@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/flags"
+	"github.com/desertwitch/par2cron/internal/verify"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVerifyFlagSet(opts *verify.Options) *pflag.FlagSet {
+	fs := pflag.NewFlagSet("verify", pflag.ContinueOnError)
+	fs.Var(&opts.MinAge, "age", "")
+	fs.IntVar(&opts.Limit, "limit", 0, "")
+	fs.Var(&opts.Tags, "tag", "")
+	fs.BoolVar(&opts.StrictPar2, "strict-par2", false, "")
+
+	return fs
+}
+
+// Expectation: The effective options should hold the merged values, along with their sources.
+func Test_effectiveOptions_Success(t *testing.T) {
+	t.Parallel()
+
+	var opts verify.Options
+	fs := newTestVerifyFlagSet(&opts)
+	require.NoError(t, fs.Parse([]string{"--limit", "3"}))
+
+	section := &configFileVerify{
+		MinAge: &flags.Duration{},
+		Tags:   &flags.Tags{},
+	}
+	require.NoError(t, section.MinAge.Set("168h"))
+	require.NoError(t, section.Tags.Set("photos"))
+	section.Merge(&opts, newTestGlobal(), false, map[string]bool{"limit": true})
+
+	got := make(map[string]effectiveOption)
+	for _, opt := range effectiveOptions(fs, section, nil) {
+		got[opt.Key] = opt
+	}
+
+	require.Equal(t, effectiveOption{Key: "limit", Value: 3, Source: effectiveSourceFlag}, got["limit"])
+	require.Equal(t, effectiveOption{Key: "age", Value: "168h", Source: effectiveSourceConfig}, got["age"])
+	require.Equal(t, effectiveOption{Key: "tag", Value: []string{"photos"}, Source: effectiveSourceConfig}, got["tag"])
+	require.Equal(t, effectiveOption{Key: "strict-par2", Value: false, Source: effectiveSourceDefault}, got["strict-par2"])
+	require.Equal(t, effectiveOption{Key: "allowed-args", Value: []string(nil), Source: effectiveSourceDefault}, got["allowed-args"])
+}
+
+// Expectation: The par2 arguments after "--" should take precedence, also without any configuration file.
+func Test_effectiveOptions_ExternalArgs_Success(t *testing.T) {
+	t.Parallel()
+
+	var opts verify.Options
+	fs := newTestVerifyFlagSet(&opts)
+
+	got := make(map[string]effectiveOption)
+	for _, opt := range effectiveOptions(fs, (*configFileVerify)(nil), []string{"-q"}) {
+		got[opt.Key] = opt
+	}
+
+	require.Equal(t, effectiveOption{Key: "args", Value: []string{"-q"}, Source: effectiveSourceFlag}, got["args"])
+	require.Equal(t, effectiveOption{Key: "limit", Value: 0, Source: effectiveSourceDefault}, got["limit"])
+}
+
+// Expectation: The effective options should be dumped as configuration file section or as JSON.
+func Test_dumpEffectiveConfig_Success(t *testing.T) {
+	t.Parallel()
+
+	opts := []effectiveOption{
+		{Key: "limit", Value: 3, Source: effectiveSourceFlag},
+		{Key: "tag", Value: []string{"photos"}, Source: effectiveSourceConfig},
+		{Key: "glob", Value: "*", Source: effectiveSourceDefault},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, dumpEffectiveConfig(&buf, "verify", opts, false))
+	require.Equal(t, "verify:\n"+
+		"  limit: 3 # flag\n"+
+		"  tag: [photos] # config\n"+
+		"  glob: '*' # default\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, dumpEffectiveConfig(&buf, "verify", opts, true))
+
+	var got struct {
+		Command string            `json:"command"`
+		Options map[string]any    `json:"options"`
+		Sources map[string]string `json:"sources"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	require.Equal(t, "verify", got.Command)
+	require.InDelta(t, 3, got.Options["limit"], 0)
+	require.Equal(t, []any{"photos"}, got.Options["tag"])
+	require.Equal(t, effectiveSourceConfig, got.Sources["tag"])
+}
+
+// Expectation: The commands with a configuration file section should have a "dump-effective-config" flag.
+func Test_NewRootCmd_HasDumpEffectiveConfigFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRootCmd(t.Context())
+
+	for _, name := range []string{"create", "recreate", "verify", "repair", "info"} {
+		sub, _, err := cmd.Find([]string{name})
+		require.NoError(t, err)
+
+		flag := sub.Flags().Lookup("dump-effective-config")
+		require.NotNil(t, flag, name)
+		require.Equal(t, "bool", flag.Value.Type())
+	}
+}
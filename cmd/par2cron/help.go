@@ -72,7 +72,47 @@ Pass "-r15 -n1" (15% redundancy, 1 recovery file) to par2:
   par2cron create /mnt/storage -- -r15 -n1
 
 Run for around 1 hour (as soft limit), hide created files:
-  par2cron create -d 1h --hidden /mnt/storage`
+  par2cron create -d 1h --hidden /mnt/storage
+
+Compute the block count per set for blocks of around 4 MiB:
+  par2cron create --target-block-size 4M /mnt/storage
+
+Protect all files except temporary and log files:
+  par2cron create --glob-exclude '*.tmp' --glob-exclude '*.log' /mnt/storage`
+
+const recreateUsage = "recreate [flags] <dir> [dir...] [-- par2-arg...]"
+
+const recreateHelpShort = "Re-creates PAR2 sets created with outdated arguments"
+
+const recreateHelpLong = `Re-creates the PAR2 sets created with outdated par2 arguments
+Replaces them with sets created with the current par2 arguments
+
+Scans a directory tree for par2cron manifests whose recorded
+creation arguments differ from the current ones (as for create,
+also from the "create" section of a --config file). With the
+--older-redundancy-than flag, only sets with less redundancy
+than given are re-created, regardless of their other arguments.
+
+Each new set is created next to the old one, which is only
+replaced once the new set is complete, so a failure leaves the
+old set intact. Sets are only re-created if they were last
+verified as healthy, so verify shortly before re-creating.
+
+To exclude directories from this operation, put ignore files:
+  - ".par2cron-ignore" (ignore directory)
+  - ".par2cron-ignore-all" (ignore directory and subdirectories)
+
+Full documentation at: https://github.com/desertwitch/par2cron`
+
+const recreateHelpExample = `
+List the sets created with other arguments than "-r15 -n1":
+  par2cron recreate --dry-run /mnt/storage -- -r15 -n1
+
+Re-create sets with less than 10% redundancy at 15% redundancy:
+  par2cron recreate --older-redundancy-than 10% /mnt/storage -- -r15
+
+Run for around 1 hour (as soft limit), verify the new sets:
+  par2cron recreate -d 1h -v -c /tmp/par2cron.yaml /mnt/storage`
 
 const verifyUsage = "verify [flags] <dir> [dir...] [-- par2-arg...]"
 
@@ -101,7 +141,10 @@ Verify all sets, argument "-q" (quiet mode) for par2:
   par2cron verify /mnt/storage -- -q
 
 Verify sets not verified < 7 days, run around 2 hours:
-  par2cron verify -a 7d -d 2h /mnt/storage`
+  par2cron verify -a 7d -d 2h /mnt/storage
+
+Verify only the sets (or directories) listed in a file:
+  par2cron verify --from-stdin /mnt/storage < changed.txt`
 
 const repairUsage = "repair [flags] <dir> [dir...] [-- par2-arg...]"
 
@@ -155,9 +198,130 @@ Analyze a 7-day cycle with 2-hour daily runs:
 Analyze a 14-day cycle with 4-hour weekly runs:
   par2cron info -a 14d -d 4h -i 1w /mnt/storage
 
+Report PAR2 sets duplicated across directories:
+  par2cron info --detect-duplicates /mnt/storage
+
+Report redundancy statistics across the library:
+  par2cron info --stats /mnt/storage
+
+Estimate the parity size of re-creating all sets at 20% redundancy:
+  par2cron info --target-redundancy 0.2 /mnt/storage
+
+Report PAR2 sets with a lowered health score:
+  par2cron info --health /mnt/storage
+
+Report ignore files honored during the scan:
+  par2cron info --ignores /mnt/storage
+
 Output results as JSON (stdout/standard output):
   par2cron info --json /mnt/storage`
 
+const exportUsage = "export [flags] <dir> [dir...]"
+
+const exportHelpShort = "Exports all manifests as one consolidated JSON/CSV"
+
+const exportHelpLong = `Exports the manifests of all PAR2 sets as one JSON document or CSV table
+Walks the directory tree, reads every par2cron manifest and writes one
+record per PAR2 set (sorted by path) to standard output, containing:
+
+  - the path of the PAR2 set and whether it is a bundle
+  - the creation time and mode, number and total size of protected files
+  - the last verification time, verdict, exit code and duration
+  - the number of verifications (and consecutive corrupted verifications)
+  - the last repair time, number of repairs and exit code
+
+The CSV table is UTF-8 encoded (RFC 4180), with a header row and quoting
+of fields containing separators, quotes or line breaks. Absent values are
+empty fields, durations are in nanoseconds and times in RFC 3339 format.
+Paths containing bytes that are not valid UTF-8 have these escaped (\xNN).
+
+This operation is read-only and does not use or update the manifest cache.
+
+To exclude directories from this operation, put ignore files:
+  - ".par2cron-ignore" (ignore directory)
+  - ".par2cron-ignore-all" (ignore directory and subdirectories)
+
+Full documentation at: https://github.com/desertwitch/par2cron`
+
+const exportHelpExample = `
+Export all manifests as a JSON document:
+  par2cron export /mnt/storage > manifests.json
+
+Export all manifests as a CSV table for a spreadsheet:
+  par2cron export -f csv /mnt/storage > manifests.csv
+
+Export also external PAR2 sets (without par2cron manifest):
+  par2cron export -e -f csv /mnt/storage > manifests.csv`
+
+const attentionUsage = "attention [flags] <dir> [dir...]"
+
+const attentionHelpShort = "Lists the PAR2 sets needing attention (for alerting)"
+
+const attentionHelpLong = `Lists the PAR2 sets needing attention, for alerting (e.g. nightly emails)
+Walks the directory tree, reads every par2cron manifest and writes the PAR2
+sets needing attention (sorted by path) to standard output, these being:
+
+  - PAR2 sets with corruption, which are repairable
+  - PAR2 sets with corruption, which are unrepairable
+  - PAR2 sets never verified, which are overdue after their creation
+
+A never verified PAR2 set is overdue when it was not verified within the
+--overdue period after its creation, or within its verify-interval (marker
+configuration) if it has one. PAR2 sets without a creation record can only
+need attention once verified. With --json the PAR2 sets are written as one
+JSON document, including the path, verdict and last verification time.
+
+The exit code is non-zero if any PAR2 sets need attention, allowing for
+alerts to be sent conditionally. It is the exit code of the most severe
+verdict: unrepairable (4), repairable (3) and overdue (6, as warnings).
+
+This operation is read-only and does not use or update the manifest cache.
+
+To exclude directories from this operation, put ignore files:
+  - ".par2cron-ignore" (ignore directory)
+  - ".par2cron-ignore-all" (ignore directory and subdirectories)
+
+Full documentation at: https://github.com/desertwitch/par2cron`
+
+const attentionHelpExample = `
+List the PAR2 sets needing attention:
+  par2cron attention /mnt/storage
+
+Send an alert email only if any PAR2 sets need attention:
+  par2cron attention --json /mnt/storage > attention.json || mail -s "par2cron" root < attention.json
+
+Consider never verified PAR2 sets overdue after two weeks:
+  par2cron attention -o 14d /mnt/storage`
+
+const restoreMetadataUsage = "restore-metadata [flags] <dir> [dir...]"
+
+const restoreMetadataHelpShort = "Restores the recorded ownership and permissions of protected files"
+
+const restoreMetadataHelpLong = `Restores the recorded ownership and permissions of protected files
+Walks the directory tree, reads every par2cron manifest and restores the
+owner, group and permissions of the protected files, as they were recorded
+in the creation record of the PAR2 set (when created with --record-metadata).
+
+This is meant for after a repair, as par2 recreates missing files with their
+content, but not necessarily with their original ownership and permissions.
+Files that already have the recorded metadata are left as they are, and PAR2
+sets without recorded metadata are not changed. Restoring the ownership of
+files owned by other users usually requires running as root. Ownership is
+not recorded (or restored) on Windows.
+
+To exclude directories from this operation, put ignore files:
+  - ".par2cron-ignore" (ignore directory)
+  - ".par2cron-ignore-all" (ignore directory and subdirectories)
+
+Full documentation at: https://github.com/desertwitch/par2cron`
+
+const restoreMetadataHelpExample = `
+Show which files would have their metadata restored:
+  par2cron restore-metadata --dry-run /mnt/storage
+
+Restore the metadata of the protected files after a repair:
+  sudo par2cron restore-metadata /mnt/storage`
+
 const bundleUsage = "bundle"
 
 const bundleHelpShort = "Commands for interacting with par2cron's bundle format"
@@ -251,6 +415,37 @@ Print information about multiple bundle files:
 Print information about bundle files in working directory:
   par2cron bundle info *.p2c.par2`
 
+const selfTestUsage = "self-test [flags] [dir] [-- par2-args...]"
+
+const selfTestHelpShort = "Runs an end-to-end self-test in a scratch directory"
+
+const selfTestHelpLong = `Runs an end-to-end self-test of par2cron and par2
+Generates sample files in a temporary directory (within [dir] or the
+system's temporary directory), then runs through the following stages:
+
+  - generate:  sample files are written along with a marker file
+  - create:    a PAR2 set is created for the sample files
+  - corrupt:   one sample file is deliberately corrupted
+  - verify:    verification must report the PAR2 set as repairable
+  - repair:    repair must restore the corrupted sample file
+  - re-verify: verification must report the PAR2 set as healthy
+
+A pass/fail report for each stage is printed to standard output.
+Once a stage fails, all remaining stages are skipped and the command
+returns a non-zero exit code. The scratch directory is always removed.
+
+Full documentation at: https://github.com/desertwitch/par2cron`
+
+const selfTestHelpExample = `
+Run the self-test in the system's temporary directory:
+  par2cron self-test
+
+Run the self-test on the filesystem to be protected:
+  par2cron self-test /mnt/storage
+
+Run the self-test with custom par2 creation arguments:
+  par2cron self-test /mnt/storage -- -r15 -n2`
+
 const toolUsage = "tool"
 
 const toolHelpShort = "Useful utility commands for interacting with PAR2 files"
@@ -465,3 +465,19 @@ func Test_Integration_GenMarkdownCmd_NonexistentDir_Error(t *testing.T) {
 	cmd.SetArgs([]string{"gen-markdown", dir})
 	require.ErrorIs(t, cmd.Execute(), schema.ErrExitBadInvocation)
 }
+
+// Expectation: The "self-test" command should pass all stages and leave no scratch directory behind.
+//
+//nolint:paralleltest
+func Test_Integration_SelfTestCmd_Success(t *testing.T) {
+	dir := t.TempDir()
+
+	cmd := newRootCmd(t.Context())
+	cmd.SetArgs([]string{"self-test", dir})
+
+	require.NoError(t, cmd.Execute())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
@@ -41,19 +41,24 @@ import (
 	"slices"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/desertwitch/par2cron/internal/bundler"
 	"github.com/desertwitch/par2cron/internal/create"
+	"github.com/desertwitch/par2cron/internal/export"
+	"github.com/desertwitch/par2cron/internal/flags"
 	"github.com/desertwitch/par2cron/internal/info"
 	"github.com/desertwitch/par2cron/internal/logging"
 	"github.com/desertwitch/par2cron/internal/repair"
 	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/selftest"
 	"github.com/desertwitch/par2cron/internal/tool"
 	"github.com/desertwitch/par2cron/internal/util"
 	"github.com/desertwitch/par2cron/internal/verify"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/spf13/cobra/doc"
+	"github.com/spf13/pflag"
 )
 
 var (
@@ -61,6 +66,15 @@ var (
 	profFileMem *os.File
 )
 
+// defaultMinPar2Version is the known-good minimum version of "par2", as older
+// versions have bugs affecting (among others) the processing of large files.
+const defaultMinPar2Version = "0.8.0"
+
+var (
+	errPar2Outdated  = errors.New("par2 is outdated")
+	errRunningAsRoot = errors.New("running as root")
+)
+
 func checkForPar2(ctx context.Context, runner schema.CommandRunner, errout io.Writer) error {
 	var out bytes.Buffer
 
@@ -79,6 +93,53 @@ func checkForPar2(ctx context.Context, runner schema.CommandRunner, errout io.Wr
 	return nil
 }
 
+// checkPar2Version returns [errPar2Outdated] if the version of "par2" (as
+// captured by [checkForPar2]) is older than --min-par2-version, or if it
+// cannot be determined (with any --min-par2-version set).
+func checkPar2Version(opts *globalOptions) error {
+	if opts.minPar2Version.Raw == "" {
+		return nil
+	}
+
+	version, ok := util.ParsePar2Version(schema.Par2Version)
+	if !ok {
+		return fmt.Errorf("%w: version not found in %q, want at least %s",
+			errPar2Outdated, schema.Par2Version, opts.minPar2Version.Raw)
+	}
+	if slices.Compare(version[:], opts.minPar2Version.Value[:]) < 0 {
+		return fmt.Errorf("%w: version %d.%d.%d, want at least %s",
+			errPar2Outdated, version[0], version[1], version[2], opts.minPar2Version.Raw)
+	}
+
+	return nil
+}
+
+// checkRoot returns [errRunningAsRoot] if the effective user ID (as given by
+// [os.Geteuid]) is the one of root, unless that is allowed with --allow-root.
+// As the user ID is -1 on platforms without one (Windows), it never is root.
+func checkRoot(opts *globalOptions, euid int) error {
+	if opts.allowRoot || euid != 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: effective user ID %d", errRunningAsRoot, euid)
+}
+
+// checkWarnings returns [schema.ErrExitWarnings] if any warnings (or errors)
+// were logged during the run, where these are to be treated as errors (with
+// --warnings-as-errors), so that the run does not end successfully.
+func checkWarnings(opts *globalOptions, log *logging.Logger) error {
+	if !opts.warningsAsErrors {
+		return nil
+	}
+
+	if n := log.Warnings(); n > 0 {
+		return fmt.Errorf("%w: %d warnings (--warnings-as-errors)", schema.ErrExitWarnings, n)
+	}
+
+	return nil
+}
+
 func stopProfile() {
 	if profFile != nil {
 		pprof.StopCPUProfile()
@@ -95,6 +156,21 @@ func stopProfileMem() {
 	}
 }
 
+// profileFlagAliases maps the long-form profiling flag names to their
+// established short names, so either spelling can be used by the user.
+var profileFlagAliases = map[string]string{
+	"cpu-profile": "pprof",
+	"mem-profile": "mprof",
+}
+
+func normalizeFlagName(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+	if alias, ok := profileFlagAliases[name]; ok {
+		name = alias
+	}
+
+	return pflag.NormalizedName(name)
+}
+
 func wrapArgsError(validator cobra.PositionalArgs) cobra.PositionalArgs {
 	return func(cmd *cobra.Command, args []string) error {
 		if err := validator(cmd, args); err != nil {
@@ -106,19 +182,103 @@ func wrapArgsError(validator cobra.PositionalArgs) cobra.PositionalArgs {
 }
 
 type globalOptions struct {
-	cgroupPath string
-	logOptions *logging.Options
+	cgroupPath   string
+	ioThrottle   flags.IOThrottle
+	par2Env      flags.EnvVars
+	par2Flavor   flags.Par2Flavor
+	tempDir      string
+	summaryFile  string
+	metricsFile  string
+	pauseFile    string
+	ignoreNames  util.IgnoreNames
+	sidecarNames util.SidecarNames
+	maxDepth     flags.MaxDepth
+	noRecurse    bool
+	logOptions   *logging.Options
+
+	// maxManifestSize is the size above which manifests are refused as
+	// invalid when read (guarding against pathologically grown manifests).
+	maxManifestSize flags.ByteSize
+
+	// minPar2Version is the minimum version of "par2" below which a warning
+	// is emitted at startup, or which fails it with requirePar2Version.
+	minPar2Version     flags.Version
+	requirePar2Version bool
+
+	// allowRoot silences the warning emitted at startup when running as root,
+	// which refuseRoot instead turns into a failure (allowRoot has precedence).
+	allowRoot  bool
+	refuseRoot bool
+
+	// warningsAsErrors fails a run which has logged any warnings (or errors),
+	// even if all of its jobs succeeded (as a stricter health gate).
+	warningsAsErrors bool
+
+	// allowedPar2Args are the par2 arguments permitted in addition to the
+	// default allowlist ([util.DefaultAllowedPar2Args]), as set from the
+	// configuration file.
+	allowedPar2Args []string
 }
 
 func newGlobalOptions() *globalOptions {
 	opts := &globalOptions{
+		ignoreNames: util.IgnoreNames{
+			File:    schema.IgnoreFile,
+			AllFile: schema.IgnoreAllFile,
+		},
+		sidecarNames: util.SidecarNames{
+			ManifestSuffix: schema.ManifestExtension,
+			LockSuffix:     schema.LockExtension,
+		},
 		logOptions: &logging.Options{},
 	}
+	_ = opts.par2Flavor.Set(schema.Par2FlavorAuto)
+	_ = opts.minPar2Version.Set(defaultMinPar2Version)
 	_ = opts.logOptions.LogLevel.Set("info")
+	_ = opts.logOptions.Color.Set(schema.ColorAuto)
 
 	return opts
 }
 
+// resolveMaxDepth applies --no-recurse as a maximum depth of 0 (the given
+// directory only), which must not be combined with an explicit --max-depth.
+func resolveMaxDepth(opts *globalOptions, maxDepthSet bool) error {
+	if !opts.noRecurse {
+		return nil
+	}
+	if maxDepthSet {
+		return errors.New("--no-recurse cannot be combined with --max-depth")
+	}
+
+	return opts.maxDepth.Set("0") //nolint:wrapcheck
+}
+
+// isTerminal returns if the reader is a terminal (and not a pipe or file),
+// which is the case when par2cron is run manually (but not from cron).
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// resolvePar2Flavor sets the runtime "par2" flavor, either as pinned by the
+// user or as detected from the version output captured by [checkForPar2].
+func resolvePar2Flavor(opts *globalOptions) {
+	if opts.par2Flavor.Value == "" || opts.par2Flavor.Value == schema.Par2FlavorAuto {
+		schema.Par2Flavor = util.DetectPar2Flavor(schema.Par2Version)
+	} else {
+		schema.Par2Flavor = opts.par2Flavor.Value
+	}
+}
+
 func newRunner(opts *globalOptions) (*util.CtxRunner, error) {
 	var ropts []util.RunnerOption
 
@@ -126,6 +286,22 @@ func newRunner(opts *globalOptions) (*util.CtxRunner, error) {
 		ropts = append(ropts, util.WithCgroup(opts.cgroupPath))
 	}
 
+	if opts.ioThrottle.Value != "" {
+		ropts = append(ropts, util.WithIOThrottle(opts.ioThrottle.Value, opts.ioThrottle.Level))
+	}
+
+	// The temporary directory is passed first, as to remain overridable
+	// through an explicitly given TMPDIR environment variable (--par2-env).
+	var env []string
+	if opts.tempDir != "" {
+		env = append(env, "TMPDIR="+opts.tempDir)
+	}
+	env = append(env, opts.par2Env.Value...)
+
+	if len(env) > 0 {
+		ropts = append(ropts, util.WithEnv(env))
+	}
+
 	runner, err := util.NewCtxRunner(ropts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create runner: %w", err)
@@ -173,29 +349,63 @@ func newRootCmd(ctx context.Context) *cobra.Command {
 			return nil
 		},
 	}
-	rootCmd.PersistentFlags().String("pprof", "", "write CPU performance profile to file")
-	rootCmd.PersistentFlags().String("mprof", "", "write RAM allocation profile to file")
+	rootCmd.PersistentFlags().String("pprof", "", "write CPU performance profile to file (alias: --cpu-profile)")
+	rootCmd.PersistentFlags().String("mprof", "", "write RAM allocation profile to file (alias: --mem-profile)")
 	rootCmd.PersistentFlags().StringVar(&globalOptions.cgroupPath, "cgroup", "", "cgroup v2 directory to constrain par2 processes")
+	rootCmd.PersistentFlags().Var(&globalOptions.ioThrottle, "io-throttle", "I/O scheduling class of par2 processes (none|idle|best-effort[:0-7])")
+	rootCmd.PersistentFlags().StringVar(&globalOptions.ignoreNames.File, "ignore-file", schema.IgnoreFile, "filename of ignore files (ignore directory)")
+	rootCmd.PersistentFlags().StringVar(&globalOptions.ignoreNames.AllFile, "ignore-all-file", schema.IgnoreAllFile, "filename of ignore-all files (ignore directory and subdirectories)")
+	rootCmd.PersistentFlags().StringVar(&globalOptions.sidecarNames.ManifestSuffix, "manifest-suffix", schema.ManifestExtension, "filename suffix of manifests (appended to the PAR2 filename)")
+	rootCmd.PersistentFlags().StringVar(&globalOptions.sidecarNames.LockSuffix, "lock-suffix", schema.LockExtension, "filename suffix of lock files (appended to the PAR2 filename)")
+	rootCmd.PersistentFlags().BoolVar(&globalOptions.sidecarNames.Hidden, "hidden-sidecars", false, "always name manifests and lock files as hidden (also for non-hidden PAR2 sets)")
+	rootCmd.PersistentFlags().Var(&globalOptions.maxManifestSize, "max-manifest-size", "refuse manifests larger than this size as invalid when read (e.g. 64M; 0 for no limit)")
+	rootCmd.PersistentFlags().Var(&globalOptions.maxDepth, "max-depth", "maximum directory depth below each given directory to enumerate (0 = given directory only)")
+	rootCmd.PersistentFlags().BoolVar(&globalOptions.noRecurse, "no-recurse", false, "only enumerate each given directory itself, not its subdirectories (same as --max-depth 0)")
+	rootCmd.PersistentFlags().Var(&globalOptions.par2Env, "par2-env", "environment variable KEY=VALUE passed to par2 processes (can be repeated)")
+	rootCmd.PersistentFlags().Var(&globalOptions.par2Flavor, "par2-flavor", "flavor of the installed par2 (auto|par2cmdline|turbo)")
+	rootCmd.PersistentFlags().Var(&globalOptions.minPar2Version, "min-par2-version", "minimum version of the installed par2, warning at startup if older (empty to disable)")
+	rootCmd.PersistentFlags().BoolVar(&globalOptions.requirePar2Version, "require-par2-version", false, "fail at startup if the installed par2 is older than --min-par2-version")
+	rootCmd.PersistentFlags().BoolVar(&globalOptions.allowRoot, "allow-root", false, "do not warn at startup of create, recreate, verify and repair when running as root")
+	rootCmd.PersistentFlags().BoolVar(&globalOptions.refuseRoot, "refuse-root", false, "fail at startup of create, recreate, verify and repair when running as root (unless --allow-root)")
+	rootCmd.PersistentFlags().StringVar(&globalOptions.pauseFile, "pause-file", "", "skip all work of create, verify, repair and restore-metadata while this (sentinel) file exists")
+	rootCmd.PersistentFlags().BoolVar(&globalOptions.warningsAsErrors, "warnings-as-errors", false, "fail create, verify, repair and restore-metadata runs with a dedicated exit code if any warnings were logged")
+	rootCmd.PersistentFlags().StringVar(&globalOptions.summaryFile, "summary-file", "", "write a human-readable summary of each run to file (replaced atomically)")
+	rootCmd.PersistentFlags().StringVar(&globalOptions.metricsFile, "metrics-file", "", "write the last run and last success time of each operation to an OpenMetrics file (replaced atomically)")
+	rootCmd.PersistentFlags().StringVar(&globalOptions.tempDir, "temp-dir", "", "directory for temporary files (of par2 processes and par2cron itself)")
 	rootCmd.PersistentFlags().VarP(&globalOptions.logOptions.LogLevel, "log-level", "l", "minimum level of emitted logs (debug|info|warn|error)")
 	rootCmd.PersistentFlags().StringVar(&globalOptions.logOptions.SeqURL, "seq-url", "", "CLEF ingestion URL for a (remote) Seq logging server")
 	rootCmd.PersistentFlags().StringVar(&globalOptions.logOptions.SeqKey, "seq-key", "", "API key for a (remote) Seq logging server")
+	rootCmd.PersistentFlags().StringVar(&globalOptions.logOptions.LogFile, "log-file", "", "additionally write logs to a (size-rotated) log file")
+	rootCmd.PersistentFlags().IntVar(&globalOptions.logOptions.LogFileSize, "log-file-size", logging.DefaultLogFileSize, "size in MiB at which the log file is rotated")
+	rootCmd.PersistentFlags().IntVar(&globalOptions.logOptions.LogFileKeep, "log-file-keep", logging.DefaultLogFileKeep, "number of rotated log files to keep")
 	rootCmd.PersistentFlags().BoolVar(&globalOptions.logOptions.WantJSON, "json", false, "output results/logs in JSON format (where applicable)")
+	rootCmd.PersistentFlags().BoolVar(&globalOptions.logOptions.FlattenLogs, "flatten-logs", false, "output logs as one logfmt line per record with a fixed field order (in place of text or JSON logs)")
+	rootCmd.PersistentFlags().Var(&globalOptions.logOptions.Color, "color", "colorize text logs by level (auto|always|never)")
+	rootCmd.PersistentFlags().BoolVar(&globalOptions.logOptions.ProgressBar, "progress-bar", false, "show the progress of jobs on a status line below the logs (text logs to a terminal only)")
+	rootCmd.PersistentFlags().Var(&globalOptions.logOptions.PathPrefixMap, "path-prefix-map", "rewrite displayed paths with prefix from to prefix to (can be repeated)")
+
+	rootCmd.SetGlobalNormalizationFunc(normalizeFlagName)
 
 	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
 		return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
 	})
 
 	createCmd := newCreateCmd(ctx, globalOptions)
+	recreateCmd := newRecreateCmd(ctx, globalOptions)
 	verifyCmd := newVerifyCmd(ctx, globalOptions)
 	repairCmd := newRepairCmd(ctx, globalOptions)
 
 	infoCmd := newInfoCmd(ctx, globalOptions)
 	toolCmd := newToolCmd(ctx, globalOptions)
 	bundleCmd := newBundleCmd(ctx, globalOptions)
+	exportCmd := newExportCmd(ctx, globalOptions)
+	attentionCmd := newAttentionCmd(ctx, globalOptions)
+	restoreMetadataCmd := newRestoreMetadataCmd(ctx, globalOptions)
+	selfTestCmd := newSelfTestCmd(ctx, globalOptions)
 	checkConfigCmd := newCheckConfigCmd(ctx)
 	genMarkdownCmd := newGenMarkdownCmd(rootCmd)
 
-	rootCmd.AddCommand(createCmd, verifyCmd, repairCmd, infoCmd, toolCmd, bundleCmd, checkConfigCmd, genMarkdownCmd)
+	rootCmd.AddCommand(createCmd, recreateCmd, verifyCmd, repairCmd, infoCmd, exportCmd, attentionCmd, restoreMetadataCmd, toolCmd, bundleCmd, selfTestCmd, checkConfigCmd, genMarkdownCmd)
 
 	return rootCmd
 }
@@ -289,6 +499,7 @@ func newBundlePackCmd(ctx context.Context, globalOptions *globalOptions) *cobra.
 	var resolvedPaths []string
 
 	fsys := afero.NewOsFs()
+	op := operation{name: "bundle pack"}
 
 	globalOptions.logOptions.Logout = os.Stderr
 	globalOptions.logOptions.Stdout = os.Stdout
@@ -299,12 +510,29 @@ func newBundlePackCmd(ctx context.Context, globalOptions *globalOptions) *cobra.
 		Short: bundlePackHelpShort,
 		Long:  bundlePackHelpLong,
 		Args:  wrapArgsError(cobra.MinimumNArgs(1)),
-		PreRunE: func(_ *cobra.Command, args []string) error {
+		PreRunE: func(cmd *cobra.Command, args []string) error {
 			resolved, err := resolvePathArgs(fsys, args)
 			if err != nil {
 				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
 			}
 
+			if err := globalOptions.ignoreNames.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := globalOptions.sidecarNames.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := globalOptions.logOptions.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := resolveMaxDepth(globalOptions, cmd.Flags().Changed("max-depth")); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			bundlerOptions.IgnoreNames = globalOptions.ignoreNames
+			bundlerOptions.SidecarNames = globalOptions.sidecarNames
+			bundlerOptions.MaxManifestSize = globalOptions.maxManifestSize.Value
+			bundlerOptions.MaxDepth = globalOptions.maxDepth
+
 			resolvedPaths = slices.Clone(resolved)
 
 			return nil
@@ -323,7 +551,7 @@ func newBundlePackCmd(ctx context.Context, globalOptions *globalOptions) *cobra.
 			ctx := context.WithValue(ctx, schema.ModeKey, "pack")
 
 			result, err := prog.BundlerService.Pack(ctx, resolvedPaths, bundlerOptions)
-			logOperationResult(err, result, prog.log.With("op", "bundle", "mode", "pack"))
+			err = op.finish(fsys, globalOptions, err, result, prog.log.With("op", "bundle", "mode", "pack"))
 			if err != nil {
 				return fmt.Errorf("bundle: pack: %w", err)
 			}
@@ -342,6 +570,7 @@ func newBundleUnpackCmd(ctx context.Context, globalOptions *globalOptions) *cobr
 	var resolvedPaths []string
 
 	fsys := afero.NewOsFs()
+	op := operation{name: "bundle unpack"}
 
 	globalOptions.logOptions.Logout = os.Stderr
 	globalOptions.logOptions.Stdout = os.Stdout
@@ -352,12 +581,29 @@ func newBundleUnpackCmd(ctx context.Context, globalOptions *globalOptions) *cobr
 		Short: bundleUnpackHelpShort,
 		Long:  bundleUnpackHelpLong,
 		Args:  wrapArgsError(cobra.MinimumNArgs(1)),
-		PreRunE: func(_ *cobra.Command, args []string) error {
+		PreRunE: func(cmd *cobra.Command, args []string) error {
 			resolved, err := resolvePathArgs(fsys, args)
 			if err != nil {
 				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
 			}
 
+			if err := globalOptions.ignoreNames.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := globalOptions.sidecarNames.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := globalOptions.logOptions.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := resolveMaxDepth(globalOptions, cmd.Flags().Changed("max-depth")); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			bundlerOptions.IgnoreNames = globalOptions.ignoreNames
+			bundlerOptions.SidecarNames = globalOptions.sidecarNames
+			bundlerOptions.MaxManifestSize = globalOptions.maxManifestSize.Value
+			bundlerOptions.MaxDepth = globalOptions.maxDepth
+
 			resolvedPaths = slices.Clone(resolved)
 
 			return nil
@@ -376,7 +622,7 @@ func newBundleUnpackCmd(ctx context.Context, globalOptions *globalOptions) *cobr
 			ctx := context.WithValue(ctx, schema.ModeKey, "unpack")
 
 			result, err := prog.BundlerService.Unpack(ctx, resolvedPaths, bundlerOptions)
-			logOperationResult(err, result, prog.log.With("op", "bundle", "mode", "unpack"))
+			err = op.finish(fsys, globalOptions, err, result, prog.log.With("op", "bundle", "mode", "unpack"))
 			if err != nil {
 				return fmt.Errorf("bundle: unpack: %w", err)
 			}
@@ -454,8 +700,10 @@ func newCreateCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comm
 	var createOptions create.Options
 	var configPath string
 	var resolvedPaths []string
+	var dumpConfig bool
 
 	fsys := afero.NewOsFs()
+	op := operation{name: "create", par2: true, root: true, warnings: true}
 
 	globalOptions.logOptions.Logout = os.Stderr
 	globalOptions.logOptions.Stdout = os.Stdout
@@ -470,8 +718,10 @@ func newCreateCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comm
 		Example: createHelpExample,
 		Args:    wrapArgsError(cobra.MinimumNArgs(1)),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkForPar2(ctx, &util.CtxRunner{}, globalOptions.logOptions.Stderr); err != nil {
-				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+			if !dumpConfig {
+				if err := checkForPar2(ctx, &util.CtxRunner{}, globalOptions.logOptions.Stderr); err != nil {
+					return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+				}
 			}
 
 			result, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
@@ -487,12 +737,29 @@ func newCreateCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comm
 			if err != nil {
 				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
 			}
+			createOptions.IgnoreNames = globalOptions.ignoreNames
+			createOptions.SidecarNames = globalOptions.sidecarNames
+			createOptions.MaxManifestSize = globalOptions.maxManifestSize.Value
+			createOptions.MaxDepth = globalOptions.maxDepth
+			createOptions.PauseFile = globalOptions.pauseFile
+			if err := op.preRun(globalOptions, dumpConfig); err != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+			}
 
 			resolvedPaths = slices.Clone(result.ResolvedPaths)
 
+			if dumpConfig {
+				return dumpEffectiveConfig(cmd.OutOrStdout(), "create",
+					effectiveOptions(cmd.Flags(), result.Section, result.ExternalArgs), globalOptions.logOptions.WantJSON)
+			}
+
 			return nil
 		},
 		RunE: func(_ *cobra.Command, _ []string) (ret error) { //nolint:nonamedreturns
+			if dumpConfig {
+				return nil
+			}
+
 			runner, rerr := newRunner(globalOptions)
 			if rerr != nil {
 				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, rerr)
@@ -502,9 +769,10 @@ func newCreateCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comm
 			prog := NewProgram(fsys, *globalOptions.logOptions, runner, &util.BundleHandler{}, &util.Par2Handler{}, util.GobCacheHandler{})
 			defer prog.Shutdown()
 			defer recoverOperationPanic(&ret, prog.log.With("op", "create"))
+			op.start(globalOptions, prog.log)
 
 			result, err := prog.CreationService.Create(ctx, resolvedPaths, createOptions)
-			logOperationResult(err, result, prog.log.With("op", "create"))
+			err = op.finish(fsys, globalOptions, err, result, prog.log.With("op", "create"))
 			if err != nil {
 				return fmt.Errorf("create: %w", err)
 			}
@@ -514,28 +782,150 @@ func newCreateCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comm
 	}
 	createCmd.Flags().BoolVar(&createOptions.HideFiles, "hidden", false, "create PAR2 sets and related files as hidden (dotfiles)")
 	createCmd.Flags().BoolVarP(&createOptions.Bundle, "bundle", "b", false, "bundle created PAR2 sets into one single file")
+	createCmd.Flags().BoolVar(&createOptions.ExcludeEmpty, "exclude-empty", false, "exclude empty (zero-byte) files from created PAR2 sets")
+	createCmd.Flags().BoolVar(&createOptions.AdoptExisting, "adopt-existing", false, "adopt existing same-named (non-par2cron) PAR2 sets into par2cron management")
+	createCmd.Flags().BoolVar(&createOptions.StrictGlob, "strict-glob", false, "fail jobs where the glob matches no files in a non-empty marked folder (keeping the marker)")
+	createCmd.Flags().BoolVar(&createOptions.WriteStamp, "write-stamp", false, "write a stamp file (set name and time) next to each successfully created PAR2 set")
+	createCmd.Flags().StringVar(&createOptions.StampFile, "stamp-file", schema.StampFile, "filename of the stamp file written with --write-stamp")
+	createCmd.Flags().BoolVar(&createOptions.WriteFileList, "write-file-list", false, "write a list of the protected files (names and sizes) next to each created PAR2 set")
+	createCmd.Flags().BoolVar(&createOptions.ProtectCreationManifest, "protect-creation-manifest", false, "also protect a snapshot of the creation manifest with PAR2 sets (in folder mode)")
+	createCmd.Flags().BoolVar(&createOptions.RetrySingleThreaded, "retry-single-threaded", false, "on a par2 crash (killed by a signal or internal error), retry the job once single-threaded (-t1)")
+	createCmd.Flags().BoolVar(&createOptions.RecordMetadata, "record-metadata", false, "record the owner and group of protected files in the creation manifest (see restore-metadata)")
+	createCmd.Flags().BoolVar(&createOptions.FolderFingerprint, "folder-fingerprint", false, "record a fingerprint of the protected files (names, sizes and times) for checking the folder composition at verification (in folder mode)")
+	createCmd.Flags().Var(&createOptions.TargetBlockSize, "target-block-size", "compute a par2 block count (-b) per set to hit roughly this block size (e.g. 4M)")
+	createCmd.Flags().Var(&createOptions.MaxSetSize, "max-set-size", "split the protected files of a folder into multiple PAR2 sets of at most this size each (e.g. 500G; in folder mode)")
 	createCmd.Flags().BoolVarP(&createOptions.Par2Verify, "verify", "v", false, "PAR2 sets must pass verification as part of creation")
 	createCmd.Flags().StringVarP(&configPath, "config", "c", "", "path to a par2cron YAML configuration file")
+	createCmd.Flags().BoolVar(&dumpConfig, "dump-effective-config", false, "print the effective options (flags over config over defaults) and exit without running")
 	createCmd.Flags().StringVarP(&createOptions.Par2Glob, "glob", "g", "*", "PAR2 set default glob (files to include)")
+	createCmd.Flags().StringArrayVar(&createOptions.Par2GlobExclude, "glob-exclude", nil, "PAR2 set default glob of files to exclude from those included by --glob (can be repeated)")
 	createCmd.Flags().VarP(&createOptions.MaxDuration, "duration", "d", "time budget per run (best effort/soft limit)")
+	createCmd.Flags().IntVar(&createOptions.Limit, "limit", 0, "maximum number of jobs processed per run (0 for no limit)")
+	createCmd.Flags().IntVar(&createOptions.MaxErrors, "max-errors", 0, "abort the run once this many jobs have failed (0 for no limit)")
 	createCmd.Flags().VarP(&createOptions.Par2Mode, "mode", "m", "PAR2 set default mode; creates a set per (folder|nested|file|recursive)")
 
 	return createCmd
 }
 
+// newRecreateCmd returns the "recreate" [cobra.Command] pointer for the program.
+// It takes its options from the "create" section of the configuration file, as
+// the current arguments of creation are the ones the PAR2 sets are re-created with.
+func newRecreateCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Command {
+	var recreateOptions create.RecreateOptions
+	var configPath string
+	var resolvedPaths []string
+	var dumpConfig bool
+
+	fsys := afero.NewOsFs()
+	op := operation{name: "recreate", par2: true, root: true, warnings: true}
+
+	globalOptions.logOptions.Logout = os.Stderr
+	globalOptions.logOptions.Stdout = os.Stdout
+	globalOptions.logOptions.Stderr = os.Stderr
+
+	recreateCmd := &cobra.Command{
+		Use:     recreateUsage,
+		Short:   recreateHelpShort,
+		Long:    recreateHelpLong,
+		Example: recreateHelpExample,
+		Args:    wrapArgsError(cobra.MinimumNArgs(1)),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !dumpConfig {
+				if err := checkForPar2(ctx, &util.CtxRunner{}, globalOptions.logOptions.Stderr); err != nil {
+					return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+				}
+			}
+
+			result, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
+				FSys:           fsys,
+				Args:           args,
+				DashAt:         cmd.ArgsLenAtDash(),
+				ConfigPath:     configPath,
+				CommandOptions: &recreateOptions.Options, // mutated
+				GlobalOptions:  globalOptions,            // mutated
+				ExtractSection: func(cfg *configFile) *configFileCreate { return cfg.Create },
+				VisitFlags:     cmd.Flags().Visit,
+			})
+			if err != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+			}
+			recreateOptions.IgnoreNames = globalOptions.ignoreNames
+			recreateOptions.SidecarNames = globalOptions.sidecarNames
+			recreateOptions.MaxManifestSize = globalOptions.maxManifestSize.Value
+			recreateOptions.MaxDepth = globalOptions.maxDepth
+			recreateOptions.PauseFile = globalOptions.pauseFile
+			if err := op.preRun(globalOptions, dumpConfig); err != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+			}
+
+			resolvedPaths = slices.Clone(result.ResolvedPaths)
+
+			if dumpConfig {
+				return dumpEffectiveConfig(cmd.OutOrStdout(), "create",
+					effectiveOptions(cmd.Flags(), result.Section, result.ExternalArgs), globalOptions.logOptions.WantJSON)
+			}
+
+			return nil
+		},
+		RunE: func(_ *cobra.Command, _ []string) (ret error) { //nolint:nonamedreturns
+			if dumpConfig {
+				return nil
+			}
+
+			runner, rerr := newRunner(globalOptions)
+			if rerr != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, rerr)
+			}
+			defer runner.Close()
+
+			prog := NewProgram(fsys, *globalOptions.logOptions, runner, &util.BundleHandler{}, &util.Par2Handler{}, util.GobCacheHandler{})
+			defer prog.Shutdown()
+			defer recoverOperationPanic(&ret, prog.log.With("op", "recreate"))
+			op.start(globalOptions, prog.log)
+
+			result, err := prog.CreationService.Recreate(ctx, resolvedPaths, recreateOptions)
+			err = op.finish(fsys, globalOptions, err, result, prog.log.With("op", "recreate"))
+			if err != nil {
+				return fmt.Errorf("recreate: %w", err)
+			}
+
+			return nil
+		},
+	}
+	recreateCmd.Flags().Var(&recreateOptions.OlderRedundancyThan, "older-redundancy-than", "only re-create PAR2 sets created with less redundancy than this (e.g. 0.1 or 10%)")
+	recreateCmd.Flags().BoolVar(&recreateOptions.DryRun, "dry-run", false, "only log the PAR2 sets that would be re-created, without changing anything")
+	recreateCmd.Flags().BoolVar(&recreateOptions.RetrySingleThreaded, "retry-single-threaded", false, "on a par2 crash (killed by a signal or internal error), retry the job once single-threaded (-t1)")
+	recreateCmd.Flags().Var(&recreateOptions.TargetBlockSize, "target-block-size", "compute a par2 block count (-b) per set to hit roughly this block size (e.g. 4M)")
+	recreateCmd.Flags().BoolVarP(&recreateOptions.Par2Verify, "verify", "v", false, "new PAR2 sets must pass verification before replacing the old ones")
+	recreateCmd.Flags().StringVarP(&configPath, "config", "c", "", "path to a par2cron YAML configuration file (uses its create section)")
+	recreateCmd.Flags().BoolVar(&dumpConfig, "dump-effective-config", false, "print the effective options (flags over config over defaults) and exit without running")
+	recreateCmd.Flags().VarP(&recreateOptions.MaxDuration, "duration", "d", "time budget per run (best effort/soft limit)")
+	recreateCmd.Flags().IntVar(&recreateOptions.Limit, "limit", 0, "maximum number of jobs processed per run (0 for no limit)")
+	recreateCmd.Flags().IntVar(&recreateOptions.MaxErrors, "max-errors", 0, "abort the run once this many jobs have failed (0 for no limit)")
+
+	return recreateCmd
+}
+
 // newVerifyCmd returns the "verify" [cobra.Command] pointer for the program.
 func newVerifyCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Command {
 	var verifyOptions verify.Options
 	var configPath string
 	var resolvedPaths []string
+	var dumpConfig bool
+	var fromStdin bool
+	var forcePaths []string
 
 	fsys := afero.NewOsFs()
+	op := operation{name: "verify", par2: true, root: true, warnings: true}
 
 	globalOptions.logOptions.Logout = os.Stderr
 	globalOptions.logOptions.Stdout = os.Stdout
 	globalOptions.logOptions.Stderr = os.Stderr
 
 	_ = verifyOptions.RunInterval.Set("24h")
+	_ = verifyOptions.Order.Set(schema.VerifyOrderOldest)
+	_ = verifyOptions.NameMismatch.Set(schema.NameMismatchFix)
+	_ = verifyOptions.BasePath.Set(schema.BasePathAuto)
 
 	verifyCmd := &cobra.Command{
 		Use:     verifyUsage,
@@ -544,8 +934,10 @@ func newVerifyCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comm
 		Example: verifyHelpExample,
 		Args:    wrapArgsError(cobra.MinimumNArgs(1)),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkForPar2(ctx, &util.CtxRunner{}, globalOptions.logOptions.Stderr); err != nil {
-				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+			if !dumpConfig {
+				if err := checkForPar2(ctx, &util.CtxRunner{}, globalOptions.logOptions.Stderr); err != nil {
+					return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+				}
 			}
 
 			result, err := runPrelude(&preludeInput[*verify.Options, *configFileVerify]{
@@ -561,12 +953,35 @@ func newVerifyCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comm
 			if err != nil {
 				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
 			}
+			if fromStdin {
+				verifyOptions.Queue = cmd.InOrStdin()
+			}
+			if verifyOptions.Force, err = resolveForcePaths(forcePaths); err != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+			}
+			verifyOptions.IgnoreNames = globalOptions.ignoreNames
+			verifyOptions.SidecarNames = globalOptions.sidecarNames
+			verifyOptions.MaxManifestSize = globalOptions.maxManifestSize.Value
+			verifyOptions.MaxDepth = globalOptions.maxDepth
+			verifyOptions.PauseFile = globalOptions.pauseFile
+			if err := op.preRun(globalOptions, dumpConfig); err != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+			}
 
 			resolvedPaths = slices.Clone(result.ResolvedPaths)
 
+			if dumpConfig {
+				return dumpEffectiveConfig(cmd.OutOrStdout(), "verify",
+					effectiveOptions(cmd.Flags(), result.Section, result.ExternalArgs), globalOptions.logOptions.WantJSON)
+			}
+
 			return nil
 		},
 		RunE: func(_ *cobra.Command, _ []string) (ret error) { //nolint:nonamedreturns
+			if dumpConfig {
+				return nil
+			}
+
 			runner, rerr := newRunner(globalOptions)
 			if rerr != nil {
 				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, rerr)
@@ -576,9 +991,10 @@ func newVerifyCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comm
 			prog := NewProgram(fsys, *globalOptions.logOptions, runner, &util.BundleHandler{}, &util.Par2Handler{}, util.GobCacheHandler{})
 			defer prog.Shutdown()
 			defer recoverOperationPanic(&ret, prog.log.With("op", "verify"))
+			op.start(globalOptions, prog.log)
 
 			result, err := prog.VerificationService.Verify(ctx, resolvedPaths, verifyOptions)
-			logOperationResult(err, result, prog.log.With("op", "verify"))
+			err = op.finish(fsys, globalOptions, err, result, prog.log.With("op", "verify"))
 			if err != nil {
 				return fmt.Errorf("verify: %w", err)
 			}
@@ -587,12 +1003,50 @@ func newVerifyCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comm
 		},
 	}
 	verifyCmd.Flags().BoolVar(&verifyOptions.SkipNotCreated, "skip-not-created", false, "skip PAR2 sets without a par2cron manifest containing a creation record")
+	verifyCmd.Flags().BoolVar(&verifyOptions.CleanOrphans, "clean-orphans", false, "remove orphaned par2cron manifests whose PAR2 set no longer exists")
+	verifyCmd.Flags().BoolVar(&fromStdin, "from-stdin", false, "only process PAR2 sets (or directories) read as newline-delimited paths from stdin")
+	verifyCmd.Flags().StringArrayVar(&forcePaths, "force", nil, "verify this PAR2 set (or all below this directory) regardless of --age (can be repeated)")
+	verifyCmd.Flags().Var(&verifyOptions.Tags, "tag", "only process PAR2 sets having all of these tags (can be repeated)")
+	verifyCmd.Flags().Var(&verifyOptions.CreatedBefore, "created-before", "only process PAR2 sets created before this date (YYYY-MM-DD or RFC 3339)")
+	verifyCmd.Flags().Var(&verifyOptions.CreatedAfter, "created-after", "only process PAR2 sets created at or after this date (YYYY-MM-DD or RFC 3339)")
+	verifyCmd.Flags().BoolVar(&verifyOptions.IncludeNotCreated, "include-not-created", false, "also process PAR2 sets without a creation record with --created-before/--created-after")
 	verifyCmd.Flags().BoolVarP(&verifyOptions.IncludeExternal, "include-external", "e", false, "include PAR2 sets without a par2cron manifest (and create one)")
 	verifyCmd.Flags().StringVarP(&configPath, "config", "c", "", "path to a par2cron YAML configuration file")
+	verifyCmd.Flags().BoolVar(&dumpConfig, "dump-effective-config", false, "print the effective options (flags over config over defaults) and exit without running")
 	verifyCmd.Flags().StringVar(&verifyOptions.CacheDir, "cache", "", "directory for optional manifest cache (use same for all commands)")
+	verifyCmd.Flags().StringVar(&verifyOptions.MirrorDir, "mirror", "", "also verify against a mirror copy of the <dir> (reports diverging results)")
+	verifyCmd.Flags().BoolVar(&verifyOptions.NoManifestUpdate, "no-manifest-update", false, "report verification results only, never write par2cron manifests")
+	verifyCmd.Flags().BoolVar(&verifyOptions.OnlyNeedingRepair, "only-needing-repair", false, "only log jobs found corrupted or failing (healthy and skipped at debug level)")
+	verifyCmd.Flags().BoolVar(&verifyOptions.StrictPar2, "strict-par2", false, "fail jobs whose PAR2 has changed since its par2cron manifest (instead of resetting the manifest)")
+	verifyCmd.Flags().BoolVar(&verifyOptions.RefreshStamp, "refresh-stamp", false, "refresh the time of existing stamp files (see create --write-stamp) after healthy verification")
+	verifyCmd.Flags().StringVar(&verifyOptions.StampFile, "stamp-file", schema.StampFile, "filename of the stamp file refreshed with --refresh-stamp")
+	verifyCmd.Flags().BoolVar(&verifyOptions.Par2Quiet, "par2-quiet", false, "run par2 in quiet mode (-q, must not be passed as par2 argument as well)")
+	verifyCmd.Flags().BoolVar(&verifyOptions.Par2Verbose, "par2-verbose", false, "run par2 in verbose mode (-v, must not be passed as par2 argument as well)")
+	verifyCmd.Flags().Var(&verifyOptions.Par2ExitCodes, "par2-exit-code", "classify a par2 exit code as (success|repairable|unrepairable|usage-error) (can be repeated)")
 	verifyCmd.Flags().VarP(&verifyOptions.MaxDuration, "duration", "d", "time budget per run (best effort/soft limit)")
+	verifyCmd.Flags().IntVar(&verifyOptions.Limit, "limit", 0, "maximum number of jobs processed per run (0 for no limit)")
+	verifyCmd.Flags().IntVar(&verifyOptions.MaxErrors, "max-errors", 0, "abort the run once this many jobs have failed (0 for no limit)")
+	verifyCmd.Flags().IntVarP(&verifyOptions.Jobs, "jobs", "j", verify.DefaultJobs, "number of par2 verifications run concurrently")
+	verifyCmd.Flags().IntVar(&verifyOptions.IOConcurrency, "io-concurrency", verify.DefaultIOConcurrency, "number of par2cron manifests read concurrently while scanning for jobs")
 	verifyCmd.Flags().VarP(&verifyOptions.MinAge, "age", "a", "minimum time between re-verifications (skip if verified within this period)")
+	verifyCmd.Flags().StringVar(&verifyOptions.ChangedSince, "changed-since", "", "only verify PAR2 sets whose protected files (or PAR2 files) were modified after this file (in place of --age)")
 	verifyCmd.Flags().VarP(&verifyOptions.RunInterval, "calc-run-interval", "i", "how often you run par2cron verify (for backlog calculations)")
+	verifyCmd.Flags().Var(&verifyOptions.MinRunInterval, "min-run-interval", "minimum time between runs per <dir> (skip if previous run finished within)")
+	verifyCmd.Flags().Var(&verifyOptions.Order, "order", "order of verification; (oldest) verified first, (newest) created first or (random) shuffled daily")
+	verifyCmd.Flags().BoolVar(&verifyOptions.Quick, "quick", false, "only check PAR2 indexes and the presence and sizes of protected files (no par2; flags problems for full verification)")
+	verifyCmd.Flags().BoolVar(&verifyOptions.DropCaches, "drop-caches", false, "drop the page cache of PAR2 and protected files before par2 reads them (Linux; reads from disk)")
+	verifyCmd.Flags().StringVar(&verifyOptions.Checkpoint, "checkpoint", "", "record the PAR2 sets processed within a pass over the <dir> into this file (for --resume)")
+	verifyCmd.Flags().BoolVar(&verifyOptions.Resume, "resume", false, "skip the PAR2 sets already processed within the pass of the --checkpoint (continue it)")
+	verifyCmd.Flags().BoolVar(&verifyOptions.FindRelocated, "find-relocated", false, "on corruption, search the PAR2 set's directory tree for missing protected files moved elsewhere (by size and hashes)")
+	verifyCmd.Flags().BoolVar(&verifyOptions.Relocate, "relocate", false, "on corruption, re-run par2 with the missing protected files found moved elsewhere (implies --find-relocated)")
+	verifyCmd.Flags().BoolVar(&verifyOptions.FileStatus, "file-status", false, "record the per-file status (intact, damaged, missing) of corrupted PAR2 sets from the par2 output (not with quiet verbosity)")
+	verifyCmd.Flags().StringVar(&verifyOptions.Classifier, "classifier", "", "command classifying the par2 result from its exit code and output (as JSON), in place of the built-in classification")
+	verifyCmd.Flags().StringVar(&verifyOptions.CrossCheck, "cross-check", "", "command cross-checking the PAR2 sets par2 found clean (given the PAR2 path), whose failure needs attention despite par2")
+	verifyCmd.Flags().IntVar(&verifyOptions.VerifyPasses, "verify-passes", 1, "number of par2 passes per PAR2 set, only clean if all passes are (for questionable media; records disagreeing passes)")
+	verifyCmd.Flags().BoolVar(&verifyOptions.DereferenceManifest, "dereference-manifest", false, "follow manifests without their PAR2 set to the PAR2 set stored elsewhere they record (par2_location)")
+	verifyCmd.Flags().BoolVar(&verifyOptions.RetrySingleThreaded, "retry-single-threaded", false, "on a par2 crash (killed by a signal or internal error), retry the job once single-threaded (-t1)")
+	verifyCmd.Flags().Var(&verifyOptions.BasePath, "basepath", "directory par2 resolves protected files against; (auto) the PAR2 set's unless -B is passed, (set-dir) the PAR2 set's or (scan-root) the <dir>")
+	verifyCmd.Flags().Var(&verifyOptions.NameMismatch, "name-mismatch", "on a manifest name not matching its PAR2 set; (fix) correct the manifest or (skip) the PAR2 set")
 
 	return verifyCmd
 }
@@ -602,8 +1056,15 @@ func newRepairCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comm
 	var repairOptions repair.Options
 	var configPath string
 	var resolvedPaths []string
+	var dumpConfig bool
+	var fromStdin bool
+	var assumeYes bool
+
+	_ = repairOptions.NameMismatch.Set(schema.NameMismatchFix)
+	_ = repairOptions.BasePath.Set(schema.BasePathAuto)
 
 	fsys := afero.NewOsFs()
+	op := operation{name: "repair", par2: true, root: true, warnings: true}
 
 	globalOptions.logOptions.Logout = os.Stderr
 	globalOptions.logOptions.Stdout = os.Stdout
@@ -616,8 +1077,10 @@ func newRepairCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comm
 		Example: repairHelpExample,
 		Args:    wrapArgsError(cobra.MinimumNArgs(1)),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkForPar2(ctx, &util.CtxRunner{}, globalOptions.logOptions.Stderr); err != nil {
-				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+			if !dumpConfig {
+				if err := checkForPar2(ctx, &util.CtxRunner{}, globalOptions.logOptions.Stderr); err != nil {
+					return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+				}
 			}
 
 			result, err := runPrelude(&preludeInput[*repair.Options, *configFileRepair]{
@@ -633,12 +1096,34 @@ func newRepairCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comm
 			if err != nil {
 				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
 			}
+			if fromStdin {
+				repairOptions.Queue = cmd.InOrStdin()
+			} else if !assumeYes && isTerminal(cmd.InOrStdin()) {
+				repairOptions.Confirm = cmd.InOrStdin()
+			}
+			repairOptions.IgnoreNames = globalOptions.ignoreNames
+			repairOptions.SidecarNames = globalOptions.sidecarNames
+			repairOptions.MaxManifestSize = globalOptions.maxManifestSize.Value
+			repairOptions.MaxDepth = globalOptions.maxDepth
+			repairOptions.PauseFile = globalOptions.pauseFile
+			if err := op.preRun(globalOptions, dumpConfig); err != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+			}
 
 			resolvedPaths = slices.Clone(result.ResolvedPaths)
 
+			if dumpConfig {
+				return dumpEffectiveConfig(cmd.OutOrStdout(), "repair",
+					effectiveOptions(cmd.Flags(), result.Section, result.ExternalArgs), globalOptions.logOptions.WantJSON)
+			}
+
 			return nil
 		},
 		RunE: func(_ *cobra.Command, _ []string) (ret error) { //nolint:nonamedreturns
+			if dumpConfig {
+				return nil
+			}
+
 			runner, rerr := newRunner(globalOptions)
 			if rerr != nil {
 				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, rerr)
@@ -648,9 +1133,10 @@ func newRepairCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comm
 			prog := NewProgram(fsys, *globalOptions.logOptions, runner, &util.BundleHandler{}, &util.Par2Handler{}, util.GobCacheHandler{})
 			defer prog.Shutdown()
 			defer recoverOperationPanic(&ret, prog.log.With("op", "repair"))
+			op.start(globalOptions, prog.log)
 
 			result, err := prog.RepairService.Repair(ctx, resolvedPaths, repairOptions)
-			logOperationResult(err, result, prog.log.With("op", "repair"))
+			err = op.finish(fsys, globalOptions, err, result, prog.log.With("op", "repair"))
 			if err != nil {
 				return fmt.Errorf("repair: %w", err)
 			}
@@ -659,14 +1145,29 @@ func newRepairCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comm
 		},
 	}
 	repairCmd.Flags().BoolVar(&repairOptions.SkipNotCreated, "skip-not-created", false, "skip PAR2 sets without a par2cron manifest containing a creation record")
+	repairCmd.Flags().BoolVar(&repairOptions.CleanOrphans, "clean-orphans", false, "remove orphaned par2cron manifests whose PAR2 set no longer exists")
+	repairCmd.Flags().BoolVar(&fromStdin, "from-stdin", false, "only process PAR2 sets (or directories) read as newline-delimited paths from stdin")
+	repairCmd.Flags().BoolVarP(&assumeYes, "assume-yes", "y", false, "do not prompt for confirmation before repairing (when run from a terminal)")
+	repairCmd.Flags().Var(&repairOptions.Tags, "tag", "only process PAR2 sets having all of these tags (can be repeated)")
 	repairCmd.Flags().BoolVarP(&repairOptions.AttemptUnrepairables, "attempt-unrepairables", "u", false, "attempt to repair PAR2 sets marked as unrepairable")
 	repairCmd.Flags().BoolVarP(&repairOptions.Par2Verify, "verify", "v", false, "PAR2 sets must pass verification as part of repair")
 	repairCmd.Flags().BoolVarP(&repairOptions.PurgeBackups, "purge-backups", "p", false, "remove obsolete backup files (.1, .2, ...) after successful repair")
 	repairCmd.Flags().BoolVarP(&repairOptions.RestoreBackups, "restore-backups", "r", false, "roll back protected files to pre-repair state after unsuccessful repair")
+	repairCmd.Flags().BoolVar(&repairOptions.Rebaseline, "rebaseline", false, "refresh the manifest's PAR2 hash and protected file metadata after successful repair")
+	repairCmd.Flags().BoolVar(&repairOptions.SkipReadOnly, "skip-read-only", false, "skip PAR2 sets on a read-only mounted filesystem (instead of failing them)")
+	repairCmd.Flags().BoolVar(&repairOptions.DereferenceManifest, "dereference-manifest", false, "follow manifests without their PAR2 set to the PAR2 set stored elsewhere they record (par2_location)")
+	repairCmd.Flags().BoolVar(&repairOptions.RetrySingleThreaded, "retry-single-threaded", false, "on a par2 crash (killed by a signal or internal error), retry the job once single-threaded (-t1)")
+	repairCmd.Flags().Var(&repairOptions.BasePath, "basepath", "directory par2 resolves protected files against; (auto) the PAR2 set's unless -B is passed, (set-dir) the PAR2 set's or (scan-root) the <dir>")
+	repairCmd.Flags().Var(&repairOptions.NameMismatch, "name-mismatch", "on a manifest name not matching its PAR2 set; (fix) correct the manifest or (skip) the PAR2 set")
+	repairCmd.Flags().BoolVar(&repairOptions.Par2Quiet, "par2-quiet", false, "run par2 in quiet mode (-q, must not be passed as par2 argument as well)")
+	repairCmd.Flags().BoolVar(&repairOptions.Par2Verbose, "par2-verbose", false, "run par2 in verbose mode (-v, must not be passed as par2 argument as well)")
 	repairCmd.Flags().IntVarP(&repairOptions.MinTestedCount, "min-tested", "t", 0, "repair only when verified as corrupted at least X times")
 	repairCmd.Flags().StringVar(&repairOptions.CacheDir, "cache", "", "directory for optional manifest cache (use same for all commands)")
 	repairCmd.Flags().StringVarP(&configPath, "config", "c", "", "path to a par2cron YAML configuration file")
+	repairCmd.Flags().BoolVar(&dumpConfig, "dump-effective-config", false, "print the effective options (flags over config over defaults) and exit without running")
 	repairCmd.Flags().VarP(&repairOptions.MaxDuration, "duration", "d", "time budget per run (best effort/soft limit)")
+	repairCmd.Flags().IntVar(&repairOptions.Limit, "limit", 0, "maximum number of jobs processed per run (0 for no limit)")
+	repairCmd.Flags().IntVar(&repairOptions.MaxErrors, "max-errors", 0, "abort the run once this many jobs have failed (0 for no limit)")
 
 	return repairCmd
 }
@@ -675,6 +1176,7 @@ func newInfoCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comman
 	var infoOptions info.Options
 	var configPath string
 	var resolvedPaths []string
+	var dumpConfig bool
 
 	fsys := afero.NewOsFs()
 
@@ -704,12 +1206,25 @@ func newInfoCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comman
 			if err != nil {
 				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
 			}
+			infoOptions.IgnoreNames = globalOptions.ignoreNames
+			infoOptions.SidecarNames = globalOptions.sidecarNames
+			infoOptions.MaxManifestSize = globalOptions.maxManifestSize.Value
+			infoOptions.MaxDepth = globalOptions.maxDepth
 
 			resolvedPaths = slices.Clone(result.ResolvedPaths)
 
+			if dumpConfig {
+				return dumpEffectiveConfig(cmd.OutOrStdout(), "info",
+					effectiveOptions(cmd.Flags(), result.Section, result.ExternalArgs), globalOptions.logOptions.WantJSON)
+			}
+
 			return nil
 		},
 		RunE: func(_ *cobra.Command, _ []string) (ret error) { //nolint:nonamedreturns
+			if dumpConfig {
+				return nil
+			}
+
 			runner, rerr := newRunner(globalOptions)
 			if rerr != nil {
 				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, rerr)
@@ -729,8 +1244,15 @@ func newInfoCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comman
 		},
 	}
 	infoCmd.Flags().BoolVar(&infoOptions.SkipNotCreated, "skip-not-created", false, "skip PAR2 sets without a par2cron manifest containing a creation record")
+	infoCmd.Flags().Var(&infoOptions.Tags, "tag", "only process PAR2 sets having all of these tags (can be repeated)")
 	infoCmd.Flags().BoolVarP(&infoOptions.IncludeExternal, "include-external", "e", false, "include external PAR2 sets without a par2cron manifest")
+	infoCmd.Flags().BoolVar(&infoOptions.DetectDuplicates, "detect-duplicates", false, "report PAR2 sets sharing the same set ID at different paths (parses all sets)")
+	infoCmd.Flags().BoolVar(&infoOptions.Stats, "stats", false, "report library-wide redundancy statistics (parses all sets, respects --duration)")
+	infoCmd.Flags().Var(&infoOptions.TargetRedundancy, "target-redundancy", "estimate the parity size if re-creating all sets at this redundancy (e.g. 0.2 or 20%, parses all sets, respects --duration)")
+	infoCmd.Flags().BoolVar(&infoOptions.Health, "health", false, "report PAR2 sets with a lowered health score from their verification history (worst first)")
+	infoCmd.Flags().BoolVar(&infoOptions.Ignores, "ignores", false, "report the ignore and ignore-all files honored during the scan (and the PAR2 sets they skipped)")
 	infoCmd.Flags().StringVarP(&configPath, "config", "c", "", "path to a par2cron YAML configuration file")
+	infoCmd.Flags().BoolVar(&dumpConfig, "dump-effective-config", false, "print the effective options (flags over config over defaults) and exit without running")
 	infoCmd.Flags().StringVar(&infoOptions.CacheDir, "cache", "", "directory for optional manifest cache (use same for all commands)")
 	infoCmd.Flags().VarP(&infoOptions.MaxDuration, "duration", "d", "target time budget for each verify run (soft limit)")
 	infoCmd.Flags().VarP(&infoOptions.MinAge, "age", "a", "target cycle length (time between re-verifications)")
@@ -739,13 +1261,305 @@ func newInfoCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Comman
 	return infoCmd
 }
 
+// newExportCmd returns the "export" [cobra.Command] pointer for the program.
+func newExportCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Command {
+	var exportOptions export.Options
+	var resolvedPaths []string
+
+	fsys := afero.NewOsFs()
+
+	globalOptions.logOptions.Logout = os.Stderr
+	globalOptions.logOptions.Stdout = os.Stdout
+	globalOptions.logOptions.Stderr = os.Stderr
+
+	_ = exportOptions.Format.Set(schema.ExportFormatJSON)
+
+	exportCmd := &cobra.Command{
+		Use:     exportUsage,
+		Short:   exportHelpShort,
+		Long:    exportHelpLong,
+		Example: exportHelpExample,
+		Args:    wrapArgsError(cobra.MinimumNArgs(1)),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			resolved, err := resolvePathArgs(fsys, args)
+			if err != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+			}
+
+			if err := globalOptions.ignoreNames.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := globalOptions.sidecarNames.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := globalOptions.logOptions.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := resolveMaxDepth(globalOptions, cmd.Flags().Changed("max-depth")); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			exportOptions.IgnoreNames = globalOptions.ignoreNames
+			exportOptions.SidecarNames = globalOptions.sidecarNames
+			exportOptions.MaxManifestSize = globalOptions.maxManifestSize.Value
+			exportOptions.MaxDepth = globalOptions.maxDepth
+
+			resolvedPaths = slices.Clone(resolved)
+
+			return nil
+		},
+		RunE: func(_ *cobra.Command, _ []string) (ret error) { //nolint:nonamedreturns
+			runner, rerr := newRunner(globalOptions)
+			if rerr != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, rerr)
+			}
+			defer runner.Close()
+
+			prog := NewProgram(fsys, *globalOptions.logOptions, runner, &util.BundleHandler{}, &util.Par2Handler{}, util.GobCacheHandler{})
+			defer prog.Shutdown()
+			defer recoverOperationPanic(&ret, prog.log.With("op", "export"))
+
+			err := prog.ExportService.Export(ctx, resolvedPaths, exportOptions)
+			if err != nil {
+				return fmt.Errorf("export: %w", err)
+			}
+
+			return nil
+		},
+	}
+	exportCmd.Flags().VarP(&exportOptions.Format, "format", "f", "format of the exported manifests (json|csv)")
+	exportCmd.Flags().BoolVar(&exportOptions.SkipNotCreated, "skip-not-created", false, "skip PAR2 sets without a par2cron manifest containing a creation record")
+	exportCmd.Flags().BoolVarP(&exportOptions.IncludeExternal, "include-external", "e", false, "include external PAR2 sets without a par2cron manifest")
+
+	return exportCmd
+}
+
+// newAttentionCmd returns the "attention" [cobra.Command] pointer for the program.
+func newAttentionCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Command {
+	var attentionOptions export.AttentionOptions
+	var resolvedPaths []string
+
+	fsys := afero.NewOsFs()
+
+	globalOptions.logOptions.Logout = os.Stderr
+	globalOptions.logOptions.Stdout = os.Stdout
+	globalOptions.logOptions.Stderr = os.Stderr
+
+	_ = attentionOptions.Overdue.Set("7d")
+
+	attentionCmd := &cobra.Command{
+		Use:     attentionUsage,
+		Short:   attentionHelpShort,
+		Long:    attentionHelpLong,
+		Example: attentionHelpExample,
+		Args:    wrapArgsError(cobra.MinimumNArgs(1)),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			resolved, err := resolvePathArgs(fsys, args)
+			if err != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+			}
+
+			if err := globalOptions.ignoreNames.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := globalOptions.sidecarNames.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := globalOptions.logOptions.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := resolveMaxDepth(globalOptions, cmd.Flags().Changed("max-depth")); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			attentionOptions.IgnoreNames = globalOptions.ignoreNames
+			attentionOptions.SidecarNames = globalOptions.sidecarNames
+			attentionOptions.MaxManifestSize = globalOptions.maxManifestSize.Value
+			attentionOptions.MaxDepth = globalOptions.maxDepth
+
+			resolvedPaths = slices.Clone(resolved)
+
+			return nil
+		},
+		RunE: func(_ *cobra.Command, _ []string) (ret error) { //nolint:nonamedreturns
+			runner, rerr := newRunner(globalOptions)
+			if rerr != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, rerr)
+			}
+			defer runner.Close()
+
+			prog := NewProgram(fsys, *globalOptions.logOptions, runner, &util.BundleHandler{}, &util.Par2Handler{}, util.GobCacheHandler{})
+			defer prog.Shutdown()
+			defer recoverOperationPanic(&ret, prog.log.With("op", "attention"))
+
+			err := prog.ExportService.Attention(ctx, resolvedPaths, attentionOptions)
+			if err != nil {
+				return fmt.Errorf("attention: %w", err)
+			}
+
+			return nil
+		},
+	}
+	attentionCmd.Flags().VarP(&attentionOptions.Overdue, "overdue", "o", "time after creation by which a never verified PAR2 set is overdue (per-set verify-interval takes precedence)")
+	attentionCmd.Flags().BoolVar(&attentionOptions.SkipNotCreated, "skip-not-created", false, "skip PAR2 sets without a par2cron manifest containing a creation record")
+	attentionCmd.Flags().BoolVarP(&attentionOptions.IncludeExternal, "include-external", "e", false, "include external PAR2 sets without a par2cron manifest")
+
+	return attentionCmd
+}
+
+// newRestoreMetadataCmd returns the "restore-metadata" [cobra.Command] pointer for the program.
+func newRestoreMetadataCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Command {
+	var metadataOptions repair.MetadataOptions
+	var resolvedPaths []string
+
+	fsys := afero.NewOsFs()
+	op := operation{name: "restore-metadata", warnings: true}
+
+	globalOptions.logOptions.Logout = os.Stderr
+	globalOptions.logOptions.Stdout = os.Stdout
+	globalOptions.logOptions.Stderr = os.Stderr
+
+	restoreMetadataCmd := &cobra.Command{
+		Use:     restoreMetadataUsage,
+		Short:   restoreMetadataHelpShort,
+		Long:    restoreMetadataHelpLong,
+		Example: restoreMetadataHelpExample,
+		Args:    wrapArgsError(cobra.MinimumNArgs(1)),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			resolved, err := resolvePathArgs(fsys, args)
+			if err != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+			}
+
+			if err := globalOptions.ignoreNames.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := globalOptions.sidecarNames.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := globalOptions.logOptions.Validate(); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			if err := resolveMaxDepth(globalOptions, cmd.Flags().Changed("max-depth")); err != nil {
+				return fmt.Errorf("%w: failed to validate options: %w", schema.ErrExitBadInvocation, err)
+			}
+			metadataOptions.IgnoreNames = globalOptions.ignoreNames
+			metadataOptions.SidecarNames = globalOptions.sidecarNames
+			metadataOptions.MaxManifestSize = globalOptions.maxManifestSize.Value
+			metadataOptions.MaxDepth = globalOptions.maxDepth
+			metadataOptions.PauseFile = globalOptions.pauseFile
+
+			resolvedPaths = slices.Clone(resolved)
+
+			return nil
+		},
+		RunE: func(_ *cobra.Command, _ []string) (ret error) { //nolint:nonamedreturns
+			runner, rerr := newRunner(globalOptions)
+			if rerr != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, rerr)
+			}
+			defer runner.Close()
+
+			prog := NewProgram(fsys, *globalOptions.logOptions, runner, &util.BundleHandler{}, &util.Par2Handler{}, util.GobCacheHandler{})
+			defer prog.Shutdown()
+			defer recoverOperationPanic(&ret, prog.log.With("op", "restore-metadata"))
+
+			result, err := prog.RepairService.RestoreMetadata(ctx, resolvedPaths, metadataOptions)
+			err = op.finish(fsys, globalOptions, err, result, prog.log.With("op", "restore-metadata"))
+			if err != nil {
+				return fmt.Errorf("restore-metadata: %w", err)
+			}
+
+			return nil
+		},
+	}
+	restoreMetadataCmd.Flags().BoolVar(&metadataOptions.DryRun, "dry-run", false, "only log the files whose metadata would be restored, without changing anything")
+
+	return restoreMetadataCmd
+}
+
+// newSelfTestCmd returns the "self-test" [cobra.Command] pointer for the program.
+func newSelfTestCmd(ctx context.Context, globalOptions *globalOptions) *cobra.Command {
+	var selfTestOptions selftest.Options
+	var baseDir string
+
+	fsys := afero.NewOsFs()
+	op := operation{name: "self-test", par2: true}
+
+	globalOptions.logOptions.Logout = os.Stderr
+	globalOptions.logOptions.Stdout = os.Stdout
+	globalOptions.logOptions.Stderr = os.Stderr
+
+	selfTestCmd := &cobra.Command{
+		Use:     selfTestUsage,
+		Short:   selfTestHelpShort,
+		Long:    selfTestHelpLong,
+		Example: selfTestHelpExample,
+		Args:    wrapArgsError(cobra.ArbitraryArgs),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkForPar2(ctx, &util.CtxRunner{}, globalOptions.logOptions.Stderr); err != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+			}
+
+			paths, par2Args := args, []string(nil)
+			if dashAt := cmd.ArgsLenAtDash(); dashAt >= 0 {
+				paths, par2Args = args[:dashAt], args[dashAt:]
+			}
+			if len(paths) > 1 {
+				return fmt.Errorf("%w: accepts at most 1 <dir>, received %d", schema.ErrExitBadInvocation, len(paths))
+			}
+			selfTestOptions.SetPar2Args(par2Args)
+
+			baseDir = os.TempDir()
+			if globalOptions.tempDir != "" {
+				if err := util.CheckTempDir(fsys, globalOptions.tempDir); err != nil {
+					return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+				}
+				baseDir = globalOptions.tempDir
+			}
+			if len(paths) == 1 {
+				resolved, err := resolvePathArgs(fsys, paths)
+				if err != nil {
+					return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+				}
+				baseDir = resolved[0]
+			}
+			if err := op.preRun(globalOptions, false); err != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
+			}
+
+			return nil
+		},
+		RunE: func(_ *cobra.Command, _ []string) (ret error) { //nolint:nonamedreturns
+			runner, rerr := newRunner(globalOptions)
+			if rerr != nil {
+				return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, rerr)
+			}
+			defer runner.Close()
+
+			prog := NewProgram(fsys, *globalOptions.logOptions, runner, &util.BundleHandler{}, &util.Par2Handler{}, util.GobCacheHandler{})
+			defer prog.Shutdown()
+			defer recoverOperationPanic(&ret, prog.log.With("op", "self-test"))
+			op.start(globalOptions, prog.log)
+
+			if _, err := prog.SelfTestService.SelfTest(ctx, baseDir, selfTestOptions); err != nil {
+				return fmt.Errorf("self-test: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	return selfTestCmd
+}
+
 type Program struct {
 	CreationService     *create.Service
 	VerificationService *verify.Service
 	RepairService       *repair.Service
 	InfoService         *info.Service
+	ExportService       *export.Service
 	BundlerService      *bundler.Service
 	ToolService         *tool.Service
+	SelfTestService     *selftest.Service
 
 	log *logging.Logger
 }
@@ -764,9 +1578,11 @@ func NewProgram(
 		CreationService:     create.NewService(fsys, log, r, b, p, c),
 		VerificationService: verify.NewService(fsys, log, r, b, c),
 		RepairService:       repair.NewService(fsys, log, r, b, c),
-		InfoService:         info.NewService(fsys, log, r, b, c),
+		InfoService:         info.NewService(fsys, log, r, b, p, c),
+		ExportService:       export.NewService(fsys, log, r, b, c),
 		BundlerService:      bundler.NewService(fsys, log, b, p),
 		ToolService:         tool.NewService(fsys, log, b, p),
+		SelfTestService:     selftest.NewService(fsys, log, r, b, p, c),
 
 		log: log,
 	}
@@ -790,41 +1606,96 @@ func logOperationResult(err error, result util.ResultTracker, log *logging.Logge
 
 	switch {
 	case err == nil && result.Error == 0:
-		log.Info(
-			fmt.Sprintf("Operation completed (%d/%d jobs processed)",
-				processedCount, result.Selected),
+		args := []any{
 			"successCount", result.Success,
 			"skipCount", result.Skipped,
 			"errorCount", result.Error,
 			"processedCount", processedCount,
 			"selectedCount", result.Selected,
+		}
+		args = append(args, filteredArgs(result)...)
+		args = append(args, timingArgs(result)...)
+
+		log.Info(
+			fmt.Sprintf("Operation completed (%d/%d jobs processed)",
+				processedCount, result.Selected),
+			args...,
 		)
 
 	case errors.Is(err, context.Canceled):
-		log.Error(
-			fmt.Sprintf("Operation interrupted (%d/%d jobs processed)",
-				processedCount, result.Selected),
+		// The interrupted jobs are not processed, so they remain as well.
+		remainingCount := max(result.Selected-processedCount, 0)
+
+		args := []any{
 			"successCount", result.Success,
 			"skipCount", result.Skipped,
 			"errorCount", result.Error,
 			"processedCount", processedCount,
 			"selectedCount", result.Selected,
-		)
+			"remainingCount", remainingCount,
+		}
+		if result.RemainingDuration > 0 {
+			args = append(args,
+				"remainingEstimate", result.RemainingDuration.Round(time.Second).String(),
+				"remainingUnknownCount", result.RemainingUnknown,
+			)
+		}
+		args = append(args, filteredArgs(result)...)
+		args = append(args, timingArgs(result)...)
 
-	default:
 		log.Error(
-			fmt.Sprintf("Operation completed with errors (%d/%d jobs processed)",
+			fmt.Sprintf("Operation interrupted (%d/%d jobs processed)",
 				processedCount, result.Selected),
+			args...,
+		)
+
+	default:
+		args := []any{
 			"successCount", result.Success,
 			"skipCount", result.Skipped,
 			"errorCount", result.Error,
 			"processedCount", processedCount,
 			"selectedCount", result.Selected,
 			"error", err,
+		}
+		args = append(args, filteredArgs(result)...)
+		args = append(args, timingArgs(result)...)
+
+		log.Error(
+			fmt.Sprintf("Operation completed with errors (%d/%d jobs processed)",
+				processedCount, result.Selected),
+			args...,
 		)
 	}
 }
 
+// timingArgs returns the phases of the elapsed time of the operation as log
+// arguments, for the operations tracking these (see [util.Timings]).
+func timingArgs(result util.ResultTracker) []any {
+	if result.Timings == nil {
+		return nil
+	}
+
+	phases := result.Timings.Phases()
+
+	return []any{
+		"totalDuration", phases.Total.Round(time.Millisecond).String(),
+		"walkDuration", phases.Walk.Round(time.Millisecond).String(),
+		"parseDuration", phases.Parse.Round(time.Millisecond).String(),
+		"par2Duration", phases.Par2.Round(time.Millisecond).String(),
+	}
+}
+
+// filteredArgs returns the candidates dropped by the filters as log arguments,
+// for the operations tracking these (see [util.Filtered]) with nothing selected.
+func filteredArgs(result util.ResultTracker) []any {
+	if result.Filtered == nil || result.Selected > 0 {
+		return nil
+	}
+
+	return []any{"filteredCount", result.Filtered.Total(), result.Filtered.Attr()}
+}
+
 func main() {
 	var exitCode int
 	defer func() {
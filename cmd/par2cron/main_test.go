@@ -3,8 +3,11 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +16,8 @@ import (
 	"github.com/desertwitch/par2cron/internal/schema"
 	"github.com/desertwitch/par2cron/internal/testutil"
 	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/desertwitch/par2cron/internal/verify"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
 )
 
@@ -63,6 +68,188 @@ func Test_checkForPar2_EmptyOutput_Success(t *testing.T) {
 	require.Equal(t, "keep-this", schema.Par2Version)
 }
 
+// Expectation: checkPar2Version should only return an error for an older or
+// undeterminable par2 version, and never with an empty --min-par2-version.
+//
+//nolint:paralleltest
+func Test_checkPar2Version_Table(t *testing.T) {
+	oldVersion := schema.Par2Version
+
+	t.Cleanup(func() {
+		schema.Par2Version = oldVersion
+	})
+
+	tests := []struct {
+		name       string
+		version    string
+		minVersion string
+		wantErr    bool
+	}{
+		{"equal", "par2cmdline version 0.8.0", "0.8.0", false},
+		{"newer patch", "par2cmdline version 0.8.1", "0.8.0", false},
+		{"newer turbo", "par2cmdline-turbo version 1.1.1", "0.8.1", false},
+		{"older minor", "par2cmdline version 0.7.4", "0.8.0", true},
+		{"older patch", "par2cmdline version 0.8.0", "0.8.1", true},
+		{"numeric not lexical", "par2cmdline version 0.10.0", "0.9.0", false},
+		{"unknown version", "something else", "0.8.0", true},
+		{"disabled", "par2cmdline version 0.4", "", false},
+		{"disabled unknown", "", "", false},
+	}
+
+	for _, tt := range tests {
+		opts := newGlobalOptions()
+		require.NoError(t, opts.minPar2Version.Set(tt.minVersion))
+
+		schema.Par2Version = tt.version
+		err := checkPar2Version(opts)
+		if tt.wantErr {
+			require.ErrorIs(t, err, errPar2Outdated, tt.name)
+		} else {
+			require.NoError(t, err, tt.name)
+		}
+	}
+}
+
+// Expectation: Running as root should be warned about, refused with --refuse-root and allowed with --allow-root.
+func Test_checkRoot_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		euid       int
+		allowRoot  bool
+		refuseRoot bool
+		want       string
+	}{
+		{"root warns", 0, false, false, "warn"},
+		{"root refused", 0, false, true, "refuse"},
+		{"root allowed", 0, true, false, "allow"},
+		{"root allowed over refused", 0, true, true, "allow"},
+		{"non-root", 1000, false, true, "allow"},
+		{"no user IDs", -1, false, true, "allow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := newGlobalOptions()
+			opts.allowRoot = tt.allowRoot
+			opts.refuseRoot = tt.refuseRoot
+
+			got := "allow"
+			if err := checkRoot(opts, tt.euid); err != nil {
+				require.ErrorIs(t, err, errRunningAsRoot)
+				got = "warn"
+				if opts.refuseRoot {
+					got = "refuse"
+				}
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// Expectation: A successful run that only logged a warning should fail with the dedicated exit code, only with --warnings-as-errors.
+func Test_checkWarnings_Verify_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	hash, err := util.HashFile(fs, "/data/test"+schema.Par2Extension)
+	require.NoError(t, err)
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.SHA256 = hash
+	mf.Verification = &schema.VerificationManifest{
+		Time:     time.Now().Add(-30 * 24 * time.Hour),
+		Duration: 10 * time.Hour,
+	}
+	mfData, err := json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension, mfData, 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+	log := logging.NewLogger(ls)
+
+	prog := verify.NewService(fs, log, &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	args := verify.Options{Par2Args: []string{"-v"}}
+	_ = args.MinAge.Set("7d")
+	_ = args.MaxDuration.Set("1h")
+	_ = args.RunInterval.Set("24h")
+
+	result, err := prog.Verify(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Success)
+	require.Contains(t, logBuf.String(), "Backlog is growing indefinitely")
+
+	opts := newGlobalOptions()
+	require.NoError(t, checkWarnings(opts, log))
+
+	opts.warningsAsErrors = true
+	err = checkWarnings(opts, log)
+	require.ErrorIs(t, err, schema.ErrExitWarnings)
+	require.Equal(t, schema.ExitCodeWarnings, schema.ExitCodeFor(err))
+}
+
+// Expectation: A run without any warnings should succeed, also with --warnings-as-errors.
+func Test_checkWarnings_NoWarnings_Success(t *testing.T) {
+	t.Parallel()
+
+	log := logging.NewLogger(logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard})
+	log.Info("info message")
+
+	opts := newGlobalOptions()
+	opts.warningsAsErrors = true
+	require.NoError(t, checkWarnings(opts, log))
+}
+
+// Expectation: The default --min-par2-version should be set and valid.
+func Test_newGlobalOptions_MinPar2Version_Success(t *testing.T) {
+	t.Parallel()
+
+	opts := newGlobalOptions()
+	require.Equal(t, defaultMinPar2Version, opts.minPar2Version.Raw)
+	require.NotEqual(t, [3]int{}, opts.minPar2Version.Value)
+	require.False(t, opts.requirePar2Version)
+}
+
+// Expectation: resolvePar2Flavor should detect the flavor unless it was pinned.
+//
+//nolint:paralleltest
+func Test_resolvePar2Flavor_Success(t *testing.T) {
+	oldVersion := schema.Par2Version
+	oldFlavor := schema.Par2Flavor
+
+	t.Cleanup(func() {
+		schema.Par2Version = oldVersion
+		schema.Par2Flavor = oldFlavor
+	})
+
+	opts := newGlobalOptions()
+
+	schema.Par2Version = "par2cmdline-turbo version 1.1.1"
+	resolvePar2Flavor(opts)
+	require.Equal(t, schema.Par2FlavorTurbo, schema.Par2Flavor)
+
+	schema.Par2Version = "par2cmdline version 0.8.1"
+	resolvePar2Flavor(opts)
+	require.Equal(t, schema.Par2FlavorClassic, schema.Par2Flavor)
+
+	require.NoError(t, opts.par2Flavor.Set(schema.Par2FlavorTurbo))
+	resolvePar2Flavor(opts)
+	require.Equal(t, schema.Par2FlavorTurbo, schema.Par2Flavor)
+}
+
 // Expectation: checkForPar2 should return an exec-prefixed error when par2 is unavailable.
 //
 //nolint:paralleltest
@@ -99,6 +286,7 @@ func Test_NewProgram_Success(t *testing.T) {
 	require.NotNil(t, prog.VerificationService)
 	require.NotNil(t, prog.RepairService)
 	require.NotNil(t, prog.InfoService)
+	require.NotNil(t, prog.ExportService)
 }
 
 // Expectation: The root command should be returned with the subcommands.
@@ -139,6 +327,60 @@ func Test_NewRootCmd_HasCgroupFlag_Success(t *testing.T) {
 	require.Empty(t, flag.DefValue)
 }
 
+// Expectation: The root command should have a "par2-env" persistent flag, which is passed into the runner.
+func Test_NewRootCmd_HasPar2EnvFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRootCmd(t.Context())
+
+	flag := cmd.PersistentFlags().Lookup("par2-env")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "KEY=VALUE", flag.Value.Type())
+	require.Empty(t, flag.DefValue)
+
+	opts := newGlobalOptions()
+	require.NoError(t, opts.par2Env.Set("OMP_NUM_THREADS=2"))
+
+	runner, err := newRunner(opts)
+	require.NoError(t, err)
+	require.Equal(t, []string{"OMP_NUM_THREADS=2"}, runner.Env)
+}
+
+// Expectation: The root command should have a "temp-dir" persistent flag, which is passed into the runner.
+func Test_NewRootCmd_HasTempDirFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRootCmd(t.Context())
+
+	flag := cmd.PersistentFlags().Lookup("temp-dir")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "string", flag.Value.Type())
+	require.Empty(t, flag.DefValue)
+
+	opts := newGlobalOptions()
+	opts.tempDir = "/mnt/cache/tmp"
+	require.NoError(t, opts.par2Env.Set("TMPDIR=/mnt/other"))
+
+	runner, err := newRunner(opts)
+	require.NoError(t, err)
+	require.Equal(t, []string{"TMPDIR=/mnt/cache/tmp", "TMPDIR=/mnt/other"}, runner.Env)
+}
+
+// Expectation: The root command should have a "no-recurse" persistent flag.
+func Test_NewRootCmd_HasNoRecurseFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRootCmd(t.Context())
+
+	flag := cmd.PersistentFlags().Lookup("no-recurse")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "bool", flag.Value.Type())
+	require.Equal(t, "false", flag.DefValue)
+}
+
 // Expectation: The root command should have a "seq-url" persistent flag.
 func Test_NewRootCmd_HasSeqURLFlag_Success(t *testing.T) {
 	t.Parallel()
@@ -204,6 +446,25 @@ func Test_NewRootCmd_HasMprofFlag_Success(t *testing.T) {
 	require.Empty(t, flag.DefValue)
 }
 
+// Expectation: The profiling flags should be reachable by their long-form aliases.
+func Test_NewRootCmd_ProfileFlagAliases_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRootCmd(t.Context())
+
+	cpu := cmd.PersistentFlags().Lookup("cpu-profile")
+	require.NotNil(t, cpu)
+	require.Equal(t, "pprof", cpu.Name)
+
+	mem := cmd.PersistentFlags().Lookup("mem-profile")
+	require.NotNil(t, mem)
+	require.Equal(t, "mprof", mem.Name)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--cpu-profile", "/tmp/cpu.prof", "--mem-profile", "/tmp/mem.prof"}))
+	require.Equal(t, "/tmp/cpu.prof", cpu.Value.String())
+	require.Equal(t, "/tmp/mem.prof", mem.Value.String())
+}
+
 // Expectation: The root command should have a "create" subcommand.
 func Test_NewRootCmd_HasCreateCommand_Success(t *testing.T) {
 	t.Parallel()
@@ -256,6 +517,45 @@ func Test_NewRootCmd_HasInfoCommand_Success(t *testing.T) {
 	require.Equal(t, "info", infoCmd.Name())
 }
 
+// Expectation: The root command should have an "export" subcommand.
+func Test_NewRootCmd_HasExportCommand_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRootCmd(t.Context())
+
+	exportCmd, _, err := cmd.Find([]string{"export"})
+
+	require.NoError(t, err)
+	require.NotNil(t, exportCmd)
+	require.Equal(t, "export", exportCmd.Name())
+}
+
+// Expectation: The "export" command should have a "format" flag defaulting to JSON.
+func Test_NewExportCmd_HasFormatFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newExportCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("format")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "format", flag.Value.Type())
+	require.Equal(t, "json", flag.DefValue)
+	require.Equal(t, "f", flag.Shorthand)
+}
+
+// Expectation: The "export" command cannot run without arguments.
+func Test_NewExportCmd_RequiresArgs_Error(t *testing.T) {
+	t.Parallel()
+
+	cmd := newExportCmd(t.Context(), newGlobalOptions())
+	cmd.SetArgs([]string{})
+
+	err := cmd.Execute()
+
+	require.Error(t, err)
+}
+
 // Expectation: The root command should have a "check-config" subcommand.
 func Test_NewRootCmd_HasCheckConfigCommand_Success(t *testing.T) {
 	t.Parallel()
@@ -456,6 +756,19 @@ func Test_NewCreateCmd_DefaultArgs_Success(t *testing.T) {
 	require.True(t, cmd.HasFlags())
 }
 
+// Expectation: The "create" command should have a "limit" flag.
+func Test_NewCreateCmd_HasLimitFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newCreateCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("limit")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "int", flag.Value.Type())
+	require.Equal(t, "0", flag.Value.String())
+}
+
 // Expectation: The "create" command should have a "config" flag.
 func Test_NewCreateCmd_HasConfigFlag_Success(t *testing.T) {
 	t.Parallel()
@@ -513,6 +826,45 @@ func Test_NewCreateCmd_HasBundleFlag_Success(t *testing.T) {
 	require.Equal(t, "false", flag.Value.String())
 }
 
+// Expectation: The "create" command should have an "exclude-empty" flag.
+func Test_NewCreateCmd_HasExcludeEmptyFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newCreateCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("exclude-empty")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "bool", flag.Value.Type())
+	require.Equal(t, "false", flag.Value.String())
+}
+
+// Expectation: The "create" command should have an "adopt-existing" flag.
+func Test_NewCreateCmd_HasAdoptExistingFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newCreateCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("adopt-existing")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "bool", flag.Value.Type())
+	require.Equal(t, "false", flag.Value.String())
+}
+
+// Expectation: The "create" command should have a "strict-glob" flag.
+func Test_NewCreateCmd_HasStrictGlobFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newCreateCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("strict-glob")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "bool", flag.Value.Type())
+	require.Equal(t, "false", flag.Value.String())
+}
+
 // Expectation: The "create" command should have a "verify" flag.
 func Test_NewCreateCmd_HasVerifyFlag_Success(t *testing.T) {
 	t.Parallel()
@@ -579,6 +931,32 @@ func Test_NewVerifyCmd_DefaultArgs_Success(t *testing.T) {
 	require.True(t, cmd.HasFlags())
 }
 
+// Expectation: The "verify" command should have a "limit" flag.
+func Test_NewVerifyCmd_HasLimitFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newVerifyCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("limit")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "int", flag.Value.Type())
+	require.Equal(t, "0", flag.Value.String())
+}
+
+// Expectation: The "verify" command should have a "strict-par2" flag.
+func Test_NewVerifyCmd_HasStrictPar2Flag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newVerifyCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("strict-par2")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "bool", flag.Value.Type())
+	require.Equal(t, "false", flag.Value.String())
+}
+
 // Expectation: The "verify" command should have a "config" flag.
 func Test_NewVerifyCmd_HasConfigFlag_Success(t *testing.T) {
 	t.Parallel()
@@ -670,6 +1048,45 @@ func Test_NewVerifyCmd_HasIncludeExternalFlag_Success(t *testing.T) {
 	require.Equal(t, "false", flag.Value.String())
 }
 
+// Expectation: The "verify" command should have a "clean-orphans" flag.
+func Test_NewVerifyCmd_HasCleanOrphansFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newVerifyCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("clean-orphans")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "bool", flag.Value.Type())
+	require.Equal(t, "false", flag.Value.String())
+}
+
+// Expectation: The "verify" command should have a "from-stdin" flag.
+func Test_NewVerifyCmd_HasFromStdinFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newVerifyCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("from-stdin")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "bool", flag.Value.Type())
+	require.Equal(t, "false", flag.Value.String())
+}
+
+// Expectation: The "verify" command should have a "tag" flag.
+func Test_NewVerifyCmd_HasTagFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newVerifyCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("tag")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "tags", flag.Value.Type())
+	require.Empty(t, flag.Value.String())
+}
+
 // Expectation: The "verify" command should have a "skip-not-created" flag.
 func Test_NewVerifyCmd_HasSkipNotCreatedFlag_Success(t *testing.T) {
 	t.Parallel()
@@ -706,6 +1123,19 @@ func Test_NewRepairCmd_DefaultArgs_Success(t *testing.T) {
 	require.True(t, cmd.HasFlags())
 }
 
+// Expectation: The "repair" command should have a "limit" flag.
+func Test_NewRepairCmd_HasLimitFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRepairCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("limit")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "int", flag.Value.Type())
+	require.Equal(t, "0", flag.Value.String())
+}
+
 // Expectation: The "repair" command should have a "duration" flag.
 func Test_NewRepairCmd_HasDurationFlag_Success(t *testing.T) {
 	t.Parallel()
@@ -788,6 +1218,66 @@ func Test_NewRepairCmd_HasVerifyFlag_Success(t *testing.T) {
 	require.Equal(t, "false", flag.DefValue)
 }
 
+// Expectation: The "repair" command should have a "clean-orphans" flag.
+func Test_NewRepairCmd_HasCleanOrphansFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRepairCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("clean-orphans")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "bool", flag.Value.Type())
+	require.Equal(t, "false", flag.Value.String())
+}
+
+// Expectation: The "repair" command should have a "from-stdin" flag.
+func Test_NewRepairCmd_HasFromStdinFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRepairCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("from-stdin")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "bool", flag.Value.Type())
+	require.Equal(t, "false", flag.Value.String())
+}
+
+// Expectation: The "repair" command should have an "assume-yes" flag.
+func Test_NewRepairCmd_HasAssumeYesFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRepairCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("assume-yes")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "y", flag.Shorthand)
+	require.Equal(t, "bool", flag.Value.Type())
+	require.Equal(t, "false", flag.Value.String())
+}
+
+// Expectation: A reader that is not a file should not be considered a terminal.
+func Test_isTerminal_NotFile_Success(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, isTerminal(strings.NewReader("")))
+}
+
+// Expectation: The "repair" command should have a "tag" flag.
+func Test_NewRepairCmd_HasTagFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRepairCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("tag")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "tags", flag.Value.Type())
+	require.Empty(t, flag.Value.String())
+}
+
 // Expectation: The "repair" command should have a "skip-not-created" flag.
 func Test_NewRepairCmd_HasSkipNotCreatedFlag_Success(t *testing.T) {
 	t.Parallel()
@@ -941,6 +1431,19 @@ func Test_NewInfoCmd_HasIncludeExternalFlag_Success(t *testing.T) {
 	require.Equal(t, "false", flag.Value.String())
 }
 
+// Expectation: The "info" command should have a "tag" flag.
+func Test_NewInfoCmd_HasTagFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newInfoCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("tag")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "tags", flag.Value.Type())
+	require.Empty(t, flag.Value.String())
+}
+
 // Expectation: The "info" command should have a "skip-not-created" flag.
 func Test_NewInfoCmd_HasSkipNotCreatedFlag_Success(t *testing.T) {
 	t.Parallel()
@@ -1139,6 +1642,33 @@ func Test_logOperationResult_NoErrors_Success(t *testing.T) {
 	require.Contains(t, logOutput, "\"selectedCount\":7")
 }
 
+// Expectation: logOperationResult should log the phases of the elapsed time where tracked.
+func Test_logOperationResult_Timings_Success(t *testing.T) {
+	t.Parallel()
+
+	logout := &testutil.SafeBuffer{}
+	ls := logging.Options{
+		Logout:   logout,
+		Stdout:   &testutil.SafeBuffer{},
+		Stderr:   &testutil.SafeBuffer{},
+		WantJSON: true,
+	}
+	_ = ls.LogLevel.Set("info")
+	log := logging.NewLogger(ls)
+
+	timings := util.NewTimings()
+	timings.Add(util.PhasePar2, 3*time.Second)
+
+	logOperationResult(nil, util.ResultTracker{Selected: 1, Success: 1, Timings: timings}, log)
+	require.Contains(t, logout.String(), "\"par2Duration\":\"3s\"")
+	require.Contains(t, logout.String(), "\"walkDuration\":\"0s\"")
+	require.Contains(t, logout.String(), "\"totalDuration\"")
+
+	logout.Reset()
+	logOperationResult(nil, util.ResultTracker{Selected: 1, Success: 1}, log)
+	require.NotContains(t, logout.String(), "totalDuration")
+}
+
 // Expectation: logOperationResult should log error when errors occurred but operation completed.
 func Test_logOperationResult_CompletedWithErrors_Success(t *testing.T) {
 	t.Parallel()
@@ -1232,6 +1762,43 @@ func Test_logOperationResult_ContextCanceled_Success(t *testing.T) {
 	require.Contains(t, logOutput, "\"selectedCount\":10")
 }
 
+// Expectation: logOperationResult should report the remaining jobs of an interruption, with their estimate where known.
+func Test_logOperationResult_Interrupted_Remaining_Success(t *testing.T) {
+	t.Parallel()
+
+	logout := &testutil.SafeBuffer{}
+	ls := logging.Options{
+		Logout:   logout,
+		Stdout:   &testutil.SafeBuffer{},
+		Stderr:   &testutil.SafeBuffer{},
+		WantJSON: true,
+	}
+	_ = ls.LogLevel.Set("info")
+	log := logging.NewLogger(ls)
+
+	result := util.ResultTracker{
+		Success:           3,
+		Selected:          10,
+		RemainingDuration: 90 * time.Minute,
+		RemainingUnknown:  2,
+	}
+
+	logOperationResult(fmt.Errorf("context error: %w", context.Canceled), result, log)
+
+	logOutput := logout.String()
+	require.Contains(t, logOutput, "Operation interrupted (3/10 jobs processed)")
+	require.Contains(t, logOutput, "\"remainingCount\":7")
+	require.Contains(t, logOutput, "\"remainingEstimate\":\"1h30m0s\"")
+	require.Contains(t, logOutput, "\"remainingUnknownCount\":2")
+
+	logout.Reset()
+	logOperationResult(context.Canceled, util.ResultTracker{Selected: 4, Success: 1}, log)
+
+	logOutput = logout.String()
+	require.Contains(t, logOutput, "\"remainingCount\":3")
+	require.NotContains(t, logOutput, "remainingEstimate")
+}
+
 // Expectation: logOperationResult should handle zero counts correctly.
 func Test_logOperationResult_ZeroCounts_Success(t *testing.T) {
 	t.Parallel()
@@ -1295,3 +1862,68 @@ func Test_logOperationResult_PartialCompletion_Success(t *testing.T) {
 	require.Contains(t, logOutput, "\"processedCount\":5")
 	require.Contains(t, logOutput, "\"selectedCount\":20")
 }
+
+// Expectation: The root command should have an "attention" subcommand.
+func Test_NewRootCmd_HasAttentionCommand_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRootCmd(t.Context())
+
+	attentionCmd, _, err := cmd.Find([]string{"attention"})
+
+	require.NoError(t, err)
+	require.NotNil(t, attentionCmd)
+	require.Equal(t, "attention", attentionCmd.Name())
+}
+
+// Expectation: The "attention" command should have an "overdue" flag defaulting to 7 days.
+func Test_NewAttentionCmd_HasOverdueFlag_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newAttentionCmd(t.Context(), newGlobalOptions())
+
+	flag := cmd.Flags().Lookup("overdue")
+
+	require.NotNil(t, flag)
+	require.Equal(t, "duration", flag.Value.Type())
+	require.Equal(t, "7d", flag.DefValue)
+	require.Equal(t, "o", flag.Shorthand)
+}
+
+// Expectation: The "attention" command cannot run without arguments.
+func Test_NewAttentionCmd_RequiresArgs_Error(t *testing.T) {
+	t.Parallel()
+
+	cmd := newAttentionCmd(t.Context(), newGlobalOptions())
+	cmd.SetArgs([]string{})
+
+	err := cmd.Execute()
+
+	require.Error(t, err)
+}
+
+// Expectation: The root command should contain the "restore-metadata" command.
+func Test_NewRootCmd_HasRestoreMetadataCommand_Success(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRootCmd(t.Context())
+
+	restoreMetadataCmd, _, err := cmd.Find([]string{"restore-metadata"})
+
+	require.NoError(t, err)
+	require.NotNil(t, restoreMetadataCmd)
+	require.Equal(t, "restore-metadata", restoreMetadataCmd.Name())
+	require.NotNil(t, restoreMetadataCmd.Flags().Lookup("dry-run"))
+}
+
+// Expectation: The "restore-metadata" command cannot run without arguments.
+func Test_NewRestoreMetadataCmd_RequiresArgs_Error(t *testing.T) {
+	t.Parallel()
+
+	cmd := newRestoreMetadataCmd(t.Context(), newGlobalOptions())
+	cmd.SetArgs([]string{})
+
+	err := cmd.Execute()
+
+	require.Error(t, err)
+}
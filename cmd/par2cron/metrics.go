@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+)
+
+const (
+	metricLastRun     = "par2cron_last_run_timestamp_seconds"
+	metricLastSuccess = "par2cron_last_success_timestamp_seconds"
+)
+
+type metricHelp struct {
+	name string
+	help string
+}
+
+// metricsHelp are the metrics of a metrics file with their help texts, in
+// the order they are written out.
+var metricsHelp = []metricHelp{
+	{metricLastRun, "Time of the last run of the operation."},
+	{metricLastSuccess, "Time of the last fully successful run (without errors) of the operation."},
+}
+
+var metricsLinePattern = regexp.MustCompile(`^(par2cron_[a-z_]+)\{operation="([^"\\]*)"\} (\d+)$`)
+
+// metricsValues are the values of the metrics of a metrics file, by metric
+// name and then operation (as the label of the metric).
+type metricsValues map[string]map[string]int64
+
+// writeMetricsFile atomically writes the metrics of the operation to the
+// --metrics-file (if set), in the OpenMetrics text format. The values of
+// other operations and the last success of this operation (for a run that
+// was not fully successful) are kept from the previous metrics file, which
+// is read and replaced under a lock on its sidecar lock file (so that runs
+// finishing at the same time do not drop each other's values).
+// Failure to do so is logged but not returned.
+func writeMetricsFile(fsys afero.Fs, path string, op string, err error, result util.ResultTracker, log *logging.Logger) {
+	if path == "" {
+		return
+	}
+
+	unlock, lerr := util.AcquireLock(fsys, path+schema.LockExtension, true)
+	if lerr != nil {
+		log.Warn("Failed to lock metrics file (not writing it)", "path", path, "error", lerr)
+
+		return
+	}
+	defer unlock()
+
+	values := metricsValues{}
+	if data, rerr := afero.ReadFile(fsys, path); rerr == nil {
+		values = parseMetrics(data)
+	} else if !errors.Is(rerr, fs.ErrNotExist) {
+		log.Warn("Failed to read previous metrics file (not keeping its values)", "path", path, "error", rerr)
+	}
+
+	updateMetrics(values, op, time.Now(), err, result)
+
+	if werr := util.WriteFileAtomic(fsys, path, formatMetrics(values)); werr != nil {
+		log.Warn("Failed to write metrics file", "path", path, "error", werr)
+	}
+}
+
+// updateMetrics records the run of the operation into the values, where only
+// a fully successful (neither failed, interrupted nor with failed jobs) run
+// updates the last success.
+func updateMetrics(values metricsValues, op string, now time.Time, err error, result util.ResultTracker) {
+	values.set(metricLastRun, op, now.Unix())
+
+	if err == nil && result.Error == 0 {
+		values.set(metricLastSuccess, op, now.Unix())
+	}
+}
+
+func (v metricsValues) set(name string, op string, value int64) {
+	if v[name] == nil {
+		v[name] = make(map[string]int64)
+	}
+	v[name][op] = value
+}
+
+// parseMetrics parses the values of the known metrics from a metrics file
+// (as written by [formatMetrics]), skipping all lines that are not such.
+func parseMetrics(data []byte) metricsValues {
+	values := metricsValues{}
+
+	for line := range strings.Lines(string(data)) {
+		m := metricsLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		if !slices.ContainsFunc(metricsHelp, func(h metricHelp) bool { return h.name == m[1] }) {
+			continue
+		}
+
+		value, err := strconv.ParseInt(m[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		values.set(m[1], m[2], value)
+	}
+
+	return values
+}
+
+func formatMetrics(values metricsValues) []byte {
+	var b strings.Builder
+
+	for _, metric := range metricsHelp {
+		fmt.Fprintf(&b, "# HELP %s %s\n", metric.name, metric.help)
+		fmt.Fprintf(&b, "# TYPE %s gauge\n", metric.name)
+
+		for _, op := range slices.Sorted(maps.Keys(values[metric.name])) {
+			fmt.Fprintf(&b, "%s{operation=%q} %d\n", metric.name, op, values[metric.name][op])
+		}
+	}
+	b.WriteString("# EOF\n")
+
+	return []byte(b.String())
+}
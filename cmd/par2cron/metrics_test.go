@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: A fully successful run should update both its last run and its last success.
+func Test_updateMetrics_Success(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	values := metricsValues{}
+
+	updateMetrics(values, "verify", now, nil, util.ResultTracker{Selected: 1, Success: 1})
+
+	require.Equal(t, int64(1700000000), values[metricLastRun]["verify"])
+	require.Equal(t, int64(1700000000), values[metricLastSuccess]["verify"])
+}
+
+// Expectation: A run with errors (failed jobs, a failure or an interruption) should only update its last run,
+// keeping the previous last success.
+func Test_updateMetrics_Error_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		err    error
+		result util.ResultTracker
+	}{
+		{"failed jobs", fmt.Errorf("%w: %w", schema.ErrExitPartialFailure, errors.New("failure")), util.ResultTracker{Selected: 1, Error: 1}},
+		{"failed run", schema.ErrExitBadInvocation, util.ResultTracker{}},
+		{"interrupted", context.Canceled, util.ResultTracker{Selected: 2, Success: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			values := metricsValues{}
+			values.set(metricLastSuccess, "verify", 1600000000)
+
+			updateMetrics(values, "verify", time.Unix(1700000000, 0), tt.err, tt.result)
+
+			require.Equal(t, int64(1700000000), values[metricLastRun]["verify"])
+			require.Equal(t, int64(1600000000), values[metricLastSuccess]["verify"])
+		})
+	}
+}
+
+// Expectation: The formatted metrics should parse back to the same values, skipping unknown lines.
+func Test_parseMetrics_RoundTrip_Success(t *testing.T) {
+	t.Parallel()
+
+	values := metricsValues{}
+	values.set(metricLastRun, "repair", 1700000100)
+	values.set(metricLastRun, "verify", 1700000000)
+	values.set(metricLastSuccess, "verify", 1600000000)
+
+	data := formatMetrics(values)
+	require.Equal(t, `# HELP par2cron_last_run_timestamp_seconds Time of the last run of the operation.
+# TYPE par2cron_last_run_timestamp_seconds gauge
+par2cron_last_run_timestamp_seconds{operation="repair"} 1700000100
+par2cron_last_run_timestamp_seconds{operation="verify"} 1700000000
+# HELP par2cron_last_success_timestamp_seconds Time of the last fully successful run (without errors) of the operation.
+# TYPE par2cron_last_success_timestamp_seconds gauge
+par2cron_last_success_timestamp_seconds{operation="verify"} 1600000000
+# EOF
+`, string(data))
+	require.Equal(t, values, parseMetrics(data))
+
+	data = append(data, []byte("par2cron_unknown{operation=\"verify\"} 1\nnot a metric\n")...)
+	require.Equal(t, values, parseMetrics(data))
+}
+
+// Expectation: The metrics file should keep the last success of a run with errors and the values
+// of other operations from the previous metrics file, or not be written at all without a path.
+func Test_writeMetricsFile_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/status", 0o755))
+
+	log := logging.NewLogger(logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard})
+
+	writeMetricsFile(fs, "", "verify", nil, util.ResultTracker{}, log)
+
+	entries, err := afero.ReadDir(fs, "/status")
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	previous := metricsValues{}
+	previous.set(metricLastRun, "repair", 1600000100)
+	previous.set(metricLastSuccess, "repair", 1600000100)
+	previous.set(metricLastRun, "verify", 1600000000)
+	previous.set(metricLastSuccess, "verify", 1600000000)
+	require.NoError(t, afero.WriteFile(fs, "/status/par2cron.prom", formatMetrics(previous), 0o644))
+
+	runErr := fmt.Errorf("%w: %w", schema.ErrExitPartialFailure, errors.New("failure"))
+	writeMetricsFile(fs, "/status/par2cron.prom", "verify", runErr, util.ResultTracker{Selected: 1, Error: 1}, log)
+
+	data, err := afero.ReadFile(fs, "/status/par2cron.prom")
+	require.NoError(t, err)
+	values := parseMetrics(data)
+	require.Greater(t, values[metricLastRun]["verify"], int64(1600000000))
+	require.Equal(t, int64(1600000000), values[metricLastSuccess]["verify"])
+	require.Equal(t, int64(1600000100), values[metricLastSuccess]["repair"])
+
+	writeMetricsFile(fs, "/status/par2cron.prom", "verify", nil, util.ResultTracker{Selected: 1, Success: 1}, log)
+
+	data, err = afero.ReadFile(fs, "/status/par2cron.prom")
+	require.NoError(t, err)
+	values = parseMetrics(data)
+	require.Equal(t, values[metricLastRun]["verify"], values[metricLastSuccess]["verify"])
+	require.Equal(t, int64(1600000100), values[metricLastRun]["repair"])
+}
+
+// Expectation: Runs writing the same metrics file at the same time should not drop each other's values.
+func Test_writeMetricsFile_Concurrent_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewOsFs()
+	path := filepath.Join(t.TempDir(), "par2cron.prom")
+
+	log := logging.NewLogger(logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard})
+
+	ops := []string{"create", "verify", "repair", "bundle pack", "bundle unpack", "restore-metadata", "recreate", "self-test"}
+
+	var wg sync.WaitGroup
+	for _, op := range ops {
+		wg.Go(func() {
+			for range 10 {
+				writeMetricsFile(fs, path, op, nil, util.ResultTracker{}, log)
+			}
+		})
+	}
+	wg.Wait()
+
+	data, err := afero.ReadFile(fs, path)
+	require.NoError(t, err)
+
+	values := parseMetrics(data)
+	for _, op := range ops {
+		require.Contains(t, values[metricLastRun], op)
+		require.Contains(t, values[metricLastSuccess], op)
+	}
+}
+
+// Expectation: A failure to write the metrics file should only be logged.
+func Test_writeMetricsFile_Error(t *testing.T) {
+	t.Parallel()
+
+	var logBuf testutil.SafeBuffer
+	log := logging.NewLogger(logging.Options{Logout: &logBuf, Stdout: io.Discard, Stderr: io.Discard})
+
+	fs := afero.NewReadOnlyFs(afero.NewMemMapFs())
+	writeMetricsFile(fs, "/status/par2cron.prom", "create", nil, util.ResultTracker{}, log)
+
+	require.Contains(t, logBuf.String(), "Failed to write metrics file")
+}
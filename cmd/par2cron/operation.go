@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+)
+
+// operation describes the operation of a command, for the checks and outputs
+// shared by all commands: at their startup (in PreRunE), at the start of their
+// run (in RunE) and on the completion of their run.
+type operation struct {
+	name     string // name of the operation in the summary and metrics files
+	par2     bool   // runs "par2" (checked for its flavor and version)
+	root     bool   // writes next to the data (checked against running as root)
+	warnings bool   // fails on logged warnings (with --warnings-as-errors)
+}
+
+// preRun resolves the runtime "par2" flavor and refuses to run with an
+// outdated "par2" (with --require-par2-version) or as root (with
+// --refuse-root), as far as these apply to the operation. Nothing is refused
+// when nothing is run (with --dump-effective-config).
+func (op operation) preRun(opts *globalOptions, dumpConfig bool) error {
+	if op.par2 {
+		resolvePar2Flavor(opts)
+	}
+
+	if dumpConfig {
+		return nil
+	}
+
+	if op.par2 {
+		if err := checkPar2Version(opts); err != nil && opts.requirePar2Version {
+			return fmt.Errorf("%w (--require-par2-version)", err)
+		}
+	}
+	if op.root {
+		if err := checkRoot(opts, os.Geteuid()); err != nil && opts.refuseRoot {
+			return fmt.Errorf("%w (--refuse-root)", err)
+		}
+	}
+
+	return nil
+}
+
+// start logs the runtime "par2" flavor and warns about an outdated "par2" or
+// running as root, as far as these apply to the operation.
+func (op operation) start(opts *globalOptions, log *logging.Logger) {
+	if op.par2 {
+		log.Debug("Using par2 flavor", "flavor", schema.Par2Flavor, "par2Version", schema.Par2Version)
+		if err := checkPar2Version(opts); err != nil {
+			log.Warn("Installed par2 may be affected by known bugs (consider upgrading; or see --min-par2-version)", "error", err)
+		}
+	}
+	if op.root {
+		if err := checkRoot(opts, os.Geteuid()); err != nil {
+			log.Warn("Running as root may create sidecar files with wrong ownership (run as the data owner; or see --allow-root)", "error", err)
+		}
+	}
+}
+
+// finish completes the run of the operation, failing an otherwise successful
+// run on logged warnings (with --warnings-as-errors), then logging its result
+// and writing the summary and metrics files. It returns the final error.
+func (op operation) finish(fsys afero.Fs, opts *globalOptions, err error, result util.ResultTracker, log *logging.Logger) error {
+	if err == nil && op.warnings {
+		err = checkWarnings(opts, log)
+	}
+
+	logOperationResult(err, result, log)
+	writeSummaryFile(fsys, opts.summaryFile, op.name, err, result, log)
+	writeMetricsFile(fsys, opts.metricsFile, op.name, err, result, log)
+
+	return err
+}
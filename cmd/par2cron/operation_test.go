@@ -0,0 +1,100 @@
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: An outdated par2 should only be refused with --require-par2-version,
+// for operations running par2 and unless nothing is run (--dump-effective-config).
+//
+//nolint:paralleltest
+func Test_operation_preRun_Table(t *testing.T) {
+	oldVersion := schema.Par2Version
+	oldFlavor := schema.Par2Flavor
+
+	t.Cleanup(func() {
+		schema.Par2Version = oldVersion
+		schema.Par2Flavor = oldFlavor
+	})
+
+	tests := []struct {
+		name       string
+		op         operation
+		require    bool
+		dumpConfig bool
+		wantErr    bool
+	}{
+		{"warned only", operation{name: "verify", par2: true, root: true}, false, false, false},
+		{"refused", operation{name: "verify", par2: true, root: true}, true, false, true},
+		{"refused without root check", operation{name: "self-test", par2: true}, true, false, true},
+		{"not refused on dump", operation{name: "verify", par2: true, root: true}, true, true, false},
+		{"not running par2", operation{name: "bundle pack"}, true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema.Par2Version = "par2cmdline version 0.7.4"
+			schema.Par2Flavor = ""
+
+			opts := newGlobalOptions()
+			opts.allowRoot = true
+			opts.requirePar2Version = tt.require
+
+			err := tt.op.preRun(opts, tt.dumpConfig)
+			if tt.wantErr {
+				require.ErrorIs(t, err, errPar2Outdated)
+				require.ErrorContains(t, err, "(--require-par2-version)")
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tt.op.par2 {
+				require.Equal(t, schema.Par2FlavorClassic, schema.Par2Flavor)
+			} else {
+				require.Empty(t, schema.Par2Flavor)
+			}
+		})
+	}
+}
+
+// Expectation: Finishing a run that logged a warning should fail it only for operations
+// failing on warnings (with --warnings-as-errors), and write the summary file either way.
+func Test_operation_finish_Warnings_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+
+	var logBuf testutil.SafeBuffer
+	log := logging.NewLogger(logging.Options{Logout: &logBuf, Stdout: io.Discard, Stderr: io.Discard})
+	log.Warn("warn message")
+
+	opts := newGlobalOptions()
+	opts.warningsAsErrors = true
+	opts.summaryFile = "/summary.txt"
+
+	result := util.ResultTracker{Selected: 1, Success: 1}
+
+	err := operation{name: "bundle pack"}.finish(fs, opts, nil, result, log)
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, "/summary.txt")
+	require.NoError(t, err)
+	require.Contains(t, string(data), "par2cron bundle pack: completed\n")
+
+	err = operation{name: "verify", warnings: true}.finish(fs, opts, nil, result, log)
+	require.ErrorIs(t, err, schema.ErrExitWarnings)
+	require.Contains(t, logBuf.String(), "Operation completed with errors")
+
+	data, err = afero.ReadFile(fs, "/summary.txt")
+	require.NoError(t, err)
+	require.Contains(t, string(data), "par2cron verify: completed with errors\n")
+	require.Contains(t, string(data), "(--warnings-as-errors)")
+}
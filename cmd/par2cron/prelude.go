@@ -3,11 +3,13 @@ package main
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"path/filepath"
 	"slices"
 	"strings"
 
 	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
 	"github.com/spf13/afero"
 	"github.com/spf13/pflag"
 )
@@ -30,6 +32,13 @@ type preludeInput[A any, C configMergeable[A]] struct {
 
 type preludeResult struct {
 	ResolvedPaths []string
+
+	// Section is the command's section of the configuration file, which
+	// is a typed nil pointer if there was none (for dumping its options).
+	Section any
+
+	// ExternalArgs are the par2 arguments given after "--" (nil if none).
+	ExternalArgs []string
 }
 
 func runPrelude[A any, C configMergeable[A]](in *preludeInput[A, C]) (*preludeResult, error) {
@@ -54,13 +63,14 @@ func runPrelude[A any, C configMergeable[A]](in *preludeInput[A, C]) (*preludeRe
 		}
 	}
 
+	var section C
 	if in.ConfigPath != "" {
 		cfg, err := parseConfigFile(in.FSys, in.ConfigPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse --config file: %w", err)
 		}
 
-		section := in.ExtractSection(cfg)
+		section = in.ExtractSection(cfg)
 		if section != nil {
 			setFlags := make(map[string]bool)
 			in.VisitFlags(func(f *pflag.Flag) {
@@ -70,17 +80,53 @@ func runPrelude[A any, C configMergeable[A]](in *preludeInput[A, C]) (*preludeRe
 		}
 	}
 
+	allowedPar2Args := par2ArgsAllowlist(in.GlobalOptions)
+
 	if hasExternalArgs {
+		if err := util.CheckPar2Args(externalArgs, allowedPar2Args); err != nil {
+			return nil, fmt.Errorf("failed to validate arguments after --: %w", err)
+		}
 		if setter, ok := any(in.CommandOptions).(schema.OptionsPar2ArgsSettable); ok {
 			setter.SetPar2Args(externalArgs)
 		}
 	}
 
+	// The merged par2 arguments (and those of any markers) are checked against
+	// the same allowlist by the command's options, not only those after "--".
+	if setter, ok := any(in.CommandOptions).(schema.OptionsPar2ArgsAllowable); ok {
+		setter.SetAllowedPar2Args(allowedPar2Args)
+	}
+
 	resolved, err := resolvePathArgs(in.FSys, pathArgs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve paths: %w", err)
 	}
 
+	if err := in.GlobalOptions.ignoreNames.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate options: %w", err)
+	}
+	if err := in.GlobalOptions.sidecarNames.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate options: %w", err)
+	}
+	if err := in.GlobalOptions.logOptions.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate options: %w", err)
+	}
+
+	var maxDepthSet bool
+	in.VisitFlags(func(f *pflag.Flag) {
+		if f.Name == "max-depth" {
+			maxDepthSet = true
+		}
+	})
+	if err := resolveMaxDepth(in.GlobalOptions, maxDepthSet); err != nil {
+		return nil, fmt.Errorf("failed to validate options: %w", err)
+	}
+	if in.GlobalOptions.tempDir != "" {
+		if err := util.CheckTempDir(in.FSys, in.GlobalOptions.tempDir); err != nil {
+			return nil, fmt.Errorf("failed to validate options: %w", err)
+		}
+	}
+
 	if validator, ok := any(in.CommandOptions).(schema.OptionsValidatable); ok {
 		if err := validator.Validate(); err != nil {
 			if errors.Is(err, schema.ErrUnsupportedGlob) {
@@ -92,7 +138,17 @@ func runPrelude[A any, C configMergeable[A]](in *preludeInput[A, C]) (*preludeRe
 		}
 	}
 
-	return &preludeResult{ResolvedPaths: resolved}, nil
+	return &preludeResult{
+		ResolvedPaths: resolved,
+		Section:       section,
+		ExternalArgs:  externalArgs,
+	}, nil
+}
+
+// par2ArgsAllowlist returns the par2 arguments permitted for the command, which
+// is the default allowlist extended by those of the configuration file.
+func par2ArgsAllowlist(global *globalOptions) []string {
+	return append(slices.Clone(util.DefaultAllowedPar2Args), global.allowedPar2Args...)
 }
 
 func resolvePathArgs(fsys afero.Fs, pathArgs []string) ([]string, error) {
@@ -105,7 +161,9 @@ func resolvePathArgs(fsys afero.Fs, pathArgs []string) ([]string, error) {
 		}
 
 		resolved[i] = abs
-		if fi, err := fsys.Stat(abs); err != nil {
+		if fi, err := fsys.Stat(abs); errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: failed to access root directory: %w", schema.ErrExitMissingData, err)
+		} else if err != nil {
 			return nil, fmt.Errorf("failed to access root directory: %w", err)
 		} else if !fi.IsDir() {
 			return nil, fmt.Errorf("root directory is not a directory: %s", abs)
@@ -114,3 +172,20 @@ func resolvePathArgs(fsys afero.Fs, pathArgs []string) ([]string, error) {
 
 	return resolved, nil
 }
+
+// resolveForcePaths converts the paths given to --force to absolute paths,
+// which are not required to exist (or to be directories) at this point.
+func resolveForcePaths(paths []string) ([]string, error) {
+	resolved := make([]string, 0, len(paths))
+
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert path to absolute: %w", err)
+		}
+
+		resolved = append(resolved, abs)
+	}
+
+	return resolved, nil
+}
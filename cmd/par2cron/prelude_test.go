@@ -11,6 +11,7 @@ import (
 	"github.com/desertwitch/par2cron/internal/logging"
 	"github.com/desertwitch/par2cron/internal/repair"
 	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
 	"github.com/desertwitch/par2cron/internal/verify"
 	"github.com/spf13/afero"
 	"github.com/spf13/pflag"
@@ -26,6 +27,14 @@ func newTestGlobal() *globalOptions {
 	_ = logs.LogLevel.Set("info")
 
 	return &globalOptions{
+		ignoreNames: util.IgnoreNames{
+			File:    schema.IgnoreFile,
+			AllFile: schema.IgnoreAllFile,
+		},
+		sidecarNames: util.SidecarNames{
+			ManifestSuffix: schema.ManifestExtension,
+			LockSuffix:     schema.LockExtension,
+		},
 		logOptions: logs,
 	}
 }
@@ -164,6 +173,140 @@ func Test_runPrelude_HasExternalArgsPrecedence_Success(t *testing.T) {
 	require.Equal(t, []string{"-r30"}, opts.Par2Args)
 }
 
+// Expectation: Arguments after -- should pass when permitted by the configured allowlist.
+func Test_runPrelude_AllowedArgs_Permitted_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	yamlContent := `verify:
+  allowed-args: ["-B/data"]`
+	require.NoError(t, afero.WriteFile(fs, "/par2cron.yaml", []byte(yamlContent), 0o644))
+
+	opts := &verify.Options{}
+
+	_, err := runPrelude(&preludeInput[*verify.Options, *configFileVerify]{
+		FSys:           fs,
+		Args:           []string{"/data", "-q", "-t4", "-B/data"},
+		DashAt:         1,
+		ConfigPath:     "/par2cron.yaml",
+		CommandOptions: opts,
+		GlobalOptions:  newTestGlobal(),
+		ExtractSection: func(cfg *configFile) *configFileVerify { return cfg.Verify },
+		VisitFlags:     noVisitFlags,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"-q", "-t4", "-B/data"}, opts.Par2Args)
+}
+
+// Expectation: Arguments after -- should be rejected when not permitted by the configured allowlist.
+func Test_runPrelude_AllowedArgs_Rejected_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	yamlContent := `repair:
+  allowed-args: []`
+	require.NoError(t, afero.WriteFile(fs, "/par2cron.yaml", []byte(yamlContent), 0o644))
+
+	for _, args := range [][]string{
+		{"-B/etc"},
+		{"-q", "-a/tmp/other.par2"},
+		{"-r10", "--", "/etc/passwd"},
+		{"-rfoo"},
+	} {
+		opts := &repair.Options{}
+
+		_, err := runPrelude(&preludeInput[*repair.Options, *configFileRepair]{
+			FSys:           fs,
+			Args:           append([]string{"/data"}, args...),
+			DashAt:         1,
+			ConfigPath:     "/par2cron.yaml",
+			CommandOptions: opts,
+			GlobalOptions:  newTestGlobal(),
+			ExtractSection: func(cfg *configFile) *configFileRepair { return cfg.Repair },
+			VisitFlags:     noVisitFlags,
+		})
+
+		require.ErrorIs(t, err, schema.ErrPar2ArgNotAllowed, args)
+		require.Empty(t, opts.Par2Args)
+	}
+}
+
+// Expectation: The default allowlist should apply without a configured allowlist.
+func Test_runPrelude_AllowedArgs_Default_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	opts := &verify.Options{}
+
+	_, err := runPrelude(&preludeInput[*verify.Options, *configFileVerify]{
+		FSys:           fs,
+		Args:           []string{"/data", "-q", "-B/etc"},
+		DashAt:         1,
+		CommandOptions: opts,
+		GlobalOptions:  newTestGlobal(),
+		ExtractSection: func(cfg *configFile) *configFileVerify { return cfg.Verify },
+		VisitFlags:     noVisitFlags,
+	})
+
+	require.ErrorIs(t, err, schema.ErrPar2ArgNotAllowed)
+	require.Empty(t, opts.Par2Args)
+}
+
+// Expectation: The merged arguments should be checked, not only those after --.
+func Test_runPrelude_AllowedArgs_MergedArgs_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	opts := &repair.Options{Par2Args: []string{"-q", "-a/tmp/other.par2"}}
+
+	_, err := runPrelude(&preludeInput[*repair.Options, *configFileRepair]{
+		FSys:           fs,
+		Args:           []string{"/data"},
+		DashAt:         -1,
+		CommandOptions: opts,
+		GlobalOptions:  newTestGlobal(),
+		ExtractSection: func(cfg *configFile) *configFileRepair { return cfg.Repair },
+		VisitFlags:     noVisitFlags,
+	})
+
+	require.ErrorIs(t, err, schema.ErrPar2ArgNotAllowed)
+	require.Equal(t, util.DefaultAllowedPar2Args, opts.AllowedPar2Args)
+}
+
+// Expectation: The arguments of the configuration file should be checked against its allowlist.
+func Test_runPrelude_AllowedArgs_ConfigArgs_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	yamlContent := `create:
+  args: ["-r10", "-B/etc"]`
+	require.NoError(t, afero.WriteFile(fs, "/par2cron.yaml", []byte(yamlContent), 0o644))
+
+	_, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
+		FSys:           fs,
+		Args:           []string{"/data"},
+		DashAt:         -1,
+		ConfigPath:     "/par2cron.yaml",
+		CommandOptions: newTestCreateOptions(),
+		GlobalOptions:  newTestGlobal(),
+		ExtractSection: func(cfg *configFile) *configFileCreate { return cfg.Create },
+		VisitFlags:     noVisitFlags,
+	})
+
+	require.ErrorIs(t, err, schema.ErrPar2ArgNotAllowed)
+}
+
 // Expectation: When DashAt is set and there's external args, they take precedence.
 func Test_runPrelude_HasExternalArgsPrecedence_VerifyType_Success(t *testing.T) {
 	t.Parallel()
@@ -259,7 +402,7 @@ func Test_runPrelude_MultiplePathsWithExternalArgs_Success(t *testing.T) {
 
 	result, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
 		FSys:           fs,
-		Args:           []string{"/data", "/backup", "-B"},
+		Args:           []string{"/data", "/backup", "-q"},
 		DashAt:         2,
 		CommandOptions: opts,
 		GlobalOptions:  newTestGlobal(),
@@ -269,7 +412,7 @@ func Test_runPrelude_MultiplePathsWithExternalArgs_Success(t *testing.T) {
 
 	require.NoError(t, err)
 	require.Equal(t, []string{"/data", "/backup"}, result.ResolvedPaths)
-	require.Equal(t, []string{"-B"}, opts.Par2Args)
+	require.Equal(t, []string{"-q"}, opts.Par2Args)
 }
 
 // Expectation: Path stat failure after DashAt should not be checked (it is an external arg).
@@ -283,7 +426,7 @@ func Test_runPrelude_NoCheckPathAfterDash_Success(t *testing.T) {
 
 	result, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
 		FSys:           fs,
-		Args:           []string{"/data", "-nonexistent-flag"},
+		Args:           []string{"/data", "-v"},
 		DashAt:         1,
 		CommandOptions: opts,
 		GlobalOptions:  newTestGlobal(),
@@ -293,7 +436,7 @@ func Test_runPrelude_NoCheckPathAfterDash_Success(t *testing.T) {
 
 	require.NoError(t, err)
 	require.Len(t, result.ResolvedPaths, 1)
-	require.Equal(t, []string{"-nonexistent-flag"}, opts.Par2Args)
+	require.Equal(t, []string{"-v"}, opts.Par2Args)
 }
 
 // Expectation: Empty args with no DashAt should return empty resolved paths.
@@ -368,7 +511,7 @@ func Test_runPrelude_SinglePathNoDash_Success(t *testing.T) {
 	require.Nil(t, opts.Par2Args)
 }
 
-// Expectation: An error should be returned when a path does not exist.
+// Expectation: An error should be returned when a path does not exist, being missing data (not a bad invocation).
 func Test_runPrelude_PathNotExist_Error(t *testing.T) {
 	t.Parallel()
 
@@ -386,6 +529,7 @@ func Test_runPrelude_PathNotExist_Error(t *testing.T) {
 
 	require.Error(t, err)
 	require.ErrorContains(t, err, "failed to access root directory")
+	require.ErrorIs(t, err, schema.ErrExitMissingData)
 	require.Nil(t, result)
 }
 
@@ -411,6 +555,55 @@ func Test_runPrelude_PathNotDirectory_Error(t *testing.T) {
 	require.Nil(t, result)
 }
 
+// Expectation: An error should be returned when the temporary directory does not exist.
+func Test_runPrelude_TempDirNotExist_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	global := newTestGlobal()
+	global.tempDir = "/nonexistent"
+
+	result, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
+		FSys:           fs,
+		Args:           []string{"/data"},
+		DashAt:         -1,
+		CommandOptions: newTestCreateOptions(),
+		GlobalOptions:  global,
+		ExtractSection: func(cfg *configFile) *configFileCreate { return cfg.Create },
+		VisitFlags:     noVisitFlags,
+	})
+
+	require.ErrorContains(t, err, "temp-dir")
+	require.Nil(t, result)
+}
+
+// Expectation: An existing and writable temporary directory should pass the prelude.
+func Test_runPrelude_TempDir_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	require.NoError(t, fs.MkdirAll("/tmp/scratch", 0o755))
+
+	global := newTestGlobal()
+	global.tempDir = "/tmp/scratch"
+
+	result, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
+		FSys:           fs,
+		Args:           []string{"/data"},
+		DashAt:         -1,
+		CommandOptions: newTestCreateOptions(),
+		GlobalOptions:  global,
+		ExtractSection: func(cfg *configFile) *configFileCreate { return cfg.Create },
+		VisitFlags:     noVisitFlags,
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+}
+
 // Expectation: An error on the second path should still fail the whole prelude.
 func Test_runPrelude_SecondPathNotExist_Error(t *testing.T) {
 	t.Parallel()
@@ -537,7 +730,7 @@ func Test_runPrelude_NilConfigSection_Success(t *testing.T) {
 
 	fs := afero.NewMemMapFs()
 	require.NoError(t, fs.MkdirAll("/data", 0o755))
-	require.NoError(t, afero.WriteFile(fs, "/par2cron.yaml", []byte("verify:\n  args: [\"-B\"]"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/par2cron.yaml", []byte("verify:\n  args: [\"-B\"]\n  allowed-args: [\"-B\"]"), 0o644))
 
 	result, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
 		FSys:           fs,
@@ -623,6 +816,111 @@ func Test_runPrelude_ConfigMergesLogSettings_Success(t *testing.T) {
 	require.Equal(t, slog.LevelDebug, global.logOptions.LogLevel.Value)
 }
 
+// Expectation: Each command should pick up the log settings of its own section from one
+// configuration file, while an explicitly set --log-level (or --json) still wins.
+func Test_runPrelude_ConfigPerSectionLogSettings_Table(t *testing.T) {
+	t.Parallel()
+
+	yamlContent := `create:
+  log-level: "debug"
+verify:
+  log-level: "warn"
+  json: true
+repair:
+  log-level: "error"
+info:
+  log-level: "info"
+  json: true`
+
+	prelude := func(fs afero.Fs, global *globalOptions, command string, visit func(fn func(*pflag.Flag))) error {
+		var err error
+		switch command {
+		case "create":
+			_, err = runPrelude(&preludeInput[*create.Options, *configFileCreate]{
+				FSys:           fs,
+				Args:           []string{"/data"},
+				DashAt:         -1,
+				ConfigPath:     "/par2cron.yaml",
+				CommandOptions: newTestCreateOptions(),
+				GlobalOptions:  global,
+				VisitFlags:     visit,
+				ExtractSection: func(cfg *configFile) *configFileCreate { return cfg.Create },
+			})
+		case "verify":
+			_, err = runPrelude(&preludeInput[*verify.Options, *configFileVerify]{
+				FSys:           fs,
+				Args:           []string{"/data"},
+				DashAt:         -1,
+				ConfigPath:     "/par2cron.yaml",
+				CommandOptions: &verify.Options{},
+				GlobalOptions:  global,
+				VisitFlags:     visit,
+				ExtractSection: func(cfg *configFile) *configFileVerify { return cfg.Verify },
+			})
+		case "repair":
+			_, err = runPrelude(&preludeInput[*repair.Options, *configFileRepair]{
+				FSys:           fs,
+				Args:           []string{"/data"},
+				DashAt:         -1,
+				ConfigPath:     "/par2cron.yaml",
+				CommandOptions: &repair.Options{},
+				GlobalOptions:  global,
+				VisitFlags:     visit,
+				ExtractSection: func(cfg *configFile) *configFileRepair { return cfg.Repair },
+			})
+		case "info":
+			_, err = runPrelude(&preludeInput[*info.Options, *configFileInfo]{
+				FSys:           fs,
+				Args:           []string{"/data"},
+				DashAt:         -1,
+				ConfigPath:     "/par2cron.yaml",
+				CommandOptions: &info.Options{},
+				GlobalOptions:  global,
+				VisitFlags:     visit,
+				ExtractSection: func(cfg *configFile) *configFileInfo { return cfg.Info },
+			})
+		}
+
+		return err
+	}
+
+	tests := []struct {
+		command  string
+		wantLvl  slog.Level
+		wantJSON bool
+	}{
+		{"create", slog.LevelDebug, false},
+		{"verify", slog.LevelWarn, true},
+		{"repair", slog.LevelError, false},
+		{"info", slog.LevelInfo, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			require.NoError(t, fs.MkdirAll("/data", 0o755))
+			require.NoError(t, afero.WriteFile(fs, "/par2cron.yaml", []byte(yamlContent), 0o644))
+
+			global := newTestGlobal()
+			_ = global.logOptions.LogLevel.Set("error")
+			require.NoError(t, prelude(fs, global, tt.command, noVisitFlags))
+			require.Equal(t, tt.wantLvl, global.logOptions.LogLevel.Value)
+			require.Equal(t, tt.wantJSON, global.logOptions.WantJSON)
+
+			explicit := newTestGlobal()
+			_ = explicit.logOptions.LogLevel.Set("debug")
+			require.NoError(t, prelude(fs, explicit, tt.command, func(fn func(*pflag.Flag)) {
+				fn(&pflag.Flag{Name: "log-level"})
+				fn(&pflag.Flag{Name: "json"})
+			}))
+			require.Equal(t, slog.LevelDebug, explicit.logOptions.LogLevel.Value)
+			require.False(t, explicit.logOptions.WantJSON)
+		})
+	}
+}
+
 // Expectation: Config with duration should merge the duration value.
 func Test_runPrelude_ConfigMergesDuration_Success(t *testing.T) {
 	t.Parallel()
@@ -805,6 +1103,150 @@ func Test_runPrelude_ValidateGenericError_Error(t *testing.T) {
 	require.Nil(t, result)
 }
 
+// Expectation: Invalid ignore filenames should be rejected before any command options are validated.
+func Test_runPrelude_ValidateIgnoreNames_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	global := newTestGlobal()
+	global.ignoreNames.File = "sub/.par2cronignore"
+
+	result, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
+		FSys:           fs,
+		Args:           []string{"/data"},
+		DashAt:         -1,
+		CommandOptions: newTestCreateOptions(),
+		GlobalOptions:  global,
+		ExtractSection: func(cfg *configFile) *configFileCreate { return cfg.Create },
+		VisitFlags:     noVisitFlags,
+	})
+
+	require.ErrorContains(t, err, "ignore-file")
+	require.ErrorContains(t, err, "path separators")
+	require.Nil(t, result)
+}
+
+// Expectation: Invalid log file options should be rejected before running.
+func Test_runPrelude_ValidateLogFile_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	yamlContent := `create:
+  log-file: /var/log/par2cron.log
+  log-file-size: 0
+`
+	require.NoError(t, afero.WriteFile(fs, "/config.yaml", []byte(yamlContent), 0o644))
+
+	global := newTestGlobal()
+
+	result, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
+		FSys:           fs,
+		Args:           []string{"/data"},
+		DashAt:         -1,
+		ConfigPath:     "/config.yaml",
+		CommandOptions: newTestCreateOptions(),
+		GlobalOptions:  global,
+		ExtractSection: func(cfg *configFile) *configFileCreate { return cfg.Create },
+		VisitFlags:     noVisitFlags,
+	})
+
+	require.ErrorContains(t, err, "log-file-size")
+	require.Nil(t, result)
+	require.Equal(t, "/var/log/par2cron.log", global.logOptions.LogFile)
+}
+
+// Expectation: Ignore filenames from the config file should be merged into the global options.
+func Test_runPrelude_ConfigIgnoreNames_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	yamlContent := `create:
+  ignore-file: .par2cronignore
+  ignore-all-file: .par2cronignore-all
+`
+	require.NoError(t, afero.WriteFile(fs, "/config.yaml", []byte(yamlContent), 0o644))
+
+	global := newTestGlobal()
+
+	result, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
+		FSys:           fs,
+		Args:           []string{"/data"},
+		DashAt:         -1,
+		ConfigPath:     "/config.yaml",
+		CommandOptions: newTestCreateOptions(),
+		GlobalOptions:  global,
+		ExtractSection: func(cfg *configFile) *configFileCreate { return cfg.Create },
+		VisitFlags:     noVisitFlags,
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.Equal(t, ".par2cronignore", global.ignoreNames.File)
+	require.Equal(t, ".par2cronignore-all", global.ignoreNames.AllFile)
+}
+
+// Expectation: The manifest and lock naming scheme from the config file should be merged into the global options.
+func Test_runPrelude_ConfigSidecarNames_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	yamlContent := `create:
+  manifest-suffix: .meta
+  lock-suffix: .lck
+  hidden-sidecars: true
+`
+	require.NoError(t, afero.WriteFile(fs, "/config.yaml", []byte(yamlContent), 0o644))
+
+	global := newTestGlobal()
+
+	result, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
+		FSys:           fs,
+		Args:           []string{"/data"},
+		DashAt:         -1,
+		ConfigPath:     "/config.yaml",
+		CommandOptions: newTestCreateOptions(),
+		GlobalOptions:  global,
+		ExtractSection: func(cfg *configFile) *configFileCreate { return cfg.Create },
+		VisitFlags:     noVisitFlags,
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.Equal(t, util.SidecarNames{ManifestSuffix: ".meta", LockSuffix: ".lck", Hidden: true}, global.sidecarNames)
+}
+
+// Expectation: An invalid manifest suffix should fail the prelude.
+func Test_runPrelude_InvalidSidecarNames_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	global := newTestGlobal()
+	global.sidecarNames.ManifestSuffix = global.sidecarNames.LockSuffix
+
+	result, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
+		FSys:           fs,
+		Args:           []string{"/data"},
+		DashAt:         -1,
+		CommandOptions: newTestCreateOptions(),
+		GlobalOptions:  global,
+		ExtractSection: func(cfg *configFile) *configFileCreate { return cfg.Create },
+		VisitFlags:     noVisitFlags,
+	})
+
+	require.ErrorContains(t, err, "manifest-suffix")
+	require.Nil(t, result)
+}
+
 // Expectation: Passing a config with recursive mode and a shallow glob should pass validation.
 func Test_runPrelude_ConfigRecursiveShallowGlob_Success(t *testing.T) {
 	t.Parallel()
@@ -845,6 +1287,7 @@ func Test_runPrelude_VerifyType_Success(t *testing.T) {
 
 	yamlContent := `verify:
   args: ["-B"]
+  allowed-args: ["-B"]
   include-external: true`
 	require.NoError(t, afero.WriteFile(fs, "/par2cron.yaml", []byte(yamlContent), 0o644))
 
@@ -870,6 +1313,35 @@ func Test_runPrelude_VerifyType_Success(t *testing.T) {
 	require.True(t, opts.IncludeExternal)
 }
 
+// Expectation: A par2-quiet from the config should be refused alongside a verbosity in the par2 arguments.
+func Test_runPrelude_VerifyConfigPar2Quiet_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	yamlContent := `verify:
+  par2-quiet: true`
+	require.NoError(t, afero.WriteFile(fs, "/par2cron.yaml", []byte(yamlContent), 0o644))
+
+	opts := &verify.Options{}
+
+	result, err := runPrelude(&preludeInput[*verify.Options, *configFileVerify]{
+		FSys:           fs,
+		Args:           []string{"/data", "-v"},
+		DashAt:         1,
+		ConfigPath:     "/par2cron.yaml",
+		CommandOptions: opts,
+		GlobalOptions:  newTestGlobal(),
+		ExtractSection: func(cfg *configFile) *configFileVerify { return cfg.Verify },
+		VisitFlags:     noVisitFlags,
+	})
+
+	require.ErrorIs(t, err, schema.ErrPar2Verbosity)
+	require.Nil(t, result)
+	require.True(t, opts.Par2Quiet)
+}
+
 // Expectation: Verify with config having calc-run-interval should merge correctly.
 func Test_runPrelude_VerifyConfigRunInterval_Success(t *testing.T) {
 	t.Parallel()
@@ -911,6 +1383,7 @@ func Test_runPrelude_RepairType_Success(t *testing.T) {
 
 	yamlContent := `repair:
   args: ["-C"]
+  allowed-args: ["-C"]
   purge-backups: true`
 	require.NoError(t, afero.WriteFile(fs, "/par2cron.yaml", []byte(yamlContent), 0o644))
 
@@ -1056,6 +1529,7 @@ func Test_resolvePathArgs_PathNotExist_Error(t *testing.T) {
 
 	require.Error(t, err)
 	require.ErrorContains(t, err, "failed to access root directory")
+	require.ErrorIs(t, err, schema.ErrExitMissingData)
 	require.Nil(t, resolved)
 }
 
@@ -1143,3 +1617,69 @@ func Test_resolvePathArgs_NestedDir_Success(t *testing.T) {
 	require.Len(t, resolved, 1)
 	require.Equal(t, "/data/subdir/deep", resolved[0])
 }
+
+// Expectation: The no-recurse option should resolve to a maximum depth of 0.
+func Test_runPrelude_NoRecurse_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	global := newTestGlobal()
+	global.noRecurse = true
+
+	result, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
+		FSys:           fs,
+		Args:           []string{"/data"},
+		DashAt:         -1,
+		CommandOptions: newTestCreateOptions(),
+		GlobalOptions:  global,
+		ExtractSection: func(cfg *configFile) *configFileCreate { return cfg.Create },
+		VisitFlags:     noVisitFlags,
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.False(t, global.maxDepth.Unlimited())
+	require.Equal(t, "0", global.maxDepth.String())
+}
+
+// Expectation: The no-recurse option should not be combinable with an explicit maximum depth.
+func Test_runPrelude_NoRecurseWithMaxDepth_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	global := newTestGlobal()
+	global.noRecurse = true
+	require.NoError(t, global.maxDepth.Set("2"))
+
+	result, err := runPrelude(&preludeInput[*create.Options, *configFileCreate]{
+		FSys:           fs,
+		Args:           []string{"/data"},
+		DashAt:         -1,
+		CommandOptions: newTestCreateOptions(),
+		GlobalOptions:  global,
+		ExtractSection: func(cfg *configFile) *configFileCreate { return cfg.Create },
+		VisitFlags: func(fn func(*pflag.Flag)) {
+			fn(&pflag.Flag{Name: "max-depth"})
+		},
+	})
+
+	require.Error(t, err)
+	require.ErrorContains(t, err, "--no-recurse cannot be combined with --max-depth")
+	require.Nil(t, result)
+}
+
+// Expectation: The paths given to --force should be resolved to absolute paths, without needing to exist.
+func Test_resolveForcePaths_Success(t *testing.T) {
+	t.Parallel()
+
+	resolved, err := resolveForcePaths([]string{"/data/missing.par2", "relative"})
+
+	require.NoError(t, err)
+	require.Len(t, resolved, 2)
+	require.Equal(t, "/data/missing.par2", resolved[0])
+	require.True(t, filepath.IsAbs(resolved[1]))
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+)
+
+// maxSummaryIssues is the maximum of issues listed within a summary file.
+const maxSummaryIssues = 5
+
+// writeSummaryFile atomically writes a human-readable summary of the operation
+// to the --summary-file (if set), failure to do so is logged but not returned.
+func writeSummaryFile(fsys afero.Fs, path string, op string, err error, result util.ResultTracker, log *logging.Logger) {
+	if path == "" {
+		return
+	}
+
+	data := formatSummary(op, time.Now(), err, result)
+	if werr := util.WriteFileAtomic(fsys, path, data); werr != nil {
+		log.Warn("Failed to write summary file", "path", path, "error", werr)
+	}
+}
+
+func formatSummary(op string, now time.Time, err error, result util.ResultTracker) []byte {
+	processedCount := result.Success + result.Error + result.Skipped
+
+	var status string
+	switch {
+	case err == nil && result.Error == 0:
+		status = "completed"
+	case errors.Is(err, context.Canceled):
+		status = "interrupted"
+	default:
+		status = "completed with errors"
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "par2cron %s: %s\n", op, status)
+	fmt.Fprintf(&b, "Time: %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Jobs: %d/%d processed (%d success, %d skipped, %d error)\n",
+		processedCount, result.Selected, result.Success, result.Skipped, result.Error)
+
+	if result.Filtered != nil && result.Selected == 0 {
+		if total := result.Filtered.Total(); total > 0 {
+			fmt.Fprintf(&b, "Filtered: all %d candidates (%s)\n", total, result.Filtered)
+		} else {
+			b.WriteString("Filtered: none (no PAR2 sets found)\n")
+		}
+	}
+
+	if result.Timings != nil {
+		phases := result.Timings.Phases()
+		fmt.Fprintf(&b, "Timing: %s total (walk %s, parse %s, par2 %s)\n",
+			phases.Total.Round(time.Millisecond), phases.Walk.Round(time.Millisecond),
+			phases.Parse.Round(time.Millisecond), phases.Par2.Round(time.Millisecond))
+	}
+
+	if err != nil {
+		issues := summaryIssues(err)
+
+		if len(issues) > maxSummaryIssues {
+			fmt.Fprintf(&b, "Issues (%d of %d):\n", maxSummaryIssues, len(issues))
+			issues = issues[:maxSummaryIssues]
+		} else {
+			fmt.Fprintf(&b, "Issues (%d):\n", len(issues))
+		}
+
+		for _, issue := range issues {
+			fmt.Fprintf(&b, "  - %s\n", strings.ReplaceAll(issue, "\n", " "))
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// summaryIssues returns the individual (per-job) errors that were joined
+// within the error, or otherwise the error itself as the only issue.
+func summaryIssues(err error) []string {
+	if joined := findJoined(err); joined != nil {
+		issues := make([]string, 0, len(joined))
+		for _, e := range joined {
+			issues = append(issues, e.Error())
+		}
+
+		return issues
+	}
+
+	return []string{err.Error()}
+}
+
+// findJoined descends the error tree and returns the errors of the first
+// errors.Join it encounters, telling it apart from fmt.Errorf with multiple
+// %w verbs by the message being exactly the newline-joined messages.
+func findJoined(err error) []error {
+	switch e := err.(type) { //nolint:errorlint
+	case interface{ Unwrap() []error }:
+		errs := e.Unwrap()
+
+		msgs := make([]string, 0, len(errs))
+		for _, sub := range errs {
+			msgs = append(msgs, sub.Error())
+		}
+		if err.Error() == strings.Join(msgs, "\n") {
+			return errs
+		}
+
+		for _, sub := range errs {
+			if joined := findJoined(sub); joined != nil {
+				return joined
+			}
+		}
+
+	case interface{ Unwrap() error }:
+		if sub := e.Unwrap(); sub != nil {
+			return findJoined(sub)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: A successful run should be summarized without any issues.
+func Test_formatSummary_Completed_Success(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	result := util.ResultTracker{Selected: 4, Success: 3, Skipped: 1}
+
+	require.Equal(t, "par2cron verify: completed\n"+
+		"Time: 2026-01-02T03:04:05Z\n"+
+		"Jobs: 4/4 processed (3 success, 1 skipped, 0 error)\n",
+		string(formatSummary("verify", now, nil, result)))
+}
+
+// Expectation: A run tracking the phases of its elapsed time should be summarized with these.
+func Test_formatSummary_Timings_Success(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	timings := util.NewTimings()
+	timings.Add(util.PhaseParse, 1500*time.Millisecond)
+	timings.Add(util.PhasePar2, time.Minute)
+	result := util.ResultTracker{Selected: 1, Success: 1, Timings: timings}
+
+	summary := string(formatSummary("verify", now, nil, result))
+	require.Contains(t, summary, "Jobs: 1/1 processed (1 success, 0 skipped, 0 error)\nTiming: ")
+	require.Contains(t, summary, "(walk 0s, parse 1.5s, par2 1m0s)\n")
+}
+
+// Expectation: A run with nothing selected should tell an entirely filtered tree apart from an empty one.
+func Test_formatSummary_Filtered_Success(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	filtered := util.NewFiltered()
+	summary := string(formatSummary("verify", now, nil, util.ResultTracker{Filtered: filtered}))
+	require.Contains(t, summary, "Jobs: 0/0 processed (0 success, 0 skipped, 0 error)\nFiltered: none (no PAR2 sets found)\n")
+
+	filtered.AddN(schema.ReasonNotDue, 2)
+	filtered.Add(schema.ReasonSkipNotCreated)
+	summary = string(formatSummary("verify", now, nil, util.ResultTracker{Filtered: filtered}))
+	require.Contains(t, summary, "Filtered: all 3 candidates (not_due 2, skip_not_created 1)\n")
+
+	summary = string(formatSummary("verify", now, nil, util.ResultTracker{Selected: 1, Success: 1, Filtered: filtered}))
+	require.NotContains(t, summary, "Filtered:")
+}
+
+// Expectation: A partially failed run should list the joined per-job errors as issues.
+func Test_formatSummary_PartialFailure_Success(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	result := util.ResultTracker{Selected: 3, Success: 1, Error: 2}
+	err := fmt.Errorf("%w: %w", schema.ErrExitPartialFailure, errors.Join(
+		errors.New("/data/a.par2: repair needed"),
+		errors.New("/data/b.par2: unrepairable"),
+	))
+
+	require.Equal(t, "par2cron verify: completed with errors\n"+
+		"Time: 2026-01-02T03:04:05Z\n"+
+		"Jobs: 3/3 processed (1 success, 0 skipped, 2 error)\n"+
+		"Issues (2):\n"+
+		"  - /data/a.par2: repair needed\n"+
+		"  - /data/b.par2: unrepairable\n",
+		string(formatSummary("verify", now, err, result)))
+}
+
+// Expectation: Only the top issues should be listed, and an interruption should be summarized as such.
+func Test_formatSummary_Table(t *testing.T) {
+	t.Parallel()
+
+	errs := make([]error, 0, maxSummaryIssues+2)
+	for i := range maxSummaryIssues + 2 {
+		errs = append(errs, fmt.Errorf("/data/%d.par2: failure", i))
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{
+			"top issues",
+			fmt.Errorf("%w: %w", schema.ErrExitPartialFailure, errors.Join(errs...)),
+			[]string{"completed with errors", fmt.Sprintf("Issues (%d of %d):", maxSummaryIssues, len(errs)), "/data/4.par2", "\n"},
+		},
+		{
+			"interrupted",
+			fmt.Errorf("context error: %w", context.Canceled),
+			[]string{"par2cron create: interrupted", "Issues (1):\n  - context error: context canceled\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		got := string(formatSummary("create", time.Now(), tt.err, util.ResultTracker{}))
+		for _, want := range tt.want {
+			require.Contains(t, got, want, tt.name)
+		}
+		require.NotContains(t, got, "/data/5.par2", tt.name)
+	}
+}
+
+// Expectation: The summary file should be written with the run outcome, or not at all without a path.
+func Test_writeSummaryFile_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/status", 0o755))
+
+	log := logging.NewLogger(logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard})
+	result := util.ResultTracker{Selected: 2, Success: 1, Error: 1}
+	err := fmt.Errorf("%w: %w", schema.ErrExitPartialFailure, errors.Join(errors.New("/data/a.par2: failure")))
+
+	writeSummaryFile(fs, "", "repair", err, result, log)
+
+	entries, rerr := afero.ReadDir(fs, "/status")
+	require.NoError(t, rerr)
+	require.Empty(t, entries)
+
+	writeSummaryFile(fs, "/status/summary.txt", "repair", err, result, log)
+
+	data, rerr := afero.ReadFile(fs, "/status/summary.txt")
+	require.NoError(t, rerr)
+	require.Contains(t, string(data), "par2cron repair: completed with errors\n")
+	require.Contains(t, string(data), "Jobs: 2/2 processed (1 success, 0 skipped, 1 error)\n")
+	require.Contains(t, string(data), "  - /data/a.par2: failure\n")
+}
+
+// Expectation: A failure to write the summary file should only be logged.
+func Test_writeSummaryFile_Error(t *testing.T) {
+	t.Parallel()
+
+	var logBuf testutil.SafeBuffer
+	log := logging.NewLogger(logging.Options{Logout: &logBuf, Stdout: io.Discard, Stderr: io.Discard})
+
+	fs := afero.NewReadOnlyFs(afero.NewMemMapFs())
+	writeSummaryFile(fs, "/status/summary.txt", "create", nil, util.ResultTracker{}, log)
+
+	require.Contains(t, logBuf.String(), "Failed to write summary file")
+}
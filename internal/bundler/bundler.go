@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/desertwitch/par2cron/internal/bundle"
+	"github.com/desertwitch/par2cron/internal/flags"
 	"github.com/desertwitch/par2cron/internal/logging"
 	"github.com/desertwitch/par2cron/internal/schema"
 	"github.com/desertwitch/par2cron/internal/util"
@@ -20,6 +21,10 @@ type Options struct {
 	Force           bool
 	IncludeExternal bool
 	SkipNotCreated  bool
+	IgnoreNames     util.IgnoreNames
+	SidecarNames    util.SidecarNames
+	MaxDepth        flags.MaxDepth
+	MaxManifestSize int64
 }
 
 type Service struct {
@@ -54,6 +59,7 @@ type Job struct {
 	manifestName string
 	manifestPath string
 	lockPath     string
+	lockSuffix   string
 	workingDir   string
 
 	force    bool
@@ -69,9 +75,9 @@ func NewJob(par2Path string, opts Options, mf *schema.Manifest, isBundle bool) *
 	bj.par2Path = par2Path
 
 	if !isBundle {
-		bj.manifestName = bj.par2Name + schema.ManifestExtension
-		bj.manifestPath = bj.par2Path + schema.ManifestExtension
-		bj.lockPath = bj.par2Path + schema.LockExtension
+		bj.manifestName = opts.SidecarNames.ManifestName(bj.par2Name)
+		bj.manifestPath = opts.SidecarNames.ManifestPath(bj.par2Path)
+		bj.lockPath = opts.SidecarNames.LockPath(bj.par2Path)
 	} else {
 		bj.manifestName = bj.par2Name
 		bj.manifestPath = bj.par2Path
@@ -79,6 +85,7 @@ func NewJob(par2Path string, opts Options, mf *schema.Manifest, isBundle bool) *
 	}
 
 	bj.force = opts.Force
+	bj.lockSuffix = opts.SidecarNames.LockSuffixName()
 	bj.isBundle = isBundle
 	bj.manifest = mf
 
@@ -238,7 +245,7 @@ func (prog *Service) processMode(ctx context.Context, rootDirs []string, opts Op
 
 func (prog *Service) packEnumerate(ctx context.Context, rootDir string, opts Options) ([]*Job, error) {
 	jobs := []*Job{}
-	checker := util.NewIgnoreChecker(prog.fsys, rootDir)
+	checker := util.NewIgnoreChecker(prog.fsys, rootDir, opts.IgnoreNames)
 
 	var partialErrors int
 	err := prog.walker.WalkDir(rootDir, func(par2path string, d fs.DirEntry, err error) error {
@@ -252,6 +259,12 @@ func (prog *Service) packEnumerate(ctx context.Context, rootDir string, opts Opt
 			return nil
 		}
 
+		if d.IsDir() && util.ExceedsMaxDepth(rootDir, par2path, opts.MaxDepth) {
+			logger := prog.bundleLogger(ctx, nil, par2path)
+			logger.Debug("A directory was skipped due to the maximum depth")
+
+			return fs.SkipDir
+		}
 		if d.IsDir() || !util.IsPar2Index(d.Name()) {
 			return nil
 		} // --- End of Hot Path ---
@@ -292,7 +305,7 @@ func (prog *Service) packEnumerate(ctx context.Context, rootDir string, opts Opt
 }
 
 func (prog *Service) packProcessManifest(ctx context.Context, par2path string, opts Options) (*Job, error) {
-	manifestPath := par2path + schema.ManifestExtension
+	manifestPath := opts.SidecarNames.ManifestPath(par2path)
 
 	if _, err := util.LstatIfPossible(prog.fsys, manifestPath); err != nil {
 		if !opts.IncludeExternal {
@@ -310,7 +323,7 @@ func (prog *Service) packProcessManifest(ctx context.Context, par2path string, o
 		return job, nil
 	}
 
-	unlock, err := util.AcquireLock(prog.fsys, par2path+schema.LockExtension, false)
+	unlock, err := util.AcquireLock(prog.fsys, opts.SidecarNames.LockPath(par2path), false)
 	if err != nil {
 		if errors.Is(err, schema.ErrFileIsLocked) {
 			logger := prog.bundleLogger(ctx, nil, manifestPath)
@@ -331,8 +344,8 @@ func (prog *Service) packProcessManifest(ctx context.Context, par2path string, o
 	}
 	unlock()
 
-	mf := &schema.Manifest{}
-	if err := json.Unmarshal(data, mf); err != nil {
+	mf, err := util.UnmarshalManifest(data, opts.MaxManifestSize)
+	if err != nil {
 		if opts.SkipNotCreated {
 			logger := prog.bundleLogger(ctx, nil, manifestPath)
 			logger.Debug("No unmarshalable manifest (skipping; --skip-not-created)")
@@ -399,7 +412,7 @@ func (prog *Service) packBundle(ctx context.Context, job *Job) error {
 		}
 	}
 
-	manifestData, err := json.MarshalIndent(job.manifest, "", "  ")
+	manifestData, err := util.MarshalManifest(job.manifest)
 	if err != nil {
 		return fmt.Errorf("failed to marshal manifest: %w", err)
 	}
@@ -432,7 +445,7 @@ func (prog *Service) packBundle(ctx context.Context, job *Job) error {
 
 func (prog *Service) unpackEnumerate(ctx context.Context, rootDir string, opts Options) ([]*Job, error) {
 	jobs := []*Job{}
-	checker := util.NewIgnoreChecker(prog.fsys, rootDir)
+	checker := util.NewIgnoreChecker(prog.fsys, rootDir, opts.IgnoreNames)
 
 	err := prog.walker.WalkDir(rootDir, func(par2path string, d fs.DirEntry, err error) error {
 		if err := ctx.Err(); err != nil {
@@ -445,6 +458,12 @@ func (prog *Service) unpackEnumerate(ctx context.Context, rootDir string, opts O
 			return nil
 		}
 
+		if d.IsDir() && util.ExceedsMaxDepth(rootDir, par2path, opts.MaxDepth) {
+			logger := prog.bundleLogger(ctx, nil, par2path)
+			logger.Debug("A directory was skipped due to the maximum depth")
+
+			return fs.SkipDir
+		}
 		if d.IsDir() || !util.IsPar2Index(d.Name()) {
 			return nil
 		} // --- End of Hot Path ---
@@ -512,7 +531,7 @@ func (prog *Service) unpackBundle(ctx context.Context, job *Job) error {
 	lockFile := filepath.Join(job.workingDir, strings.TrimSuffix(
 		manifestName,
 		filepath.Ext(manifestName),
-	)+schema.LockExtension)
+	)+job.lockSuffix)
 	unlock2, err := util.AcquireLock(prog.fsys, lockFile, false)
 	if err != nil {
 		if !errors.Is(err, schema.ErrFileIsLocked) {
@@ -921,6 +921,10 @@ func Test_Service_packBundle_Success(t *testing.T) {
 			require.NotEmpty(t, manifest.Bytes)
 			require.NotEmpty(t, files)
 
+			var mf schema.Manifest
+			require.NoError(t, json.Unmarshal(manifest.Bytes, &mf))
+			require.Equal(t, schema.ProgramVersion, mf.ProgramVersion)
+
 			require.NoError(t, afero.WriteFile(fsys, bundlePath, []byte("bundledata"), 0o644))
 
 			return nil
@@ -18,7 +18,7 @@ import (
 )
 
 const (
-	GobCacheVersion   = 1
+	GobCacheVersion   = 5
 	GobCacheExtension = ".gob.zst"
 )
 
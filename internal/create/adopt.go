@@ -0,0 +1,136 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+)
+
+// adoptExisting writes a par2cron manifest for an existing (external) PAR2
+// set at the job's PAR2 path, reconstructing the creation record from the
+// parsed set. It returns false if there is no such set to adopt (missing,
+// not a regular file or already having a par2cron manifest).
+func (prog *Service) adoptExisting(ctx context.Context, job *Job) (bool, error) {
+	fi, err := util.LstatIfPossible(prog.fsys, job.par2Path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to stat: %w", err)
+	}
+	if !fi.Mode().IsRegular() {
+		return false, nil
+	}
+
+	if _, err := util.LstatIfPossible(prog.fsys, job.manifestPath); err == nil {
+		return false, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("failed to stat manifest: %w", err)
+	}
+
+	unlock, err := util.AcquireLock(prog.fsys, job.lockPath, false)
+	if err != nil {
+		logger := prog.creationLogger(ctx, job, job.lockPath)
+
+		if errors.Is(err, schema.ErrFileIsLocked) {
+			logger.Warn("File is locked by another instance (will retry next run)", "error", err)
+		} else {
+			logger.Error("Failed to lock before PAR2 adoption (will retry next run)", "error", err)
+		}
+
+		return false, fmt.Errorf("failed to lock: %w", err)
+	}
+	defer unlock()
+
+	mf, err := prog.adoptionManifest(ctx, job, fi.ModTime())
+	if err != nil {
+		logger := prog.creationLogger(ctx, job, job.par2Path)
+		logger.Error("Failed to parse existing PAR2 for adoption (will retry next run)", "error", err)
+
+		return false, err
+	}
+
+	if sha256hash, err := util.HashFile(prog.fsys, job.par2Path); err != nil {
+		logger := prog.creationLogger(ctx, job, job.par2Path)
+		logger.Warn("Failed to hash PAR2 for par2cron manifest (will retry on verify)", "error", err)
+	} else {
+		mf.SHA256 = sha256hash
+	}
+
+	if err := util.WriteManifest(ctx, prog.fsys, prog.bundler, job.manifestPath, mf, false); err != nil {
+		logger := prog.creationLogger(ctx, job, job.manifestPath)
+		logger.Error("Failed to write par2cron manifest (will retry next run)", "error", err)
+
+		return false, fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	logger := prog.creationLogger(ctx, job, job.par2Path)
+	logger.Info("Adopted existing PAR2 into par2cron management",
+		"elements", len(mf.Creation.Elements))
+
+	return true, nil
+}
+
+// adoptionManifest parses the PAR2 set at the job's PAR2 path and returns
+// a manifest with a creation record of its protected files. As the original
+// arguments are unknown, these are left empty and the creation time is taken
+// from the modification time of the PAR2 index file.
+func (prog *Service) adoptionManifest(ctx context.Context, job *Job, modTime time.Time) (*schema.Manifest, error) {
+	p, err := prog.par2er.ParseFile(ctx, prog.fsys, job.par2Path, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse: %w", err)
+	}
+
+	seen := make(map[string]struct{})
+	elements := []schema.FsElement{}
+	for _, set := range p.Sets {
+		for _, fp := range set.RecoverySet {
+			name := filepath.FromSlash(fp.Name)
+			if _, ok := seen[name]; ok || name == "" {
+				continue
+			}
+			seen[name] = struct{}{}
+
+			elem := schema.FsElement{
+				Path: filepath.Join(job.workingDir, name),
+				Name: name,
+				Size: fp.Size,
+			}
+			if fi, err := util.LstatIfPossible(prog.fsys, elem.Path); err == nil {
+				elem.Mode = fi.Mode()
+				elem.ModTime = fi.ModTime()
+				if job.recordMetadata {
+					elem.Owner = util.FileOwner(fi)
+				}
+			}
+
+			elements = append(elements, elem)
+		}
+	}
+
+	if len(elements) == 0 {
+		return nil, errNothingToAdopt
+	}
+
+	mf := schema.NewManifest(job.par2Name)
+	mf.Creation = schema.NewCreationManifest()
+	mf.Creation.Time = modTime
+	mf.Creation.Mode = job.par2Mode
+	mf.Creation.Elements = elements
+	mf.Creation.VerifyInterval = job.verifyInterval
+	mf.Creation.Tags = slices.Clone(job.tags)
+	mf.Creation.Marker = job.marker
+	if job.folderFingerprint && job.par2Mode == schema.CreateFolderMode {
+		mf.Creation.FolderFingerprint = util.FolderFingerprint(elements)
+	}
+
+	return mf, nil
+}
@@ -0,0 +1,215 @@
+package create
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func newAdoptTestJob(adopt bool) *Job {
+	return &Job{
+		workingDir:    "/data/folder",
+		markerPath:    "/data/folder/_par2cron",
+		par2Mode:      schema.CreateFolderMode,
+		par2Name:      "test" + schema.Par2Extension,
+		par2Path:      "/data/folder/test" + schema.Par2Extension,
+		par2Args:      []string{"-r10"},
+		par2Glob:      "*",
+		lockPath:      "/data/folder/test" + schema.Par2Extension + schema.LockExtension,
+		manifestName:  "test" + schema.Par2Extension + schema.ManifestExtension,
+		manifestPath:  "/data/folder/test" + schema.Par2Extension + schema.ManifestExtension,
+		adoptExisting: adopt,
+	}
+}
+
+func newAdoptTestFs(t *testing.T) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder/sub", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/file.txt", []byte("content"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/sub/other.txt", []byte("other"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/test"+schema.Par2Extension, []byte("existing"), 0o644))
+
+	return fs
+}
+
+func newAdoptTestParser(err error) *testutil.MockPar2Handler {
+	return &testutil.MockPar2Handler{
+		ParseFileFunc: func(fsys afero.Fs, path string, panicAsErr bool) (*par2.File, error) {
+			if err != nil {
+				return nil, err
+			}
+
+			return &par2.File{Sets: []par2.Set{{
+				RecoverySet: []par2.FilePacket{
+					{Name: "file.txt", Size: 7},
+					{Name: "sub/other.txt", Size: 5},
+					{Name: "file.txt", Size: 7},
+				},
+			}}}, nil
+		},
+	}
+}
+
+// Expectation: An existing PAR2 set should be adopted with a reconstructed creation record.
+func Test_Service_createCombined_AdoptExisting_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := newAdoptTestFs(t)
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, newAdoptTestParser(nil), &testutil.MockCacheHandler{})
+	job := newAdoptTestJob(true)
+
+	require.NoError(t, prog.createCombined(t.Context(), job, []schema.FsElement{{Path: "/data/folder/file.txt", Name: "file.txt"}}))
+	require.Zero(t, called)
+	require.Contains(t, logBuf.String(), "Adopted existing PAR2 into par2cron management")
+	require.NotContains(t, logBuf.String(), "Same-named PAR2 already exists")
+
+	data, err := afero.ReadFile(fs, job.manifestPath)
+	require.NoError(t, err)
+
+	var mf schema.Manifest
+	require.NoError(t, json.Unmarshal(data, &mf))
+
+	fi, err := fs.Stat(job.par2Path)
+	require.NoError(t, err)
+
+	require.Equal(t, job.par2Name, mf.Name)
+	require.NotEmpty(t, mf.SHA256)
+	require.NotNil(t, mf.Creation)
+	require.Equal(t, schema.CreateFolderMode, mf.Creation.Mode)
+	require.Empty(t, mf.Creation.Args)
+	require.True(t, fi.ModTime().Equal(mf.Creation.Time))
+	require.Len(t, mf.Creation.Elements, 2)
+	require.Equal(t, "file.txt", mf.Creation.Elements[0].Name)
+	require.EqualValues(t, 7, mf.Creation.Elements[0].Size)
+	require.Equal(t, "sub/other.txt", mf.Creation.Elements[1].Name)
+	require.False(t, mf.Creation.Elements[1].ModTime.IsZero())
+}
+
+// Expectation: A PAR2 set already having a par2cron manifest should not be adopted.
+func Test_Service_createCombined_AdoptExisting_HasManifest_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := newAdoptTestFs(t)
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/test"+schema.Par2Extension+schema.ManifestExtension, []byte("{}"), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, newAdoptTestParser(nil), &testutil.MockCacheHandler{})
+	job := newAdoptTestJob(true)
+
+	require.NoError(t, prog.createCombined(t.Context(), job, []schema.FsElement{{Path: "/data/folder/file.txt", Name: "file.txt"}}))
+	require.Contains(t, logBuf.String(), "Same-named PAR2 already exists in folder")
+	require.NotContains(t, logBuf.String(), "Adopted existing PAR2")
+
+	data, err := afero.ReadFile(fs, job.manifestPath)
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(data))
+}
+
+// Expectation: Without adoption enabled, an existing PAR2 set should only be warned about.
+func Test_Service_createCombined_AdoptExisting_Disabled_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := newAdoptTestFs(t)
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, newAdoptTestParser(nil), &testutil.MockCacheHandler{})
+	job := newAdoptTestJob(false)
+
+	require.NoError(t, prog.createCombined(t.Context(), job, []schema.FsElement{{Path: "/data/folder/file.txt", Name: "file.txt"}}))
+	require.Contains(t, logBuf.String(), "Same-named PAR2 already exists in folder")
+
+	_, err := fs.Stat(job.manifestPath)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// Expectation: An unparsable PAR2 set should fail the job without writing a manifest.
+func Test_Service_createCombined_AdoptExisting_Parse_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := newAdoptTestFs(t)
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, newAdoptTestParser(errors.New("malformed packet")), &testutil.MockCacheHandler{})
+	job := newAdoptTestJob(true)
+
+	err := prog.createCombined(t.Context(), job, []schema.FsElement{{Path: "/data/folder/file.txt", Name: "file.txt"}})
+	require.ErrorContains(t, err, "malformed packet")
+	require.Contains(t, logBuf.String(), "Failed to parse existing PAR2 for adoption")
+
+	_, err = fs.Stat(job.manifestPath)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = fs.Stat(job.lockPath)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// Expectation: A PAR2 set without any protected files should not be adopted.
+func Test_Service_adoptExisting_NoFiles_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := newAdoptTestFs(t)
+	par2er := &testutil.MockPar2Handler{
+		ParseFileFunc: func(fsys afero.Fs, path string, panicAsErr bool) (*par2.File, error) {
+			return &par2.File{Sets: []par2.Set{{}}}, nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}),
+		&testutil.MockRunner{}, &util.BundleHandler{}, par2er, &testutil.MockCacheHandler{})
+
+	adopted, err := prog.adoptExisting(t.Context(), newAdoptTestJob(true))
+	require.ErrorIs(t, err, errNothingToAdopt)
+	require.False(t, adopted)
+}
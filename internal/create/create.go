@@ -1,8 +1,8 @@
 package create
 
 import (
+	"cmp"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -28,30 +28,60 @@ const (
 
 var (
 	errNoFilesToProtect  = errors.New("no files to protect")
+	errGlobMatchedNone   = errors.New("glob matched no files")
+	errNothingToAdopt    = errors.New("no protected files in par2")
 	errWrongModeArgument = errors.New("wrong mode for argument")
 
-	// https://github.com/bmatcuk/doublestar/blob/master/utils.go#L153
-	globMetaReplacer = strings.NewReplacer("*", "\\*", "?", "\\?", "[", "\\[", "]", "\\]", "{", "\\{", "}", "\\}")
-
-	_ schema.OptionsValidatable      = (*Options)(nil)
-	_ schema.OptionsPar2ArgsSettable = (*Options)(nil)
+	_ schema.OptionsValidatable       = (*Options)(nil)
+	_ schema.OptionsPar2ArgsSettable  = (*Options)(nil)
+	_ schema.OptionsPar2ArgsAllowable = (*Options)(nil)
 )
 
 type Options struct {
-	Par2Args    []string
-	Par2Glob    string
-	Par2Mode    flags.CreateMode
-	Par2Verify  bool
-	MaxDuration flags.Duration
-	HideFiles   bool
-	Bundle      bool
+	Par2Args                []string
+	AllowedPar2Args         []string
+	Par2Glob                string
+	Par2GlobExclude         []string
+	Par2Mode                flags.CreateMode
+	Par2Verify              bool
+	TargetBlockSize         flags.ByteSize
+	MaxSetSize              flags.ByteSize
+	MaxDuration             flags.Duration
+	Limit                   int
+	MaxErrors               int
+	HideFiles               bool
+	Bundle                  bool
+	ExcludeEmpty            bool
+	AdoptExisting           bool
+	StrictGlob              bool
+	WriteStamp              bool
+	StampFile               string
+	WriteFileList           bool
+	ProtectCreationManifest bool
+	RetrySingleThreaded     bool
+	RecordMetadata          bool
+	FolderFingerprint       bool
+	IgnoreNames             util.IgnoreNames
+	SidecarNames            util.SidecarNames
+	MaxDepth                flags.MaxDepth
+	MaxManifestSize         int64
+	PauseFile               string
 }
 
 func (o *Options) SetPar2Args(args []string) {
 	o.Par2Args = slices.Clone(args)
 }
 
+func (o *Options) SetAllowedPar2Args(allowed []string) {
+	o.AllowedPar2Args = slices.Clone(allowed)
+}
+
 func (o *Options) Validate() error {
+	if o.AllowedPar2Args != nil {
+		if err := util.CheckPar2Args(o.Par2Args, o.AllowedPar2Args); err != nil {
+			return fmt.Errorf("args: %w", err)
+		}
+	}
 	if ok := doublestar.ValidatePattern(o.Par2Glob); !ok {
 		return fmt.Errorf("glob: %w", doublestar.ErrBadPattern)
 	}
@@ -62,6 +92,25 @@ func (o *Options) Validate() error {
 		return schema.ErrUnsupportedGlob
 	}
 
+	if err := validateGlobExcludes(o.Par2GlobExclude, o.Par2Mode.Value); err != nil {
+		return err
+	}
+
+	if o.WriteStamp {
+		if err := util.ValidateStampFile(o.StampFile); err != nil {
+			return fmt.Errorf("stamp-file: %w", err)
+		}
+	}
+
+	if o.TargetBlockSize.Value > 0 {
+		if o.TargetBlockSize.Value < minTargetBlockSize {
+			return fmt.Errorf("target-block-size: must be at least %d bytes, got %d", minTargetBlockSize, o.TargetBlockSize.Value)
+		}
+		if util.HasPar2BlockArg(o.Par2Args) {
+			return errors.New("target-block-size: cannot be combined with par2 arguments -b or -s")
+		}
+	}
+
 	return nil
 }
 
@@ -116,7 +165,29 @@ type Job struct {
 	lockPath      string
 	manifestName  string
 	manifestPath  string
+	stampFile     string
+	stampPath     string
+	creationPath  string
+	fileListPath  string
 	asBundle      bool
+	excludeEmpty  bool
+	adoptExisting bool
+	strictGlob    bool
+	tags          []string
+	sidecarNames  util.SidecarNames
+
+	verifyInterval      time.Duration
+	targetBlockSize     int64
+	par2GlobExclude     []string
+	noAutoRepair        bool
+	retrySingleThreaded bool
+	recordMetadata      bool
+	folderFingerprint   bool
+	maxSetSize          int64
+	maxManifestSize     int64
+	subset              *schema.CreationSubset
+	fingerprint         string // of the entire folder, for a subset
+	marker              *schema.CreationMarker
 }
 
 func NewJob(markerPath string, cfg MarkerConfig) *Job {
@@ -129,18 +200,54 @@ func NewJob(markerPath string, cfg MarkerConfig) *Job {
 	cj.hiddenFiles = *cfg.HideFiles
 	cj.markerPersist = *cfg.PersistMarker
 	cj.asBundle = *cfg.Bundle
+	if cfg.ExcludeEmpty != nil {
+		cj.excludeEmpty = *cfg.ExcludeEmpty
+	}
+	if cfg.AdoptExisting != nil {
+		cj.adoptExisting = *cfg.AdoptExisting
+	}
 
 	cj.par2Mode = cfg.Par2Mode.Value
 	cj.par2Args = slices.Clone(*cfg.Par2Args)
 	cj.par2Glob = *cfg.Par2Glob
+	if cfg.Par2GlobExclude != nil {
+		cj.par2GlobExclude = slices.Clone(*cfg.Par2GlobExclude)
+	}
 	cj.par2Verify = *cfg.Par2Verify
+	if cfg.VerifyInterval != nil {
+		cj.verifyInterval = cfg.VerifyInterval.Value
+	}
+	if cfg.NoAutoRepair != nil {
+		cj.noAutoRepair = *cfg.NoAutoRepair
+	}
+	cj.targetBlockSize = cfg.TargetBlockSize
+	cj.maxSetSize = cfg.MaxSetSize
+	cj.maxManifestSize = cfg.MaxManifestSize
+	cj.retrySingleThreaded = cfg.RetrySingleThreaded
+	cj.recordMetadata = cfg.RecordMetadata
+	cj.folderFingerprint = cfg.FolderFingerprint
+	if cfg.Tags != nil {
+		cj.tags = slices.Compact(slices.Sorted(slices.Values(*cfg.Tags)))
+	}
+	cj.marker = cfg.Marker
 
+	cj.sidecarNames = cfg.SidecarNames
 	cj.markerPath = markerPath
 	cj.workingDir = filepath.Dir(markerPath)
 	cj.par2Path = filepath.Join(cj.workingDir, cj.par2Name)
-	cj.lockPath = cj.par2Path + schema.LockExtension
-	cj.manifestName = cj.par2Name + schema.ManifestExtension
-	cj.manifestPath = cj.par2Path + schema.ManifestExtension
+	cj.lockPath = cj.sidecarNames.LockPath(cj.par2Path)
+	cj.manifestName = cj.sidecarNames.ManifestName(cj.par2Name)
+	cj.manifestPath = cj.sidecarNames.ManifestPath(cj.par2Path)
+	if cfg.StampFile != "" {
+		cj.stampFile = cfg.StampFile
+		cj.stampPath = filepath.Join(cj.workingDir, cj.stampFile)
+	}
+	if cfg.FileList {
+		cj.fileListPath = cj.par2Path + schema.FileListExtension
+	}
+	if cfg.ProtectCreationManifest != nil && *cfg.ProtectCreationManifest && cj.par2Mode == schema.CreateFolderMode {
+		cj.creationPath = cj.par2Path + schema.CreationManifestExtension
+	}
 
 	return cj
 }
@@ -155,9 +262,15 @@ func newFileModeJob(job Job, path string) Job {
 
 	job.workingDir = filepath.Dir(path)
 	job.par2Path = filepath.Join(job.workingDir, job.par2Name)
-	job.manifestName = job.par2Name + schema.ManifestExtension
-	job.manifestPath = job.par2Path + schema.ManifestExtension
-	job.lockPath = job.par2Path + schema.LockExtension
+	job.manifestName = job.sidecarNames.ManifestName(job.par2Name)
+	job.manifestPath = job.sidecarNames.ManifestPath(job.par2Path)
+	job.lockPath = job.sidecarNames.LockPath(job.par2Path)
+	if job.stampFile != "" {
+		job.stampPath = filepath.Join(job.workingDir, job.stampFile)
+	}
+	if job.fileListPath != "" {
+		job.fileListPath = job.par2Path + schema.FileListExtension
+	}
 
 	return job
 }
@@ -172,9 +285,15 @@ func newNestedModeJob(job Job, dir string) Job {
 
 	job.workingDir = dir
 	job.par2Path = filepath.Join(job.workingDir, job.par2Name)
-	job.manifestName = job.par2Name + schema.ManifestExtension
-	job.manifestPath = job.par2Path + schema.ManifestExtension
-	job.lockPath = job.par2Path + schema.LockExtension
+	job.manifestName = job.sidecarNames.ManifestName(job.par2Name)
+	job.manifestPath = job.sidecarNames.ManifestPath(job.par2Path)
+	job.lockPath = job.sidecarNames.LockPath(job.par2Path)
+	if job.stampFile != "" {
+		job.stampPath = filepath.Join(job.workingDir, job.stampFile)
+	}
+	if job.fileListPath != "" {
+		job.fileListPath = job.par2Path + schema.FileListExtension
+	}
 
 	return job
 }
@@ -184,12 +303,25 @@ func (prog *Service) Create(ctx context.Context, rootDirs []string, opts Options
 	results := util.NewResultTracker()
 	logger := prog.creationLogger(ctx, nil, nil)
 
+	if util.IsPaused(prog.fsys, opts.PauseFile) {
+		logger.Info("Paused by sentinel file (nothing done; will check again next run)", "pauseFile", opts.PauseFile)
+
+		return results, nil
+	}
+
 	if err := prog.considerRecursive(&opts); err != nil {
 		return results, fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, err)
 	}
 
 	jobs := []*Job{}
 	for _, rootDir := range rootDirs {
+		if err := util.CheckDataRoot(prog.fsys, rootDir, false); err != nil {
+			logger.Error("Failed to access the protected data (skipping; unmounted volume?)", "path", rootDir, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", rootDir, err))
+
+			continue
+		}
+
 		logger.Info("Scanning filesystem for jobs...",
 			"walker", prog.walker.Name(), "path", rootDir)
 
@@ -220,6 +352,9 @@ func (prog *Service) Create(ctx context.Context, rootDirs []string, opts Options
 		defer deadlineCancel()
 	}
 
+	progress := prog.log.NewProgress("creating", len(jobs))
+	defer progress.Close()
+
 	for i, job := range jobs {
 		if err := ctx.Err(); err != nil {
 			return results, fmt.Errorf("context error: %w", err)
@@ -236,8 +371,27 @@ func (prog *Service) Create(ctx context.Context, rootDirs []string, opts Options
 			}
 		}
 
+		if opts.Limit > 0 && i >= opts.Limit {
+			logger := prog.creationLogger(ctx, nil, nil)
+			logger.Warn("Reached the --limit of jobs per run (will continue next run)",
+				"unprocessedJobs", len(jobs)-i, "totalJobs", len(jobs),
+				"limit", opts.Limit)
+
+			break
+		}
+
+		if opts.MaxErrors > 0 && results.Error >= opts.MaxErrors {
+			logger := prog.creationLogger(ctx, nil, nil)
+			logger.Error("Reached the --max-errors threshold of failed jobs (aborting the run)",
+				"unprocessedJobs", len(jobs)-i, "totalJobs", len(jobs),
+				"maxErrors", opts.MaxErrors)
+
+			break
+		}
+
 		pos := fmt.Sprintf("%d/%d", i+1, len(jobs))
 		ctx := context.WithValue(ctx, schema.PosKey, pos)
+		progress.Step(i, job.par2Path)
 
 		logger := prog.creationLogger(ctx, job, nil)
 		logger.Info("Job started")
@@ -269,7 +423,7 @@ func (prog *Service) Create(ctx context.Context, rootDirs []string, opts Options
 
 func (prog *Service) Enumerate(ctx context.Context, rootDir string, opts Options) ([]*Job, error) {
 	jobs := []*Job{}
-	checker := util.NewIgnoreChecker(prog.fsys, rootDir)
+	checker := util.NewIgnoreChecker(prog.fsys, rootDir, opts.IgnoreNames)
 
 	var errs []error
 	err := prog.walker.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
@@ -283,6 +437,12 @@ func (prog *Service) Enumerate(ctx context.Context, rootDir string, opts Options
 			return nil
 		}
 
+		if d.IsDir() && util.ExceedsMaxDepth(rootDir, path, opts.MaxDepth) {
+			logger := prog.creationLogger(ctx, nil, path)
+			logger.Debug("A directory was skipped due to the maximum depth")
+
+			return fs.SkipDir
+		}
 		if d.IsDir() || !strings.HasPrefix(d.Name(), createMarkerPathPrefix) {
 			return nil
 		} // --- End of Hot Path ---
@@ -302,7 +462,9 @@ func (prog *Service) Enumerate(ctx context.Context, rootDir string, opts Options
 			return nil
 		}
 
-		jobs = append(jobs, NewJob(path, *cfg))
+		job := NewJob(path, *cfg)
+		job.strictGlob = opts.StrictGlob
+		jobs = append(jobs, job)
 
 		return nil
 	})
@@ -363,7 +525,7 @@ func (prog *Service) findElementsToProtect(ctx context.Context, job *Job) ([]sch
 	}
 
 	globFsys := afero.NewIOFS(prog.fsys)
-	globPath := globMetaReplacer.Replace(job.workingDir)
+	globPath := util.EscapeGlob(job.workingDir)
 
 	globPattern := filepath.Join(globPath, job.par2Glob)
 	globOptions := []doublestar.GlobOption{
@@ -387,9 +549,17 @@ func (prog *Service) findElementsToProtect(ctx context.Context, job *Job) ([]sch
 		return nil, fmt.Errorf("failed to glob: %w", err)
 	}
 
+	matched := 0
 	protectableElements := []schema.FsElement{}
 	for _, f := range protectablePaths {
-		if f == job.markerPath {
+		if f == job.markerPath || f == job.stampPath {
+			continue
+		}
+		// The excludes are subtracted from the glob, matched the same way (--glob-exclude).
+		if pattern, excluded := util.GlobExcludedBy(job.workingDir, f, job.par2GlobExclude); excluded {
+			logger := prog.creationLogger(ctx, job, f)
+			logger.Debug("A path was excluded from the glob", "exclude", pattern)
+
 			continue
 		}
 		// par2cmdline -R will include .par2 in subdirs, so keep this consistent.
@@ -397,13 +567,20 @@ func (prog *Service) findElementsToProtect(ctx context.Context, job *Job) ([]sch
 			if util.EndsWithFold(f, schema.Par2Extension) {
 				continue
 			}
-			if util.EndsWithFold(f, schema.Par2Extension+schema.LockExtension) {
+			if util.EndsWithFold(f, schema.Par2Extension+schema.CreationManifestExtension) {
+				continue
+			}
+			if util.EndsWithFold(f, schema.Par2Extension+schema.FileListExtension) {
+				continue
+			}
+			if job.sidecarNames.IsLock(f) {
 				continue
 			}
-			if util.EndsWithFold(f, schema.Par2Extension+schema.ManifestExtension) {
+			if job.sidecarNames.IsManifest(f) {
 				continue
 			}
 		}
+		matched++
 
 		fi, err := util.LstatIfPossible(prog.fsys, f)
 		if err != nil {
@@ -413,7 +590,7 @@ func (prog *Service) findElementsToProtect(ctx context.Context, job *Job) ([]sch
 			return nil, fmt.Errorf("failed to lstat: %w", err)
 		}
 
-		if (!fi.IsDir() && fi.Size() == 0) || (fi.IsDir() && job.par2Mode != schema.CreateRecursiveMode) {
+		if fi.IsDir() && job.par2Mode != schema.CreateRecursiveMode {
 			continue
 		}
 
@@ -424,6 +601,22 @@ func (prog *Service) findElementsToProtect(ctx context.Context, job *Job) ([]sch
 			continue
 		}
 
+		// FIFOs, sockets and devices have no data to protect (and could hang par2).
+		if !fi.IsDir() && !fi.Mode().IsRegular() {
+			logger := prog.creationLogger(ctx, job, f)
+			logger.Debug("A special file was skipped (not a regular file)", "type", fi.Mode().Type().String())
+
+			continue
+		}
+
+		// A file mode PAR2 set cannot be created for an empty file alone.
+		if !fi.IsDir() && fi.Size() == 0 && (job.excludeEmpty || job.par2Mode == schema.CreateFileMode) {
+			logger := prog.creationLogger(ctx, job, f)
+			logger.Debug("An empty file was skipped (zero bytes)")
+
+			continue
+		}
+
 		// In other modes, the structure is guaranteed to be shallow.
 		name := fi.Name()
 		if job.par2Mode == schema.CreateFolderMode {
@@ -436,17 +629,44 @@ func (prog *Service) findElementsToProtect(ctx context.Context, job *Job) ([]sch
 			}
 		}
 
-		protectableElements = append(protectableElements, schema.FsElement{
+		elem := schema.FsElement{
 			Path:    f,
 			Name:    name,
 			Size:    fi.Size(),
 			Mode:    fi.Mode(),
 			IsDir:   fi.IsDir(),
 			ModTime: fi.ModTime(),
-		})
+		}
+		if job.recordMetadata {
+			elem.Owner = util.FileOwner(fi)
+		}
+
+		protectableElements = append(protectableElements, elem)
+	}
+
+	// A nested mode PAR2 set cannot be created for only empty files either.
+	if job.par2Mode == schema.CreateNestedMode {
+		protectableElements = withoutEmptyDirs(protectableElements)
 	}
 
-	if len(protectableElements) == 0 {
+	// An empty folder is not a misconfiguration, but a glob matching nothing
+	// among existing files likely is one (e.g. a typo in the extension).
+	if matched == 0 && job.strictGlob {
+		if hasContent, err := prog.hasFolderContent(job); err != nil {
+			logger := prog.creationLogger(ctx, job, job.workingDir)
+			logger.Error("Failed to read folder (will retry next run)", "error", err)
+
+			return nil, fmt.Errorf("failed to read folder: %w", err)
+		} else if hasContent {
+			logger := prog.creationLogger(ctx, job, job.workingDir)
+			logger.Error("Glob pattern matched no files in non-empty folder (check the glob; will retry next run)",
+				"glob", job.par2Glob, "error", errGlobMatchedNone)
+
+			return nil, errGlobMatchedNone
+		}
+	}
+
+	if !hasData(protectableElements) {
 		logger := prog.creationLogger(ctx, job, job.workingDir)
 		logger.Warn("Nothing to protect (discarding the job)")
 
@@ -457,12 +677,29 @@ func (prog *Service) findElementsToProtect(ctx context.Context, job *Job) ([]sch
 }
 
 func (prog *Service) createCombined(ctx context.Context, job *Job, elements []schema.FsElement) error {
-	if exists, err := prog.par2AlreadyExists(ctx, job); err != nil {
+	if job.adoptExisting {
+		if adopted, err := prog.adoptExisting(ctx, job); err != nil {
+			return fmt.Errorf("failed to adopt: %w", err)
+		} else if adopted {
+			return nil
+		}
+	}
+
+	if path, err := prog.existingPar2(ctx, job); err != nil {
 		return fmt.Errorf("failed to check existence: %w", err)
-	} else if exists {
+	} else if path != "" {
+		prog.reportDrift(ctx, job, path, elements)
+
 		return nil
 	}
 
+	if job.maxSetSize > 0 && job.par2Mode == schema.CreateFolderMode {
+		split, err := prog.createSubsets(ctx, job, elements)
+		if split || err != nil {
+			return err
+		}
+	}
+
 	if err := prog.runCreate(ctx, job, elements); err != nil {
 		return err
 	}
@@ -499,11 +736,13 @@ func (prog *Service) createNested(ctx context.Context, job *Job, elements []sche
 
 		j := newNestedModeJob(*job, dir)
 
-		if exists, err := prog.par2AlreadyExists(ctx, &j); err != nil {
+		if path, err := prog.existingPar2(ctx, &j); err != nil {
 			errs = append(errs, fmt.Errorf("%s: failed to check existence: %w", j.par2Path, err))
 
 			continue
-		} else if exists {
+		} else if path != "" {
+			prog.reportDrift(ctx, &j, path, groups[dir])
+
 			continue
 		}
 
@@ -587,9 +826,11 @@ func (prog *Service) runCreate(ctx context.Context, job *Job, elements []schema.
 	}
 	defer unlock()
 
-	cmdArgs := make([]string, 0, 1+len(job.par2Args)+1+1+len(elements))
+	par2Args := prog.withBlockCount(ctx, job, elements)
+
+	cmdArgs := make([]string, 0, 1+len(par2Args)+1+1+len(elements))
 	cmdArgs = append(cmdArgs, "create")
-	cmdArgs = append(cmdArgs, job.par2Args...)
+	cmdArgs = append(cmdArgs, par2Args...)
 	cmdArgs = append(cmdArgs, "--")
 	cmdArgs = append(cmdArgs, job.par2Path)
 	cmdArgs = append(cmdArgs, getPaths(elements)...)
@@ -598,11 +839,37 @@ func (prog *Service) runCreate(ctx context.Context, job *Job, elements []schema.
 	mf.Creation = schema.NewCreationManifest()
 	mf.Creation.Mode = job.par2Mode
 	mf.Creation.Glob = job.par2Glob
-	mf.Creation.Args = slices.Clone(job.par2Args)
+	mf.Creation.GlobExclude = slices.Clone(job.par2GlobExclude)
+	mf.Creation.Args = slices.Clone(par2Args)
 	mf.Creation.Elements = elements
+	mf.Creation.VerifyInterval = job.verifyInterval
+	mf.Creation.Tags = slices.Clone(job.tags)
+	mf.Creation.NoAutoRepair = job.noAutoRepair
+	mf.Creation.Subset = job.subset
+	mf.Creation.Marker = job.marker
+	if job.folderFingerprint && job.par2Mode == schema.CreateFolderMode {
+		mf.Creation.FolderFingerprint = cmp.Or(job.fingerprint, util.FolderFingerprint(elements))
+	}
 
 	mf.Creation.Time = time.Now()
+	if job.creationPath != "" {
+		if err := util.WriteManifest(ctx, prog.fsys, prog.bundler, job.creationPath, mf, false); err != nil {
+			needsCleanup = true
+			logger := prog.creationLogger(ctx, job, job.creationPath)
+			logger.Error("Failed to write creation manifest for protection (will retry next run)", "error", err)
+
+			return fmt.Errorf("failed to write creation manifest: %w", err)
+		}
+		cmdArgs = append(cmdArgs, job.creationPath)
+	}
+
 	err = prog.runner.Run(ctx, "par2", cmdArgs, job.workingDir, prog.log.Options.Stdout, prog.log.Options.Stdout)
+	if job.retrySingleThreaded && ctx.Err() == nil && util.IsPar2Crash(err) {
+		logger := prog.creationLogger(ctx, job, job.par2Path)
+		logger.Warn("Failed to create PAR2 (retrying single-threaded)", "error", err)
+		prog.removePar2Files(ctx, job)
+		err = prog.runner.Run(ctx, "par2", util.Par2SingleThreadedArgs(cmdArgs, schema.Par2Flavor), job.workingDir, prog.log.Options.Stdout, prog.log.Options.Stdout)
+	}
 	mf.Creation.Duration = time.Since(mf.Creation.Time)
 
 	if err != nil {
@@ -612,6 +879,9 @@ func (prog *Service) runCreate(ctx context.Context, job *Job, elements []schema.
 		c := util.AsExitCode(err)
 		if c != nil {
 			err = fmt.Errorf("%w (%d)", err, *c)
+			if class := util.Par2ExitError(*c); class != nil {
+				err = fmt.Errorf("%w: %w", class, err)
+			}
 		}
 
 		logger := prog.creationLogger(ctx, job, job.par2Path)
@@ -647,7 +917,7 @@ func (prog *Service) runCreate(ctx context.Context, job *Job, elements []schema.
 
 	if job.par2Verify {
 		vs := verify.NewService(prog.fsys, prog.log, prog.runner, prog.bundler, prog.cacher)
-		vj := verify.NewJob(job.par2Path, verify.Options{}, mf, job.asBundle)
+		vj := verify.NewJob(job.par2Path, verify.Options{SidecarNames: job.sidecarNames, RetrySingleThreaded: job.retrySingleThreaded}, mf, job.asBundle)
 
 		if err := vs.RunVerify(ctx, vj, true); err != nil {
 			needsCleanup = true
@@ -658,6 +928,26 @@ func (prog *Service) runCreate(ctx context.Context, job *Job, elements []schema.
 		}
 	}
 
+	if job.fileListPath != "" {
+		if err := util.WriteFileList(prog.fsys, job.fileListPath, schema.NewFileList(job.par2Name, elements)); err != nil {
+			needsCleanup = true
+			logger := prog.creationLogger(ctx, job, job.fileListPath)
+			logger.Error("Failed to write file list (will retry next run)", "error", err)
+
+			return fmt.Errorf("failed to write file list: %w", err)
+		}
+	}
+
+	if job.stampPath != "" {
+		if err := util.WriteStamp(prog.fsys, job.stampPath, schema.NewStamp(job.par2Name, "create")); err != nil {
+			needsCleanup = true
+			logger := prog.creationLogger(ctx, job, job.stampPath)
+			logger.Error("Failed to write stamp file (will retry next run)", "error", err)
+
+			return fmt.Errorf("failed to write stamp: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -685,7 +975,7 @@ func (prog *Service) packAsBundle(ctx context.Context, job *Job, mf *schema.Mani
 	bundleName := baseName + schema.BundleExtension + schema.Par2Extension
 	bundlePath := filepath.Join(job.workingDir, bundleName)
 
-	manifestData, err := json.MarshalIndent(mf, "", "  ")
+	manifestData, err := util.MarshalManifest(mf)
 	if err != nil {
 		return fmt.Errorf("failed to marshal manifest: %w", err)
 	}
@@ -64,6 +64,55 @@ func Test_Options_Validate_InvalidGlobPattern_Error(t *testing.T) {
 	require.ErrorIs(t, opts.Validate(), doublestar.ErrBadPattern)
 }
 
+// Expectation: Validation should fail on invalid excludes, and on deep excludes in recursive mode.
+func Test_Options_Validate_GlobExclude_Error(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{Par2Glob: "*", Par2GlobExclude: []string{"*.tmp", "**/*.log"}}
+	require.NoError(t, opts.Par2Mode.Set(schema.CreateFolderMode))
+	require.NoError(t, opts.Validate())
+
+	opts.Par2GlobExclude = []string{"*.tmp", "{unclosed"}
+	require.ErrorIs(t, opts.Validate(), doublestar.ErrBadPattern)
+
+	opts.Par2GlobExclude = []string{""}
+	require.ErrorIs(t, opts.Validate(), doublestar.ErrBadPattern)
+
+	opts.Par2GlobExclude = []string{"**/*.log"}
+	require.NoError(t, opts.Par2Mode.Set(schema.CreateRecursiveMode))
+	require.ErrorIs(t, opts.Validate(), schema.ErrUnsupportedGlob)
+}
+
+// Expectation: Validation should fail when a stamp file is to be written with an unusable filename.
+func Test_Options_Validate_StampFile_Error(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{Par2Glob: "*", WriteStamp: true, StampFile: "_par2cron.done"}
+	require.ErrorContains(t, opts.Validate(), "stamp-file")
+
+	opts.StampFile = "sub/done"
+	require.ErrorContains(t, opts.Validate(), "stamp-file")
+
+	opts.WriteStamp = false
+	require.NoError(t, opts.Validate())
+}
+
+// Expectation: Validation should fail on a too small target block size or one combined with -b or -s.
+func Test_Options_Validate_TargetBlockSize_Error(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{Par2Glob: "*"}
+	require.NoError(t, opts.TargetBlockSize.Set("2"))
+	require.ErrorContains(t, opts.Validate(), "target-block-size")
+
+	require.NoError(t, opts.TargetBlockSize.Set("4M"))
+	opts.Par2Args = []string{"-r10", "-b2000"}
+	require.ErrorContains(t, opts.Validate(), "target-block-size")
+
+	opts.Par2Args = []string{"-r10"}
+	require.NoError(t, opts.Validate())
+}
+
 // Expectation: The correct paths should be derived from the [createConfig].
 func Test_NewJob_Success(t *testing.T) {
 	t.Parallel()
@@ -110,6 +159,8 @@ func Test_NewJob_HideFiles_Success(t *testing.T) {
 		HideFiles:     new(true),
 		PersistMarker: new(true),
 		Bundle:        new(true),
+		ExcludeEmpty:  new(true),
+		AdoptExisting: new(true),
 	}
 
 	job := NewJob("/data/folder/_par2cron", cfg)
@@ -128,6 +179,8 @@ func Test_NewJob_HideFiles_Success(t *testing.T) {
 	require.True(t, job.hiddenFiles)
 	require.True(t, job.markerPersist)
 	require.True(t, job.asBundle)
+	require.True(t, job.excludeEmpty)
+	require.True(t, job.adoptExisting)
 }
 
 // Expectation: The relevant fields should be changed for file mode, others not.
@@ -157,122 +210,710 @@ func Test_newFileModeJob_Success(t *testing.T) {
 	require.Equal(t, "*.txt", got.par2Glob)
 	require.True(t, got.par2Verify)
 
-	require.NotEqual(t, baseJob, got)
-}
+	require.NotEqual(t, baseJob, got)
+}
+
+// Expectation: The relevant fields should be changed for file mode, others not.
+func Test_newFileModeJob_HideFiles_Success(t *testing.T) {
+	t.Parallel()
+
+	baseJob := Job{
+		workingDir: "/data/folder",
+		markerPath: "/data/folder/_par2cron",
+		par2Name:   ".folder" + schema.Par2Extension,
+		par2Mode:   schema.CreateFileMode,
+		par2Args:   []string{"-r10", "-n5"},
+		par2Glob:   "*.txt",
+		par2Verify: true,
+	}
+
+	got := newFileModeJob(baseJob, "/data/folder/document.txt")
+
+	require.Equal(t, ".document.txt"+schema.Par2Extension, got.par2Name)
+	require.Equal(t, "/data/folder/.document.txt"+schema.Par2Extension, got.par2Path)
+	require.Equal(t, ".document.txt"+schema.Par2Extension+schema.ManifestExtension, got.manifestName)
+	require.Equal(t, "/data/folder/.document.txt"+schema.Par2Extension+schema.ManifestExtension, got.manifestPath)
+	require.Equal(t, "/data/folder/.document.txt"+schema.Par2Extension+schema.LockExtension, got.lockPath)
+	require.Equal(t, "/data/folder", got.workingDir)
+	require.Equal(t, "/data/folder/_par2cron", got.markerPath)
+	require.Equal(t, schema.CreateFileMode, got.par2Mode)
+	require.Equal(t, []string{"-r10", "-n5"}, got.par2Args)
+	require.Equal(t, "*.txt", got.par2Glob)
+	require.True(t, got.par2Verify)
+
+	require.NotEqual(t, baseJob, got)
+}
+
+// Expectation: The relevant fields should be changed for nested mode, others not.
+func Test_newNestedModeJob_Success(t *testing.T) {
+	t.Parallel()
+
+	baseJob := Job{
+		workingDir: "/data/folder",
+		markerPath: "/data/folder/_par2cron",
+		par2Mode:   schema.CreateFileMode,
+		par2Args:   []string{"-r10", "-n5"},
+		par2Glob:   "*.txt",
+		par2Verify: true,
+	}
+
+	got := newNestedModeJob(baseJob, "/data/folder/subdir")
+
+	require.Equal(t, "subdir"+schema.Par2Extension, got.par2Name)
+	require.Equal(t, "/data/folder/subdir/subdir"+schema.Par2Extension, got.par2Path)
+	require.Equal(t, "subdir"+schema.Par2Extension+schema.ManifestExtension, got.manifestName)
+	require.Equal(t, "/data/folder/subdir/subdir"+schema.Par2Extension+schema.ManifestExtension, got.manifestPath)
+	require.Equal(t, "/data/folder/subdir/subdir"+schema.Par2Extension+schema.LockExtension, got.lockPath)
+	require.Equal(t, "/data/folder/subdir", got.workingDir)
+	require.Equal(t, "/data/folder/_par2cron", got.markerPath)
+	require.Equal(t, schema.CreateFileMode, got.par2Mode)
+	require.Equal(t, []string{"-r10", "-n5"}, got.par2Args)
+	require.Equal(t, "*.txt", got.par2Glob)
+	require.True(t, got.par2Verify)
+
+	require.NotEqual(t, baseJob, got)
+}
+
+// Expectation: The relevant fields should be changed for nested mode, others not.
+func Test_newNestedModeJob_HideFiles_Success(t *testing.T) {
+	t.Parallel()
+
+	baseJob := Job{
+		workingDir: "/data/folder",
+		markerPath: "/data/folder/_par2cron",
+		par2Name:   ".folder" + schema.Par2Extension,
+		par2Mode:   schema.CreateFileMode,
+		par2Args:   []string{"-r10", "-n5"},
+		par2Glob:   "*.txt",
+		par2Verify: true,
+	}
+
+	got := newNestedModeJob(baseJob, "/data/folder/subdir")
+
+	require.Equal(t, ".subdir"+schema.Par2Extension, got.par2Name)
+	require.Equal(t, "/data/folder/subdir/.subdir"+schema.Par2Extension, got.par2Path)
+	require.Equal(t, ".subdir"+schema.Par2Extension+schema.ManifestExtension, got.manifestName)
+	require.Equal(t, "/data/folder/subdir/.subdir"+schema.Par2Extension+schema.ManifestExtension, got.manifestPath)
+	require.Equal(t, "/data/folder/subdir/.subdir"+schema.Par2Extension+schema.LockExtension, got.lockPath)
+	require.Equal(t, "/data/folder/subdir", got.workingDir)
+	require.Equal(t, "/data/folder/_par2cron", got.markerPath)
+	require.Equal(t, schema.CreateFileMode, got.par2Mode)
+	require.Equal(t, []string{"-r10", "-n5"}, got.par2Args)
+	require.Equal(t, "*.txt", got.par2Glob)
+	require.True(t, got.par2Verify)
+
+	require.NotEqual(t, baseJob, got)
+}
+
+// Expectation: The program should handle a single job that succeeds.
+func Test_Service_Create_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/file.txt", []byte("content"), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var called bool
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called = true
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/folder"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	args := Options{Par2Args: []string{"-r10"}, Par2Glob: "*"}
+	_, err := prog.Create(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	require.True(t, called)
+	require.Contains(t, logBuf.String(), "Job completed with success")
+}
+
+// Expectation: An existing pause file should skip all work, and its absence should not.
+func Test_Service_Create_PauseFile_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		paused bool
+	}{
+		{"pause file exists", true},
+		{"pause file missing", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(""), 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/file.txt", []byte("content"), 0o644))
+
+			if tt.paused {
+				require.NoError(t, afero.WriteFile(fs, "/run/par2cron.pause", []byte(""), 0o644))
+			}
+
+			var logBuf testutil.SafeBuffer
+			ls := logging.Options{
+				Logout: &logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+			_ = ls.LogLevel.Set("info")
+
+			var called bool
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					called = true
+					require.NoError(t, afero.WriteFile(fs, "/data/folder/folder"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+					return nil
+				},
+			}
+
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+			args := Options{Par2Args: []string{"-r10"}, Par2Glob: "*", PauseFile: "/run/par2cron.pause"}
+			results, err := prog.Create(t.Context(), []string{"/data"}, args)
+			require.NoError(t, err)
+
+			require.Equal(t, !tt.paused, called)
+			if tt.paused {
+				require.Zero(t, results.Selected)
+				require.Contains(t, logBuf.String(), "Paused by sentinel file")
+			} else {
+				require.Equal(t, 1, results.Success)
+				require.NotContains(t, logBuf.String(), "Paused by sentinel file")
+			}
+		})
+	}
+}
+
+// Expectation: A stamp file should be written next to the created PAR2 set, without being protected by it.
+func Test_Service_Create_WriteStamp_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/file.txt", []byte("content"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/done.json", []byte("stale"), 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	var runArgs []string
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			runArgs = args
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/folder"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	args := Options{Par2Glob: "*", WriteStamp: true, StampFile: "done.json"}
+	_, err := prog.Create(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	require.Contains(t, runArgs, "/data/folder/file.txt")
+	require.NotContains(t, runArgs, "/data/folder/done.json")
+
+	stamp, err := util.ReadStamp(fs, "/data/folder/done.json")
+	require.NoError(t, err)
+	require.Equal(t, "folder"+schema.Par2Extension, stamp.Name)
+	require.Equal(t, "create", stamp.Operation)
+	require.False(t, stamp.Time.IsZero())
+}
+
+// Expectation: The file list should list exactly the files protected by the created PAR2 set.
+func Test_Service_Create_WriteFileList_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder/sub", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/a.txt", []byte("content"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/sub/b.txt", []byte("more content"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/folder"+schema.Par2Extension+schema.FileListExtension, []byte("stale"), 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	var protected []string
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			protected = slices.Clone(args[slices.Index(args, "--")+2:])
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/folder"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	args := Options{Par2Glob: "**", Par2Mode: flags.CreateMode{Value: schema.CreateFolderMode}, WriteFileList: true}
+	_, err := prog.Create(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, "/data/folder/folder"+schema.Par2Extension+schema.FileListExtension)
+	require.NoError(t, err)
+
+	var list schema.FileList
+	require.NoError(t, json.Unmarshal(data, &list))
+	require.Equal(t, "folder"+schema.Par2Extension, list.Name)
+
+	listed := make([]string, 0, len(list.Files))
+	for _, f := range list.Files {
+		listed = append(listed, filepath.Join("/data/folder", f.Name))
+	}
+	require.ElementsMatch(t, protected, listed)
+	require.ElementsMatch(t, []schema.FileListEntry{{Name: "a.txt", Size: 7}, {Name: "sub/b.txt", Size: 12}}, list.Files)
+}
+
+// Expectation: The file list should be removed together with the other files of a failed creation.
+func Test_Service_Create_WriteFileList_Failure_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/a.txt", []byte("content"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/folder"+schema.Par2Extension+schema.FileListExtension, []byte("stale"), 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			return errors.New("disk I/O error")
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	args := Options{Par2Glob: "*", WriteFileList: true}
+	_, err := prog.Create(t.Context(), []string{"/data"}, args)
+	require.Error(t, err)
+
+	exists, err := afero.Exists(fs, "/data/folder/folder"+schema.Par2Extension+schema.FileListExtension)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+// Expectation: A snapshot of the creation manifest should be written and protected in folder mode.
+func Test_Service_Create_ProtectCreationManifest_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/file.txt", []byte("content"), 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	snapshotPath := "/data/folder/folder" + schema.Par2Extension + schema.CreationManifestExtension
+
+	var runArgs []string
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			runArgs = args
+			exists, err := afero.Exists(fs, snapshotPath)
+			require.NoError(t, err)
+			require.True(t, exists)
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/folder"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	args := Options{Par2Glob: "*", Par2Mode: flags.CreateMode{Value: schema.CreateFolderMode}, ProtectCreationManifest: true}
+	_, err := prog.Create(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"/data/folder/file.txt", snapshotPath}, runArgs[len(runArgs)-2:])
+
+	data, err := afero.ReadFile(fs, snapshotPath)
+	require.NoError(t, err)
+	mf, err := util.UnmarshalManifest(data, 0)
+	require.NoError(t, err)
+	require.Equal(t, "folder"+schema.Par2Extension, mf.Name)
+	require.Len(t, mf.Creation.Elements, 1)
+	require.Empty(t, mf.SHA256)
+}
+
+// Expectation: No snapshot of the creation manifest should be written in modes other than folder mode.
+func Test_Service_Create_ProtectCreationManifest_FileMode_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/file.txt", []byte("content"), 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	var runArgs []string
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			runArgs = args
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	args := Options{Par2Glob: "*", Par2Mode: flags.CreateMode{Value: schema.CreateFileMode}, ProtectCreationManifest: true}
+	_, err := prog.Create(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	require.Equal(t, "/data/folder/file.txt", runArgs[len(runArgs)-1])
+
+	exists, err := afero.Exists(fs, "/data/folder/file.txt"+schema.Par2Extension+schema.CreationManifestExtension)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+// Expectation: The job loop should abort once --max-errors jobs have failed, returning a partial failure.
+func Test_Service_Create_MaxErrors_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	for _, dir := range []string{"/data/a", "/data/b", "/data/c", "/data/d"} {
+		require.NoError(t, fs.MkdirAll(dir, 0o755))
+		require.NoError(t, afero.WriteFile(fs, dir+"/"+createMarkerPathPrefix, []byte{}, 0o644))
+		require.NoError(t, afero.WriteFile(fs, dir+"/file.txt", []byte("content"), 0o644))
+	}
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+
+			return errors.New("disk I/O error")
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	results, err := prog.Create(t.Context(), []string{"/data"}, Options{Par2Glob: "*", MaxErrors: 2})
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+
+	require.Equal(t, 2, called)
+	require.Equal(t, 4, results.Selected)
+	require.Equal(t, 2, results.Error)
+	require.Contains(t, logBuf.String(), "Reached the --max-errors threshold of failed jobs")
+	require.Contains(t, logBuf.String(), "unprocessedJobs=2")
+}
+
+// Expectation: The job loop should stop after the --limit of jobs, retaining the unprocessed markers.
+func Test_Service_Create_Limit_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	for _, dir := range []string{"/data/a", "/data/b", "/data/c"} {
+		require.NoError(t, fs.MkdirAll(dir, 0o755))
+		require.NoError(t, afero.WriteFile(fs, dir+"/"+createMarkerPathPrefix, []byte{}, 0o644))
+		require.NoError(t, afero.WriteFile(fs, dir+"/file.txt", []byte("content"), 0o644))
+	}
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+			require.NoError(t, afero.WriteFile(fs, workingDir+"/"+filepath.Base(workingDir)+schema.Par2Extension, []byte("par2data"), 0o644))
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	results, err := prog.Create(t.Context(), []string{"/data"}, Options{Par2Glob: "*", Limit: 1})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, called)
+	require.Equal(t, 3, results.Selected)
+	require.Equal(t, 1, results.Success)
+	require.Contains(t, logBuf.String(), "Reached the --limit of jobs per run")
+
+	var markers int
+	for _, dir := range []string{"/data/a", "/data/b", "/data/c"} {
+		if exists, _ := afero.Exists(fs, dir+"/"+createMarkerPathPrefix); exists {
+			markers++
+		}
+	}
+	require.Equal(t, 2, markers)
+}
+
+// Expectation: The verification interval from the marker should be stored in the creation manifest.
+func Test_Service_Create_MarkerVerifyInterval_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(`verify-interval: "12h"`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/file.txt", []byte("content"), 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/folder"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	_, err := prog.Create(t.Context(), []string{"/data"}, Options{Par2Glob: "*"})
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, "/data/folder/folder"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+
+	var mf schema.Manifest
+	require.NoError(t, json.Unmarshal(data, &mf))
+	require.NotNil(t, mf.Creation)
+	require.Equal(t, 12*time.Hour, mf.Creation.VerifyInterval)
+}
+
+// Expectation: The no-auto-repair policy from the marker should be stored in the creation manifest.
+func Test_Service_Create_MarkerNoAutoRepair_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(`no-auto-repair: true`), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/file.txt", []byte("content"), 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/folder"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	_, err := prog.Create(t.Context(), []string{"/data"}, Options{Par2Glob: "*"})
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, "/data/folder/folder"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+
+	var mf schema.Manifest
+	require.NoError(t, json.Unmarshal(data, &mf))
+	require.NotNil(t, mf.Creation)
+	require.True(t, mf.Creation.NoAutoRepair)
+}
+
+// Expectation: The settings given in the contents of the marker should round-trip into the creation manifest
+// as given (not as made effective), and a marker without contents should still be recorded by its name.
+func Test_Service_Create_MarkerRecorded_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/configured", 0o755))
+	require.NoError(t, fs.MkdirAll("/data/plain", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/configured/"+createMarkerPathPrefix, []byte("glob: \"*.txt\"\nmode: recursive\nargs: [\"-r20\"]\n"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/configured/file.txt", []byte("content"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/plain/"+createMarkerPathPrefix, []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/plain/file.txt", []byte("content"), 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			return afero.WriteFile(fs, filepath.Join(workingDir, filepath.Base(workingDir)+schema.Par2Extension), []byte("par2data"), 0o644)
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	_, err := prog.Create(t.Context(), []string{"/data"}, Options{Par2Glob: "*", Par2Args: []string{"-r10"}})
+	require.NoError(t, err)
+
+	readManifest := func(path string) schema.Manifest {
+		t.Helper()
 
-// Expectation: The relevant fields should be changed for file mode, others not.
-func Test_newFileModeJob_HideFiles_Success(t *testing.T) {
-	t.Parallel()
+		data, err := afero.ReadFile(fs, path)
+		require.NoError(t, err)
 
-	baseJob := Job{
-		workingDir: "/data/folder",
-		markerPath: "/data/folder/_par2cron",
-		par2Name:   ".folder" + schema.Par2Extension,
-		par2Mode:   schema.CreateFileMode,
-		par2Args:   []string{"-r10", "-n5"},
-		par2Glob:   "*.txt",
-		par2Verify: true,
-	}
+		var mf schema.Manifest
+		require.NoError(t, json.Unmarshal(data, &mf))
+		require.NotNil(t, mf.Creation)
 
-	got := newFileModeJob(baseJob, "/data/folder/document.txt")
+		return mf
+	}
 
-	require.Equal(t, ".document.txt"+schema.Par2Extension, got.par2Name)
-	require.Equal(t, "/data/folder/.document.txt"+schema.Par2Extension, got.par2Path)
-	require.Equal(t, ".document.txt"+schema.Par2Extension+schema.ManifestExtension, got.manifestName)
-	require.Equal(t, "/data/folder/.document.txt"+schema.Par2Extension+schema.ManifestExtension, got.manifestPath)
-	require.Equal(t, "/data/folder/.document.txt"+schema.Par2Extension+schema.LockExtension, got.lockPath)
-	require.Equal(t, "/data/folder", got.workingDir)
-	require.Equal(t, "/data/folder/_par2cron", got.markerPath)
-	require.Equal(t, schema.CreateFileMode, got.par2Mode)
-	require.Equal(t, []string{"-r10", "-n5"}, got.par2Args)
-	require.Equal(t, "*.txt", got.par2Glob)
-	require.True(t, got.par2Verify)
+	mf := readManifest("/data/configured/configured" + schema.Par2Extension + schema.ManifestExtension)
+	require.Equal(t, schema.CreateRecursiveMode, mf.Creation.Mode)
+	require.Contains(t, mf.Creation.Args, "-R")
+	require.NotNil(t, mf.Creation.Marker)
+	require.Equal(t, createMarkerPathPrefix, mf.Creation.Marker.File)
+	require.Equal(t, new("*.txt"), mf.Creation.Marker.Glob)
+	require.Equal(t, new(schema.CreateRecursiveMode), mf.Creation.Marker.Mode)
+	require.Equal(t, &[]string{"-r20"}, mf.Creation.Marker.Args)
+	require.Nil(t, mf.Creation.Marker.Name)
+	require.Nil(t, mf.Creation.Marker.Tags)
 
-	require.NotEqual(t, baseJob, got)
+	mf = readManifest("/data/plain/plain" + schema.Par2Extension + schema.ManifestExtension)
+	require.Equal(t, []string{"-r10"}, mf.Creation.Args)
+	require.Equal(t, &schema.CreationMarker{File: createMarkerPathPrefix}, mf.Creation.Marker)
 }
 
-// Expectation: The relevant fields should be changed for nested mode, others not.
-func Test_newNestedModeJob_Success(t *testing.T) {
+// Expectation: A folder fingerprint should only be recorded with the option, and match the one
+// recomputed from a listing of the folder after the creation (as at verification).
+func Test_Service_Create_FolderFingerprint_Table(t *testing.T) {
 	t.Parallel()
 
-	baseJob := Job{
-		workingDir: "/data/folder",
-		markerPath: "/data/folder/_par2cron",
-		par2Mode:   schema.CreateFileMode,
-		par2Args:   []string{"-r10", "-n5"},
-		par2Glob:   "*.txt",
-		par2Verify: true,
+	tests := []struct {
+		name string
+		opt  bool
+	}{
+		{"off", false},
+		{"on", true},
 	}
 
-	got := newNestedModeJob(baseJob, "/data/folder/subdir")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
 
-	require.Equal(t, "subdir"+schema.Par2Extension, got.par2Name)
-	require.Equal(t, "/data/folder/subdir/subdir"+schema.Par2Extension, got.par2Path)
-	require.Equal(t, "subdir"+schema.Par2Extension+schema.ManifestExtension, got.manifestName)
-	require.Equal(t, "/data/folder/subdir/subdir"+schema.Par2Extension+schema.ManifestExtension, got.manifestPath)
-	require.Equal(t, "/data/folder/subdir/subdir"+schema.Par2Extension+schema.LockExtension, got.lockPath)
-	require.Equal(t, "/data/folder/subdir", got.workingDir)
-	require.Equal(t, "/data/folder/_par2cron", got.markerPath)
-	require.Equal(t, schema.CreateFileMode, got.par2Mode)
-	require.Equal(t, []string{"-r10", "-n5"}, got.par2Args)
-	require.Equal(t, "*.txt", got.par2Glob)
-	require.True(t, got.par2Verify)
+			fs := afero.NewMemMapFs()
+			require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(""), 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/a.txt", []byte("content a"), 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/b.txt", []byte("content b"), 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/empty.txt", []byte(""), 0o644))
 
-	require.NotEqual(t, baseJob, got)
-}
+			ls := logging.Options{
+				Logout: io.Discard,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
 
-// Expectation: The relevant fields should be changed for nested mode, others not.
-func Test_newNestedModeJob_HideFiles_Success(t *testing.T) {
-	t.Parallel()
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					require.NoError(t, afero.WriteFile(fs, "/data/folder/folder"+schema.Par2Extension, []byte("par2data"), 0o644))
 
-	baseJob := Job{
-		workingDir: "/data/folder",
-		markerPath: "/data/folder/_par2cron",
-		par2Name:   ".folder" + schema.Par2Extension,
-		par2Mode:   schema.CreateFileMode,
-		par2Args:   []string{"-r10", "-n5"},
-		par2Glob:   "*.txt",
-		par2Verify: true,
-	}
+					return nil
+				},
+			}
 
-	got := newNestedModeJob(baseJob, "/data/folder/subdir")
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
 
-	require.Equal(t, ".subdir"+schema.Par2Extension, got.par2Name)
-	require.Equal(t, "/data/folder/subdir/.subdir"+schema.Par2Extension, got.par2Path)
-	require.Equal(t, ".subdir"+schema.Par2Extension+schema.ManifestExtension, got.manifestName)
-	require.Equal(t, "/data/folder/subdir/.subdir"+schema.Par2Extension+schema.ManifestExtension, got.manifestPath)
-	require.Equal(t, "/data/folder/subdir/.subdir"+schema.Par2Extension+schema.LockExtension, got.lockPath)
-	require.Equal(t, "/data/folder/subdir", got.workingDir)
-	require.Equal(t, "/data/folder/_par2cron", got.markerPath)
-	require.Equal(t, schema.CreateFileMode, got.par2Mode)
-	require.Equal(t, []string{"-r10", "-n5"}, got.par2Args)
-	require.Equal(t, "*.txt", got.par2Glob)
-	require.True(t, got.par2Verify)
+			_, err := prog.Create(t.Context(), []string{"/data"}, Options{
+				Par2Glob:          "*",
+				Par2Mode:          flags.CreateMode{Value: schema.CreateFolderMode},
+				FolderFingerprint: tt.opt,
+			})
+			require.NoError(t, err)
 
-	require.NotEqual(t, baseJob, got)
+			data, err := afero.ReadFile(fs, "/data/folder/folder"+schema.Par2Extension+schema.ManifestExtension)
+			require.NoError(t, err)
+
+			var mf schema.Manifest
+			require.NoError(t, json.Unmarshal(data, &mf))
+			require.NotNil(t, mf.Creation)
+
+			if !tt.opt {
+				require.Empty(t, mf.Creation.FolderFingerprint)
+
+				return
+			}
+
+			elements, err := util.ListFolderElements(fs, "/data/folder", "*", nil, util.SidecarNames{}, "")
+			require.NoError(t, err)
+			require.Equal(t, util.FolderFingerprint(elements), mf.Creation.FolderFingerprint)
+		})
+	}
 }
 
-// Expectation: The program should handle a single job that succeeds.
-func Test_Service_Create_Success(t *testing.T) {
+// Expectation: The tags from the marker should be stored sorted and unique in the creation manifest.
+func Test_Service_Create_MarkerTags_Success(t *testing.T) {
 	t.Parallel()
 
 	fs := afero.NewMemMapFs()
 	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
-	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(`tags: ["tier:critical", "media:video", "tier:critical"]`), 0o644))
 	require.NoError(t, afero.WriteFile(fs, "/data/folder/file.txt", []byte("content"), 0o644))
 
-	var logBuf testutil.SafeBuffer
 	ls := logging.Options{
-		Logout: &logBuf,
+		Logout: io.Discard,
 		Stdout: io.Discard,
 		Stderr: io.Discard,
 	}
-	_ = ls.LogLevel.Set("info")
 
-	var called bool
 	runner := &testutil.MockRunner{
 		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
-			called = true
 			require.NoError(t, afero.WriteFile(fs, "/data/folder/folder"+schema.Par2Extension, []byte("par2data"), 0o644))
 
 			return nil
@@ -281,12 +922,17 @@ func Test_Service_Create_Success(t *testing.T) {
 
 	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
 
-	args := Options{Par2Args: []string{"-r10"}, Par2Glob: "*"}
-	_, err := prog.Create(t.Context(), []string{"/data"}, args)
+	_, err := prog.Create(t.Context(), []string{"/data"}, Options{Par2Glob: "*"})
 	require.NoError(t, err)
 
-	require.True(t, called)
-	require.Contains(t, logBuf.String(), "Job completed with success")
+	data, err := afero.ReadFile(fs, "/data/folder/folder"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+
+	var mf schema.Manifest
+	require.NoError(t, json.Unmarshal(data, &mf))
+	require.NotNil(t, mf.Creation)
+	require.Equal(t, []string{"media:video", "tier:critical"}, mf.Creation.Tags)
+	require.Equal(t, []string{"media:video", "tier:critical"}, schema.NewJobMeta("", &mf, false).Tags)
 }
 
 // Expectation: The program should handle multiple provided root directories.
@@ -405,6 +1051,78 @@ func Test_Service_Create_Generic_Error(t *testing.T) {
 	require.Contains(t, logBuf.String(), "Job failure (will retry next run)")
 }
 
+// Expectation: A par2 crash should be retried once single-threaded (after removing the
+// partial PAR2 files) only with the option, while other failures should not be retried.
+func Test_Service_Create_RetrySingleThreaded_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		retry     bool
+		code      int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"crash retried", true, schema.Par2ExitCodeLogicError, 2, false},
+		{"crash without option", false, schema.Par2ExitCodeLogicError, 1, true},
+		{"io error not retried", true, schema.Par2ExitCodeFileIOError, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(""), 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/file.txt", []byte("content"), 0o644))
+
+			ls := logging.Options{
+				Logout: io.Discard,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+
+			var runArgs [][]string
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					runArgs = append(runArgs, args)
+
+					if !slices.Contains(args, "-t1") {
+						require.NoError(t, afero.WriteFile(fs, "/data/folder/folder"+schema.Par2Extension, []byte("partial"), 0o644))
+
+						return testutil.CreateExitError(t, ctx, tt.code)
+					}
+
+					exists, err := afero.Exists(fs, "/data/folder/folder"+schema.Par2Extension)
+					require.NoError(t, err)
+					require.False(t, exists)
+					require.NoError(t, afero.WriteFile(fs, "/data/folder/folder"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+					return nil
+				},
+			}
+
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+			args := Options{Par2Args: []string{"-t8"}, Par2Glob: "*", RetrySingleThreaded: tt.retry}
+			_, err := prog.Create(t.Context(), []string{"/data"}, args)
+			if tt.wantErr {
+				require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Len(t, runArgs, tt.wantCalls)
+			require.Contains(t, runArgs[0], "-t8")
+			if tt.wantCalls > 1 {
+				require.NotContains(t, runArgs[1], "-t8")
+				require.Contains(t, runArgs[1], "-t1")
+			}
+		})
+	}
+}
+
 // Expectation: The program should handle multiple jobs that succeed.
 func Test_Service_Create_MultipleJobs_Success(t *testing.T) {
 	t.Parallel()
@@ -966,6 +1684,38 @@ func Test_Service_Enumerate_IgnoreFile_Success(t *testing.T) {
 	require.Contains(t, jobs[0].par2Path, "folder1")
 }
 
+// Expectation: Marker files in directories beyond the maximum depth should not be enumerated.
+func Test_Service_Enumerate_MaxDepth_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder1/folder2", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/"+createMarkerPathPrefix, []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder1/"+createMarkerPathPrefix, []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder1/folder2/"+createMarkerPathPrefix, []byte(""), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("debug")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	args := Options{Par2Args: []string{"-r10"}}
+	require.NoError(t, args.MaxDepth.Set("1"))
+	jobs, err := prog.Enumerate(t.Context(), "/data", args)
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	for _, job := range jobs {
+		require.NotContains(t, job.par2Path, "folder2")
+	}
+	require.Contains(t, logBuf.String(), "A directory was skipped due to the maximum depth")
+}
+
 // Expectation: The function should respect the ignore file rules.
 func Test_Service_Enumerate_IgnoreFileAll_Success(t *testing.T) {
 	t.Parallel()
@@ -1979,45 +2729,148 @@ func Test_Service_createPar2_CannotRemoveMarker_Error(t *testing.T) {
 		},
 	}
 
-	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	job := &Job{
+		workingDir:   "/data/folder",
+		markerPath:   "/data/folder/_par2cron",
+		par2Mode:     schema.CreateFolderMode,
+		par2Name:     "folder" + schema.Par2Extension,
+		par2Path:     "/data/folder/folder" + schema.Par2Extension,
+		par2Args:     []string{"-r10"},
+		par2Glob:     "*",
+		lockPath:     "/data/folder/folder" + schema.Par2Extension + schema.LockExtension,
+		manifestName: "folder" + schema.Par2Extension + schema.ManifestExtension,
+		manifestPath: "/data/folder/folder" + schema.Par2Extension + schema.ManifestExtension,
+	}
+
+	require.ErrorContains(t, prog.createPar2(t.Context(), job), "failed to delete marker file")
+	require.Equal(t, 1, called)
+
+	markerExists, _ := afero.Exists(fs, "/data/folder/_par2cron")
+	require.True(t, markerExists)
+}
+
+// The function should skip irrelevant files and return the correct files.
+func Test_Service_findElementsToProtect_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, fs.MkdirAll("/data/folder/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/file.txt", []byte("content"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/existing"+schema.Par2Extension, []byte("par2"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/existing.vol25+22.PAR2", []byte("par2"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/manifest"+schema.Par2Extension+schema.ManifestExtension, []byte("{}"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/manifest"+schema.Par2Extension+schema.LockExtension, []byte("{}"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/_par2cron", []byte(""), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	job := &Job{
+		workingDir:   "/data/folder",
+		markerPath:   "/data/folder/_par2cron",
+		par2Name:     "folder" + schema.Par2Extension,
+		par2Path:     "/data/folder/folder" + schema.Par2Extension,
+		par2Args:     []string{"-r10"},
+		par2Glob:     "*",
+		lockPath:     "/data/folder/folder" + schema.Par2Extension + schema.LockExtension,
+		manifestName: "folder" + schema.Par2Extension + schema.ManifestExtension,
+		manifestPath: "/data/folder/folder" + schema.Par2Extension + schema.ManifestExtension,
+	}
+
+	files, err := prog.findElementsToProtect(t.Context(), job)
+
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "/data/folder/file.txt", files[0].Path)
+	require.Equal(t, "file.txt", files[0].Name)
+}
+
+// Expectation: The excludes should be subtracted from the files included by the glob.
+func Test_Service_findElementsToProtect_GlobExclude_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		glob    string
+		exclude []string
+		want    []string
+	}{
+		{"no excludes", "*", nil, []string{"a.mkv", "b.mkv", "c.tmp", "d.log"}},
+		{"one exclude", "*", []string{"*.tmp"}, []string{"a.mkv", "b.mkv", "d.log"}},
+		{"two excludes", "*", []string{"*.tmp", "*.log"}, []string{"a.mkv", "b.mkv"}},
+		{"include and exclude", "*.mkv", []string{"b.*"}, []string{"a.mkv"}},
+		{"exclude without include", "*.mkv", []string{"*.tmp"}, []string{"a.mkv", "b.mkv"}},
+		{"shallow exclude on deep glob", "**/*", []string{"*.tmp"}, []string{"a.mkv", "b.mkv", "d.log", "sub/e.mkv", "sub/f.tmp"}},
+		{"deep exclude on deep glob", "**/*", []string{"**/*.tmp", "sub/*.mkv"}, []string{"a.mkv", "b.mkv", "d.log"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			require.NoError(t, fs.MkdirAll("/data/folder/sub", 0o755))
+			for _, name := range []string{"a.mkv", "b.mkv", "c.tmp", "d.log", "sub/e.mkv", "sub/f.tmp"} {
+				require.NoError(t, afero.WriteFile(fs, "/data/folder/"+name, []byte("content"), 0o644))
+			}
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/_par2cron", []byte(""), 0o644))
+
+			ls := logging.Options{
+				Logout: io.Discard,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+			_ = ls.LogLevel.Set("info")
+
+			prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
 
-	job := &Job{
-		workingDir:   "/data/folder",
-		markerPath:   "/data/folder/_par2cron",
-		par2Mode:     schema.CreateFolderMode,
-		par2Name:     "folder" + schema.Par2Extension,
-		par2Path:     "/data/folder/folder" + schema.Par2Extension,
-		par2Args:     []string{"-r10"},
-		par2Glob:     "*",
-		lockPath:     "/data/folder/folder" + schema.Par2Extension + schema.LockExtension,
-		manifestName: "folder" + schema.Par2Extension + schema.ManifestExtension,
-		manifestPath: "/data/folder/folder" + schema.Par2Extension + schema.ManifestExtension,
-	}
+			job := &Job{
+				workingDir:      "/data/folder",
+				markerPath:      "/data/folder/_par2cron",
+				par2Name:        "folder" + schema.Par2Extension,
+				par2Path:        "/data/folder/folder" + schema.Par2Extension,
+				par2Args:        []string{"-r10"},
+				par2Glob:        tt.glob,
+				par2GlobExclude: tt.exclude,
+				par2Mode:        schema.CreateFolderMode,
+				lockPath:        "/data/folder/folder" + schema.Par2Extension + schema.LockExtension,
+				manifestName:    "folder" + schema.Par2Extension + schema.ManifestExtension,
+				manifestPath:    "/data/folder/folder" + schema.Par2Extension + schema.ManifestExtension,
+			}
 
-	require.ErrorContains(t, prog.createPar2(t.Context(), job), "failed to delete marker file")
-	require.Equal(t, 1, called)
+			files, err := prog.findElementsToProtect(t.Context(), job)
+			require.NoError(t, err)
 
-	markerExists, _ := afero.Exists(fs, "/data/folder/_par2cron")
-	require.True(t, markerExists)
+			names := make([]string, 0, len(files))
+			for _, f := range files {
+				names = append(names, f.Name)
+			}
+			require.ElementsMatch(t, tt.want, names)
+		})
+	}
 }
 
-// The function should skip irrelevant files and return the correct files.
-func Test_Service_findElementsToProtect_Success(t *testing.T) {
+// Expectation: A job whose files are all excluded should have nothing to protect.
+func Test_Service_findElementsToProtect_GlobExclude_AllExcluded_Error(t *testing.T) {
 	t.Parallel()
 
 	fs := afero.NewMemMapFs()
 	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
-	require.NoError(t, fs.MkdirAll("/data/folder/folder", 0o755))
-	require.NoError(t, afero.WriteFile(fs, "/data/folder/file.txt", []byte("content"), 0o644))
-	require.NoError(t, afero.WriteFile(fs, "/data/folder/existing"+schema.Par2Extension, []byte("par2"), 0o644))
-	require.NoError(t, afero.WriteFile(fs, "/data/folder/existing.vol25+22.PAR2", []byte("par2"), 0o644))
-	require.NoError(t, afero.WriteFile(fs, "/data/folder/manifest"+schema.Par2Extension+schema.ManifestExtension, []byte("{}"), 0o644))
-	require.NoError(t, afero.WriteFile(fs, "/data/folder/manifest"+schema.Par2Extension+schema.LockExtension, []byte("{}"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/a.tmp", []byte("content"), 0o644))
 	require.NoError(t, afero.WriteFile(fs, "/data/folder/_par2cron", []byte(""), 0o644))
 
-	var logBuf testutil.SafeBuffer
 	ls := logging.Options{
-		Logout: &logBuf,
+		Logout: io.Discard,
 		Stdout: io.Discard,
 		Stderr: io.Discard,
 	}
@@ -2026,23 +2879,17 @@ func Test_Service_findElementsToProtect_Success(t *testing.T) {
 	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
 
 	job := &Job{
-		workingDir:   "/data/folder",
-		markerPath:   "/data/folder/_par2cron",
-		par2Name:     "folder" + schema.Par2Extension,
-		par2Path:     "/data/folder/folder" + schema.Par2Extension,
-		par2Args:     []string{"-r10"},
-		par2Glob:     "*",
-		lockPath:     "/data/folder/folder" + schema.Par2Extension + schema.LockExtension,
-		manifestName: "folder" + schema.Par2Extension + schema.ManifestExtension,
-		manifestPath: "/data/folder/folder" + schema.Par2Extension + schema.ManifestExtension,
+		workingDir:      "/data/folder",
+		markerPath:      "/data/folder/_par2cron",
+		par2Name:        "folder" + schema.Par2Extension,
+		par2Path:        "/data/folder/folder" + schema.Par2Extension,
+		par2Glob:        "*",
+		par2GlobExclude: []string{"*.tmp"},
+		par2Mode:        schema.CreateFolderMode,
 	}
 
-	files, err := prog.findElementsToProtect(t.Context(), job)
-
-	require.NoError(t, err)
-	require.Len(t, files, 1)
-	require.Equal(t, "/data/folder/file.txt", files[0].Path)
-	require.Equal(t, "file.txt", files[0].Name)
+	_, err := prog.findElementsToProtect(t.Context(), job)
+	require.ErrorIs(t, err, errNoFilesToProtect)
 }
 
 // Expectation: A deep glob should preserve the relative path in the element name in folder mode.
@@ -2504,6 +3351,163 @@ func Test_Service_findElementsToProtect_NoFilesToProtect_Error(t *testing.T) {
 	require.Contains(t, logBuf.String(), "Nothing to protect")
 }
 
+// Expectation: Only empty files should result in nothing to protect, as par2 cannot create a PAR2 set from them.
+func Test_Service_findElementsToProtect_OnlyEmptyFiles_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/empty1.txt", []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/empty2.txt", []byte{}, 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	job := &Job{
+		workingDir: "/data/folder",
+		markerPath: "/data/folder/_par2cron",
+		par2Mode:   schema.CreateFolderMode,
+		par2Glob:   "*",
+	}
+
+	_, err := prog.findElementsToProtect(t.Context(), job)
+
+	require.ErrorIs(t, err, errNoFilesToProtect)
+	require.Contains(t, logBuf.String(), "Nothing to protect")
+}
+
+// Expectation: With a strict glob, a glob matching nothing in a non-empty folder should fail the job and retain the marker.
+func Test_Service_Create_StrictGlob_NoMatch_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/file.mkv", []byte("content"), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	results, err := prog.Create(t.Context(), []string{"/data"}, Options{Par2Glob: "*.mvk", StrictGlob: true})
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+	require.ErrorIs(t, err, errGlobMatchedNone)
+	require.Equal(t, 1, results.Error)
+	require.Zero(t, called)
+	require.Contains(t, logBuf.String(), "Glob pattern matched no files in non-empty folder")
+
+	exists, err := afero.Exists(fs, "/data/folder/"+createMarkerPathPrefix)
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+// Expectation: With a strict glob, an empty folder should still result in nothing to protect.
+func Test_Service_findElementsToProtect_StrictGlob_EmptyFolder_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/_par2cron", []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/.hidden", []byte("content"), 0o644))
+
+	prog := NewService(fs, logging.NewLogger(logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}),
+		&testutil.MockRunner{}, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	job := &Job{
+		workingDir: "/data/folder",
+		markerPath: "/data/folder/_par2cron",
+		par2Mode:   schema.CreateFolderMode,
+		par2Glob:   "*.mkv",
+		strictGlob: true,
+	}
+
+	_, err := prog.findElementsToProtect(t.Context(), job)
+	require.ErrorIs(t, err, errNoFilesToProtect)
+}
+
+// Expectation: Without a strict glob, a glob matching nothing should result in nothing to protect.
+func Test_Service_findElementsToProtect_NoStrictGlob_NoMatch_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/file.mkv", []byte("content"), 0o644))
+
+	prog := NewService(fs, logging.NewLogger(logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}),
+		&testutil.MockRunner{}, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	job := &Job{
+		workingDir: "/data/folder",
+		markerPath: "/data/folder/_par2cron",
+		par2Mode:   schema.CreateFolderMode,
+		par2Glob:   "*.mvk",
+	}
+
+	_, err := prog.findElementsToProtect(t.Context(), job)
+	require.ErrorIs(t, err, errNoFilesToProtect)
+}
+
+// Expectation: Empty files should be skipped in file mode and for directories with only empty files in nested mode.
+func Test_Service_findElementsToProtect_EmptyFiles_Modes_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder/sub1", 0o755))
+	require.NoError(t, fs.MkdirAll("/data/folder/sub2", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/sub1/data.txt", []byte("content"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/sub1/empty.txt", []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/sub2/empty.txt", []byte{}, 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	job := &Job{
+		workingDir: "/data/folder",
+		markerPath: "/data/folder/_par2cron",
+		par2Mode:   schema.CreateNestedMode,
+		par2Glob:   "**/*",
+	}
+
+	files, err := prog.findElementsToProtect(t.Context(), job)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"/data/folder/sub1/data.txt", "/data/folder/sub1/empty.txt"}, getPaths(files))
+
+	job.par2Mode = schema.CreateFileMode
+	files, err = prog.findElementsToProtect(t.Context(), job)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"/data/folder/sub1/data.txt"}, getPaths(files))
+}
+
 // Expectation: Function should reject deep (/) glob patterns in recursive creation mode.
 func Test_Service_findElementsToProtect_DeepGlobInRecursiveMode_Error(t *testing.T) {
 	t.Parallel()
@@ -3480,6 +4484,106 @@ func Test_Service_runCreate_PostVerification_Failure_Error(t *testing.T) {
 }
 
 // Expectation: The function should call par2 with correct arguments.
+// Expectation: The function should add a block count computed from the target block size.
+func Test_Service_runCreate_TargetBlockSize_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+
+	var capturedArgs []string
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			capturedArgs = slices.Clone(args)
+
+			return afero.WriteFile(fs, "/data/folder/test"+schema.Par2Extension, []byte("par2data"), 0o644)
+		},
+	}
+
+	ls := logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	job := &Job{
+		workingDir:      "/data/folder",
+		markerPath:      "/data/folder/_par2cron",
+		par2Mode:        schema.CreateFolderMode,
+		par2Name:        "test" + schema.Par2Extension,
+		par2Path:        "/data/folder/test" + schema.Par2Extension,
+		par2Args:        []string{"-r10"},
+		lockPath:        "/data/folder/test" + schema.Par2Extension + schema.LockExtension,
+		manifestName:    "test" + schema.Par2Extension + schema.ManifestExtension,
+		manifestPath:    "/data/folder/test" + schema.Par2Extension + schema.ManifestExtension,
+		targetBlockSize: 4 << 20,
+	}
+
+	files := []schema.FsElement{
+		{Path: "/data/folder/a.mkv", Name: "a.mkv", Size: 6 << 30},
+		{Path: "/data/folder/b.mkv", Name: "b.mkv", Size: 2<<30 + 1},
+	}
+
+	require.NoError(t, prog.runCreate(t.Context(), job, files))
+	require.Equal(t, []string{"create", "-r10", "-b2049", "--", job.par2Path, "/data/folder/a.mkv", "/data/folder/b.mkv"}, capturedArgs)
+	require.Equal(t, []string{"-r10"}, job.par2Args)
+
+	data, err := afero.ReadFile(fs, job.manifestPath)
+	require.NoError(t, err)
+	var mf schema.Manifest
+	require.NoError(t, json.Unmarshal(data, &mf))
+	require.Equal(t, []string{"-r10", "-b2049"}, mf.Creation.Args)
+
+	job.par2Args = []string{"-r10", "-s1048576"}
+	require.NoError(t, prog.runCreate(t.Context(), job, files))
+	require.Equal(t, []string{"create", "-r10", "-s1048576", "--", job.par2Path, "/data/folder/a.mkv", "/data/folder/b.mkv"}, capturedArgs)
+}
+
+// Expectation: The block count should be the total size over the target block size, within par2's limits.
+func Test_par2BlockCount_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		totalSize  int64
+		files      int
+		targetSize int64
+		expect     int
+	}{
+		{"exact multiple", 8 << 30, 1, 4 << 20, 2048},
+		{"rounded up", 8<<30 + 1, 1, 4 << 20, 2049},
+		{"smaller than a block", 1000, 1, 4 << 20, 1},
+		{"at least the files", 10 << 20, 50, 4 << 20, 50},
+		{"at most the maximum", 1 << 40, 1, 1 << 20, par2MaxBlockCount},
+		{"no total size", 0, 3, 4 << 20, 0},
+		{"no target size", 8 << 30, 1, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expect, par2BlockCount(tt.totalSize, tt.files, tt.targetSize))
+		})
+	}
+}
+
+// Expectation: The protected size should include the contents of directories (recursive mode).
+func Test_Service_protectedSize_Recursive_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder/sub/deep", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/sub/a.bin", make([]byte, 100), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/sub/deep/b.bin", make([]byte, 50), 0o644))
+
+	ls := logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	size, files := prog.protectedSize([]schema.FsElement{
+		{Path: "/data/folder/top.bin", Size: 25},
+		{Path: "/data/folder/sub", IsDir: true, Size: 4096},
+	})
+	require.Equal(t, int64(175), size)
+	require.Equal(t, 3, files)
+}
+
 func Test_Service_runCreate_CorrectArgs_Success(t *testing.T) {
 	t.Parallel()
 
@@ -3652,6 +4756,8 @@ func Test_Service_runCreate_ManifestUnmarshal_Success(t *testing.T) {
 		lockPath:     "/data/folder/test" + schema.Par2Extension + schema.LockExtension,
 		manifestName: "test" + schema.Par2Extension + schema.ManifestExtension,
 		manifestPath: "/data/folder/test" + schema.Par2Extension + schema.ManifestExtension,
+
+		par2GlobExclude: []string{"*.tmp.txt"},
 	}
 
 	files := []schema.FsElement{
@@ -3677,6 +4783,7 @@ func Test_Service_runCreate_ManifestUnmarshal_Success(t *testing.T) {
 	require.Equal(t, schema.Par2Version, mf.Creation.Par2Version)
 	require.Equal(t, schema.CreateFolderMode, mf.Creation.Mode)
 	require.Equal(t, "*.txt", mf.Creation.Glob)
+	require.Equal(t, []string{"*.tmp.txt"}, mf.Creation.GlobExclude)
 	require.Equal(t, []string{"-r10"}, mf.Creation.Args)
 	require.False(t, mf.Creation.Time.IsZero())
 	require.Greater(t, mf.Creation.Duration, time.Duration(0))
@@ -3805,6 +4912,53 @@ func Test_Service_runCreate_Par2Fails_Error(t *testing.T) {
 	require.False(t, manifestExists)
 }
 
+// Expectation: A stamp file of a previous creation should be removed when the creation fails.
+func Test_Service_runCreate_Par2Fails_RemovesStamp_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/file.txt", []byte("content"), 0o644))
+	require.NoError(t, util.WriteStamp(fs, "/data/folder/"+schema.StampFile, schema.NewStamp("test"+schema.Par2Extension, "create")))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			return testutil.CreateExitError(t, ctx, 5)
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	job := &Job{
+		workingDir:   "/data/folder",
+		markerPath:   "/data/folder/_par2cron",
+		par2Mode:     schema.CreateFolderMode,
+		par2Name:     "test" + schema.Par2Extension,
+		par2Path:     "/data/folder/test" + schema.Par2Extension,
+		par2Glob:     "*",
+		lockPath:     "/data/folder/test" + schema.Par2Extension + schema.LockExtension,
+		manifestName: "test" + schema.Par2Extension + schema.ManifestExtension,
+		manifestPath: "/data/folder/test" + schema.Par2Extension + schema.ManifestExtension,
+		stampFile:    schema.StampFile,
+		stampPath:    "/data/folder/" + schema.StampFile,
+	}
+
+	files := []schema.FsElement{
+		{Path: "/data/folder/file.txt", Name: "file.txt"},
+	}
+
+	require.Error(t, prog.runCreate(t.Context(), job, files))
+
+	stampExists, _ := afero.Exists(fs, job.stampPath)
+	require.False(t, stampExists)
+}
+
 // Expectation: The function should error if manifest write fails.
 func Test_Service_runCreate_ManifestWriteFails_Error(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,65 @@
+//go:build !windows
+
+package create
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: Named pipes and symlinks should be skipped, empty and non-empty regular files selected.
+func Test_Service_findElementsToProtect_SpecialFiles_Success(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	workingDir := filepath.Join(root, "project")
+	require.NoError(t, os.MkdirAll(workingDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, "real.txt"), []byte("content"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, "empty.txt"), []byte{}, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, "_par2cron"), []byte(""), 0o600))
+	require.NoError(t, syscall.Mkfifo(filepath.Join(workingDir, "pipe.txt"), 0o600))
+	require.NoError(t, os.Symlink(filepath.Join(workingDir, "real.txt"), filepath.Join(workingDir, "link.txt")))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("debug")
+
+	prog := NewService(afero.NewOsFs(), logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	job := &Job{
+		workingDir: workingDir,
+		markerPath: filepath.Join(workingDir, "_par2cron"),
+		par2Mode:   schema.CreateFolderMode,
+		par2Glob:   "*.txt",
+	}
+
+	files, err := prog.findElementsToProtect(t.Context(), job)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(workingDir, "empty.txt"), filepath.Join(workingDir, "real.txt")}, getPaths(files))
+
+	require.Contains(t, logBuf.String(), "special file was skipped")
+	require.Contains(t, logBuf.String(), "symbolic link was skipped")
+
+	job.excludeEmpty = true
+	files, err = prog.findElementsToProtect(t.Context(), job)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(workingDir, "real.txt")}, getPaths(files))
+	require.Contains(t, logBuf.String(), "empty file was skipped")
+}
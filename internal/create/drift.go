@@ -0,0 +1,119 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+)
+
+// reportDrift compares the elements that would be protected by the job with
+// those recorded in the creation manifest of the same-named PAR2 (or bundle)
+// at par2Path, logging the elements that are new or were removed since. It
+// is best-effort, PAR2 sets without a (readable) creation record are skipped.
+func (prog *Service) reportDrift(ctx context.Context, job *Job, par2Path string, elements []schema.FsElement) {
+	mf, err := prog.readExistingManifest(ctx, par2Path, job.sidecarNames, job.maxManifestSize)
+	if err != nil {
+		logger := prog.creationLogger(ctx, job, par2Path)
+		logger.Debug("Failed to read par2cron manifest of same-named PAR2 (not comparing files)", "error", err)
+
+		return
+	}
+	if mf == nil || mf.Creation == nil {
+		return
+	}
+
+	added, removed := diffElements(mf.Creation.Elements, elements)
+	if len(added) == 0 && len(removed) == 0 {
+		logger := prog.creationLogger(ctx, job, par2Path)
+		logger.Debug("Same-named PAR2 protects the files to protect as found in folder")
+
+		return
+	}
+
+	logger := prog.creationLogger(ctx, job, par2Path)
+	logger.Warn("Same-named PAR2 protects other files than found in folder (consider re-creating)",
+		"newFiles", len(added), "removedFiles", len(removed))
+
+	for _, name := range added {
+		logger.Info("File is new since creation of same-named PAR2 (not protected)", "name", name)
+	}
+	for _, name := range removed {
+		logger.Info("File was removed since creation of same-named PAR2 (or is no longer matched)", "name", name)
+	}
+}
+
+// readExistingManifest returns the par2cron manifest of the PAR2 (or bundle)
+// at par2Path, or nil if the PAR2 is not managed by par2cron (no manifest).
+// A manifest larger than maxSize (0 for no limit) is refused as invalid.
+func (prog *Service) readExistingManifest(ctx context.Context, par2Path string, names util.SidecarNames, maxSize int64) (*schema.Manifest, error) {
+	var data []byte
+
+	if util.IsPar2Bundle(par2Path) {
+		bun, err := prog.bundler.Open(ctx, prog.fsys, par2Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open bundle: %w", err)
+		}
+		defer bun.Close()
+
+		data, err = bun.Manifest(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle manifest: %w", err)
+		}
+	} else {
+		var err error
+
+		data, err = afero.ReadFile(prog.fsys, names.ManifestPath(par2Path))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil //nolint:nilnil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read manifest: %w", err)
+		}
+	}
+
+	mf, err := util.UnmarshalManifest(data, maxSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+	}
+
+	return mf, nil
+}
+
+// diffElements returns the sorted names of the current elements that are not
+// recorded (added), and of the recorded elements that are not current (removed).
+func diffElements(recorded []schema.FsElement, current []schema.FsElement) ([]string, []string) {
+	recordedNames := make(map[string]struct{}, len(recorded))
+	for _, el := range recorded {
+		if el.Name != "" {
+			recordedNames[el.Name] = struct{}{}
+		}
+	}
+
+	currentNames := make(map[string]struct{}, len(current))
+	for _, el := range current {
+		if el.Name != "" {
+			currentNames[el.Name] = struct{}{}
+		}
+	}
+
+	var added, removed []string
+	for name := range currentNames {
+		if _, ok := recordedNames[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	for name := range recordedNames {
+		if _, ok := currentNames[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+
+	return added, removed
+}
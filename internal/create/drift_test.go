@@ -0,0 +1,158 @@
+package create
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func newDriftTestJob() *Job {
+	return &Job{
+		workingDir:   "/data/folder",
+		markerPath:   "/data/folder/_par2cron",
+		par2Mode:     schema.CreateFolderMode,
+		par2Name:     "test" + schema.Par2Extension,
+		par2Path:     "/data/folder/test" + schema.Par2Extension,
+		par2Args:     []string{"-r10"},
+		par2Glob:     "*",
+		lockPath:     "/data/folder/test" + schema.Par2Extension + schema.LockExtension,
+		manifestName: "test" + schema.Par2Extension + schema.ManifestExtension,
+		manifestPath: "/data/folder/test" + schema.Par2Extension + schema.ManifestExtension,
+	}
+}
+
+func writeDriftTestManifest(t *testing.T, fs afero.Fs, names ...string) {
+	t.Helper()
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.Creation = schema.NewCreationManifest()
+	for _, name := range names {
+		mf.Creation.Elements = append(mf.Creation.Elements, schema.FsElement{Name: name})
+	}
+
+	data, err := json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/test"+schema.Par2Extension+schema.ManifestExtension, data, 0o644))
+}
+
+// Expectation: The new and removed files should be logged when a same-named PAR2 exists.
+func Test_Service_createCombined_Drift_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/test"+schema.Par2Extension, []byte("existing"), 0o644))
+	writeDriftTestManifest(t, fs, "a.txt", "c.txt")
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var called bool
+	runner := &testutil.MockRunner{
+		RunFunc: func(_ context.Context, _ string, _ []string, _ string, _ io.Writer, _ io.Writer) error {
+			called = true
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	files := []schema.FsElement{
+		{Path: "/data/folder/a.txt", Name: "a.txt"},
+		{Path: "/data/folder/b.txt", Name: "b.txt"},
+	}
+
+	require.NoError(t, prog.createCombined(t.Context(), newDriftTestJob(), files))
+	require.False(t, called)
+
+	logs := logBuf.String()
+	require.Contains(t, logs, "Same-named PAR2 protects other files than found in folder")
+	require.Contains(t, logs, "newFiles=1")
+	require.Contains(t, logs, "removedFiles=1")
+	require.Contains(t, logs, "File is new since creation of same-named PAR2 (not protected)")
+	require.Contains(t, logs, "name=b.txt")
+	require.Contains(t, logs, "File was removed since creation of same-named PAR2")
+	require.Contains(t, logs, "name=c.txt")
+}
+
+// Expectation: No drift should be logged when the recorded files match.
+func Test_Service_createCombined_NoDrift_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/test"+schema.Par2Extension, []byte("existing"), 0o644))
+	writeDriftTestManifest(t, fs, "a.txt")
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	files := []schema.FsElement{
+		{Path: "/data/folder/a.txt", Name: "a.txt"},
+	}
+
+	require.NoError(t, prog.createCombined(t.Context(), newDriftTestJob(), files))
+	require.Contains(t, logBuf.String(), "Same-named PAR2 already exists in folder")
+	require.NotContains(t, logBuf.String(), "Same-named PAR2 protects other files")
+}
+
+// Expectation: No drift should be logged for an existing PAR2 without a manifest.
+func Test_Service_createCombined_DriftNoManifest_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/test"+schema.Par2Extension, []byte("existing"), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("debug")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	files := []schema.FsElement{
+		{Path: "/data/folder/a.txt", Name: "a.txt"},
+	}
+
+	require.NoError(t, prog.createCombined(t.Context(), newDriftTestJob(), files))
+	require.NotContains(t, logBuf.String(), "Same-named PAR2 protects")
+	require.NotContains(t, logBuf.String(), "Failed to read par2cron manifest")
+}
+
+// Expectation: The added and removed names should be returned sorted, ignoring empty names.
+func Test_diffElements_Success(t *testing.T) {
+	t.Parallel()
+
+	recorded := []schema.FsElement{{Name: "b"}, {Name: "a"}, {Name: ""}, {Name: "d"}}
+	current := []schema.FsElement{{Name: "e"}, {Name: "a"}, {Name: "c"}, {Name: ""}}
+
+	added, removed := diffElements(recorded, current)
+
+	require.Equal(t, []string{"c", "e"}, added)
+	require.Equal(t, []string{"b", "d"}, removed)
+}
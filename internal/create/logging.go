@@ -1,6 +1,7 @@
 package create
 
 import (
+	"cmp"
 	"context"
 
 	"github.com/desertwitch/par2cron/internal/logging"
@@ -15,7 +16,7 @@ func (prog *Service) creationLogger(ctx context.Context, job *Job, path any) *lo
 	}
 
 	if job != nil {
-		logElems = append(logElems, "job", job.markerPath)
+		logElems = append(logElems, "job", cmp.Or(job.markerPath, job.par2Path))
 
 		if ctx.Value(schema.PosKey) != nil {
 			logElems = append(logElems, "job_position", ctx.Value(schema.PosKey))
@@ -37,6 +38,20 @@ func (prog *Service) creationLogger(ctx context.Context, job *Job, path any) *lo
 	return prog.log.With(logElems...)
 }
 
+func (prog *Service) recreateLogger(ctx context.Context, target *recreateTarget) *logging.Logger {
+	logElems := []any{"job", target.par2Path}
+
+	if ctx.Value(schema.PosKey) != nil {
+		logElems = append(logElems, "job_position", ctx.Value(schema.PosKey))
+	}
+
+	logElems = append(logElems,
+		"recordedArgs", target.manifest.Creation.Args,
+		"args", target.args)
+
+	return prog.log.With(logElems...)
+}
+
 func (prog *Service) markerLogger(job any, key any, value any) *logging.Logger {
 	logElems := []any{}
 
@@ -26,6 +26,31 @@ type MarkerConfig struct {
 	HideFiles     *bool             `yaml:"hidden"`
 	PersistMarker *bool             `yaml:"persist"`
 	Bundle        *bool             `yaml:"bundle"`
+	ExcludeEmpty  *bool             `yaml:"exclude-empty"`
+	AdoptExisting *bool             `yaml:"adopt-existing"`
+	Tags          *[]string         `yaml:"tags"`
+
+	ProtectCreationManifest *bool `yaml:"protect-creation-manifest"`
+
+	Par2GlobExclude *[]string `yaml:"glob-exclude"`
+
+	VerifyInterval *flags.Duration `yaml:"verify-interval"`
+
+	NoAutoRepair *bool `yaml:"no-auto-repair"`
+
+	SidecarNames        util.SidecarNames `yaml:"-"`
+	StampFile           string            `yaml:"-"` // empty for no stamp file
+	FileList            bool              `yaml:"-"`
+	TargetBlockSize     int64             `yaml:"-"` // zero for no block count
+	MaxSetSize          int64             `yaml:"-"` // zero for no subsets
+	MaxManifestSize     int64             `yaml:"-"` // zero for no limit
+	RetrySingleThreaded bool              `yaml:"-"`
+	RecordMetadata      bool              `yaml:"-"`
+	FolderFingerprint   bool              `yaml:"-"`
+
+	// Marker is the marker file with the settings of its contents, as
+	// recorded into the creation manifest (nil without a marker file).
+	Marker *schema.CreationMarker `yaml:"-"`
 }
 
 func NewMarkerConfig(markerPath string, opts Options) *MarkerConfig {
@@ -34,20 +59,43 @@ func NewMarkerConfig(markerPath string, opts Options) *MarkerConfig {
 	par2Name := filepath.Base(filepath.Dir(markerPath)) + schema.Par2Extension
 	par2Args := slices.Clone(opts.Par2Args)
 	par2Glob := opts.Par2Glob
+	par2GlobExclude := slices.Clone(opts.Par2GlobExclude)
 	par2Mode := opts.Par2Mode
 	par2Verify := opts.Par2Verify
 	hideFiles := opts.HideFiles
 	asBundle := opts.Bundle
+	excludeEmpty := opts.ExcludeEmpty
+	adoptExisting := opts.AdoptExisting
+	protectCreationManifest := opts.ProtectCreationManifest
 	persistMarker := false
+	verifyInterval := flags.Duration{}
+	noAutoRepair := false
 
 	cfg.Par2Name = &par2Name
 	cfg.Par2Args = &par2Args
 	cfg.Par2Glob = &par2Glob
+	cfg.Par2GlobExclude = &par2GlobExclude
 	cfg.Par2Mode = &par2Mode
 	cfg.Par2Verify = &par2Verify
 	cfg.HideFiles = &hideFiles
 	cfg.Bundle = &asBundle
+	cfg.ExcludeEmpty = &excludeEmpty
+	cfg.AdoptExisting = &adoptExisting
+	cfg.ProtectCreationManifest = &protectCreationManifest
 	cfg.PersistMarker = &persistMarker
+	cfg.VerifyInterval = &verifyInterval
+	cfg.NoAutoRepair = &noAutoRepair
+	cfg.SidecarNames = opts.SidecarNames
+	if opts.WriteStamp {
+		cfg.StampFile = opts.StampFile
+	}
+	cfg.FileList = opts.WriteFileList
+	cfg.TargetBlockSize = opts.TargetBlockSize.Value
+	cfg.MaxSetSize = opts.MaxSetSize.Value
+	cfg.MaxManifestSize = opts.MaxManifestSize
+	cfg.RetrySingleThreaded = opts.RetrySingleThreaded
+	cfg.RecordMetadata = opts.RecordMetadata
+	cfg.FolderFingerprint = opts.FolderFingerprint
 
 	return cfg
 }
@@ -63,6 +111,24 @@ func (m *MarkerConfig) Validate() error {
 		return schema.ErrUnsupportedGlob
 	}
 
+	if m.Par2GlobExclude != nil {
+		if err := validateGlobExcludes(*m.Par2GlobExclude, m.Par2Mode.Value); err != nil {
+			return err
+		}
+	}
+
+	if m.VerifyInterval != nil && m.VerifyInterval.Value < 0 {
+		return fmt.Errorf("verify-interval: must not be negative, got %s", m.VerifyInterval.Raw)
+	}
+
+	if m.Tags != nil {
+		for _, tag := range *m.Tags {
+			if err := schema.ValidateTag(tag); err != nil {
+				return fmt.Errorf("tags: %w", err)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -78,6 +144,14 @@ func (prog *Service) parseMarkerFile(markerPath string, opts Options) (*MarkerCo
 		return nil, fmt.Errorf("failed to parse content: %w", err)
 	}
 
+	// The marker arguments are held to the allowlist, as anyone able to write
+	// a marker in the data tree could otherwise point par2 at other paths.
+	if opts.AllowedPar2Args != nil {
+		if err := util.CheckPar2Args(*cfg.Par2Args, opts.AllowedPar2Args); err != nil {
+			return nil, fmt.Errorf("failed to validate content: args: %w", err)
+		}
+	}
+
 	prog.considerRecursiveMarker(markerPath, cfg)
 
 	if err := cfg.Validate(); err != nil {
@@ -131,6 +205,7 @@ func (prog *Service) parseMarkerContent(markerPath string, cfg *MarkerConfig) er
 	if err := decoder.Decode(&yamlConfig); err != nil && !errors.Is(err, io.EOF) {
 		return fmt.Errorf("failed to decode: %w", err)
 	}
+	cfg.Marker = yamlConfig.creationMarker(filepath.Base(markerPath))
 
 	if yamlConfig.Par2Name != nil {
 		name := *yamlConfig.Par2Name
@@ -158,6 +233,13 @@ func (prog *Service) parseMarkerContent(markerPath string, cfg *MarkerConfig) er
 		cfg.Par2Glob = yamlConfig.Par2Glob
 	}
 
+	if yamlConfig.Par2GlobExclude != nil {
+		logger := prog.markerLogger(markerPath, "glob-exclude", *yamlConfig.Par2GlobExclude)
+		logger.Debug("Parsed setting from marker file contents")
+
+		cfg.Par2GlobExclude = yamlConfig.Par2GlobExclude
+	}
+
 	if yamlConfig.Par2Mode != nil {
 		logger := prog.markerLogger(markerPath, "mode", yamlConfig.Par2Mode.Value)
 		logger.Debug("Parsed setting from marker file contents")
@@ -193,9 +275,97 @@ func (prog *Service) parseMarkerContent(markerPath string, cfg *MarkerConfig) er
 		cfg.Bundle = yamlConfig.Bundle
 	}
 
+	if yamlConfig.ExcludeEmpty != nil {
+		logger := prog.markerLogger(markerPath, "exclude-empty", *yamlConfig.ExcludeEmpty)
+		logger.Debug("Parsed setting from marker file contents")
+
+		cfg.ExcludeEmpty = yamlConfig.ExcludeEmpty
+	}
+
+	if yamlConfig.AdoptExisting != nil {
+		logger := prog.markerLogger(markerPath, "adopt-existing", *yamlConfig.AdoptExisting)
+		logger.Debug("Parsed setting from marker file contents")
+
+		cfg.AdoptExisting = yamlConfig.AdoptExisting
+	}
+
+	if yamlConfig.ProtectCreationManifest != nil {
+		logger := prog.markerLogger(markerPath, "protect-creation-manifest", *yamlConfig.ProtectCreationManifest)
+		logger.Debug("Parsed setting from marker file contents")
+
+		cfg.ProtectCreationManifest = yamlConfig.ProtectCreationManifest
+	}
+
+	if yamlConfig.Tags != nil {
+		logger := prog.markerLogger(markerPath, "tags", *yamlConfig.Tags)
+		logger.Debug("Parsed setting from marker file contents")
+
+		cfg.Tags = yamlConfig.Tags
+	}
+
+	if yamlConfig.VerifyInterval != nil {
+		logger := prog.markerLogger(markerPath, "verify-interval", yamlConfig.VerifyInterval.Value.String())
+		logger.Debug("Parsed setting from marker file contents")
+
+		cfg.VerifyInterval = yamlConfig.VerifyInterval
+	}
+
+	if yamlConfig.NoAutoRepair != nil {
+		logger := prog.markerLogger(markerPath, "no-auto-repair", *yamlConfig.NoAutoRepair)
+		logger.Debug("Parsed setting from marker file contents")
+
+		cfg.NoAutoRepair = yamlConfig.NoAutoRepair
+	}
+
 	return nil
 }
 
+// creationMarker returns the settings given in the contents of a marker file
+// (as decoded into the configuration) for recording into the creation manifest.
+// They are copied, as the effective configuration is modified after parsing.
+func (m *MarkerConfig) creationMarker(file string) *schema.CreationMarker {
+	cm := &schema.CreationMarker{
+		File:                    file,
+		Name:                    clonePtr(m.Par2Name),
+		Args:                    cloneSlicePtr(m.Par2Args),
+		Glob:                    clonePtr(m.Par2Glob),
+		GlobExclude:             cloneSlicePtr(m.Par2GlobExclude),
+		Verify:                  clonePtr(m.Par2Verify),
+		Hidden:                  clonePtr(m.HideFiles),
+		Persist:                 clonePtr(m.PersistMarker),
+		Bundle:                  clonePtr(m.Bundle),
+		ExcludeEmpty:            clonePtr(m.ExcludeEmpty),
+		AdoptExisting:           clonePtr(m.AdoptExisting),
+		ProtectCreationManifest: clonePtr(m.ProtectCreationManifest),
+		Tags:                    cloneSlicePtr(m.Tags),
+		NoAutoRepair:            clonePtr(m.NoAutoRepair),
+	}
+	if m.Par2Mode != nil {
+		cm.Mode = new(m.Par2Mode.Value)
+	}
+	if m.VerifyInterval != nil {
+		cm.VerifyInterval = new(m.VerifyInterval.Value)
+	}
+
+	return cm
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+
+	return new(*p)
+}
+
+func cloneSlicePtr[T any](p *[]T) *[]T {
+	if p == nil {
+		return nil
+	}
+
+	return new(slices.Clone(*p))
+}
+
 func (prog *Service) considerRecursiveMarker(markerPath string, cfg *MarkerConfig) {
 	if cfg.Par2Mode.Value != schema.CreateRecursiveMode && slices.Contains(*cfg.Par2Args, "-R") {
 		logger := prog.markerLogger(markerPath, nil, nil)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/bmatcuk/doublestar/v4"
 	"github.com/desertwitch/par2cron/internal/flags"
@@ -35,6 +36,10 @@ func Test_NewMarkerConfig_Success(t *testing.T) {
 	require.False(t, *cfg.HideFiles)
 	require.False(t, *cfg.PersistMarker)
 	require.False(t, *cfg.Bundle)
+	require.False(t, *cfg.ExcludeEmpty)
+	require.False(t, *cfg.AdoptExisting)
+	require.Zero(t, cfg.VerifyInterval.Value)
+	require.False(t, *cfg.NoAutoRepair)
 }
 
 // Expectation: Validation should pass when mode is recursive with a shallow glob.
@@ -89,6 +94,36 @@ func Test_MarkerConfig_Validate_InvalidGlobPattern_Error(t *testing.T) {
 	require.ErrorIs(t, cfg.Validate(), doublestar.ErrBadPattern)
 }
 
+// Expectation: Validation should fail when the verification interval is negative.
+func Test_MarkerConfig_Validate_NegativeVerifyInterval_Error(t *testing.T) {
+	t.Parallel()
+
+	cfg := &MarkerConfig{
+		Par2Glob:       new("*"),
+		Par2Mode:       &flags.CreateMode{},
+		VerifyInterval: &flags.Duration{Raw: "-1h", Value: -time.Hour},
+	}
+	require.NoError(t, cfg.Par2Mode.Set(schema.CreateFolderMode))
+
+	require.ErrorContains(t, cfg.Validate(), "verify-interval")
+}
+
+// Expectation: Validation should fail when a tag is empty or contains whitespace.
+func Test_MarkerConfig_Validate_InvalidTag_Error(t *testing.T) {
+	t.Parallel()
+
+	cfg := &MarkerConfig{
+		Par2Glob: new("*"),
+		Par2Mode: &flags.CreateMode{},
+		Tags:     &[]string{"tier:critical", "media video"},
+	}
+	require.NoError(t, cfg.Par2Mode.Set(schema.CreateFolderMode))
+
+	err := cfg.Validate()
+	require.ErrorIs(t, err, schema.ErrInvalidTag)
+	require.ErrorContains(t, err, "tags")
+}
+
 // Expectation: An error should be returned when recursive mode and deep glob are combined via marker file.
 func Test_Service_parseMarkerFile_RecursiveDeepGlob_Error(t *testing.T) {
 	t.Parallel()
@@ -143,6 +178,37 @@ glob: "**/*.mp4"`
 	require.Nil(t, cfg)
 }
 
+// Expectation: Marker arguments not permitted by the allowlist should be rejected.
+func Test_Service_parseMarkerFile_ArgsNotAllowed_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+
+	yamlContent := `args: ["-r10", "-B/etc"]`
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(yamlContent), 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+
+	args := Options{Par2Args: []string{"-r5"}, AllowedPar2Args: util.DefaultAllowedPar2Args}
+	cfg, err := prog.parseMarkerFile("/data/folder/"+createMarkerPathPrefix, args)
+
+	require.ErrorIs(t, err, schema.ErrPar2ArgNotAllowed)
+	require.Nil(t, cfg)
+
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(`args: ["-r10"]`), 0o644))
+	cfg, err = prog.parseMarkerFile("/data/folder/"+createMarkerPathPrefix, args)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"-r10"}, *cfg.Par2Args)
+}
+
 // Expectation: A non-nil configuration should be returned.
 func Test_Service_parseMarkerFile_ValidMarker_Success(t *testing.T) {
 	t.Parallel()
@@ -179,11 +245,17 @@ func Test_Service_parseMarkerFile_WithYAMLConfig_Success(t *testing.T) {
 	yamlContent := `name: "custom.par2"
 args: ["-r15", "-n5"]
 glob: "*.txt"
+glob-exclude: ["*.tmp.txt"]
 mode: "file"
 verify: true
 hidden: true
 persist: true
-bundle: true`
+bundle: true
+exclude-empty: true
+adopt-existing: true
+protect-creation-manifest: true
+verify-interval: "7d"
+no-auto-repair: true`
 	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(yamlContent), 0o644))
 
 	var logBuf testutil.SafeBuffer
@@ -204,11 +276,17 @@ bundle: true`
 	require.Equal(t, "custom"+schema.Par2Extension, *cfg.Par2Name)
 	require.Equal(t, []string{"-r15", "-n5"}, *cfg.Par2Args)
 	require.Equal(t, "*.txt", *cfg.Par2Glob)
+	require.Equal(t, []string{"*.tmp.txt"}, *cfg.Par2GlobExclude)
 	require.Equal(t, "file", cfg.Par2Mode.Value)
 	require.True(t, *cfg.Par2Verify)
 	require.True(t, *cfg.HideFiles)
 	require.True(t, *cfg.PersistMarker)
 	require.True(t, *cfg.Bundle)
+	require.True(t, *cfg.ExcludeEmpty)
+	require.True(t, *cfg.AdoptExisting)
+	require.True(t, *cfg.ProtectCreationManifest)
+	require.Equal(t, 7*24*time.Hour, cfg.VerifyInterval.Value)
+	require.True(t, *cfg.NoAutoRepair)
 }
 
 // Expectation: The YAML configuration should reject an unknown mode.
@@ -0,0 +1,598 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/flags"
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/desertwitch/par2cron/internal/verify"
+	"github.com/spf13/afero"
+)
+
+// recreateStagePrefix is prepended to the name of a PAR2 set being re-created,
+// so that the new set is created next to the old one (which stays in place)
+// and only replaces it once it was created (and verified) with success.
+const recreateStagePrefix = ".par2cron-recreate."
+
+// recreateAsidePrefix is prepended to the names of the files of the old set
+// while the staged set replaces it, so that the old set can be restored if
+// replacing it fails, and is only removed once the new set is in place.
+const recreateAsidePrefix = ".par2cron-replaced."
+
+// par2DefaultRedundancy is the redundancy of par2 without an -r argument.
+const par2DefaultRedundancy = 0.05
+
+var (
+	errNotRecreatable = errors.New("not re-creatable")
+	errRecreateDryRun = errors.New("not re-created (--dry-run)")
+)
+
+// RecreateOptions are the options of re-creating PAR2 sets whose recorded
+// par2 arguments are outdated, with the (current) options of creation.
+type RecreateOptions struct {
+	Options
+
+	OlderRedundancyThan flags.Redundancy
+	DryRun              bool
+}
+
+// recreateTarget is a PAR2 set to be re-created with the current arguments.
+type recreateTarget struct {
+	workingDir   string
+	par2Name     string
+	par2Path     string
+	manifestPath string
+	lockPath     string
+	isBundle     bool
+	args         []string
+	manifest     *schema.Manifest
+}
+
+// Recreate re-creates the PAR2 sets below the root directories that were
+// created with other par2 arguments than the current ones (or, with
+// --older-redundancy-than, with less redundancy). Each new set is created
+// next to the old one, which is only replaced once the new set is complete,
+// so that a failure leaves the old set intact.
+func (prog *Service) Recreate(ctx context.Context, rootDirs []string, opts RecreateOptions) (util.ResultTracker, error) {
+	errs := []error{}
+	results := util.NewResultTracker()
+	logger := prog.creationLogger(ctx, nil, nil)
+
+	if util.IsPaused(prog.fsys, opts.PauseFile) {
+		logger.Info("Paused by sentinel file (nothing done; will check again next run)", "pauseFile", opts.PauseFile)
+
+		return results, nil
+	}
+
+	if opts.DryRun {
+		logger.Info("Running in dry-run mode (PAR2 sets will not be re-created)")
+	}
+
+	vs := verify.NewService(prog.fsys, prog.log, prog.runner, prog.bundler, prog.cacher)
+	va := verify.Options{SkipNotCreated: true, IgnoreNames: opts.IgnoreNames, SidecarNames: opts.SidecarNames, MaxDepth: opts.MaxDepth, MaxManifestSize: opts.MaxManifestSize}
+
+	targets := []*recreateTarget{}
+	for _, rootDir := range rootDirs {
+		if err := util.CheckDataRoot(prog.fsys, rootDir, false); err != nil {
+			logger.Error("Failed to access the protected data (skipping; unmounted volume?)", "path", rootDir, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", rootDir, err))
+
+			continue
+		}
+
+		logger.Info("Scanning filesystem for jobs...",
+			"walker", prog.walker.Name(), "path", rootDir)
+
+		metas, err := vs.Enumerate(ctx, rootDir, va, prog.cacher.NewCache(prog.fsys, "", rootDir))
+		if err != nil {
+			if !errors.Is(err, schema.ErrNonFatal) {
+				return results, fmt.Errorf("%s: failed to enumerate jobs: %w", rootDir, err)
+			}
+
+			errs = append(errs, fmt.Errorf("%s: failed to enumerate some jobs: %w", rootDir, err))
+		}
+
+		for _, meta := range metas {
+			if err := ctx.Err(); err != nil {
+				return results, fmt.Errorf("context error: %w", err)
+			}
+
+			mf, err := vs.LoadManifest(ctx, meta, va)
+			if err != nil {
+				logger := prog.creationLogger(ctx, nil, meta.Par2Path)
+				logger.Error("Failed to load par2cron manifest (will retry next run)", "error", err)
+				errs = append(errs, fmt.Errorf("%s: %w", meta.Par2Path, err))
+
+				continue
+			}
+
+			if target := prog.considerRecreate(ctx, meta, mf, opts); target != nil {
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	if len(targets) > 0 {
+		logger.Info(fmt.Sprintf("Starting to process %d jobs...", len(targets)),
+			"maxDuration", opts.MaxDuration.Value.String())
+		results.Selected = len(targets)
+	} else {
+		logger.Info("Nothing to do (will check again next run)")
+	}
+
+	var deadlineCtx context.Context //nolint:contextcheck
+	var deadlineCancel context.CancelFunc
+	if opts.MaxDuration.Value > 0 {
+		deadlineCtx, deadlineCancel = context.WithDeadline(ctx, time.Now().Add(opts.MaxDuration.Value))
+		defer deadlineCancel()
+	}
+
+	progress := prog.log.NewProgress("re-creating", len(targets))
+	defer progress.Close()
+
+	for i, target := range targets {
+		if err := ctx.Err(); err != nil {
+			return results, fmt.Errorf("context error: %w", err)
+		}
+
+		if i > 0 && deadlineCtx != nil {
+			if err := deadlineCtx.Err(); errors.Is(err, context.DeadlineExceeded) {
+				logger := prog.creationLogger(ctx, nil, nil)
+				logger.Warn("Exceeded the --duration budget (will continue next run)",
+					"unprocessedJobs", len(targets)-i, "totalJobs", len(targets),
+					"maxDuration", opts.MaxDuration.Value.String())
+
+				break
+			}
+		}
+
+		if opts.Limit > 0 && i >= opts.Limit {
+			logger := prog.creationLogger(ctx, nil, nil)
+			logger.Warn("Reached the --limit of jobs per run (will continue next run)",
+				"unprocessedJobs", len(targets)-i, "totalJobs", len(targets),
+				"limit", opts.Limit)
+
+			break
+		}
+
+		if opts.MaxErrors > 0 && results.Error >= opts.MaxErrors {
+			logger := prog.creationLogger(ctx, nil, nil)
+			logger.Error("Reached the --max-errors threshold of failed jobs (aborting the run)",
+				"unprocessedJobs", len(targets)-i, "totalJobs", len(targets),
+				"maxErrors", opts.MaxErrors)
+
+			break
+		}
+
+		pos := fmt.Sprintf("%d/%d", i+1, len(targets))
+		ctx := context.WithValue(ctx, schema.PosKey, pos)
+		progress.Step(i, target.par2Path)
+
+		logger := prog.recreateLogger(ctx, target)
+		logger.Info("Job started")
+
+		err := prog.recreateSet(ctx, target, opts)
+		switch {
+		case err == nil:
+			logger.Info("Job completed with success")
+			results.Success++
+
+		case errors.Is(err, errRecreateDryRun):
+			logger.Info("Job would be re-created (--dry-run)")
+			results.Skipped++
+
+		case errors.Is(err, errNotRecreatable):
+			logger.Warn("Job cannot be re-created (skipping)", "error", err)
+			results.Skipped++
+
+		case util.OnlyContains(err, schema.ErrFileIsLocked):
+			logger.Warn("Job unavailable (will retry next run)", "error", err)
+			results.Skipped++
+
+		default:
+			logger.Error("Job failure (will retry next run)", "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", target.par2Path, err))
+			results.Error++
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return results, fmt.Errorf("context error: %w", err)
+	}
+
+	if len(errs) > 0 {
+		return results, fmt.Errorf("%w: %w",
+			schema.ErrExitPartialFailure, errors.Join(errs...))
+	}
+
+	return results, nil
+}
+
+// considerRecreate returns the PAR2 set as a target to re-create, if its
+// recorded par2 arguments are outdated, or nil otherwise. With a minimum
+// redundancy (--older-redundancy-than), only sets created with less than
+// it are outdated, otherwise those created with other par2 arguments.
+func (prog *Service) considerRecreate(ctx context.Context, meta *verify.JobMeta, mf *schema.Manifest, opts RecreateOptions) *recreateTarget {
+	if mf == nil || mf.Creation == nil {
+		return nil
+	}
+
+	args := recreateArgs(mf.Creation.Mode, opts.Par2Args)
+	logger := prog.creationLogger(ctx, nil, meta.Par2Path).With("recordedArgs", mf.Creation.Args, "args", args)
+
+	if opts.OlderRedundancyThan.Value > 0 {
+		redundancy, known := par2Redundancy(mf.Creation.Args)
+		if !known {
+			logger.Debug("Redundancy is not known from the recorded arguments (skipping)")
+
+			return nil
+		}
+		if redundancy >= opts.OlderRedundancyThan.Value {
+			return nil
+		}
+		logger.Debug("Redundancy is below --older-redundancy-than (selecting)",
+			"redundancy", redundancy, "olderRedundancyThan", opts.OlderRedundancyThan.Value)
+	} else {
+		if slices.Equal(comparableArgs(mf.Creation.Args, args, opts.TargetBlockSize.Value), comparableArgs(args, args, opts.TargetBlockSize.Value)) {
+			return nil
+		}
+		logger.Debug("Recorded arguments differ from the current ones (selecting)")
+	}
+
+	names := opts.SidecarNames
+	target := &recreateTarget{
+		workingDir: filepath.Dir(meta.Par2Path),
+		par2Name:   filepath.Base(meta.Par2Path),
+		par2Path:   meta.Par2Path,
+		isBundle:   meta.IsBundle,
+		args:       args,
+		manifest:   mf,
+	}
+	if meta.IsBundle {
+		target.manifestPath = meta.Par2Path
+		target.lockPath = meta.Par2Path
+	} else {
+		target.manifestPath = names.ManifestPath(meta.Par2Path)
+		target.lockPath = names.LockPath(meta.Par2Path)
+	}
+
+	return target
+}
+
+// recreateSet re-creates the PAR2 set of the target as a staged set next to
+// it, which then replaces the set (see [Service.promoteStaged]). The set is
+// locked throughout, so that it is not verified or repaired meanwhile.
+func (prog *Service) recreateSet(ctx context.Context, target *recreateTarget, opts RecreateOptions) error {
+	if !opts.DryRun {
+		unlock, err := util.AcquireLock(prog.fsys, target.lockPath, false)
+		if err != nil {
+			return fmt.Errorf("failed to lock: %w", err)
+		}
+		defer unlock()
+	}
+
+	elements, err := prog.recreatableElements(target)
+	if err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		return errRecreateDryRun
+	}
+
+	staged := newStagedJob(target, opts)
+
+	// Any failure of creation removes the staged set (and only it).
+	if err := prog.runCreate(ctx, staged, elements); err != nil {
+		return fmt.Errorf("failed to create par2: %w", err)
+	}
+
+	if err := prog.promoteStaged(ctx, target, staged); err != nil {
+		return fmt.Errorf("failed to replace par2: %w", err)
+	}
+
+	return nil
+}
+
+// recreatableElements returns the elements protected by the set of the target
+// as they are now, for re-creating the set with. A set is only re-creatable as
+// long as it was last verified as healthy (with its current PAR2), as it would
+// otherwise protect possibly corrupted files, and all its elements still exist.
+func (prog *Service) recreatableElements(target *recreateTarget) ([]schema.FsElement, error) {
+	mf := target.manifest
+
+	if target.isBundle {
+		return nil, fmt.Errorf("%w: bundles are not re-created (unpack first)", errNotRecreatable)
+	}
+
+	if exists, _ := afero.Exists(prog.fsys, target.par2Path+schema.CreationManifestExtension); exists {
+		return nil, fmt.Errorf("%w: set protects its creation manifest (re-create by hand)", errNotRecreatable)
+	}
+
+	if mf.Verification == nil {
+		return nil, fmt.Errorf("%w: set was not verified yet (verify first)", errNotRecreatable)
+	}
+	if mf.Verification.RepairNeeded {
+		return nil, fmt.Errorf("%w: set was verified as corrupted (repair first)", errNotRecreatable)
+	}
+
+	hash, err := util.HashFile(prog.fsys, target.par2Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash par2: %w", err)
+	}
+	if hash != mf.SHA256 {
+		return nil, fmt.Errorf("%w: PAR2 changed since its last verification (verify first)", errNotRecreatable)
+	}
+
+	elements := make([]schema.FsElement, 0, len(mf.Creation.Elements))
+	for _, el := range mf.Creation.Elements {
+		if el.Name == "" || !filepath.IsLocal(el.Name) {
+			return nil, fmt.Errorf("%w: protected element without a usable name (%q)", errNotRecreatable, el.Name)
+		}
+
+		path := filepath.Join(target.workingDir, el.Name)
+
+		fi, err := util.LstatIfPossible(prog.fsys, path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: protected element %q not found (repair first)", errNotRecreatable, el.Name)
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to lstat: %w", err)
+		}
+
+		elem := schema.FsElement{
+			Path:    path,
+			Name:    el.Name,
+			Size:    fi.Size(),
+			Mode:    fi.Mode(),
+			IsDir:   fi.IsDir(),
+			ModTime: fi.ModTime(),
+		}
+		// The metadata stays recorded for sets created with --record-metadata.
+		if el.Owner != nil {
+			elem.Owner = util.FileOwner(fi)
+		}
+
+		elements = append(elements, elem)
+	}
+
+	if !hasData(elements) {
+		return nil, fmt.Errorf("%w: no protected elements recorded", errNotRecreatable)
+	}
+
+	return elements, nil
+}
+
+// newStagedJob returns the job creating the set of the target under a staged
+// name, with the recorded settings of the set and the current arguments.
+func newStagedJob(target *recreateTarget, opts RecreateOptions) *Job {
+	cr := target.manifest.Creation
+
+	sj := &Job{}
+
+	sj.hiddenFiles = strings.HasPrefix(target.par2Name, ".")
+	sj.workingDir = target.workingDir
+	sj.par2Mode = cr.Mode
+	sj.par2Name = recreateStagePrefix + target.par2Name
+	sj.par2Path = filepath.Join(sj.workingDir, sj.par2Name)
+	sj.par2Args = slices.Clone(target.args)
+	sj.par2Glob = cr.Glob
+	sj.par2GlobExclude = slices.Clone(cr.GlobExclude)
+	sj.par2Verify = opts.Par2Verify
+	sj.verifyInterval = cr.VerifyInterval
+	sj.noAutoRepair = cr.NoAutoRepair
+	sj.folderFingerprint = cr.FolderFingerprint != ""
+	// A subset keeps the fingerprint of the entire folder (as recorded).
+	if cr.Subset != nil {
+		subset := *cr.Subset
+		sj.subset = &subset
+		sj.fingerprint = cr.FolderFingerprint
+	}
+	sj.tags = slices.Clone(cr.Tags)
+	sj.marker = cr.Marker
+	sj.targetBlockSize = opts.TargetBlockSize.Value
+	sj.retrySingleThreaded = opts.RetrySingleThreaded
+	sj.maxManifestSize = opts.MaxManifestSize
+
+	sj.sidecarNames = opts.SidecarNames
+	sj.lockPath = sj.sidecarNames.LockPath(sj.par2Path)
+	sj.manifestName = sj.sidecarNames.ManifestName(sj.par2Name)
+	sj.manifestPath = sj.sidecarNames.ManifestPath(sj.par2Path)
+
+	return sj
+}
+
+// promoteStaged replaces the set of the target with the staged set. The old
+// index and volumes are moved aside first, then the new volumes and the new
+// index are moved into place and the manifest of the new set is written. Any
+// failure on the way rolls all moves back, so that the old set is restored as
+// it was (and the staged set is removed). Only once the new set is in place
+// are the moved-aside files of the old set removed.
+func (prog *Service) promoteStaged(ctx context.Context, target *recreateTarget, staged *Job) error {
+	mf, err := prog.readExistingManifest(ctx, staged.par2Path, staged.sidecarNames, staged.maxManifestSize)
+	if err != nil {
+		return fmt.Errorf("failed to read staged manifest: %w", err)
+	} else if mf == nil {
+		return errors.New("failed to read staged manifest: not found")
+	}
+	mf.Name = target.par2Name
+
+	oldVolumes, err := par2.VolumeFiles(prog.fsys, target.par2Path)
+	if err != nil {
+		return fmt.Errorf("failed to list old volumes: %w", err)
+	}
+	newVolumes, err := par2.VolumeFiles(prog.fsys, staged.par2Path)
+	if err != nil {
+		return fmt.Errorf("failed to list new volumes: %w", err)
+	}
+
+	stagedRoot := util.TrimSuffixFold(staged.par2Name, schema.Par2Extension)
+	root := util.TrimSuffixFold(target.par2Name, schema.Par2Extension)
+
+	sw := &setSwap{fsys: prog.fsys}
+
+	asidePaths := make([]string, 0, len(oldVolumes)+1)
+	for _, path := range append([]string{target.par2Path}, oldVolumes...) {
+		aside := filepath.Join(target.workingDir, recreateAsidePrefix+filepath.Base(path))
+		if err := sw.move(path, aside); err != nil {
+			prog.rollbackPromote(ctx, target, staged, sw)
+
+			return fmt.Errorf("failed to move old set aside: %w", err)
+		}
+		asidePaths = append(asidePaths, aside)
+	}
+
+	for _, volume := range newVolumes {
+		path := filepath.Join(target.workingDir, root+strings.TrimPrefix(filepath.Base(volume), stagedRoot))
+		if err := sw.move(volume, path); err != nil {
+			prog.rollbackPromote(ctx, target, staged, sw)
+
+			return fmt.Errorf("failed to rename volume: %w", err)
+		}
+	}
+
+	if err := sw.move(staged.par2Path, target.par2Path); err != nil {
+		prog.rollbackPromote(ctx, target, staged, sw)
+
+		return fmt.Errorf("failed to rename index: %w", err)
+	}
+
+	if err := util.WriteManifest(ctx, prog.fsys, prog.bundler, target.manifestPath, mf, false); err != nil {
+		prog.rollbackPromote(ctx, target, staged, sw)
+
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	for _, path := range []string{staged.manifestPath, staged.lockPath} {
+		if err := prog.fsys.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger := prog.recreateLogger(ctx, target)
+			logger.Warn("Failed to cleanup a staged file (needs manual deletion)", "file", path, "error", err)
+		}
+	}
+
+	for _, path := range asidePaths {
+		if err := prog.fsys.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger := prog.recreateLogger(ctx, target)
+			logger.Warn("Failed to remove a file of the old set (needs manual deletion)", "file", path, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// rollbackPromote rolls back the moves of a failed [Service.promoteStaged],
+// restoring the old set, and then removes the staged set. Should any move not
+// be rolled back, all files are left as they are for manual attention.
+func (prog *Service) rollbackPromote(ctx context.Context, target *recreateTarget, staged *Job, sw *setSwap) {
+	logger := prog.recreateLogger(ctx, target)
+
+	if path, err := sw.rollback(); err != nil {
+		logger.Error("Failed to restore a file of the old set (needs manual attention)", "file", path, "error", err)
+
+		return
+	}
+
+	logger.Warn("Failed to replace the old set with the new set (old set restored)")
+	prog.cleanupAfterFailure(ctx, staged)
+}
+
+// setSwap records the file moves of swapping a PAR2 set, so that they can be
+// rolled back as a whole.
+type setSwap struct {
+	fsys  afero.Fs
+	moves [][2]string
+}
+
+func (sw *setSwap) move(from string, to string) error {
+	if err := sw.fsys.Rename(from, to); err != nil {
+		return fmt.Errorf("failed to rename: %w", err)
+	}
+	sw.moves = append(sw.moves, [2]string{from, to})
+
+	return nil
+}
+
+// rollback reverts the recorded moves in reverse order. It returns the path
+// of the first file that could not be moved back along with the error.
+func (sw *setSwap) rollback() (string, error) {
+	for i := len(sw.moves) - 1; i >= 0; i-- {
+		from, to := sw.moves[i][0], sw.moves[i][1]
+		if err := sw.fsys.Rename(to, from); err != nil {
+			return to, fmt.Errorf("failed to rename: %w", err)
+		}
+		sw.moves = sw.moves[:i]
+	}
+
+	return "", nil
+}
+
+// recreateArgs returns the current par2 arguments for a set of the mode,
+// with -R as needed by (and only by) the recursive mode, as for creation.
+func recreateArgs(mode string, par2Args []string) []string {
+	args := slices.DeleteFunc(slices.Clone(par2Args), func(a string) bool { return a == "-R" })
+	if mode == schema.CreateRecursiveMode {
+		args = append(args, "-R")
+	}
+
+	return args
+}
+
+// comparableArgs returns the par2 arguments for comparing recorded arguments
+// with the current ones. With a target block size (and no block count or size
+// in the current arguments), the block count (-b) is left out, as it is then
+// computed per set from its size rather than being one of the arguments.
+func comparableArgs(args []string, current []string, targetBlockSize int64) []string {
+	if targetBlockSize <= 0 || util.HasPar2BlockArg(current) {
+		return args
+	}
+
+	return slices.DeleteFunc(slices.Clone(args), func(a string) bool {
+		return strings.HasPrefix(strings.TrimSpace(a), "-b")
+	})
+}
+
+// par2Redundancy returns the redundancy set by the par2 arguments (-r), or
+// the default redundancy of par2 without one. It is not known for arguments
+// setting it by size (-rk, -rm, -rg) or by the recovery block count (-c).
+func par2Redundancy(args []string) (float64, bool) {
+	redundancy := par2DefaultRedundancy
+
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimSpace(args[i])
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-c") {
+			return 0, false
+		}
+
+		value, ok := strings.CutPrefix(arg, "-r")
+		if !ok {
+			continue
+		}
+
+		// "-r10", "-r 10", "-r=10" or "-r" followed by "10".
+		value = strings.TrimPrefix(strings.TrimSpace(value), "=")
+		if value == "" && i+1 < len(args) && !strings.HasPrefix(strings.TrimSpace(args[i+1]), "-") {
+			i++
+			value = strings.TrimSpace(args[i])
+		}
+
+		pct, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, false
+		}
+		redundancy = pct / 100 //nolint:mnd
+	}
+
+	return redundancy, true
+}
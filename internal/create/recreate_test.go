@@ -0,0 +1,425 @@
+package create
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// createRecreatable creates a folder mode PAR2 set (with two volumes) in the directory,
+// created with the arguments and last verified as healthy, returning its manifest.
+func createRecreatable(t *testing.T, fs afero.Fs, dir string, args []string) *schema.Manifest {
+	t.Helper()
+
+	name := filepath.Base(dir)
+	par2Path := filepath.Join(dir, name+schema.Par2Extension)
+
+	require.NoError(t, fs.MkdirAll(dir, 0o755))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, "file.txt"), []byte("content"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, par2Path, []byte("old-index"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, name+".vol00+01.par2"), []byte("old-vol"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, name+".vol01+02.par2"), []byte("old-vol"), 0o644))
+
+	mf := schema.NewManifest(name + schema.Par2Extension)
+	mf.SHA256 = fmt.Sprintf("%x", sha256.Sum256([]byte("old-index")))
+	mf.Creation = schema.NewCreationManifest()
+	mf.Creation.Time = time.Now().Add(-time.Hour)
+	mf.Creation.Mode = schema.CreateFolderMode
+	mf.Creation.Glob = "*"
+	mf.Creation.Args = args
+	mf.Creation.Tags = []string{"tier:critical"}
+	mf.Creation.Elements = []schema.FsElement{{Name: "file.txt", Size: 7}}
+	mf.Verification = schema.NewVerificationManifest()
+	mf.Verification.Count = 1
+
+	require.NoError(t, util.WriteManifest(t.Context(), fs, &util.BundleHandler{}, par2Path+schema.ManifestExtension, mf, false))
+
+	return mf
+}
+
+// newRecreateService returns a service whose runner creates a PAR2 set (with one
+// volume) as par2 would, recording the arguments of each run.
+func newRecreateService(t *testing.T, fs afero.Fs, logBuf *testutil.SafeBuffer, calls *[][]string, runErr error) *Service {
+	t.Helper()
+
+	ls := logging.Options{
+		Logout: logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("debug")
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			*calls = append(*calls, args)
+			if runErr != nil {
+				return runErr
+			}
+
+			dash := slices.Index(args, "--")
+			par2Path := args[dash+1]
+			root := util.TrimSuffixFold(par2Path, schema.Par2Extension)
+			require.NoError(t, afero.WriteFile(fs, par2Path, []byte("new-index"), 0o644))
+			require.NoError(t, afero.WriteFile(fs, root+".vol00+02.par2", []byte("new-vol"), 0o644))
+
+			return nil
+		},
+	}
+
+	return NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+}
+
+func readRecreatedManifest(t *testing.T, fs afero.Fs, par2Path string) *schema.Manifest {
+	t.Helper()
+
+	data, err := afero.ReadFile(fs, par2Path+schema.ManifestExtension)
+	require.NoError(t, err)
+
+	mf, err := util.UnmarshalManifest(data, 0)
+	require.NoError(t, err)
+
+	return mf
+}
+
+// Expectation: A set created with other arguments should be replaced by one created with the
+// current arguments, keeping its recorded settings, with the old volumes and staged files removed.
+func Test_Service_Recreate_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createRecreatable(t, fs, "/data/a", []string{"-r5"})
+
+	var logBuf testutil.SafeBuffer
+	var calls [][]string
+	prog := newRecreateService(t, fs, &logBuf, &calls, nil)
+
+	results, err := prog.Recreate(t.Context(), []string{"/data"}, RecreateOptions{Options: Options{Par2Args: []string{"-r15"}}})
+	require.NoError(t, err)
+	require.Equal(t, 1, results.Success)
+
+	require.Len(t, calls, 1)
+	require.Equal(t, []string{"create", "-r15", "--", "/data/a/" + recreateStagePrefix + "a.par2", "/data/a/file.txt"}, calls[0])
+
+	data, err := afero.ReadFile(fs, "/data/a/a.par2")
+	require.NoError(t, err)
+	require.Equal(t, "new-index", string(data))
+
+	data, err = afero.ReadFile(fs, "/data/a/a.vol00+02.par2")
+	require.NoError(t, err)
+	require.Equal(t, "new-vol", string(data))
+
+	for _, path := range []string{
+		"/data/a/a.vol00+01.par2",
+		"/data/a/a.vol01+02.par2",
+		"/data/a/" + recreateStagePrefix + "a.par2",
+		"/data/a/" + recreateStagePrefix + "a.vol00+02.par2",
+		"/data/a/" + recreateStagePrefix + "a.par2" + schema.ManifestExtension,
+		"/data/a/" + recreateAsidePrefix + "a.par2",
+		"/data/a/" + recreateAsidePrefix + "a.vol00+01.par2",
+	} {
+		exists, err := afero.Exists(fs, path)
+		require.NoError(t, err)
+		require.False(t, exists, path)
+	}
+
+	mf := readRecreatedManifest(t, fs, "/data/a/a.par2")
+	require.Equal(t, "a.par2", mf.Name)
+	require.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte("new-index"))), mf.SHA256)
+	require.Equal(t, []string{"-r15"}, mf.Creation.Args)
+	require.Equal(t, []string{"tier:critical"}, mf.Creation.Tags)
+	require.Equal(t, schema.CreateFolderMode, mf.Creation.Mode)
+	require.Len(t, mf.Creation.Elements, 1)
+	require.Equal(t, "file.txt", mf.Creation.Elements[0].Name)
+	require.Nil(t, mf.Verification)
+}
+
+// Expectation: A set created with the current arguments should not be re-created.
+func Test_Service_Recreate_UpToDate_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createRecreatable(t, fs, "/data/a", []string{"-r15"})
+
+	var logBuf testutil.SafeBuffer
+	var calls [][]string
+	prog := newRecreateService(t, fs, &logBuf, &calls, nil)
+
+	results, err := prog.Recreate(t.Context(), []string{"/data"}, RecreateOptions{Options: Options{Par2Args: []string{"-r15"}}})
+	require.NoError(t, err)
+
+	require.Empty(t, calls)
+	require.Equal(t, 0, results.Selected)
+	require.Contains(t, logBuf.String(), "Nothing to do")
+}
+
+// Expectation: A failure of creating the new set should leave the old set intact and remove the staged set.
+func Test_Service_Recreate_CreateFails_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	old := createRecreatable(t, fs, "/data/a", []string{"-r5"})
+
+	var logBuf testutil.SafeBuffer
+	var calls [][]string
+	prog := newRecreateService(t, fs, &logBuf, &calls, errors.New("par2 failed"))
+
+	results, err := prog.Recreate(t.Context(), []string{"/data"}, RecreateOptions{Options: Options{Par2Args: []string{"-r15"}}})
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+	require.Equal(t, 1, results.Error)
+	require.Len(t, calls, 1)
+
+	data, err := afero.ReadFile(fs, "/data/a/a.par2")
+	require.NoError(t, err)
+	require.Equal(t, "old-index", string(data))
+
+	for _, path := range []string{"/data/a/a.vol00+01.par2", "/data/a/a.vol01+02.par2"} {
+		exists, err := afero.Exists(fs, path)
+		require.NoError(t, err)
+		require.True(t, exists, path)
+	}
+
+	exists, err := afero.Exists(fs, "/data/a/"+recreateStagePrefix+"a.par2"+schema.ManifestExtension)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.Equal(t, old.Creation.Args, readRecreatedManifest(t, fs, "/data/a/a.par2").Creation.Args)
+}
+
+// Expectation: A failure of moving the new set into place should roll back to the old set,
+// with the moved-aside files of the old set restored and the staged set removed.
+func Test_Service_Recreate_PromoteFails_Error(t *testing.T) {
+	t.Parallel()
+
+	memFs := afero.NewMemMapFs()
+	old := createRecreatable(t, memFs, "/data/a", []string{"-r5"})
+	fs := &testutil.FailingRenameFs{Fs: memFs, FailPattern: "vol00+02"}
+
+	var logBuf testutil.SafeBuffer
+	var calls [][]string
+	prog := newRecreateService(t, memFs, &logBuf, &calls, nil)
+	prog.fsys = fs
+
+	results, err := prog.Recreate(t.Context(), []string{"/data"}, RecreateOptions{Options: Options{Par2Args: []string{"-r15"}}})
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+	require.Equal(t, 1, results.Error)
+	require.Len(t, calls, 1)
+	require.Contains(t, logBuf.String(), "old set restored")
+
+	data, err := afero.ReadFile(memFs, "/data/a/a.par2")
+	require.NoError(t, err)
+	require.Equal(t, "old-index", string(data))
+
+	for _, path := range []string{"/data/a/a.vol00+01.par2", "/data/a/a.vol01+02.par2"} {
+		data, err := afero.ReadFile(memFs, path)
+		require.NoError(t, err, path)
+		require.Equal(t, "old-vol", string(data), path)
+	}
+
+	for _, path := range []string{
+		"/data/a/a.vol00+02.par2",
+		"/data/a/" + recreateAsidePrefix + "a.par2",
+		"/data/a/" + recreateAsidePrefix + "a.vol00+01.par2",
+		"/data/a/" + recreateStagePrefix + "a.par2",
+		"/data/a/" + recreateStagePrefix + "a.vol00+02.par2",
+		"/data/a/" + recreateStagePrefix + "a.par2" + schema.ManifestExtension,
+	} {
+		exists, err := afero.Exists(memFs, path)
+		require.NoError(t, err)
+		require.False(t, exists, path)
+	}
+
+	require.Equal(t, old.Creation.Args, readRecreatedManifest(t, memFs, "/data/a/a.par2").Creation.Args)
+}
+
+// Expectation: A dry run should only log the sets that would be re-created, without changing anything.
+func Test_Service_Recreate_DryRun_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createRecreatable(t, fs, "/data/a", []string{"-r5"})
+
+	var logBuf testutil.SafeBuffer
+	var calls [][]string
+	prog := newRecreateService(t, fs, &logBuf, &calls, nil)
+
+	opts := RecreateOptions{Options: Options{Par2Args: []string{"-r15"}}, DryRun: true}
+	results, err := prog.Recreate(t.Context(), []string{"/data"}, opts)
+	require.NoError(t, err)
+
+	require.Empty(t, calls)
+	require.Equal(t, 1, results.Selected)
+	require.Equal(t, 1, results.Skipped)
+	require.Contains(t, logBuf.String(), "Job would be re-created (--dry-run)")
+
+	data, err := afero.ReadFile(fs, "/data/a/a.par2")
+	require.NoError(t, err)
+	require.Equal(t, "old-index", string(data))
+}
+
+// Expectation: With --older-redundancy-than, only the sets with less redundancy should be re-created.
+func Test_Service_Recreate_OlderRedundancyThan_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createRecreatable(t, fs, "/data/a", []string{"-r5"})
+	createRecreatable(t, fs, "/data/b", []string{"-r20", "-n1"})
+	createRecreatable(t, fs, "/data/c", nil)
+
+	var logBuf testutil.SafeBuffer
+	var calls [][]string
+	prog := newRecreateService(t, fs, &logBuf, &calls, nil)
+
+	opts := RecreateOptions{Options: Options{Par2Args: []string{"-r15"}}}
+	require.NoError(t, opts.OlderRedundancyThan.Set("10%"))
+
+	results, err := prog.Recreate(t.Context(), []string{"/data"}, opts)
+	require.NoError(t, err)
+	require.Equal(t, 2, results.Success)
+
+	require.Len(t, calls, 2)
+	require.Contains(t, calls[0], "/data/a/"+recreateStagePrefix+"a.par2")
+	require.Contains(t, calls[1], "/data/c/"+recreateStagePrefix+"c.par2")
+	require.Equal(t, []string{"-r20", "-n1"}, readRecreatedManifest(t, fs, "/data/b/b.par2").Creation.Args)
+}
+
+// Expectation: The --duration budget should end the run after the first job.
+func Test_Service_Recreate_Duration_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createRecreatable(t, fs, "/data/a", []string{"-r5"})
+	createRecreatable(t, fs, "/data/b", []string{"-r5"})
+
+	var logBuf testutil.SafeBuffer
+	var calls [][]string
+	prog := newRecreateService(t, fs, &logBuf, &calls, nil)
+
+	opts := RecreateOptions{Options: Options{Par2Args: []string{"-r15"}}}
+	require.NoError(t, opts.MaxDuration.Set("1ns"))
+
+	results, err := prog.Recreate(t.Context(), []string{"/data"}, opts)
+	require.NoError(t, err)
+
+	require.Len(t, calls, 1)
+	require.Equal(t, 1, results.Success)
+	require.Contains(t, logBuf.String(), "Exceeded the --duration budget")
+}
+
+// Expectation: Sets that are not known to be healthy (or cannot be re-created as recorded)
+// should be skipped without running par2 and be left as they are.
+func Test_Service_Recreate_NotRecreatable_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		modify func(t *testing.T, fs afero.Fs, mf *schema.Manifest)
+		reason string
+	}{
+		{"not verified", func(t *testing.T, fs afero.Fs, mf *schema.Manifest) {
+			t.Helper()
+			mf.Verification = nil
+		}, "not verified yet"},
+		{"needs repair", func(t *testing.T, fs afero.Fs, mf *schema.Manifest) {
+			t.Helper()
+			mf.Verification.RepairNeeded = true
+		}, "verified as corrupted"},
+		{"par2 changed", func(t *testing.T, fs afero.Fs, mf *schema.Manifest) {
+			t.Helper()
+			mf.SHA256 = "other"
+		}, "PAR2 changed"},
+		{"element missing", func(t *testing.T, fs afero.Fs, mf *schema.Manifest) {
+			t.Helper()
+			require.NoError(t, fs.Remove("/data/a/file.txt"))
+		}, "not found"},
+		{"protected creation manifest", func(t *testing.T, fs afero.Fs, mf *schema.Manifest) {
+			t.Helper()
+			require.NoError(t, afero.WriteFile(fs, "/data/a/a.par2"+schema.CreationManifestExtension, []byte("{}"), 0o644))
+		}, "protects its creation manifest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			mf := createRecreatable(t, fs, "/data/a", []string{"-r5"})
+			tt.modify(t, fs, mf)
+			require.NoError(t, util.WriteManifest(t.Context(), fs, &util.BundleHandler{}, "/data/a/a.par2"+schema.ManifestExtension, mf, false))
+
+			var logBuf testutil.SafeBuffer
+			var calls [][]string
+			prog := newRecreateService(t, fs, &logBuf, &calls, nil)
+
+			results, err := prog.Recreate(t.Context(), []string{"/data"}, RecreateOptions{Options: Options{Par2Args: []string{"-r15"}}})
+			require.NoError(t, err)
+
+			require.Empty(t, calls)
+			require.Equal(t, 1, results.Skipped)
+			require.Contains(t, logBuf.String(), "Job cannot be re-created")
+			require.Contains(t, logBuf.String(), tt.reason)
+
+			data, err := afero.ReadFile(fs, "/data/a/a.par2")
+			require.NoError(t, err)
+			require.Equal(t, "old-index", string(data))
+		})
+	}
+}
+
+// Expectation: The redundancy should be parsed from the arguments as par2 would.
+func Test_par2Redundancy_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		args  []string
+		want  float64
+		known bool
+	}{
+		{"default", nil, par2DefaultRedundancy, true},
+		{"attached", []string{"-r10", "-n1"}, 0.10, true},
+		{"equals", []string{"-r=20"}, 0.20, true},
+		{"spaced", []string{"-r 15"}, 0.15, true},
+		{"separate", []string{"-r", "30"}, 0.30, true},
+		{"last wins", []string{"-r10", "-r25"}, 0.25, true},
+		{"recursive", []string{"-R", "-r10"}, 0.10, true},
+		{"after dash", []string{"--", "-r10"}, par2DefaultRedundancy, true},
+		{"by size", []string{"-rm100"}, 0, false},
+		{"by count", []string{"-c100"}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, known := par2Redundancy(tt.args)
+			require.Equal(t, tt.known, known)
+			require.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+// Expectation: The current arguments should carry -R only for the recursive mode, with a
+// block count computed from a target block size not counting as a differing argument.
+func Test_recreateArgs_comparableArgs_Success(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"-r15", "-R"}, recreateArgs(schema.CreateRecursiveMode, []string{"-r15"}))
+	require.Equal(t, []string{"-r15"}, recreateArgs(schema.CreateFolderMode, []string{"-R", "-r15"}))
+
+	require.Equal(t, []string{"-r15"}, comparableArgs([]string{"-r15", "-b100"}, []string{"-r15"}, 4096))
+	require.Equal(t, []string{"-r15", "-b100"}, comparableArgs([]string{"-r15", "-b100"}, []string{"-r15"}, 0))
+	require.Equal(t, []string{"-r15", "-b100"}, comparableArgs([]string{"-r15", "-b100"}, []string{"-r15", "-b200"}, 4096))
+}
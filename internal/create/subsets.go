@@ -0,0 +1,143 @@
+package create
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+)
+
+// createSubsets splits the protected files of a folder mode job exceeding the
+// maximum set size (--max-set-size) into size-bounded subsets, creating a PAR2
+// set for each of them (see [newSubsetJob]). It returns false if the files fit
+// into a single set, which is then created as usual, unless the folder was
+// split before (its first subset exists), so it is not protected twice.
+func (prog *Service) createSubsets(ctx context.Context, job *Job, elements []schema.FsElement) (bool, error) {
+	subsets := partitionElements(elements, job.maxSetSize)
+
+	if len(subsets) < 2 { //nolint:mnd
+		first := newSubsetJob(*job, 1, 1)
+		if path, err := prog.existingPar2(ctx, &first); err != nil {
+			return true, fmt.Errorf("failed to check existence: %w", err)
+		} else if path != "" {
+			return true, nil
+		}
+
+		return false, nil
+	}
+
+	logger := prog.creationLogger(ctx, job, job.workingDir)
+	logger.Info("Splitting protected files into size-bounded PAR2 sets",
+		"sets", len(subsets), "maxSetSize", util.FmtBytes(job.maxSetSize))
+
+	var fingerprint string
+	if job.folderFingerprint {
+		fingerprint = util.FolderFingerprint(elements)
+	}
+
+	var errs []error
+	for i, subset := range subsets {
+		if err := ctx.Err(); err != nil {
+			return true, fmt.Errorf("context error: %w", err)
+		}
+
+		mpos := fmt.Sprintf("%d/%d", i+1, len(subsets))
+		ctx := context.WithValue(ctx, schema.MposKey, mpos)
+
+		j := newSubsetJob(*job, i+1, len(subsets))
+		j.fingerprint = fingerprint
+
+		if len(subset) == 1 && subset[0].Size > job.maxSetSize {
+			logger := prog.creationLogger(ctx, &j, subset[0].Path)
+			logger.Warn("A protected file exceeds the maximum set size (protected in a set of its own)",
+				"size", util.FmtBytes(subset[0].Size))
+		}
+
+		if path, err := prog.existingPar2(ctx, &j); err != nil {
+			errs = append(errs, fmt.Errorf("%s: failed to check existence: %w", j.par2Path, err))
+
+			continue
+		} else if path != "" {
+			prog.reportDrift(ctx, &j, path, subset)
+
+			continue
+		}
+
+		if err := prog.runCreate(ctx, &j, subset); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", j.par2Path, err))
+
+			continue
+		}
+
+		logger := prog.creationLogger(ctx, &j, j.par2Path)
+		logger.Info("Succeeded to create PAR2")
+	}
+
+	if len(errs) > 0 {
+		return true, fmt.Errorf("%d/%d subjobs failed: %w",
+			len(errs), len(subsets), errors.Join(errs...))
+	}
+
+	return true, nil
+}
+
+// newSubsetJob returns the job creating the subset at index (1-based) of the
+// count subsets of a folder, named after the PAR2 set of the folder with the
+// index inserted before the extension (such as folder.1.par2).
+func newSubsetJob(job Job, index int, count int) Job {
+	job.par2Name = util.TrimSuffixFold(job.par2Name, schema.Par2Extension) + "." + strconv.Itoa(index) + schema.Par2Extension
+	job.par2Path = filepath.Join(job.workingDir, job.par2Name)
+	job.manifestName = job.sidecarNames.ManifestName(job.par2Name)
+	job.manifestPath = job.sidecarNames.ManifestPath(job.par2Path)
+	job.lockPath = job.sidecarNames.LockPath(job.par2Path)
+	if job.fileListPath != "" {
+		job.fileListPath = job.par2Path + schema.FileListExtension
+	}
+	if job.creationPath != "" {
+		job.creationPath = job.par2Path + schema.CreationManifestExtension
+	}
+	job.subset = &schema.CreationSubset{Index: index, Count: count, MaxSize: job.maxSetSize}
+
+	return job
+}
+
+// partitionElements splits the elements into subsets whose sizes each add up
+// to at most maxSize (first-fit decreasing), where an element exceeding it is
+// a subset of its own. The subsets are ordered by their largest element, and
+// the elements within each subset by path.
+func partitionElements(elements []schema.FsElement, maxSize int64) [][]schema.FsElement {
+	sorted := slices.Clone(elements)
+	slices.SortFunc(sorted, func(a, b schema.FsElement) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), strings.Compare(a.Path, b.Path))
+	})
+
+	var subsets [][]schema.FsElement
+	var sizes []int64
+	for _, el := range sorted {
+		i := slices.IndexFunc(sizes, func(size int64) bool {
+			return size+el.Size <= maxSize
+		})
+		if i < 0 {
+			subsets = append(subsets, nil)
+			sizes = append(sizes, 0)
+			i = len(subsets) - 1
+		}
+		subsets[i] = append(subsets[i], el)
+		sizes[i] += el.Size
+	}
+
+	for _, subset := range subsets {
+		slices.SortFunc(subset, func(a, b schema.FsElement) int {
+			return strings.Compare(a.Path, b.Path)
+		})
+	}
+
+	return subsets
+}
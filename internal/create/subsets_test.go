@@ -0,0 +1,179 @@
+package create
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/flags"
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: The elements should be packed into as few subsets under the size bound as first-fit
+// decreasing allows, an oversized element into a subset of its own, ordered deterministically.
+func Test_partitionElements_Table(t *testing.T) {
+	t.Parallel()
+
+	el := func(path string, size int64) schema.FsElement {
+		return schema.FsElement{Path: path, Name: path, Size: size}
+	}
+
+	tests := []struct {
+		name     string
+		elements []schema.FsElement
+		maxSize  int64
+		want     [][]string
+	}{
+		{"fits", []schema.FsElement{el("b", 3), el("a", 4)}, 10, [][]string{{"a", "b"}}},
+		{"exact", []schema.FsElement{el("a", 5), el("b", 5)}, 10, [][]string{{"a", "b"}}},
+		{"split", []schema.FsElement{el("a", 6), el("b", 5), el("c", 4)}, 10, [][]string{{"a", "c"}, {"b"}}},
+		{"oversized", []schema.FsElement{el("a", 1), el("big", 20), el("b", 2)}, 10, [][]string{{"big"}, {"a", "b"}}},
+		{"empty files", []schema.FsElement{el("a", 10), el("e", 0)}, 10, [][]string{{"a", "e"}}},
+		{"ties by path", []schema.FsElement{el("d", 6), el("c", 6), el("b", 6)}, 10, [][]string{{"b"}, {"c"}, {"d"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			subsets := partitionElements(tt.elements, tt.maxSize)
+
+			got := make([][]string, 0, len(subsets))
+			for _, subset := range subsets {
+				var size int64
+				names := []string{}
+				for _, e := range subset {
+					names = append(names, e.Name)
+					size += e.Size
+				}
+				if len(subset) > 1 {
+					require.LessOrEqual(t, size, tt.maxSize)
+				}
+				got = append(got, names)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// subsetTestService returns a service whose runner creates the PAR2 given to par2 (after "--").
+func subsetTestService(t *testing.T, fs afero.Fs, calls *int) *Service {
+	t.Helper()
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			*calls++
+			i := slices.Index(args, "--")
+			require.Positive(t, i)
+
+			return afero.WriteFile(fs, args[i+1], []byte("par2data"), 0o644)
+		},
+	}
+
+	return NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+}
+
+// Expectation: A folder exceeding the maximum set size should be protected by numbered subsets,
+// each recording its files and position, while a folder fitting into one set is not split.
+func Test_Service_Create_MaxSetSize_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		maxSetSize  int64
+		wantSubsets [][]string
+	}{
+		{"split", 10, [][]string{{"a.txt", "c.txt"}, {"b.txt"}}},
+		{"fits", 100, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(""), 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/a.txt", []byte("aaaaaa"), 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/b.txt", []byte("bbbbb"), 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/c.txt", []byte("cccc"), 0o644))
+
+			var calls int
+			prog := subsetTestService(t, fs, &calls)
+
+			_, err := prog.Create(t.Context(), []string{"/data"}, Options{
+				Par2Glob:   "*",
+				Par2Mode:   flags.CreateMode{Value: schema.CreateFolderMode},
+				MaxSetSize: flags.ByteSize{Value: tt.maxSetSize},
+			})
+			require.NoError(t, err)
+
+			if tt.wantSubsets == nil {
+				require.Equal(t, 1, calls)
+				exists, err := afero.Exists(fs, "/data/folder/folder"+schema.Par2Extension+schema.ManifestExtension)
+				require.NoError(t, err)
+				require.True(t, exists)
+
+				return
+			}
+
+			require.Equal(t, len(tt.wantSubsets), calls)
+			exists, err := afero.Exists(fs, "/data/folder/folder"+schema.Par2Extension)
+			require.NoError(t, err)
+			require.False(t, exists)
+
+			for i, want := range tt.wantSubsets {
+				par2Name := "folder." + string(rune('1'+i)) + schema.Par2Extension
+
+				data, err := afero.ReadFile(fs, "/data/folder/"+par2Name+schema.ManifestExtension)
+				require.NoError(t, err)
+
+				var mf schema.Manifest
+				require.NoError(t, json.Unmarshal(data, &mf))
+				require.Equal(t, par2Name, mf.Name)
+				require.Equal(t, &schema.CreationSubset{Index: i + 1, Count: len(tt.wantSubsets), MaxSize: tt.maxSetSize}, mf.Creation.Subset)
+
+				names := []string{}
+				for _, el := range mf.Creation.Elements {
+					names = append(names, el.Name)
+				}
+				require.Equal(t, want, names)
+			}
+		})
+	}
+}
+
+// Expectation: A folder split before should not be protected again by a single set once it fits.
+func Test_Service_Create_MaxSetSize_SplitBefore_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/"+createMarkerPathPrefix, []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/a.txt", []byte("aaaaaa"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/folder.1"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	var calls int
+	prog := subsetTestService(t, fs, &calls)
+
+	_, err := prog.Create(t.Context(), []string{"/data"}, Options{
+		Par2Glob:   "*",
+		Par2Mode:   flags.CreateMode{Value: schema.CreateFolderMode},
+		MaxSetSize: flags.ByteSize{Value: 100},
+	})
+	require.NoError(t, err)
+	require.Zero(t, calls)
+}
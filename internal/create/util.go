@@ -7,46 +7,71 @@ import (
 	"io/fs"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/desertwitch/par2cron/internal/par2"
 	"github.com/desertwitch/par2cron/internal/schema"
 	"github.com/desertwitch/par2cron/internal/util"
 	"github.com/spf13/afero"
 )
 
 func (prog *Service) cleanupAfterFailure(ctx context.Context, job *Job) {
-	entries, err := afero.ReadDir(prog.fsys, job.workingDir)
+	files := prog.par2Files(ctx, job)
+	files = append(files, job.manifestPath, job.lockPath, job.stampPath, job.creationPath, job.fileListPath)
+
+	prog.removeFiles(ctx, job, files)
+}
+
+// removePar2Files removes the PAR2 files (left) of the job, such as of a par2
+// run that failed, so that par2 can create them anew.
+func (prog *Service) removePar2Files(ctx context.Context, job *Job) {
+	prog.removeFiles(ctx, job, prog.par2Files(ctx, job))
+}
+
+// par2Files returns the paths of the PAR2 files (index, bundle and volumes) of
+// the job, as to be removed on failure.
+func (prog *Service) par2Files(ctx context.Context, job *Job) []string {
+	root := strings.TrimSuffix(util.TrimSuffixFold(job.par2Path, schema.Par2Extension), schema.BundleExtension)
+	indexPath := root + schema.Par2Extension
+
+	files := []string{indexPath, root + schema.BundleExtension + schema.Par2Extension}
+
+	volumes, err := par2.VolumeFiles(prog.fsys, indexPath)
 	if err != nil {
 		logger := prog.creationLogger(ctx, job, job.workingDir)
 		logger.Warn("Failed to read directory for cleanup (needs manual deletion)", "error", err)
-
-		return
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		name := entry.Name()
+	return append(files, volumes...)
+}
 
-		if !util.IsPar2SetMember(job.par2Name, name) {
+func (prog *Service) removeFiles(ctx context.Context, job *Job, files []string) {
+	for _, f := range files {
+		if f == "" {
 			continue
 		}
-
-		path := filepath.Join(job.workingDir, name)
-		if err := prog.fsys.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
-			logger := prog.creationLogger(ctx, job, path)
+		if err := prog.fsys.Remove(f); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger := prog.creationLogger(ctx, job, f)
 			logger.Warn("Failed to cleanup a file after failure (needs manual deletion)", "error", err)
 		}
 	}
+}
 
-	for _, f := range []string{job.manifestPath, job.lockPath} {
-		if err := prog.fsys.Remove(f); err != nil && !errors.Is(err, fs.ErrNotExist) {
-			logger := prog.creationLogger(ctx, job, f)
-			logger.Warn("Failed to cleanup a file after failure (needs manual deletion)", "error", err)
+// validateGlobExcludes validates the patterns excluded from the glob, which
+// underlie the same restrictions as the glob itself in the recursive mode.
+func validateGlobExcludes(patterns []string, mode string) error {
+	for _, pattern := range patterns {
+		if ok := doublestar.ValidatePattern(pattern); !ok || pattern == "" {
+			return fmt.Errorf("glob-exclude: %q: %w", pattern, doublestar.ErrBadPattern)
+		}
+		if mode == schema.CreateRecursiveMode && util.IsGlobRecursive(pattern) {
+			return fmt.Errorf("glob-exclude: %q: %w", pattern, schema.ErrUnsupportedGlob)
 		}
 	}
+
+	return nil
 }
 
 func (prog *Service) considerRecursive(opts *Options) error {
@@ -77,6 +102,14 @@ func (prog *Service) considerRecursive(opts *Options) error {
 }
 
 func (prog *Service) par2AlreadyExists(ctx context.Context, job *Job) (bool, error) {
+	path, err := prog.existingPar2(ctx, job)
+
+	return path != "", err
+}
+
+// existingPar2 returns the path of a same-named PAR2 (or bundle) existing in
+// the job's folder, or an empty path if there is no such PAR2 in the folder.
+func (prog *Service) existingPar2(ctx context.Context, job *Job) (string, error) {
 	baseName := util.TrimSuffixFold(job.par2Name, schema.Par2Extension)
 	baseName = strings.TrimPrefix(baseName, ".")
 
@@ -104,15 +137,145 @@ func (prog *Service) par2AlreadyExists(ctx context.Context, job *Job) (bool, err
 				logger.Warn("Same-named PAR2 already exists in folder (not overwriting)", "path", path)
 			}
 
-			return true, nil
+			return path, nil
 		} else if !errors.Is(err, fs.ErrNotExist) {
-			return false, fmt.Errorf("failed to stat: %w", err)
+			return "", fmt.Errorf("failed to stat: %w", err)
 		}
 	}
 
+	return "", nil
+}
+
+// hasFolderContent returns if the job's folder contains any non-hidden entries
+// other than the marker file and files belonging to PAR2 sets.
+func (prog *Service) hasFolderContent(job *Job) (bool, error) {
+	entries, err := afero.ReadDir(prog.fsys, job.workingDir)
+	if err != nil {
+		return false, fmt.Errorf("failed to read dir: %w", err)
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, ".") || name == filepath.Base(job.markerPath) || name == job.stampFile {
+			continue
+		}
+		if util.EndsWithFold(name, schema.Par2Extension) ||
+			job.sidecarNames.IsLock(name) ||
+			job.sidecarNames.IsManifest(name) {
+			continue
+		}
+
+		return true, nil
+	}
+
 	return false, nil
 }
 
+// hasData returns if any of the elements is a directory or non-empty file,
+// as par2 cannot create a PAR2 set from only empty files (no data to protect).
+func hasData(elements []schema.FsElement) bool {
+	return slices.ContainsFunc(elements, func(e schema.FsElement) bool {
+		return e.IsDir || e.Size > 0
+	})
+}
+
+// withoutEmptyDirs returns the elements without those of directories that
+// contain only empty files, as each directory becomes its own PAR2 set.
+func withoutEmptyDirs(elements []schema.FsElement) []schema.FsElement {
+	groups := make(map[string][]schema.FsElement)
+	for _, e := range elements {
+		dir := filepath.Dir(e.Path)
+		groups[dir] = append(groups[dir], e)
+	}
+
+	return slices.DeleteFunc(elements, func(e schema.FsElement) bool {
+		return !hasData(groups[filepath.Dir(e.Path)])
+	})
+}
+
+const (
+	// par2MaxBlockCount is the maximum number of source blocks supported by par2.
+	par2MaxBlockCount = 32768
+
+	// minTargetBlockSize is the smallest block size supported by par2 (a multiple of 4).
+	minTargetBlockSize = 4
+)
+
+// par2BlockCount returns the number of source blocks to split the total size
+// into blocks of roughly the target block size, which par2 requires to be at
+// least the number of files and at most [par2MaxBlockCount]. Zero is returned
+// without a total size or target block size, in which case none should be set.
+func par2BlockCount(totalSize int64, files int, targetBlockSize int64) int {
+	if totalSize <= 0 || targetBlockSize <= 0 {
+		return 0
+	}
+
+	count := (totalSize + targetBlockSize - 1) / targetBlockSize
+	count = max(count, int64(files), 1)
+
+	return int(min(count, par2MaxBlockCount))
+}
+
+// withBlockCount returns the par2 arguments of the job with a block count (-b)
+// added, computed from the total size of the elements to hit roughly the job's
+// target block size (--target-block-size). The arguments are returned as they
+// are without a target block size, or when they already set a block count (-b)
+// or block size (-s) of their own, such as from a marker file.
+func (prog *Service) withBlockCount(ctx context.Context, job *Job, elements []schema.FsElement) []string {
+	if job.targetBlockSize <= 0 {
+		return job.par2Args
+	}
+
+	logger := prog.creationLogger(ctx, job, job.par2Path)
+
+	if util.HasPar2BlockArg(job.par2Args) {
+		logger.Debug("Not computing a block count (par2 arguments already set -b or -s)", "args", job.par2Args)
+
+		return job.par2Args
+	}
+
+	totalSize, files := prog.protectedSize(elements)
+
+	count := par2BlockCount(totalSize, files, job.targetBlockSize)
+	if count == 0 {
+		return job.par2Args
+	}
+
+	args := append(slices.Clone(job.par2Args), "-b"+strconv.Itoa(count))
+	logger.Debug("Computed block count from the target block size (--target-block-size)",
+		"totalSize", totalSize, "files", files,
+		"targetBlockSize", job.targetBlockSize, "blockCount", count)
+
+	return args
+}
+
+// protectedSize returns the total size and number of files protected by the
+// elements, including the contents of directories (in recursive mode).
+func (prog *Service) protectedSize(elements []schema.FsElement) (int64, int) {
+	var totalSize int64
+	var files int
+
+	for _, e := range elements {
+		if !e.IsDir {
+			totalSize += e.Size
+			files++
+
+			continue
+		}
+
+		_ = afero.Walk(prog.fsys, e.Path, func(_ string, info fs.FileInfo, err error) error {
+			if err == nil && info.Mode().IsRegular() {
+				totalSize += info.Size()
+				files++
+			}
+
+			return nil
+		})
+	}
+
+	return totalSize, files
+}
+
 func getPaths(files []schema.FsElement) []string {
 	paths := make([]string, len(files))
 	for i, f := range files {
@@ -0,0 +1,207 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/flags"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+)
+
+var errNeedsAttention = errors.New("PAR2 sets need attention")
+
+type AttentionOptions struct {
+	Overdue         flags.Duration
+	IncludeExternal bool
+	SkipNotCreated  bool
+
+	IgnoreNames     util.IgnoreNames
+	SidecarNames    util.SidecarNames
+	MaxDepth        flags.MaxDepth
+	MaxManifestSize int64
+}
+
+// AttentionResult contains the complete attention command output (for JSON).
+type AttentionResult struct {
+	// Roots are the root directories for this result.
+	Roots []string `json:"roots"`
+
+	// Time is when this result was generated.
+	Time time.Time `json:"time"`
+
+	// Sets are the PAR2 sets needing attention, sorted by path.
+	Sets []*AttentionEntry `json:"sets"`
+}
+
+// AttentionEntry is a single PAR2 set needing attention.
+type AttentionEntry struct {
+	// Path is the path of the PAR2 index file (or bundle).
+	Path string `json:"path"`
+
+	// Verdict is the outcome of the last verification (repairable,
+	// unrepairable or unverified, the latter only when overdue).
+	Verdict string `json:"verdict"`
+
+	// Created is the time of the creation, if recorded.
+	Created *time.Time `json:"created,omitempty"`
+
+	// LastVerified is the time of the last verification (completion), if any.
+	LastVerified *time.Time `json:"last_verified,omitempty"`
+
+	// OverdueSince is when an unverified PAR2 set became overdue.
+	OverdueSince *time.Time `json:"overdue_since,omitempty"`
+
+	// CorruptedCount is the number of consecutive corrupted verifications.
+	CorruptedCount int `json:"corrupted_count"`
+
+	// LastError is the error of the last failed verification or repair, if any.
+	LastError string `json:"last_error,omitempty"`
+
+	// LastErrorAt is the time of the last error, if any.
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+
+	// LastErrorOp is the operation that failed with the last error, if any.
+	LastErrorOp string `json:"last_error_op,omitempty"`
+}
+
+// Attention enumerates all PAR2 sets below the root directories and writes
+// the ones needing attention to standard output: those needing repair, those
+// unrepairable and those never verified within the overdue period after their
+// creation (or their own verification interval, if set). The exit code is the
+// one of the most severe verdict, with overdue sets being warnings.
+func (prog *Service) Attention(ctx context.Context, rootDirs []string, opts AttentionOptions) error {
+	records, rerr := prog.Records(ctx, rootDirs, Options{
+		IncludeExternal: opts.IncludeExternal,
+		SkipNotCreated:  opts.SkipNotCreated,
+		IgnoreNames:     opts.IgnoreNames,
+		SidecarNames:    opts.SidecarNames,
+		MaxDepth:        opts.MaxDepth,
+		MaxManifestSize: opts.MaxManifestSize,
+	})
+	if rerr != nil && !errors.Is(rerr, schema.ErrExitPartialFailure) {
+		return rerr
+	}
+
+	now := time.Now()
+
+	entries := []*AttentionEntry{}
+	for _, rec := range records {
+		if entry := newAttentionEntry(rec, opts.Overdue.Value, now); entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	var err error
+	if prog.log.Options.WantJSON {
+		err = writeJSON(prog.log.Options.Stdout, &AttentionResult{
+			Roots: prog.log.MapPaths(rootDirs),
+			Time:  now,
+			Sets:  entries,
+		})
+	} else {
+		prog.printAttention(entries)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write json: %w", err)
+	}
+
+	if aerr := attentionError(entries); aerr != nil {
+		return errors.Join(aerr, rerr)
+	}
+
+	return rerr
+}
+
+// newAttentionEntry returns the entry for a record needing attention, or nil.
+// Unverified sets without a creation time are left out, as these cannot be
+// overdue (these are mostly external sets waiting for their first verify).
+func newAttentionEntry(rec *Record, overdue time.Duration, now time.Time) *AttentionEntry {
+	entry := &AttentionEntry{
+		Path:           rec.Path,
+		Verdict:        rec.Verdict,
+		Created:        rec.Created,
+		LastVerified:   rec.LastVerified,
+		CorruptedCount: rec.CorruptedCount,
+		LastError:      rec.LastError,
+		LastErrorAt:    rec.LastErrorAt,
+		LastErrorOp:    rec.LastErrorOp,
+	}
+
+	switch rec.Verdict {
+	case VerdictRepairable, VerdictUnrepairable:
+		return entry
+
+	case VerdictUnverified:
+		if rec.Created == nil {
+			return nil
+		}
+
+		if rec.VerifyInterval > 0 {
+			overdue = rec.VerifyInterval
+		}
+
+		since := rec.Created.Add(overdue)
+		if since.After(now) {
+			return nil
+		}
+		entry.OverdueSince = &since
+
+		return entry
+
+	default:
+		return nil
+	}
+}
+
+func attentionError(entries []*AttentionEntry) error {
+	var repairables, unrepairables int
+	for _, entry := range entries {
+		switch entry.Verdict {
+		case VerdictRepairable:
+			repairables++
+		case VerdictUnrepairable:
+			unrepairables++
+		}
+	}
+
+	switch {
+	case unrepairables > 0:
+		return fmt.Errorf("%w: %w (%d)", schema.ErrExitUnrepairable, errNeedsAttention, len(entries))
+	case repairables > 0:
+		return fmt.Errorf("%w: %w (%d)", schema.ErrExitRepairable, errNeedsAttention, len(entries))
+	case len(entries) > 0:
+		return fmt.Errorf("%w: %w (%d)", schema.ErrExitWarnings, errNeedsAttention, len(entries))
+	default:
+		return nil
+	}
+}
+
+func (prog *Service) printAttention(entries []*AttentionEntry) {
+	if len(entries) == 0 {
+		fmt.Fprintf(prog.log.Options.Stdout, "No PAR2 sets need attention\n")
+
+		return
+	}
+
+	for _, entry := range entries {
+		detail := "never verified"
+		if entry.LastVerified != nil {
+			detail = "last verified " + entry.LastVerified.Format(time.RFC3339)
+		}
+		if entry.OverdueSince != nil {
+			detail += ", overdue since " + entry.OverdueSince.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(prog.log.Options.Stdout, "%-12s  %s (%s)\n", entry.Verdict, entry.Path, detail)
+		if entry.LastError != "" {
+			fmt.Fprintf(prog.log.Options.Stdout, "%-12s  last %s error at %s: %s\n", "",
+				entry.LastErrorOp, entry.LastErrorAt.Format(time.RFC3339), strconv.Quote(entry.LastError))
+		}
+	}
+
+	fmt.Fprintf(prog.log.Options.Stdout, "\n%d PAR2 sets need attention\n", len(entries))
+}
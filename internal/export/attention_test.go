@@ -0,0 +1,200 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func attentionManifest(name string, created time.Time, verified bool, repairNeeded bool, repairPossible bool) *schema.Manifest {
+	mf := schema.NewManifest(name)
+	mf.Creation = schema.NewCreationManifest()
+	mf.Creation.Time = created
+
+	if verified {
+		mf.Verification = schema.NewVerificationManifest()
+		mf.Verification.Time = created.Add(time.Hour)
+		mf.Verification.RepairNeeded = repairNeeded
+		mf.Verification.RepairPossible = repairPossible
+		if repairNeeded {
+			mf.Verification.CountCorrupted = 2
+		}
+	}
+
+	return mf
+}
+
+func newAttentionOptions(t *testing.T, overdue string) AttentionOptions {
+	t.Helper()
+
+	opts := AttentionOptions{}
+	require.NoError(t, opts.Overdue.Set(overdue))
+
+	return opts
+}
+
+// Expectation: A repairable set should need attention, with the repairable exit code.
+func Test_Service_Attention_Repairable_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	writeTestSet(t, fs, "/data/set.par2", attentionManifest("set.par2", time.Now(), true, true, true))
+
+	var stdout bytes.Buffer
+	prog := newTestService(t, fs, &stdout)
+
+	err := prog.Attention(t.Context(), []string{"/data"}, newAttentionOptions(t, "7d"))
+
+	require.ErrorIs(t, err, schema.ErrExitRepairable)
+	require.ErrorIs(t, err, errNeedsAttention)
+	require.Contains(t, stdout.String(), VerdictRepairable)
+	require.Contains(t, stdout.String(), "/data/set.par2")
+	require.Contains(t, stdout.String(), "last verified")
+}
+
+// Expectation: The last error recorded for a set needing attention should be shown inline.
+func Test_Service_Attention_LastError_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	mf := attentionManifest("set.par2", time.Now(), true, true, true)
+	mf.SetLastError("repair", errors.New("par2 repair failed: exit status 5"))
+	writeTestSet(t, fs, "/data/set.par2", mf)
+
+	var stdout bytes.Buffer
+	prog := newTestService(t, fs, &stdout)
+
+	err := prog.Attention(t.Context(), []string{"/data"}, newAttentionOptions(t, "7d"))
+
+	require.ErrorIs(t, err, schema.ErrExitRepairable)
+	require.Contains(t, stdout.String(), `last repair error at `)
+	require.Contains(t, stdout.String(), `"par2 repair failed: exit status 5"`)
+}
+
+// Expectation: An unrepairable set should need attention, with the unrepairable exit code taking precedence.
+func Test_Service_Attention_Unrepairable_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	writeTestSet(t, fs, "/data/a.par2", attentionManifest("a.par2", time.Now(), true, true, true))
+	writeTestSet(t, fs, "/data/b.par2", attentionManifest("b.par2", time.Now(), true, true, false))
+
+	var stdout bytes.Buffer
+	prog := newTestService(t, fs, &stdout)
+
+	err := prog.Attention(t.Context(), []string{"/data"}, newAttentionOptions(t, "7d"))
+
+	require.ErrorIs(t, err, schema.ErrExitUnrepairable)
+	require.Equal(t, schema.ExitCodeUnrepairable, schema.ExitCodeFor(err))
+	require.Contains(t, stdout.String(), VerdictUnrepairable)
+	require.Contains(t, stdout.String(), "2 PAR2 sets need attention")
+}
+
+// Expectation: A never verified set should need attention once overdue, as a warning.
+func Test_Service_Attention_Overdue_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	writeTestSet(t, fs, "/data/set.par2", attentionManifest("set.par2", time.Now().Add(-8*24*time.Hour), false, false, false))
+
+	var stdout bytes.Buffer
+	prog := newTestService(t, fs, &stdout)
+
+	err := prog.Attention(t.Context(), []string{"/data"}, newAttentionOptions(t, "7d"))
+
+	require.ErrorIs(t, err, schema.ErrExitWarnings)
+	require.Contains(t, stdout.String(), VerdictUnverified)
+	require.Contains(t, stdout.String(), "never verified, overdue since")
+}
+
+// Expectation: Healthy, not yet overdue and creation-less sets should not need attention.
+func Test_Service_Attention_None_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	writeTestSet(t, fs, "/data/healthy.par2", attentionManifest("healthy.par2", time.Now().Add(-30*24*time.Hour), true, false, false))
+	writeTestSet(t, fs, "/data/recent.par2", attentionManifest("recent.par2", time.Now().Add(-24*time.Hour), false, false, false))
+	writeTestSet(t, fs, "/data/adopted.par2", schema.NewManifest("adopted.par2"))
+	writeTestSet(t, fs, "/data/external.par2", nil)
+
+	var stdout bytes.Buffer
+	prog := newTestService(t, fs, &stdout)
+
+	opts := newAttentionOptions(t, "7d")
+	opts.IncludeExternal = true
+
+	require.NoError(t, prog.Attention(t.Context(), []string{"/data"}, opts))
+	require.Contains(t, stdout.String(), "No PAR2 sets need attention")
+}
+
+// Expectation: The per-set verification interval should take precedence over the overdue period.
+func Test_Service_Attention_VerifyInterval_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	mf := attentionManifest("set.par2", time.Now().Add(-2*24*time.Hour), false, false, false)
+	mf.Creation.VerifyInterval = 24 * time.Hour
+	writeTestSet(t, fs, "/data/set.par2", mf)
+
+	prog := newTestService(t, fs, io.Discard)
+
+	err := prog.Attention(t.Context(), []string{"/data"}, newAttentionOptions(t, "7d"))
+
+	require.ErrorIs(t, err, schema.ErrExitWarnings)
+}
+
+// Expectation: The JSON output should contain only the sets needing attention, with their details.
+func Test_Service_Attention_JSON_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	writeTestSet(t, fs, "/data/a.par2", attentionManifest("a.par2", time.Now(), true, true, true))
+	writeTestSet(t, fs, "/data/b.par2", attentionManifest("b.par2", time.Now(), true, false, false))
+	writeTestSet(t, fs, "/data/c.par2", attentionManifest("c.par2", time.Now().Add(-8*24*time.Hour), false, false, false))
+
+	var stdout bytes.Buffer
+	ls := logging.Options{
+		Logout:   io.Discard,
+		Stdout:   &stdout,
+		Stderr:   io.Discard,
+		WantJSON: true,
+	}
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	err := prog.Attention(t.Context(), []string{"/data"}, newAttentionOptions(t, "7d"))
+	require.ErrorIs(t, err, schema.ErrExitRepairable)
+
+	var result AttentionResult
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+	require.Equal(t, []string{"/data"}, result.Roots)
+	require.Len(t, result.Sets, 2)
+
+	require.Equal(t, "/data/a.par2", result.Sets[0].Path)
+	require.Equal(t, VerdictRepairable, result.Sets[0].Verdict)
+	require.NotNil(t, result.Sets[0].LastVerified)
+	require.Nil(t, result.Sets[0].OverdueSince)
+	require.Equal(t, 2, result.Sets[0].CorruptedCount)
+
+	require.Equal(t, "/data/c.par2", result.Sets[1].Path)
+	require.Equal(t, VerdictUnverified, result.Sets[1].Verdict)
+	require.Nil(t, result.Sets[1].LastVerified)
+	require.NotNil(t, result.Sets[1].OverdueSince)
+}
@@ -0,0 +1,286 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/flags"
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/desertwitch/par2cron/internal/verify"
+	"github.com/spf13/afero"
+)
+
+const (
+	VerdictHealthy      string = "healthy"
+	VerdictRepairable   string = "repairable"
+	VerdictUnrepairable string = "unrepairable"
+	VerdictUnverified   string = "unverified"
+)
+
+type Options struct {
+	Format          flags.ExportFormat
+	IncludeExternal bool
+	SkipNotCreated  bool
+
+	IgnoreNames     util.IgnoreNames
+	SidecarNames    util.SidecarNames
+	MaxDepth        flags.MaxDepth
+	MaxManifestSize int64
+}
+
+type Service struct {
+	fsys afero.Fs
+
+	log     *logging.Logger
+	runner  schema.CommandRunner
+	bundler schema.BundleHandler
+	cacher  schema.CacheHandler
+}
+
+func NewService(fsys afero.Fs, log *logging.Logger, runner schema.CommandRunner, bundler schema.BundleHandler, cacher schema.CacheHandler) *Service {
+	return &Service{
+		fsys:    fsys,
+		log:     log.With("op", "export"),
+		runner:  runner,
+		bundler: bundler,
+		cacher:  cacher,
+	}
+}
+
+// Result contains the complete export command output (for JSON).
+type Result struct {
+	// Roots are the root directories for this result.
+	Roots []string `json:"roots"`
+
+	// Time is when this result was generated.
+	Time time.Time `json:"time"`
+
+	// Sets are the records of all exported PAR2 sets, sorted by path.
+	Sets []*Record `json:"sets"`
+}
+
+// Record is the flattened manifest of a single PAR2 set.
+type Record struct {
+	// Path is the path of the PAR2 index file (or bundle).
+	Path string `json:"path"`
+
+	// Bundle is true if the PAR2 set is a bundle.
+	Bundle bool `json:"bundle"`
+
+	// Managed is true if the PAR2 set has a par2cron manifest.
+	Managed bool `json:"managed"`
+
+	// Created is the time of the creation, if recorded.
+	Created *time.Time `json:"created,omitempty"`
+
+	// CreationMode is the mode used for the creation, if recorded.
+	CreationMode string `json:"creation_mode,omitempty"`
+
+	// Files is the number of protected files, if recorded.
+	Files int `json:"files"`
+
+	// TotalSize is the size of all protected files (at creation), if recorded.
+	TotalSize int64 `json:"total_size"`
+
+	// VerifyInterval is the per-set verification interval (0 = none).
+	VerifyInterval time.Duration `json:"verify_interval_ns"`
+
+	// Verdict is the outcome of the last verification.
+	Verdict string `json:"verdict"`
+
+	// LastVerified is the time of the last verification, if any.
+	LastVerified *time.Time `json:"last_verified,omitempty"`
+
+	// VerifyCompleted is the completion time of the last verification, if recorded.
+	VerifyCompleted *time.Time `json:"verify_completed,omitempty"`
+
+	// VerifyCount is the number of verifications.
+	VerifyCount int `json:"verify_count"`
+
+	// CorruptedCount is the number of consecutive corrupted verifications.
+	CorruptedCount int `json:"corrupted_count"`
+
+	// VerifyExitCode is the par2 exit code of the last verification, if any.
+	VerifyExitCode *int `json:"verify_exit_code,omitempty"`
+
+	// VerifyDuration is the duration of the last verification.
+	VerifyDuration time.Duration `json:"verify_duration_ns"`
+
+	// LastRepaired is the time of the last repair, if any.
+	LastRepaired *time.Time `json:"last_repaired,omitempty"`
+
+	// RepairCompleted is the completion time of the last repair, if recorded.
+	RepairCompleted *time.Time `json:"repair_completed,omitempty"`
+
+	// RepairCount is the number of repairs.
+	RepairCount int `json:"repair_count"`
+
+	// RepairExitCode is the par2 exit code of the last repair, if any.
+	RepairExitCode *int `json:"repair_exit_code,omitempty"`
+
+	// Interrupted is the operation that was interrupted, if any.
+	Interrupted string `json:"interrupted,omitempty"`
+	// LastError is the error of the last failed verification or repair, if any.
+	LastError string `json:"last_error,omitempty"`
+	// LastErrorAt is the time of the last error, if any.
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+	// LastErrorOp is the operation that failed with the last error, if any.
+	LastErrorOp string `json:"last_error_op,omitempty"`
+}
+
+// Export enumerates all PAR2 sets below the root directories, loads their
+// manifests and writes them as one consolidated JSON document or CSV table.
+// Sets that could not be read are left out, returning a partial failure.
+func (prog *Service) Export(ctx context.Context, rootDirs []string, opts Options) error {
+	records, rerr := prog.Records(ctx, rootDirs, opts)
+	if rerr != nil && !errors.Is(rerr, schema.ErrExitPartialFailure) {
+		return rerr
+	}
+
+	var err error
+	switch opts.Format.Value {
+	case schema.ExportFormatCSV:
+		err = writeCSV(prog.log.Options.Stdout, records)
+	default:
+		err = writeJSON(prog.log.Options.Stdout, &Result{
+			Roots: prog.log.MapPaths(rootDirs),
+			Time:  time.Now(),
+			Sets:  records,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", opts.Format.Value, err)
+	}
+
+	return rerr
+}
+
+// Records returns the records of all PAR2 sets below the root directories.
+// The manifest cache is not used, as the full manifests are needed anyway.
+func (prog *Service) Records(ctx context.Context, rootDirs []string, opts Options) ([]*Record, error) {
+	vs := verify.NewService(prog.fsys, prog.log, prog.runner, prog.bundler, prog.cacher)
+	va := verify.Options{IncludeExternal: opts.IncludeExternal, SkipNotCreated: opts.SkipNotCreated, IgnoreNames: opts.IgnoreNames, SidecarNames: opts.SidecarNames, MaxDepth: opts.MaxDepth, MaxManifestSize: opts.MaxManifestSize}
+
+	records := []*Record{}
+	errs := []error{}
+	for _, rootDir := range rootDirs {
+		metas, err := vs.Enumerate(ctx, rootDir, va, prog.cacher.NewCache(prog.fsys, "", rootDir))
+		if err != nil {
+			if !errors.Is(err, schema.ErrNonFatal) {
+				return nil, fmt.Errorf("%s: failed to enumerate jobs: %w", rootDir, err)
+			}
+
+			errs = append(errs, fmt.Errorf("%s: %w", rootDir, err))
+		}
+
+		for _, meta := range metas {
+			if err := ctx.Err(); err != nil {
+				return nil, fmt.Errorf("context error: %w", err)
+			}
+
+			var mf *schema.Manifest
+			if meta.HasManifest {
+				mf, err = vs.LoadManifest(ctx, meta, va)
+				if err != nil {
+					prog.log.Error("Failed to load par2cron manifest (skipping)", "path", meta.Par2Path, "error", err)
+					errs = append(errs, fmt.Errorf("%s: %w", meta.Par2Path, err))
+
+					continue
+				}
+			}
+
+			records = append(records, newRecord(prog.log.MapPath(meta.Par2Path), mf, meta.IsBundle))
+		}
+	}
+
+	slices.SortFunc(records, func(a, b *Record) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	if len(errs) > 0 {
+		return records, fmt.Errorf("%w: %w", schema.ErrExitPartialFailure, errors.Join(errs...))
+	}
+
+	return records, nil
+}
+
+func newRecord(par2Path string, mf *schema.Manifest, isBundle bool) *Record {
+	rec := &Record{
+		Path:    escapePath(par2Path),
+		Bundle:  isBundle,
+		Managed: mf != nil,
+		Verdict: verdict(schema.NewJobMeta(par2Path, mf, isBundle)),
+	}
+
+	if mf == nil {
+		return rec
+	}
+
+	if mf.Creation != nil {
+		rec.Created = &mf.Creation.Time
+		rec.CreationMode = mf.Creation.Mode
+		rec.VerifyInterval = mf.Creation.VerifyInterval
+
+		for _, elem := range mf.Creation.Elements {
+			if elem.IsDir {
+				continue
+			}
+			rec.Files++
+			rec.TotalSize += elem.Size
+		}
+	}
+
+	if mf.Verification != nil {
+		rec.LastVerified = &mf.Verification.Time
+		if !mf.Verification.CompletedAt.IsZero() {
+			rec.VerifyCompleted = &mf.Verification.CompletedAt
+		}
+		rec.VerifyCount = mf.Verification.Count
+		rec.CorruptedCount = mf.Verification.CountCorrupted
+		rec.VerifyExitCode = &mf.Verification.ExitCode
+		rec.VerifyDuration = mf.Verification.Duration
+	}
+
+	if mf.Repair != nil {
+		rec.LastRepaired = &mf.Repair.Time
+		if !mf.Repair.CompletedAt.IsZero() {
+			rec.RepairCompleted = &mf.Repair.CompletedAt
+		}
+		rec.RepairCount = mf.Repair.Count
+		rec.RepairExitCode = &mf.Repair.ExitCode
+	}
+
+	if mf.Interruption != nil {
+		rec.Interrupted = mf.Interruption.Operation
+	}
+
+	if mf.LastError != "" {
+		rec.LastError = mf.LastError
+		rec.LastErrorAt = &mf.LastErrorAt
+		rec.LastErrorOp = mf.LastErrorOp
+	}
+
+	return rec
+}
+
+func verdict(meta *schema.JobMeta) string {
+	switch {
+	case !meta.HasManifest || !meta.HasVerification:
+		return VerdictUnverified
+
+	case meta.RepairNeeded && meta.RepairPossible:
+		return VerdictRepairable
+
+	case meta.RepairNeeded && !meta.RepairPossible:
+		return VerdictUnrepairable
+
+	default:
+		return VerdictHealthy
+	}
+}
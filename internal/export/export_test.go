@@ -0,0 +1,231 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestSet(t *testing.T, fs afero.Fs, par2Path string, mf *schema.Manifest) {
+	t.Helper()
+
+	require.NoError(t, afero.WriteFile(fs, par2Path, []byte("par2data"), 0o644))
+
+	if mf == nil {
+		return
+	}
+
+	data, err := json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, par2Path+schema.ManifestExtension, data, 0o644))
+}
+
+func newTestService(t *testing.T, fs afero.Fs, stdout io.Writer) *Service {
+	t.Helper()
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: stdout,
+		Stderr: io.Discard,
+	}
+
+	return NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+}
+
+func testManifest(name string) *schema.Manifest {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	mf := schema.NewManifest(name)
+	mf.Creation = schema.NewCreationManifest()
+	mf.Creation.Time = created
+	mf.Creation.Mode = schema.CreateFolderMode
+	mf.Creation.Elements = []schema.FsElement{
+		{Name: "a.bin", Size: 100},
+		{Name: "b.bin", Size: 50},
+		{Name: "sub", IsDir: true, Size: 4096},
+	}
+	mf.Verification = schema.NewVerificationManifest()
+	mf.Verification.Time = created.Add(time.Hour)
+	mf.Verification.Count = 3
+	mf.Verification.ExitCode = schema.Par2ExitCodeRepairPossible
+	mf.Verification.RepairNeeded = true
+	mf.Verification.RepairPossible = true
+	mf.Repair = schema.NewRepairManifest()
+	mf.Repair.Time = created.Add(2 * time.Hour)
+	mf.Repair.Count = 1
+
+	return mf
+}
+
+// Expectation: The records should be flattened from the manifests and sorted by path.
+func Test_Service_Records_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/b", 0o755))
+	require.NoError(t, fs.MkdirAll("/data/a", 0o755))
+	writeTestSet(t, fs, "/data/b/b.par2", testManifest("b.par2"))
+	writeTestSet(t, fs, "/data/a/a.par2", schema.NewManifest("a.par2"))
+
+	prog := newTestService(t, fs, io.Discard)
+
+	records, err := prog.Records(t.Context(), []string{"/data"}, Options{})
+
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	require.Equal(t, "/data/a/a.par2", records[0].Path)
+	require.True(t, records[0].Managed)
+	require.Equal(t, VerdictUnverified, records[0].Verdict)
+	require.Nil(t, records[0].Created)
+	require.Nil(t, records[0].VerifyExitCode)
+
+	rec := records[1]
+	require.Equal(t, "/data/b/b.par2", rec.Path)
+	require.Equal(t, schema.CreateFolderMode, rec.CreationMode)
+	require.Equal(t, 2, rec.Files)
+	require.EqualValues(t, 150, rec.TotalSize)
+	require.Equal(t, VerdictRepairable, rec.Verdict)
+	require.Equal(t, 3, rec.VerifyCount)
+	require.Equal(t, schema.Par2ExitCodeRepairPossible, *rec.VerifyExitCode)
+	require.Equal(t, 1, rec.RepairCount)
+	require.NotNil(t, rec.LastRepaired)
+}
+
+// Expectation: External sets should only be exported with IncludeExternal, as unmanaged.
+func Test_Service_Records_IncludeExternal_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	writeTestSet(t, fs, "/data/ext.par2", nil)
+
+	prog := newTestService(t, fs, io.Discard)
+
+	records, err := prog.Records(t.Context(), []string{"/data"}, Options{})
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	records, err = prog.Records(t.Context(), []string{"/data"}, Options{IncludeExternal: true})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.False(t, records[0].Managed)
+	require.Equal(t, VerdictUnverified, records[0].Verdict)
+}
+
+// Expectation: The JSON export should contain all sets as one document.
+func Test_Service_Export_JSON_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	writeTestSet(t, fs, "/data/set.par2", testManifest("set.par2"))
+
+	var stdout bytes.Buffer
+	prog := newTestService(t, fs, &stdout)
+
+	opts := Options{}
+	require.NoError(t, opts.Format.Set(schema.ExportFormatJSON))
+	require.NoError(t, prog.Export(t.Context(), []string{"/data"}, opts))
+
+	var result Result
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+	require.Equal(t, []string{"/data"}, result.Roots)
+	require.Len(t, result.Sets, 1)
+	require.Equal(t, "/data/set.par2", result.Sets[0].Path)
+	require.Equal(t, VerdictRepairable, result.Sets[0].Verdict)
+}
+
+// Expectation: The CSV export should quote paths with separators and keep Unicode paths intact.
+func Test_Service_Export_CSV_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	dir := "/data/Ünïcödé, \"quoted\"\n日本"
+	require.NoError(t, fs.MkdirAll(dir, 0o755))
+	writeTestSet(t, fs, dir+"/set.par2", testManifest("set.par2"))
+
+	var stdout bytes.Buffer
+	prog := newTestService(t, fs, &stdout)
+
+	opts := Options{}
+	require.NoError(t, opts.Format.Set(schema.ExportFormatCSV))
+	require.NoError(t, prog.Export(t.Context(), []string{"/data"}, opts))
+
+	rows, err := csv.NewReader(&stdout).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, csvHeader, rows[0])
+	require.Len(t, rows[1], len(csvHeader))
+	require.Equal(t, dir+"/set.par2", rows[1][0])
+	require.Equal(t, "2026-01-02T03:04:05Z", rows[1][3])
+	require.Equal(t, VerdictRepairable, rows[1][8])
+	require.Equal(t, "0", rows[1][16])
+	require.Empty(t, rows[1][17])
+}
+
+// Expectation: Invalid UTF-8 bytes should be escaped, valid paths left unchanged.
+func Test_escapePath_Success(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "/data/日本.par2", escapePath("/data/日本.par2"))
+	require.Equal(t, `/data/a\xff\xfeb.par2`, escapePath("/data/a\xff\xfeb.par2"))
+}
+
+// Expectation: An unreadable bundle should be left out and a partial failure returned.
+func Test_Service_Export_BundleOpen_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	writeTestSet(t, fs, "/data/good.par2", testManifest("good.par2"))
+	require.NoError(t, afero.WriteFile(fs, "/data/bad"+schema.BundleExtension+schema.Par2Extension, []byte("garbage"), 0o644))
+
+	var stdout bytes.Buffer
+	prog := newTestService(t, fs, &stdout)
+
+	err := prog.Export(t.Context(), []string{"/data"}, Options{})
+
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+
+	var result Result
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+	require.Len(t, result.Sets, 1)
+	require.Equal(t, "/data/good.par2", result.Sets[0].Path)
+}
+
+// Expectation: The roots and paths should be exported with the path prefix map applied.
+func Test_Service_Export_PathPrefixMap_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/sub", 0o755))
+	writeTestSet(t, fs, "/data/sub/set.par2", testManifest("set.par2"))
+
+	var stdout bytes.Buffer
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: &stdout,
+		Stderr: io.Discard,
+	}
+	require.NoError(t, ls.PathPrefixMap.Set("/data=/mnt/user/data"))
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	require.NoError(t, prog.Export(t.Context(), []string{"/data"}, Options{}))
+
+	var result Result
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+	require.Equal(t, []string{"/mnt/user/data"}, result.Roots)
+	require.Len(t, result.Sets, 1)
+	require.Equal(t, "/mnt/user/data/sub/set.par2", result.Sets[0].Path)
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var csvHeader = []string{
+	"path",
+	"bundle",
+	"managed",
+	"created",
+	"creation_mode",
+	"files",
+	"total_size",
+	"verify_interval_ns",
+	"verdict",
+	"last_verified",
+	"verify_count",
+	"corrupted_count",
+	"verify_exit_code",
+	"verify_duration_ns",
+	"last_repaired",
+	"repair_count",
+	"repair_exit_code",
+	"interrupted",
+	"verify_completed",
+	"repair_completed",
+	"last_error",
+	"last_error_at",
+	"last_error_op",
+}
+
+func writeJSON(w io.Writer, result any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(result); err != nil {
+		return fmt.Errorf("failed to encode: %w", err)
+	}
+
+	return nil
+}
+
+// writeCSV writes the records as an RFC 4180 table (UTF-8, with header),
+// where quoting of paths containing separators, quotes or line breaks is left
+// to [csv.Writer] and absent values (times, exit codes) are empty fields.
+func writeCSV(w io.Writer, records []*Record) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	for _, rec := range records {
+		row := []string{
+			rec.Path,
+			strconv.FormatBool(rec.Bundle),
+			strconv.FormatBool(rec.Managed),
+			fmtTime(rec.Created),
+			rec.CreationMode,
+			strconv.Itoa(rec.Files),
+			strconv.FormatInt(rec.TotalSize, 10),
+			strconv.FormatInt(int64(rec.VerifyInterval), 10),
+			rec.Verdict,
+			fmtTime(rec.LastVerified),
+			strconv.Itoa(rec.VerifyCount),
+			strconv.Itoa(rec.CorruptedCount),
+			fmtInt(rec.VerifyExitCode),
+			strconv.FormatInt(int64(rec.VerifyDuration), 10),
+			fmtTime(rec.LastRepaired),
+			strconv.Itoa(rec.RepairCount),
+			fmtInt(rec.RepairExitCode),
+			rec.Interrupted,
+			fmtTime(rec.VerifyCompleted),
+			fmtTime(rec.RepairCompleted),
+			rec.LastError,
+			fmtTime(rec.LastErrorAt),
+			rec.LastErrorOp,
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("failed to write row: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to flush: %w", err)
+	}
+
+	return nil
+}
+
+// escapePath returns the path unchanged if it is valid UTF-8, otherwise with
+// all invalid bytes escaped as \xNN (rather than replaced with U+FFFD, which
+// would make otherwise distinct paths indistinguishable in the output).
+func escapePath(path string) string {
+	if utf8.ValidString(path) {
+		return path
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(path); {
+		r, size := utf8.DecodeRuneInString(path[i:])
+		if r == utf8.RuneError && size == 1 {
+			fmt.Fprintf(&sb, `\x%02x`, path[i])
+		} else {
+			sb.WriteString(path[i : i+size])
+		}
+		i += size
+	}
+
+	return sb.String()
+}
+
+func fmtTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+func fmtInt(i *int) string {
+	if i == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*i)
+}
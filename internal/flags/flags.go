@@ -5,8 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/desertwitch/par2cron/internal/schema"
 	"github.com/spf13/pflag"
@@ -18,10 +23,42 @@ var (
 	_ pflag.Value = (*Duration)(nil)
 	_ pflag.Value = (*LogLevel)(nil)
 	_ pflag.Value = (*CreateMode)(nil)
+	_ pflag.Value = (*Par2Flavor)(nil)
+	_ pflag.Value = (*MaxDepth)(nil)
+	_ pflag.Value = (*VerifyOrder)(nil)
+	_ pflag.Value = (*Color)(nil)
+	_ pflag.Value = (*ExportFormat)(nil)
+	_ pflag.Value = (*PathPrefixMap)(nil)
+	_ pflag.Value = (*IOThrottle)(nil)
+	_ pflag.Value = (*Tags)(nil)
+	_ pflag.Value = (*EnvVars)(nil)
+	_ pflag.Value = (*Par2ExitCodes)(nil)
+	_ pflag.Value = (*ByteSize)(nil)
+	_ pflag.Value = (*NameMismatch)(nil)
+	_ pflag.Value = (*Date)(nil)
+	_ pflag.Value = (*Version)(nil)
+	_ pflag.Value = (*Redundancy)(nil)
+	_ pflag.Value = (*BasePath)(nil)
 
 	_ yaml.Unmarshaler = (*Duration)(nil)
 	_ yaml.Unmarshaler = (*LogLevel)(nil)
 	_ yaml.Unmarshaler = (*CreateMode)(nil)
+	_ yaml.Unmarshaler = (*Par2Flavor)(nil)
+	_ yaml.Unmarshaler = (*MaxDepth)(nil)
+	_ yaml.Unmarshaler = (*VerifyOrder)(nil)
+	_ yaml.Unmarshaler = (*Color)(nil)
+	_ yaml.Unmarshaler = (*ExportFormat)(nil)
+	_ yaml.Unmarshaler = (*PathPrefixMap)(nil)
+	_ yaml.Unmarshaler = (*IOThrottle)(nil)
+	_ yaml.Unmarshaler = (*Tags)(nil)
+	_ yaml.Unmarshaler = (*EnvVars)(nil)
+	_ yaml.Unmarshaler = (*Par2ExitCodes)(nil)
+	_ yaml.Unmarshaler = (*ByteSize)(nil)
+	_ yaml.Unmarshaler = (*NameMismatch)(nil)
+	_ yaml.Unmarshaler = (*Date)(nil)
+	_ yaml.Unmarshaler = (*Version)(nil)
+	_ yaml.Unmarshaler = (*Redundancy)(nil)
+	_ yaml.Unmarshaler = (*BasePath)(nil)
 
 	errInvalidValue = errors.New("invalid value")
 )
@@ -156,3 +193,845 @@ func (f *CreateMode) Type() string {
 func (f *CreateMode) UnmarshalYAML(node *yaml.Node) error {
 	return f.Set(node.Value)
 }
+
+type Par2Flavor struct {
+	Raw   string
+	Value string
+}
+
+func (f *Par2Flavor) String() string {
+	return f.Raw
+}
+
+func (f *Par2Flavor) Set(s string) error {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	switch s {
+	case schema.Par2FlavorAuto:
+		f.Value = schema.Par2FlavorAuto
+	case schema.Par2FlavorClassic:
+		f.Value = schema.Par2FlavorClassic
+	case schema.Par2FlavorTurbo:
+		f.Value = schema.Par2FlavorTurbo
+	default:
+		return fmt.Errorf("%w: %q is not recognized", errInvalidValue, s)
+	}
+
+	f.Raw = s
+
+	return nil
+}
+
+func (f *Par2Flavor) Type() string {
+	return "flavor"
+}
+
+func (f *Par2Flavor) UnmarshalYAML(node *yaml.Node) error {
+	return f.Set(node.Value)
+}
+
+type VerifyOrder struct {
+	Raw   string
+	Value string
+}
+
+func (f *VerifyOrder) String() string {
+	return f.Raw
+}
+
+func (f *VerifyOrder) Set(s string) error {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	switch s {
+	case schema.VerifyOrderOldest:
+		f.Value = schema.VerifyOrderOldest
+	case schema.VerifyOrderNewest:
+		f.Value = schema.VerifyOrderNewest
+	case schema.VerifyOrderRandom:
+		f.Value = schema.VerifyOrderRandom
+	default:
+		return fmt.Errorf("%w: %q is not recognized", errInvalidValue, s)
+	}
+
+	f.Raw = s
+
+	return nil
+}
+
+func (f *VerifyOrder) Type() string {
+	return "order"
+}
+
+func (f *VerifyOrder) UnmarshalYAML(node *yaml.Node) error {
+	return f.Set(node.Value)
+}
+
+// MaxDepth is the maximum directory depth below a scan root, where an empty
+// (zero) value means unlimited and a depth of 0 means the scan root only.
+type MaxDepth struct {
+	Raw   string
+	Value int
+}
+
+func (f *MaxDepth) String() string {
+	return f.Raw
+}
+
+func (f *MaxDepth) Set(s string) error {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if s == "" || s == "unlimited" {
+		f.Raw = ""
+		f.Value = 0
+
+		return nil
+	}
+
+	conv, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("failed to atoi: %w", err)
+	}
+	if conv < 0 {
+		return fmt.Errorf("%w: %d must not be negative", errInvalidValue, conv)
+	}
+
+	f.Raw = s
+	f.Value = conv
+
+	return nil
+}
+
+func (f *MaxDepth) Type() string {
+	return "depth"
+}
+
+func (f *MaxDepth) Unlimited() bool {
+	return f.Raw == ""
+}
+
+func (f *MaxDepth) UnmarshalYAML(node *yaml.Node) error {
+	return f.Set(node.Value)
+}
+
+type Color struct {
+	Raw   string
+	Value string
+}
+
+func (f *Color) String() string {
+	return f.Raw
+}
+
+func (f *Color) Set(s string) error {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	switch s {
+	case schema.ColorAuto:
+		f.Value = schema.ColorAuto
+	case schema.ColorAlways:
+		f.Value = schema.ColorAlways
+	case schema.ColorNever:
+		f.Value = schema.ColorNever
+	default:
+		return fmt.Errorf("%w: %q is not recognized", errInvalidValue, s)
+	}
+
+	f.Raw = s
+
+	return nil
+}
+
+func (f *Color) Type() string {
+	return "when"
+}
+
+func (f *Color) UnmarshalYAML(node *yaml.Node) error {
+	return f.Set(node.Value)
+}
+
+type ExportFormat struct {
+	Raw   string
+	Value string
+}
+
+func (f *ExportFormat) String() string {
+	return f.Raw
+}
+
+func (f *ExportFormat) Set(s string) error {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	switch s {
+	case schema.ExportFormatJSON:
+		f.Value = schema.ExportFormatJSON
+	case schema.ExportFormatCSV:
+		f.Value = schema.ExportFormatCSV
+	default:
+		return fmt.Errorf("%w: %q is not recognized", errInvalidValue, s)
+	}
+
+	f.Raw = s
+
+	return nil
+}
+
+func (f *ExportFormat) Type() string {
+	return "format"
+}
+
+func (f *ExportFormat) UnmarshalYAML(node *yaml.Node) error {
+	return f.Set(node.Value)
+}
+
+// maxIOThrottleLevel is the lowest priority level of the best-effort class.
+const maxIOThrottleLevel = 7
+
+// IOThrottle is the I/O scheduling class (and priority level within the
+// class) that "par2" is run with, where "best-effort" without a level means
+// the lowest level (7) and an empty (or "none") value means no throttling.
+type IOThrottle struct {
+	Raw   string
+	Value string
+	Level int
+}
+
+func (f *IOThrottle) String() string {
+	return f.Raw
+}
+
+func (f *IOThrottle) Set(s string) error {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	class, level, hasLevel := strings.Cut(s, ":")
+
+	switch class {
+	case "", schema.IOThrottleNone:
+		if hasLevel {
+			return fmt.Errorf("%w: %q does not take a level", errInvalidValue, s)
+		}
+		f.Value = ""
+		f.Level = 0
+
+	case schema.IOThrottleIdle:
+		if hasLevel {
+			return fmt.Errorf("%w: %q does not take a level", errInvalidValue, s)
+		}
+		f.Value = schema.IOThrottleIdle
+		f.Level = 0
+
+	case schema.IOThrottleBestEffort:
+		conv := maxIOThrottleLevel
+		if hasLevel {
+			var err error
+			if conv, err = strconv.Atoi(level); err != nil {
+				return fmt.Errorf("failed to atoi: %w", err)
+			}
+			if conv < 0 || conv > maxIOThrottleLevel {
+				return fmt.Errorf("%w: %d must be between 0 and %d", errInvalidValue, conv, maxIOThrottleLevel)
+			}
+		}
+		f.Value = schema.IOThrottleBestEffort
+		f.Level = conv
+
+	default:
+		return fmt.Errorf("%w: %q is not recognized", errInvalidValue, s)
+	}
+
+	f.Raw = s
+
+	return nil
+}
+
+func (f *IOThrottle) Type() string {
+	return "class[:level]"
+}
+
+func (f *IOThrottle) UnmarshalYAML(node *yaml.Node) error {
+	return f.Set(node.Value)
+}
+
+// PathPrefix is a single mapping of a path prefix (as seen by par2cron) to
+// another path prefix (as it should be displayed to the user).
+type PathPrefix struct {
+	From string
+	To   string
+}
+
+// PathPrefixMap is a repeatable list of "from=to" path prefix mappings, used
+// only for presenting paths (such as those seen inside a container) as they
+// are known elsewhere (such as on the host), never for filesystem operations.
+type PathPrefixMap struct {
+	Raw   []string
+	Value []PathPrefix
+}
+
+func (f *PathPrefixMap) String() string {
+	return strings.Join(f.Raw, ",")
+}
+
+func (f *PathPrefixMap) Set(s string) error {
+	s = strings.TrimSpace(s)
+
+	from, to, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("%w: %q is not in the form from=to", errInvalidValue, s)
+	}
+	if !filepath.IsAbs(from) || !filepath.IsAbs(to) {
+		return fmt.Errorf("%w: %q must map an absolute path to an absolute path", errInvalidValue, s)
+	}
+
+	f.Raw = append(f.Raw, s)
+	f.Value = append(f.Value, PathPrefix{From: filepath.Clean(from), To: filepath.Clean(to)})
+
+	return nil
+}
+
+func (f *PathPrefixMap) Type() string {
+	return "from=to"
+}
+
+func (f *PathPrefixMap) UnmarshalYAML(node *yaml.Node) error {
+	*f = PathPrefixMap{}
+
+	if node.Kind == yaml.ScalarNode {
+		return f.Set(node.Value)
+	}
+
+	var entries []string
+	if err := node.Decode(&entries); err != nil {
+		return fmt.Errorf("failed to decode: %w", err)
+	}
+	for _, entry := range entries {
+		if err := f.Set(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Map returns the path with the longest matching "from" prefix replaced by
+// its "to" prefix, or the path unchanged if no mapping matches. Prefixes only
+// match whole path elements, so "/mnt/data" does not match "/mnt/database".
+func (f *PathPrefixMap) Map(path string) string {
+	best := -1
+	for i, m := range f.Value {
+		if !hasPathPrefix(path, m.From) {
+			continue
+		}
+		if best < 0 || len(m.From) > len(f.Value[best].From) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return path
+	}
+
+	m := f.Value[best]
+
+	return filepath.Join(m.To, strings.TrimPrefix(path, m.From))
+}
+
+func hasPathPrefix(path string, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+
+	return len(path) == len(prefix) || prefix == string(filepath.Separator) || path[len(prefix)] == filepath.Separator
+}
+
+// Par2ExitCodes is a repeatable list of "code=class" mappings, classifying
+// par2 exit codes (of forks deviating from par2cmdline) as verification
+// results, taking precedence over the classification of par2cmdline.
+// The exit code 0 is always a success and cannot be mapped otherwise.
+type Par2ExitCodes struct {
+	Raw   []string
+	Value map[int]string
+}
+
+func (f *Par2ExitCodes) String() string {
+	return strings.Join(f.Raw, ",")
+}
+
+func (f *Par2ExitCodes) Set(s string) error {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	code, class, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("%w: %q is not in the form code=class", errInvalidValue, s)
+	}
+
+	c, err := strconv.Atoi(code)
+	if err != nil || c < 1 || c > 255 { //nolint:mnd
+		return fmt.Errorf("%w: %q must map an exit code from 1 to 255", errInvalidValue, s)
+	}
+
+	switch class {
+	case schema.Par2ExitClassSuccess, schema.Par2ExitClassRepairable,
+		schema.Par2ExitClassUnrepairable, schema.Par2ExitClassUsageError:
+	default:
+		return fmt.Errorf("%w: %q must map to %s, %s, %s or %s", errInvalidValue, s,
+			schema.Par2ExitClassSuccess, schema.Par2ExitClassRepairable,
+			schema.Par2ExitClassUnrepairable, schema.Par2ExitClassUsageError)
+	}
+
+	if f.Value == nil {
+		f.Value = make(map[int]string)
+	}
+	f.Raw = append(f.Raw, s)
+	f.Value[c] = class
+
+	return nil
+}
+
+func (f *Par2ExitCodes) Type() string {
+	return "code=class"
+}
+
+func (f *Par2ExitCodes) UnmarshalYAML(node *yaml.Node) error {
+	*f = Par2ExitCodes{}
+
+	if node.Kind == yaml.ScalarNode {
+		return f.Set(node.Value)
+	}
+
+	var entries []string
+	if err := node.Decode(&entries); err != nil {
+		return fmt.Errorf("failed to decode: %w", err)
+	}
+	for _, entry := range entries {
+		if err := f.Set(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Tags is a repeatable list of required tags, where multiple tags can also be
+// given as a comma-separated list. A set matches only if it has all the tags.
+type Tags struct {
+	Raw   []string
+	Value []string
+}
+
+func (f *Tags) String() string {
+	return strings.Join(f.Raw, ",")
+}
+
+func (f *Tags) Set(s string) error {
+	s = strings.TrimSpace(s)
+
+	for tag := range strings.SplitSeq(s, ",") {
+		tag = strings.TrimSpace(tag)
+		if err := schema.ValidateTag(tag); err != nil {
+			return fmt.Errorf("%w: %w", errInvalidValue, err)
+		}
+		if !slices.Contains(f.Value, tag) {
+			f.Value = append(f.Value, tag)
+		}
+	}
+
+	f.Raw = append(f.Raw, s)
+
+	return nil
+}
+
+func (f *Tags) Type() string {
+	return "tags"
+}
+
+func (f Tags) MarshalJSON() ([]byte, error) {
+	if f.Value == nil {
+		return []byte("[]"), nil
+	}
+
+	by, err := json.Marshal(f.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %w", err)
+	}
+
+	return by, nil
+}
+
+func (f *Tags) UnmarshalJSON(data []byte) error {
+	var entries []string
+
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to unmarshal: %w", err)
+	}
+
+	*f = Tags{}
+	for _, entry := range entries {
+		if err := f.Set(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *Tags) UnmarshalYAML(node *yaml.Node) error {
+	*f = Tags{}
+
+	if node.Kind == yaml.ScalarNode {
+		return f.Set(node.Value)
+	}
+
+	var entries []string
+	if err := node.Decode(&entries); err != nil {
+		return fmt.Errorf("failed to decode: %w", err)
+	}
+	for _, entry := range entries {
+		if err := f.Set(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EnvVars is a repeatable list of environment variables in the KEY=VALUE form,
+// where a value may be empty, but may not be split by commas (unlike [Tags]).
+type EnvVars struct {
+	Raw   []string
+	Value []string
+}
+
+func (f *EnvVars) String() string {
+	return strings.Join(f.Raw, ",")
+}
+
+func (f *EnvVars) Set(s string) error {
+	key, _, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("%w: %q is not in the form KEY=VALUE", errInvalidValue, s)
+	}
+	if key == "" || strings.ContainsFunc(key, unicode.IsSpace) {
+		return fmt.Errorf("%w: %q must have a key without whitespace", errInvalidValue, s)
+	}
+
+	f.Raw = append(f.Raw, s)
+	f.Value = append(f.Value, s)
+
+	return nil
+}
+
+func (f *EnvVars) Type() string {
+	return "KEY=VALUE"
+}
+
+func (f *EnvVars) UnmarshalYAML(node *yaml.Node) error {
+	*f = EnvVars{}
+
+	if node.Kind == yaml.ScalarNode {
+		return f.Set(node.Value)
+	}
+
+	var entries []string
+	if err := node.Decode(&entries); err != nil {
+		return fmt.Errorf("failed to decode: %w", err)
+	}
+	for _, entry := range entries {
+		if err := f.Set(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ByteSize is a size in bytes, given as a number with an optional binary unit
+// suffix (K, M, G or T, optionally followed by "B" or "iB"), where an empty
+// (or "0") value means unset.
+type ByteSize struct {
+	Raw   string
+	Value int64
+}
+
+func (f *ByteSize) String() string {
+	return f.Raw
+}
+
+func (f *ByteSize) Set(s string) error {
+	s = strings.TrimSpace(s)
+
+	if s == "" || s == "0" {
+		f.Raw = ""
+		f.Value = 0
+
+		return nil
+	}
+
+	num := strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(s), "B"), "I")
+
+	var shift uint
+	if i := strings.IndexAny(num, "KMGT"); i >= 0 && i == len(num)-1 {
+		shift = 10 * uint(strings.IndexByte("KMGT", num[i])+1) //nolint:mnd
+		num = num[:i]
+	}
+
+	conv, err := strconv.ParseInt(strings.TrimSpace(num), 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse: %w", err)
+	}
+	if conv <= 0 {
+		return fmt.Errorf("%w: %q must be positive", errInvalidValue, s)
+	}
+	if conv > (1<<63-1)>>shift {
+		return fmt.Errorf("%w: %q is too large", errInvalidValue, s)
+	}
+
+	f.Raw = s
+	f.Value = conv << shift
+
+	return nil
+}
+
+func (f *ByteSize) Type() string {
+	return "size"
+}
+
+func (f *ByteSize) UnmarshalYAML(node *yaml.Node) error {
+	return f.Set(node.Value)
+}
+
+// NameMismatch is the policy for a manifest whose stored name does not match
+// the PAR2 set it was found for, where an empty value means not checking it.
+type NameMismatch struct {
+	Raw   string
+	Value string
+}
+
+func (f *NameMismatch) String() string {
+	return f.Raw
+}
+
+func (f *NameMismatch) Set(s string) error {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	switch s {
+	case schema.NameMismatchFix:
+		f.Value = schema.NameMismatchFix
+	case schema.NameMismatchSkip:
+		f.Value = schema.NameMismatchSkip
+	default:
+		return fmt.Errorf("%w: %q is not recognized", errInvalidValue, s)
+	}
+
+	f.Raw = s
+
+	return nil
+}
+
+func (f *NameMismatch) Type() string {
+	return "policy"
+}
+
+func (f *NameMismatch) UnmarshalYAML(node *yaml.Node) error {
+	return f.Set(node.Value)
+}
+
+// Date is a point in time, given as a date (in local time, at its start), a
+// date and time (in local time) or an RFC 3339 timestamp, where an empty
+// value means unset.
+type Date struct {
+	Raw   string
+	Value time.Time
+}
+
+func (f *Date) String() string {
+	return f.Raw
+}
+
+func (f *Date) Set(s string) error {
+	s = strings.TrimSpace(s)
+
+	if s == "" {
+		f.Raw = ""
+		f.Value = time.Time{}
+
+		return nil
+	}
+
+	var conv time.Time
+	var err error
+	for _, layout := range []string{time.DateOnly, time.DateTime, "2006-01-02T15:04:05"} {
+		if conv, err = time.ParseInLocation(layout, s, time.Local); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		if conv, err = time.Parse(time.RFC3339, s); err != nil {
+			return fmt.Errorf("%w: %q is not a date (YYYY-MM-DD), date and time or RFC 3339 timestamp", errInvalidValue, s)
+		}
+	}
+
+	f.Raw = s
+	f.Value = conv
+
+	return nil
+}
+
+func (f *Date) Type() string {
+	return "date"
+}
+
+func (f *Date) UnmarshalYAML(node *yaml.Node) error {
+	return f.Set(node.Value)
+}
+
+// Version is a version number of major, minor and (optionally) patch numbers
+// separated by dots (such as "0.8.1"), where an empty value means unset.
+type Version struct {
+	Raw   string
+	Value [3]int
+}
+
+func (f *Version) String() string {
+	return f.Raw
+}
+
+func (f *Version) Set(s string) error {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+
+	if s == "" {
+		f.Raw = ""
+		f.Value = [3]int{}
+
+		return nil
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 || len(parts) > 3 { //nolint:mnd
+		return fmt.Errorf("%w: %q is not in the form major.minor[.patch]", errInvalidValue, s)
+	}
+
+	var conv [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || strings.HasPrefix(part, "+") {
+			return fmt.Errorf("%w: %q is not in the form major.minor[.patch]", errInvalidValue, s)
+		}
+		conv[i] = n
+	}
+
+	f.Raw = s
+	f.Value = conv
+
+	return nil
+}
+
+func (f *Version) Type() string {
+	return "version"
+}
+
+func (f *Version) UnmarshalYAML(node *yaml.Node) error {
+	return f.Set(node.Value)
+}
+
+// Redundancy is a share of parity data relative to the protected data, given
+// as a fraction (such as "0.2") or percentage (such as "20%") of up to 100%,
+// where an empty value means unset.
+type Redundancy struct {
+	Raw   string
+	Value float64
+}
+
+func (f *Redundancy) String() string {
+	return f.Raw
+}
+
+func (f *Redundancy) Set(s string) error {
+	s = strings.TrimSpace(s)
+
+	if s == "" {
+		f.Raw = ""
+		f.Value = 0
+
+		return nil
+	}
+
+	num, isPct := strings.CutSuffix(s, "%")
+
+	conv, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse: %w", err)
+	}
+	if isPct {
+		conv /= 100 //nolint:mnd
+	}
+	if conv <= 0 || conv > 1 || math.IsNaN(conv) {
+		return fmt.Errorf("%w: %q must be above 0 and at most 1 (100%%)", errInvalidValue, s)
+	}
+
+	f.Raw = s
+	f.Value = conv
+
+	return nil
+}
+
+func (f *Redundancy) Type() string {
+	return "redundancy"
+}
+
+func (f Redundancy) MarshalJSON() ([]byte, error) {
+	by, err := json.Marshal(f.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %w", err)
+	}
+
+	return by, nil
+}
+
+func (f *Redundancy) UnmarshalJSON(data []byte) error {
+	var s string
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("failed to unmarshal: %w", err)
+	}
+
+	return f.Set(s)
+}
+
+func (f *Redundancy) UnmarshalYAML(node *yaml.Node) error {
+	return f.Set(node.Value)
+}
+
+// BasePath is how the directory that par2 resolves the protected files
+// against is computed, where an empty value means the automatic behavior.
+type BasePath struct {
+	Raw   string
+	Value string
+}
+
+func (f *BasePath) String() string {
+	return f.Raw
+}
+
+func (f *BasePath) Set(s string) error {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	switch s {
+	case schema.BasePathAuto:
+		f.Value = schema.BasePathAuto
+	case schema.BasePathSetDir:
+		f.Value = schema.BasePathSetDir
+	case schema.BasePathScanRoot:
+		f.Value = schema.BasePathScanRoot
+	default:
+		return fmt.Errorf("%w: %q is not recognized", errInvalidValue, s)
+	}
+
+	f.Raw = s
+
+	return nil
+}
+
+func (f *BasePath) Type() string {
+	return "mode"
+}
+
+func (f *BasePath) UnmarshalYAML(node *yaml.Node) error {
+	return f.Set(node.Value)
+}
@@ -3,6 +3,7 @@ package flags
 import (
 	"encoding/json"
 	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
@@ -415,3 +416,790 @@ func Test_CreateMode_UnmarshalYAML_Success(t *testing.T) {
 	require.Equal(t, schema.CreateFileMode, f.Value)
 	require.Equal(t, schema.CreateFileMode, f.Raw)
 }
+
+// Expectation: The function should set all supported flavors.
+func Test_Par2Flavor_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	for _, flavor := range []string{schema.Par2FlavorAuto, schema.Par2FlavorClassic, schema.Par2FlavorTurbo} {
+		f := &Par2Flavor{}
+
+		require.NoError(t, f.Set(flavor))
+		require.Equal(t, flavor, f.Value)
+		require.Equal(t, flavor, f.Raw)
+	}
+}
+
+// Expectation: The function should return an error on an invalid flavor.
+func Test_Par2Flavor_Set_InvalidFlavor_Error(t *testing.T) {
+	t.Parallel()
+
+	f := &Par2Flavor{}
+
+	err := f.Set("invalid")
+
+	require.ErrorIs(t, err, errInvalidValue)
+}
+
+// Expectation: The function should return it's type as string.
+func Test_Par2Flavor_Type_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &Par2Flavor{}
+
+	require.Equal(t, "flavor", f.Type())
+}
+
+// Expectation: The function should unmarshal a valid flavor from YAML.
+func Test_Par2Flavor_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var f Par2Flavor
+
+	err := yaml.Unmarshal([]byte(schema.Par2FlavorTurbo), &f)
+
+	require.NoError(t, err)
+	require.Equal(t, schema.Par2FlavorTurbo, f.Value)
+	require.Equal(t, schema.Par2FlavorTurbo, f.String())
+}
+
+// Expectation: The function should set a valid depth and keep unlimited as the zero value.
+func Test_MaxDepth_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &MaxDepth{}
+	require.True(t, f.Unlimited())
+
+	require.NoError(t, f.Set("0"))
+	require.False(t, f.Unlimited())
+	require.Equal(t, 0, f.Value)
+	require.Equal(t, "0", f.String())
+
+	require.NoError(t, f.Set(" 3 "))
+	require.False(t, f.Unlimited())
+	require.Equal(t, 3, f.Value)
+
+	require.NoError(t, f.Set("unlimited"))
+	require.True(t, f.Unlimited())
+	require.Empty(t, f.String())
+}
+
+// Expectation: The function should return an error on a negative or non-numeric depth.
+func Test_MaxDepth_Set_Invalid_Error(t *testing.T) {
+	t.Parallel()
+
+	f := &MaxDepth{}
+
+	require.ErrorIs(t, f.Set("-1"), errInvalidValue)
+	require.Error(t, f.Set("two"))
+	require.True(t, f.Unlimited())
+}
+
+// Expectation: The function should return it's type as string.
+func Test_MaxDepth_Type_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &MaxDepth{}
+
+	require.Equal(t, "depth", f.Type())
+}
+
+// Expectation: The function should unmarshal a valid depth from YAML.
+func Test_MaxDepth_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var f MaxDepth
+
+	err := yaml.Unmarshal([]byte("2"), &f)
+
+	require.NoError(t, err)
+	require.Equal(t, 2, f.Value)
+	require.False(t, f.Unlimited())
+}
+
+// Expectation: The function should set all valid verification orders.
+func Test_VerifyOrder_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	for _, order := range []string{schema.VerifyOrderOldest, schema.VerifyOrderNewest, schema.VerifyOrderRandom} {
+		f := &VerifyOrder{}
+
+		require.NoError(t, f.Set(order))
+		require.Equal(t, order, f.Value)
+		require.Equal(t, order, f.Raw)
+	}
+}
+
+// Expectation: The function should return an error on an invalid order.
+func Test_VerifyOrder_Set_InvalidOrder_Error(t *testing.T) {
+	t.Parallel()
+
+	f := &VerifyOrder{}
+
+	err := f.Set("sideways")
+
+	require.ErrorIs(t, err, errInvalidValue)
+}
+
+// Expectation: The function should return it's type as string.
+func Test_VerifyOrder_Type_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &VerifyOrder{}
+
+	require.Equal(t, "order", f.Type())
+}
+
+// Expectation: The function should unmarshal a valid order from YAML.
+func Test_VerifyOrder_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var f VerifyOrder
+
+	err := yaml.Unmarshal([]byte(schema.VerifyOrderNewest), &f)
+
+	require.NoError(t, err)
+	require.Equal(t, schema.VerifyOrderNewest, f.Value)
+	require.Equal(t, schema.VerifyOrderNewest, f.String())
+}
+
+// Expectation: The function should set all valid color settings.
+func Test_Color_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	for _, color := range []string{schema.ColorAuto, schema.ColorAlways, schema.ColorNever} {
+		f := &Color{}
+
+		require.NoError(t, f.Set(color))
+		require.Equal(t, color, f.Value)
+		require.Equal(t, color, f.Raw)
+	}
+}
+
+// Expectation: The function should return an error on an invalid color setting.
+func Test_Color_Set_InvalidColor_Error(t *testing.T) {
+	t.Parallel()
+
+	f := &Color{}
+
+	err := f.Set("rainbow")
+
+	require.ErrorIs(t, err, errInvalidValue)
+}
+
+// Expectation: The function should return it's type as string.
+func Test_Color_Type_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &Color{}
+
+	require.Equal(t, "when", f.Type())
+}
+
+// Expectation: The function should unmarshal a valid color setting from YAML.
+func Test_Color_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var f Color
+
+	err := yaml.Unmarshal([]byte(schema.ColorNever), &f)
+
+	require.NoError(t, err)
+	require.Equal(t, schema.ColorNever, f.Value)
+	require.Equal(t, schema.ColorNever, f.String())
+}
+
+// Expectation: The function should set all valid export formats.
+func Test_ExportFormat_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{schema.ExportFormatJSON, schema.ExportFormatCSV} {
+		f := &ExportFormat{}
+
+		require.NoError(t, f.Set(strings.ToUpper(format)))
+		require.Equal(t, format, f.Value)
+		require.Equal(t, format, f.Raw)
+	}
+}
+
+// Expectation: The function should return an error on an invalid export format.
+func Test_ExportFormat_Set_InvalidFormat_Error(t *testing.T) {
+	t.Parallel()
+
+	f := &ExportFormat{}
+
+	err := f.Set("xml")
+
+	require.ErrorIs(t, err, errInvalidValue)
+}
+
+// Expectation: The function should return it's type as string.
+func Test_ExportFormat_Type_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &ExportFormat{}
+
+	require.Equal(t, "format", f.Type())
+}
+
+// Expectation: The function should set all valid I/O scheduling classes and levels.
+func Test_IOThrottle_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		class string
+		level int
+	}{
+		{"", "", 0},
+		{"none", "", 0},
+		{"idle", schema.IOThrottleIdle, 0},
+		{"best-effort", schema.IOThrottleBestEffort, 7},
+		{" Best-Effort:0 ", schema.IOThrottleBestEffort, 0},
+		{"best-effort:4", schema.IOThrottleBestEffort, 4},
+	}
+
+	for _, tt := range tests {
+		f := &IOThrottle{}
+
+		require.NoError(t, f.Set(tt.input), tt.input)
+		require.Equal(t, tt.class, f.Value, tt.input)
+		require.Equal(t, tt.level, f.Level, tt.input)
+		require.Equal(t, strings.ToLower(strings.TrimSpace(tt.input)), f.String())
+	}
+}
+
+// Expectation: The function should return an error on invalid classes and levels.
+func Test_IOThrottle_Set_Invalid_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"realtime", "idle:3", "none:1", "best-effort:8", "best-effort:-1"} {
+		f := &IOThrottle{}
+
+		require.ErrorIs(t, f.Set(input), errInvalidValue, input)
+	}
+
+	f := &IOThrottle{}
+	require.Error(t, f.Set("best-effort:low"))
+}
+
+// Expectation: The function should return it's type as string.
+func Test_IOThrottle_Type_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &IOThrottle{}
+
+	require.Equal(t, "class[:level]", f.Type())
+}
+
+// Expectation: The function should unmarshal a valid I/O scheduling class from YAML.
+func Test_IOThrottle_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var f IOThrottle
+
+	err := yaml.Unmarshal([]byte("best-effort:6"), &f)
+
+	require.NoError(t, err)
+	require.Equal(t, schema.IOThrottleBestEffort, f.Value)
+	require.Equal(t, 6, f.Level)
+}
+
+// Expectation: The function should append repeated and comma-separated tags, without duplicates.
+func Test_Tags_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &Tags{}
+
+	require.NoError(t, f.Set("tier:critical"))
+	require.NoError(t, f.Set(" media:video, tier:critical "))
+
+	require.Equal(t, []string{"tier:critical", "media:video"}, f.Value)
+	require.Equal(t, "tier:critical,media:video, tier:critical", f.String())
+	require.Equal(t, "tags", f.Type())
+}
+
+// Expectation: The function should return an error on empty tags or tags with whitespace.
+func Test_Tags_Set_Invalid_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "a,,b", "tier critical"} {
+		f := &Tags{}
+
+		require.ErrorIs(t, f.Set(input), errInvalidValue, input)
+	}
+}
+
+// Expectation: The function should unmarshal tags from a YAML scalar or sequence.
+func Test_Tags_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var f Tags
+	require.NoError(t, yaml.Unmarshal([]byte("a,b"), &f))
+	require.Equal(t, []string{"a", "b"}, f.Value)
+
+	require.NoError(t, yaml.Unmarshal([]byte("[c, d]"), &f))
+	require.Equal(t, []string{"c", "d"}, f.Value)
+}
+
+// Expectation: The function should marshal the tags as JSON list (also when empty) and back.
+func Test_Tags_MarshalJSON_Success(t *testing.T) {
+	t.Parallel()
+
+	by, err := json.Marshal(Tags{})
+	require.NoError(t, err)
+	require.JSONEq(t, `[]`, string(by))
+
+	by, err = json.Marshal(Tags{Value: []string{"a"}})
+	require.NoError(t, err)
+	require.JSONEq(t, `["a"]`, string(by))
+
+	var f Tags
+	require.NoError(t, json.Unmarshal(by, &f))
+	require.Equal(t, []string{"a"}, f.Value)
+}
+
+// Expectation: The function should append environment variables, keeping commas in values.
+func Test_EnvVars_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &EnvVars{}
+
+	require.NoError(t, f.Set("OMP_NUM_THREADS=2"))
+	require.NoError(t, f.Set("PAR2_OPTS=a,b=c"))
+	require.NoError(t, f.Set("EMPTY="))
+
+	require.Equal(t, []string{"OMP_NUM_THREADS=2", "PAR2_OPTS=a,b=c", "EMPTY="}, f.Value)
+	require.Equal(t, "KEY=VALUE", f.Type())
+}
+
+// Expectation: The function should return an error on entries without a (valid) key.
+func Test_EnvVars_Set_Invalid_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "NOVALUE", "=value", "MY VAR=1"} {
+		f := &EnvVars{}
+
+		require.ErrorIs(t, f.Set(input), errInvalidValue, input)
+	}
+}
+
+// Expectation: The function should unmarshal environment variables from a YAML scalar or sequence.
+func Test_EnvVars_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var f EnvVars
+	require.NoError(t, yaml.Unmarshal([]byte("A=1"), &f))
+	require.Equal(t, []string{"A=1"}, f.Value)
+
+	require.NoError(t, yaml.Unmarshal([]byte(`["B=2", "C=3,4"]`), &f))
+	require.Equal(t, []string{"B=2", "C=3,4"}, f.Value)
+}
+
+// Expectation: The function should append cleaned absolute mappings.
+func Test_PathPrefixMap_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &PathPrefixMap{}
+
+	require.NoError(t, f.Set("/data/=/mnt/user/data"))
+	require.NoError(t, f.Set(" /media=/mnt/disks "))
+
+	require.Equal(t, []PathPrefix{{From: "/data", To: "/mnt/user/data"}, {From: "/media", To: "/mnt/disks"}}, f.Value)
+	require.Equal(t, "/data/=/mnt/user/data,/media=/mnt/disks", f.String())
+}
+
+// Expectation: The function should return an error on malformed or relative mappings.
+func Test_PathPrefixMap_Set_Invalid_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"/data", "data=/mnt/data", "/data=mnt/data", "=/mnt"} {
+		f := &PathPrefixMap{}
+
+		require.ErrorIs(t, f.Set(s), errInvalidValue, s)
+		require.Empty(t, f.Value)
+	}
+}
+
+// Expectation: The function should return it's type as string.
+func Test_PathPrefixMap_Type_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &PathPrefixMap{}
+
+	require.Equal(t, "from=to", f.Type())
+}
+
+// Expectation: The longest whole-element prefix should be mapped, others left unchanged.
+func Test_PathPrefixMap_Map_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &PathPrefixMap{}
+	require.NoError(t, f.Set("/data=/mnt/user/data"))
+	require.NoError(t, f.Set("/data/media=/mnt/disks/media"))
+
+	require.Equal(t, "/mnt/user/data", f.Map("/data"))
+	require.Equal(t, "/mnt/user/data/a/b.par2", f.Map("/data/a/b.par2"))
+	require.Equal(t, "/mnt/disks/media/c.par2", f.Map("/data/media/c.par2"))
+	require.Equal(t, "/database/c.par2", f.Map("/database/c.par2"))
+	require.Equal(t, "/other/c.par2", f.Map("/other/c.par2"))
+}
+
+// Expectation: An empty map should return all paths unchanged.
+func Test_PathPrefixMap_Map_Empty_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &PathPrefixMap{}
+
+	require.Equal(t, "/data/a.par2", f.Map("/data/a.par2"))
+}
+
+// Expectation: The function should unmarshal both a single mapping and a list of mappings.
+func Test_PathPrefixMap_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var single PathPrefixMap
+	require.NoError(t, yaml.Unmarshal([]byte(`/data=/mnt/data`), &single))
+	require.Equal(t, []PathPrefix{{From: "/data", To: "/mnt/data"}}, single.Value)
+
+	var list PathPrefixMap
+	require.NoError(t, yaml.Unmarshal([]byte("- /data=/mnt/data\n- /media=/mnt/media\n"), &list))
+	require.Len(t, list.Value, 2)
+	require.Equal(t, "/mnt/media", list.Value[1].To)
+}
+
+// Expectation: The function should parse the mappings of exit codes to classifications.
+func Test_Par2ExitCodes_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &Par2ExitCodes{}
+
+	require.NoError(t, f.Set("4=repairable"))
+	require.NoError(t, f.Set(" 5=Unrepairable "))
+
+	require.Equal(t, map[int]string{4: "repairable", 5: "unrepairable"}, f.Value)
+	require.Equal(t, "4=repairable,5=unrepairable", f.String())
+}
+
+// Expectation: The function should return an error on malformed codes or classifications.
+func Test_Par2ExitCodes_Set_Invalid_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"4", "abc=repairable", "0=repairable", "256=repairable", "4=corrupt", "=repairable"} {
+		f := &Par2ExitCodes{}
+
+		require.ErrorIs(t, f.Set(s), errInvalidValue, s)
+		require.Empty(t, f.Value)
+	}
+}
+
+// Expectation: The function should return it's type as string.
+func Test_Par2ExitCodes_Type_Success(t *testing.T) {
+	t.Parallel()
+
+	f := &Par2ExitCodes{}
+
+	require.Equal(t, "code=class", f.Type())
+}
+
+// Expectation: The function should unmarshal both a single mapping and a list of mappings.
+func Test_Par2ExitCodes_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var single Par2ExitCodes
+	require.NoError(t, yaml.Unmarshal([]byte(`4=repairable`), &single))
+	require.Equal(t, map[int]string{4: "repairable"}, single.Value)
+
+	var list Par2ExitCodes
+	require.NoError(t, yaml.Unmarshal([]byte("- 4=repairable\n- 5=usage-error\n"), &list))
+	require.Equal(t, map[int]string{4: "repairable", 5: "usage-error"}, list.Value)
+
+	var invalid Par2ExitCodes
+	require.Error(t, yaml.Unmarshal([]byte(`4=corrupt`), &invalid))
+}
+
+// Expectation: The function should set valid sizes with and without binary units.
+func Test_ByteSize_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"65536", 65536},
+		{"512K", 512 << 10},
+		{"4M", 4 << 20},
+		{"4mb", 4 << 20},
+		{"4MiB", 4 << 20},
+		{" 1G ", 1 << 30},
+		{"2T", 2 << 40},
+		{"100B", 100},
+		{"0", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		f := &ByteSize{}
+		require.NoError(t, f.Set(tt.in), tt.in)
+		require.Equal(t, tt.want, f.Value, tt.in)
+	}
+}
+
+// Expectation: The function should return an error on invalid, negative or overflowing sizes.
+func Test_ByteSize_Set_Invalid_Error(t *testing.T) {
+	t.Parallel()
+
+	f := &ByteSize{}
+
+	require.ErrorIs(t, f.Set("-1M"), errInvalidValue)
+	require.ErrorIs(t, f.Set("99999999999T"), errInvalidValue)
+	require.Error(t, f.Set("4X"))
+	require.Error(t, f.Set("M"))
+	require.Error(t, f.Set("four"))
+	require.Zero(t, f.Value)
+}
+
+// Expectation: The function should unmarshal a valid size from YAML.
+func Test_ByteSize_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var f ByteSize
+	require.NoError(t, yaml.Unmarshal([]byte(`8M`), &f))
+	require.Equal(t, int64(8<<20), f.Value)
+	require.Equal(t, "8M", f.String())
+	require.Equal(t, "size", f.Type())
+}
+
+// Expectation: The function should set all valid name mismatch policies.
+func Test_NameMismatch_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]string{
+		"fix":    schema.NameMismatchFix,
+		" Skip ": schema.NameMismatchSkip,
+	} {
+		f := &NameMismatch{}
+
+		require.NoError(t, f.Set(input), input)
+		require.Equal(t, want, f.Value, input)
+		require.Equal(t, want, f.String(), input)
+	}
+}
+
+// Expectation: The function should return an error on an invalid policy.
+func Test_NameMismatch_Set_Invalid_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "ignore", "fix,skip"} {
+		f := &NameMismatch{}
+
+		require.ErrorIs(t, f.Set(input), errInvalidValue, input)
+	}
+}
+
+// Expectation: The function should unmarshal a valid policy from YAML.
+func Test_NameMismatch_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var f NameMismatch
+
+	require.NoError(t, yaml.Unmarshal([]byte(`skip`), &f))
+	require.Equal(t, schema.NameMismatchSkip, f.Value)
+	require.Equal(t, "policy", f.Type())
+}
+
+// Expectation: The function should parse dates, dates with times and RFC 3339 timestamps.
+func Test_Date_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]time.Time{
+		"2024-05-01":           time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local),
+		" 2024-05-01 13:30:00": time.Date(2024, 5, 1, 13, 30, 0, 0, time.Local),
+		"2024-05-01T13:30:00":  time.Date(2024, 5, 1, 13, 30, 0, 0, time.Local),
+		"2024-05-01T13:30:00Z": time.Date(2024, 5, 1, 13, 30, 0, 0, time.UTC),
+	} {
+		f := &Date{}
+
+		require.NoError(t, f.Set(input), input)
+		require.True(t, want.Equal(f.Value), input)
+		require.Equal(t, strings.TrimSpace(input), f.String(), input)
+	}
+
+	f := &Date{}
+	require.NoError(t, f.Set("2024-05-01"))
+	require.NoError(t, f.Set(""))
+	require.True(t, f.Value.IsZero())
+	require.Empty(t, f.String())
+}
+
+// Expectation: The function should return an error on an invalid date.
+func Test_Date_Set_Invalid_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"yesterday", "2024-13-01", "01.05.2024", "2024-05-01T13:30"} {
+		f := &Date{}
+
+		require.ErrorIs(t, f.Set(input), errInvalidValue, input)
+	}
+}
+
+// Expectation: The function should unmarshal an (unquoted) date from YAML.
+func Test_Date_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var f Date
+
+	require.NoError(t, yaml.Unmarshal([]byte(`2024-05-01`), &f))
+	require.True(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local).Equal(f.Value))
+	require.Equal(t, "date", f.Type())
+}
+
+// Expectation: The function should parse version numbers with and without a patch number.
+func Test_Version_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string][3]int{
+		"0.8.1":   {0, 8, 1},
+		" 1.0 ":   {1, 0, 0},
+		"v0.6.14": {0, 6, 14},
+		"10.20.3": {10, 20, 3},
+	} {
+		f := &Version{}
+
+		require.NoError(t, f.Set(input), input)
+		require.Equal(t, want, f.Value, input)
+	}
+
+	f := &Version{}
+	require.NoError(t, f.Set("0.8.1"))
+	require.NoError(t, f.Set(""))
+	require.Equal(t, [3]int{}, f.Value)
+	require.Empty(t, f.String())
+}
+
+// Expectation: The function should return an error on an invalid version number.
+func Test_Version_Set_Invalid_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"1", "1.2.3.4", "1.x", "1.-2", "1.+2", "latest", "1..2"} {
+		f := &Version{}
+
+		require.ErrorIs(t, f.Set(input), errInvalidValue, input)
+	}
+}
+
+// Expectation: The function should unmarshal a version number from YAML.
+func Test_Version_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var f Version
+
+	require.NoError(t, yaml.Unmarshal([]byte(`"0.8.0"`), &f))
+	require.Equal(t, [3]int{0, 8, 0}, f.Value)
+	require.Equal(t, "version", f.Type())
+}
+
+// Expectation: The function should parse redundancies given as fractions and percentages.
+func Test_Redundancy_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]float64{
+		"0.2":    0.2,
+		" 0.05 ": 0.05,
+		"1":      1,
+		"20%":    0.2,
+		"7.5 %":  0.075,
+		"100%":   1,
+	} {
+		f := &Redundancy{}
+
+		require.NoError(t, f.Set(input), input)
+		require.InDelta(t, want, f.Value, 1e-9, input)
+	}
+
+	f := &Redundancy{}
+	require.NoError(t, f.Set("0.2"))
+	require.NoError(t, f.Set(""))
+	require.Zero(t, f.Value)
+	require.Empty(t, f.String())
+}
+
+// Expectation: The function should return an error on an invalid or out of range redundancy.
+func Test_Redundancy_Set_Invalid_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"0", "-0.1", "1.5", "150%", "0%", "NaN"} {
+		f := &Redundancy{}
+
+		require.ErrorIs(t, f.Set(input), errInvalidValue, input)
+	}
+
+	f := &Redundancy{}
+	require.Error(t, f.Set("twenty"))
+}
+
+// Expectation: The function should marshal to and unmarshal from the raw value.
+func Test_Redundancy_JSON_Roundtrip_Success(t *testing.T) {
+	t.Parallel()
+
+	f := Redundancy{}
+	require.NoError(t, f.Set("20%"))
+
+	by, err := json.Marshal(f)
+	require.NoError(t, err)
+	require.JSONEq(t, `"20%"`, string(by))
+
+	var g Redundancy
+	require.NoError(t, json.Unmarshal(by, &g))
+	require.InDelta(t, 0.2, g.Value, 1e-9)
+}
+
+// Expectation: The function should unmarshal a redundancy from YAML.
+func Test_Redundancy_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var f Redundancy
+
+	require.NoError(t, yaml.Unmarshal([]byte(`0.2`), &f))
+	require.InDelta(t, 0.2, f.Value, 1e-9)
+	require.Equal(t, "redundancy", f.Type())
+}
+
+// Expectation: The function should set all valid basepath modes.
+func Test_BasePath_Set_Success(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]string{
+		"auto":      schema.BasePathAuto,
+		" Set-Dir ": schema.BasePathSetDir,
+		"SCAN-ROOT": schema.BasePathScanRoot,
+	} {
+		f := &BasePath{}
+
+		require.NoError(t, f.Set(input), input)
+		require.Equal(t, want, f.Value, input)
+		require.Equal(t, want, f.String(), input)
+	}
+}
+
+// Expectation: The function should return an error on an invalid basepath mode.
+func Test_BasePath_Set_Invalid_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "root", "/data"} {
+		f := &BasePath{}
+
+		require.ErrorIs(t, f.Set(input), errInvalidValue, input)
+	}
+}
+
+// Expectation: The function should unmarshal a valid basepath mode from YAML.
+func Test_BasePath_UnmarshalYAML_Success(t *testing.T) {
+	t.Parallel()
+
+	var f BasePath
+
+	require.NoError(t, yaml.Unmarshal([]byte(`scan-root`), &f))
+	require.Equal(t, schema.BasePathScanRoot, f.Value)
+	require.Equal(t, "mode", f.Type())
+}
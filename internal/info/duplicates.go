@@ -0,0 +1,124 @@
+package info
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/desertwitch/par2cron/internal/verify"
+)
+
+// DuplicateInfo contains PAR2 sets sharing the same set ID across paths.
+type DuplicateInfo struct {
+	// Count is the number of set IDs found at more than one path.
+	Count int `json:"count"`
+
+	// Sets are the duplicated set IDs, along with their paths.
+	Sets []DuplicateSet `json:"sets"`
+
+	// Warning indicates PAR2 files that could not be parsed.
+	Warning string `json:"warning,omitempty"`
+}
+
+// DuplicateSet is a set ID found at more than one path.
+type DuplicateSet struct {
+	// SetID is the hex-encoded PAR2 set ID.
+	SetID string `json:"set_id"`
+
+	// Paths are the paths of the PAR2 index files (or bundles), sorted.
+	Paths []string `json:"paths"`
+}
+
+// findDuplicates parses the set IDs of all jobs and returns those set IDs
+// that were found at more than one path, sorted by their first path. Jobs
+// that cannot be parsed are skipped over, returning a non-fatal error.
+func (prog *Service) findDuplicates(ctx context.Context, metas []*verify.JobMeta) (*DuplicateInfo, error) {
+	pathsByID := make(map[par2.Hash][]string)
+
+	var errs []error
+	for _, meta := range metas {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("context error: %w", err)
+		}
+
+		sets, err := prog.parseSets(ctx, meta)
+		if err != nil {
+			prog.log.Warn("Failed to parse PAR2 set for duplicate detection", "path", meta.Par2Path, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", meta.Par2Path, err))
+
+			continue
+		}
+
+		for _, set := range sets {
+			if !slices.Contains(pathsByID[set.SetID], meta.Par2Path) {
+				pathsByID[set.SetID] = append(pathsByID[set.SetID], meta.Par2Path)
+			}
+		}
+	}
+
+	dups := &DuplicateInfo{Sets: []DuplicateSet{}}
+	for id, paths := range pathsByID {
+		if len(paths) < 2 { //nolint:mnd
+			continue
+		}
+		paths = prog.log.MapPaths(paths)
+		slices.Sort(paths)
+		dups.Sets = append(dups.Sets, DuplicateSet{SetID: fmt.Sprintf("%x", id), Paths: paths})
+	}
+	slices.SortFunc(dups.Sets, func(a, b DuplicateSet) int {
+		return strings.Compare(a.Paths[0], b.Paths[0])
+	})
+	dups.Count = len(dups.Sets)
+
+	if len(errs) > 0 {
+		return dups, fmt.Errorf("%w: %d PAR2 sets failed to parse: %w", schema.ErrNonFatal, len(errs), errors.Join(errs...))
+	}
+
+	return dups, nil
+}
+
+func (prog *Service) parseSets(ctx context.Context, meta *verify.JobMeta) ([]par2.Set, error) {
+	if meta.IsBundle {
+		sets, err := util.ParseBundlePar2Index(ctx, prog.fsys, meta.Par2Path, prog.par2er, prog.bundler)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bundle: %w", err)
+		}
+
+		return sets, nil
+	}
+
+	f, err := prog.par2er.ParseFile(ctx, prog.fsys, meta.Par2Path, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse: %w", err)
+	}
+
+	return f.Sets, nil
+}
+
+func (prog *Service) printDuplicateInfo(dups *DuplicateInfo, err error) {
+	if err != nil {
+		fmt.Fprintf(prog.log.Options.Stdout, "Warning: Not all PAR2 sets could be parsed for duplicate detection (%v)\n", err)
+		fmt.Fprintf(prog.log.Options.Stdout, "\n")
+	}
+
+	if dups.Count == 0 {
+		fmt.Fprintf(prog.log.Options.Stdout, "Duplicate PAR2 sets (same set ID at different paths): none\n")
+		fmt.Fprintf(prog.log.Options.Stdout, "\n")
+
+		return
+	}
+
+	fmt.Fprintf(prog.log.Options.Stdout, "Duplicate PAR2 sets (same set ID at different paths): %d\n", dups.Count)
+	for _, dup := range dups.Sets {
+		fmt.Fprintf(prog.log.Options.Stdout, "  Set ID %s (%d paths):\n", dup.SetID, len(dup.Paths))
+		for _, path := range dup.Paths {
+			fmt.Fprintf(prog.log.Options.Stdout, "    %s\n", path)
+		}
+	}
+	fmt.Fprintf(prog.log.Options.Stdout, "\n")
+}
@@ -0,0 +1,157 @@
+package info
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// newDuplicatesFs returns a filesystem with the same set ("a") at two paths,
+// and a unique set ("b"), all with a manifest containing a verification.
+func newDuplicatesFs(t *testing.T) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	writeTestSets(t, fs, map[string]*schema.Manifest{
+		"/data/one/a.par2": verifiedTestManifest("/data/one/a.par2"),
+		"/data/two/a.par2": verifiedTestManifest("/data/two/a.par2"),
+		"/data/two/b.par2": verifiedTestManifest("/data/two/b.par2"),
+	})
+
+	return fs
+}
+
+// setIDSets returns a set with the set ID derived from the filename.
+func setIDSets(path string) []par2.Set {
+	var id par2.Hash
+	copy(id[:], filepath.Base(path))
+
+	return []par2.Set{{SetID: id}}
+}
+
+func newDuplicatesService(t *testing.T, fs afero.Fs, par2er schema.Par2Handler, stdout io.Writer, wantJSON bool) *Service {
+	t.Helper()
+
+	ls := logging.Options{
+		Logout:   io.Discard,
+		Stdout:   stdout,
+		Stderr:   io.Discard,
+		WantJSON: wantJSON,
+	}
+
+	return NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, par2er, &testutil.MockCacheHandler{})
+}
+
+// Expectation: Sets sharing a set ID at different paths should be reported with count and paths.
+func Test_Service_Info_DetectDuplicates_Success(t *testing.T) {
+	t.Parallel()
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newDuplicatesFs(t), newTestParser(setIDSets), &stdout, false)
+
+	args := Options{DetectDuplicates: true}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	out := stdout.String()
+	require.Contains(t, out, "Duplicate PAR2 sets (same set ID at different paths): 1")
+	require.Contains(t, out, "(2 paths)")
+	require.Contains(t, out, "    /data/one/a.par2\n    /data/two/a.par2\n")
+	require.NotContains(t, out, "b.par2")
+}
+
+// Expectation: Without --detect-duplicates, no sets should be parsed.
+func Test_Service_Info_DetectDuplicates_Disabled_Success(t *testing.T) {
+	t.Parallel()
+
+	var parsed bool
+	par2er := &testutil.MockPar2Handler{
+		ParseFileFunc: func(fsys afero.Fs, path string, panicAsErr bool) (*par2.File, error) {
+			parsed = true
+
+			return &par2.File{}, nil
+		},
+	}
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newDuplicatesFs(t), par2er, &stdout, false)
+
+	args := Options{}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	require.False(t, parsed)
+	require.NotContains(t, stdout.String(), "Duplicate PAR2 sets")
+}
+
+// Expectation: The duplicates should be contained in the JSON result.
+func Test_Service_Info_DetectDuplicates_JSON_Success(t *testing.T) {
+	t.Parallel()
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newDuplicatesFs(t), newTestParser(setIDSets), &stdout, true)
+
+	args := Options{DetectDuplicates: true}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	var result Result
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+
+	require.NotNil(t, result.DuplicateInfo)
+	require.Equal(t, 1, result.DuplicateInfo.Count)
+	require.Len(t, result.DuplicateInfo.Sets, 1)
+	require.Equal(t, []string{"/data/one/a.par2", "/data/two/a.par2"}, result.DuplicateInfo.Sets[0].Paths)
+	require.True(t, strings.HasPrefix(result.DuplicateInfo.Sets[0].SetID, "612e70617232"))
+	require.Empty(t, result.DuplicateInfo.Warning)
+}
+
+// Expectation: Unparsable sets should be skipped with a warning, still reporting the others.
+func Test_Service_Info_DetectDuplicates_ParseFails_Success(t *testing.T) {
+	t.Parallel()
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newDuplicatesFs(t), newTestParser(setIDSets, "/data/two/b.par2"), &stdout, true)
+
+	args := Options{DetectDuplicates: true}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	var result Result
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+
+	require.Equal(t, 1, result.DuplicateInfo.Count)
+	require.Contains(t, result.DuplicateInfo.Warning, "1 PAR2 sets failed to parse")
+}
+
+// Expectation: The duplicate paths should be reported with the path prefix map applied.
+func Test_Service_Info_DetectDuplicates_PathPrefixMap_Success(t *testing.T) {
+	t.Parallel()
+
+	var stdout testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: &stdout,
+		Stderr: io.Discard,
+	}
+	require.NoError(t, ls.PathPrefixMap.Set("/data/two=/mnt/two"))
+	prog := NewService(newDuplicatesFs(t), logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, newTestParser(setIDSets), &testutil.MockCacheHandler{})
+
+	args := Options{DetectDuplicates: true}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	out := stdout.String()
+	require.Contains(t, out, "Scanning filesystem '/data' for jobs")
+	require.Contains(t, out, "    /data/one/a.par2\n    /mnt/two/a.par2\n")
+}
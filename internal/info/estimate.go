@@ -0,0 +1,168 @@
+package info
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/desertwitch/par2cron/internal/verify"
+)
+
+// recoveryPacketOverhead is the size of a recovery slice packet besides its
+// slice data, being the packet header (64 bytes) and the exponent (4 bytes).
+const recoveryPacketOverhead = 68
+
+var errNoSliceSize = errors.New("no main packet (unknown slice size)")
+
+// EstimateInfo contains the projected parity size of all PAR2 sets, if they
+// were re-created at the target redundancy (--target-redundancy).
+type EstimateInfo struct {
+	// TargetRedundancyPct is the target redundancy as a percentage.
+	TargetRedundancyPct float64 `json:"target_redundancy_pct"`
+
+	// SetCount is the number of PAR2 sets included in the estimate.
+	SetCount int `json:"set_count"`
+
+	// TotalSets is the number of PAR2 sets found (including those left out).
+	TotalSets int `json:"total_sets"`
+
+	// ProtectedBytes is the size of all protected files.
+	ProtectedBytes int64 `json:"protected_bytes"`
+
+	// CurrentParityBytes is the size of all PAR2 files (or bundles).
+	CurrentParityBytes int64 `json:"current_parity_bytes"`
+
+	// EstimatedParityBytes is the size of the recovery data at the target.
+	EstimatedParityBytes int64 `json:"estimated_parity_bytes"`
+
+	// DifferenceBytes is the estimated minus the current parity size.
+	DifferenceBytes int64 `json:"difference_bytes"`
+
+	// Partial is true if the estimate was stopped at the --duration.
+	Partial bool `json:"partial"`
+
+	// Warning indicates PAR2 sets that were left out of the estimate.
+	Warning string `json:"warning,omitempty"`
+}
+
+// setEstimate contains the estimate of a single PAR2 set.
+type setEstimate struct {
+	protectedBytes int64
+	parityBytes    int64
+	estimatedBytes int64
+}
+
+// collectEstimate parses all jobs and projects their parity sizes at the
+// target redundancy. With a duration given, collection stops once it is
+// exceeded, returning the estimate of the jobs collected until then. Jobs
+// that cannot be parsed are skipped over, returning a non-fatal error.
+func (prog *Service) collectEstimate(ctx context.Context, metas []*verify.JobMeta, target float64, maxDuration time.Duration) (*EstimateInfo, error) {
+	est := &EstimateInfo{TargetRedundancyPct: target * 100, TotalSets: len(metas)} //nolint:mnd
+
+	var deadline time.Time
+	if maxDuration > 0 {
+		deadline = time.Now().Add(maxDuration)
+	}
+
+	var errs []error
+	for i, meta := range metas {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("context error: %w", err)
+		}
+
+		if i > 0 && !deadline.IsZero() && time.Now().After(deadline) {
+			est.Partial = true
+
+			break
+		}
+
+		se, err := prog.estimateSet(ctx, meta, target)
+		if err != nil {
+			prog.log.Warn("Failed to parse PAR2 set for redundancy estimate", "path", meta.Par2Path, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", meta.Par2Path, err))
+
+			continue
+		}
+
+		est.SetCount++
+		est.ProtectedBytes += se.protectedBytes
+		est.CurrentParityBytes += se.parityBytes
+		est.EstimatedParityBytes += se.estimatedBytes
+	}
+	est.DifferenceBytes = est.EstimatedParityBytes - est.CurrentParityBytes
+
+	if len(errs) > 0 {
+		return est, fmt.Errorf("%w: %d PAR2 sets failed to parse: %w", schema.ErrNonFatal, len(errs), errors.Join(errs...))
+	}
+
+	return est, nil
+}
+
+// estimateSet projects the recovery data of a set at the target redundancy,
+// which (as with the "-r" argument of par2) is the share of its source slices
+// that are needed as recovery slices, keeping the slice size of the set.
+func (prog *Service) estimateSet(ctx context.Context, meta *verify.JobMeta, target float64) (*setEstimate, error) {
+	sets, err := prog.parseSets(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	se := &setEstimate{}
+
+	for _, set := range sets {
+		if set.MainPacket == nil || set.MainPacket.SliceSize == 0 {
+			return nil, errNoSliceSize
+		}
+		sliceSize := int64(set.MainPacket.SliceSize) //nolint:gosec
+
+		var sourceSlices int64
+		seen := make(map[par2.Hash]struct{})
+		for _, fp := range set.RecoverySet {
+			if _, ok := seen[fp.FileID]; ok {
+				continue
+			}
+			seen[fp.FileID] = struct{}{}
+
+			se.protectedBytes += fp.Size
+			sourceSlices += (fp.Size + sliceSize - 1) / sliceSize
+		}
+
+		recoverySlices := int64(math.Ceil(float64(sourceSlices) * target))
+		se.estimatedBytes += recoverySlices * (sliceSize + recoveryPacketOverhead)
+	}
+
+	se.parityBytes, err = prog.paritySize(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return se, nil
+}
+
+func (prog *Service) printEstimateInfo(est *EstimateInfo, err error) {
+	if err != nil {
+		fmt.Fprintf(prog.log.Options.Stdout, "Warning: Not all PAR2 sets could be parsed for the redundancy estimate (%v)\n", err)
+		fmt.Fprintf(prog.log.Options.Stdout, "\n")
+	}
+
+	if est.Partial {
+		fmt.Fprintf(prog.log.Options.Stdout, "Redundancy estimate at %.1f%% (PARTIAL, stopped after --duration at %d of %d PAR2 sets):\n", est.TargetRedundancyPct, est.SetCount, est.TotalSets)
+	} else {
+		fmt.Fprintf(prog.log.Options.Stdout, "Redundancy estimate at %.1f%% (%d of %d PAR2 sets):\n", est.TargetRedundancyPct, est.SetCount, est.TotalSets)
+	}
+	fmt.Fprintf(prog.log.Options.Stdout, "  %-28s %s\n", "Protected data:", util.FmtBytes(est.ProtectedBytes))
+	fmt.Fprintf(prog.log.Options.Stdout, "  %-28s %s\n", "Current parity data:", util.FmtBytes(est.CurrentParityBytes))
+	fmt.Fprintf(prog.log.Options.Stdout, "  %-28s %s\n", "Estimated parity data:", util.FmtBytes(est.EstimatedParityBytes))
+	if est.DifferenceBytes >= 0 {
+		fmt.Fprintf(prog.log.Options.Stdout, "  %-28s %s more\n", "Difference:", util.FmtBytes(est.DifferenceBytes))
+	} else {
+		fmt.Fprintf(prog.log.Options.Stdout, "  %-28s %s less\n", "Difference:", util.FmtBytes(-est.DifferenceBytes))
+	}
+	fmt.Fprintf(prog.log.Options.Stdout, "  (estimated recovery data only, excluding the index and metadata packets)\n")
+	fmt.Fprintf(prog.log.Options.Stdout, "\n")
+}
@@ -0,0 +1,133 @@
+package info
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// estimateSets returns the sets of two protected files (with one of them
+// repeated) of 11 source slices of 100 bytes, returning a set without a main
+// packet for any files in the sliceless paths.
+func estimateSets(sliceless ...string) func(path string) []par2.Set {
+	return func(path string) []par2.Set {
+		set := par2.Set{
+			MainPacket: &par2.MainPacket{SliceSize: 100},
+			RecoverySet: []par2.FilePacket{
+				{FileID: par2.Hash{1}, Name: "x.bin", Size: 300},
+				{FileID: par2.Hash{2}, Name: "y.bin", Size: 750},
+				{FileID: par2.Hash{1}, Name: "x.bin", Size: 300},
+			},
+		}
+		if slices.Contains(sliceless, path) {
+			set.MainPacket = nil
+		}
+
+		return []par2.Set{set}
+	}
+}
+
+// Expectation: The estimate should project the recovery slices at the target and compare them to the current parity.
+func Test_Service_Info_TargetRedundancy_Success(t *testing.T) {
+	t.Parallel()
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newStatsFs(t), newTestParser(estimateSets()), &stdout, false)
+
+	args := Options{}
+	_ = args.RunInterval.Set("24h")
+	_ = args.TargetRedundancy.Set("0.2")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	out := stdout.String()
+	require.Contains(t, out, "Redundancy estimate at 20.0% (2 of 2 PAR2 sets):")
+	require.Contains(t, out, "Protected data:              2.1 KiB\n")
+	require.Contains(t, out, "Current parity data:         200 B\n")
+	require.Contains(t, out, "Estimated parity data:       1008 B\n")
+	require.Contains(t, out, "Difference:                  808 B more\n")
+	require.NotContains(t, out, "PARTIAL")
+	require.NotContains(t, out, "Library statistics")
+}
+
+// Expectation: The estimate should be contained in the JSON result.
+func Test_Service_Info_TargetRedundancy_JSON_Success(t *testing.T) {
+	t.Parallel()
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newStatsFs(t), newTestParser(estimateSets()), &stdout, true)
+
+	args := Options{}
+	_ = args.RunInterval.Set("24h")
+	_ = args.TargetRedundancy.Set("1%")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	var result Result
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+
+	require.NotNil(t, result.EstimateInfo)
+	require.InDelta(t, 1.0, result.EstimateInfo.TargetRedundancyPct, 0.001)
+	require.Equal(t, 2, result.EstimateInfo.SetCount)
+	require.Equal(t, 2, result.EstimateInfo.TotalSets)
+	require.EqualValues(t, 2100, result.EstimateInfo.ProtectedBytes)
+	require.EqualValues(t, 200, result.EstimateInfo.CurrentParityBytes)
+	require.EqualValues(t, 336, result.EstimateInfo.EstimatedParityBytes)
+	require.EqualValues(t, 136, result.EstimateInfo.DifferenceBytes)
+	require.False(t, result.EstimateInfo.Partial)
+	require.Empty(t, result.EstimateInfo.Warning)
+	require.Equal(t, "1%", result.Options.TargetRedundancy.Raw)
+}
+
+// Expectation: Sets without a known slice size should be left out with a warning, still estimating the others.
+func Test_Service_Info_TargetRedundancy_NoSliceSize_Success(t *testing.T) {
+	t.Parallel()
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newStatsFs(t), newTestParser(estimateSets("/data/two/b.par2")), &stdout, true)
+
+	args := Options{}
+	_ = args.RunInterval.Set("24h")
+	_ = args.TargetRedundancy.Set("0.2")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	var result Result
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+
+	require.Equal(t, 1, result.EstimateInfo.SetCount)
+	require.Equal(t, 2, result.EstimateInfo.TotalSets)
+	require.EqualValues(t, 504, result.EstimateInfo.EstimatedParityBytes)
+	require.Contains(t, result.EstimateInfo.Warning, "1 PAR2 sets failed to parse")
+	require.Contains(t, result.EstimateInfo.Warning, "unknown slice size")
+}
+
+// Expectation: An exceeded duration should stop the estimate and mark it as partial.
+func Test_Service_collectEstimate_Duration_Success(t *testing.T) {
+	t.Parallel()
+
+	var parsed int
+	par2er := newTestParser(estimateSets())
+	parseFunc := par2er.ParseFileFunc
+	par2er.ParseFileFunc = func(fsys afero.Fs, path string, panicAsErr bool) (*par2.File, error) {
+		parsed++
+		time.Sleep(5 * time.Millisecond)
+
+		return parseFunc(fsys, path, panicAsErr)
+	}
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newStatsFs(t), par2er, &stdout, false)
+
+	args := Options{}
+	_ = args.RunInterval.Set("24h")
+	_ = args.MaxDuration.Set("1ms")
+	_ = args.TargetRedundancy.Set("0.2")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	require.Equal(t, 1, parsed)
+	require.Contains(t, stdout.String(), "Redundancy estimate at 20.0% (PARTIAL, stopped after --duration at 1 of 2 PAR2 sets):")
+}
@@ -0,0 +1,72 @@
+package info
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/verify"
+)
+
+// HealthInfo contains the PAR2 sets with a health score below the maximum.
+type HealthInfo struct {
+	// Count is the number of sets having a health score below the maximum.
+	Count int `json:"count"`
+
+	// Sets are these sets, sorted by their health score (worst first).
+	Sets []HealthSet `json:"sets"`
+}
+
+// HealthSet is a PAR2 set with its (advisory) health score.
+type HealthSet struct {
+	// Path is the path of the PAR2 index file (or bundle).
+	Path string `json:"path"`
+
+	// Score is the health score (see [schema.HealthScore]).
+	Score int `json:"score"`
+}
+
+// collectHealth returns the sets with a health score below the maximum,
+// sorted by their score (worst first), ties by path. Sets without a health
+// history (such as never verified since it was introduced) are left out.
+func (prog *Service) collectHealth(metas []*verify.JobMeta) *HealthInfo {
+	health := &HealthInfo{Sets: []HealthSet{}}
+
+	for _, meta := range metas {
+		if !meta.HasHealth || meta.HealthScore >= schema.MaxHealthScore {
+			continue
+		}
+
+		health.Sets = append(health.Sets, HealthSet{
+			Path:  prog.log.MapPath(meta.Par2Path),
+			Score: meta.HealthScore,
+		})
+	}
+
+	slices.SortFunc(health.Sets, func(a, b HealthSet) int {
+		return cmp.Or(
+			cmp.Compare(a.Score, b.Score),
+			strings.Compare(a.Path, b.Path),
+		)
+	})
+	health.Count = len(health.Sets)
+
+	return health
+}
+
+func (prog *Service) printHealthInfo(health *HealthInfo) {
+	if health.Count == 0 {
+		fmt.Fprintf(prog.log.Options.Stdout, "PAR2 sets with a lowered health score: none\n")
+		fmt.Fprintf(prog.log.Options.Stdout, "\n")
+
+		return
+	}
+
+	fmt.Fprintf(prog.log.Options.Stdout, "PAR2 sets with a lowered health score (worst first): %d\n", health.Count)
+	for _, set := range health.Sets {
+		fmt.Fprintf(prog.log.Options.Stdout, "  %3d  %s\n", set.Score, set.Path)
+	}
+	fmt.Fprintf(prog.log.Options.Stdout, "\n")
+}
@@ -0,0 +1,93 @@
+package info
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// newHealthFs returns a filesystem with four sets, three of them having a
+// health history (with a perfect, a lowered and a worst score).
+func newHealthFs(t *testing.T) afero.Fs {
+	t.Helper()
+
+	sets := map[string]*schema.Manifest{}
+	for path, health := range map[string]*schema.HealthManifest{
+		"/data/perfect/a.par2": {CountClean: 5, Score: schema.MaxHealthScore},
+		"/data/lowered/b.par2": {CountClean: 5, CountCorrupted: 1, Score: schema.HealthScore(5, 1, 0)},
+		"/data/worst/c.par2":   {CountCorrupted: 2, CountRepaired: 2, Score: schema.HealthScore(0, 2, 2)},
+		"/data/unknown/d.par2": nil,
+	} {
+		sets[path] = schema.NewManifest(filepath.Base(path))
+		sets[path].Health = health
+	}
+
+	fs := afero.NewMemMapFs()
+	writeTestSets(t, fs, sets)
+
+	return fs
+}
+
+// Expectation: The sets with a lowered health score should be listed, worst first.
+func Test_Service_Info_Health_Success(t *testing.T) {
+	t.Parallel()
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newHealthFs(t), newTestParser(statsSets), &stdout, false)
+
+	args := Options{Health: true}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	out := stdout.String()
+	require.Contains(t, out, "PAR2 sets with a lowered health score (worst first): 2\n"+
+		"    9  /data/worst/c.par2\n"+
+		"   75  /data/lowered/b.par2\n")
+	require.NotContains(t, out, "/data/perfect/a.par2")
+	require.NotContains(t, out, "/data/unknown/d.par2")
+}
+
+// Expectation: Without any lowered health scores, none should be reported.
+func Test_Service_Info_Health_None_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := newHealthFs(t)
+	require.NoError(t, fs.RemoveAll("/data/lowered"))
+	require.NoError(t, fs.RemoveAll("/data/worst"))
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, fs, newTestParser(statsSets), &stdout, false)
+
+	args := Options{Health: true}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	require.Contains(t, stdout.String(), "PAR2 sets with a lowered health score: none\n")
+}
+
+// Expectation: The sets with a lowered health score should be contained in the JSON result.
+func Test_Service_Info_Health_JSON_Success(t *testing.T) {
+	t.Parallel()
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newHealthFs(t), newTestParser(statsSets), &stdout, true)
+
+	args := Options{Health: true}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	var result Result
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+
+	require.NotNil(t, result.HealthInfo)
+	require.Equal(t, 2, result.HealthInfo.Count)
+	require.Equal(t, []HealthSet{
+		{Path: "/data/worst/c.par2", Score: schema.HealthScore(0, 2, 2)},
+		{Path: "/data/lowered/b.par2", Score: schema.HealthScore(5, 1, 0)},
+	}, result.HealthInfo.Sets)
+}
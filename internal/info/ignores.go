@@ -0,0 +1,73 @@
+package info
+
+import (
+	"fmt"
+
+	"github.com/desertwitch/par2cron/internal/util"
+)
+
+// IgnoreInfo contains the ignore (and ignore-all) files honored during the scan.
+type IgnoreInfo struct {
+	// Count is the number of ignore files that were honored.
+	Count int `json:"count"`
+
+	// SetCount is the number of PAR2 sets skipped due to these ignore files.
+	SetCount int `json:"set_count"`
+
+	// Files are these ignore files, sorted by their path.
+	Files []IgnoreFile `json:"files"`
+}
+
+// IgnoreFile is an ignore (or ignore-all) file honored during the scan.
+type IgnoreFile struct {
+	// Path is the path of the ignore file.
+	Path string `json:"path"`
+
+	// All is whether it is an ignore-all file (also covering subdirectories).
+	All bool `json:"all"`
+
+	// SetCount is the number of PAR2 sets skipped due to the ignore file.
+	SetCount int `json:"set_count"`
+
+	// DirCount is the number of directories these skipped PAR2 sets are in.
+	DirCount int `json:"dir_count"`
+}
+
+// collectIgnores returns the ignore files honored during the scan. Only those
+// having caused any PAR2 sets to be skipped are known, as the walk checks for
+// ignore files only upon encountering a PAR2 set.
+func (prog *Service) collectIgnores(hits *util.IgnoreHits) *IgnoreInfo {
+	ignores := &IgnoreInfo{Files: []IgnoreFile{}}
+
+	for _, hit := range hits.List() {
+		ignores.Files = append(ignores.Files, IgnoreFile{
+			Path:     prog.log.MapPath(hit.Path),
+			All:      hit.All,
+			SetCount: hit.Sets,
+			DirCount: hit.Dirs,
+		})
+		ignores.SetCount += hit.Sets
+	}
+	ignores.Count = len(ignores.Files)
+
+	return ignores
+}
+
+func (prog *Service) printIgnoreInfo(ignores *IgnoreInfo) {
+	if ignores.Count == 0 {
+		fmt.Fprintf(prog.log.Options.Stdout, "Ignore files honored during the scan: none\n")
+		fmt.Fprintf(prog.log.Options.Stdout, "\n")
+
+		return
+	}
+
+	fmt.Fprintf(prog.log.Options.Stdout, "Ignore files honored during the scan: %d (%d PAR2 sets skipped)\n", ignores.Count, ignores.SetCount)
+	for _, file := range ignores.Files {
+		kind := "ignore"
+		if file.All {
+			kind = "ignore-all"
+		}
+		fmt.Fprintf(prog.log.Options.Stdout, "  %-10s  %d sets in %d dirs  %s\n", kind, file.SetCount, file.DirCount, file.Path)
+	}
+	fmt.Fprintf(prog.log.Options.Stdout, "\n")
+}
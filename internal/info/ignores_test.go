@@ -0,0 +1,88 @@
+package info
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// newIgnoresFs returns a filesystem with five sets, four of them skipped due
+// to an ignore file (one set) and an ignore-all file (three sets in two dirs).
+func newIgnoresFs(t *testing.T) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	writeTestSets(t, fs, map[string]*schema.Manifest{
+		"/data/kept/a.par2":    nil,
+		"/data/ignored/b.par2": nil,
+		"/data/all/c.par2":     nil,
+		"/data/all/sub/d.par2": nil,
+		"/data/all/sub/e.par2": nil,
+	})
+	require.NoError(t, afero.WriteFile(fs, "/data/ignored/"+schema.IgnoreFile, []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/all/"+schema.IgnoreAllFile, []byte{}, 0o644))
+
+	return fs
+}
+
+// Expectation: The honored ignore files should be listed with their skipped sets and directories.
+func Test_Service_Info_Ignores_Success(t *testing.T) {
+	t.Parallel()
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newIgnoresFs(t), newTestParser(statsSets), &stdout, false)
+
+	args := Options{Ignores: true}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	require.Contains(t, stdout.String(), "Ignore files honored during the scan: 2 (4 PAR2 sets skipped)\n"+
+		"  ignore-all  3 sets in 2 dirs  /data/all/"+schema.IgnoreAllFile+"\n"+
+		"  ignore      1 sets in 1 dirs  /data/ignored/"+schema.IgnoreFile+"\n")
+}
+
+// Expectation: Without any honored ignore files, none should be reported.
+func Test_Service_Info_Ignores_None_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := newIgnoresFs(t)
+	require.NoError(t, fs.Remove("/data/ignored/"+schema.IgnoreFile))
+	require.NoError(t, fs.Remove("/data/all/"+schema.IgnoreAllFile))
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, fs, newTestParser(statsSets), &stdout, false)
+
+	args := Options{Ignores: true}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	require.Contains(t, stdout.String(), "Ignore files honored during the scan: none\n")
+}
+
+// Expectation: The honored ignore files should be contained in the JSON result.
+func Test_Service_Info_Ignores_JSON_Success(t *testing.T) {
+	t.Parallel()
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newIgnoresFs(t), newTestParser(statsSets), &stdout, true)
+
+	args := Options{Ignores: true}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	var result Result
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+
+	require.NotNil(t, result.IgnoreInfo)
+	require.Equal(t, 2, result.IgnoreInfo.Count)
+	require.Equal(t, 4, result.IgnoreInfo.SetCount)
+	require.Equal(t, []IgnoreFile{
+		{Path: "/data/all/" + schema.IgnoreAllFile, All: true, SetCount: 3, DirCount: 2},
+		{Path: "/data/ignored/" + schema.IgnoreFile, All: false, SetCount: 1, DirCount: 1},
+	}, result.IgnoreInfo.Files)
+	require.Equal(t, 1, result.Summary.JobCount)
+}
@@ -24,7 +24,20 @@ type Options struct {
 	RunInterval     flags.Duration `json:"run_interval"`
 	IncludeExternal bool           `json:"include_external"`
 	SkipNotCreated  bool           `json:"skip_not_created"`
+	Tags            flags.Tags     `json:"tags"`
 	CacheDir        string         `json:"cache_dir"`
+
+	DetectDuplicates bool `json:"detect_duplicates"`
+	Stats            bool `json:"stats"`
+	Health           bool `json:"health"`
+	Ignores          bool `json:"ignores"`
+
+	TargetRedundancy flags.Redundancy `json:"target_redundancy"`
+
+	IgnoreNames     util.IgnoreNames  `json:"-"`
+	SidecarNames    util.SidecarNames `json:"-"`
+	MaxDepth        flags.MaxDepth    `json:"-"`
+	MaxManifestSize int64             `json:"-"`
 }
 
 type Service struct {
@@ -34,10 +47,18 @@ type Service struct {
 	runner  schema.CommandRunner
 	walker  schema.FilesystemWalker
 	bundler schema.BundleHandler
+	par2er  schema.Par2Handler
 	cacher  schema.CacheHandler
 }
 
-func NewService(fsys afero.Fs, log *logging.Logger, runner schema.CommandRunner, bundler schema.BundleHandler, cacher schema.CacheHandler) *Service {
+func NewService(
+	fsys afero.Fs,
+	log *logging.Logger,
+	runner schema.CommandRunner,
+	bundler schema.BundleHandler,
+	par2er schema.Par2Handler,
+	cacher schema.CacheHandler,
+) *Service {
 	var walker schema.FilesystemWalker
 	if _, ok := fsys.(*afero.OsFs); ok {
 		walker = util.OSWalker{}
@@ -51,6 +72,7 @@ func NewService(fsys afero.Fs, log *logging.Logger, runner schema.CommandRunner,
 		runner:  runner,
 		walker:  walker,
 		bundler: bundler,
+		par2er:  par2er,
 		cacher:  cacher,
 	}
 }
@@ -63,7 +85,7 @@ func (prog *Service) openCache(rootDir string, opts Options) schema.Cache {
 	}
 
 	if err := cache.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
-		fmt.Fprintf(prog.log.Options.Stdout, "Warning: Manifest cache for '%s' could not be loaded (%v)\n", rootDir, err)
+		fmt.Fprintf(prog.log.Options.Stdout, "Warning: Manifest cache for '%s' could not be loaded (%v)\n", prog.log.MapPath(rootDir), err)
 	}
 
 	return cache
@@ -84,14 +106,17 @@ func (prog *Service) Info(ctx context.Context, rootDirs []string, opts Options)
 	now := time.Now()
 
 	vs := verify.NewService(prog.fsys, prog.log, prog.runner, prog.bundler, prog.cacher)
-	va := verify.Options{IncludeExternal: opts.IncludeExternal, SkipNotCreated: opts.SkipNotCreated}
+	va := verify.Options{IncludeExternal: opts.IncludeExternal, SkipNotCreated: opts.SkipNotCreated, Tags: opts.Tags, IgnoreNames: opts.IgnoreNames, SidecarNames: opts.SidecarNames, MaxDepth: opts.MaxDepth, MaxManifestSize: opts.MaxManifestSize}
+	if opts.Ignores {
+		va.IgnoreHits = util.NewIgnoreHits()
+	}
 
 	metas := []*verify.JobMeta{}
 	for _, rootDir := range rootDirs {
 		cache := prog.openCache(rootDir, opts)
 
 		fmt.Fprintf(prog.log.Options.Stdout, "Scanning filesystem '%s' for jobs (using '%s', %d in cache)...\n",
-			rootDir, prog.walker.Name(), cache.Len())
+			prog.log.MapPath(rootDir), prog.walker.Name(), cache.Len())
 
 		meta, err := vs.Enumerate(ctx, rootDir, va, cache)
 		if err != nil {
@@ -99,7 +124,7 @@ func (prog *Service) Info(ctx context.Context, rootDirs []string, opts Options)
 				return fmt.Errorf("%s: failed to enumerate jobs: %w", rootDir, err)
 			}
 
-			fmt.Fprintf(prog.log.Options.Stdout, "Warning: Not all manifests could be read for '%s' (%v)\n", rootDir, err)
+			fmt.Fprintf(prog.log.Options.Stdout, "Warning: Not all manifests could be read for '%s' (%v)\n", prog.log.MapPath(rootDir), err)
 		}
 
 		cache.PruneUnwalked()
@@ -130,6 +155,38 @@ func (prog *Service) Info(ctx context.Context, rootDirs []string, opts Options)
 	}
 	fmt.Fprintf(prog.log.Options.Stdout, "\n")
 
+	if opts.DetectDuplicates {
+		dups, err := prog.findDuplicates(ctx, metas)
+		if err != nil && !errors.Is(err, schema.ErrNonFatal) {
+			return fmt.Errorf("failed to detect duplicates: %w", err)
+		}
+		prog.printDuplicateInfo(dups, err)
+	}
+
+	if opts.Stats {
+		stats, err := prog.collectStats(ctx, metas, opts.MaxDuration.Value)
+		if err != nil && !errors.Is(err, schema.ErrNonFatal) {
+			return fmt.Errorf("failed to collect statistics: %w", err)
+		}
+		prog.printStatsInfo(stats, err)
+	}
+
+	if opts.TargetRedundancy.Value > 0 {
+		est, err := prog.collectEstimate(ctx, metas, opts.TargetRedundancy.Value, opts.MaxDuration.Value)
+		if err != nil && !errors.Is(err, schema.ErrNonFatal) {
+			return fmt.Errorf("failed to estimate redundancy: %w", err)
+		}
+		prog.printEstimateInfo(est, err)
+	}
+
+	if opts.Health {
+		prog.printHealthInfo(prog.collectHealth(metas))
+	}
+
+	if opts.Ignores {
+		prog.printIgnoreInfo(prog.collectIgnores(va.IgnoreHits))
+	}
+
 	if js.KnownCount == 0 {
 		fmt.Fprintf(prog.log.Options.Stdout, "Warning: No duration data available, run a full verification to establish baseline\n")
 		fmt.Fprintf(prog.log.Options.Stdout, "\n")
@@ -243,7 +300,7 @@ func (prog *Service) printCycleInfo(js verify.Stats, jobs []*verify.JobMeta, opt
 		if job.HasVerification {
 			if job.VerifyTime.After(cycleStart) {
 				verifiedCount++
-				verifiedDuration += job.VerifyDuration
+				verifiedDuration += job.EstDuration()
 			}
 		}
 	}
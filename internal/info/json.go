@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io/fs"
 	"path/filepath"
-	"slices"
 	"time"
 
 	"github.com/desertwitch/par2cron/internal/schema"
@@ -41,6 +40,21 @@ type Result struct {
 	// CycleInfo contains verification progress within the current cycle window.
 	CycleInfo *CycleInfo `json:"cycle_info,omitempty"`
 
+	// DuplicateInfo contains PAR2 sets sharing a set ID (--detect-duplicates).
+	DuplicateInfo *DuplicateInfo `json:"duplicate_info,omitempty"`
+
+	// StatsInfo contains redundancy statistics across all PAR2 sets (--stats).
+	StatsInfo *StatsInfo `json:"stats_info,omitempty"`
+
+	// EstimateInfo contains the projected parity size at a target redundancy (--target-redundancy).
+	EstimateInfo *EstimateInfo `json:"estimate_info,omitempty"`
+
+	// HealthInfo contains the PAR2 sets with a lowered health score (--health).
+	HealthInfo *HealthInfo `json:"health_info,omitempty"`
+
+	// IgnoreInfo contains the ignore files honored during the scan (--ignores).
+	IgnoreInfo *IgnoreInfo `json:"ignore_info,omitempty"`
+
 	// Warning indicates issues encountered during enumeration.
 	Warning string `json:"warning,omitempty"`
 }
@@ -185,7 +199,7 @@ func (prog *Service) openCacheJSON(rootDir string, opts Options, result *Result)
 	}
 
 	if err := cache.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
-		result.Warning = fmt.Sprintf("Manifest cache for '%s' could not be loaded: %v", rootDir, err)
+		result.Warning = fmt.Sprintf("Manifest cache for '%s' could not be loaded: %v", prog.log.MapPath(rootDir), err)
 	}
 
 	return cache
@@ -199,10 +213,13 @@ func (prog *Service) Result(ctx context.Context, rootDirs []string, opts Options
 	now := time.Now()
 
 	vs := verify.NewService(prog.fsys, prog.log, prog.runner, prog.bundler, prog.cacher)
-	va := verify.Options{IncludeExternal: opts.IncludeExternal, SkipNotCreated: opts.SkipNotCreated}
+	va := verify.Options{IncludeExternal: opts.IncludeExternal, SkipNotCreated: opts.SkipNotCreated, Tags: opts.Tags, IgnoreNames: opts.IgnoreNames, SidecarNames: opts.SidecarNames, MaxDepth: opts.MaxDepth, MaxManifestSize: opts.MaxManifestSize}
+	if opts.Ignores {
+		va.IgnoreHits = util.NewIgnoreHits()
+	}
 
 	result := &Result{
-		Roots:   slices.Clone(rootDirs),
+		Roots:   prog.log.MapPaths(rootDirs),
 		Time:    now,
 		Options: &opts,
 	}
@@ -253,6 +270,50 @@ func (prog *Service) Result(ctx context.Context, rootDirs []string, opts Options
 		result.Summary.LastVerification = &js.LastVerification
 	}
 
+	if opts.DetectDuplicates {
+		dups, err := prog.findDuplicates(ctx, metas)
+		if err != nil {
+			if !errors.Is(err, schema.ErrNonFatal) {
+				return nil, fmt.Errorf("failed to detect duplicates: %w", err)
+			}
+
+			dups.Warning = fmt.Sprintf("Not all PAR2 sets could be parsed: %v", err)
+		}
+		result.DuplicateInfo = dups
+	}
+
+	if opts.Stats {
+		stats, err := prog.collectStats(ctx, metas, opts.MaxDuration.Value)
+		if err != nil {
+			if !errors.Is(err, schema.ErrNonFatal) {
+				return nil, fmt.Errorf("failed to collect statistics: %w", err)
+			}
+
+			stats.Warning = fmt.Sprintf("Not all PAR2 sets could be parsed: %v", err)
+		}
+		result.StatsInfo = stats
+	}
+
+	if opts.TargetRedundancy.Value > 0 {
+		est, err := prog.collectEstimate(ctx, metas, opts.TargetRedundancy.Value, opts.MaxDuration.Value)
+		if err != nil {
+			if !errors.Is(err, schema.ErrNonFatal) {
+				return nil, fmt.Errorf("failed to estimate redundancy: %w", err)
+			}
+
+			est.Warning = fmt.Sprintf("Not all PAR2 sets could be parsed: %v", err)
+		}
+		result.EstimateInfo = est
+	}
+
+	if opts.Health {
+		result.HealthInfo = prog.collectHealth(metas)
+	}
+
+	if opts.Ignores {
+		result.IgnoreInfo = prog.collectIgnores(va.IgnoreHits)
+	}
+
 	if js.KnownCount == 0 {
 		result.Summary.Warning = "No duration data available, run a full verification to establish baseline"
 
@@ -348,7 +409,7 @@ func (prog *Service) buildCycleInfo(js verify.Stats, jobs []*verify.JobMeta, opt
 		if job.HasVerification {
 			if job.VerifyTime.After(cycleStart) {
 				verifiedCount++
-				verifiedDuration += job.VerifyDuration
+				verifiedDuration += job.EstDuration()
 			}
 		}
 	}
@@ -0,0 +1,180 @@
+package info
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/desertwitch/par2cron/internal/verify"
+)
+
+// StatsInfo contains the redundancy statistics across all PAR2 sets.
+type StatsInfo struct {
+	// SetCount is the number of PAR2 sets included in the statistics.
+	SetCount int `json:"set_count"`
+
+	// TotalSets is the number of PAR2 sets found (including those left out).
+	TotalSets int `json:"total_sets"`
+
+	// Files is the number of protected files.
+	Files int `json:"files"`
+
+	// ProtectedBytes is the size of all protected files.
+	ProtectedBytes int64 `json:"protected_bytes"`
+
+	// ParityBytes is the size of all PAR2 files (or bundles).
+	ParityBytes int64 `json:"parity_bytes"`
+
+	// RedundancyPct is the parity size as percentage of the protected size.
+	RedundancyPct float64 `json:"redundancy_pct"`
+
+	// AvgSetBytes is the average size of protected files per PAR2 set.
+	AvgSetBytes int64 `json:"avg_set_bytes"`
+
+	// Partial is true if the statistics were stopped at the --duration.
+	Partial bool `json:"partial"`
+
+	// Warning indicates PAR2 sets that were left out of the statistics.
+	Warning string `json:"warning,omitempty"`
+}
+
+// setStats contains the statistics of a single PAR2 set.
+type setStats struct {
+	files          int
+	protectedBytes int64
+	parityBytes    int64
+}
+
+// collectStats parses all jobs and sums up their protected and parity sizes.
+// With a duration given, collection stops once it is exceeded, returning the
+// statistics of the jobs collected until then. Jobs that cannot be parsed are
+// skipped over, returning a non-fatal error.
+func (prog *Service) collectStats(ctx context.Context, metas []*verify.JobMeta, maxDuration time.Duration) (*StatsInfo, error) {
+	stats := &StatsInfo{TotalSets: len(metas)}
+
+	var deadline time.Time
+	if maxDuration > 0 {
+		deadline = time.Now().Add(maxDuration)
+	}
+
+	var errs []error
+	for i, meta := range metas {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("context error: %w", err)
+		}
+
+		if i > 0 && !deadline.IsZero() && time.Now().After(deadline) {
+			stats.Partial = true
+
+			break
+		}
+
+		ss, err := prog.statSet(ctx, meta)
+		if err != nil {
+			prog.log.Warn("Failed to parse PAR2 set for statistics", "path", meta.Par2Path, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", meta.Par2Path, err))
+
+			continue
+		}
+
+		stats.SetCount++
+		stats.Files += ss.files
+		stats.ProtectedBytes += ss.protectedBytes
+		stats.ParityBytes += ss.parityBytes
+	}
+
+	if stats.ProtectedBytes > 0 {
+		stats.RedundancyPct = float64(stats.ParityBytes) / float64(stats.ProtectedBytes) * 100 //nolint:mnd
+	}
+	if stats.SetCount > 0 {
+		stats.AvgSetBytes = stats.ProtectedBytes / int64(stats.SetCount)
+	}
+
+	if len(errs) > 0 {
+		return stats, fmt.Errorf("%w: %d PAR2 sets failed to parse: %w", schema.ErrNonFatal, len(errs), errors.Join(errs...))
+	}
+
+	return stats, nil
+}
+
+func (prog *Service) statSet(ctx context.Context, meta *verify.JobMeta) (*setStats, error) {
+	sets, err := prog.parseSets(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	ss := &setStats{}
+
+	seen := make(map[par2.Hash]struct{})
+	for _, set := range sets {
+		for _, fp := range set.RecoverySet {
+			if _, ok := seen[fp.FileID]; ok {
+				continue
+			}
+			seen[fp.FileID] = struct{}{}
+
+			ss.files++
+			ss.protectedBytes += fp.Size
+		}
+	}
+
+	ss.parityBytes, err = prog.paritySize(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
+
+// paritySize returns the size of all PAR2 files of a set (index and volumes),
+// or the size of the bundle file (which also includes the par2cron manifest).
+func (prog *Service) paritySize(meta *verify.JobMeta) (int64, error) {
+	if meta.IsBundle {
+		fi, err := prog.fsys.Stat(meta.Par2Path)
+		if err != nil {
+			return 0, fmt.Errorf("failed to stat bundle: %w", err)
+		}
+
+		return fi.Size(), nil
+	}
+
+	volumes, err := par2.VolumeFiles(prog.fsys, meta.Par2Path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find volumes: %w", err)
+	}
+
+	var size int64
+	for _, path := range append([]string{meta.Par2Path}, volumes...) {
+		fi, err := prog.fsys.Stat(path)
+		if err != nil {
+			return 0, fmt.Errorf("failed to stat par2: %w", err)
+		}
+
+		size += fi.Size()
+	}
+
+	return size, nil
+}
+
+func (prog *Service) printStatsInfo(stats *StatsInfo, err error) {
+	if err != nil {
+		fmt.Fprintf(prog.log.Options.Stdout, "Warning: Not all PAR2 sets could be parsed for statistics (%v)\n", err)
+		fmt.Fprintf(prog.log.Options.Stdout, "\n")
+	}
+
+	if stats.Partial {
+		fmt.Fprintf(prog.log.Options.Stdout, "Library statistics (PARTIAL, stopped after --duration at %d of %d PAR2 sets):\n", stats.SetCount, stats.TotalSets)
+	} else {
+		fmt.Fprintf(prog.log.Options.Stdout, "Library statistics (%d of %d PAR2 sets):\n", stats.SetCount, stats.TotalSets)
+	}
+	fmt.Fprintf(prog.log.Options.Stdout, "  %-28s %d\n", "Protected files:", stats.Files)
+	fmt.Fprintf(prog.log.Options.Stdout, "  %-28s %s\n", "Protected data:", util.FmtBytes(stats.ProtectedBytes))
+	fmt.Fprintf(prog.log.Options.Stdout, "  %-28s %s\n", "Parity data:", util.FmtBytes(stats.ParityBytes))
+	fmt.Fprintf(prog.log.Options.Stdout, "  %-28s %.1f%%\n", "Redundancy:", stats.RedundancyPct)
+	fmt.Fprintf(prog.log.Options.Stdout, "  %-28s %s\n", "Average set size:", util.FmtBytes(stats.AvgSetBytes))
+	fmt.Fprintf(prog.log.Options.Stdout, "\n")
+}
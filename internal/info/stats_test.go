@@ -0,0 +1,161 @@
+package info
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// newStatsFs returns a filesystem with two sets ("a" and "b"), each with a
+// recovery volume and a manifest containing a verification.
+func newStatsFs(t *testing.T) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	writeTestSets(t, fs, map[string]*schema.Manifest{
+		"/data/one/a.par2": verifiedTestManifest("/data/one/a.par2"),
+		"/data/two/b.par2": verifiedTestManifest("/data/two/b.par2"),
+	})
+	for _, path := range []string{"/data/one/a.par2", "/data/two/b.par2"} {
+		vol := strings.TrimSuffix(path, schema.Par2Extension) + ".vol00+01" + schema.Par2Extension
+		require.NoError(t, afero.WriteFile(fs, vol, make([]byte, 96), 0o644))
+	}
+	require.NoError(t, afero.WriteFile(fs, "/data/one/other.par2", make([]byte, 1000), 0o644))
+
+	return fs
+}
+
+// statsSets returns a set of two protected files (with one of them repeated).
+func statsSets(string) []par2.Set {
+	return []par2.Set{{
+		RecoverySet: []par2.FilePacket{
+			{FileID: par2.Hash{1}, Name: "x.bin", Size: 300},
+			{FileID: par2.Hash{2}, Name: "y.bin", Size: 700},
+			{FileID: par2.Hash{1}, Name: "x.bin", Size: 300},
+		},
+	}}
+}
+
+// Expectation: The statistics should sum up protected files and parity sizes of all sets.
+func Test_Service_Info_Stats_Success(t *testing.T) {
+	t.Parallel()
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newStatsFs(t), newTestParser(statsSets), &stdout, false)
+
+	args := Options{Stats: true}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	out := stdout.String()
+	require.Contains(t, out, "Library statistics (2 of 2 PAR2 sets):")
+	require.Contains(t, out, "Protected files:             4\n")
+	require.Contains(t, out, "Protected data:              2.0 KiB\n")
+	require.Contains(t, out, "Parity data:                 200 B\n")
+	require.Contains(t, out, "Redundancy:                  10.0%\n")
+	require.Contains(t, out, "Average set size:            1000 B\n")
+	require.NotContains(t, out, "PARTIAL")
+}
+
+// Expectation: Without --stats, no sets should be parsed.
+func Test_Service_Info_Stats_Disabled_Success(t *testing.T) {
+	t.Parallel()
+
+	var parsed bool
+	par2er := &testutil.MockPar2Handler{
+		ParseFileFunc: func(fsys afero.Fs, path string, panicAsErr bool) (*par2.File, error) {
+			parsed = true
+
+			return &par2.File{}, nil
+		},
+	}
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newStatsFs(t), par2er, &stdout, false)
+
+	args := Options{}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	require.False(t, parsed)
+	require.NotContains(t, stdout.String(), "Library statistics")
+}
+
+// Expectation: The statistics should be contained in the JSON result.
+func Test_Service_Info_Stats_JSON_Success(t *testing.T) {
+	t.Parallel()
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newStatsFs(t), newTestParser(statsSets), &stdout, true)
+
+	args := Options{Stats: true}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	var result Result
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+
+	require.NotNil(t, result.StatsInfo)
+	require.Equal(t, 2, result.StatsInfo.SetCount)
+	require.Equal(t, 2, result.StatsInfo.TotalSets)
+	require.Equal(t, 4, result.StatsInfo.Files)
+	require.EqualValues(t, 2000, result.StatsInfo.ProtectedBytes)
+	require.EqualValues(t, 200, result.StatsInfo.ParityBytes)
+	require.InDelta(t, 10.0, result.StatsInfo.RedundancyPct, 0.001)
+	require.EqualValues(t, 1000, result.StatsInfo.AvgSetBytes)
+	require.False(t, result.StatsInfo.Partial)
+	require.Empty(t, result.StatsInfo.Warning)
+}
+
+// Expectation: Unparsable sets should be left out with a warning, still reporting the others.
+func Test_Service_Info_Stats_ParseFails_Success(t *testing.T) {
+	t.Parallel()
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newStatsFs(t), newTestParser(statsSets, "/data/two/b.par2"), &stdout, true)
+
+	args := Options{Stats: true}
+	_ = args.RunInterval.Set("24h")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	var result Result
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+
+	require.Equal(t, 1, result.StatsInfo.SetCount)
+	require.Equal(t, 2, result.StatsInfo.TotalSets)
+	require.EqualValues(t, 1000, result.StatsInfo.ProtectedBytes)
+	require.Contains(t, result.StatsInfo.Warning, "1 PAR2 sets failed to parse")
+}
+
+// Expectation: An exceeded duration should stop the collection and mark the statistics as partial.
+func Test_Service_collectStats_Duration_Success(t *testing.T) {
+	t.Parallel()
+
+	var parsed int
+	par2er := newTestParser(statsSets)
+	parseFunc := par2er.ParseFileFunc
+	par2er.ParseFileFunc = func(fsys afero.Fs, path string, panicAsErr bool) (*par2.File, error) {
+		parsed++
+		time.Sleep(5 * time.Millisecond)
+
+		return parseFunc(fsys, path, panicAsErr)
+	}
+
+	var stdout testutil.SafeBuffer
+	prog := newDuplicatesService(t, newStatsFs(t), par2er, &stdout, false)
+
+	args := Options{Stats: true}
+	_ = args.RunInterval.Set("24h")
+	_ = args.MaxDuration.Set("1ms")
+	require.NoError(t, prog.Info(t.Context(), []string{"/data"}, args))
+
+	require.Equal(t, 1, parsed)
+	require.Contains(t, stdout.String(), "Library statistics (PARTIAL, stopped after --duration at 1 of 2 PAR2 sets):")
+}
@@ -0,0 +1,185 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var _ slog.Handler = (*flatHandler)(nil)
+
+// flatOrder is the fixed order of the well-known keys, which follow the time,
+// level and message of a record. All other keys follow these, sorted by key.
+var flatOrder = []string{
+	"op",
+	"job",
+	"job_position",
+	"job_position_sub",
+	"job_priority",
+	"path",
+	"error",
+}
+
+type flatAttr struct {
+	key   string
+	value string
+}
+
+// flatHandler writes each record as one logfmt line (key=value pairs) with a
+// fixed field order, for ingestion into systems that dislike multi-attribute
+// records. Groups are flattened into dotted keys, and values containing
+// spaces, quotes, equal signs or line breaks are quoted (and escaped).
+type flatHandler struct {
+	w     io.Writer
+	mu    *sync.Mutex
+	level slog.Leveler
+
+	attrs  []flatAttr
+	prefix string
+}
+
+func newFlatHandler(w io.Writer, level slog.Leveler) *flatHandler {
+	return &flatHandler{w: w, mu: &sync.Mutex{}, level: level}
+}
+
+func (h *flatHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *flatHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := slices.Clone(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendFlatAttr(attrs, h.prefix, a)
+
+		return true
+	})
+
+	slices.SortStableFunc(attrs, func(a, b flatAttr) int {
+		ra, rb := flatRank(a.key), flatRank(b.key)
+		if ra != rb {
+			return ra - rb
+		}
+		if ra < len(flatOrder) {
+			return 0
+		}
+
+		return strings.Compare(a.key, b.key)
+	})
+
+	var buf bytes.Buffer
+	if !r.Time.IsZero() {
+		buf.WriteString("time=")
+		buf.WriteString(r.Time.Format(time.RFC3339Nano))
+		buf.WriteByte(' ')
+	}
+	buf.WriteString("level=")
+	buf.WriteString(r.Level.String())
+	buf.WriteString(" msg=")
+	buf.WriteString(flatQuote(r.Message))
+	for _, a := range attrs {
+		buf.WriteByte(' ')
+		buf.WriteString(a.key)
+		buf.WriteByte('=')
+		buf.WriteString(a.value)
+	}
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, err := h.w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write: %w", err)
+	}
+
+	return nil
+}
+
+func (h *flatHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	nh := *h
+	nh.attrs = slices.Clone(h.attrs)
+	for _, a := range attrs {
+		nh.attrs = appendFlatAttr(nh.attrs, h.prefix, a)
+	}
+
+	return &nh
+}
+
+func (h *flatHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	nh := *h
+	nh.prefix = h.prefix + name + "."
+
+	return &nh
+}
+
+func flatRank(key string) int {
+	if i := slices.Index(flatOrder, key); i >= 0 {
+		return i
+	}
+
+	return len(flatOrder)
+}
+
+func appendFlatAttr(attrs []flatAttr, prefix string, a slog.Attr) []flatAttr {
+	v := a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+
+	if v.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			attrs = appendFlatAttr(attrs, groupPrefix, ga)
+		}
+
+		return attrs
+	}
+
+	return append(attrs, flatAttr{key: flatQuote(prefix + a.Key), value: flatValue(v)})
+}
+
+func flatValue(v slog.Value) string {
+	switch v.Kind() { //nolint:exhaustive
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return flatQuote(err.Error())
+		}
+
+		return flatQuote(fmt.Sprint(v.Any()))
+	default:
+		return flatQuote(v.String())
+	}
+}
+
+// flatQuote returns the string as is, or quoted if it would otherwise not
+// be a single logfmt value (always quoting line breaks into escapes).
+func flatQuote(s string) string {
+	if s == "" {
+		return `""`
+	}
+
+	if !utf8.ValidString(s) || strings.ContainsFunc(s, func(r rune) bool {
+		return r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r)
+	}) {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: A flat handler should be returned regardless of JSON.
+func Test_NewLogger_FlattenLogs_Success(t *testing.T) {
+	t.Parallel()
+
+	ls := Options{
+		Logout:      &testutil.SafeBuffer{},
+		WantJSON:    true,
+		FlattenLogs: true,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	logger := NewLogger(ls)
+	_, ok := logger.Handler().(*warnCountHandler).handler.(*flatHandler)
+
+	require.True(t, ok)
+}
+
+// Expectation: A job result should be written as one line with the well-known keys in a fixed order.
+func Test_flatHandler_JobResult_Success(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(newFlatHandler(&buf, slog.LevelInfo))
+
+	logger = logger.With("op", "verify")
+	logger.With("path", "/data/set.par2", "job_position", "1/2", "job", "/data/set.par2").
+		Error("Failed to verify PAR2",
+			"duration", 90*time.Second,
+			"error", errors.New("par2cmdline: repair is required\nrepair is possible"),
+			"args", []string{"-q", "-t8"},
+		)
+
+	require.Equal(t, 1, strings.Count(buf.String(), "\n"))
+
+	line := strings.TrimSpace(buf.String())
+	_, rest, ok := strings.Cut(line, " ")
+	require.True(t, ok)
+	require.True(t, strings.HasPrefix(line, "time="))
+	require.Equal(t, `level=ERROR msg="Failed to verify PAR2" op=verify job=/data/set.par2 job_position=1/2 path=/data/set.par2 `+
+		`error="par2cmdline: repair is required\nrepair is possible" args="[-q -t8]" duration=1m30s`, rest)
+}
+
+// Expectation: The field order should be stable regardless of the order of the attributes.
+func Test_flatHandler_StableOrder_Success(t *testing.T) {
+	t.Parallel()
+
+	var a, b bytes.Buffer
+	slog.New(newFlatHandler(&a, slog.LevelInfo)).Info("Done", "z", 1, "job", "x", "b", true, "op", "create", "a", "y")
+	slog.New(newFlatHandler(&b, slog.LevelInfo)).Info("Done", "a", "y", "op", "create", "b", true, "job", "x", "z", 1)
+
+	_, restA, _ := strings.Cut(a.String(), " ")
+	_, restB, _ := strings.Cut(b.String(), " ")
+
+	require.Equal(t, "level=INFO msg=Done op=create job=x a=y b=true z=1\n", restA)
+	require.Equal(t, restA, restB)
+}
+
+// Expectation: Groups should be flattened into dotted keys and records below the level dropped.
+func Test_flatHandler_GroupsAndLevel_Success(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(newFlatHandler(&buf, slog.LevelInfo))
+
+	logger.Debug("Hidden")
+	logger.WithGroup("stats").Info("Done", slog.Group("jobs", "ok", 2), "key", "")
+
+	_, rest, _ := strings.Cut(buf.String(), " ")
+	require.Equal(t, `level=INFO msg=Done stats.jobs.ok=2 stats.key=""`+"\n", rest)
+}
+
+// Expectation: Values should only be quoted when needed for a single logfmt value.
+func Test_flatQuote_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", "plain"},
+		{"", `""`},
+		{"with space", `"with space"`},
+		{"a=b", `"a=b"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{"line\nbreak", `"line\nbreak"`},
+		{"日本", "日本"},
+		{"\xff", `"\xff"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, flatQuote(tt.in))
+		})
+	}
+}
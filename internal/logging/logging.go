@@ -1,18 +1,22 @@
 package logging
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/desertwitch/par2cron/internal/flags"
+	"github.com/desertwitch/par2cron/internal/schema"
 	slogseq "github.com/desertwitch/slog-seq"
 	"github.com/lmittmann/tint"
 )
 
 type Options struct {
 	LogLevel flags.LogLevel
+	Color    flags.Color
 
 	Logout io.Writer
 	Stdout io.Writer
@@ -21,7 +25,45 @@ type Options struct {
 	SeqURL string
 	SeqKey string
 
+	// LogFile is an optional file that all logs are additionally written to,
+	// which is rotated once it would exceed LogFileSize MiB (keeping the last
+	// LogFileKeep rotated files around).
+	LogFile     string
+	LogFileSize int
+	LogFileKeep int
+
 	WantJSON bool
+
+	// FlattenLogs writes the logs as one logfmt line per record, with a fixed
+	// field order, in place of the text or JSON logs (results are unaffected).
+	FlattenLogs bool
+
+	// ProgressBar renders the advancement of a run's jobs on a status line
+	// below the console logs, only if these are text logs to a terminal.
+	ProgressBar bool
+
+	// PathPrefixMap rewrites the paths in all logs (and those results which
+	// support it), such as for showing host paths when inside a container.
+	PathPrefixMap flags.PathPrefixMap
+}
+
+const (
+	DefaultLogFileSize = 10
+	DefaultLogFileKeep = 5
+)
+
+func (o Options) Validate() error {
+	if o.LogFile == "" {
+		return nil
+	}
+	if o.LogFileSize < 1 {
+		return fmt.Errorf("log-file-size: must be at least 1 (MiB), got %d", o.LogFileSize)
+	}
+	if o.LogFileKeep < 0 {
+		return fmt.Errorf("log-file-keep: must not be negative, got %d", o.LogFileKeep)
+	}
+
+	return nil
 }
 
 type Logger struct {
@@ -29,27 +71,62 @@ type Logger struct {
 
 	Options    Options
 	seqHandler *slogseq.SeqHandler
+	logFile    *rotatingFile
+	progress   *progressLine
+
+	// warnings is the count of all records of warning level (or above),
+	// shared by all loggers derived from the same logger (with [Logger.With]).
+	warnings *atomic.Int64
 }
 
 func NewLogger(opts Options) *Logger {
 	var logger *slog.Logger
 
+	logout := opts.Logout
+	var progress *progressLine
+	if opts.ProgressBar && !opts.WantJSON && !opts.FlattenLogs && isTerminal(opts.Logout) {
+		progress = newProgressLine(opts.Logout)
+		logout = progress.writer(opts.Logout)
+
+		// The par2 output (if also to the terminal) must not corrupt the status line.
+		if isTerminal(opts.Stdout) {
+			opts.Stdout = progress.writer(opts.Stdout)
+		}
+	}
+
 	var consoleHandler slog.Handler
-	if opts.WantJSON {
+	switch {
+	case opts.FlattenLogs:
+		consoleHandler = newFlatHandler(logout, opts.LogLevel.Value)
+	case opts.WantJSON:
 		consoleHandler = slog.NewJSONHandler(opts.Logout, &slog.HandlerOptions{
 			Level: opts.LogLevel.Value,
 		})
-	} else {
-		consoleHandler = tint.NewHandler(opts.Logout, &tint.Options{
+	default:
+		consoleHandler = tint.NewHandler(logout, &tint.Options{
 			Level:      opts.LogLevel.Value,
 			TimeFormat: time.TimeOnly,
+			NoColor:    !wantColor(opts.Color.Value, opts.Logout, os.Getenv("NO_COLOR")),
 		})
 	}
 
+	handlers := []slog.Handler{consoleHandler}
+	consoleLogger := slog.New(consoleHandler)
+
+	var logFile *rotatingFile
+	if opts.LogFile != "" {
+		if err := opts.Validate(); err != nil {
+			consoleLogger.Error("Invalid log file options (logging to console only)", "error", err)
+		} else if rf, err := newRotatingFile(opts.LogFile, int64(opts.LogFileSize)*bytesPerMiB, opts.LogFileKeep); err != nil {
+			consoleLogger.Error("Failed to open log file (logging to console only)", "path", opts.LogFile, "error", err)
+		} else {
+			logFile = rf
+			handlers = append(handlers, newFileHandler(rf, opts))
+		}
+	}
+
 	var seqHandler *slogseq.SeqHandler
 	if opts.SeqURL != "" {
-		consoleLogger := slog.New(consoleHandler)
-
 		attrs := []slog.Attr{
 			slog.String("service", "par2cron"),
 		}
@@ -72,29 +149,117 @@ func NewLogger(opts Options) *Logger {
 		if err := seqHandler.Ping(); err != nil {
 			consoleLogger.Error("Failed to connect to Seq server", "error", err)
 		}
-		logger = slog.New(&fanoutHandler{
-			handlers: []slog.Handler{consoleHandler, seqHandler},
-		})
-	} else {
-		logger = slog.New(consoleHandler)
+		handlers = append(handlers, seqHandler)
 	}
 
+	var handler slog.Handler = consoleHandler
+	if len(handlers) > 1 {
+		handler = &fanoutHandler{handlers: handlers}
+	}
+	if len(opts.PathPrefixMap.Value) > 0 {
+		handler = &pathMapHandler{handler: handler, pathMap: &opts.PathPrefixMap}
+	}
+	warnings := &atomic.Int64{}
+	handler = &warnCountHandler{handler: handler, count: warnings}
+	logger = slog.New(handler)
+
 	return &Logger{
 		Logger:     logger,
 		Options:    opts,
 		seqHandler: seqHandler,
+		logFile:    logFile,
+		progress:   progress,
+		warnings:   warnings,
 	}
 }
 
+// newFileHandler returns a handler in the same format as the console,
+// with the colors removed from the text format (as not needed in files).
+func newFileHandler(w io.Writer, opts Options) slog.Handler {
+	if opts.FlattenLogs {
+		return newFlatHandler(w, opts.LogLevel.Value)
+	}
+	if opts.WantJSON {
+		return slog.NewJSONHandler(w, &slog.HandlerOptions{
+			Level: opts.LogLevel.Value,
+		})
+	}
+
+	return tint.NewHandler(w, &tint.Options{
+		Level:      opts.LogLevel.Value,
+		TimeFormat: time.DateTime,
+		NoColor:    true,
+	})
+}
+
 func (l *Logger) With(args ...any) *Logger {
 	return &Logger{
-		Logger:  l.Logger.With(args...),
-		Options: l.Options,
+		Logger:   l.Logger.With(args...),
+		Options:  l.Options,
+		progress: l.progress,
+		warnings: l.warnings,
 	}
 }
 
+// Warnings returns the count of records of warning level (or above) logged
+// so far, including those below the minimum level of emitted logs.
+func (l *Logger) Warnings() int64 {
+	if l.warnings == nil {
+		return 0
+	}
+
+	return l.warnings.Load()
+}
+
+// MapPath returns the path as it should be displayed to the user, that is
+// with the path prefix map applied (for results which are not logs).
+func (l *Logger) MapPath(path string) string {
+	return l.Options.PathPrefixMap.Map(path)
+}
+
+// MapPaths returns a copy of the paths with the path prefix map applied.
+func (l *Logger) MapPaths(paths []string) []string {
+	mapped := make([]string, len(paths))
+	for i, path := range paths {
+		mapped[i] = l.MapPath(path)
+	}
+
+	return mapped
+}
+
 func (l *Logger) Close() {
 	if l.seqHandler != nil {
 		_ = l.seqHandler.Close()
 	}
+	if l.logFile != nil {
+		_ = l.logFile.Close()
+	}
+}
+
+// wantColor returns if the text output to w should be colored, where "auto"
+// (or no setting) colors only terminals and respects the NO_COLOR convention
+// (https://no-color.org), which "always" overrides.
+func wantColor(color string, w io.Writer, noColor string) bool {
+	switch color {
+	case schema.ColorAlways:
+		return true
+	case schema.ColorNever:
+		return false
+	default:
+		return noColor == "" && isTerminal(w)
+	}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
 }
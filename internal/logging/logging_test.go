@@ -2,8 +2,12 @@ package logging
 
 import (
 	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/desertwitch/par2cron/internal/schema"
 	"github.com/desertwitch/par2cron/internal/testutil"
 	"github.com/stretchr/testify/require"
 )
@@ -19,7 +23,7 @@ func Test_NewLogger_WantTint_Success(t *testing.T) {
 	_ = ls.LogLevel.Set("info")
 
 	logger := NewLogger(ls)
-	_, ok := logger.Handler().(*slog.JSONHandler)
+	_, ok := logger.Handler().(*warnCountHandler).handler.(*slog.JSONHandler)
 
 	require.False(t, ok)
 	require.NotNil(t, logger)
@@ -36,7 +40,7 @@ func Test_NewLogger_WantJSON_Success(t *testing.T) {
 	_ = ls.LogLevel.Set("info")
 
 	logger := NewLogger(ls)
-	_, ok := logger.Handler().(*slog.JSONHandler)
+	_, ok := logger.Handler().(*warnCountHandler).handler.(*slog.JSONHandler)
 
 	require.True(t, ok)
 	require.NotNil(t, logger)
@@ -120,7 +124,7 @@ func Test_NewLogger_WithSeqURL_ReturnsFanoutHandler(t *testing.T) {
 	require.NotNil(t, logger)
 	require.NotNil(t, logger.seqHandler)
 
-	_, ok := logger.Handler().(*fanoutHandler)
+	_, ok := logger.Handler().(*warnCountHandler).handler.(*fanoutHandler)
 	require.True(t, ok)
 	logger.Close()
 }
@@ -139,7 +143,7 @@ func Test_NewLogger_WithSeqURL_NoKey_ReturnsFanoutHandler(t *testing.T) {
 	require.NotNil(t, logger)
 	require.NotNil(t, logger.seqHandler)
 
-	_, ok := logger.Handler().(*fanoutHandler)
+	_, ok := logger.Handler().(*warnCountHandler).handler.(*fanoutHandler)
 	require.True(t, ok)
 	logger.Close()
 }
@@ -160,3 +164,147 @@ func Test_Logger_Close_NoSeq_Success(t *testing.T) {
 		logger.Close()
 	})
 }
+
+// Expectation: NewLogger with LogFile should additionally write uncolored logs to the file.
+func Test_NewLogger_WithLogFile_Success(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "par2cron.log")
+	buf := &testutil.SafeBuffer{}
+
+	ls := Options{
+		Logout:      buf,
+		LogFile:     path,
+		LogFileSize: DefaultLogFileSize,
+		LogFileKeep: DefaultLogFileKeep,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	logger := NewLogger(ls)
+	require.NotNil(t, logger.logFile)
+
+	_, ok := logger.Handler().(*warnCountHandler).handler.(*fanoutHandler)
+	require.True(t, ok)
+
+	logger.Info("test message", "key", "value")
+	logger.Close()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "test message")
+	require.Contains(t, string(data), "key=value")
+	require.NotContains(t, string(data), "\x1b[")
+	require.Contains(t, buf.String(), "test message")
+}
+
+// Expectation: NewLogger with LogFile and JSON should write JSON logs to the file.
+func Test_NewLogger_WithLogFile_JSON_Success(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "par2cron.log")
+
+	ls := Options{
+		Logout:      &testutil.SafeBuffer{},
+		LogFile:     path,
+		LogFileSize: DefaultLogFileSize,
+		WantJSON:    true,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	logger := NewLogger(ls)
+	logger.Info("test message")
+	logger.Close()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"msg":"test message"`)
+}
+
+// Expectation: NewLogger should fall back to console only when the log file cannot be opened.
+func Test_NewLogger_WithLogFile_OpenFails_Success(t *testing.T) {
+	t.Parallel()
+
+	buf := &testutil.SafeBuffer{}
+
+	ls := Options{
+		Logout:      buf,
+		LogFile:     filepath.Join(t.TempDir(), "missing", "par2cron.log"),
+		LogFileSize: DefaultLogFileSize,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	logger := NewLogger(ls)
+	require.Nil(t, logger.logFile)
+	require.Contains(t, buf.String(), "Failed to open log file")
+}
+
+// Expectation: Invalid log file options should be rejected.
+func Test_Options_Validate_Error(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Options{}.Validate())
+	require.NoError(t, Options{LogFile: "/tmp/par2cron.log", LogFileSize: 1}.Validate())
+	require.ErrorContains(t, Options{LogFile: "/tmp/par2cron.log"}.Validate(), "log-file-size")
+	require.ErrorContains(t, Options{LogFile: "/tmp/par2cron.log", LogFileSize: 1, LogFileKeep: -1}.Validate(), "log-file-keep")
+}
+
+// Expectation: Colors should follow the setting, with auto coloring only terminals without NO_COLOR.
+func Test_wantColor_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		color   string
+		noColor string
+		expect  bool
+	}{
+		{"always", schema.ColorAlways, "", true},
+		{"always overrides NO_COLOR", schema.ColorAlways, "1", true},
+		{"never", schema.ColorNever, "", false},
+		{"auto without terminal", schema.ColorAuto, "", false},
+		{"unset without terminal", "", "", false},
+		{"auto with NO_COLOR", schema.ColorAuto, "1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expect, wantColor(tt.color, &testutil.SafeBuffer{}, tt.noColor))
+		})
+	}
+}
+
+// Expectation: Only character devices should be detected as terminals.
+func Test_isTerminal_Success(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.CreateTemp(t.TempDir(), "log")
+	require.NoError(t, err)
+	defer f.Close()
+
+	require.False(t, isTerminal(f))
+	require.False(t, isTerminal(&testutil.SafeBuffer{}))
+}
+
+// Expectation: Text logs should contain ANSI codes only when colors are enabled.
+func Test_NewLogger_Color_Success(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		color  string
+		expect bool
+	}{
+		{schema.ColorAlways, true},
+		{schema.ColorNever, false},
+		{schema.ColorAuto, false},
+	} {
+		buf := &testutil.SafeBuffer{}
+		ls := Options{Logout: buf}
+		_ = ls.LogLevel.Set("info")
+		_ = ls.Color.Set(tc.color)
+
+		NewLogger(ls).Error("test message")
+
+		require.Equal(t, tc.expect, strings.Contains(buf.String(), "\x1b["), tc.color)
+	}
+}
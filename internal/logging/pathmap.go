@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/desertwitch/par2cron/internal/flags"
+)
+
+var _ slog.Handler = (*pathMapHandler)(nil)
+
+// pathMapHandler rewrites all absolute path attributes of the records through
+// the path prefix map, before passing them on to the wrapped handler. It only
+// changes what is logged, the paths used by the program are left unchanged.
+type pathMapHandler struct {
+	handler slog.Handler
+	pathMap *flags.PathPrefixMap
+}
+
+func (h *pathMapHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *pathMapHandler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(h.mapAttr(a))
+
+		return true
+	})
+
+	return h.handler.Handle(ctx, nr) //nolint:wrapcheck
+}
+
+func (h *pathMapHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	mapped := make([]slog.Attr, len(attrs))
+
+	for i, a := range attrs {
+		mapped[i] = h.mapAttr(a)
+	}
+
+	return &pathMapHandler{handler: h.handler.WithAttrs(mapped), pathMap: h.pathMap}
+}
+
+func (h *pathMapHandler) WithGroup(name string) slog.Handler {
+	return &pathMapHandler{handler: h.handler.WithGroup(name), pathMap: h.pathMap}
+}
+
+func (h *pathMapHandler) mapAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+
+	switch v.Kind() { //nolint:exhaustive
+	case slog.KindString:
+		if s := v.String(); filepath.IsAbs(s) {
+			return slog.String(a.Key, h.pathMap.Map(s))
+		}
+
+	case slog.KindGroup:
+		group := v.Group()
+		mapped := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			mapped[i] = h.mapAttr(ga)
+		}
+
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(mapped...)}
+	}
+
+	return a
+}
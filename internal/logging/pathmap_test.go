@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: Absolute path attributes should be mapped, including those added with With and in groups.
+func Test_NewLogger_PathPrefixMap_Success(t *testing.T) {
+	t.Parallel()
+
+	var buf testutil.SafeBuffer
+	ls := Options{
+		Logout:   &buf,
+		WantJSON: true,
+	}
+	_ = ls.LogLevel.Set("info")
+	require.NoError(t, ls.PathPrefixMap.Set("/data=/mnt/user/data"))
+
+	logger := NewLogger(ls)
+	_, ok := logger.Handler().(*warnCountHandler).handler.(*pathMapHandler)
+	require.True(t, ok)
+
+	logger.With("dir", "/data/sub").Info("Job done", "path", "/data/a.par2", "op", "verify",
+		slog.Group("job", slog.String("path", "/data/b.par2")))
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+	require.Equal(t, "/mnt/user/data/sub", entry["dir"])
+	require.Equal(t, "/mnt/user/data/a.par2", entry["path"])
+	require.Equal(t, "verify", entry["op"])
+	require.Equal(t, map[string]any{"path": "/mnt/user/data/b.par2"}, entry["job"])
+}
+
+// Expectation: Without mappings, the handler should not be wrapped.
+func Test_NewLogger_PathPrefixMap_Empty_Success(t *testing.T) {
+	t.Parallel()
+
+	ls := Options{
+		Logout:   &testutil.SafeBuffer{},
+		WantJSON: true,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	logger := NewLogger(ls)
+	_, ok := logger.Handler().(*warnCountHandler).handler.(*pathMapHandler)
+
+	require.False(t, ok)
+}
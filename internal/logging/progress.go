@@ -0,0 +1,198 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	// clearLine returns the cursor to the start of the line and erases it.
+	clearLine = "\r\x1b[K"
+
+	// defaultProgressWidth is the width of the status line when the width
+	// of the terminal is not known (from the COLUMNS environment variable).
+	defaultProgressWidth = 80
+)
+
+// progressLine keeps a single status line at the bottom of a terminal, below
+// all other output written through it, which it clears before and redraws
+// after each write (as that output would otherwise corrupt the status line).
+type progressLine struct {
+	mu     sync.Mutex
+	term   io.Writer
+	status string
+	width  int
+
+	// partial is if the last write did not end with a newline, so that
+	// the status line is only redrawn once the output line is complete.
+	partial bool
+}
+
+func newProgressLine(term io.Writer) *progressLine {
+	width := defaultProgressWidth
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		width = cols
+	}
+
+	return &progressLine{term: term, width: width}
+}
+
+// writer returns a writer to w (which shares the terminal of the status line),
+// that keeps the status line below all of its output.
+func (p *progressLine) writer(w io.Writer) io.Writer {
+	return &progressWriter{line: p, w: w}
+}
+
+func (p *progressLine) write(w io.Writer, b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.status != "" && !p.partial {
+		_, _ = io.WriteString(p.term, clearLine)
+	}
+
+	n, err := w.Write(b)
+
+	p.partial = len(b) > 0 && !bytes.HasSuffix(b, []byte("\n"))
+	if p.status != "" && !p.partial {
+		_, _ = io.WriteString(p.term, p.status)
+	}
+
+	return n, err //nolint:wrapcheck
+}
+
+// set replaces the status line, where an empty status removes it.
+func (p *progressLine) set(status string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status = truncateStatus(status, p.width-1)
+	if status == p.status {
+		return
+	}
+	p.status = status
+
+	if !p.partial {
+		_, _ = io.WriteString(p.term, clearLine+p.status)
+	}
+}
+
+type progressWriter struct {
+	line *progressLine
+	w    io.Writer
+}
+
+func (pw *progressWriter) Write(b []byte) (int, error) {
+	return pw.line.write(pw.w, b)
+}
+
+// truncateStatus shortens the status to at most width characters, removing
+// from its middle (so that both the counts and the end of a path are kept).
+func truncateStatus(status string, width int) string {
+	const ellipsis = "..."
+
+	if width <= len(ellipsis) || utf8.RuneCountInString(status) <= width {
+		return status
+	}
+
+	runes := []rune(status)
+	head := (width - len(ellipsis)) / 2 //nolint:mnd
+	tail := width - len(ellipsis) - head
+
+	return string(runes[:head]) + ellipsis + string(runes[len(runes)-tail:])
+}
+
+// Progress renders the advancement of a run's jobs on a single status line
+// (such as "[12/400] verifying /data/set.par2") below the console logs. It
+// is nil when the status line is not enabled, with all methods then no-ops.
+type Progress struct {
+	mu    sync.Mutex
+	line  *progressLine
+	log   *Logger
+	verb  string
+	total int
+	done  int
+	path  string
+}
+
+// NewProgress returns a [Progress] for a run of total jobs, which are
+// described with verb (such as "verifying"), or nil if not enabled.
+func (l *Logger) NewProgress(verb string, total int) *Progress {
+	if l.progress == nil || total <= 0 {
+		return nil
+	}
+
+	p := &Progress{
+		line:  l.progress,
+		log:   l,
+		verb:  verb,
+		total: total,
+	}
+	p.render()
+
+	return p
+}
+
+// Started shows the path as the one of the most recently started job.
+func (p *Progress) Started(path string) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.path = path
+	p.render()
+}
+
+// Finished counts a job as finished, regardless of its outcome.
+func (p *Progress) Finished() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done++
+	p.render()
+}
+
+// Step shows the path as the one of the running job, after done jobs have
+// finished (for runs which process their jobs one after another).
+func (p *Progress) Step(done int, path string) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done = done
+	p.path = path
+	p.render()
+}
+
+// Close removes the status line, so that it does not remain after the run.
+func (p *Progress) Close() {
+	if p == nil {
+		return
+	}
+
+	p.line.set("")
+}
+
+func (p *Progress) render() {
+	status := fmt.Sprintf("[%d/%d] %s", p.done, p.total, p.verb)
+	if p.path != "" {
+		status += " " + p.log.MapPath(p.path)
+	}
+
+	p.line.set(status)
+}
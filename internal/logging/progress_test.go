@@ -0,0 +1,157 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: Output should be written above the status line, which is redrawn after it.
+func Test_progressLine_Write_Success(t *testing.T) {
+	t.Parallel()
+
+	var term testutil.SafeBuffer
+	line := &progressLine{term: &term, width: defaultProgressWidth}
+	w := line.writer(&term)
+
+	_, err := w.Write([]byte("before\n"))
+	require.NoError(t, err)
+	require.Equal(t, "before\n", term.String())
+
+	line.set("[0/2] verifying")
+	term.Reset()
+
+	n, err := w.Write([]byte("log line\n"))
+	require.NoError(t, err)
+	require.Equal(t, len("log line\n"), n)
+	require.Equal(t, clearLine+"log line\n"+"[0/2] verifying", term.String())
+}
+
+// Expectation: The status line should only be redrawn once a partial output line is complete.
+func Test_progressLine_Write_Partial_Success(t *testing.T) {
+	t.Parallel()
+
+	var term testutil.SafeBuffer
+	line := &progressLine{term: &term, width: defaultProgressWidth}
+	w := line.writer(&term)
+
+	line.set("[0/1] repairing")
+	term.Reset()
+
+	_, err := w.Write([]byte("Loading: 50%"))
+	require.NoError(t, err)
+	require.Equal(t, clearLine+"Loading: 50%", term.String())
+
+	line.set("[1/1] repairing")
+	require.Equal(t, clearLine+"Loading: 50%", term.String())
+
+	_, err = w.Write([]byte(" done\n"))
+	require.NoError(t, err)
+	require.Equal(t, clearLine+"Loading: 50% done\n[1/1] repairing", term.String())
+}
+
+// Expectation: An empty status should remove the status line.
+func Test_progressLine_Set_Clear_Success(t *testing.T) {
+	t.Parallel()
+
+	var term testutil.SafeBuffer
+	line := &progressLine{term: &term, width: defaultProgressWidth}
+
+	line.set("[0/1] creating")
+	line.set("")
+	require.Equal(t, clearLine+"[0/1] creating"+clearLine, term.String())
+
+	term.Reset()
+	_, err := line.writer(&term).Write([]byte("log line\n"))
+	require.NoError(t, err)
+	require.Equal(t, "log line\n", term.String())
+}
+
+// Expectation: A status should be shortened in its middle to fit the width.
+func Test_truncateStatus_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status string
+		width  int
+		want   string
+	}{
+		{"fits", "[1/2] verifying /data", 40, "[1/2] verifying /data"},
+		{"exact", "abcdef", 6, "abcdef"},
+		{"shortened", "[1/2] verifying /data/movies/film.par2", 20, "[1/2] ve...film.par2"},
+		{"multibyte", "äöüäöüäöüä", 7, "äö...üä"},
+		{"tiny width", "[1/2] verifying", 3, "[1/2] verifying"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := truncateStatus(tt.status, tt.width)
+			require.Equal(t, tt.want, got)
+			if tt.width > 3 {
+				require.LessOrEqual(t, len([]rune(got)), tt.width)
+			}
+		})
+	}
+}
+
+// Expectation: The progress of jobs should be rendered with counts and the (mapped) path.
+func Test_Progress_Success(t *testing.T) {
+	t.Parallel()
+
+	var term testutil.SafeBuffer
+	logger := NewLogger(Options{Logout: &term})
+	require.NoError(t, logger.Options.PathPrefixMap.Set("/data=/mnt/user"))
+	logger.progress = &progressLine{term: &term, width: defaultProgressWidth}
+
+	progress := logger.NewProgress("verifying", 2)
+	require.NotNil(t, progress)
+	require.Equal(t, "[0/2] verifying", logger.progress.status)
+
+	progress.Started("/data/a.par2")
+	require.Equal(t, "[0/2] verifying /mnt/user/a.par2", logger.progress.status)
+
+	progress.Finished()
+	require.Equal(t, "[1/2] verifying /mnt/user/a.par2", logger.progress.status)
+
+	progress.Step(1, "/data/b.par2")
+	require.Equal(t, "[1/2] verifying /mnt/user/b.par2", logger.progress.status)
+
+	progress.Close()
+	require.Empty(t, logger.progress.status)
+	require.True(t, strings.HasSuffix(term.String(), clearLine))
+}
+
+// Expectation: No progress should be rendered when not a terminal, and a nil progress should be a no-op.
+func Test_Progress_Disabled_Success(t *testing.T) {
+	t.Parallel()
+
+	var term testutil.SafeBuffer
+	logger := NewLogger(Options{Logout: &term, ProgressBar: true})
+	require.Nil(t, logger.progress)
+
+	progress := logger.NewProgress("verifying", 2)
+	require.Nil(t, progress)
+
+	progress.Started("/data/a.par2")
+	progress.Finished()
+	progress.Step(1, "/data/b.par2")
+	progress.Close()
+
+	logger.Info("message")
+	require.NotContains(t, term.String(), clearLine)
+	require.NotContains(t, term.String(), "verifying")
+}
+
+// Expectation: No progress should be rendered for JSON logs, even if enabled.
+func Test_NewLogger_ProgressBar_JSON_Success(t *testing.T) {
+	t.Parallel()
+
+	logger := NewLogger(Options{Logout: &testutil.SafeBuffer{}, ProgressBar: true, WantJSON: true})
+	require.Nil(t, logger.progress)
+	require.Nil(t, logger.NewProgress("verifying", 1))
+}
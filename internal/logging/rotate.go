@@ -0,0 +1,155 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+const (
+	logFilePerm fs.FileMode = 0o644
+	bytesPerMiB int64       = 1024 * 1024
+)
+
+var _ io.WriteCloser = (*rotatingFile)(nil)
+
+// rotatingFile is an [io.WriteCloser] that appends to a log file and rotates
+// it once a write would grow it beyond maxSize bytes. Rotated files are kept
+// as path.1 (newest) up to path.N (oldest), where N is the keep count; with a
+// keep count of zero, the log file is simply truncated on rotation.
+type rotatingFile struct {
+	mu sync.Mutex
+
+	path    string
+	maxSize int64
+	keep    int
+
+	file *os.File
+	size int64
+}
+
+func newRotatingFile(path string, maxSize int64, keep int) (*rotatingFile, error) {
+	rf := &rotatingFile{
+		path:    path,
+		maxSize: maxSize,
+		keep:    keep,
+	}
+
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+
+	return rf, nil
+}
+
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	// A single record larger than maxSize is still written as a whole, but
+	// into a fresh file (so that a log file never holds a partial record).
+	if rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize {
+		if err := rf.rotate(); err != nil && rf.file == nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	if err != nil {
+		return n, fmt.Errorf("failed to write: %w", err)
+	}
+
+	return n, nil
+}
+
+func (rf *rotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		return nil
+	}
+
+	err := rf.file.Close()
+	rf.file = nil
+	if err != nil {
+		return fmt.Errorf("failed to close: %w", err)
+	}
+
+	return nil
+}
+
+func (rf *rotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFilePerm)
+	if err != nil {
+		return fmt.Errorf("failed to open: %w", err)
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("failed to stat: %w", err)
+	}
+
+	rf.file = f
+	rf.size = fi.Size()
+
+	return nil
+}
+
+// rotate closes the log file, shifts the rotated files and re-opens a fresh
+// log file. The log file is re-opened also when shifting fails, so logging
+// continues (into the not rotated log file) rather than being lost entirely.
+func (rf *rotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return fmt.Errorf("failed to close: %w", err)
+	}
+	rf.file = nil
+
+	shiftErr := rf.shift()
+
+	if err := rf.open(); err != nil {
+		return err
+	}
+
+	return shiftErr
+}
+
+func (rf *rotatingFile) shift() error {
+	if rf.keep == 0 {
+		if err := os.Remove(rf.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := os.Remove(rf.backupPath(rf.keep)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove oldest: %w", err)
+	}
+
+	for i := rf.keep - 1; i >= 1; i-- {
+		if err := os.Rename(rf.backupPath(i), rf.backupPath(i+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to shift: %w", err)
+		}
+	}
+
+	if err := os.Rename(rf.path, rf.backupPath(1)); err != nil {
+		return fmt.Errorf("failed to rename: %w", err)
+	}
+
+	return nil
+}
+
+func (rf *rotatingFile) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", rf.path, n)
+}
@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: Writes up to exactly the maximum size should not rotate, one byte more should.
+func Test_rotatingFile_Write_SizeBoundary_Success(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "par2cron.log")
+
+	rf, err := newRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+	defer rf.Close()
+
+	_, err = rf.Write([]byte("12345"))
+	require.NoError(t, err)
+	_, err = rf.Write([]byte("67890"))
+	require.NoError(t, err)
+
+	require.NoFileExists(t, path+".1")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "1234567890", string(data))
+
+	_, err = rf.Write([]byte("x"))
+	require.NoError(t, err)
+
+	rotated, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	require.Equal(t, "1234567890", string(rotated))
+
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "x", string(data))
+}
+
+// Expectation: Only the configured amount of rotated files should be kept, newest first.
+func Test_rotatingFile_Write_KeepCount_Success(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "par2cron.log")
+
+	rf, err := newRotatingFile(path, 4, 2)
+	require.NoError(t, err)
+	defer rf.Close()
+
+	for _, rec := range []string{"aaaa", "bbbb", "cccc", "dddd"} {
+		_, err = rf.Write([]byte(rec))
+		require.NoError(t, err)
+	}
+
+	for file, want := range map[string]string{path: "dddd", path + ".1": "cccc", path + ".2": "bbbb"} {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		require.Equal(t, want, string(data))
+	}
+	require.NoFileExists(t, path+".3")
+}
+
+// Expectation: With a keep count of zero, the log file should be truncated on rotation.
+func Test_rotatingFile_Write_KeepZero_Success(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "par2cron.log")
+
+	rf, err := newRotatingFile(path, 4, 0)
+	require.NoError(t, err)
+	defer rf.Close()
+
+	_, err = rf.Write([]byte("aaaa"))
+	require.NoError(t, err)
+	_, err = rf.Write([]byte("bb"))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "bb", string(data))
+	require.NoFileExists(t, path+".1")
+}
+
+// Expectation: A record larger than the maximum size should be written as a whole into a fresh file.
+func Test_rotatingFile_Write_OversizedRecord_Success(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "par2cron.log")
+
+	rf, err := newRotatingFile(path, 4, 1)
+	require.NoError(t, err)
+	defer rf.Close()
+
+	_, err = rf.Write([]byte("aa"))
+	require.NoError(t, err)
+	_, err = rf.Write([]byte(strings.Repeat("b", 10)))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, strings.Repeat("b", 10), string(data))
+}
+
+// Expectation: An existing log file should be appended to, counting towards the maximum size.
+func Test_rotatingFile_ExistingFile_Success(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "par2cron.log")
+	require.NoError(t, os.WriteFile(path, []byte("12345678"), 0o644))
+
+	rf, err := newRotatingFile(path, 10, 1)
+	require.NoError(t, err)
+	defer rf.Close()
+
+	_, err = rf.Write([]byte("90"))
+	require.NoError(t, err)
+	require.NoFileExists(t, path+".1")
+
+	_, err = rf.Write([]byte("x"))
+	require.NoError(t, err)
+
+	rotated, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	require.Equal(t, "1234567890", string(rotated))
+}
+
+// Expectation: Writes after closing should fail with an error.
+func Test_rotatingFile_WriteAfterClose_Error(t *testing.T) {
+	t.Parallel()
+
+	rf, err := newRotatingFile(filepath.Join(t.TempDir(), "par2cron.log"), 10, 1)
+	require.NoError(t, err)
+	require.NoError(t, rf.Close())
+
+	_, err = rf.Write([]byte("x"))
+	require.ErrorIs(t, err, os.ErrClosed)
+}
+
+// Expectation: An unopenable log file path should return an error.
+func Test_newRotatingFile_Open_Error(t *testing.T) {
+	t.Parallel()
+
+	_, err := newRotatingFile(filepath.Join(t.TempDir(), "missing", "par2cron.log"), 10, 1)
+	require.ErrorContains(t, err, "failed to open")
+}
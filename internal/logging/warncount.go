@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+var _ slog.Handler = (*warnCountHandler)(nil)
+
+// warnCountHandler counts all records of warning level (or above), before
+// passing them on to the wrapped handler. The records are counted regardless
+// of the minimum level of the wrapped handler, so also when not emitted.
+type warnCountHandler struct {
+	handler slog.Handler
+	count   *atomic.Int64
+}
+
+func (h *warnCountHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= slog.LevelWarn || h.handler.Enabled(ctx, level)
+}
+
+func (h *warnCountHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelWarn {
+		h.count.Add(1)
+	}
+
+	if !h.handler.Enabled(ctx, r.Level) {
+		return nil
+	}
+
+	return h.handler.Handle(ctx, r) //nolint:wrapcheck
+}
+
+func (h *warnCountHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &warnCountHandler{handler: h.handler.WithAttrs(attrs), count: h.count}
+}
+
+func (h *warnCountHandler) WithGroup(name string) slog.Handler {
+	return &warnCountHandler{handler: h.handler.WithGroup(name), count: h.count}
+}
@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: Records of warning level (or above) should be counted across derived loggers.
+func Test_Logger_Warnings_Success(t *testing.T) {
+	t.Parallel()
+
+	buf := &testutil.SafeBuffer{}
+	ls := Options{
+		Logout:   buf,
+		WantJSON: true,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	logger := NewLogger(ls)
+	child := logger.With("op", "verify").WithGroup("job")
+
+	logger.Debug("debug message")
+	logger.Info("info message")
+	require.Zero(t, logger.Warnings())
+
+	logger.Warn("warn message")
+	child.Error("error message")
+	require.EqualValues(t, 2, logger.Warnings())
+	require.EqualValues(t, 2, logger.With("key", "value").Warnings())
+
+	require.Contains(t, buf.String(), "warn message")
+	require.Contains(t, buf.String(), "error message")
+	require.NotContains(t, buf.String(), "debug message")
+}
+
+// Expectation: Warnings should be counted even if below the minimum level of emitted logs.
+func Test_Logger_Warnings_BelowLevel_Success(t *testing.T) {
+	t.Parallel()
+
+	buf := &testutil.SafeBuffer{}
+	ls := Options{
+		Logout:   buf,
+		WantJSON: true,
+	}
+	_ = ls.LogLevel.Set("error")
+
+	logger := NewLogger(ls)
+	require.True(t, logger.Enabled(t.Context(), slog.LevelWarn))
+	require.False(t, logger.Enabled(t.Context(), slog.LevelInfo))
+
+	logger.Warn("warn message")
+	require.EqualValues(t, 1, logger.Warnings())
+	require.Empty(t, buf.String())
+}
+
+// Expectation: A logger without a warning count should report no warnings.
+func Test_Logger_Warnings_Nil_Success(t *testing.T) {
+	t.Parallel()
+
+	logger := &Logger{Logger: slog.New(slog.DiscardHandler)}
+	logger.Warn("warn message")
+
+	require.Zero(t, logger.Warnings())
+}
@@ -0,0 +1,124 @@
+package par2
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	par2Extension = ".par2"
+	volumePrefix  = ".vol"
+)
+
+// VolumeFiles returns the paths of all recovery volumes belonging to the PAR2
+// index at indexPath, in the order of their first recovery block. The index
+// itself is neither returned, nor does it need to exist.
+//
+// Volumes are matched as <root>.vol<start><sep><count>.par2 (case-insensitive),
+// where <sep> is '+' or '-' and both numbers may have any width or padding.
+// This covers the default, uniform (-u) and limited (-l) layouts of par2cmdline,
+// where only the numbering (and not the naming scheme) of the volumes differs.
+func VolumeFiles(fsys afero.Fs, indexPath string) ([]string, error) {
+	entries, err := afero.ReadDir(fsys, filepath.Dir(indexPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	type volume struct {
+		path  string
+		start string
+	}
+
+	indexName := filepath.Base(indexPath)
+
+	volumes := []volume{}
+	for _, entry := range entries {
+		if !entry.Mode().IsRegular() {
+			continue
+		}
+
+		start, ok := volumeStart(indexName, entry.Name())
+		if !ok {
+			continue
+		}
+
+		volumes = append(volumes, volume{
+			path:  filepath.Join(filepath.Dir(indexPath), entry.Name()),
+			start: start,
+		})
+	}
+
+	slices.SortFunc(volumes, func(a, b volume) int {
+		return cmp.Or(
+			cmp.Compare(len(a.start), len(b.start)),
+			strings.Compare(a.start, b.start),
+			strings.Compare(a.path, b.path),
+		)
+	})
+
+	paths := make([]string, 0, len(volumes))
+	for _, v := range volumes {
+		paths = append(paths, v.path)
+	}
+
+	return paths, nil
+}
+
+// IsVolumeOf reports whether name is the filename of a recovery volume of the
+// PAR2 index indexName, comparing both as case-insensitive basenames.
+func IsVolumeOf(indexName, name string) bool {
+	_, ok := volumeStart(indexName, name)
+
+	return ok
+}
+
+// volumeStart returns the number of the first recovery block of a volume (as
+// a string without leading zeros), if name is a volume of the index indexName.
+func volumeStart(indexName, name string) (string, bool) {
+	indexName = strings.ToLower(filepath.Base(indexName))
+	name = strings.ToLower(filepath.Base(name))
+
+	root, ok := strings.CutSuffix(indexName, par2Extension)
+	if !ok || root == "" || root == "." || root == ".." {
+		return "", false
+	}
+
+	tail, ok := strings.CutPrefix(name, root+volumePrefix) // <start><sep><count>.par2
+	if !ok {
+		return "", false
+	}
+	tail, ok = strings.CutSuffix(tail, par2Extension) // <start><sep><count>
+	if !ok {
+		return "", false
+	}
+
+	pos := strings.IndexAny(tail, "+-")
+	if pos <= 0 || !isDigits(tail[:pos]) || !isDigits(tail[pos+1:]) {
+		return "", false
+	}
+
+	start := strings.TrimLeft(tail[:pos], "0")
+	if start == "" {
+		start = "0"
+	}
+
+	return start, true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,169 @@
+package par2
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: The volumes of the default (and uniform) layout should be returned in order.
+func Test_VolumeFiles_Uniform_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	for _, name := range []string{
+		"files.par2",
+		"files.vol10+10.par2",
+		"files.vol00+10.par2",
+		"files.vol20+10.par2",
+	} {
+		require.NoError(t, afero.WriteFile(fs, "/data/"+name, []byte("par2"), 0o644))
+	}
+
+	volumes, err := VolumeFiles(fs, "/data/files.par2")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"/data/files.vol00+10.par2",
+		"/data/files.vol10+10.par2",
+		"/data/files.vol20+10.par2",
+	}, volumes)
+}
+
+// Expectation: The volumes of the limited layout should be returned in numeric (not lexical) order.
+func Test_VolumeFiles_Limited_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	for _, name := range []string{
+		"files.par2",
+		"files.vol0+1.par2",
+		"files.vol1+2.par2",
+		"files.vol3+4.par2",
+		"files.vol7+8.par2",
+		"files.vol15+16.par2",
+		"files.vol31+9.PAR2",
+	} {
+		require.NoError(t, afero.WriteFile(fs, "/data/"+name, []byte("par2"), 0o644))
+	}
+
+	volumes, err := VolumeFiles(fs, "/data/files.par2")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"/data/files.vol0+1.par2",
+		"/data/files.vol1+2.par2",
+		"/data/files.vol3+4.par2",
+		"/data/files.vol7+8.par2",
+		"/data/files.vol15+16.par2",
+		"/data/files.vol31+9.PAR2",
+	}, volumes)
+}
+
+// Expectation: Volumes of other sets, other files and directories should not be returned.
+func Test_VolumeFiles_Unrelated_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/files.vol02+02.par2", 0o755))
+	for _, name := range []string{
+		"files.par2",
+		"files.vol00+02.par2",
+		"files.backup.par2",
+		"files.backup.vol00+02.par2",
+		"other.vol00+02.par2",
+		"files.vol00+02.txt",
+		"files.volcano.par2",
+	} {
+		require.NoError(t, afero.WriteFile(fs, "/data/"+name, []byte("par2"), 0o644))
+	}
+
+	volumes, err := VolumeFiles(fs, "/data/files.par2")
+	require.NoError(t, err)
+	require.Equal(t, []string{"/data/files.vol00+02.par2"}, volumes)
+}
+
+// Expectation: No volumes should be returned for an index without any, also when it does not exist.
+func Test_VolumeFiles_NoVolumes_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	volumes, err := VolumeFiles(fs, "/data/files.par2")
+	require.NoError(t, err)
+	require.Empty(t, volumes)
+}
+
+// Expectation: An error should be returned when the directory cannot be read.
+func Test_VolumeFiles_MissingDirectory_Error(t *testing.T) {
+	t.Parallel()
+
+	_, err := VolumeFiles(afero.NewMemMapFs(), "/missing/files.par2")
+	require.ErrorContains(t, err, "failed to read directory")
+}
+
+// Expectation: The function should meet the table's expectations.
+func Test_IsVolumeOf_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		index  string
+		fname  string
+		expect bool
+	}{
+		// valid plus-separated volumes
+		{"basic plus", "test.par2", "test.vol00+01.par2", true},
+		{"large numbers plus", "test.par2", "test.vol999+100.par2", true},
+		{"single digits plus", "test.par2", "test.vol0+1.par2", true},
+		{"leading zeros plus", "test.par2", "test.vol007+010.par2", true},
+		{"dotted root plus", "test.backup.par2", "test.backup.vol00+01.par2", true},
+
+		// valid minus-separated volumes
+		{"basic minus", "test.par2", "test.vol00-01.par2", true},
+		{"large numbers minus", "test.par2", "test.vol999-100.par2", true},
+		{"dotted root minus", "test.backup.par2", "test.backup.vol05-10.par2", true},
+
+		// case-insensitive basenames
+		{"uppercase volume", "test.par2", "TEST.VOL00+01.PAR2", true},
+		{"uppercase index", "TEST.PAR2", "test.vol00+01.par2", true},
+		{"with directories", "/data/test.par2", "/other/test.vol00+01.par2", true},
+
+		// mixed separators
+		{"plus then minus", "test.par2", "test.vol00+01-02.par2", false},
+		{"minus then plus", "test.par2", "test.vol00-01+02.par2", false},
+
+		// wrong root
+		{"different root", "test.par2", "other.vol00+01.par2", false},
+		{"longer root", "test.par2", "testing.vol00+01.par2", false},
+		{"shorter root", "test.par2", "tes.vol00+01.par2", false},
+
+		// malformed mid section
+		{"no separator", "test.par2", "test.vol01.par2", false},
+		{"double separator", "test.par2", "test.vol01+02+03.par2", false},
+		{"leading separator", "test.par2", "test.vol+01.par2", false},
+		{"trailing separator", "test.par2", "test.vol01+.par2", false},
+		{"non-digit lhs", "test.par2", "test.volab+01.par2", false},
+		{"non-digit rhs", "test.par2", "test.vol01+ab.par2", false},
+		{"empty mid", "test.par2", "test.vol.par2", false},
+
+		// wrong extension
+		{"txt extension", "test.par2", "test.vol00+01.txt", false},
+		{"no extension", "test.par2", "test.vol00+01", false},
+		{"index not par2", "test.txt", "test.vol00+01.par2", false},
+
+		// not a volume at all
+		{"index file", "test.par2", "test.par2", false},
+		{"no vol prefix", "test.par2", "test.00+01.par2", false},
+		{"empty root", ".par2", ".vol00+01.par2", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expect, IsVolumeOf(tt.index, tt.fname))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package repair
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// confirmRepair lists the PAR2 sets about to be repaired (up to --limit) and
+// reads the operator's confirmation from the reader, where only an answer of
+// "y" or "yes" confirms, and anything else (including no answer) declines.
+func (prog *Service) confirmRepair(metas []*JobMeta, opts Options, r io.Reader) (bool, error) {
+	if opts.Limit > 0 && len(metas) > opts.Limit {
+		metas = metas[:opts.Limit]
+	}
+
+	w := prog.log.Options.Stderr
+
+	fmt.Fprintf(w, "The following %d PAR2 sets are about to be repaired:\n", len(metas))
+	for _, meta := range metas {
+		fmt.Fprintf(w, "  %s\n", prog.log.MapPath(meta.Par2Path))
+	}
+	fmt.Fprintf(w, "Proceed with the repair? [y/N]: ")
+
+	answer, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,139 @@
+package repair
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// newConfirmFs returns a filesystem with two PAR2 sets flagged for repair.
+func newConfirmFs(t *testing.T) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	for _, name := range []string{"test", "test2"} {
+		require.NoError(t, afero.WriteFile(fs, "/data/"+name+schema.Par2Extension, []byte("par2data"), 0o644))
+
+		hash, err := util.HashFile(fs, "/data/"+name+schema.Par2Extension)
+		require.NoError(t, err)
+
+		mf := schema.NewManifest(name + schema.Par2Extension)
+		mf.SHA256 = hash
+		mf.Verification = &schema.VerificationManifest{
+			RepairNeeded:   true,
+			RepairPossible: true,
+		}
+		mfData, err := json.Marshal(mf)
+		require.NoError(t, err)
+		require.NoError(t, afero.WriteFile(fs, "/data/"+name+schema.Par2Extension+schema.ManifestExtension, mfData, 0o644))
+	}
+
+	return fs
+}
+
+// Expectation: The candidate sets should be listed and repaired after confirming with "yes".
+func Test_Service_Repair_Confirm_Yes_Success(t *testing.T) {
+	t.Parallel()
+
+	var logBuf, stderr testutil.SafeBuffer
+	ls := logging.Options{Logout: &logBuf, Stdout: io.Discard, Stderr: &stderr}
+	_ = ls.LogLevel.Set("info")
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+
+			return nil
+		},
+	}
+
+	prog := NewService(newConfirmFs(t), logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	args := Options{Confirm: strings.NewReader("Yes\n")}
+	_, err := prog.Repair(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, called)
+	require.Contains(t, stderr.String(), "The following 2 PAR2 sets are about to be repaired:\n")
+	require.Contains(t, stderr.String(), "  /data/test"+schema.Par2Extension+"\n")
+	require.Contains(t, stderr.String(), "  /data/test2"+schema.Par2Extension+"\n")
+	require.Contains(t, stderr.String(), "Proceed with the repair? [y/N]: ")
+	require.Equal(t, 2, strings.Count(logBuf.String(), "Job completed with success"))
+}
+
+// Expectation: The run should be aborted cleanly without repairing after declining with "no".
+func Test_Service_Repair_Confirm_No_Success(t *testing.T) {
+	t.Parallel()
+
+	for _, answer := range []string{"no\n", "\n", ""} {
+		var logBuf, stderr testutil.SafeBuffer
+		ls := logging.Options{Logout: &logBuf, Stdout: io.Discard, Stderr: &stderr}
+		_ = ls.LogLevel.Set("info")
+
+		var called int
+		runner := &testutil.MockRunner{
+			RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+				called++
+
+				return nil
+			},
+		}
+
+		prog := NewService(newConfirmFs(t), logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+		args := Options{Confirm: strings.NewReader(answer)}
+		results, err := prog.Repair(t.Context(), []string{"/data"}, args)
+		require.NoError(t, err, answer)
+
+		require.Zero(t, called, answer)
+		require.Zero(t, results.Selected, answer)
+		require.Contains(t, stderr.String(), "Proceed with the repair? [y/N]: ", answer)
+		require.Contains(t, logBuf.String(), "Repair was not confirmed (aborting the run)", answer)
+	}
+}
+
+// Expectation: Only the sets within --limit should be listed for confirmation.
+func Test_Service_confirmRepair_Limit_Success(t *testing.T) {
+	t.Parallel()
+
+	var stderr testutil.SafeBuffer
+	ls := logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: &stderr}
+	prog := NewService(afero.NewMemMapFs(), logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	metas := []*JobMeta{
+		{&schema.JobMeta{Par2Path: "/data/a.par2"}},
+		{&schema.JobMeta{Par2Path: "/data/b.par2"}},
+	}
+
+	confirmed, err := prog.confirmRepair(metas, Options{Limit: 1}, strings.NewReader("y\n"))
+	require.NoError(t, err)
+	require.True(t, confirmed)
+	require.Contains(t, stderr.String(), "The following 1 PAR2 sets are about to be repaired:\n  /data/a.par2\n")
+	require.NotContains(t, stderr.String(), "/data/b.par2")
+}
+
+// Expectation: A failure to read the confirmation should be returned as error.
+func Test_Service_confirmRepair_ReadFails_Error(t *testing.T) {
+	t.Parallel()
+
+	ls := logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}
+	prog := NewService(afero.NewMemMapFs(), logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	metas := []*JobMeta{{&schema.JobMeta{Par2Path: "/data/a.par2"}}}
+
+	confirmed, err := prog.confirmRepair(metas, Options{}, iotest.ErrReader(errors.New("read failure")))
+	require.ErrorContains(t, err, "failed to read confirmation")
+	require.False(t, confirmed)
+}
@@ -0,0 +1,64 @@
+package repair
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+)
+
+// Dereference makes the job that of a PAR2 set stored elsewhere, whose
+// manifest (with the lock file) at manifestPath is next to the protected
+// files, which par2 then resolves (and repairs) against that directory.
+func (job *Job) Dereference(manifestPath string) {
+	job.workingDir = filepath.Dir(manifestPath)
+	job.basePath = job.workingDir
+	job.manifestName = filepath.Base(manifestPath)
+	job.manifestPath = manifestPath
+	job.lockPath = job.sidecarNames.LockPath(job.sidecarNames.Par2Paths(manifestPath)[0])
+	job.dereferenced = true
+}
+
+// processDereferencedManifest returns the job of a PAR2 set stored elsewhere,
+// as recorded in the manifest at manifestPath (--dereference-manifest). Any
+// manifest that has its PAR2 set next to it (or not recording one stored
+// elsewhere) is silently skipped, as it is not for dereferencing.
+func (prog *Service) processDereferencedManifest(ctx context.Context, manifestPath string, opts Options) (*JobMeta, error) {
+	defer prog.timings.Since(util.PhaseParse, time.Now())
+
+	par2Path, mf, err := util.ReadDereferencedManifest(prog.fsys, manifestPath, opts.SidecarNames, opts.MaxManifestSize)
+	if err != nil {
+		logger := prog.repairLogger(ctx, nil, manifestPath)
+		if errors.Is(err, schema.ErrFileIsLocked) {
+			logger.Debug("Manifest is locked by another instance (will retry next run)", "reason", schema.ReasonLocked)
+			prog.filtered.Add(schema.ReasonLocked)
+
+			return nil, schema.ErrSilentSkip
+		}
+		logger.Error("Failed to read par2cron manifest (will retry next run)", "reason", schema.ReasonManifestRead, "error", err)
+
+		return nil, schema.ErrNonFatal
+	}
+	if par2Path == "" {
+		return nil, schema.ErrSilentSkip
+	}
+
+	if _, err := util.LstatIfPossible(prog.fsys, par2Path); err != nil {
+		logger := prog.repairLogger(ctx, nil, manifestPath)
+		logger.Error("Failed to find PAR2 set stored elsewhere (will retry next run)",
+			"reason", schema.ReasonDereference, "par2", par2Path, "error", err)
+
+		return nil, schema.ErrNonFatal
+	}
+
+	meta := schema.NewJobMeta(par2Path, mf, false)
+	meta.ManifestPath = manifestPath
+
+	logger := prog.repairLogger(ctx, meta, manifestPath)
+	logger.Debug("Dereferenced manifest to PAR2 set stored elsewhere")
+
+	return NewJobMeta(meta), nil
+}
@@ -0,0 +1,116 @@
+package repair
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// createDereferencedSet writes a repairable PAR2 set into the parity tree, with
+// its manifest (recording the PAR2 set as par2_location) into the data tree,
+// returning the path of the manifest.
+func createDereferencedSet(t *testing.T, fs afero.Fs) string {
+	t.Helper()
+
+	createRepairableSet(t, fs, "/parity/movies/movies"+schema.Par2Extension)
+	require.NoError(t, fs.MkdirAll("/data/movies", 0o755))
+
+	mfPath := "/data/movies/movies" + schema.Par2Extension + schema.ManifestExtension
+	require.NoError(t, fs.Rename("/parity/movies/movies"+schema.Par2Extension+schema.ManifestExtension, mfPath))
+
+	data, err := afero.ReadFile(fs, mfPath)
+	require.NoError(t, err)
+
+	var mf schema.Manifest
+	require.NoError(t, json.Unmarshal(data, &mf))
+	mf.Par2Location = "/parity/movies/movies" + schema.Par2Extension
+
+	data, err = json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, mfPath, data, 0o644))
+
+	return mfPath
+}
+
+// Expectation: A manifest without its PAR2 set should be dereferenced to the PAR2 set in the sibling
+// parity tree with the option, repairing the data as basepath and recording it into that manifest.
+func Test_Service_Repair_DereferenceManifest_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	mfPath := createDereferencedSet(t, fs)
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	var runArgs [][]string
+	var runDirs []string
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			runArgs = append(runArgs, args)
+			runDirs = append(runDirs, workingDir)
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	res, err := prog.Repair(t.Context(), []string{"/data"}, Options{DereferenceManifest: true, Par2Verify: true})
+
+	require.NoError(t, err)
+	require.Equal(t, 1, res.Success)
+	require.Equal(t, [][]string{
+		{"repair", "-B/data/movies", "--", "/parity/movies/movies" + schema.Par2Extension},
+		{"verify", "-B/data/movies", "--", "/parity/movies/movies" + schema.Par2Extension},
+	}, runArgs)
+	require.Equal(t, []string{"/data/movies", "/data/movies"}, runDirs)
+
+	data, err := afero.ReadFile(fs, mfPath)
+	require.NoError(t, err)
+
+	var mf schema.Manifest
+	require.NoError(t, json.Unmarshal(data, &mf))
+	require.NotNil(t, mf.Repair)
+	require.NotNil(t, mf.Verification)
+	require.False(t, mf.Verification.RepairNeeded)
+	require.Equal(t, "/parity/movies/movies"+schema.Par2Extension, mf.Par2Location)
+}
+
+// Expectation: A manifest of a PAR2 set stored elsewhere should not be a job without the option.
+func Test_Service_Repair_DereferenceManifest_Off_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createDereferencedSet(t, fs)
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	var calls int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			calls++
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	_, _ = prog.Repair(t.Context(), []string{"/data"}, Options{})
+
+	require.Zero(t, calls)
+}
@@ -0,0 +1,211 @@
+package repair
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/desertwitch/par2cron/internal/flags"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/desertwitch/par2cron/internal/verify"
+)
+
+// metadataModeBits are the bits of a recorded mode that are restored.
+const metadataModeBits = fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky
+
+// MetadataOptions are the options of restoring the recorded ownership and
+// permissions of the protected files (restore-metadata).
+type MetadataOptions struct {
+	DryRun          bool
+	IgnoreNames     util.IgnoreNames
+	SidecarNames    util.SidecarNames
+	MaxDepth        flags.MaxDepth
+	MaxManifestSize int64
+	PauseFile       string
+}
+
+// RestoreMetadata enumerates all PAR2 sets below the root directories and
+// restores the ownership and permissions of their protected files, as they
+// were recorded in the creation manifest (with --record-metadata). This is
+// meant for after a repair, as par2 recreates files with their content but
+// not their original ownership and permissions. Files that already have the
+// recorded metadata are left as they are, and failures are partial failures.
+// PAR2 sets with restored files count as successes, those with nothing to
+// restore as skipped.
+func (prog *Service) RestoreMetadata(ctx context.Context, rootDirs []string, opts MetadataOptions) (util.ResultTracker, error) {
+	vs := verify.NewService(prog.fsys, prog.log, prog.runner, prog.bundler, prog.cacher)
+	results := util.NewResultTracker()
+	va := verify.Options{SkipNotCreated: true, IgnoreNames: opts.IgnoreNames, SidecarNames: opts.SidecarNames, MaxDepth: opts.MaxDepth, MaxManifestSize: opts.MaxManifestSize}
+
+	if util.IsPaused(prog.fsys, opts.PauseFile) {
+		prog.log.Info("Paused by sentinel file (nothing done; will check again next run)", "pauseFile", opts.PauseFile)
+
+		return results, nil
+	}
+
+	if opts.DryRun {
+		prog.log.Info("Running in dry-run mode (metadata will not be restored)")
+	}
+
+	var restored int
+	errs := []error{}
+	for _, rootDir := range rootDirs {
+		metas, err := vs.Enumerate(ctx, rootDir, va, prog.cacher.NewCache(prog.fsys, "", rootDir))
+		if err != nil {
+			if !errors.Is(err, schema.ErrNonFatal) {
+				return results, fmt.Errorf("%s: failed to enumerate jobs: %w", rootDir, err)
+			}
+
+			errs = append(errs, fmt.Errorf("%s: %w", rootDir, err))
+		}
+
+		results.Selected += len(metas)
+		for _, meta := range metas {
+			if err := ctx.Err(); err != nil {
+				return results, fmt.Errorf("context error: %w", err)
+			}
+
+			mf, err := vs.LoadManifest(ctx, meta, va)
+			if err != nil {
+				logger := prog.repairLogger(ctx, meta.JobMeta, meta.Par2Path)
+				logger.Error("Failed to load par2cron manifest (skipping)", "error", err)
+				errs = append(errs, fmt.Errorf("%s: %w", meta.Par2Path, err))
+				results.Error++
+
+				continue
+			}
+
+			n, err := prog.restoreSetMetadata(ctx, meta, mf, opts)
+			restored += n
+			switch {
+			case err != nil:
+				errs = append(errs, fmt.Errorf("%s: %w", meta.Par2Path, err))
+				results.Error++
+			case n > 0:
+				results.Success++
+			default:
+				results.Skipped++
+			}
+		}
+	}
+
+	if opts.DryRun {
+		prog.log.Info("Metadata would be restored (--dry-run)", "files", restored)
+	} else {
+		prog.log.Info("Metadata was restored", "files", restored)
+	}
+
+	if len(errs) > 0 {
+		return results, fmt.Errorf("%w: %w", schema.ErrExitPartialFailure, errors.Join(errs...))
+	}
+
+	return results, nil
+}
+
+// restoreSetMetadata restores the recorded metadata of the protected files of
+// one PAR2 set, under its lock, returning the number of files restored.
+func (prog *Service) restoreSetMetadata(ctx context.Context, meta *verify.JobMeta, mf *schema.Manifest, opts MetadataOptions) (int, error) {
+	if mf == nil || mf.Creation == nil {
+		return 0, nil
+	}
+
+	lockPath := opts.SidecarNames.LockPath(meta.Par2Path)
+	if meta.IsBundle {
+		lockPath = meta.Par2Path
+	}
+	unlock, err := util.AcquireLock(prog.fsys, lockPath, false)
+	if err != nil {
+		logger := prog.repairLogger(ctx, meta.JobMeta, lockPath)
+		if errors.Is(err, schema.ErrFileIsLocked) {
+			logger.Warn("File is locked by another instance (not restoring metadata)", "error", err)
+		} else {
+			logger.Error("Failed to lock before restoring metadata", "error", err)
+		}
+
+		return 0, fmt.Errorf("failed to lock: %w", err)
+	}
+	defer unlock()
+
+	var restored int
+	errs := []error{}
+	workingDir := filepath.Dir(meta.Par2Path)
+
+	for _, el := range mf.Creation.Elements {
+		if el.Owner == nil {
+			continue
+		}
+		if el.Name == "" || !filepath.IsLocal(el.Name) {
+			logger := prog.repairLogger(ctx, meta.JobMeta, meta.Par2Path)
+			logger.Warn("Protected element without a usable name (not restoring metadata)", "name", el.Name)
+
+			continue
+		}
+
+		path := filepath.Join(workingDir, el.Name)
+		logger := prog.repairLogger(ctx, meta.JobMeta, path)
+
+		changed, err := prog.restoreElementMetadata(path, el, opts.DryRun)
+		if err != nil {
+			logger.Error("Failed to restore metadata of protected file", "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", el.Name, err))
+
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		restored++
+		if opts.DryRun {
+			logger.Info("Metadata of protected file would be restored (--dry-run)",
+				"mode", el.Mode&metadataModeBits, "uid", el.Owner.UID, "gid", el.Owner.GID)
+		} else {
+			logger.Info("Restored metadata of protected file",
+				"mode", el.Mode&metadataModeBits, "uid", el.Owner.UID, "gid", el.Owner.GID)
+		}
+	}
+
+	if len(errs) > 0 {
+		return restored, fmt.Errorf("failed to restore metadata of %d files: %w", len(errs), errors.Join(errs...))
+	}
+
+	return restored, nil
+}
+
+// restoreElementMetadata restores the recorded owner and permissions of the
+// file at path (owner first, as changing it can clear the setuid/setgid
+// bits), returning if anything differed from the recorded metadata.
+func (prog *Service) restoreElementMetadata(path string, el schema.FsElement, dryRun bool) (bool, error) {
+	fi, err := util.LstatIfPossible(prog.fsys, path)
+	if err != nil {
+		return false, fmt.Errorf("failed to lstat: %w", err)
+	}
+	if fi.Mode()&fs.ModeSymlink != 0 || fi.IsDir() != el.IsDir {
+		return false, fmt.Errorf("unexpected file type: %s", fi.Mode().Type())
+	}
+
+	owner := util.FileOwner(fi)
+	ownerDiffers := owner == nil || *owner != *el.Owner
+	modeDiffers := fi.Mode()&metadataModeBits != el.Mode&metadataModeBits
+
+	if dryRun {
+		return ownerDiffers || modeDiffers, nil
+	}
+
+	if ownerDiffers {
+		if err := prog.fsys.Chown(path, int(el.Owner.UID), int(el.Owner.GID)); err != nil {
+			return false, fmt.Errorf("failed to chown: %w", err)
+		}
+		modeDiffers = true
+	}
+	if modeDiffers {
+		if err := prog.fsys.Chmod(path, el.Mode&metadataModeBits); err != nil {
+			return ownerDiffers, fmt.Errorf("failed to chmod: %w", err)
+		}
+	}
+
+	return ownerDiffers || modeDiffers, nil
+}
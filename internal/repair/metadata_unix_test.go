@@ -0,0 +1,207 @@
+//go:build !windows
+
+package repair
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/create"
+	"github.com/desertwitch/par2cron/internal/flags"
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: The metadata recorded at creation should be restored for a file whose permissions changed since,
+// with --dry-run only logging it, and files with the recorded metadata left as they are.
+func Test_Service_RestoreMetadata_RoundTrip_Success(t *testing.T) {
+	t.Parallel()
+
+	fsys := afero.NewOsFs()
+	root := t.TempDir()
+	folder := filepath.Join(root, "folder")
+	require.NoError(t, os.MkdirAll(filepath.Join(folder, "sub"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "_par2cron"), []byte(""), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "a.txt"), []byte("content a"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "sub", "b.txt"), []byte("content b"), 0o600))
+	require.NoError(t, os.Chmod(filepath.Join(folder, "a.txt"), 0o640))
+	require.NoError(t, os.Chmod(filepath.Join(folder, "sub", "b.txt"), 0o604))
+
+	par2Path := filepath.Join(folder, "folder"+schema.Par2Extension)
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			return os.WriteFile(par2Path, []byte("par2data"), 0o600)
+		},
+	}
+
+	cs := create.NewService(fsys, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+	_, err := cs.Create(t.Context(), []string{root}, create.Options{Par2Glob: "**/*.txt", Par2Mode: flags.CreateMode{Value: schema.CreateFolderMode}, RecordMetadata: true})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(par2Path + schema.ManifestExtension)
+	require.NoError(t, err)
+
+	var mf schema.Manifest
+	require.NoError(t, json.Unmarshal(data, &mf))
+	require.NotNil(t, mf.Creation)
+	require.Len(t, mf.Creation.Elements, 2)
+
+	fi, err := os.Stat(filepath.Join(folder, "a.txt"))
+	require.NoError(t, err)
+	for _, el := range mf.Creation.Elements {
+		require.Equal(t, util.FileOwner(fi), el.Owner, el.Name)
+	}
+
+	// A repair recreated the file, but not with its original permissions.
+	require.NoError(t, os.Chmod(filepath.Join(folder, "a.txt"), 0o600))
+
+	prog := NewService(fsys, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	result, err := prog.RestoreMetadata(t.Context(), []string{root}, MetadataOptions{DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, util.ResultTracker{Selected: 1, Success: 1}, result)
+	require.Contains(t, logBuf.String(), "Metadata of protected file would be restored")
+	requireFileMode(t, filepath.Join(folder, "a.txt"), 0o600)
+
+	result, err = prog.RestoreMetadata(t.Context(), []string{root}, MetadataOptions{})
+	require.NoError(t, err)
+	require.Equal(t, util.ResultTracker{Selected: 1, Success: 1}, result)
+	require.Contains(t, logBuf.String(), "Restored metadata of protected file")
+	require.Contains(t, logBuf.String(), "files=1")
+	requireFileMode(t, filepath.Join(folder, "a.txt"), 0o640)
+	requireFileMode(t, filepath.Join(folder, "sub", "b.txt"), 0o604)
+}
+
+// Expectation: Sets created without --record-metadata should not have their files changed.
+func Test_Service_RestoreMetadata_NotRecorded_Success(t *testing.T) {
+	t.Parallel()
+
+	fsys := afero.NewOsFs()
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.txt"), []byte("content a"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "test"+schema.Par2Extension), []byte("par2data"), 0o600))
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.Creation = schema.NewCreationManifest()
+	mf.Creation.Elements = []schema.FsElement{{Name: "a.txt", Size: 9, Mode: 0o644}}
+	data, err := json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "test"+schema.Par2Extension+schema.ManifestExtension), data, 0o600))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	prog := NewService(fsys, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	result, err := prog.RestoreMetadata(t.Context(), []string{root}, MetadataOptions{})
+	require.NoError(t, err)
+	require.Equal(t, util.ResultTracker{Selected: 1, Skipped: 1}, result)
+	requireFileMode(t, filepath.Join(root, "a.txt"), 0o600)
+}
+
+// Expectation: No metadata should be restored while the pause (sentinel) file exists.
+func Test_Service_RestoreMetadata_Paused_Success(t *testing.T) {
+	t.Parallel()
+
+	fsys := afero.NewOsFs()
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a.txt"), []byte("content a"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "test"+schema.Par2Extension), []byte("par2data"), 0o600))
+
+	fi, err := os.Stat(filepath.Join(root, "a.txt"))
+	require.NoError(t, err)
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.Creation = schema.NewCreationManifest()
+	mf.Creation.Elements = []schema.FsElement{{Name: "a.txt", Size: 9, Mode: 0o640, Owner: util.FileOwner(fi)}}
+	data, err := json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "test"+schema.Par2Extension+schema.ManifestExtension), data, 0o600))
+
+	pauseFile := filepath.Join(t.TempDir(), "pause")
+	require.NoError(t, os.WriteFile(pauseFile, []byte(""), 0o600))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+	prog := NewService(fsys, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	result, err := prog.RestoreMetadata(t.Context(), []string{root}, MetadataOptions{PauseFile: pauseFile})
+	require.NoError(t, err)
+	require.Zero(t, result.Selected)
+	require.Contains(t, logBuf.String(), "Paused by sentinel file")
+	requireFileMode(t, filepath.Join(root, "a.txt"), 0o600)
+
+	require.NoError(t, os.Remove(pauseFile))
+	_, err = prog.RestoreMetadata(t.Context(), []string{root}, MetadataOptions{PauseFile: pauseFile})
+	require.NoError(t, err)
+	requireFileMode(t, filepath.Join(root, "a.txt"), 0o640)
+}
+
+// Expectation: A protected file that is missing should fail as a partial failure, not stopping the others.
+func Test_Service_RestoreMetadata_Missing_Error(t *testing.T) {
+	t.Parallel()
+
+	fsys := afero.NewOsFs()
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "b.txt"), []byte("content b"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "test"+schema.Par2Extension), []byte("par2data"), 0o600))
+
+	fi, err := os.Stat(filepath.Join(root, "b.txt"))
+	require.NoError(t, err)
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.Creation = schema.NewCreationManifest()
+	mf.Creation.Elements = []schema.FsElement{
+		{Name: "a.txt", Size: 9, Mode: 0o644, Owner: util.FileOwner(fi)},
+		{Name: "b.txt", Size: 9, Mode: 0o640, Owner: util.FileOwner(fi)},
+	}
+	data, err := json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "test"+schema.Par2Extension+schema.ManifestExtension), data, 0o600))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	prog := NewService(fsys, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	result, err := prog.RestoreMetadata(t.Context(), []string{root}, MetadataOptions{})
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+	require.Equal(t, util.ResultTracker{Selected: 1, Error: 1}, result)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	requireFileMode(t, filepath.Join(root, "b.txt"), 0o640)
+}
+
+func requireFileMode(t *testing.T, path string, want fs.FileMode) {
+	t.Helper()
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, want, fi.Mode().Perm())
+}
@@ -0,0 +1,117 @@
+package repair
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func createRepairableSet(t *testing.T, fs afero.Fs, par2Path string) {
+	t.Helper()
+
+	require.NoError(t, fs.MkdirAll(filepath.Dir(par2Path), 0o755))
+	require.NoError(t, afero.WriteFile(fs, par2Path, []byte("par2data"), 0o644))
+
+	hash, err := util.HashFile(fs, par2Path)
+	require.NoError(t, err)
+
+	mf := schema.NewManifest(filepath.Base(par2Path))
+	mf.SHA256 = hash
+	mf.Verification = &schema.VerificationManifest{
+		RepairNeeded:   true,
+		RepairPossible: true,
+	}
+	mfData, err := json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, par2Path+schema.ManifestExtension, mfData, 0o644))
+}
+
+// Expectation: Only the queued sets should be repaired, with invalid paths logged and resulting in a partial failure.
+func Test_Service_Repair_Queue_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createRepairableSet(t, fs, "/data/a/one"+schema.Par2Extension)
+	createRepairableSet(t, fs, "/data/b/two"+schema.Par2Extension)
+	createRepairableSet(t, fs, "/data/c/three"+schema.Par2Extension)
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var mu sync.Mutex
+	var repaired []string
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			mu.Lock()
+			defer mu.Unlock()
+			repaired = append(repaired, args[len(args)-1])
+
+			return nil
+		},
+	}
+
+	queue := "/data/a/one.par2\n/data/c\n/data/b/gone.par2\n"
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	results, err := prog.Repair(t.Context(), []string{"/data"}, Options{Queue: strings.NewReader(queue)})
+
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+	require.ErrorContains(t, err, "1 queued paths invalid")
+	require.Equal(t, 2, results.Selected)
+
+	slices.Sort(repaired)
+	require.Equal(t, []string{"/data/a/one.par2", "/data/c/three.par2"}, repaired)
+	require.Contains(t, logBuf.String(), "A queued path was skipped due to FS error")
+}
+
+// Expectation: The job loop should stop after the --limit of jobs.
+func Test_Service_Repair_Limit_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createRepairableSet(t, fs, "/data/a/one"+schema.Par2Extension)
+	createRepairableSet(t, fs, "/data/b/two"+schema.Par2Extension)
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	results, err := prog.Repair(t.Context(), []string{"/data"}, Options{Limit: 1})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, called)
+	require.Equal(t, 2, results.Selected)
+	require.Equal(t, 1, results.Success)
+	require.Contains(t, logBuf.String(), "Reached the --limit of jobs per run")
+}
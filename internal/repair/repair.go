@@ -2,9 +2,9 @@ package repair
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"path/filepath"
 	"slices"
@@ -18,49 +18,104 @@ import (
 	"github.com/spf13/afero"
 )
 
-var _ schema.OptionsPar2ArgsSettable = (*Options)(nil)
+var (
+	_ schema.OptionsValidatable       = (*Options)(nil)
+	_ schema.OptionsPar2ArgsSettable  = (*Options)(nil)
+	_ schema.OptionsPar2ArgsAllowable = (*Options)(nil)
+)
 
 type Options struct {
 	Par2Args             []string
+	AllowedPar2Args      []string
+	Par2Quiet            bool
+	Par2Verbose          bool
 	Par2Verify           bool
 	MaxDuration          flags.Duration
+	Limit                int
+	MaxErrors            int
 	MinTestedCount       int
 	SkipNotCreated       bool
+	CleanOrphans         bool
+	Tags                 flags.Tags
 	AttemptUnrepairables bool
 	PurgeBackups         bool
 	RestoreBackups       bool
+	Rebaseline           bool
+	SkipReadOnly         bool
+	NameMismatch         flags.NameMismatch
 	CacheDir             string
+	Queue                io.Reader
+	Confirm              io.Reader
+	IgnoreNames          util.IgnoreNames
+	SidecarNames         util.SidecarNames
+	MaxDepth             flags.MaxDepth
+	MaxManifestSize      int64
+	PauseFile            string
+	BasePath             flags.BasePath
+	RetrySingleThreaded  bool
+	DereferenceManifest  bool
 }
 
 func (o *Options) SetPar2Args(args []string) {
 	o.Par2Args = slices.Clone(args)
 }
 
+func (o *Options) SetAllowedPar2Args(allowed []string) {
+	o.AllowedPar2Args = slices.Clone(allowed)
+}
+
+func (o *Options) Validate() error {
+	if o.AllowedPar2Args != nil {
+		if err := util.CheckPar2Args(o.Par2Args, o.AllowedPar2Args); err != nil {
+			return fmt.Errorf("args: %w", err)
+		}
+	}
+	if err := util.CheckPar2Verbosity(o.Par2Args, o.Par2Quiet, o.Par2Verbose); err != nil {
+		return fmt.Errorf("par2 verbosity: %w", err)
+	}
+	if err := util.CheckPar2BasePath(o.Par2Args, o.BasePath.Value); err != nil {
+		return fmt.Errorf("basepath: %w", err)
+	}
+
+	return nil
+}
+
 type Service struct {
 	fsys afero.Fs
 
-	log     *logging.Logger
-	runner  schema.CommandRunner
-	walker  schema.FilesystemWalker
-	bundler schema.BundleHandler
-	cacher  schema.CacheHandler
+	log      *logging.Logger
+	runner   schema.CommandRunner
+	walker   schema.FilesystemWalker
+	readOnly schema.ReadOnlyChecker
+	bundler  schema.BundleHandler
+	cacher   schema.CacheHandler
+
+	// timings are the phases of the current run, nil outside of a run.
+	timings *util.Timings
+
+	// filtered are the candidates dropped in the current run, nil outside of a run.
+	filtered *util.Filtered
 }
 
 func NewService(fsys afero.Fs, log *logging.Logger, runner schema.CommandRunner, bundler schema.BundleHandler, cacher schema.CacheHandler) *Service {
 	var walker schema.FilesystemWalker
+	var readOnly schema.ReadOnlyChecker
 	if _, ok := fsys.(*afero.OsFs); ok {
 		walker = util.OSWalker{}
+		readOnly = util.OSReadOnlyChecker{}
 	} else {
 		walker = util.AferoWalker{Fs: fsys}
+		readOnly = util.AferoReadOnlyChecker{Fs: fsys}
 	}
 
 	return &Service{
-		fsys:    fsys,
-		log:     log.With("op", "repair"),
-		runner:  runner,
-		walker:  walker,
-		bundler: bundler,
-		cacher:  cacher,
+		fsys:     fsys,
+		log:      log.With("op", "repair"),
+		runner:   runner,
+		walker:   walker,
+		readOnly: readOnly,
+		bundler:  bundler,
+		cacher:   cacher,
 	}
 }
 
@@ -73,16 +128,22 @@ func NewJobMeta(meta *schema.JobMeta) *JobMeta {
 }
 
 type Job struct {
-	workingDir     string
-	par2Name       string
-	par2Path       string
-	par2Args       []string
-	par2Verify     bool
-	manifestName   string
-	manifestPath   string
-	lockPath       string
-	purgeBackups   bool
-	restoreBackups bool
+	workingDir          string
+	basePath            string
+	par2Name            string
+	par2Path            string
+	par2Args            []string
+	par2Verbosity       []string
+	par2Verify          bool
+	manifestName        string
+	manifestPath        string
+	lockPath            string
+	purgeBackups        bool
+	restoreBackups      bool
+	rebaseline          bool
+	sidecarNames        util.SidecarNames
+	retrySingleThreaded bool
+	dereferenced        bool
 
 	isBundle bool
 	manifest *schema.Manifest
@@ -92,15 +153,17 @@ func NewJob(par2Path string, opts Options, mf *schema.Manifest, isBundle bool) *
 	rj := &Job{}
 
 	rj.workingDir = filepath.Dir(par2Path)
+	rj.basePath = rj.workingDir
 	rj.par2Name = filepath.Base(par2Path)
 	rj.par2Path = par2Path
 	rj.par2Args = slices.Clone(opts.Par2Args)
+	rj.par2Verbosity = util.Par2VerbosityArgs(opts.Par2Quiet, opts.Par2Verbose)
 	rj.par2Verify = opts.Par2Verify
 
 	if !isBundle {
-		rj.manifestName = rj.par2Name + schema.ManifestExtension
-		rj.manifestPath = rj.par2Path + schema.ManifestExtension
-		rj.lockPath = rj.par2Path + schema.LockExtension
+		rj.manifestName = opts.SidecarNames.ManifestName(rj.par2Name)
+		rj.manifestPath = opts.SidecarNames.ManifestPath(rj.par2Path)
+		rj.lockPath = opts.SidecarNames.LockPath(rj.par2Path)
 	} else {
 		rj.manifestName = rj.par2Name
 		rj.manifestPath = rj.par2Path
@@ -109,6 +172,9 @@ func NewJob(par2Path string, opts Options, mf *schema.Manifest, isBundle bool) *
 
 	rj.purgeBackups = opts.PurgeBackups
 	rj.restoreBackups = opts.RestoreBackups
+	rj.rebaseline = opts.Rebaseline
+	rj.sidecarNames = opts.SidecarNames
+	rj.retrySingleThreaded = opts.RetrySingleThreaded
 
 	rj.isBundle = isBundle
 	rj.manifest = mf
@@ -137,10 +203,39 @@ func (prog *Service) Repair(ctx context.Context, rootDirs []string, opts Options
 	results := util.NewResultTracker()
 	logger := prog.repairLogger(ctx, nil, nil)
 
+	prog.timings = util.NewTimings()
+	results.Timings = prog.timings
+	prog.filtered = util.NewFiltered()
+	results.Filtered = prog.filtered
+
+	if util.IsPaused(prog.fsys, opts.PauseFile) {
+		logger.Info("Paused by sentinel file (nothing done; will check again next run)", "pauseFile", opts.PauseFile)
+
+		return results, nil
+	}
+
 	metas := []*JobMeta{}
+	caches := make(map[string]schema.Cache, len(rootDirs))
 	for _, rootDir := range rootDirs {
 		cache := prog.openCache(ctx, rootDir, opts)
 
+		// An empty root is only missing its data (such as a volume not being
+		// mounted) if PAR2 sets below it are known from an earlier run (cache).
+		if err := util.CheckDataRoot(prog.fsys, rootDir, cache.Len() > 0); err != nil {
+			logger.Error("Failed to access the protected data (skipping; unmounted volume?)", "path", rootDir, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", rootDir, err))
+
+			continue
+		}
+
+		// The queued paths are only known after opening all caches, which
+		// are then not pruned (being only partially walked for the queue).
+		if opts.Queue != nil {
+			caches[rootDir] = cache
+
+			continue
+		}
+
 		logger.Info("Scanning filesystem for jobs...",
 			"walker", prog.walker.Name(), "path", rootDir, "cached", cache.Len())
 
@@ -162,12 +257,45 @@ func (prog *Service) Repair(ctx context.Context, rootDirs []string, opts Options
 		metas = append(metas, ms...)
 	}
 
+	if opts.Queue != nil {
+		logger.Info("Reading queued paths for jobs (--from-stdin)...")
+
+		ms, err := prog.EnumerateQueue(ctx, opts.Queue, caches, opts)
+		if err != nil {
+			if !errors.Is(err, schema.ErrNonFatal) {
+				return results, fmt.Errorf("failed to enumerate queued jobs: %w", err)
+			}
+
+			errs = append(errs, fmt.Errorf("failed to enumerate some queued jobs: %w", err))
+		}
+
+		metas = append(metas, ms...)
+	}
+
+	// The operator confirms the repair when running interactively (unless --assume-yes).
+	if len(metas) > 0 && opts.Confirm != nil {
+		confirmed, err := prog.confirmRepair(metas, opts, opts.Confirm)
+		if err != nil {
+			return results, fmt.Errorf("failed to confirm repair: %w", err)
+		}
+		if !confirmed {
+			logger.Warn("Repair was not confirmed (aborting the run)", "candidateJobs", len(metas))
+
+			if len(errs) > 0 {
+				return results, fmt.Errorf("%w: %w",
+					schema.ErrExitPartialFailure, errors.Join(errs...))
+			}
+
+			return results, nil
+		}
+	}
+
 	if len(metas) > 0 {
 		logger.Info(fmt.Sprintf("Starting to process %d jobs...", len(metas)),
 			"maxDuration", opts.MaxDuration.Value.String())
 		results.Selected = len(metas)
 	} else {
-		logger.Info("Nothing to do (will check again next run)")
+		logger.Info(prog.filtered.NothingToDo(), prog.filtered.Attr())
 	}
 
 	var deadlineCtx context.Context //nolint:contextcheck
@@ -177,6 +305,9 @@ func (prog *Service) Repair(ctx context.Context, rootDirs []string, opts Options
 		defer deadlineCancel()
 	}
 
+	progress := prog.log.NewProgress("repairing", len(metas))
+	defer progress.Close()
+
 	for i, meta := range metas {
 		if err := ctx.Err(); err != nil {
 			return results, fmt.Errorf("context error: %w", err)
@@ -193,10 +324,46 @@ func (prog *Service) Repair(ctx context.Context, rootDirs []string, opts Options
 			}
 		}
 
+		if opts.Limit > 0 && i >= opts.Limit {
+			logger := prog.repairLogger(ctx, nil, nil)
+			logger.Warn("Reached the --limit of jobs per run (will continue next run)",
+				"unprocessedJobs", len(metas)-i, "totalJobs", len(metas),
+				"limit", opts.Limit)
+
+			break
+		}
+
+		if opts.MaxErrors > 0 && results.Error >= opts.MaxErrors {
+			logger := prog.repairLogger(ctx, nil, nil)
+			logger.Error("Reached the --max-errors threshold of failed jobs (aborting the run)",
+				"unprocessedJobs", len(metas)-i, "totalJobs", len(metas),
+				"maxErrors", opts.MaxErrors)
+
+			break
+		}
+
 		pos := fmt.Sprintf("%d/%d", i+1, len(metas))
 		ctx := context.WithValue(ctx, schema.PosKey, pos)
+		progress.Step(i, meta.Par2Path)
+
+		if err := prog.checkReadOnly(ctx, meta); err != nil {
+			logger := prog.repairLogger(ctx, meta, nil)
+			if opts.SkipReadOnly {
+				logger.Warn("Job skipped due to a read-only filesystem (--skip-read-only)", "reason", schema.ReasonReadOnly)
+				results.Skipped++
 
-		mf, err := prog.loadManifest(ctx, meta)
+				continue
+			}
+
+			logger.Error("Job failure due to a read-only filesystem (will retry next run; or use --skip-read-only)",
+				"reason", schema.ReasonReadOnly)
+			errs = append(errs, fmt.Errorf("%s: %w", meta.Par2Path, err))
+			results.Error++
+
+			continue
+		}
+
+		mf, err := prog.loadManifest(ctx, meta, opts)
 		if err != nil {
 			if errors.Is(err, schema.ErrFileIsLocked) {
 				logger.Warn("Manifest unavailable (will retry next run)", "error", err)
@@ -212,6 +379,10 @@ func (prog *Service) Repair(ctx context.Context, rootDirs []string, opts Options
 			continue
 		}
 		job := NewJob(meta.Par2Path, opts, mf, meta.IsBundle)
+		if meta.ManifestPath != "" {
+			job.Dereference(meta.ManifestPath)
+		}
+		job.basePath = util.Par2BasePath(opts.BasePath.Value, rootDirs, job.workingDir)
 
 		logger := prog.repairLogger(ctx, job, nil)
 		logger.Info("Job started")
@@ -222,6 +393,8 @@ func (prog *Service) Repair(ctx context.Context, rootDirs []string, opts Options
 		} else if errors.Is(err, schema.ErrFileIsLocked) || errors.Is(err, schema.ErrManifestMismatch) {
 			logger.Warn("Job unavailable (will retry next run)", "error", err)
 			results.Skipped++
+		} else if ctx.Err() != nil {
+			logger.Warn("Job interrupted (will prioritize next run)", "error", err)
 		} else {
 			logger.Error("Job failure (will retry next run)", "error", err)
 			errs = append(errs, fmt.Errorf("%s: %w", job.par2Path, err))
@@ -243,9 +416,35 @@ func (prog *Service) Repair(ctx context.Context, rootDirs []string, opts Options
 	return results, nil
 }
 
+// checkReadOnly returns [schema.ErrReadOnlyFS] if the filesystem containing the
+// PAR2 set is mounted read-only, as par2 would otherwise fail mid-repair. If
+// this cannot be determined, the job is proceeded with as it normally would.
+func (prog *Service) checkReadOnly(ctx context.Context, meta *JobMeta) error {
+	dir := filepath.Dir(meta.Par2Path)
+	if meta.ManifestPath != "" {
+		dir = filepath.Dir(meta.ManifestPath)
+	}
+
+	readOnly, err := prog.readOnly.IsReadOnly(dir)
+	if err != nil {
+		logger := prog.repairLogger(ctx, meta, nil)
+		logger.Debug("Failed to check for a read-only filesystem (proceeding)", "error", err)
+
+		return nil
+	}
+	if readOnly {
+		return schema.ErrReadOnlyFS
+	}
+
+	return nil
+}
+
 func (prog *Service) Enumerate(ctx context.Context, rootDir string, opts Options, cache schema.Cache) ([]*JobMeta, error) {
 	metas := []*JobMeta{}
-	checker := util.NewIgnoreChecker(prog.fsys, rootDir)
+	checker := util.NewIgnoreChecker(prog.fsys, rootDir, opts.IgnoreNames)
+
+	endWalk := prog.timings.StartWalk()
+	defer endWalk()
 
 	var partialErrors int
 	err := prog.walker.WalkDir(rootDir, func(par2path string, d fs.DirEntry, err error) error {
@@ -254,17 +453,47 @@ func (prog *Service) Enumerate(ctx context.Context, rootDir string, opts Options
 		}
 		if err != nil {
 			logger := prog.repairLogger(ctx, nil, par2path)
-			logger.Warn("A path was skipped due to FS error (will retry next run)", "error", err)
+			logger.Warn("A path was skipped due to FS error (will retry next run)", "reason", schema.ReasonFSError, "error", err)
 
 			return nil
 		}
 
+		if d.IsDir() && util.ExceedsMaxDepth(rootDir, par2path, opts.MaxDepth) {
+			logger := prog.repairLogger(ctx, nil, par2path)
+			logger.Debug("A directory was skipped due to the maximum depth", "reason", schema.ReasonMaxDepth)
+			prog.filtered.Add(schema.ReasonMaxDepth)
+
+			return fs.SkipDir
+		}
+		if !d.IsDir() && opts.SidecarNames.IsManifest(d.Name()) {
+			prog.considerOrphanedManifest(ctx, par2path, checker, opts)
+			if !opts.DereferenceManifest || checker.ShouldIgnore(par2path) {
+				return nil
+			}
+
+			// A manifest of a PAR2 set stored elsewhere is never cached,
+			// as the cache is keyed by the PAR2 sets found in the walk.
+			meta, err := prog.processDereferencedManifest(ctx, par2path, opts)
+			if err != nil {
+				if errors.Is(err, schema.ErrNonFatal) {
+					partialErrors++
+				}
+
+				return nil
+			}
+			if prog.isRepairCandidate(ctx, meta.JobMeta, opts) {
+				metas = append(metas, meta)
+			}
+
+			return nil
+		}
 		if d.IsDir() || !util.IsPar2Index(d.Name()) {
 			return nil
 		} // --- End of Hot Path ---
 		if checker.ShouldIgnore(par2path) {
 			logger := prog.repairLogger(ctx, nil, par2path)
-			logger.Debug("A path was skipped due to a present ignore-file")
+			logger.Debug("A path was skipped due to a present ignore-file", "reason", schema.ReasonIgnoreFile)
+			prog.filtered.Add(schema.ReasonIgnoreFile)
 
 			return nil
 		}
@@ -274,7 +503,7 @@ func (prog *Service) Enumerate(ctx context.Context, rootDir string, opts Options
 				metas = append(metas, NewJobMeta(meta))
 			}
 		} else {
-			meta, err := prog.processManifest(ctx, par2path)
+			meta, err := prog.processManifest(ctx, par2path, opts)
 			if err != nil {
 				if !errors.Is(err, schema.ErrNonFatal) && !errors.Is(err, schema.ErrSilentSkip) {
 					return fmt.Errorf("failed to process manifest: %w", err)
@@ -304,57 +533,168 @@ func (prog *Service) Enumerate(ctx context.Context, rootDir string, opts Options
 	return metas, nil
 }
 
+// considerOrphanedManifest warns about a par2cron manifest without its PAR2
+// set, which is never a job, and removes it if requested (--clean-orphans).
+func (prog *Service) considerOrphanedManifest(ctx context.Context, manifestPath string, checker *util.IgnoreChecker, opts Options) {
+	if checker.ShouldIgnore(manifestPath) {
+		return
+	}
+
+	logger := prog.repairLogger(ctx, nil, manifestPath)
+
+	if orphaned, err := util.IsOrphanedManifest(prog.fsys, manifestPath, opts.SidecarNames); err != nil || !orphaned {
+		if err != nil {
+			logger.Debug("Failed to check manifest for a PAR2 set (will retry next run)", "error", err)
+		}
+
+		return
+	}
+
+	if !opts.CleanOrphans {
+		logger.Warn("Orphaned manifest without PAR2 set (remove it or use --clean-orphans)",
+			"reason", schema.ReasonOrphanedManifest)
+
+		return
+	}
+
+	removed, err := util.RemoveOrphanedManifest(prog.fsys, manifestPath, opts.SidecarNames)
+	if err != nil {
+		if errors.Is(err, schema.ErrFileIsLocked) {
+			logger.Debug("Orphaned manifest is locked by another instance (will retry next run)", "reason", schema.ReasonLocked)
+		} else {
+			logger.Warn("Failed to remove orphaned manifest (will retry next run)",
+				"reason", schema.ReasonOrphanedManifest, "error", err)
+		}
+
+		return
+	}
+	if removed {
+		logger.Info("Removed orphaned manifest without PAR2 set (--clean-orphans)",
+			"reason", schema.ReasonOrphanedManifest)
+	}
+}
+
+// considerManifestName checks the name stored in a manifest against the PAR2
+// set it was found for (--name-mismatch), such as after renaming the PAR2 set
+// on disk. On a mismatch, the manifest is either corrected (fix) or the job is
+// skipped (skip).
+func (prog *Service) considerManifestName(ctx context.Context, par2path string, mf *schema.Manifest, isBundle bool, opts Options) error {
+	if opts.NameMismatch.Value == "" {
+		return nil
+	}
+
+	name, mismatch := util.ManifestNameMismatch(par2path, mf, isBundle)
+	if !mismatch {
+		return nil
+	}
+
+	logger := prog.repairLogger(ctx, nil, par2path)
+
+	if opts.NameMismatch.Value == schema.NameMismatchSkip {
+		logger.Warn("Manifest name does not match its PAR2 set (skipping; --name-mismatch skip)",
+			"reason", schema.ReasonNameMismatch, "name", mf.Name, "expected", name)
+		prog.filtered.Add(schema.ReasonNameMismatch)
+
+		return schema.ErrSilentSkip
+	}
+
+	if err := util.CorrectManifestName(ctx, prog.fsys, prog.bundler, par2path, name, isBundle, opts.SidecarNames); err != nil {
+		logger.Warn("Failed to correct manifest name to match its PAR2 set (will retry next run)",
+			"reason", schema.ReasonNameMismatch, "name", mf.Name, "expected", name, "error", err)
+
+		return nil
+	}
+
+	logger.Info("Corrected manifest name to match its PAR2 set (--name-mismatch fix)",
+		"reason", schema.ReasonNameMismatch, "before", mf.Name, "after", name)
+	mf.Name = name
+
+	return nil
+}
+
 func (prog *Service) isRepairCandidate(ctx context.Context, meta *schema.JobMeta, opts Options) bool {
 	if opts.SkipNotCreated && !meta.HasCreation {
 		logger := prog.repairLogger(ctx, meta, nil)
-		logger.Debug("No creation manifest (skipping; --skip-not-created)")
+		logger.Debug("No creation manifest (skipping; --skip-not-created)", "reason", schema.ReasonSkipNotCreated)
+		prog.filtered.Add(schema.ReasonSkipNotCreated)
+
+		return false
+	}
+
+	if !schema.HasTags(meta.Tags, opts.Tags.Value) {
+		logger := prog.repairLogger(ctx, meta, nil)
+		logger.Debug("Missing required tags (skipping; --tag)", "reason", schema.ReasonTagMismatch, "tags", meta.Tags)
+		prog.filtered.Add(schema.ReasonTagMismatch)
 
 		return false
 	}
 
 	if !meta.HasVerification {
 		logger := prog.repairLogger(ctx, meta, nil)
-		logger.Debug("No verification manifest (skipping; not a repair candidate)")
+		logger.Debug("No verification manifest (skipping; not a repair candidate)", "reason", schema.ReasonNoVerification)
+		prog.filtered.Add(schema.ReasonNoVerification)
 
 		return false
 	}
 
 	if meta.RepairNeeded && (meta.CountCorrupted >= opts.MinTestedCount) {
 		if opts.AttemptUnrepairables || meta.RepairPossible {
+			if meta.NoAutoRepair {
+				logger := prog.repairLogger(ctx, meta, nil)
+				logger.Info("Repair disabled by policy (skipping; no-auto-repair)", "reason", schema.ReasonNoAutoRepair)
+				prog.filtered.Add(schema.ReasonNoAutoRepair)
+
+				return false
+			}
+
 			return true
 		}
 	}
 
+	reason := schema.ReasonRepairImpossible
+	switch {
+	case !meta.RepairNeeded:
+		reason = schema.ReasonRepairNotNeeded
+	case meta.CountCorrupted < opts.MinTestedCount:
+		reason = schema.ReasonMinTestedNotMet
+	}
+
 	logger := prog.repairLogger(ctx, meta, nil)
 	logger.Debug("Not a candidate for repair",
+		"reason", reason,
 		"minTested", opts.MinTestedCount,
 		"actualTested", meta.CountCorrupted,
 		"repairNeeded", meta.RepairNeeded,
 		"repairPossible", meta.RepairPossible,
 	)
+	prog.filtered.Add(reason)
 
 	return false
 }
 
-func (prog *Service) processManifest(ctx context.Context, par2path string) (*JobMeta, error) {
+func (prog *Service) processManifest(ctx context.Context, par2path string, opts Options) (*JobMeta, error) {
+	defer prog.timings.Since(util.PhaseParse, time.Now())
+
 	if util.IsPar2Bundle(par2path) {
-		return prog.processBundleManifest(ctx, par2path)
+		return prog.processBundleManifest(ctx, par2path, opts)
 	}
 
-	manifestPath := par2path + schema.ManifestExtension
+	manifestPath := opts.SidecarNames.ManifestPath(par2path)
 
 	if _, err := util.LstatIfPossible(prog.fsys, manifestPath); err != nil {
 		logger := prog.repairLogger(ctx, nil, manifestPath)
-		logger.Debug("Failed to find par2cron manifest (will retry next run)", "error", err)
+		logger.Debug("Failed to find par2cron manifest (will retry next run)", "reason", schema.ReasonNoManifest, "error", err)
+		prog.filtered.Add(schema.ReasonNoManifest)
 
 		return nil, schema.ErrSilentSkip
 	}
 
-	unlock, err := util.AcquireLock(prog.fsys, par2path+schema.LockExtension, false)
+	unlock, err := util.AcquireLock(prog.fsys, opts.SidecarNames.LockPath(par2path), false)
 	if err != nil {
 		if errors.Is(err, schema.ErrFileIsLocked) {
 			logger := prog.repairLogger(ctx, nil, manifestPath)
-			logger.Debug("Manifest is locked by another instance (will retry next run)")
+			logger.Debug("Manifest is locked by another instance (will retry next run)", "reason", schema.ReasonLocked)
+			prog.filtered.Add(schema.ReasonLocked)
 
 			return nil, schema.ErrSilentSkip
 		}
@@ -365,29 +705,34 @@ func (prog *Service) processManifest(ctx context.Context, par2path string) (*Job
 	if err != nil {
 		unlock()
 		logger := prog.repairLogger(ctx, nil, manifestPath)
-		logger.Error("Failed to read par2cron manifest (will retry next run)", "error", err)
+		logger.Error("Failed to read par2cron manifest (will retry next run)", "reason", schema.ReasonManifestRead, "error", err)
 
 		return nil, schema.ErrNonFatal
 	}
 	unlock()
 
-	mf := &schema.Manifest{}
-	if err := json.Unmarshal(data, mf); err != nil {
+	mf, err := util.UnmarshalManifest(data, opts.MaxManifestSize)
+	if err != nil {
 		logger := prog.repairLogger(ctx, nil, manifestPath)
-		logger.Warn("Failed to unmarshal par2cron manifest (will retry next run)", "error", err)
+		logger.Warn("Failed to unmarshal par2cron manifest (will retry next run)", "reason", util.ManifestInvalidReason(err), "error", err)
 
 		return nil, schema.ErrSilentSkip
 	}
 
+	if err := prog.considerManifestName(ctx, par2path, mf, false, opts); err != nil {
+		return nil, err
+	}
+
 	return NewJobMeta(schema.NewJobMeta(par2path, mf, false)), nil
 }
 
-func (prog *Service) processBundleManifest(ctx context.Context, bundlePath string) (*JobMeta, error) {
+func (prog *Service) processBundleManifest(ctx context.Context, bundlePath string, opts Options) (*JobMeta, error) {
 	unlock, err := util.AcquireLock(prog.fsys, bundlePath, false)
 	if err != nil {
 		if errors.Is(err, schema.ErrFileIsLocked) {
 			logger := prog.repairLogger(ctx, nil, bundlePath)
-			logger.Debug("Bundle is locked by another instance (will retry next run)")
+			logger.Debug("Bundle is locked by another instance (will retry next run)", "reason", schema.ReasonLocked)
+			prog.filtered.Add(schema.ReasonLocked)
 
 			return nil, schema.ErrSilentSkip
 		}
@@ -398,7 +743,7 @@ func (prog *Service) processBundleManifest(ctx context.Context, bundlePath strin
 	if err != nil {
 		unlock()
 		logger := prog.repairLogger(ctx, nil, bundlePath)
-		logger.Error("Failed to open bundle (will retry next run)", "error", err)
+		logger.Error("Failed to open bundle (will retry next run)", "reason", schema.ReasonBundleOpen, "error", err)
 
 		return nil, schema.ErrNonFatal
 	}
@@ -407,32 +752,38 @@ func (prog *Service) processBundleManifest(ctx context.Context, bundlePath strin
 		_ = bun.Close()
 		unlock()
 		logger := prog.repairLogger(ctx, nil, bundlePath)
-		logger.Error("Failed to read par2cron manifest (will retry next run)", "error", err)
+		logger.Error("Failed to read par2cron manifest (will retry next run)", "reason", schema.ReasonManifestRead, "error", err)
 
 		return nil, schema.ErrNonFatal
 	}
 	_ = bun.Close()
 	unlock()
 
-	mf := &schema.Manifest{}
-	if err := json.Unmarshal(by, mf); err != nil {
+	mf, err := util.UnmarshalManifest(by, opts.MaxManifestSize)
+	if err != nil {
 		logger := prog.repairLogger(ctx, nil, bundlePath)
-		logger.Error("Failed to unmarshal par2cron manifest (will retry next run)", "error", err)
+		logger.Error("Failed to unmarshal par2cron manifest (will retry next run)", "reason", util.ManifestInvalidReason(err), "error", err)
 
 		return nil, schema.ErrSilentSkip
 	}
 
+	if err := prog.considerManifestName(ctx, bundlePath, mf, true, opts); err != nil {
+		return nil, err
+	}
+
 	return NewJobMeta(schema.NewJobMeta(bundlePath, mf, true)), nil
 }
 
-func (prog *Service) loadManifest(ctx context.Context, meta *JobMeta) (*schema.Manifest, error) {
+func (prog *Service) loadManifest(ctx context.Context, meta *JobMeta, opts Options) (*schema.Manifest, error) {
+	defer prog.timings.Since(util.PhaseParse, time.Now())
+
 	if meta.IsBundle {
-		return prog.loadBundleManifest(ctx, meta)
+		return prog.loadBundleManifest(ctx, meta, opts)
 	}
 
-	manifestPath := meta.Par2Path + schema.ManifestExtension
+	manifestPath, lockPath := opts.SidecarNames.SidecarPaths(meta.JobMeta)
 
-	unlock, err := util.AcquireLock(prog.fsys, meta.Par2Path+schema.LockExtension, false)
+	unlock, err := util.AcquireLock(prog.fsys, lockPath, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to lock: %w", err)
 	}
@@ -444,15 +795,15 @@ func (prog *Service) loadManifest(ctx context.Context, meta *JobMeta) (*schema.M
 	}
 	unlock()
 
-	mf := &schema.Manifest{}
-	if err := json.Unmarshal(data, mf); err != nil {
+	mf, err := util.UnmarshalManifest(data, opts.MaxManifestSize)
+	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal: %w", err)
 	}
 
 	return mf, nil
 }
 
-func (prog *Service) loadBundleManifest(ctx context.Context, meta *JobMeta) (*schema.Manifest, error) {
+func (prog *Service) loadBundleManifest(ctx context.Context, meta *JobMeta, opts Options) (*schema.Manifest, error) {
 	bundlePath := meta.Par2Path
 
 	unlock, err := util.AcquireLock(prog.fsys, bundlePath, false)
@@ -475,14 +826,83 @@ func (prog *Service) loadBundleManifest(ctx context.Context, meta *JobMeta) (*sc
 	_ = bun.Close()
 	unlock()
 
-	mf := &schema.Manifest{}
-	if err := json.Unmarshal(by, mf); err != nil {
+	mf, err := util.UnmarshalManifest(by, opts.MaxManifestSize)
+	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal: %w", err)
 	}
 
 	return mf, nil
 }
 
+// markInterrupted records an interruption marker in the manifest of a job
+// that was cancelled while in-flight, so the next verification prioritizes
+// it. The write is best-effort, and must not be cancelled by the context.
+func (prog *Service) markInterrupted(ctx context.Context, job *Job) {
+	job.manifest.Interruption = schema.NewInterruptionManifest("repair")
+
+	if err := util.WriteManifest(context.WithoutCancel(ctx), prog.fsys, prog.bundler, job.manifestPath, job.manifest, job.isBundle); err != nil {
+		logger := prog.repairLogger(ctx, job, job.manifestPath)
+		logger.Warn("Failed to write interruption marker to par2cron manifest", "error", err)
+	}
+}
+
+// recordLastError records the error of a failed job in the manifest, so that
+// it can be told without the logs why the PAR2 set is unhealthy. The write is
+// best-effort, and must not be cancelled by the same context.
+func (prog *Service) recordLastError(ctx context.Context, job *Job, err error) {
+	job.manifest.SetLastError("repair", err)
+
+	if err := util.WriteManifest(context.WithoutCancel(ctx), prog.fsys, prog.bundler, job.manifestPath, job.manifest, job.isBundle); err != nil {
+		logger := prog.repairLogger(ctx, job, job.manifestPath)
+		logger.Warn("Failed to write last error to par2cron manifest", "error", err)
+	}
+}
+
+// rebaselineManifest refreshes the PAR2 hash and the recorded metadata of the
+// protected elements after a successful repair, as par2cmdline may have
+// rewritten damaged PAR2 files (or touched protected files) in the process.
+// Elements that can no longer be found keep their previously recorded values.
+func (prog *Service) rebaselineManifest(ctx context.Context, job *Job) {
+	if !job.isBundle {
+		sha256hash, err := util.HashFile(prog.fsys, job.par2Path)
+		if err != nil {
+			logger := prog.repairLogger(ctx, job, job.par2Path)
+			logger.Warn("Failed to hash PAR2 for rebaselining (keeping previous hash)", "error", err)
+		} else {
+			job.manifest.SHA256 = sha256hash
+		}
+	}
+
+	if job.manifest.Creation == nil {
+		return
+	}
+
+	var refreshed int
+	for i, el := range job.manifest.Creation.Elements {
+		if el.Name == "" {
+			continue
+		}
+
+		path := filepath.Join(job.workingDir, el.Name)
+
+		fi, err := util.LstatIfPossible(prog.fsys, path)
+		if err != nil {
+			logger := prog.repairLogger(ctx, job, path)
+			logger.Warn("Failed to stat protected element for rebaselining (keeping previous values)", "error", err)
+
+			continue
+		}
+
+		job.manifest.Creation.Elements[i].Size = fi.Size()
+		job.manifest.Creation.Elements[i].Mode = fi.Mode()
+		job.manifest.Creation.Elements[i].ModTime = fi.ModTime()
+		refreshed++
+	}
+
+	logger := prog.repairLogger(ctx, job, job.par2Path)
+	logger.Debug("Rebaselined par2cron manifest after repair", "elements", refreshed)
+}
+
 //nolint:funlen
 func (prog *Service) runRepair(ctx context.Context, job *Job) error {
 	unlock, err := util.AcquireLock(prog.fsys, job.lockPath, false)
@@ -497,7 +917,10 @@ func (prog *Service) runRepair(ctx context.Context, job *Job) error {
 			logger := prog.repairLogger(ctx, job, job.par2Path)
 			logger.Error("Failed to hash PAR2 against par2cron manifest", "error", err)
 
-			return fmt.Errorf("failed to hash par2: %w", err)
+			err = fmt.Errorf("failed to hash par2: %w", err)
+			prog.recordLastError(ctx, job, err)
+
+			return err
 		}
 
 		if sha256hash != job.manifest.SHA256 {
@@ -507,24 +930,36 @@ func (prog *Service) runRepair(ctx context.Context, job *Job) error {
 				"manifestHash", job.manifest.SHA256,
 			)
 
-			return fmt.Errorf("%w: par2 hash mismatch", schema.ErrManifestMismatch)
+			err := fmt.Errorf("%w: par2 hash mismatch", schema.ErrManifestMismatch)
+			prog.recordLastError(ctx, job, err)
+
+			return err
+		}
+	}
+
+	if job.manifest.Creation != nil {
+		for _, path := range util.MissingElements(prog.fsys, job.workingDir, job.manifest.Creation.Elements) {
+			logger := prog.repairLogger(ctx, job, path)
+			logger.Error("Protected file not found relative to the PAR2 set (moved without its PAR2 set?)")
 		}
 	}
 
-	cmdArgs := make([]string, 0, 1+len(job.par2Args)+1+1)
+	// The basepath is pinned (by default) to the directory of the PAR2 set (see verify).
+	basePath := job.basePath
+	if basePath == "" {
+		basePath = job.workingDir
+	}
+
+	cmdArgs := make([]string, 0, 1+len(job.par2Args)+len(job.par2Verbosity)+1+1+1)
 	cmdArgs = append(cmdArgs, "repair")
 	cmdArgs = append(cmdArgs, job.par2Args...)
+	cmdArgs = append(cmdArgs, job.par2Verbosity...)
+	if !util.HasPar2BasePath(job.par2Args) {
+		cmdArgs = append(cmdArgs, "-B"+basePath)
+	}
 	cmdArgs = append(cmdArgs, "--")
 	cmdArgs = append(cmdArgs, job.par2Path)
 
-	if job.manifest.Repair == nil {
-		job.manifest.Repair = schema.NewRepairManifest()
-	}
-	job.manifest.Repair.ProgramVersion = schema.ProgramVersion
-	job.manifest.Repair.Par2Version = schema.Par2Version
-	job.manifest.Repair.Args = slices.Clone(job.par2Args)
-	job.manifest.Repair.Count++
-
 	var purger *backupPurger
 	if job.purgeBackups {
 		purger, err = newBackupPurger(prog.fsys, prog.repairLogger(ctx, job, nil), job.workingDir)
@@ -542,21 +977,40 @@ func (prog *Service) runRepair(ctx context.Context, job *Job) error {
 			logger := prog.repairLogger(ctx, job, job.par2Path)
 			logger.Warn("Failed to create backup file restorer (cannot --restore-backups)", "error", err)
 		} else {
+			// The verdict is captured before the repair, for comparing it with
+			// the one of a verification of the restored protected files.
+			verdictBefore := manifestVerdict(job.manifest)
 			defer func() {
 				if needsRestore {
 					if err := restorer.Restore(); err != nil {
 						logger := prog.repairLogger(ctx, job, job.par2Path)
 						logger.Warn("Failed to restore backup files (cannot --restore-backups)", "error", err)
+					} else if restorer.restored > 0 && ctx.Err() == nil {
+						prog.verifyRestored(ctx, job, verdictBefore)
 					}
 				}
 			}()
 		}
 	}
 
-	job.manifest.Repair.Time = time.Now()
-	err = prog.runner.Run(ctx, "par2", cmdArgs, job.workingDir, prog.log.Options.Stdout, prog.log.Options.Stdout)
-	job.manifest.Repair.Duration = time.Since(job.manifest.Repair.Time)
+	startTime := time.Now()
+	err = prog.runner.Run(ctx, "par2", cmdArgs, basePath, prog.log.Options.Stdout, prog.log.Options.Stdout)
+	if job.retrySingleThreaded && ctx.Err() == nil && util.IsPar2Crash(err) {
+		logger := prog.repairLogger(ctx, job, job.par2Path)
+		logger.Warn("Failed to repair PAR2 (retrying single-threaded)", "error", err)
+		err = prog.runner.Run(ctx, "par2", util.Par2SingleThreadedArgs(cmdArgs, schema.Par2Flavor), basePath, prog.log.Options.Stdout, prog.log.Options.Stdout)
+	}
+	duration := time.Since(startTime)
+	prog.timings.Add(util.PhasePar2, duration)
+
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		needsRestore = true
+		prog.markInterrupted(ctx, job)
 
+		return fmt.Errorf("context error: %w", ctxErr)
+	}
+
+	// A failed repair is not recorded (only its error), as before.
 	if err != nil {
 		needsRestore = true
 
@@ -564,14 +1018,39 @@ func (prog *Service) runRepair(ctx context.Context, job *Job) error {
 		c := util.AsExitCode(err)
 		if c != nil {
 			err = fmt.Errorf("%w (%d)", err, *c)
+			if class := util.Par2ExitError(*c); class != nil {
+				err = fmt.Errorf("%w: %w", class, err)
+			}
 		}
 		logger := prog.repairLogger(ctx, job, job.par2Path)
 		logger.Error("Failed to repair PAR2", "error", err)
+		prog.recordLastError(ctx, job, err)
 
 		return err
 	}
 
+	if job.manifest.Repair == nil {
+		job.manifest.Repair = schema.NewRepairManifest()
+	}
+	job.manifest.Repair.ProgramVersion = schema.ProgramVersion
+	job.manifest.Repair.Par2Version = schema.Par2Version
+	job.manifest.Repair.Args = slices.Clone(job.par2Args)
+	job.manifest.Repair.Count++
+	job.manifest.Repair.Time = startTime
+	job.manifest.Repair.CompletedAt = startTime.Add(duration)
+	job.manifest.Repair.Duration = duration
 	job.manifest.Repair.ExitCode = schema.Par2ExitCodeSuccess
+	job.manifest.Interruption = nil
+	job.manifest.ClearLastError()
+
+	if job.manifest.Health == nil {
+		job.manifest.Health = schema.NewHealthManifest()
+	}
+	job.manifest.Health.AddRepair()
+
+	if job.rebaseline {
+		prog.rebaselineManifest(ctx, job)
+	}
 
 	if err := util.WriteManifest(ctx, prog.fsys, prog.bundler, job.manifestPath, job.manifest, job.isBundle); err != nil {
 		logger := prog.repairLogger(ctx, job, job.manifestPath)
@@ -580,9 +1059,17 @@ func (prog *Service) runRepair(ctx context.Context, job *Job) error {
 
 	if job.par2Verify {
 		vs := verify.NewService(prog.fsys, prog.log, prog.runner, prog.bundler, prog.cacher)
-		vj := verify.NewJob(job.par2Path, verify.Options{}, job.manifest, job.isBundle)
+		vj := verify.NewJob(job.par2Path, verify.Options{SidecarNames: job.sidecarNames, RetrySingleThreaded: job.retrySingleThreaded}, job.manifest, job.isBundle)
+		if job.dereferenced {
+			vj.Dereference(job.manifestPath, job.sidecarNames)
+		}
+		vj.SetBasePath(job.basePath)
+
+		verifyStart := time.Now()
+		err := vs.RunVerify(ctx, vj, true)
+		prog.timings.Since(util.PhasePar2, verifyStart)
 
-		if err := vs.RunVerify(ctx, vj, true); err != nil {
+		if err != nil {
 			return fmt.Errorf("failed to verify par2: %w", err)
 		}
 	}
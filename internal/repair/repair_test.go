@@ -6,14 +6,17 @@ import (
 	"errors"
 	"io"
 	"io/fs"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/desertwitch/par2cron/internal/create"
 	"github.com/desertwitch/par2cron/internal/logging"
 	"github.com/desertwitch/par2cron/internal/schema"
 	"github.com/desertwitch/par2cron/internal/testutil"
 	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/desertwitch/par2cron/internal/verify"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
 )
@@ -301,6 +304,166 @@ func Test_Service_Repair_Success(t *testing.T) {
 	require.Contains(t, logBuf.String(), "Job completed with success")
 }
 
+// Expectation: An existing pause file should skip all work, and its absence should not.
+func Test_Service_Repair_PauseFile_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		paused bool
+	}{
+		{"pause file exists", true},
+		{"pause file missing", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			require.NoError(t, fs.MkdirAll("/data", 0o755))
+			require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+			hash, err := util.HashFile(fs, "/data/test"+schema.Par2Extension)
+			require.NoError(t, err)
+
+			mf := schema.NewManifest("test" + schema.Par2Extension)
+			mf.SHA256 = hash
+			mf.Verification = &schema.VerificationManifest{
+				RepairNeeded:   true,
+				RepairPossible: true,
+			}
+			mfData, err := json.Marshal(mf)
+			require.NoError(t, err)
+			require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension, mfData, 0o644))
+
+			if tt.paused {
+				require.NoError(t, afero.WriteFile(fs, "/run/par2cron.pause", []byte(""), 0o644))
+			}
+
+			var logBuf testutil.SafeBuffer
+			ls := logging.Options{
+				Logout: &logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+			_ = ls.LogLevel.Set("info")
+
+			var called bool
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					called = true
+
+					return nil
+				},
+			}
+
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+			args := Options{Par2Args: []string{"-v"}, PauseFile: "/run/par2cron.pause"}
+			results, err := prog.Repair(t.Context(), []string{"/data"}, args)
+			require.NoError(t, err)
+
+			require.Equal(t, !tt.paused, called)
+			if tt.paused {
+				require.Zero(t, results.Selected)
+				require.Contains(t, logBuf.String(), "Paused by sentinel file")
+			} else {
+				require.Equal(t, 1, results.Success)
+				require.NotContains(t, logBuf.String(), "Paused by sentinel file")
+			}
+		})
+	}
+}
+
+// Expectation: The phases of a run should be tracked and sum up to roughly its total.
+func Test_Service_Repair_Timings_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	hash, err := util.HashFile(fs, "/data/test"+schema.Par2Extension)
+	require.NoError(t, err)
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.SHA256 = hash
+	mf.Verification = &schema.VerificationManifest{
+		RepairNeeded:   true,
+		RepairPossible: true,
+	}
+	mfData, err := json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension, mfData, 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			time.Sleep(50 * time.Millisecond)
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	results, err := prog.Repair(t.Context(), []string{"/data"}, Options{Par2Args: []string{"-v"}})
+	require.NoError(t, err)
+	require.Equal(t, 1, results.Success)
+	require.NotNil(t, results.Timings)
+
+	phases := results.Timings.Phases()
+	sum := phases.Walk + phases.Parse + phases.Par2
+
+	require.GreaterOrEqual(t, phases.Par2, 50*time.Millisecond)
+	require.Positive(t, phases.Parse)
+	require.LessOrEqual(t, sum, phases.Total)
+	require.InDelta(t, phases.Total.Seconds(), sum.Seconds(), phases.Total.Seconds()*0.5)
+}
+
+// Expectation: The job loop should abort once --max-errors jobs have failed, returning a partial failure.
+func Test_Service_Repair_MaxErrors_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createRepairableSet(t, fs, "/data/a/one"+schema.Par2Extension)
+	createRepairableSet(t, fs, "/data/b/two"+schema.Par2Extension)
+	createRepairableSet(t, fs, "/data/c/three"+schema.Par2Extension)
+	createRepairableSet(t, fs, "/data/d/four"+schema.Par2Extension)
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+
+			return errors.New("disk I/O error")
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	results, err := prog.Repair(t.Context(), []string{"/data"}, Options{MaxErrors: 3})
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+
+	require.Equal(t, 3, called)
+	require.Equal(t, 4, results.Selected)
+	require.Equal(t, 3, results.Error)
+	require.Contains(t, logBuf.String(), "Reached the --max-errors threshold of failed jobs")
+	require.Contains(t, logBuf.String(), "unprocessedJobs=1")
+}
+
 // Expectation: The program should handle multiple provided root directories.
 func Test_Service_Repair_MultiRoot_Success(t *testing.T) {
 	t.Parallel()
@@ -639,6 +802,92 @@ func Test_Service_Repair_NoJobs_Success(t *testing.T) {
 	require.Contains(t, logBuf.String(), "Nothing to do")
 }
 
+// Expectation: A run with nothing to do should tell an empty tree apart from an entirely filtered one,
+// counting the candidates dropped per reason of the filter.
+func Test_Service_Repair_NothingToDo_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		mf     func() *schema.Manifest
+		opts   Options
+		reason string
+	}{
+		{"empty tree", nil, Options{}, ""},
+		{"no manifest", func() *schema.Manifest { return nil }, Options{}, schema.ReasonNoManifest},
+		{"no verification", func() *schema.Manifest {
+			return schema.NewManifest("test" + schema.Par2Extension)
+		}, Options{}, schema.ReasonNoVerification},
+		{"repair not needed", func() *schema.Manifest {
+			mf := schema.NewManifest("test" + schema.Par2Extension)
+			mf.Verification = &schema.VerificationManifest{}
+
+			return mf
+		}, Options{}, schema.ReasonRepairNotNeeded},
+		{"repair impossible", func() *schema.Manifest {
+			mf := schema.NewManifest("test" + schema.Par2Extension)
+			mf.Verification = &schema.VerificationManifest{RepairNeeded: true}
+
+			return mf
+		}, Options{}, schema.ReasonRepairImpossible},
+		{"skip not created", func() *schema.Manifest {
+			mf := schema.NewManifest("test" + schema.Par2Extension)
+			mf.Verification = &schema.VerificationManifest{RepairNeeded: true, RepairPossible: true}
+
+			return mf
+		}, Options{SkipNotCreated: true}, schema.ReasonSkipNotCreated},
+		{"no auto repair", func() *schema.Manifest {
+			mf := schema.NewManifest("test" + schema.Par2Extension)
+			mf.Creation = &schema.CreationManifest{NoAutoRepair: true}
+			mf.Verification = &schema.VerificationManifest{RepairNeeded: true, RepairPossible: true}
+
+			return mf
+		}, Options{}, schema.ReasonNoAutoRepair},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+			if tt.mf != nil {
+				require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+				if mf := tt.mf(); mf != nil {
+					data, err := json.Marshal(mf)
+					require.NoError(t, err)
+					require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension, data, 0o644))
+				}
+			}
+
+			var logBuf testutil.SafeBuffer
+			ls := logging.Options{
+				Logout: &logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+			_ = ls.LogLevel.Set("info")
+
+			prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+			res, err := prog.Repair(t.Context(), []string{"/data"}, tt.opts)
+			require.NoError(t, err)
+			require.Zero(t, res.Selected)
+
+			if tt.reason == "" {
+				require.Zero(t, res.Filtered.Total())
+				require.Contains(t, logBuf.String(), "no PAR2 sets found")
+
+				return
+			}
+			require.Equal(t, 1, res.Filtered.Total())
+			require.Equal(t, 1, res.Filtered.Count(tt.reason))
+			require.Contains(t, logBuf.String(), "all 1 candidates filtered")
+		})
+	}
+}
+
 // Expectation: The repair should respect a context cancellation.
 func Test_Service_Repair_CtxCancel_Error(t *testing.T) {
 	t.Parallel()
@@ -675,6 +924,64 @@ func Test_Service_Repair_CtxCancel_Error(t *testing.T) {
 	require.ErrorIs(t, err, context.Canceled)
 }
 
+// Expectation: A repair cancelled mid-job should record an interruption marker in the manifest.
+func Test_Service_Repair_CtxCancelMidJob_WritesInterruption_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	hash, err := util.HashFile(fs, "/data/test"+schema.Par2Extension)
+	require.NoError(t, err)
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.SHA256 = hash
+	mf.Verification = &schema.VerificationManifest{
+		RepairNeeded:   true,
+		RepairPossible: true,
+	}
+	mfData, err := json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension, mfData, 0o644))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			cancel()
+
+			return errors.New("signal: killed")
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	args := Options{Par2Args: []string{"-v"}}
+	_, err = prog.Repair(ctx, []string{"/data"}, args)
+	require.ErrorIs(t, err, context.Canceled)
+
+	data, err := afero.ReadFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+
+	written := &schema.Manifest{}
+	require.NoError(t, json.Unmarshal(data, written))
+	require.NotNil(t, written.Interruption)
+	require.Equal(t, "repair", written.Interruption.Operation)
+	require.False(t, written.Interruption.Time.IsZero())
+	require.Nil(t, written.Repair)
+	require.Contains(t, logBuf.String(), "Job interrupted (will prioritize next run)")
+}
+
 // Expectation: The repair should respect max duration deadline.
 func Test_Service_Repair_MaxDuration_Success(t *testing.T) {
 	t.Parallel()
@@ -1213,6 +1520,7 @@ func Test_Service_Enumerate_RepairNotNeeded_Success(t *testing.T) {
 	require.NoError(t, err)
 	require.Empty(t, jobs)
 	require.Contains(t, logBuf.String(), "Not a candidate for repair")
+	require.Contains(t, logBuf.String(), schema.ReasonRepairNotNeeded)
 }
 
 // Expectation: No job should be returned when repair is impossible without --attempt-unrepairables.
@@ -1253,6 +1561,7 @@ func Test_Service_Enumerate_RepairNeeded_RepairImpossible_Success(t *testing.T)
 	require.NoError(t, err)
 	require.Empty(t, jobs)
 	require.Contains(t, logBuf.String(), "Not a candidate for repair")
+	require.Contains(t, logBuf.String(), schema.ReasonRepairImpossible)
 }
 
 // Expectation: A job should be returned when the min-tested count is met.
@@ -1334,6 +1643,7 @@ func Test_Service_Enumerate_RepairNeeded_MinTestedCount_NotMet_Success(t *testin
 	require.NoError(t, err)
 	require.Empty(t, jobs)
 	require.Contains(t, logBuf.String(), "Not a candidate for repair")
+	require.Contains(t, logBuf.String(), schema.ReasonMinTestedNotMet)
 }
 
 // Expectation: Job should be returned when repair is impossible but --attempt-unrepairables is set.
@@ -1462,6 +1772,48 @@ func Test_Service_Enumerate_InvalidManifest_Success(t *testing.T) {
 	require.Contains(t, logBuf.String(), "Failed to unmarshal par2cron manifest")
 }
 
+// Expectation: No job should be returned when the manifest exceeds the --max-manifest-size of the options.
+func Test_Service_Enumerate_MaxManifestSize_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2"), 0o644))
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.LastError = strings.Repeat("x", 2048)
+	mf.Verification = &schema.VerificationManifest{
+		RepairNeeded:   true,
+		RepairPossible: true,
+	}
+
+	mfData, err := json.Marshal(mf)
+	require.NoError(t, err)
+
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension, mfData, 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("debug")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	args := Options{Par2Args: []string{"-v"}}
+	jobs, err := prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	args.MaxManifestSize = 1024
+	jobs, err = prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+	require.Contains(t, logBuf.String(), "reason="+schema.ReasonManifestTooLarge)
+}
+
 // Expectation: No job should be returned when --skip-not-created is set and no creation manifest exists.
 func Test_Service_Enumerate_SkipNotCreated_Success(t *testing.T) {
 	t.Parallel()
@@ -2298,6 +2650,96 @@ func Test_Service_Enumerate_CacheHit_SkipNotCreated_Success(t *testing.T) {
 	require.Contains(t, logBuf.String(), "skipping; --skip-not-created")
 }
 
+// Expectation: Enumerate should skip a cached entry when it does not have all the required tags.
+func Test_Service_Enumerate_CacheHit_Tags_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2"), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("debug")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	cachedMeta := &schema.JobMeta{
+		Par2Path:        "/data/test" + schema.Par2Extension,
+		HasManifest:     true,
+		HasCreation:     true,
+		HasVerification: true,
+		RepairNeeded:    true,
+		RepairPossible:  true,
+		CountCorrupted:  1,
+		Tags:            []string{"tier:critical"},
+	}
+
+	cache := &testutil.MockCache{
+		GetFunc: func(key string) (*schema.JobMeta, bool) {
+			if key == "/data/test"+schema.Par2Extension {
+				return cachedMeta, true
+			}
+
+			return nil, false
+		},
+	}
+
+	args := Options{Par2Args: []string{"-v"}, MinTestedCount: 1}
+	require.NoError(t, args.Tags.Set("tier:critical"))
+	jobs, err := prog.Enumerate(t.Context(), "/data", args, cache)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	require.NoError(t, args.Tags.Set("media:video"))
+	jobs, err = prog.Enumerate(t.Context(), "/data", args, cache)
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+	require.Contains(t, logBuf.String(), "skipping; --tag")
+}
+
+// Expectation: Enumerate should remove an orphaned manifest with --clean-orphans, never treating it as a job.
+func Test_Service_Enumerate_CleanOrphans_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data/gone"+schema.Par2Extension+schema.ManifestExtension, []byte("{}"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/also-gone"+schema.Par2Extension+schema.ManifestExtension, []byte("{}"), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	jobs, err := prog.Enumerate(t.Context(), "/data", Options{}, &testutil.MockCache{})
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+	require.Contains(t, logBuf.String(), "Orphaned manifest without PAR2 set")
+
+	exists, err := afero.Exists(fs, "/data/gone"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	jobs, err = prog.Enumerate(t.Context(), "/data", Options{CleanOrphans: true}, &testutil.MockCache{})
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+	require.Equal(t, 2, strings.Count(logBuf.String(), "Removed orphaned manifest without PAR2 set"))
+
+	for _, path := range []string{"/data/gone", "/data/also-gone"} {
+		exists, err := afero.Exists(fs, path+schema.Par2Extension+schema.ManifestExtension)
+		require.NoError(t, err)
+		require.False(t, exists)
+	}
+}
+
 // Expectation: Enumerate should skip a cached entry when it has no verification manifest.
 func Test_Service_Enumerate_CacheHit_NoVerification_Success(t *testing.T) {
 	t.Parallel()
@@ -2632,7 +3074,7 @@ func Test_Service_loadManifest_ValidManifest_Success(t *testing.T) {
 		},
 	}
 
-	result, err := prog.loadManifest(t.Context(), meta)
+	result, err := prog.loadManifest(t.Context(), meta, Options{})
 
 	require.NoError(t, err)
 	require.NotNil(t, result)
@@ -2665,7 +3107,7 @@ func Test_Service_loadManifest_FileNotFound_Error(t *testing.T) {
 		},
 	}
 
-	result, err := prog.loadManifest(t.Context(), meta)
+	result, err := prog.loadManifest(t.Context(), meta, Options{})
 
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "failed to read")
@@ -2698,7 +3140,7 @@ func Test_Service_loadManifest_InvalidJSON_Error(t *testing.T) {
 		},
 	}
 
-	result, err := prog.loadManifest(t.Context(), meta)
+	result, err := prog.loadManifest(t.Context(), meta, Options{})
 
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "failed to unmarshal")
@@ -2736,7 +3178,7 @@ func Test_Service_loadManifest_ReadError_Error(t *testing.T) {
 		},
 	}
 
-	result, err := prog.loadManifest(t.Context(), meta)
+	result, err := prog.loadManifest(t.Context(), meta, Options{})
 
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "failed to read")
@@ -2791,7 +3233,7 @@ func Test_Service_loadBundleManifest_ValidManifest_Success(t *testing.T) {
 		},
 	}
 
-	result, err := prog.loadBundleManifest(t.Context(), meta)
+	result, err := prog.loadBundleManifest(t.Context(), meta, Options{})
 
 	require.NoError(t, err)
 	require.NotNil(t, result)
@@ -2830,7 +3272,7 @@ func Test_Service_loadBundleManifest_OpenFails_Error(t *testing.T) {
 		},
 	}
 
-	result, err := prog.loadBundleManifest(t.Context(), meta)
+	result, err := prog.loadBundleManifest(t.Context(), meta, Options{})
 
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "failed to open")
@@ -2877,7 +3319,7 @@ func Test_Service_loadBundleManifest_ManifestReadFails_Error(t *testing.T) {
 		},
 	}
 
-	result, err := prog.loadBundleManifest(t.Context(), meta)
+	result, err := prog.loadBundleManifest(t.Context(), meta, Options{})
 
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "failed to read")
@@ -2924,7 +3366,7 @@ func Test_Service_loadBundleManifest_InvalidJSON_Error(t *testing.T) {
 		},
 	}
 
-	result, err := prog.loadBundleManifest(t.Context(), meta)
+	result, err := prog.loadBundleManifest(t.Context(), meta, Options{})
 
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "failed to unmarshal")
@@ -2983,20 +3425,134 @@ func Test_Service_runRepair_Success(t *testing.T) {
 	require.NotZero(t, job.manifest.Repair.Duration)
 	require.Equal(t, schema.Par2ExitCodeSuccess, job.manifest.Repair.ExitCode)
 	require.Equal(t, 1, job.manifest.Repair.Count)
+	require.Equal(t, 1, job.manifest.Health.CountRepaired)
+	require.Equal(t, schema.HealthScore(0, 0, 1), job.manifest.Health.Score)
 
 	manifestExists, _ := afero.Exists(fs, job.manifestPath)
 	require.True(t, manifestExists)
 }
 
-// Expectation: The repair should pass and the backup files be purged after.
-func Test_Service_runRepair_PurgeBackups_Success(t *testing.T) {
+// Expectation: The manifest hash and element metadata should be refreshed after repair with rebaseline.
+func Test_Service_runRepair_Rebaseline_Success(t *testing.T) {
 	t.Parallel()
 
 	fs := afero.NewMemMapFs()
 	require.NoError(t, fs.MkdirAll("/data", 0o755))
-	require.NoError(t, afero.WriteFile(fs, "/data/test.txt", []byte("original file"), 0o644))
-	require.NoError(t, afero.WriteFile(fs, "/data/other.txt", []byte("original file"), 0o644))
-	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2 file"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/test.txt", []byte("damaged"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	hash, err := util.HashFile(fs, "/data/test"+schema.Par2Extension)
+	require.NoError(t, err)
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			require.NoError(t, afero.WriteFile(fs, "/data/test.txt", []byte("repaired file"), 0o644))
+			require.NoError(t, fs.Chmod("/data/test.txt", 0o600))
+			require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("rewritten par2data"), 0o644))
+
+			return nil
+		},
+	}
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.SHA256 = hash
+	mf.Creation = &schema.CreationManifest{
+		Elements: []schema.FsElement{
+			{Name: "test.txt", Size: 7, Mode: 0o644},
+			{Name: "missing.txt", Size: 42, Mode: 0o644},
+		},
+	}
+
+	job := &Job{
+		workingDir:   "/data",
+		par2Name:     "test" + schema.Par2Extension,
+		par2Path:     "/data/test" + schema.Par2Extension,
+		rebaseline:   true,
+		manifestName: "test" + schema.Par2Extension + schema.ManifestExtension,
+		manifestPath: "/data/test" + schema.Par2Extension + schema.ManifestExtension,
+		lockPath:     "/data/test" + schema.Par2Extension + schema.LockExtension,
+		manifest:     mf,
+	}
+
+	require.NoError(t, prog.runRepair(t.Context(), job))
+
+	newHash, err := util.HashFile(fs, "/data/test"+schema.Par2Extension)
+	require.NoError(t, err)
+	require.Equal(t, newHash, job.manifest.SHA256)
+
+	require.Equal(t, int64(len("repaired file")), job.manifest.Creation.Elements[0].Size)
+	require.EqualValues(t, 0o600, job.manifest.Creation.Elements[0].Mode.Perm())
+	require.Equal(t, int64(42), job.manifest.Creation.Elements[1].Size)
+}
+
+// Expectation: The manifest hash and element metadata should be kept after repair without rebaseline.
+func Test_Service_runRepair_NoRebaseline_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/test.txt", []byte("damaged"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	hash, err := util.HashFile(fs, "/data/test"+schema.Par2Extension)
+	require.NoError(t, err)
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			require.NoError(t, afero.WriteFile(fs, "/data/test.txt", []byte("repaired file"), 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("rewritten par2data"), 0o644))
+
+			return nil
+		},
+	}
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.SHA256 = hash
+	mf.Creation = &schema.CreationManifest{
+		Elements: []schema.FsElement{{Name: "test.txt", Size: 7, Mode: 0o644}},
+	}
+
+	job := &Job{
+		workingDir:   "/data",
+		par2Name:     "test" + schema.Par2Extension,
+		par2Path:     "/data/test" + schema.Par2Extension,
+		manifestName: "test" + schema.Par2Extension + schema.ManifestExtension,
+		manifestPath: "/data/test" + schema.Par2Extension + schema.ManifestExtension,
+		lockPath:     "/data/test" + schema.Par2Extension + schema.LockExtension,
+		manifest:     mf,
+	}
+
+	require.NoError(t, prog.runRepair(t.Context(), job))
+
+	require.Equal(t, hash, job.manifest.SHA256)
+	require.Equal(t, int64(7), job.manifest.Creation.Elements[0].Size)
+}
+
+// Expectation: The repair should pass and the backup files be purged after.
+func Test_Service_runRepair_PurgeBackups_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/test.txt", []byte("original file"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/other.txt", []byte("original file"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2 file"), 0o644))
 	require.NoError(t, afero.WriteFile(fs, "/data/old.file.1", []byte("unrelated file"), 0o644))
 
 	hash, err := util.HashFile(fs, "/data/test"+schema.Par2Extension)
@@ -3066,7 +3622,7 @@ func Test_Service_runRepair_PurgeBackups_Success(t *testing.T) {
 	require.True(t, backup3Exists)
 }
 
-// Expectation: The repair should fail and the backup files be restored after.
+// Expectation: The repair should fail and the backup files be restored (and re-verified) after.
 func Test_Service_runRepair_RestoreBackups_Success(t *testing.T) {
 	t.Parallel()
 
@@ -3093,6 +3649,9 @@ func Test_Service_runRepair_RestoreBackups_Success(t *testing.T) {
 	runner := &testutil.MockRunner{
 		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
 			called++
+			if args[0] != "repair" {
+				return testutil.CreateExitError(t, ctx, 1)
+			}
 
 			// Simulate par2 renaming originals to backups and creating corrupt reconstructions
 			require.NoError(t, fs.Rename(dir+"/test.txt", dir+"/test.txt.1"))
@@ -3128,7 +3687,7 @@ func Test_Service_runRepair_RestoreBackups_Success(t *testing.T) {
 
 	err = prog.runRepair(t.Context(), job)
 	require.ErrorContains(t, err, "par2cmdline:")
-	require.Equal(t, 1, called)
+	require.Equal(t, 2, called)
 
 	// Original files should be restored from backups
 	testContent, err := afero.ReadFile(fs, dir+"/test.txt")
@@ -3151,6 +3710,316 @@ func Test_Service_runRepair_RestoreBackups_Success(t *testing.T) {
 	require.True(t, oldBackupExists)
 }
 
+// Expectation: Restored backup files should be re-verified (report-only) and compared with the verdict
+// from before the repair, but not without anything restored and not changing the outcome of the repair.
+func Test_Service_runRepair_RestoreBackups_Verify_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		backups    bool
+		verifyErr  func(t *testing.T, ctx context.Context) error
+		wantCalls  []string
+		wantLogged string
+	}{
+		{"same verdict", true, func(t *testing.T, ctx context.Context) error {
+			t.Helper()
+
+			return testutil.CreateExitError(t, ctx, 1)
+		}, []string{"repair", "verify"}, "back to their verdict from before the repair"},
+		{"other verdict", true, func(t *testing.T, ctx context.Context) error {
+			t.Helper()
+
+			return testutil.CreateExitError(t, ctx, 2)
+		}, []string{"repair", "verify"}, "verdictAfter=unrepairable"},
+		{"verify failed", true, func(t *testing.T, ctx context.Context) error {
+			t.Helper()
+
+			return errors.New("not runnable")
+		}, []string{"repair", "verify"}, "Failed to verify restored backup files"},
+		{"nothing restored", false, nil, []string{"repair"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewOsFs()
+			dir := t.TempDir()
+
+			require.NoError(t, afero.WriteFile(fs, dir+"/test.txt", []byte("original file"), 0o644))
+			require.NoError(t, afero.WriteFile(fs, dir+"/test"+schema.Par2Extension, []byte("par2 file"), 0o644))
+
+			hash, err := util.HashFile(fs, dir+"/test"+schema.Par2Extension)
+			require.NoError(t, err)
+
+			var logBuf testutil.SafeBuffer
+			ls := logging.Options{
+				Logout: &logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+			_ = ls.LogLevel.Set("info")
+
+			var calls []string
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					calls = append(calls, args[0])
+					if args[0] == "verify" {
+						content, err := afero.ReadFile(fs, dir+"/test.txt")
+						require.NoError(t, err)
+						require.Equal(t, "original file", string(content))
+
+						return tt.verifyErr(t, ctx)
+					}
+
+					if tt.backups {
+						require.NoError(t, fs.Rename(dir+"/test.txt", dir+"/test.txt.1"))
+						require.NoError(t, afero.WriteFile(fs, dir+"/test.txt", []byte("corrupt"), 0o644))
+					}
+
+					return testutil.CreateExitError(t, ctx, 1)
+				},
+			}
+
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+			mf := schema.NewManifest("test" + schema.Par2Extension)
+			mf.SHA256 = hash
+			mf.Verification = &schema.VerificationManifest{
+				RepairNeeded:   true,
+				RepairPossible: true,
+				Count:          1,
+			}
+
+			job := &Job{
+				workingDir:     dir,
+				par2Name:       "test" + schema.Par2Extension,
+				par2Path:       dir + "/test" + schema.Par2Extension,
+				restoreBackups: true,
+				manifestName:   "test" + schema.Par2Extension + schema.ManifestExtension,
+				manifestPath:   dir + "/test" + schema.Par2Extension + schema.ManifestExtension,
+				lockPath:       dir + "/test" + schema.Par2Extension + schema.LockExtension,
+				manifest:       mf,
+			}
+
+			err = prog.runRepair(t.Context(), job)
+			require.ErrorContains(t, err, "par2cmdline:")
+			require.Equal(t, tt.wantCalls, calls)
+			content, err := afero.ReadFile(fs, dir+"/test.txt")
+			require.NoError(t, err)
+			require.Equal(t, "original file", string(content))
+
+			if tt.wantLogged != "" {
+				require.Contains(t, logBuf.String(), tt.wantLogged)
+			}
+
+			// The report-only verification should not have changed the manifest of the job.
+			require.Equal(t, 1, job.manifest.Verification.Count)
+			require.True(t, job.manifest.Verification.RepairPossible)
+			require.NotEmpty(t, job.manifest.LastError)
+		})
+	}
+}
+
+// Expectation: A relocated set should repair with its directory as basepath and log absent files.
+func Test_Service_runRepair_RelocatedTree_MissingElement_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/new/library/sub", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/new/library/set"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	hash, err := util.HashFile(fs, "/new/library/set"+schema.Par2Extension)
+	require.NoError(t, err)
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var runArgs []string
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			runArgs = args
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	mf := schema.NewManifest("set" + schema.Par2Extension)
+	mf.SHA256 = hash
+	mf.Creation = &schema.CreationManifest{
+		Mode:     schema.CreateRecursiveMode,
+		Elements: []schema.FsElement{{Name: "sub", IsDir: true}, {Name: "a.txt"}},
+	}
+
+	job := &Job{
+		workingDir:   "/new/library",
+		par2Name:     "set" + schema.Par2Extension,
+		par2Path:     "/new/library/set" + schema.Par2Extension,
+		manifestName: "set" + schema.Par2Extension + schema.ManifestExtension,
+		manifestPath: "/new/library/set" + schema.Par2Extension + schema.ManifestExtension,
+		lockPath:     "/new/library/set" + schema.Par2Extension + schema.LockExtension,
+		manifest:     mf,
+	}
+
+	require.NoError(t, prog.runRepair(t.Context(), job))
+
+	require.Equal(t, []string{"repair", "-B/new/library", "--", job.par2Path}, runArgs)
+	require.Contains(t, logBuf.String(), "Protected file not found relative to the PAR2 set")
+	require.Contains(t, logBuf.String(), "/new/library/a.txt")
+}
+
+// Expectation: Each basepath mode should run par2 (repair and the verification after) in and
+// pass as basepath the expected directory.
+func Test_Service_Repair_BasePath_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mode    string
+		wantDir string
+	}{
+		{"unset", "", "/data/a/b"},
+		{"auto", schema.BasePathAuto, "/data/a/b"},
+		{"set dir", schema.BasePathSetDir, "/data/a/b"},
+		{"scan root", schema.BasePathScanRoot, "/data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			createRepairableSet(t, fs, "/data/a/b/test"+schema.Par2Extension)
+
+			ls := logging.Options{
+				Logout: io.Discard,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+
+			var runArgs [][]string
+			var runDirs []string
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					runArgs = append(runArgs, args)
+					runDirs = append(runDirs, workingDir)
+
+					return nil
+				},
+			}
+
+			opts := Options{Par2Verify: true}
+			if tt.mode != "" {
+				require.NoError(t, opts.BasePath.Set(tt.mode))
+			}
+			require.NoError(t, opts.Validate())
+
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+			res, err := prog.Repair(t.Context(), []string{"/data"}, opts)
+
+			require.NoError(t, err)
+			require.Equal(t, 1, res.Success)
+			require.Equal(t, []string{tt.wantDir, tt.wantDir}, runDirs)
+			require.Equal(t, [][]string{
+				{"repair", "-B" + tt.wantDir, "--", "/data/a/b/test" + schema.Par2Extension},
+				{"verify", "-B" + tt.wantDir, "--", "/data/a/b/test" + schema.Par2Extension},
+			}, runArgs)
+		})
+	}
+}
+
+// Expectation: A par2 crash of the repair should be retried once single-threaded only with the option.
+func Test_Service_Repair_RetrySingleThreaded_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		retry       bool
+		code        int
+		wantCalls   int
+		wantSuccess int
+	}{
+		{"crash retried", true, schema.Par2ExitCodeLogicError, 2, 1},
+		{"crash without option", false, schema.Par2ExitCodeLogicError, 1, 0},
+		{"repair failure not retried", true, schema.Par2ExitCodeRepairFailed, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			createRepairableSet(t, fs, "/data/test"+schema.Par2Extension)
+
+			ls := logging.Options{
+				Logout: io.Discard,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+
+			var runArgs [][]string
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					runArgs = append(runArgs, args)
+					if !slices.Contains(args, "-t1") {
+						return testutil.CreateExitError(t, ctx, tt.code)
+					}
+
+					return nil
+				},
+			}
+
+			opts := Options{Par2Args: []string{"-t8"}, RetrySingleThreaded: tt.retry}
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+			res, _ := prog.Repair(t.Context(), []string{"/data"}, opts)
+
+			require.Equal(t, tt.wantSuccess, res.Success)
+			require.Len(t, runArgs, tt.wantCalls)
+			if tt.wantCalls > 1 {
+				require.Equal(t, []string{"repair", "-B/data", "-t1", "--", "/data/test" + schema.Par2Extension}, runArgs[1])
+			}
+		})
+	}
+}
+
+// Expectation: The options should be refused with a user basepath where the basepath mode pins it.
+func Test_Options_Validate_BasePath_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range []string{schema.BasePathSetDir, schema.BasePathScanRoot} {
+		opts := Options{Par2Args: []string{"-B/elsewhere"}}
+		require.NoError(t, opts.BasePath.Set(mode))
+		require.ErrorContains(t, opts.Validate(), "basepath", mode)
+	}
+
+	opts := Options{Par2Args: []string{"-B/elsewhere"}}
+	require.NoError(t, opts.BasePath.Set(schema.BasePathAuto))
+	require.NoError(t, opts.Validate())
+}
+
+// Expectation: The options should be refused with conflicting verbosities.
+func Test_Options_Validate_Par2Verbosity_Error(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{Par2Quiet: true, Par2Verbose: true}
+	require.ErrorIs(t, opts.Validate(), schema.ErrPar2Verbosity)
+
+	opts = Options{Par2Args: []string{"-q"}, Par2Verbose: true}
+	require.ErrorIs(t, opts.Validate(), schema.ErrPar2Verbosity)
+
+	opts = Options{Par2Args: []string{"-t4"}, Par2Verbose: true}
+	require.NoError(t, opts.Validate())
+	require.Equal(t, []string{"-v"}, NewJob("/data/test"+schema.Par2Extension, opts, nil, false).par2Verbosity)
+}
+
 // Expectation: The repair should use the correct arguments.
 func Test_Service_runRepair_CorrectArgs_Success(t *testing.T) {
 	t.Parallel()
@@ -3209,6 +4078,7 @@ func Test_Service_runRepair_CorrectArgs_Success(t *testing.T) {
 		"repair",
 		"-v",
 		"-q",
+		"-B" + job.workingDir,
 		"--",
 		job.par2Path,
 	}, runArgs)
@@ -3641,6 +4511,45 @@ func Test_Service_runRepair_ArgsCloned_Success(t *testing.T) {
 	require.Equal(t, []string{"-v", "-q"}, job.manifest.Repair.Args)
 }
 
+// Expectation: runRepair should record the completion of par2 as its start (the time) plus the duration.
+func Test_Service_runRepair_CompletedAt_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createRepairableSet(t, fs, "/data/test"+schema.Par2Extension)
+
+	ls := logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			time.Sleep(5 * time.Millisecond)
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	mf, err := prog.loadManifest(t.Context(), NewJobMeta(&schema.JobMeta{Par2Path: "/data/test" + schema.Par2Extension}), Options{})
+	require.NoError(t, err)
+
+	job := NewJob("/data/test"+schema.Par2Extension, Options{}, mf, false)
+	require.NoError(t, prog.runRepair(t.Context(), job))
+
+	data, err := afero.ReadFile(fs, job.manifestPath)
+	require.NoError(t, err)
+
+	var written schema.Manifest
+	require.NoError(t, json.Unmarshal(data, &written))
+
+	r := written.Repair
+	require.NotNil(t, r)
+	require.False(t, r.CompletedAt.IsZero())
+	require.False(t, r.CompletedAt.Before(r.Time))
+	require.Equal(t, r.Duration, r.CompletedAt.Sub(r.Time))
+	require.GreaterOrEqual(t, r.Duration, 5*time.Millisecond)
+}
+
 // Expectation: The repair should update repair-specific fields
 // (time, duration, count, args, versions, exit code) rather than keeping stale values.
 func Test_Service_runRepair_UpdatesRepairFields_Success(t *testing.T) {
@@ -4355,3 +5264,372 @@ func Test_Service_runRepair_Bundle_VerifyFails_Error(t *testing.T) {
 	require.ErrorContains(t, prog.runRepair(t.Context(), job), "failed to verify par2")
 	require.Equal(t, 2, callCount)
 }
+
+// Expectation: Sets on a read-only filesystem should fail before running par2, or be skipped with --skip-read-only.
+func Test_Service_Repair_ReadOnly_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		skipReadOnly bool
+		wantSkipped  int
+		wantError    int
+		wantLog      string
+	}{
+		{"fail", false, 0, 1, "Job failure due to a read-only filesystem"},
+		{"skip", true, 1, 0, "Job skipped due to a read-only filesystem (--skip-read-only)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			createRepairableSet(t, fs, "/data/ro/one"+schema.Par2Extension)
+			createRepairableSet(t, fs, "/data/rw/two"+schema.Par2Extension)
+
+			var logBuf testutil.SafeBuffer
+			ls := logging.Options{
+				Logout: &logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+
+			var calledDirs []string
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					calledDirs = append(calledDirs, workingDir)
+
+					return nil
+				},
+			}
+
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+			prog.readOnly = &testutil.MockReadOnlyChecker{
+				IsReadOnlyFunc: func(path string) (bool, error) {
+					return path == "/data/ro", nil
+				},
+			}
+
+			results, err := prog.Repair(t.Context(), []string{"/data"}, Options{SkipReadOnly: tt.skipReadOnly})
+			if tt.wantError > 0 {
+				require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+				require.ErrorIs(t, err, schema.ErrReadOnlyFS)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, []string{"/data/rw"}, calledDirs)
+			require.Equal(t, 1, results.Success)
+			require.Equal(t, tt.wantSkipped, results.Skipped)
+			require.Equal(t, tt.wantError, results.Error)
+			require.Contains(t, logBuf.String(), tt.wantLog)
+			require.Contains(t, logBuf.String(), schema.ReasonReadOnly)
+		})
+	}
+}
+
+// Expectation: A failing read-only check should proceed with the repair as usual.
+func Test_Service_Repair_ReadOnlyCheckFails_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createRepairableSet(t, fs, "/data/one"+schema.Par2Extension)
+
+	var called bool
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called = true
+
+			return nil
+		},
+	}
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	prog.readOnly = &testutil.MockReadOnlyChecker{
+		IsReadOnlyFunc: func(_ string) (bool, error) {
+			return false, errors.New("statfs failed")
+		},
+	}
+
+	results, err := prog.Repair(t.Context(), []string{"/data"}, Options{})
+	require.NoError(t, err)
+	require.True(t, called)
+	require.Equal(t, 1, results.Success)
+}
+
+// Expectation: A renamed manifest and lock scheme should be consistently written and read across create, verify and repair.
+func Test_Service_Repair_SidecarNames_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/folder", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/_par2cron", []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/folder/file.txt", []byte("content"), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+	log := logging.NewLogger(ls)
+
+	names := util.SidecarNames{ManifestSuffix: ".meta", LockSuffix: ".lck", Hidden: true}
+	manifestPath := "/data/folder/.folder" + schema.Par2Extension + ".meta"
+
+	createRunner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			require.NoError(t, afero.WriteFile(fs, "/data/folder/folder"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+			return nil
+		},
+	}
+	cs := create.NewService(fs, log, createRunner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+	_, err := cs.Create(t.Context(), []string{"/data"}, create.Options{Par2Glob: "*", SidecarNames: names})
+	require.NoError(t, err)
+
+	exists, err := afero.Exists(fs, manifestPath)
+	require.NoError(t, err)
+	require.True(t, exists)
+	exists, err = afero.Exists(fs, "/data/folder/folder"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	verifyRunner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			return testutil.CreateExitError(t, ctx, schema.Par2ExitCodeRepairPossible)
+		},
+	}
+	vs := verify.NewService(fs, log, verifyRunner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	_, err = vs.Verify(t.Context(), []string{"/data"}, verify.Options{SidecarNames: names})
+	require.ErrorIs(t, err, schema.ErrExitRepairable)
+
+	var called bool
+	repairRunner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called = true
+
+			return nil
+		},
+	}
+	prog := NewService(fs, log, repairRunner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	_, err = prog.Repair(t.Context(), []string{"/data"}, Options{Par2Verify: true, SidecarNames: names})
+	require.NoError(t, err)
+	require.True(t, called)
+
+	exists, err = afero.Exists(fs, "/data/folder/folder"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	data, err := afero.ReadFile(fs, manifestPath)
+	require.NoError(t, err)
+
+	mf := &schema.Manifest{}
+	require.NoError(t, json.Unmarshal(data, mf))
+	require.NotNil(t, mf.Creation)
+	require.NotNil(t, mf.Repair)
+	require.False(t, mf.Verification.RepairNeeded)
+
+	job := NewJob("/data/folder/folder"+schema.Par2Extension, Options{SidecarNames: names}, mf, false)
+	require.Equal(t, manifestPath, job.manifestPath)
+	require.Equal(t, "/data/folder/.folder"+schema.Par2Extension+".lck", job.lockPath)
+}
+
+// Expectation: A manifest name not matching its PAR2 set should be corrected (fix) or skipped (skip).
+func Test_Service_Enumerate_NameMismatch_Success(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []string{schema.NameMismatchFix, schema.NameMismatchSkip} {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, fs.MkdirAll("/data", 0o755))
+		require.NoError(t, afero.WriteFile(fs, "/data/renamed"+schema.Par2Extension, []byte("par2"), 0o644))
+
+		mf := schema.NewManifest("test" + schema.Par2Extension)
+		mf.Verification = &schema.VerificationManifest{
+			RepairNeeded:   true,
+			RepairPossible: true,
+		}
+
+		mfData, err := json.Marshal(mf)
+		require.NoError(t, err)
+
+		require.NoError(t, afero.WriteFile(fs, "/data/renamed"+schema.Par2Extension+schema.ManifestExtension, mfData, 0o644))
+
+		var logBuf testutil.SafeBuffer
+		ls := logging.Options{
+			Logout: &logBuf,
+			Stdout: io.Discard,
+			Stderr: io.Discard,
+		}
+		_ = ls.LogLevel.Set("debug")
+
+		prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+		args := Options{Par2Args: []string{"-v"}}
+		require.NoError(t, args.NameMismatch.Set(policy))
+
+		jobs, err := prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
+		require.NoError(t, err)
+		require.Contains(t, logBuf.String(), schema.ReasonNameMismatch)
+
+		data, err := afero.ReadFile(fs, "/data/renamed"+schema.Par2Extension+schema.ManifestExtension)
+		require.NoError(t, err)
+		stored, err := util.UnmarshalManifest(data, 0)
+		require.NoError(t, err)
+
+		if policy == schema.NameMismatchFix {
+			require.Len(t, jobs, 1, policy)
+			require.Equal(t, "renamed"+schema.Par2Extension, stored.Name)
+		} else {
+			require.Empty(t, jobs, policy)
+			require.Equal(t, "test"+schema.Par2Extension, stored.Name)
+		}
+	}
+}
+
+// Expectation: A set marked with no-auto-repair at creation is never a repair job.
+func Test_Service_Enumerate_NoAutoRepair_Success(t *testing.T) {
+	t.Parallel()
+
+	for _, noAutoRepair := range []bool{false, true} {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, fs.MkdirAll("/data", 0o755))
+		require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2"), 0o644))
+
+		mf := schema.NewManifest("test" + schema.Par2Extension)
+		mf.Creation = &schema.CreationManifest{NoAutoRepair: noAutoRepair}
+		mf.Verification = &schema.VerificationManifest{
+			RepairNeeded:   true,
+			RepairPossible: true,
+		}
+
+		mfData, err := json.Marshal(mf)
+		require.NoError(t, err)
+
+		require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension, mfData, 0o644))
+
+		var logBuf testutil.SafeBuffer
+		ls := logging.Options{
+			Logout: &logBuf,
+			Stdout: io.Discard,
+			Stderr: io.Discard,
+		}
+		_ = ls.LogLevel.Set("debug")
+
+		prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+		jobs, err := prog.Enumerate(t.Context(), "/data", Options{Par2Args: []string{"-v"}, AttemptUnrepairables: true}, &testutil.MockCache{})
+		require.NoError(t, err)
+
+		if noAutoRepair {
+			require.Empty(t, jobs)
+			require.Contains(t, logBuf.String(), "Repair disabled by policy")
+			require.Contains(t, logBuf.String(), schema.ReasonNoAutoRepair)
+		} else {
+			require.Len(t, jobs, 1)
+			require.NotContains(t, logBuf.String(), schema.ReasonNoAutoRepair)
+		}
+	}
+}
+
+// Expectation: A failed repair should record its error (but not itself), and a later success should clear it.
+func Test_Service_Repair_LastError_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createRepairableSet(t, fs, "/data/test"+schema.Par2Extension)
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	fail := true
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			if fail {
+				return testutil.CreateExitError(t, ctx, schema.Par2ExitCodeRepairFailed)
+			}
+
+			return nil
+		},
+	}
+
+	readManifest := func() *schema.Manifest {
+		data, err := afero.ReadFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension)
+		require.NoError(t, err)
+
+		var mf schema.Manifest
+		require.NoError(t, json.Unmarshal(data, &mf))
+
+		return &mf
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	_, err := prog.Repair(t.Context(), []string{"/data"}, Options{})
+	require.ErrorIs(t, err, schema.ErrPar2RepairFailed)
+
+	mf := readManifest()
+	require.Contains(t, mf.LastError, schema.ErrPar2RepairFailed.Error())
+	require.Equal(t, "repair", mf.LastErrorOp)
+	require.False(t, mf.LastErrorAt.IsZero())
+	require.Nil(t, mf.Repair)
+
+	fail = false
+	_, err = prog.Repair(t.Context(), []string{"/data"}, Options{})
+	require.NoError(t, err)
+
+	mf = readManifest()
+	require.Empty(t, mf.LastError)
+	require.True(t, mf.LastErrorAt.IsZero())
+	require.NotNil(t, mf.Repair)
+	require.Equal(t, 1, mf.Repair.Count)
+}
+
+// Expectation: A successful repair should clear the interruption marker of an earlier run.
+func Test_Service_Repair_ClearsInterruption_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createRepairableSet(t, fs, "/data/test"+schema.Par2Extension)
+
+	manifestPath := "/data/test" + schema.Par2Extension + schema.ManifestExtension
+	data, err := afero.ReadFile(fs, manifestPath)
+	require.NoError(t, err)
+
+	var mf schema.Manifest
+	require.NoError(t, json.Unmarshal(data, &mf))
+	mf.Interruption = schema.NewInterruptionManifest("repair")
+	data, err = json.Marshal(&mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, manifestPath, data, 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	runner := &testutil.MockRunner{}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	_, err = prog.Repair(t.Context(), []string{"/data"}, Options{})
+	require.NoError(t, err)
+
+	data, err = afero.ReadFile(fs, manifestPath)
+	require.NoError(t, err)
+
+	mf = schema.Manifest{}
+	require.NoError(t, json.Unmarshal(data, &mf))
+	require.Nil(t, mf.Interruption)
+	require.NotNil(t, mf.Repair)
+}
@@ -1,12 +1,17 @@
 package repair
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/desertwitch/par2cron/internal/verify"
 	"github.com/spf13/afero"
 )
 
@@ -21,8 +26,9 @@ type backupRestorer struct {
 	fsys afero.Fs
 	log  *logging.Logger
 
-	dir    string
-	before map[uint64]fileRecord // map[inode]fileRecord
+	dir      string
+	before   map[uint64]fileRecord // map[inode]fileRecord
+	restored int
 }
 
 func newBackupRestorer(fsys afero.Fs, log *logging.Logger, dir string) (*backupRestorer, error) {
@@ -78,6 +84,7 @@ func (r *backupRestorer) Restore() error {
 
 		r.log.Debug("Restored pre-repair state from backup file",
 			"path", beforeRecord.path)
+		r.restored++
 	}
 
 	return nil
@@ -162,3 +169,76 @@ func (r *backupRestorer) getInode(path string) (uint64, error) {
 
 	return stat.Ino, nil
 }
+
+// Verdicts of a PAR2 set, as compared before the repair and after restoring.
+const (
+	verdictUnverified   = "unverified"
+	verdictHealthy      = "healthy"
+	verdictRepairable   = "repairable"
+	verdictUnrepairable = "unrepairable"
+)
+
+func manifestVerdict(mf *schema.Manifest) string {
+	switch {
+	case mf == nil || mf.Verification == nil:
+		return verdictUnverified
+
+	case mf.Verification.RepairNeeded && mf.Verification.RepairPossible:
+		return verdictRepairable
+
+	case mf.Verification.RepairNeeded:
+		return verdictUnrepairable
+
+	default:
+		return verdictHealthy
+	}
+}
+
+// verifyRestored re-verifies a PAR2 set after its protected files were restored
+// from the backup files of an unsuccessful repair, logging if it is back to its
+// verdict from before the repair. The verification is report-only (on a copy
+// of the manifest), so that it does not change the outcome of the repair.
+func (prog *Service) verifyRestored(ctx context.Context, job *Job, verdictBefore string) {
+	logger := prog.repairLogger(ctx, job, job.par2Path)
+
+	data, err := util.MarshalManifest(job.manifest)
+	if err != nil {
+		logger.Warn("Failed to verify restored backup files (not checking --restore-backups)", "error", err)
+
+		return
+	}
+	mf, err := util.UnmarshalManifest(data, 0)
+	if err != nil {
+		logger.Warn("Failed to verify restored backup files (not checking --restore-backups)", "error", err)
+
+		return
+	}
+
+	vs := verify.NewService(prog.fsys, prog.log, prog.runner, prog.bundler, prog.cacher)
+	vj := verify.NewJob(job.par2Path, verify.Options{SidecarNames: job.sidecarNames, RetrySingleThreaded: job.retrySingleThreaded, NoManifestUpdate: true}, mf, job.isBundle)
+	if job.dereferenced {
+		vj.Dereference(job.manifestPath, job.sidecarNames)
+	}
+	vj.SetBasePath(job.basePath)
+
+	verifyStart := time.Now()
+	err = vs.RunVerify(ctx, vj, true)
+	prog.timings.Since(util.PhasePar2, verifyStart)
+
+	if err != nil {
+		logger.Warn("Failed to verify restored backup files (not checking --restore-backups)", "error", err)
+
+		return
+	}
+
+	if verdictAfter := manifestVerdict(vj.Manifest()); verdictAfter != verdictBefore {
+		logger.Warn("Restored backup files differ in verdict from before the repair (needs attention)",
+			"verdictBefore", verdictBefore,
+			"verdictAfter", verdictAfter)
+
+		return
+	}
+
+	logger.Info("Restored backup files are back to their verdict from before the repair",
+		"verdict", verdictBefore)
+}
@@ -11,12 +11,29 @@ var (
 	ErrExitRepairable     = errors.New("files are corrupted, but repairable")   // [ExitCodeRepairable]
 	ErrExitUnrepairable   = errors.New("files are corrupted, but unrepairable") // [ExitCodeUnrepairable]
 	ErrExitUnclassified   = errors.New("unclassified error")                    // [ExitCodeUnclassified]
+	ErrExitWarnings       = errors.New("warnings were logged")                  // [ExitCodeWarnings]
+	ErrExitMissingData    = errors.New("protected data is missing")             // [ExitCodeMissingData]
 
-	ErrFileIsLocked     = errors.New("file is locked")
-	ErrNonFatal         = errors.New("non-fatal error")
-	ErrSilentSkip       = errors.New("skip without error")
-	ErrManifestMismatch = errors.New("manifest mismatch")
-	ErrUnsupportedGlob  = errors.New("unsupported glob")
+	ErrFileIsLocked       = errors.New("file is locked")
+	ErrNonFatal           = errors.New("non-fatal error")
+	ErrSilentSkip         = errors.New("skip without error")
+	ErrManifestMismatch   = errors.New("manifest mismatch")
+	ErrInvalidManifest    = errors.New("invalid manifest")
+	ErrManifestTooLarge   = errors.New("manifest too large")
+	ErrReadOnlyFS         = errors.New("read-only filesystem")
+	ErrUnsupportedGlob    = errors.New("unsupported glob")
+	ErrPar2ArgNotAllowed  = errors.New("par2 argument not allowed")
+	ErrInvalidAllowedArgs = errors.New("invalid allowed par2 argument")
+	ErrPar2Verbosity      = errors.New("conflicting par2 verbosity")
+
+	ErrUnsupportedIOThrottle = errors.New("unsupported io throttle")
+	ErrInvalidTag            = errors.New("invalid tag")
+
+	ErrPar2Usage        = errors.New("par2 usage error (invalid arguments)")          // [Par2ExitCodeInvalidArguments]
+	ErrPar2CriticalData = errors.New("par2 critical data missing (damaged PAR2 set)") // [Par2ExitCodeCriticalData]
+	ErrPar2RepairFailed = errors.New("par2 repair failed")                            // [Par2ExitCodeRepairFailed]
+	ErrPar2FileIO       = errors.New("par2 file i/o error")                           // [Par2ExitCodeFileIOError]
+	ErrPar2Internal     = errors.New("par2 internal error (logic or memory)")         // [Par2ExitCodeLogicError]
 )
 
 var exitErrorsByPriority = []struct {
@@ -24,11 +41,13 @@ var exitErrorsByPriority = []struct {
 	code int
 }{
 	{context.Canceled, ExitCodeInterrupted},         // 143
+	{ErrExitMissingData, ExitCodeMissingData},       // 7
 	{ErrExitUnclassified, ExitCodeUnclassified},     // 5
 	{ErrExitUnrepairable, ExitCodeUnrepairable},     // 4
 	{ErrExitRepairable, ExitCodeRepairable},         // 3
 	{ErrExitBadInvocation, ExitCodeBadInvocation},   // 2
 	{ErrExitPartialFailure, ExitCodePartialFailure}, // 1
+	{ErrExitWarnings, ExitCodeWarnings},             // 6
 }
 
 func ExitCodeFor(err error) int {
@@ -48,6 +48,26 @@ func Test_ExitCodeFor_Table(t *testing.T) {
 			err:      ErrExitRepairable,
 			expected: ExitCodeRepairable,
 		},
+		{
+			name:     "ErrExitWarnings returns warnings code",
+			err:      ErrExitWarnings,
+			expected: ExitCodeWarnings,
+		},
+		{
+			name:     "ErrExitWarnings with partial failure returns partial failure code",
+			err:      fmt.Errorf("wrapped: %w: %w", ErrExitWarnings, ErrExitPartialFailure),
+			expected: ExitCodePartialFailure,
+		},
+		{
+			name:     "ErrExitMissingData returns missing data code",
+			err:      ErrExitMissingData,
+			expected: ExitCodeMissingData,
+		},
+		{
+			name:     "ErrExitMissingData with unrepairable returns missing data code",
+			err:      fmt.Errorf("wrapped: %w: %w", ErrExitPartialFailure, errors.Join(ErrExitUnrepairable, ErrExitMissingData)),
+			expected: ExitCodeMissingData,
+		},
 		{
 			name:     "multiple known errors returns highest error",
 			err:      fmt.Errorf("wrapped: %w: %w", ErrExitPartialFailure, ErrExitBadInvocation),
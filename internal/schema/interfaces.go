@@ -15,6 +15,14 @@ type FilesystemWalker interface {
 	WalkDir(root string, fn fs.WalkDirFunc) error
 }
 
+type ReadOnlyChecker interface {
+	IsReadOnly(path string) (bool, error)
+}
+
+type CacheDropper interface {
+	DropCache(path string) error
+}
+
 type CommandRunner interface {
 	Run(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error
 }
@@ -64,3 +72,7 @@ type OptionsValidatable interface {
 type OptionsPar2ArgsSettable interface {
 	SetPar2Args(args []string)
 }
+
+type OptionsPar2ArgsAllowable interface {
+	SetAllowedPar2Args(allowed []string)
+}
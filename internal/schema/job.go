@@ -1,22 +1,51 @@
 package schema
 
-import "time"
+import (
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/par2"
+)
 
 const MetaVersion uint8 = 1
 
 type JobMeta struct {
 	Par2Path        string
-	VerifyTime      time.Time     // mf.Verification
-	VerifyDuration  time.Duration // mf.Verification
-	CountCorrupted  int           // mf.Verification
+	CreateTime      time.Time       // mf.Creation
+	VerifyTime      time.Time       // mf.Verification
+	VerifyDuration  time.Duration   // mf.Verification
+	RecentDurations []time.Duration // mf.Verification
+	VerifyInterval  time.Duration   // mf.Creation
+	Tags            []string        // mf.Creation
+	CountCorrupted  int             // mf.Verification
+	HealthScore     int             // mf.Health
 	MetaVersion     uint8
 	Walked          bool
 	IsBundle        bool
 	HasManifest     bool
 	HasCreation     bool // mf.Creation
+	NoAutoRepair    bool // mf.Creation
 	HasVerification bool // mf.Verification
 	RepairNeeded    bool // mf.Verification
 	RepairPossible  bool // mf.Verification
+	Interrupted     bool // mf.Interruption
+	QuickProblems   bool // mf.QuickVerification
+	HasHealth       bool // mf.Health
+
+	// FolderFingerprint is the recorded composition of a folder mode set,
+	// along with the glob (and excludes) for recomputing it (mf.Creation).
+	FolderFingerprint string
+	Glob              string
+	GlobExclude       []string
+
+	// ManifestPath is the path of the manifest of a PAR2 set stored elsewhere
+	// (--dereference-manifest), which is next to the protected files.
+	ManifestPath string
+
+	// SetID is the set ID of the PAR2 index, which is only parsed (and then
+	// cached) for grouping split sets, or zero if it was not parsed yet. It
+	// is only valid for the index of IndexSHA256 (mf.SHA256).
+	SetID       par2.Hash
+	IndexSHA256 string
 }
 
 func NewJobMeta(par2path string, mf *Manifest, isBundle bool) *JobMeta {
@@ -28,14 +57,35 @@ func NewJobMeta(par2path string, mf *Manifest, isBundle bool) *JobMeta {
 
 	if mf != nil {
 		meta.HasManifest = true
+		meta.IndexSHA256 = mf.SHA256
 
 		if mf.Creation != nil {
 			meta.HasCreation = true
+			meta.CreateTime = mf.Creation.Time
+			meta.VerifyInterval = mf.Creation.VerifyInterval
+			meta.Tags = mf.Creation.Tags
+			meta.NoAutoRepair = mf.Creation.NoAutoRepair
+			if mf.Creation.FolderFingerprint != "" {
+				meta.FolderFingerprint = mf.Creation.FolderFingerprint
+				meta.Glob = mf.Creation.Glob
+				meta.GlobExclude = mf.Creation.GlobExclude
+			}
+		}
+		if mf.Interruption != nil {
+			meta.Interrupted = true
+		}
+		if mf.QuickVerification.NeedsFull(mf.Verification) {
+			meta.QuickProblems = true
+		}
+		if mf.Health != nil {
+			meta.HasHealth = true
+			meta.HealthScore = mf.Health.Score
 		}
 		if mf.Verification != nil {
 			meta.HasVerification = true
 			meta.VerifyTime = mf.Verification.Time
 			meta.VerifyDuration = mf.Verification.Duration
+			meta.RecentDurations = mf.Verification.RecentDurations
 			meta.RepairNeeded = mf.Verification.RepairNeeded
 			meta.RepairPossible = mf.Verification.RepairPossible
 			meta.CountCorrupted = mf.Verification.CountCorrupted
@@ -118,11 +118,16 @@ func Test_NewJobMeta_WithVerification_Success(t *testing.T) {
 func Test_NewJobMeta_WithCreationAndVerification_Success(t *testing.T) {
 	t.Parallel()
 
+	createTime := time.Date(2023, 9, 1, 10, 0, 0, 0, time.UTC)
 	verifyTime := time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC)
 	verifyDuration := 250 * time.Millisecond
 
 	mf := NewManifest("test" + Par2Extension)
 	mf.Creation = NewCreationManifest()
+	mf.Creation.Time = createTime
+	mf.Creation.VerifyInterval = 6 * time.Hour
+	mf.Creation.Tags = []string{"tier:critical"}
+	mf.Creation.NoAutoRepair = true
 	mf.Verification = NewVerificationManifest()
 	mf.Verification.Time = verifyTime
 	mf.Verification.Duration = verifyDuration
@@ -140,9 +145,45 @@ func Test_NewJobMeta_WithCreationAndVerification_Success(t *testing.T) {
 	require.True(t, meta.HasCreation)
 	require.True(t, meta.HasVerification)
 
+	require.Equal(t, createTime, meta.CreateTime)
+	require.Equal(t, 6*time.Hour, meta.VerifyInterval)
+	require.Equal(t, []string{"tier:critical"}, meta.Tags)
+	require.True(t, meta.NoAutoRepair)
 	require.Equal(t, verifyTime, meta.VerifyTime)
 	require.Equal(t, verifyDuration, meta.VerifyDuration)
 	require.True(t, meta.RepairNeeded)
 	require.False(t, meta.RepairPossible)
 	require.Equal(t, 7, meta.CountCorrupted)
 }
+
+// Expectation: An interruption marker should be reflected in the job meta.
+func Test_NewJobMeta_WithInterruption_Success(t *testing.T) {
+	t.Parallel()
+
+	mf := NewManifest("test" + Par2Extension)
+	mf.Interruption = NewInterruptionManifest("verify")
+
+	meta := NewJobMeta("test"+Par2Extension, mf, false)
+
+	require.True(t, meta.HasManifest)
+	require.True(t, meta.Interrupted)
+	require.False(t, meta.HasVerification)
+}
+
+// Expectation: Problems of a quick verification after the last verification should be reflected in the job meta.
+func Test_NewJobMeta_WithQuickProblems_Success(t *testing.T) {
+	t.Parallel()
+
+	mf := NewManifest("test" + Par2Extension)
+	mf.Verification = NewVerificationManifest()
+	mf.Verification.Time = time.Now().Add(-time.Hour)
+	mf.QuickVerification = NewQuickVerificationManifest()
+	mf.QuickVerification.Time = time.Now()
+
+	meta := NewJobMeta("test"+Par2Extension, mf, false)
+	require.True(t, meta.QuickProblems)
+
+	mf.QuickVerification.Healthy = true
+	meta = NewJobMeta("test"+Par2Extension, mf, false)
+	require.False(t, meta.QuickProblems)
+}
@@ -4,23 +4,57 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/fs"
+	"path/filepath"
+	"slices"
 	"time"
 )
 
 const (
 	ManifestVersion = "2"
+
+	// MaxRecentDurations is the number of verification durations retained
+	// in the rolling window of the verification manifest.
+	MaxRecentDurations = 5
+
+	// MaxHealthScore is the health score of a set never found corrupted.
+	MaxHealthScore = 100
+
+	// healthCorruptedWeight and healthRepairedWeight are the weights of a
+	// corruption and a repair, as multiples of a clean verification.
+	healthCorruptedWeight = 2
+	healthRepairedWeight  = 3
 )
 
 type Manifest struct {
+	// ProgramVersion is the program version that last wrote the manifest,
+	// as it is stamped on every write of the manifest.
 	ProgramVersion  string `json:"program_version"`
 	ManifestVersion string `json:"manifest_version"`
 
 	Name   string `json:"name"`
 	SHA256 string `json:"sha256"`
 
+	// Par2Location is the path of the PAR2 index file, for a PAR2 set stored
+	// apart from its protected files (absolute, or relative to the directory
+	// of the manifest). It is followed with --dereference-manifest.
+	Par2Location string `json:"par2_location,omitempty"`
+
 	Creation     *CreationManifest     `json:"creation,omitempty"`
 	Verification *VerificationManifest `json:"verification,omitempty"`
 	Repair       *RepairManifest       `json:"repair,omitempty"`
+	Health       *HealthManifest       `json:"health,omitempty"`
+
+	MirrorVerification *MirrorVerificationManifest `json:"mirror_verification,omitempty"`
+	QuickVerification  *QuickVerificationManifest  `json:"quick_verification,omitempty"`
+	CrossCheck         *CrossCheckManifest         `json:"cross_check,omitempty"`
+	Interruption       *InterruptionManifest       `json:"interruption,omitempty"`
+
+	// LastError is the error of the last failed verification or repair of the
+	// set (at LastErrorAt, of operation LastErrorOp), for triage without logs.
+	// It is cleared by the next successful verification or repair of the set.
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitzero"`
+	LastErrorOp string    `json:"last_error_op,omitempty"`
 }
 
 func NewManifest(par2Name string) *Manifest {
@@ -31,21 +65,160 @@ func NewManifest(par2Name string) *Manifest {
 	}
 }
 
+// SetLastError records the error of a failed operation of the set.
+func (m *Manifest) SetLastError(operation string, err error) {
+	m.LastError = err.Error()
+	m.LastErrorAt = time.Now()
+	m.LastErrorOp = operation
+}
+
+// ClearLastError clears the error recorded by a failed operation of the set.
+func (m *Manifest) ClearLastError() {
+	m.LastError = ""
+	m.LastErrorAt = time.Time{}
+	m.LastErrorOp = ""
+}
+
+// Validate returns an error for values that par2cron never writes, such as
+// negative durations or counts, or protected elements outside the directory
+// of the PAR2 set, as manifests on shared filesystems can be tampered with.
+func (m *Manifest) Validate() error {
+	if c := m.Creation; c != nil {
+		if c.Duration < 0 || c.VerifyInterval < 0 {
+			return fmt.Errorf("%w: negative creation duration", ErrInvalidManifest)
+		}
+		for _, el := range c.Elements {
+			if el.Size < 0 {
+				return fmt.Errorf("%w: negative size of element %q", ErrInvalidManifest, el.Name)
+			}
+			// An empty name is recorded when it could not be derived at creation.
+			if el.Name != "" && !filepath.IsLocal(el.Name) {
+				return fmt.Errorf("%w: element %q is outside the directory", ErrInvalidManifest, el.Name)
+			}
+		}
+	}
+
+	if v := m.Verification; v != nil {
+		if v.Count < 0 || v.CountCorrupted < 0 {
+			return fmt.Errorf("%w: negative verification count", ErrInvalidManifest)
+		}
+		if v.Duration < 0 || slices.ContainsFunc(v.RecentDurations, func(d time.Duration) bool { return d < 0 }) {
+			return fmt.Errorf("%w: negative verification duration", ErrInvalidManifest)
+		}
+		if g := v.Geometry; g != nil && (g.SourceSlices < 0 || g.RecoveryFiles < 0) {
+			return fmt.Errorf("%w: negative verification geometry", ErrInvalidManifest)
+		}
+		if slices.ContainsFunc(v.Files, func(f FileStatus) bool { return f.FoundBlocks < 0 || f.TotalBlocks < 0 }) {
+			return fmt.Errorf("%w: negative file status blocks", ErrInvalidManifest)
+		}
+	}
+
+	if r := m.Repair; r != nil {
+		if r.Count < 0 || r.Duration < 0 {
+			return fmt.Errorf("%w: negative repair count or duration", ErrInvalidManifest)
+		}
+	}
+
+	if h := m.Health; h != nil {
+		if h.CountClean < 0 || h.CountCorrupted < 0 || h.CountRepaired < 0 {
+			return fmt.Errorf("%w: negative health count", ErrInvalidManifest)
+		}
+	}
+
+	if mv := m.MirrorVerification; mv != nil && mv.Duration < 0 {
+		return fmt.Errorf("%w: negative mirror verification duration", ErrInvalidManifest)
+	}
+
+	if cc := m.CrossCheck; cc != nil && cc.Duration < 0 {
+		return fmt.Errorf("%w: negative cross-check duration", ErrInvalidManifest)
+	}
+
+	if qv := m.QuickVerification; qv != nil && (qv.Count < 0 || qv.Duration < 0) {
+		return fmt.Errorf("%w: negative quick verification count or duration", ErrInvalidManifest)
+	}
+
+	return nil
+}
+
 type CreationManifest struct {
 	ProgramVersion string        `json:"program_version"`
 	Par2Version    string        `json:"par2_version"`
+	Par2Flavor     string        `json:"par2_flavor,omitempty"`
 	Time           time.Time     `json:"time"`
 	Mode           string        `json:"mode"`
 	Glob           string        `json:"glob"`
 	Args           []string      `json:"args"`
 	Duration       time.Duration `json:"duration_ns"`
 	Elements       []FsElement   `json:"elements"`
+
+	// GlobExclude are the patterns excluded from the glob (as set at creation).
+	GlobExclude []string `json:"glob_exclude,omitempty"`
+
+	// VerifyInterval overrides the minimum age between verifications (--age)
+	// for this set, where a zero value means no override (as set at creation).
+	VerifyInterval time.Duration `json:"verify_interval_ns,omitempty"`
+
+	// Tags are the labels of this set (as set at creation), for selecting
+	// sets with --tag in later operations. They are sorted and unique.
+	Tags []string `json:"tags,omitempty"`
+
+	// NoAutoRepair excludes this set from repair operations (as set at
+	// creation), while it is still verified like any other set.
+	NoAutoRepair bool `json:"no_auto_repair,omitempty"`
+
+	// FolderFingerprint is a hash over the names, sizes and modification
+	// times of the protected files (with --folder-fingerprint), for checking
+	// the composition of the folder at verification from its listing alone.
+	FolderFingerprint string `json:"folder_fingerprint,omitempty"`
+
+	// Subset is the position of this set among the size-bounded subsets the
+	// protected files of its folder were split into (with --max-set-size),
+	// nil for a set protecting all files of its folder.
+	Subset *CreationSubset `json:"subset,omitempty"`
+
+	// Marker is the marker file this set was created from, with the settings
+	// parsed from its contents, nil for a set not created from a marker file.
+	Marker *CreationMarker `json:"marker,omitempty"`
+}
+
+// CreationMarker is the marker file a PAR2 set was created from (see
+// [CreationManifest.Marker]), with only the settings given in its contents
+// (as parsed), unset ones being nil. Together with the settings given in its
+// name, it makes the intent of the marker file (removed after creation by
+// default) self-contained, for auditing and re-creating the set alike.
+type CreationMarker struct {
+	File string `json:"file"`
+
+	Name                    *string        `json:"name,omitempty"`
+	Args                    *[]string      `json:"args,omitempty"`
+	Glob                    *string        `json:"glob,omitempty"`
+	GlobExclude             *[]string      `json:"glob_exclude,omitempty"`
+	Mode                    *string        `json:"mode,omitempty"`
+	Verify                  *bool          `json:"verify,omitempty"`
+	Hidden                  *bool          `json:"hidden,omitempty"`
+	Persist                 *bool          `json:"persist,omitempty"`
+	Bundle                  *bool          `json:"bundle,omitempty"`
+	ExcludeEmpty            *bool          `json:"exclude_empty,omitempty"`
+	AdoptExisting           *bool          `json:"adopt_existing,omitempty"`
+	ProtectCreationManifest *bool          `json:"protect_creation_manifest,omitempty"`
+	Tags                    *[]string      `json:"tags,omitempty"`
+	VerifyInterval          *time.Duration `json:"verify_interval_ns,omitempty"`
+	NoAutoRepair            *bool          `json:"no_auto_repair,omitempty"`
+}
+
+// CreationSubset is the position of a PAR2 set among the subsets of a folder
+// split by the protected size (see [CreationManifest.Subset]).
+type CreationSubset struct {
+	Index   int   `json:"index"`
+	Count   int   `json:"count"`
+	MaxSize int64 `json:"max_size"`
 }
 
 func NewCreationManifest() *CreationManifest {
 	return &CreationManifest{
 		ProgramVersion: ProgramVersion,
 		Par2Version:    Par2Version,
+		Par2Flavor:     Par2Flavor,
 	}
 }
 
@@ -82,6 +255,43 @@ type VerificationManifest struct {
 	RepairNeeded   bool          `json:"repair_needed"`
 	RepairPossible bool          `json:"repair_possible"`
 	Duration       time.Duration `json:"duration_ns"`
+
+	// CompletedAt is the wall-clock completion of the par2 verification, that
+	// is Time (its start, as relied on by --age) plus Duration. It is zero for
+	// older verifications.
+	CompletedAt time.Time `json:"completed_at,omitzero"`
+
+	// RecentDurations is a rolling window of the most recent verification
+	// durations (oldest first), bounded to MaxRecentDurations entries.
+	RecentDurations []time.Duration `json:"recent_durations_ns,omitempty"`
+
+	// Geometry is the block geometry of the PAR2 set, which is parsed once
+	// at verification (nil until then, or if the set could not be parsed).
+	Geometry *GeometryManifest `json:"geometry,omitempty"`
+
+	// Files is the per-file status of the protected files, as parsed from the
+	// par2 output of a corrupted verification (only with --file-status).
+	Files []FileStatus `json:"files,omitempty"`
+
+	// PassesDisagreed is if the par2 passes of the verification disagreed
+	// on its outcome (with --verify-passes), a sign of flaky hardware.
+	PassesDisagreed bool `json:"passes_disagreed,omitempty"`
+}
+
+// FileStatus is the status of a protected file within a PAR2 set, where the
+// blocks are only known for damaged files (as reported by par2).
+type FileStatus struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	FoundBlocks int    `json:"found_blocks,omitempty"`
+	TotalBlocks int    `json:"total_blocks,omitempty"`
+}
+
+// GeometryManifest is the block geometry of a PAR2 set, as parsed from it.
+type GeometryManifest struct {
+	SliceSize     uint64 `json:"slice_size"`
+	SourceSlices  int64  `json:"source_slices"`
+	RecoveryFiles int    `json:"recovery_files"`
 }
 
 func NewVerificationManifest() *VerificationManifest {
@@ -91,6 +301,85 @@ func NewVerificationManifest() *VerificationManifest {
 	}
 }
 
+// AddDuration records a verification duration, both as the last duration
+// and into the rolling window, dropping the oldest entries beyond its bound.
+func (v *VerificationManifest) AddDuration(d time.Duration) {
+	v.Duration = d
+	v.RecentDurations = append(v.RecentDurations, d)
+
+	if n := len(v.RecentDurations); n > MaxRecentDurations {
+		v.RecentDurations = slices.Clone(v.RecentDurations[n-MaxRecentDurations:])
+	}
+}
+
+type MirrorVerificationManifest struct {
+	ProgramVersion string        `json:"program_version"`
+	Par2Version    string        `json:"par2_version"`
+	Time           time.Time     `json:"time"`
+	Args           []string      `json:"args"`
+	ExitCode       int           `json:"exit_code"`
+	PrimaryCode    int           `json:"primary_exit_code"`
+	Diverged       bool          `json:"diverged"`
+	Duration       time.Duration `json:"duration_ns"`
+}
+
+func NewMirrorVerificationManifest() *MirrorVerificationManifest {
+	return &MirrorVerificationManifest{
+		ProgramVersion: ProgramVersion,
+		Par2Version:    Par2Version,
+	}
+}
+
+// CrossCheckManifest is the outcome of the last cross-check (verify
+// --cross-check), an external command checking the integrity of the protected
+// files once more after a clean par2 verification. A failed cross-check (its
+// exit code not zero) is not clean, despite the verdict of par2.
+type CrossCheckManifest struct {
+	ProgramVersion string        `json:"program_version"`
+	Time           time.Time     `json:"time"`
+	Command        string        `json:"command"`
+	ExitCode       int           `json:"exit_code"`
+	Failed         bool          `json:"failed"`
+	Duration       time.Duration `json:"duration_ns"`
+}
+
+func NewCrossCheckManifest(command string) *CrossCheckManifest {
+	return &CrossCheckManifest{
+		ProgramVersion: ProgramVersion,
+		Command:        command,
+	}
+}
+
+// QuickVerificationManifest is the outcome of the last quick verification
+// (verify --quick), which only checks the integrity of the PAR2 index and the
+// presence and sizes of the protected files, without running par2. It never
+// replaces the (full) verification, but problems found by it have the set
+// fully verified with the next normal run (see NeedsFull).
+type QuickVerificationManifest struct {
+	ProgramVersion string        `json:"program_version"`
+	Count          int           `json:"count"`
+	Time           time.Time     `json:"time"`
+	Duration       time.Duration `json:"duration_ns"`
+	Healthy        bool          `json:"healthy"`
+	Problems       []string      `json:"problems,omitempty"`
+}
+
+func NewQuickVerificationManifest() *QuickVerificationManifest {
+	return &QuickVerificationManifest{
+		ProgramVersion: ProgramVersion,
+	}
+}
+
+// NeedsFull returns if the quick verification found problems that were not
+// yet followed up by a (full) verification, given the last one (or nil).
+func (q *QuickVerificationManifest) NeedsFull(v *VerificationManifest) bool {
+	if q == nil || q.Healthy {
+		return false
+	}
+
+	return v == nil || v.Time.Before(q.Time)
+}
+
 type RepairManifest struct {
 	ProgramVersion string        `json:"program_version"`
 	Par2Version    string        `json:"par2_version"`
@@ -99,6 +388,10 @@ type RepairManifest struct {
 	Args           []string      `json:"args"`
 	ExitCode       int           `json:"exit_code"`
 	Duration       time.Duration `json:"duration_ns"`
+
+	// CompletedAt is the wall-clock completion of the par2 repair, that is
+	// Time (its start) plus Duration. It is zero for older repairs.
+	CompletedAt time.Time `json:"completed_at,omitzero"`
 }
 
 func NewRepairManifest() *RepairManifest {
@@ -108,6 +401,115 @@ func NewRepairManifest() *RepairManifest {
 	}
 }
 
+// HealthManifest is the integrity history of a set, accumulated over all of
+// its verifications and repairs, along with the health score derived from it
+// (see [HealthScore]). It is advisory only and never affects any decisions.
+type HealthManifest struct {
+	CountClean     int `json:"count_clean"`
+	CountCorrupted int `json:"count_corrupted"`
+	CountRepaired  int `json:"count_repaired"`
+	Score          int `json:"score"`
+}
+
+func NewHealthManifest() *HealthManifest {
+	return &HealthManifest{
+		Score: MaxHealthScore,
+	}
+}
+
+// AddVerification records the outcome of a verification and recomputes the
+// health score, where corrupted is whether the set was found needing repair.
+func (h *HealthManifest) AddVerification(corrupted bool) {
+	if corrupted {
+		h.CountCorrupted++
+	} else {
+		h.CountClean++
+	}
+	h.Score = HealthScore(h.CountClean, h.CountCorrupted, h.CountRepaired)
+}
+
+// AddRepair records a successful repair and recomputes the health score.
+func (h *HealthManifest) AddRepair() {
+	h.CountRepaired++
+	h.Score = HealthScore(h.CountClean, h.CountCorrupted, h.CountRepaired)
+}
+
+// HealthScore returns the health score of a set (from 0 to [MaxHealthScore])
+// for its counts of clean verifications, corruptions and repairs:
+//
+//	100 * (clean + 1) / (clean + 1 + 2*corrupted + 3*repaired)
+//
+// A set never found corrupted scores 100, while every corruption (and every
+// repair) lowers the score, so that a set repeatedly needing repair scores
+// worse than one corrupted once. Clean verifications since then only slowly
+// raise the score again, as the history is never forgotten.
+func HealthScore(clean, corrupted, repaired int) int {
+	weight := healthCorruptedWeight*corrupted + healthRepairedWeight*repaired
+
+	return MaxHealthScore * (clean + 1) / (clean + 1 + weight)
+}
+
+type InterruptionManifest struct {
+	ProgramVersion string    `json:"program_version"`
+	Operation      string    `json:"operation"`
+	Time           time.Time `json:"time"`
+}
+
+func NewInterruptionManifest(operation string) *InterruptionManifest {
+	return &InterruptionManifest{
+		ProgramVersion: ProgramVersion,
+		Operation:      operation,
+		Time:           time.Now(),
+	}
+}
+
+// Stamp is the content of the optional stamp file written next to a PAR2 set
+// after its successful creation (and refreshed after a successful verification),
+// for external tools to check without having to understand par2cron manifests.
+type Stamp struct {
+	ProgramVersion string    `json:"program_version"`
+	Name           string    `json:"name"`
+	Operation      string    `json:"operation"`
+	Time           time.Time `json:"time"`
+}
+
+func NewStamp(par2Name string, operation string) *Stamp {
+	return &Stamp{
+		ProgramVersion: ProgramVersion,
+		Name:           par2Name,
+		Operation:      operation,
+		Time:           time.Now(),
+	}
+}
+
+// FileList is the content of the optional file list written next to a PAR2 set
+// after its successful creation, listing the protected files (with the names
+// relative to the PAR2 set) for catalog tools not to parse par2cron manifests.
+type FileList struct {
+	ProgramVersion string          `json:"program_version"`
+	Name           string          `json:"name"`
+	Files          []FileListEntry `json:"files"`
+}
+
+type FileListEntry struct {
+	Name  string `json:"name"`
+	Size  int64  `json:"size"`
+	IsDir bool   `json:"is_dir,omitempty"`
+}
+
+func NewFileList(par2Name string, elements []FsElement) *FileList {
+	files := make([]FileListEntry, 0, len(elements))
+	for _, e := range elements {
+		files = append(files, FileListEntry{Name: e.Name, Size: e.Size, IsDir: e.IsDir})
+	}
+
+	return &FileList{
+		ProgramVersion: ProgramVersion,
+		Name:           par2Name,
+		Files:          files,
+	}
+}
+
 type FsElement struct {
 	Path string `json:"-"` // Excluded from JSON (not to leak absolute paths)
 
@@ -116,4 +518,15 @@ type FsElement struct {
 	Mode    fs.FileMode `json:"mode"`
 	IsDir   bool        `json:"is_dir"`
 	ModTime time.Time   `json:"mod_time"`
+
+	// Owner is the owner of the element (as recorded at creation with
+	// --record-metadata), nil if not recorded or not known on the platform.
+	Owner *FsOwner `json:"owner,omitempty"`
+}
+
+// FsOwner is the owning user and group of a file system element, which are
+// restored along with its permissions by the restore-metadata command.
+type FsOwner struct {
+	UID uint32 `json:"uid"`
+	GID uint32 `json:"gid"`
 }
@@ -3,6 +3,7 @@ package schema
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -30,6 +31,48 @@ func Test_NewManifest_Success(t *testing.T) {
 	require.Nil(t, mf.Repair)
 }
 
+// Expectation: Validation should refuse values that par2cron never writes.
+func Test_Manifest_Validate_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mf      *Manifest
+		wantErr bool
+	}{
+		{"empty", &Manifest{}, false},
+		{"valid", &Manifest{
+			Creation:     &CreationManifest{Duration: time.Second, Elements: []FsElement{{Name: "a/b.txt", Size: 1}, {Name: ""}}},
+			Verification: &VerificationManifest{Count: 2, RecentDurations: []time.Duration{time.Second}},
+			Repair:       &RepairManifest{Count: 1},
+		}, false},
+		{"negative creation duration", &Manifest{Creation: &CreationManifest{Duration: -1}}, true},
+		{"negative verify interval", &Manifest{Creation: &CreationManifest{VerifyInterval: -1}}, true},
+		{"negative element size", &Manifest{Creation: &CreationManifest{Elements: []FsElement{{Name: "a", Size: -1}}}}, true},
+		{"element outside", &Manifest{Creation: &CreationManifest{Elements: []FsElement{{Name: "../etc/passwd"}}}}, true},
+		{"absolute element", &Manifest{Creation: &CreationManifest{Elements: []FsElement{{Name: "/etc/passwd"}}}}, true},
+		{"negative verification count", &Manifest{Verification: &VerificationManifest{CountCorrupted: -1}}, true},
+		{"negative recent duration", &Manifest{Verification: &VerificationManifest{RecentDurations: []time.Duration{1, -1}}}, true},
+		{"negative file status blocks", &Manifest{Verification: &VerificationManifest{Files: []FileStatus{{Name: "a", Status: FileStatusDamaged, FoundBlocks: -1}}}}, true},
+		{"negative geometry", &Manifest{Verification: &VerificationManifest{Geometry: &GeometryManifest{SliceSize: 4, SourceSlices: -1}}}, true},
+		{"negative repair count", &Manifest{Repair: &RepairManifest{Count: -1}}, true},
+		{"negative health count", &Manifest{Health: &HealthManifest{CountRepaired: -1}}, true},
+		{"negative mirror duration", &Manifest{MirrorVerification: &MirrorVerificationManifest{Duration: -1}}, true},
+		{"negative quick count", &Manifest{QuickVerification: &QuickVerificationManifest{Count: -1}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.wantErr {
+				require.ErrorIs(t, tt.mf.Validate(), ErrInvalidManifest)
+			} else {
+				require.NoError(t, tt.mf.Validate())
+			}
+		})
+	}
+}
+
 // Expectation: The unmarshalling should work according to expectations.
 func Test_CreationManifest_UnmarshalJSON_Table(t *testing.T) {
 	t.Parallel()
@@ -126,6 +169,48 @@ func Test_NewVerificationManifest_Success(t *testing.T) {
 	require.Equal(t, Par2Version, mf.Par2Version)
 }
 
+// Expectation: Durations are recorded into a window bounded to the most recent ones.
+func Test_VerificationManifest_AddDuration_Bounded_Success(t *testing.T) {
+	t.Parallel()
+
+	mf := NewVerificationManifest()
+	for i := 1; i <= MaxRecentDurations+2; i++ {
+		mf.AddDuration(time.Duration(i) * time.Second)
+	}
+
+	require.Equal(t, time.Duration(MaxRecentDurations+2)*time.Second, mf.Duration)
+	require.Len(t, mf.RecentDurations, MaxRecentDurations)
+	require.Equal(t, 3*time.Second, mf.RecentDurations[0])
+	require.Equal(t, time.Duration(MaxRecentDurations+2)*time.Second, mf.RecentDurations[MaxRecentDurations-1])
+}
+
+// Expectation: Only unhealthy quick verifications not followed by a verification should need a full one.
+func Test_QuickVerificationManifest_NeedsFull_Table(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		quick *QuickVerificationManifest
+		full  *VerificationManifest
+		want  bool
+	}{
+		{"no quick verification", nil, nil, false},
+		{"healthy", &QuickVerificationManifest{Healthy: true, Time: now}, nil, false},
+		{"problems without verification", &QuickVerificationManifest{Time: now}, nil, true},
+		{"problems after verification", &QuickVerificationManifest{Time: now}, &VerificationManifest{Time: now.Add(-time.Hour)}, true},
+		{"problems before verification", &QuickVerificationManifest{Time: now}, &VerificationManifest{Time: now.Add(time.Hour)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, tt.quick.NeedsFull(tt.full))
+		})
+	}
+}
+
 // Expectation: A new manifest is created with the constants populated.
 func Test_NewRepairManifest_Success(t *testing.T) {
 	t.Parallel()
@@ -135,3 +220,51 @@ func Test_NewRepairManifest_Success(t *testing.T) {
 	require.Equal(t, ProgramVersion, mf.ProgramVersion)
 	require.Equal(t, Par2Version, mf.Par2Version)
 }
+
+// Expectation: The health score should meet the table's expectations.
+func Test_HealthScore_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		clean     int
+		corrupted int
+		repaired  int
+		want      int
+	}{
+		{"no history", 0, 0, 0, MaxHealthScore},
+		{"only clean", 10, 0, 0, MaxHealthScore},
+		{"corrupted once", 0, 1, 0, 33},
+		{"corrupted and repaired once", 0, 1, 1, 16},
+		{"clean since repair", 10, 1, 1, 68},
+		{"repeatedly repaired", 10, 3, 3, 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, HealthScore(tt.clean, tt.corrupted, tt.repaired))
+		})
+	}
+}
+
+// Expectation: Verifications and repairs are counted and the score is recomputed on each.
+func Test_HealthManifest_Add_Success(t *testing.T) {
+	t.Parallel()
+
+	h := NewHealthManifest()
+	require.Equal(t, MaxHealthScore, h.Score)
+
+	h.AddVerification(false)
+	require.Equal(t, 1, h.CountClean)
+	require.Equal(t, MaxHealthScore, h.Score)
+
+	h.AddVerification(true)
+	require.Equal(t, 1, h.CountCorrupted)
+	require.Equal(t, HealthScore(1, 1, 0), h.Score)
+
+	h.AddRepair()
+	require.Equal(t, 1, h.CountRepaired)
+	require.Equal(t, HealthScore(1, 1, 1), h.Score)
+	require.Less(t, h.Score, HealthScore(1, 1, 0))
+}
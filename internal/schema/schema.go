@@ -6,6 +6,9 @@ var ProgramVersion = "devel"
 // Par2Version is the program version of "par2" as filled in at runtime.
 var Par2Version = ""
 
+// Par2Flavor is the flavor of "par2" as detected (or pinned) at runtime.
+var Par2Flavor = ""
+
 const (
 	ExitCodeSuccess        int = 0
 	ExitCodePartialFailure int = 1   // ErrExitPartialFailure
@@ -13,6 +16,8 @@ const (
 	ExitCodeRepairable     int = 3   // ErrExitRepairable
 	ExitCodeUnrepairable   int = 4   // ErrExitUnrepairable
 	ExitCodeUnclassified   int = 5   // ErrExitUnclassified
+	ExitCodeWarnings       int = 6   // ErrExitWarnings
+	ExitCodeMissingData    int = 7   // ErrExitMissingData
 	ExitCodeInterrupted    int = 143 // context.Canceled
 
 	// https://github.com/Parchive/par2cmdline/blob/master/src/libpar2.h
@@ -20,6 +25,12 @@ const (
 	Par2ExitCodeSuccess          int = 0
 	Par2ExitCodeRepairPossible   int = 1
 	Par2ExitCodeRepairImpossible int = 2
+	Par2ExitCodeInvalidArguments int = 3 // ErrPar2Usage
+	Par2ExitCodeCriticalData     int = 4 // ErrPar2CriticalData
+	Par2ExitCodeRepairFailed     int = 5 // ErrPar2RepairFailed
+	Par2ExitCodeFileIOError      int = 6 // ErrPar2FileIO
+	Par2ExitCodeLogicError       int = 7 // ErrPar2Internal
+	Par2ExitCodeMemoryError      int = 8 // ErrPar2Internal
 
 	BundleExtension   string = ".p2c"  // used as bundleExtension+par2Extension
 	Par2VolPrefix     string = ".vol"  // used as Par2VolPrefix+par2Extension
@@ -27,13 +38,84 @@ const (
 	LockExtension     string = ".lock" // used as par2Extension+lockExtension
 	ManifestExtension string = ".json" // used as par2Extension+manifestExtension
 
+	CreationManifestExtension string = ".creation.json" // used as par2Extension+creationManifestExtension
+	FileListExtension         string = ".files"         // used as par2Extension+fileListExtension
+
 	IgnoreFile    string = ".par2cron-ignore"
 	IgnoreAllFile string = ".par2cron-ignore-all"
+	LastRunFile   string = ".par2cron-last-verify"
+	StampFile     string = ".par2cron-done"
 
 	CreateFolderMode    string = "folder"
 	CreateNestedMode    string = "nested"
 	CreateFileMode      string = "file"
 	CreateRecursiveMode string = "recursive"
+
+	Par2FlavorAuto    string = "auto"
+	Par2FlavorClassic string = "par2cmdline"
+	Par2FlavorTurbo   string = "turbo"
+
+	VerifyOrderOldest string = "oldest"
+	VerifyOrderNewest string = "newest"
+	VerifyOrderRandom string = "random"
+
+	ColorAuto   string = "auto"
+	ColorAlways string = "always"
+	ColorNever  string = "never"
+
+	ExportFormatJSON string = "json"
+	ExportFormatCSV  string = "csv"
+
+	FileStatusIntact  string = "intact"
+	FileStatusDamaged string = "damaged"
+	FileStatusMissing string = "missing"
+
+	Par2ExitClassSuccess      string = "success"
+	Par2ExitClassRepairable   string = "repairable"
+	Par2ExitClassUnrepairable string = "unrepairable"
+	Par2ExitClassUsageError   string = "usage-error"
+
+	IOThrottleNone       string = "none"
+	IOThrottleIdle       string = "idle"
+	IOThrottleBestEffort string = "best-effort"
+
+	NameMismatchFix  string = "fix"
+	NameMismatchSkip string = "skip"
+
+	BasePathAuto     string = "auto"
+	BasePathSetDir   string = "set-dir"
+	BasePathScanRoot string = "scan-root"
+)
+
+// Reason codes are attached to the log records of skipped or failed jobs
+// (as "reason"), so that they can be told apart without parsing the message.
+const (
+	ReasonFSError          string = "fs_error"
+	ReasonMaxDepth         string = "max_depth"
+	ReasonIgnoreFile       string = "ignore_file"
+	ReasonLocked           string = "locked"
+	ReasonNoManifest       string = "no_manifest"
+	ReasonManifestRead     string = "manifest_read_failed"
+	ReasonManifestInvalid  string = "manifest_invalid"
+	ReasonManifestTooLarge string = "manifest_too_large"
+	ReasonBundleOpen       string = "bundle_open_failed"
+	ReasonOrphanedManifest string = "orphaned_manifest"
+	ReasonSplitSet         string = "split_set"
+	ReasonQueueInvalid     string = "queue_invalid"
+	ReasonSkipNotCreated   string = "skip_not_created"
+	ReasonTagMismatch      string = "tag_mismatch"
+	ReasonCreatedRange     string = "created_out_of_range"
+	ReasonUnchanged        string = "unchanged_since"
+	ReasonNotDue           string = "not_due"
+	ReasonCheckpointed     string = "checkpointed"
+	ReasonNoVerification   string = "no_verification"
+	ReasonRepairNotNeeded  string = "repair_not_needed"
+	ReasonMinTestedNotMet  string = "min_tested_not_met"
+	ReasonRepairImpossible string = "repair_impossible"
+	ReasonReadOnly         string = "read_only"
+	ReasonNameMismatch     string = "name_mismatch"
+	ReasonNoAutoRepair     string = "no_auto_repair"
+	ReasonDereference      string = "dereference_failed"
 )
 
 type ctxKey int
@@ -21,6 +21,12 @@ func Test_Constants_Success(t *testing.T) {
 	require.Equal(t, 0, Par2ExitCodeSuccess)
 	require.Equal(t, 1, Par2ExitCodeRepairPossible)
 	require.Equal(t, 2, Par2ExitCodeRepairImpossible)
+	require.Equal(t, 3, Par2ExitCodeInvalidArguments)
+	require.Equal(t, 4, Par2ExitCodeCriticalData)
+	require.Equal(t, 5, Par2ExitCodeRepairFailed)
+	require.Equal(t, 6, Par2ExitCodeFileIOError)
+	require.Equal(t, 7, Par2ExitCodeLogicError)
+	require.Equal(t, 8, Par2ExitCodeMemoryError)
 
 	require.Equal(t, ".vol", Par2VolPrefix)
 	require.Equal(t, ".par2", Par2Extension)
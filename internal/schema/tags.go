@@ -0,0 +1,34 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ValidateTag returns an error if a tag is empty or contains whitespace or
+// commas, as commas separate multiple tags within a single tag expression.
+func ValidateTag(tag string) error {
+	if tag == "" {
+		return fmt.Errorf("%w: must not be empty", ErrInvalidTag)
+	}
+
+	if strings.ContainsFunc(tag, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		return fmt.Errorf("%w: %q must not contain whitespace or commas", ErrInvalidTag, tag)
+	}
+
+	return nil
+}
+
+// HasTags returns if all the wanted tags are contained in the tags, where no
+// wanted tags always match. Tags are compared case-sensitively.
+func HasTags(tags []string, want []string) bool {
+	for _, w := range want {
+		if !slices.Contains(tags, w) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,35 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: Valid tags should pass, empty tags or such with whitespace or commas not.
+func Test_ValidateTag_Success(t *testing.T) {
+	t.Parallel()
+
+	for _, tag := range []string{"critical", "tier:critical", "media/video", "日本"} {
+		require.NoError(t, ValidateTag(tag), tag)
+	}
+
+	for _, tag := range []string{"", "tier critical", "a,b", "tab\there"} {
+		require.ErrorIs(t, ValidateTag(tag), ErrInvalidTag, tag)
+	}
+}
+
+// Expectation: Only tags containing all the wanted tags should match.
+func Test_HasTags_Success(t *testing.T) {
+	t.Parallel()
+
+	tags := []string{"media:video", "tier:critical"}
+
+	require.True(t, HasTags(tags, nil))
+	require.True(t, HasTags(nil, nil))
+	require.True(t, HasTags(tags, []string{"tier:critical"}))
+	require.True(t, HasTags(tags, []string{"tier:critical", "media:video"}))
+	require.False(t, HasTags(tags, []string{"tier:critical", "media:audio"}))
+	require.False(t, HasTags(tags, []string{"Tier:Critical"}))
+	require.False(t, HasTags(nil, []string{"tier:critical"}))
+}
@@ -0,0 +1,286 @@
+package selftest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/create"
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/repair"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/desertwitch/par2cron/internal/verify"
+	"github.com/spf13/afero"
+)
+
+const (
+	scratchPrefix  string = "par2cron-selftest-"
+	markerFile     string = "_par2cron"
+	sampleFiles    int    = 4
+	sampleFileSize int    = 64 * 1024
+
+	StageGenerate string = "generate"
+	StageCreate   string = "create"
+	StageCorrupt  string = "corrupt"
+	StageVerify   string = "verify"
+	StageRepair   string = "repair"
+	StageReverify string = "re-verify"
+)
+
+var (
+	// defaultPar2Args leave enough headroom for the deliberate corruption.
+	defaultPar2Args = []string{"-r10"}
+
+	errStageFailed = errors.New("stage failed")
+	errCleanResult = errors.New("clean result")
+)
+
+type Options struct {
+	Par2Args []string
+}
+
+func (o *Options) SetPar2Args(args []string) {
+	o.Par2Args = slices.Clone(args)
+}
+
+type Service struct {
+	fsys afero.Fs
+
+	log      *logging.Logger
+	creator  *create.Service
+	verifier *verify.Service
+	repairer *repair.Service
+}
+
+func NewService(
+	fsys afero.Fs,
+	log *logging.Logger,
+	runner schema.CommandRunner,
+	bundler schema.BundleHandler,
+	par2er schema.Par2Handler,
+	cacher schema.CacheHandler,
+) *Service {
+	return &Service{
+		fsys:     fsys,
+		log:      log.With("op", "self-test"),
+		creator:  create.NewService(fsys, log, runner, bundler, par2er, cacher),
+		verifier: verify.NewService(fsys, log, runner, bundler, cacher),
+		repairer: repair.NewService(fsys, log, runner, bundler, cacher),
+	}
+}
+
+// StageResult is the outcome of a single stage of the self-test.
+type StageResult struct {
+	Name     string
+	Passed   bool
+	Skipped  bool
+	Duration time.Duration
+	Err      error
+}
+
+func (r StageResult) Status() string {
+	switch {
+	case r.Skipped:
+		return "SKIP"
+	case r.Passed:
+		return "PASS"
+	default:
+		return "FAIL"
+	}
+}
+
+type stage struct {
+	name string
+	fn   func(ctx context.Context, dir string) error
+}
+
+// SelfTest creates a scratch directory within baseDir, where it generates
+// sample files, creates a PAR2 set, corrupts a file, verifies (expecting a
+// repairable result), repairs and re-verifies (expecting a clean result).
+// Once a stage fails, all remaining stages are skipped. The scratch directory
+// is always removed before returning.
+func (prog *Service) SelfTest(ctx context.Context, baseDir string, opts Options) ([]StageResult, error) {
+	dir, err := afero.TempDir(prog.fsys, baseDir, scratchPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer func() {
+		if err := prog.fsys.RemoveAll(dir); err != nil {
+			prog.log.Warn("Failed to remove scratch directory", "path", dir, "error", err)
+		}
+	}()
+
+	prog.log.Info("Running self-test in scratch directory...", "path", dir)
+
+	stages := []stage{
+		{StageGenerate, prog.generateFiles},
+		{StageCreate, func(ctx context.Context, dir string) error { return prog.runCreate(ctx, dir, opts) }},
+		{StageCorrupt, prog.corruptFile},
+		{StageVerify, func(ctx context.Context, dir string) error { return prog.runVerify(ctx, dir, true) }},
+		{StageRepair, prog.runRepair},
+		{StageReverify, func(ctx context.Context, dir string) error { return prog.runVerify(ctx, dir, false) }},
+	}
+
+	results := make([]StageResult, 0, len(stages))
+
+	var failed error
+	for _, st := range stages {
+		if failed != nil {
+			results = append(results, StageResult{Name: st.name, Skipped: true})
+
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return results, fmt.Errorf("context error: %w", err)
+		}
+
+		start := time.Now()
+		err := st.fn(ctx, dir)
+		res := StageResult{Name: st.name, Passed: err == nil, Duration: time.Since(start), Err: err}
+		results = append(results, res)
+
+		if err != nil {
+			prog.log.Error("Self-test stage failed", "stage", st.name, "duration", res.Duration.String(), "error", err)
+			failed = fmt.Errorf("%w: %s: %w", errStageFailed, st.name, err)
+
+			continue
+		}
+
+		prog.log.Info("Self-test stage passed", "stage", st.name, "duration", res.Duration.String())
+	}
+
+	prog.printReport(results)
+
+	if failed != nil {
+		return results, fmt.Errorf("%w: %w", schema.ErrExitPartialFailure, failed)
+	}
+
+	return results, nil
+}
+
+func (prog *Service) printReport(results []StageResult) {
+	fmt.Fprintf(prog.log.Options.Stdout, "%-10s %-6s %s\n", "STAGE", "RESULT", "DURATION")
+
+	for _, res := range results {
+		duration := "-"
+		if !res.Skipped {
+			duration = res.Duration.Round(time.Millisecond).String()
+		}
+
+		fmt.Fprintf(prog.log.Options.Stdout, "%-10s %-6s %s\n", res.Name, res.Status(), duration)
+	}
+}
+
+func (prog *Service) generateFiles(_ context.Context, dir string) error {
+	rng := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0)) //nolint:gosec
+
+	for i := range sampleFiles {
+		data := make([]byte, sampleFileSize)
+		for j := range data {
+			data[j] = byte(rng.UintN(256)) //nolint:mnd
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("sample%d.bin", i+1))
+		if err := afero.WriteFile(prog.fsys, path, data, util.UmaskFilePerm); err != nil {
+			return fmt.Errorf("failed to write sample: %w", err)
+		}
+	}
+
+	if err := afero.WriteFile(prog.fsys, filepath.Join(dir, markerFile), nil, util.UmaskFilePerm); err != nil {
+		return fmt.Errorf("failed to write marker: %w", err)
+	}
+
+	return nil
+}
+
+func (prog *Service) runCreate(ctx context.Context, dir string, opts Options) error {
+	par2Args := defaultPar2Args
+	if len(opts.Par2Args) > 0 {
+		par2Args = opts.Par2Args
+	}
+
+	copts := create.Options{Par2Args: slices.Clone(par2Args), Par2Glob: "*"}
+	_ = copts.Par2Mode.Set(schema.CreateFolderMode)
+
+	result, err := prog.creator.Create(ctx, []string{dir}, copts)
+	if err != nil {
+		return fmt.Errorf("failed to create: %w", err)
+	}
+	if result.Success != 1 {
+		return fmt.Errorf("expected 1 created set, got %d", result.Success)
+	}
+
+	return nil
+}
+
+func (prog *Service) corruptFile(_ context.Context, dir string) error {
+	path := filepath.Join(dir, "sample1.bin")
+
+	f, err := prog.fsys.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open sample: %w", err)
+	}
+	defer f.Close()
+
+	garbage := make([]byte, 512) //nolint:mnd
+	for i := range garbage {
+		garbage[i] = 0xFF
+	}
+
+	if _, err := f.WriteAt(garbage, int64(sampleFileSize/2)); err != nil { //nolint:mnd
+		return fmt.Errorf("failed to corrupt sample: %w", err)
+	}
+
+	return nil
+}
+
+func (prog *Service) runVerify(ctx context.Context, dir string, expectRepairable bool) error {
+	result, err := prog.verifier.Verify(ctx, []string{dir}, verify.Options{})
+
+	if expectRepairable {
+		if !errors.Is(err, schema.ErrExitRepairable) {
+			return fmt.Errorf("expected a repairable result, got: %w", errOrClean(err))
+		}
+		if result.Error != 1 {
+			return fmt.Errorf("expected 1 corrupted set, got %d", result.Error)
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("expected a clean result, got: %w", err)
+	}
+	if result.Success != 1 {
+		return fmt.Errorf("expected 1 healthy set, got %d", result.Success)
+	}
+
+	return nil
+}
+
+func (prog *Service) runRepair(ctx context.Context, dir string) error {
+	result, err := prog.repairer.Repair(ctx, []string{dir}, repair.Options{PurgeBackups: true})
+	if err != nil {
+		return fmt.Errorf("failed to repair: %w", err)
+	}
+	if result.Success != 1 {
+		return fmt.Errorf("expected 1 repaired set, got %d", result.Success)
+	}
+
+	return nil
+}
+
+func errOrClean(err error) error {
+	if err == nil {
+		return errCleanResult
+	}
+
+	return err
+}
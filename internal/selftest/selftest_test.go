@@ -0,0 +1,186 @@
+package selftest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// newSimulatedRunner returns a runner that simulates par2 for the self-test:
+// "create" writes the PAR2 index file, "verify" reports the given exit codes
+// (one per call, in order) and "repair" succeeds.
+func newSimulatedRunner(t *testing.T, fs afero.Fs, verifyCodes ...int) *testutil.MockRunner {
+	t.Helper()
+
+	var verifyCalls int
+
+	return &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			switch args[0] {
+			case "create":
+				par2Path := filepath.Join(workingDir, filepath.Base(workingDir)+schema.Par2Extension)
+				require.NoError(t, afero.WriteFile(fs, par2Path, []byte("par2data"), 0o644))
+
+			case "verify":
+				code := verifyCodes[min(verifyCalls, len(verifyCodes)-1)]
+				verifyCalls++
+
+				if code != 0 {
+					return testutil.CreateExitError(t, ctx, code)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func newTestService(t *testing.T, fs afero.Fs, runner schema.CommandRunner, stdout io.Writer) *Service {
+	t.Helper()
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: stdout,
+		Stderr: io.Discard,
+	}
+
+	return NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &util.Par2Handler{}, &testutil.MockCacheHandler{})
+}
+
+func requireScratchRemoved(t *testing.T, fs afero.Fs, baseDir string) {
+	t.Helper()
+
+	entries, err := afero.ReadDir(fs, baseDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+// Expectation: All stages should pass when par2 behaves as expected and the scratch directory be removed.
+func Test_Service_SelfTest_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/tmp", 0o755))
+
+	var stdout testutil.SafeBuffer
+	prog := newTestService(t, fs, newSimulatedRunner(t, fs, schema.Par2ExitCodeRepairPossible, schema.Par2ExitCodeSuccess), &stdout)
+
+	results, err := prog.SelfTest(t.Context(), "/tmp", Options{})
+
+	require.NoError(t, err)
+	require.Len(t, results, 6)
+	for _, res := range results {
+		require.True(t, res.Passed, res.Name)
+		require.Equal(t, "PASS", res.Status())
+	}
+	require.Contains(t, stdout.String(), StageReverify)
+	requireScratchRemoved(t, fs, "/tmp")
+}
+
+// Expectation: A failing stage should skip all remaining stages and return a partial failure.
+func Test_Service_SelfTest_CreateFails_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/tmp", 0o755))
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			return errors.New("par2 exploded")
+		},
+	}
+
+	var stdout testutil.SafeBuffer
+	prog := newTestService(t, fs, runner, &stdout)
+
+	results, err := prog.SelfTest(t.Context(), "/tmp", Options{})
+
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+	require.ErrorIs(t, err, errStageFailed)
+	require.Len(t, results, 6)
+
+	require.True(t, results[0].Passed)
+	require.Equal(t, StageCreate, results[1].Name)
+	require.Equal(t, "FAIL", results[1].Status())
+	require.Error(t, results[1].Err)
+	for _, res := range results[2:] {
+		require.True(t, res.Skipped, res.Name)
+	}
+	require.Contains(t, stdout.String(), "SKIP")
+	requireScratchRemoved(t, fs, "/tmp")
+}
+
+// Expectation: The verify stage should fail when the corruption goes undetected.
+func Test_Service_SelfTest_CorruptionUndetected_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/tmp", 0o755))
+
+	prog := newTestService(t, fs, newSimulatedRunner(t, fs, schema.Par2ExitCodeSuccess), io.Discard)
+
+	results, err := prog.SelfTest(t.Context(), "/tmp", Options{})
+
+	require.ErrorIs(t, err, errCleanResult)
+	require.Equal(t, StageVerify, results[3].Name)
+	require.False(t, results[3].Passed)
+	require.True(t, results[4].Skipped)
+	requireScratchRemoved(t, fs, "/tmp")
+}
+
+// Expectation: The re-verify stage should fail when the repair did not restore the set.
+func Test_Service_SelfTest_RepairIneffective_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/tmp", 0o755))
+
+	prog := newTestService(t, fs, newSimulatedRunner(t, fs, schema.Par2ExitCodeRepairPossible), io.Discard)
+
+	results, err := prog.SelfTest(t.Context(), "/tmp", Options{})
+
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+	require.True(t, results[4].Passed)
+	require.Equal(t, StageReverify, results[5].Name)
+	require.False(t, results[5].Passed)
+	requireScratchRemoved(t, fs, "/tmp")
+}
+
+// Expectation: The custom par2 arguments should be passed to the creation.
+func Test_Service_SelfTest_Par2Args_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/tmp", 0o755))
+
+	var createArgs []string
+	sim := newSimulatedRunner(t, fs, schema.Par2ExitCodeRepairPossible, schema.Par2ExitCodeSuccess)
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			if args[0] == "create" {
+				createArgs = args
+			}
+
+			return sim.RunFunc(ctx, cmd, args, workingDir, stdout, stderr)
+		},
+	}
+
+	prog := newTestService(t, fs, runner, io.Discard)
+
+	opts := Options{}
+	opts.SetPar2Args([]string{"-r15"})
+	_, err := prog.SelfTest(t.Context(), "/tmp", opts)
+
+	require.NoError(t, err)
+	require.Contains(t, createArgs, "-r15")
+	require.NotContains(t, createArgs, "-r10")
+}
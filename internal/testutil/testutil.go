@@ -9,6 +9,7 @@ import (
 	"io/fs"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -100,6 +101,39 @@ func (f *FailingStatFs) Stat(name string) (os.FileInfo, error) {
 	return f.Fs.Stat(name)
 }
 
+// CaseInsensitiveFs wraps an afero.Fs to simulate a case-insensitive (but
+// case-preserving) filesystem, resolving names to an entry of another case.
+type CaseInsensitiveFs struct {
+	afero.Fs
+}
+
+func (f *CaseInsensitiveFs) resolve(name string) string {
+	if _, err := f.Fs.Stat(name); err == nil {
+		return name
+	}
+
+	dir, base := filepath.Split(name)
+	names, err := afero.ReadDir(f.Fs, dir)
+	if err != nil {
+		return name
+	}
+	for _, fi := range names {
+		if strings.EqualFold(fi.Name(), base) {
+			return filepath.Join(dir, fi.Name())
+		}
+	}
+
+	return name
+}
+
+func (f *CaseInsensitiveFs) Stat(name string) (os.FileInfo, error) {
+	return f.Fs.Stat(f.resolve(name))
+}
+
+func (f *CaseInsensitiveFs) Open(name string) (afero.File, error) {
+	return f.Fs.Open(f.resolve(name))
+}
+
 type FailingRenameFs struct {
 	afero.Fs
 
@@ -164,6 +198,32 @@ func CreateExitError(t *testing.T, ctx context.Context, code int) error {
 	return cmd.Run()
 }
 
+// MockReadOnlyChecker is a mock implementation of schema.ReadOnlyChecker.
+type MockReadOnlyChecker struct {
+	IsReadOnlyFunc func(path string) (bool, error)
+}
+
+func (m *MockReadOnlyChecker) IsReadOnly(path string) (bool, error) {
+	if m.IsReadOnlyFunc != nil {
+		return m.IsReadOnlyFunc(path)
+	}
+
+	return false, nil
+}
+
+// MockCacheDropper is a mock implementation of schema.CacheDropper.
+type MockCacheDropper struct {
+	DropCacheFunc func(path string) error
+}
+
+func (m *MockCacheDropper) DropCache(path string) error {
+	if m.DropCacheFunc != nil {
+		return m.DropCacheFunc(path)
+	}
+
+	return nil
+}
+
 // MockPar2Handler is a mock implementation of schema.Par2Handler.
 type MockPar2Handler struct {
 	ParseFunc     func(r io.ReadSeeker, checkMD5 bool) ([]par2.Set, error)
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/spf13/afero"
+)
+
+// DereferencePar2Path returns the path of the PAR2 set stored elsewhere that
+// a manifest at manifestPath records (par2_location), resolved against the
+// directory of the manifest, or an empty path if it does not record one.
+func DereferencePar2Path(manifestPath string, mf *schema.Manifest) string {
+	if mf == nil || mf.Par2Location == "" {
+		return ""
+	}
+
+	loc := filepath.FromSlash(mf.Par2Location)
+	if !filepath.IsAbs(loc) {
+		loc = filepath.Join(filepath.Dir(manifestPath), loc)
+	}
+
+	return filepath.Clean(loc)
+}
+
+// ReadDereferencedManifest reads the par2cron manifest at manifestPath (under
+// its lock) of a PAR2 set stored elsewhere, returning the manifest and the
+// path of that PAR2 set. An empty path is returned for a manifest that has its
+// PAR2 set next to it, or that does not record (or is not) a valid manifest
+// (within maxSize, see [UnmarshalManifest]).
+func ReadDereferencedManifest(fsys afero.Fs, manifestPath string, names SidecarNames, maxSize int64) (string, *schema.Manifest, error) {
+	for _, par2Path := range names.Par2Paths(manifestPath) {
+		if _, err := LstatIfPossible(fsys, par2Path); !errors.Is(err, fs.ErrNotExist) {
+			return "", nil, nil
+		}
+	}
+
+	unlock, err := AcquireLock(fsys, names.LockPath(names.Par2Paths(manifestPath)[0]), false)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to lock: %w", err)
+	}
+	data, err := afero.ReadFile(fsys, manifestPath)
+	unlock()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read: %w", err)
+	}
+
+	mf, err := UnmarshalManifest(data, maxSize)
+	if err != nil {
+		return "", nil, nil //nolint:nilerr
+	}
+
+	return DereferencePar2Path(manifestPath, mf), mf, nil
+}
+
+// SidecarPaths returns the paths of the manifest and lock file of the PAR2 set
+// of a job, which for a dereferenced manifest (of a PAR2 set stored elsewhere)
+// are those next to its protected files.
+func (n SidecarNames) SidecarPaths(meta *schema.JobMeta) (string, string) {
+	if meta.ManifestPath != "" {
+		return meta.ManifestPath, n.LockPath(n.Par2Paths(meta.ManifestPath)[0])
+	}
+
+	return n.ManifestPath(meta.Par2Path), n.LockPath(meta.Par2Path)
+}
@@ -0,0 +1,55 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: The recorded location should be resolved against the directory of the manifest unless absolute.
+func Test_DereferencePar2Path_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		location string
+		want     string
+	}{
+		{"none", "", ""},
+		{"absolute", "/parity/movies/set.par2", "/parity/movies/set.par2"},
+		{"relative", "../../parity/movies/set.par2", "/parity/movies/set.par2"},
+		{"uncleaned", "/parity//movies/./set.par2", "/parity/movies/set.par2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mf := &schema.Manifest{Par2Location: tt.location}
+			require.Equal(t, tt.want, DereferencePar2Path("/data/movies/set.par2.json", mf))
+		})
+	}
+}
+
+// Expectation: Only a manifest without its PAR2 set next to it should be dereferenced.
+func Test_ReadDereferencedManifest_Success(t *testing.T) {
+	t.Parallel()
+
+	fsys := afero.NewMemMapFs()
+	mfData := []byte(`{"name":"set.par2","par2_location":"/parity/set.par2"}`)
+	require.NoError(t, afero.WriteFile(fsys, "/data/a/set.par2.json", mfData, 0o644))
+	require.NoError(t, afero.WriteFile(fsys, "/data/b/set.par2.json", mfData, 0o644))
+	require.NoError(t, afero.WriteFile(fsys, "/data/b/set.par2", []byte("par2"), 0o644))
+
+	par2Path, mf, err := ReadDereferencedManifest(fsys, "/data/a/set.par2.json", SidecarNames{}, 0)
+	require.NoError(t, err)
+	require.Equal(t, "/parity/set.par2", par2Path)
+	require.Equal(t, "set.par2", mf.Name)
+
+	par2Path, mf, err = ReadDereferencedManifest(fsys, "/data/b/set.par2.json", SidecarNames{}, 0)
+	require.NoError(t, err)
+	require.Empty(t, par2Path)
+	require.Nil(t, mf)
+}
@@ -2,8 +2,10 @@ package util
 
 import (
 	"errors"
+	"fmt"
 	"os/exec"
 
+	"github.com/desertwitch/par2cron/internal/flags"
 	"github.com/desertwitch/par2cron/internal/schema"
 )
 
@@ -38,6 +40,65 @@ func AsExitCode(err error) *int {
 	return nil
 }
 
+// Par2ExitClass returns the classification of a par2 exit code as verification
+// result, as mapped by the user or else as by par2cmdline, or an empty string for
+// the exit codes that are no verification result (see [Par2ExitError]).
+func Par2ExitClass(code int, mapped flags.Par2ExitCodes) string {
+	if class, ok := mapped.Value[code]; ok && code != schema.Par2ExitCodeSuccess {
+		return class
+	}
+
+	switch code {
+	case schema.Par2ExitCodeSuccess:
+		return schema.Par2ExitClassSuccess
+	case schema.Par2ExitCodeRepairPossible:
+		return schema.Par2ExitClassRepairable
+	case schema.Par2ExitCodeRepairImpossible:
+		return schema.Par2ExitClassUnrepairable
+	case schema.Par2ExitCodeInvalidArguments:
+		return schema.Par2ExitClassUsageError
+	default:
+		return ""
+	}
+}
+
+// Par2ExitError returns the classification of a par2 exit code that is not
+// a verification result, or nil for these (and undocumented) exit codes.
+// Usage errors are also a bad invocation, as the par2 arguments are wrong.
+func Par2ExitError(code int) error {
+	switch code {
+	case schema.Par2ExitCodeInvalidArguments:
+		return fmt.Errorf("%w: %w", schema.ErrExitBadInvocation, schema.ErrPar2Usage)
+	case schema.Par2ExitCodeCriticalData:
+		return schema.ErrPar2CriticalData
+	case schema.Par2ExitCodeRepairFailed:
+		return schema.ErrPar2RepairFailed
+	case schema.Par2ExitCodeFileIOError:
+		return schema.ErrPar2FileIO
+	case schema.Par2ExitCodeLogicError, schema.Par2ExitCodeMemoryError:
+		return schema.ErrPar2Internal
+	default:
+		return nil
+	}
+}
+
+// IsPar2Crash returns if a par2 failure is no outcome of par2 processing the
+// PAR2 set, but of it having been killed by a signal (such as when crashing)
+// or having failed on an internal (logic or memory) error. Failures without an
+// exit code (such as par2 not being found) are not considered crashes.
+func IsPar2Crash(err error) bool {
+	code := AsExitCode(err)
+	if code == nil {
+		return false
+	}
+
+	if *code < 0 {
+		return true // Killed by a signal.
+	}
+
+	return errors.Is(Par2ExitError(*code), schema.ErrPar2Internal)
+}
+
 func OnlyContains(err, sentinel error) bool {
 	if err == nil {
 		return false
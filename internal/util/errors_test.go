@@ -6,7 +6,9 @@ import (
 	"os/exec"
 	"testing"
 
+	"github.com/desertwitch/par2cron/internal/flags"
 	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,6 +32,99 @@ func Test_AsExitCode_NonExitError_Error(t *testing.T) {
 	require.Nil(t, code)
 }
 
+// Expectation: Only signals and internal errors of par2 should be considered crashes.
+func Test_IsPar2Crash_Success(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsPar2Crash(testutil.CreateExitError(t, t.Context(), schema.Par2ExitCodeLogicError)))
+	require.True(t, IsPar2Crash(testutil.CreateExitError(t, t.Context(), schema.Par2ExitCodeMemoryError)))
+
+	killed := exec.CommandContext(t.Context(), "sh", "-c", "kill -SEGV $$").Run()
+	require.True(t, IsPar2Crash(killed))
+
+	require.False(t, IsPar2Crash(nil))
+	require.False(t, IsPar2Crash(exec.ErrNotFound))
+	require.False(t, IsPar2Crash(testutil.CreateExitError(t, t.Context(), schema.Par2ExitCodeRepairPossible)))
+	require.False(t, IsPar2Crash(testutil.CreateExitError(t, t.Context(), schema.Par2ExitCodeFileIOError)))
+}
+
+// Expectation: The par2 exit codes should be classified, except for verification results.
+func Test_Par2ExitError_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code     int
+		expected error
+	}{
+		{schema.Par2ExitCodeSuccess, nil},
+		{schema.Par2ExitCodeRepairPossible, nil},
+		{schema.Par2ExitCodeRepairImpossible, nil},
+		{schema.Par2ExitCodeInvalidArguments, schema.ErrPar2Usage},
+		{schema.Par2ExitCodeCriticalData, schema.ErrPar2CriticalData},
+		{schema.Par2ExitCodeRepairFailed, schema.ErrPar2RepairFailed},
+		{schema.Par2ExitCodeFileIOError, schema.ErrPar2FileIO},
+		{schema.Par2ExitCodeLogicError, schema.ErrPar2Internal},
+		{schema.Par2ExitCodeMemoryError, schema.ErrPar2Internal},
+		{99, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("code %d", tt.code), func(t *testing.T) {
+			t.Parallel()
+
+			err := Par2ExitError(tt.code)
+			if tt.expected == nil {
+				require.NoError(t, err)
+
+				return
+			}
+			require.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+// Expectation: The par2 exit codes should be classified as mapped, or else as by par2cmdline.
+func Test_Par2ExitClass_Table(t *testing.T) {
+	t.Parallel()
+
+	var mapped flags.Par2ExitCodes
+	require.NoError(t, mapped.Set("1=unrepairable"))
+	require.NoError(t, mapped.Set("4=repairable"))
+	mapped.Value[schema.Par2ExitCodeSuccess] = schema.Par2ExitClassUnrepairable
+
+	tests := []struct {
+		code     int
+		mapped   flags.Par2ExitCodes
+		expected string
+	}{
+		{schema.Par2ExitCodeSuccess, flags.Par2ExitCodes{}, schema.Par2ExitClassSuccess},
+		{schema.Par2ExitCodeRepairPossible, flags.Par2ExitCodes{}, schema.Par2ExitClassRepairable},
+		{schema.Par2ExitCodeRepairImpossible, flags.Par2ExitCodes{}, schema.Par2ExitClassUnrepairable},
+		{schema.Par2ExitCodeInvalidArguments, flags.Par2ExitCodes{}, schema.Par2ExitClassUsageError},
+		{schema.Par2ExitCodeCriticalData, flags.Par2ExitCodes{}, ""},
+		{schema.Par2ExitCodeSuccess, mapped, schema.Par2ExitClassSuccess},
+		{schema.Par2ExitCodeRepairPossible, mapped, schema.Par2ExitClassUnrepairable},
+		{schema.Par2ExitCodeCriticalData, mapped, schema.Par2ExitClassRepairable},
+		{schema.Par2ExitCodeRepairImpossible, mapped, schema.Par2ExitClassUnrepairable},
+		{99, mapped, ""},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, Par2ExitClass(tt.code, tt.mapped), tt.code)
+	}
+}
+
+// Expectation: A par2 usage error should also be a bad invocation.
+func Test_Par2ExitError_UsageIsBadInvocation_Success(t *testing.T) {
+	t.Parallel()
+
+	err := Par2ExitError(schema.Par2ExitCodeInvalidArguments)
+
+	require.ErrorIs(t, err, schema.ErrExitBadInvocation)
+	require.Equal(t, schema.ExitCodeBadInvocation, schema.ExitCodeFor(err))
+	require.Equal(t, schema.ExitCodeUnclassified, schema.ExitCodeFor(Par2ExitError(schema.Par2ExitCodeFileIOError)))
+}
+
 // Expectation: The highest error should be returned.
 func Test_HighestError_Table_Error(t *testing.T) {
 	t.Parallel()
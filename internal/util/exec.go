@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"syscall"
 
 	"github.com/desertwitch/par2cron/internal/schema"
@@ -33,8 +34,25 @@ func WithCgroup(path string) RunnerOption {
 	}
 }
 
+// WithEnv sets environment variables (in the KEY=VALUE form) for all commands,
+// in addition to (or overriding) those inherited from the parent process.
+func WithEnv(env []string) RunnerOption {
+	return func(r *CtxRunner) error {
+		r.Env = slices.Clone(env)
+
+		return nil
+	}
+}
+
 type CtxRunner struct {
 	CgroupFile *os.File
+
+	// Env is appended to the environment inherited from the parent process.
+	Env []string
+
+	// IONicePath and IONiceArgs are set to wrap all commands with "ionice".
+	IONicePath string
+	IONiceArgs []string
 }
 
 func NewCtxRunner(opts ...RunnerOption) (*CtxRunner, error) {
@@ -58,12 +76,17 @@ func (r *CtxRunner) Close() error {
 }
 
 func (r *CtxRunner) Run(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+	cmd, args = r.wrapCommand(cmd, args)
 	c := exec.CommandContext(ctx, cmd, args...)
 
 	c.Dir = workingDir
 	c.Stdout = stdout
 	c.Stderr = stderr
 
+	if len(r.Env) > 0 {
+		c.Env = append(os.Environ(), r.Env...)
+	}
+
 	c.Cancel = func() error {
 		return c.Process.Signal(os.Interrupt)
 	}
@@ -78,3 +101,18 @@ func (r *CtxRunner) Run(ctx context.Context, cmd string, args []string, workingD
 
 	return c.Run() //nolint:wrapcheck
 }
+
+// wrapCommand returns the command and arguments to execute, wrapped with
+// "ionice" if configured, which then execs the command with the same exit code.
+func (r *CtxRunner) wrapCommand(cmd string, args []string) (string, []string) {
+	if r.IONicePath == "" {
+		return cmd, args
+	}
+
+	wrapped := make([]string, 0, len(r.IONiceArgs)+2+len(args)) //nolint:mnd
+	wrapped = append(wrapped, r.IONiceArgs...)
+	wrapped = append(wrapped, "--", cmd)
+	wrapped = append(wrapped, args...)
+
+	return r.IONicePath, wrapped
+}
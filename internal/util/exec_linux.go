@@ -0,0 +1,40 @@
+//go:build linux
+
+package util
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+)
+
+// WithIOThrottle runs all commands with the given I/O scheduling class (and
+// priority level within the class), using "ionice" as a wrapper around them.
+func WithIOThrottle(class string, level int) RunnerOption {
+	return func(r *CtxRunner) error {
+		var args []string
+
+		switch class {
+		case "", schema.IOThrottleNone:
+			return nil
+		case schema.IOThrottleIdle:
+			args = []string{"-c", "3"}
+		case schema.IOThrottleBestEffort:
+			args = []string{"-c", "2", "-n", strconv.Itoa(level)}
+		default:
+			return fmt.Errorf("%w: %q", schema.ErrUnsupportedIOThrottle, class)
+		}
+
+		path, err := exec.LookPath("ionice")
+		if err != nil {
+			return fmt.Errorf("failed to find ionice: %w", err)
+		}
+
+		r.IONicePath = path
+		r.IONiceArgs = args
+
+		return nil
+	}
+}
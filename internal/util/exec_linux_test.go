@@ -0,0 +1,83 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: The runner should wrap commands with the "ionice" arguments of the class.
+func Test_NewCtxRunner_WithIOThrottle_Success(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("ionice"); err != nil {
+		t.Skip("ionice not found")
+	}
+
+	tests := []struct {
+		class string
+		level int
+		args  []string
+	}{
+		{schema.IOThrottleIdle, 0, []string{"-c", "3"}},
+		{schema.IOThrottleBestEffort, 5, []string{"-c", "2", "-n", "5"}},
+	}
+
+	for _, tt := range tests {
+		runner, err := NewCtxRunner(WithIOThrottle(tt.class, tt.level))
+		require.NoError(t, err)
+		require.NotEmpty(t, runner.IONicePath)
+		require.Equal(t, tt.args, runner.IONiceArgs)
+
+		cmd, args := runner.wrapCommand("par2", []string{"verify", "-q", "test.par2"})
+		require.Equal(t, runner.IONicePath, cmd)
+		require.Equal(t, append(append(tt.args, "--", "par2"), "verify", "-q", "test.par2"), args)
+	}
+}
+
+// Expectation: The runner should not wrap commands without (or with a "none") class.
+func Test_NewCtxRunner_WithIOThrottle_None_Success(t *testing.T) {
+	t.Parallel()
+
+	runner, err := NewCtxRunner(WithIOThrottle("", 0), WithIOThrottle(schema.IOThrottleNone, 0))
+	require.NoError(t, err)
+	require.Empty(t, runner.IONicePath)
+
+	cmd, args := runner.wrapCommand("par2", []string{"verify"})
+	require.Equal(t, "par2", cmd)
+	require.Equal(t, []string{"verify"}, args)
+}
+
+// Expectation: The runner should return an error on an unknown class.
+func Test_NewCtxRunner_WithIOThrottle_Unknown_Error(t *testing.T) {
+	t.Parallel()
+
+	runner, err := NewCtxRunner(WithIOThrottle("realtime", 0))
+	require.ErrorIs(t, err, schema.ErrUnsupportedIOThrottle)
+	require.Nil(t, runner)
+}
+
+// Expectation: The command should run with the class applied and its exit code preserved.
+func Test_CtxRunner_Run_WithIOThrottle_Success(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("ionice"); err != nil {
+		t.Skip("ionice not found")
+	}
+
+	runner, err := NewCtxRunner(WithIOThrottle(schema.IOThrottleBestEffort, 6))
+	require.NoError(t, err)
+
+	var stdout bytes.Buffer
+	err = runner.Run(t.Context(), "sh", []string{"-c", "ionice; exit 2"}, t.TempDir(), &stdout, io.Discard)
+
+	require.Equal(t, "best-effort: prio 6", strings.TrimSpace(stdout.String()))
+	code := AsExitCode(err)
+	require.NotNil(t, code)
+	require.Equal(t, 2, *code)
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package util
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+)
+
+// WithIOThrottle is only supported on Linux, returning an error otherwise.
+func WithIOThrottle(class string, _ int) RunnerOption {
+	return func(_ *CtxRunner) error {
+		if class == "" || class == schema.IOThrottleNone {
+			return nil
+		}
+
+		return fmt.Errorf("%w: not supported on %s", schema.ErrUnsupportedIOThrottle, runtime.GOOS)
+	}
+}
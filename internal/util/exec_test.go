@@ -74,6 +74,20 @@ func Test_CtxRunner_Run_Success(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// Expectation: The environment variables should reach the command, overriding inherited ones.
+func Test_CtxRunner_Run_WithEnv_Success(t *testing.T) {
+	t.Parallel()
+
+	runner, err := NewCtxRunner(WithEnv([]string{"PAR2CRON_TEST_A=a b", "HOME=/nonexistent", "PAR2CRON_TEST_B="}))
+	require.NoError(t, err)
+
+	var stdout testutil.SafeBuffer
+	err = runner.Run(t.Context(), "sh", []string{"-c", `printf '%s|%s|%s|%s' "$PAR2CRON_TEST_A" "$HOME" "${PAR2CRON_TEST_B-unset}" "${PATH:+path}"`}, t.TempDir(), &stdout, io.Discard)
+
+	require.NoError(t, err)
+	require.Equal(t, "a b|/nonexistent||path", stdout.String())
+}
+
 // Expectation: The runner should be respect the set working directory.
 func Test_CtxRunner_Run_WorkingDir(t *testing.T) {
 	t.Parallel()
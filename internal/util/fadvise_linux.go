@@ -0,0 +1,30 @@
+//go:build linux && !mips && !mipsle
+
+package util
+
+import (
+	"fmt"
+	"os"
+)
+
+// fadvDontNeed is the POSIX_FADV_DONTNEED advice, as taken by fadvise64(2).
+const fadvDontNeed = 4
+
+// CacheDropSupported is if [OSCacheDropper] can drop the page cache of files.
+const CacheDropSupported = true
+
+// dropPageCache advises the kernel to drop the cached pages of the file,
+// so that they are read from the storage medium again on the next read.
+func dropPageCache(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open: %w", err)
+	}
+	defer f.Close()
+
+	if err := fadvise(f.Fd(), fadvDontNeed); err != nil {
+		return fmt.Errorf("fadvise: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build linux
+
+package util
+
+import "syscall"
+
+// fadvise gives the advice for the whole file (offset and length of zero),
+// with both of these passed as 64-bit values split over two registers each.
+func fadvise(fd uintptr, advice int) error {
+	if _, _, errno := syscall.Syscall6(syscall.SYS_FADVISE64_64, fd, 0, 0, 0, 0, uintptr(advice)); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
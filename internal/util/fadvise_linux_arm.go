@@ -0,0 +1,15 @@
+//go:build linux
+
+package util
+
+import "syscall"
+
+// fadvise gives the advice for the whole file (offset and length of zero),
+// with the advice passed ahead of these to keep the 64-bit values aligned.
+func fadvise(fd uintptr, advice int) error {
+	if _, _, errno := syscall.Syscall6(syscall.SYS_ARM_FADVISE64_64, fd, uintptr(advice), 0, 0, 0, 0); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
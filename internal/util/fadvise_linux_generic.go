@@ -0,0 +1,14 @@
+//go:build linux && !386 && !arm && !mips && !mipsle
+
+package util
+
+import "syscall"
+
+// fadvise gives the advice for the whole file (offset and length of zero).
+func fadvise(fd uintptr, advice int) error {
+	if _, _, errno := syscall.Syscall6(syscall.SYS_FADVISE64, fd, 0, 0, uintptr(advice), 0, 0); errno != 0 {
+		return errno
+	}
+
+	return nil
+}
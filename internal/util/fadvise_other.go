@@ -0,0 +1,16 @@
+//go:build !linux || mips || mipsle
+
+package util
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CacheDropSupported is if [OSCacheDropper] can drop the page cache of files.
+const CacheDropSupported = false
+
+// dropPageCache is only supported on Linux (and not on 32-bit MIPS there).
+func dropPageCache(_ string) error {
+	return fmt.Errorf("dropping page cache: %w", errors.ErrUnsupported)
+}
@@ -11,14 +11,68 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"syscall"
+	"unicode"
 
 	"github.com/bmatcuk/doublestar/v4"
 	"github.com/desertwitch/par2cron/internal/bundle"
+	"github.com/desertwitch/par2cron/internal/flags"
 	"github.com/desertwitch/par2cron/internal/schema"
 	"github.com/spf13/afero"
 )
 
+// IsPaused returns if the pause (sentinel) file exists, which halts all work
+// of an operation (such as during a backup window), where an empty path (the
+// default) never pauses. A file that cannot be checked does not pause either.
+func IsPaused(fsys afero.Fs, pauseFile string) bool {
+	if pauseFile == "" {
+		return false
+	}
+
+	_, err := LstatIfPossible(fsys, pauseFile)
+
+	return err == nil
+}
+
+// IsCaseInsensitive probes if the filesystem holding the (existing) path is
+// case-insensitive, by looking up the path with the case of its base name
+// swapped, which is only found (without being listed in its directory) on a
+// case-insensitive filesystem. A base name without cased letters (or a path
+// that cannot be checked) is not conclusive and returns false.
+func IsCaseInsensitive(fsys afero.Fs, path string) bool {
+	dir, base := filepath.Split(path)
+
+	swapped := strings.Map(func(r rune) rune {
+		if unicode.IsUpper(r) {
+			return unicode.ToLower(r)
+		}
+
+		return unicode.ToUpper(r)
+	}, base)
+	if swapped == base {
+		return false
+	}
+
+	if _, err := fsys.Stat(filepath.Join(dir, swapped)); err != nil {
+		return false
+	}
+
+	f, err := fsys.Open(filepath.Clean(dir))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	names, err := f.Readdirnames(-1)
+	if err != nil {
+		return false
+	}
+
+	return !slices.Contains(names, swapped)
+}
+
 func LstatIfPossible(fsys afero.Fs, name string) (fs.FileInfo, error) {
 	if lstatter, ok := fsys.(afero.Lstater); ok {
 		fi, lstat, err := lstatter.LstatIfPossible(name)
@@ -87,7 +141,9 @@ func HashFile(fsys afero.Fs, filePath string) (string, error) {
 	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
-func WriteManifest(ctx context.Context, fsys afero.Fs, bundler schema.BundleHandler, path string, m *schema.Manifest, isBundle bool) error {
+// MarshalManifest stamps the manifest with the current program and manifest
+// versions and returns it marshalled, for all writes of manifests to go through.
+func MarshalManifest(m *schema.Manifest) ([]byte, error) {
 	// Update versions here, as we un- and re-marshalled to a possibly
 	// new manifest format (adding new fields and dropping old fields).
 	m.ProgramVersion = schema.ProgramVersion
@@ -95,7 +151,53 @@ func WriteManifest(ctx context.Context, fsys afero.Fs, bundler schema.BundleHand
 
 	data, err := json.MarshalIndent(m, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to marshal: %w", err)
+		return nil, fmt.Errorf("failed to marshal: %w", err)
+	}
+
+	return data, nil
+}
+
+// UnmarshalManifest unmarshals and validates a manifest (see [schema.Manifest.Validate]),
+// for all reads of manifests to go through, as their content is not to be trusted.
+// A rolling window of verification durations beyond its bound is cut to its newest.
+// A manifest larger than maxSize (in bytes, 0 for no limit) is refused before
+// parsing, guarding against pathologically grown or corrupted manifests.
+func UnmarshalManifest(data []byte, maxSize int64) (*schema.Manifest, error) {
+	if maxSize > 0 && int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: %s exceeds --max-manifest-size of %s",
+			schema.ErrManifestTooLarge, FmtBytes(int64(len(data))), FmtBytes(maxSize))
+	}
+
+	mf := &schema.Manifest{}
+	if err := json.Unmarshal(data, mf); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal: %w", err)
+	}
+
+	if err := mf.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate: %w", err)
+	}
+
+	if v := mf.Verification; v != nil && len(v.RecentDurations) > schema.MaxRecentDurations {
+		v.RecentDurations = slices.Clone(v.RecentDurations[len(v.RecentDurations)-schema.MaxRecentDurations:])
+	}
+
+	return mf, nil
+}
+
+// ManifestInvalidReason returns the reason code of a manifest refused by
+// [UnmarshalManifest], telling an oversized manifest apart from an invalid one.
+func ManifestInvalidReason(err error) string {
+	if errors.Is(err, schema.ErrManifestTooLarge) {
+		return schema.ReasonManifestTooLarge
+	}
+
+	return schema.ReasonManifestInvalid
+}
+
+func WriteManifest(ctx context.Context, fsys afero.Fs, bundler schema.BundleHandler, path string, m *schema.Manifest, isBundle bool) error {
+	data, err := MarshalManifest(m)
+	if err != nil {
+		return err
 	}
 
 	if !isBundle {
@@ -118,6 +220,100 @@ func WriteManifest(ctx context.Context, fsys afero.Fs, bundler schema.BundleHand
 	return nil
 }
 
+// CorrectManifestName sets the name stored in the manifest of the PAR2 set at
+// par2Path (an index file or a bundle) to the given name. The manifest is read
+// anew and written while holding the lock, so that concurrent updates to it are
+// never discarded.
+func CorrectManifestName(ctx context.Context, fsys afero.Fs, bundler schema.BundleHandler, par2Path string, name string, isBundle bool, names SidecarNames) error {
+	manifestPath, lockPath := par2Path, par2Path
+	if !isBundle {
+		manifestPath, lockPath = names.ManifestPath(par2Path), names.LockPath(par2Path)
+	}
+
+	unlock, err := AcquireLock(fsys, lockPath, false)
+	if err != nil {
+		return fmt.Errorf("failed to lock: %w", err)
+	}
+	defer unlock()
+
+	var data []byte
+	if !isBundle {
+		if data, err = afero.ReadFile(fsys, manifestPath); err != nil {
+			return fmt.Errorf("failed to read: %w", err)
+		}
+	} else {
+		bun, err := bundler.Open(ctx, fsys, manifestPath)
+		if err != nil {
+			return fmt.Errorf("failed to open bundle: %w", err)
+		}
+		data, err = bun.Manifest(ctx)
+		_ = bun.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read bundle manifest: %w", err)
+		}
+	}
+
+	mf, err := UnmarshalManifest(data, 0)
+	if err != nil {
+		return err
+	}
+	mf.Name = name
+
+	return WriteManifest(ctx, fsys, bundler, manifestPath, mf, isBundle)
+}
+
+// IsOrphanedManifest returns if a par2cron manifest no longer has its PAR2
+// index file (or bundle) next to it, as such a manifest will never be a job.
+// A manifest recording its PAR2 set as stored elsewhere is never orphaned.
+func IsOrphanedManifest(fsys afero.Fs, manifestPath string, names SidecarNames) (bool, error) {
+	for _, par2Path := range names.Par2Paths(manifestPath) {
+		if _, err := LstatIfPossible(fsys, par2Path); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return false, fmt.Errorf("failed to stat: %w", err)
+		}
+
+		return false, nil
+	}
+
+	data, err := afero.ReadFile(fsys, manifestPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read: %w", err)
+	}
+	if mf, err := UnmarshalManifest(data, 0); err == nil && mf.Par2Location != "" {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// RemoveOrphanedManifest removes a par2cron manifest (and the lock file) of a
+// no longer existing PAR2 set. The manifest is checked again once holding the
+// lock, returning false without removal if the PAR2 set exists by then.
+func RemoveOrphanedManifest(fsys afero.Fs, manifestPath string, names SidecarNames) (bool, error) {
+	lockPath := names.LockPath(names.Par2Paths(manifestPath)[0])
+
+	unlock, err := AcquireLock(fsys, lockPath, false)
+	if err != nil {
+		return false, fmt.Errorf("failed to lock: %w", err)
+	}
+	defer unlock()
+
+	orphaned, err := IsOrphanedManifest(fsys, manifestPath, names)
+	if err != nil || !orphaned {
+		return false, err
+	}
+
+	if err := fsys.Remove(manifestPath); err != nil {
+		return false, fmt.Errorf("failed to remove: %w", err)
+	}
+	_ = fsys.Remove(lockPath)
+
+	return true, nil
+}
+
 var _ schema.FilesystemWalker = (*AferoWalker)(nil)
 
 // AferoWalker is an adapter to turn the [afero.Walk] into a [filepath.WalkDir] signature.
@@ -152,6 +348,48 @@ func (w OSWalker) WalkDir(root string, fn fs.WalkDirFunc) error {
 	return filepath.WalkDir(root, fn) //nolint:wrapcheck
 }
 
+var _ schema.ReadOnlyChecker = (*AferoReadOnlyChecker)(nil)
+
+// AferoReadOnlyChecker reports paths as read-only for an [afero.ReadOnlyFs].
+type AferoReadOnlyChecker struct {
+	Fs afero.Fs
+}
+
+func (c AferoReadOnlyChecker) IsReadOnly(_ string) (bool, error) {
+	_, ok := c.Fs.(*afero.ReadOnlyFs)
+
+	return ok, nil
+}
+
+var _ schema.ReadOnlyChecker = (*OSReadOnlyChecker)(nil)
+
+// OSReadOnlyChecker reports paths as read-only if their containing filesystem
+// is mounted read-only, where supported by the platform (see [isReadOnlyMount]).
+type OSReadOnlyChecker struct{}
+
+func (c OSReadOnlyChecker) IsReadOnly(path string) (bool, error) {
+	return isReadOnlyMount(path)
+}
+
+var _ schema.CacheDropper = (*AferoCacheDropper)(nil)
+
+// AferoCacheDropper does nothing, as an [afero.Fs] has no page cache to drop.
+type AferoCacheDropper struct{}
+
+func (d AferoCacheDropper) DropCache(_ string) error {
+	return nil
+}
+
+var _ schema.CacheDropper = (*OSCacheDropper)(nil)
+
+// OSCacheDropper drops the page cache of files, where supported by the
+// platform (see [dropPageCache] and [CacheDropSupported]).
+type OSCacheDropper struct{}
+
+func (d OSCacheDropper) DropCache(path string) error {
+	return dropPageCache(path)
+}
+
 type fileInfoDirEntry struct {
 	fs.FileInfo
 }
@@ -172,21 +410,243 @@ func (fi fileInfoDirEntry) Name() string {
 	return fi.FileInfo.Name()
 }
 
+// ExceedsMaxDepth returns if a directory lies deeper below rootDir than allowed
+// by maxDepth, so that a walk can prune it (and everything below) using [fs.SkipDir].
+// The rootDir itself is at depth 0, its immediate subdirectories at depth 1.
+func ExceedsMaxDepth(rootDir string, dir string, maxDepth flags.MaxDepth) bool {
+	if maxDepth.Unlimited() {
+		return false
+	}
+
+	rel, err := filepath.Rel(rootDir, dir)
+	if err != nil || rel == "." {
+		return false
+	}
+
+	return strings.Count(rel, string(filepath.Separator))+1 > maxDepth.Value
+}
+
+// CheckDataRoot returns an error wrapping [schema.ErrExitMissingData] if the
+// root directory does not exist or is not a directory, or (with wantEntries)
+// is an empty directory, as is the mountpoint of a volume that is not mounted.
+func CheckDataRoot(fsys afero.Fs, rootDir string, wantEntries bool) error {
+	fi, err := fsys.Stat(rootDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %w", schema.ErrExitMissingData, err)
+		}
+
+		return fmt.Errorf("failed to stat: %w", err)
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("%w: not a directory", schema.ErrExitMissingData)
+	}
+	if !wantEntries {
+		return nil
+	}
+
+	f, err := fsys.Open(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to open: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Readdirnames(1); err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("%w: directory is empty", schema.ErrExitMissingData)
+		}
+
+		return fmt.Errorf("failed to read: %w", err)
+	}
+
+	return nil
+}
+
+// IgnoreNames holds the filenames of the ignore files, where any empty name
+// falls back to the respective default ([schema.IgnoreFile] and [schema.IgnoreAllFile]).
+type IgnoreNames struct {
+	File    string
+	AllFile string
+}
+
+func (n IgnoreNames) FileName() string {
+	if n.File == "" {
+		return schema.IgnoreFile
+	}
+
+	return n.File
+}
+
+func (n IgnoreNames) AllFileName() string {
+	if n.AllFile == "" {
+		return schema.IgnoreAllFile
+	}
+
+	return n.AllFile
+}
+
+func (n IgnoreNames) Validate() error {
+	for _, entry := range [][2]string{{"ignore-file", n.File}, {"ignore-all-file", n.AllFile}} {
+		key, name := entry[0], entry[1]
+
+		if strings.TrimSpace(name) == "" || name == "." || name == ".." {
+			return fmt.Errorf("%s: invalid filename %q", key, name)
+		}
+		if strings.ContainsAny(name, `/\`) {
+			return fmt.Errorf("%s: filename %q must not contain path separators", key, name)
+		}
+	}
+
+	if n.File == n.AllFile {
+		return fmt.Errorf("ignore-file: filename %q must differ from ignore-all-file", n.File)
+	}
+
+	return nil
+}
+
+// ValidateStampFile returns an error if the name is not usable as filename of
+// a stamp file, including names that would be picked up as marker files.
+func ValidateStampFile(name string) error {
+	if strings.TrimSpace(name) == "" || name == "." || name == ".." {
+		return fmt.Errorf("invalid filename %q", name)
+	}
+	if strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("filename %q must not contain path separators", name)
+	}
+	if strings.HasPrefix(name, markerFilePrefix) {
+		return fmt.Errorf("filename %q must not start with %q (as for marker files)", name, markerFilePrefix)
+	}
+	if EndsWithFold(name, schema.Par2Extension) {
+		return fmt.Errorf("filename %q must not end with %q", name, schema.Par2Extension)
+	}
+
+	return nil
+}
+
+// WriteStamp writes a stamp file atomically, so that external tools
+// checking for it never observe a partially written stamp file.
+func WriteStamp(fsys afero.Fs, path string, stamp *schema.Stamp) error {
+	data, err := json.MarshalIndent(stamp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal: %w", err)
+	}
+
+	if err := WriteFileAtomic(fsys, path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write: %w", err)
+	}
+
+	return nil
+}
+
+func WriteFileList(fsys afero.Fs, path string, list *schema.FileList) error {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal: %w", err)
+	}
+
+	if err := WriteFileAtomic(fsys, path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write: %w", err)
+	}
+
+	return nil
+}
+
+func ReadStamp(fsys afero.Fs, path string) (*schema.Stamp, error) {
+	data, err := afero.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read: %w", err)
+	}
+
+	stamp := &schema.Stamp{}
+	if err := json.Unmarshal(data, stamp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal: %w", err)
+	}
+
+	return stamp, nil
+}
+
+// CheckTempDir returns an error if the temporary directory does not exist,
+// is not a directory or is not writable (probed by creating a temporary file).
+func CheckTempDir(fsys afero.Fs, dir string) error {
+	fi, err := fsys.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("temp-dir: %w", err)
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("temp-dir: %q is not a directory", dir)
+	}
+
+	f, err := afero.TempFile(fsys, dir, ".par2cron-probe-*")
+	if err != nil {
+		return fmt.Errorf("temp-dir: %q is not writable: %w", dir, err)
+	}
+	_ = f.Close()
+	_ = fsys.Remove(f.Name())
+
+	return nil
+}
+
+// WriteFileAtomic writes data to a temporary file next to the path, which is
+// then renamed over the path, so readers never observe a partially written file.
+func WriteFileAtomic(fsys afero.Fs, path string, data []byte) error {
+	f, err := afero.TempFile(fsys, filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := f.Name()
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		_ = fsys.Remove(tmpPath)
+
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = fsys.Remove(tmpPath)
+
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	if err := fsys.Chmod(tmpPath, 0o644); err != nil { //nolint:mnd
+		_ = fsys.Remove(tmpPath)
+
+		return fmt.Errorf("failed to chmod temporary file: %w", err)
+	}
+
+	if err := fsys.Rename(tmpPath, path); err != nil {
+		_ = fsys.Remove(tmpPath)
+
+		return fmt.Errorf("failed to rename temporary file: %w", err)
+	}
+
+	return nil
+}
+
 type IgnoreChecker struct {
 	fsys    afero.Fs
 	rootDir string
+	names   IgnoreNames
 	cache   map[string]bool
 }
 
-func NewIgnoreChecker(fsys afero.Fs, rootDir string) *IgnoreChecker {
+func NewIgnoreChecker(fsys afero.Fs, rootDir string, names IgnoreNames) *IgnoreChecker {
 	return &IgnoreChecker{
 		fsys:    fsys,
 		rootDir: rootDir,
+		names:   names,
 		cache:   make(map[string]bool),
 	}
 }
 
 func (ic *IgnoreChecker) ShouldIgnore(path string) bool {
+	_, _, ignored := ic.IgnoredBy(path)
+
+	return ignored
+}
+
+// IgnoredBy returns the path of the ignore (or ignore-all, then all is true)
+// file responsible for ignoring the given path, if the path is to be ignored.
+func (ic *IgnoreChecker) IgnoredBy(path string) (string, bool, bool) {
 	dir := filepath.Dir(path)
 
 	if len(ic.cache) > 100000 { //nolint:mnd
@@ -196,32 +656,32 @@ func (ic *IgnoreChecker) ShouldIgnore(path string) bool {
 	return ic.calculateIgnore(dir)
 }
 
-func (ic *IgnoreChecker) calculateIgnore(dir string) bool {
-	ignorePath := filepath.Join(dir, schema.IgnoreFile)
+func (ic *IgnoreChecker) calculateIgnore(dir string) (string, bool, bool) {
+	ignorePath := filepath.Join(dir, ic.names.FileName())
 
 	ignored, exists := ic.cache[ignorePath]
 	if exists && ignored {
-		return true
+		return ignorePath, false, true
 	} else if !exists {
 		if _, err := LstatIfPossible(ic.fsys, ignorePath); err == nil {
 			ic.cache[ignorePath] = true
 
-			return true
+			return ignorePath, false, true
 		}
 		ic.cache[ignorePath] = false
 	}
 
 	for {
-		ignoreAllPath := filepath.Join(dir, schema.IgnoreAllFile)
+		ignoreAllPath := filepath.Join(dir, ic.names.AllFileName())
 
 		ignored, exists := ic.cache[ignoreAllPath]
 		if exists && ignored {
-			return true
+			return ignoreAllPath, true, true
 		} else if !exists {
 			if _, err := LstatIfPossible(ic.fsys, ignoreAllPath); err == nil {
 				ic.cache[ignoreAllPath] = true
 
-				return true
+				return ignoreAllPath, true, true
 			}
 			ic.cache[ignoreAllPath] = false
 		}
@@ -233,7 +693,99 @@ func (ic *IgnoreChecker) calculateIgnore(dir string) bool {
 		dir = filepath.Dir(dir)
 	}
 
-	return false
+	return "", false, false
+}
+
+// IgnoreHit is an ignore (or ignore-all) file that was honored during a walk,
+// with the number of PAR2 sets (and their directories) it caused to be skipped.
+type IgnoreHit struct {
+	Path string
+	All  bool
+	Sets int
+	Dirs int
+}
+
+// IgnoreHits records the ignore files honored during walks. A nil *IgnoreHits
+// is valid and records nothing, so that callers need not check for it.
+type IgnoreHits struct {
+	hits map[string]*IgnoreHit
+	dirs map[[2]string]struct{}
+}
+
+func NewIgnoreHits() *IgnoreHits {
+	return &IgnoreHits{
+		hits: make(map[string]*IgnoreHit),
+		dirs: make(map[[2]string]struct{}),
+	}
+}
+
+// Add records a PAR2 set at par2Path as skipped due to the ignore file at ignorePath.
+func (h *IgnoreHits) Add(ignorePath string, all bool, par2Path string) {
+	if h == nil {
+		return
+	}
+
+	hit, ok := h.hits[ignorePath]
+	if !ok {
+		hit = &IgnoreHit{Path: ignorePath, All: all}
+		h.hits[ignorePath] = hit
+	}
+	hit.Sets++
+
+	key := [2]string{ignorePath, filepath.Dir(par2Path)}
+	if _, ok := h.dirs[key]; !ok {
+		h.dirs[key] = struct{}{}
+		hit.Dirs++
+	}
+}
+
+// List returns the recorded ignore files, sorted by their path.
+func (h *IgnoreHits) List() []IgnoreHit {
+	if h == nil {
+		return []IgnoreHit{}
+	}
+
+	list := make([]IgnoreHit, 0, len(h.hits))
+	for _, hit := range h.hits {
+		list = append(list, *hit)
+	}
+	slices.SortFunc(list, func(a, b IgnoreHit) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return list
+}
+
+// globMetaReplacer escapes the glob meta characters of a literal path.
+// https://github.com/bmatcuk/doublestar/blob/master/utils.go#L153
+var globMetaReplacer = strings.NewReplacer("*", "\\*", "?", "\\?", "[", "\\[", "]", "\\]", "{", "\\{", "}", "\\}")
+
+// EscapeGlob returns the path with its glob meta characters escaped, so that
+// it can prefix a glob pattern matching below it.
+func EscapeGlob(path string) string {
+	return globMetaReplacer.Replace(path)
+}
+
+// GlobExcludedBy returns the first of the patterns matching the path, both
+// relative to the working directory (as with the glob that matched the path).
+func GlobExcludedBy(workingDir string, path string, patterns []string) (string, bool) {
+	if len(patterns) == 0 {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(workingDir, path)
+	if err != nil {
+		return "", false
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, pattern := range patterns {
+		if ok, _ := doublestar.Match(pattern, rel); ok {
+			return pattern, true
+		}
+	}
+
+	return "", false
 }
 
 func HasGlobSymlinks(fsys afero.Fs, workingDir string, pattern string) (string, bool) {
@@ -294,3 +846,56 @@ func FindBundleableFiles(fsys afero.Fs, par2Name string, workingDir string) ([]b
 
 	return inputs, nil
 }
+
+// MissingElements returns the paths of all elements (as recorded at creation)
+// that no longer exist relative to workingDir, which is the directory of the
+// PAR2 set. Elements without a recorded name cannot be resolved and are ignored.
+func MissingElements(fsys afero.Fs, workingDir string, elements []schema.FsElement) []string {
+	var missing []string
+
+	for _, el := range elements {
+		if el.Name == "" {
+			continue
+		}
+
+		path := filepath.Join(workingDir, el.Name)
+		if _, err := LstatIfPossible(fsys, path); err != nil && errors.Is(err, fs.ErrNotExist) {
+			missing = append(missing, path)
+		}
+	}
+
+	return missing
+}
+
+// ContainingRoot returns the longest of the root directories that contains
+// dir (or is dir), for telling which scanned tree a directory was found in.
+func ContainingRoot(rootDirs []string, dir string) (string, bool) {
+	var bestRoot string
+
+	for _, rootDir := range rootDirs {
+		rel, err := filepath.Rel(rootDir, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if len(rootDir) > len(bestRoot) {
+			bestRoot = rootDir
+		}
+	}
+
+	return bestRoot, bestRoot != ""
+}
+
+// Par2BasePath returns the directory that par2 should resolve the protected
+// files of the PAR2 set in workingDir against, for the given basepath mode.
+// The scan root falls back to workingDir if no root directory contains it.
+func Par2BasePath(mode string, rootDirs []string, workingDir string) string {
+	if mode != schema.BasePathScanRoot {
+		return workingDir
+	}
+
+	if rootDir, ok := ContainingRoot(rootDirs, workingDir); ok {
+		return filepath.Clean(rootDir)
+	}
+
+	return workingDir
+}
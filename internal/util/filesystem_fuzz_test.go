@@ -0,0 +1,58 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func Fuzz_UnmarshalManifest(f *testing.F) {
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.SHA256 = strings.Repeat("ab", 32)
+	mf.Creation = schema.NewCreationManifest()
+	mf.Creation.Time = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mf.Creation.Args = []string{"-r10"}
+	mf.Creation.Elements = []schema.FsElement{{Name: "sub/file.txt", Size: 7}}
+	mf.Creation.Tags = []string{"photos"}
+	mf.Verification = schema.NewVerificationManifest()
+	mf.Verification.AddDuration(time.Second)
+	mf.Repair = schema.NewRepairManifest()
+	mf.Interruption = schema.NewInterruptionManifest("verify")
+
+	data, err := MarshalManifest(mf)
+	require.NoError(f, err)
+	f.Add(data)
+
+	// Legacy (v1) manifest and malformed ones
+	f.Add([]byte(`{"name":"a.par2","creation":{"files":[{"name":"a.txt"}]}}`))
+	f.Add([]byte(`{"verification":{"duration_ns":-1,"recent_durations_ns":[1,2,3,4,5,6,7,8]}}`))
+	f.Add([]byte(`{"creation":{"duration_ns":1e400,"elements":[{"name":"../x"}]}}`))
+	f.Add([]byte("{\"name\":\"\xff\xfe.par2\",\"creation\":{\"elements\":[{\"name\":\"\xc3\"}]}}"))
+	f.Add([]byte(strings.Repeat("[", 20000) + strings.Repeat("]", 20000)))
+	f.Add([]byte(`{"creation":{"time":"99999-01-01T00:00:00Z"}}`))
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		mf, err := UnmarshalManifest(data, 0)
+		if err != nil {
+			require.Nil(t, mf)
+
+			return
+		}
+
+		require.NoError(t, mf.Validate())
+		if mf.Verification != nil {
+			require.LessOrEqual(t, len(mf.Verification.RecentDurations), schema.MaxRecentDurations)
+		}
+
+		// Whatever is accepted must also be written and read back.
+		again, err := MarshalManifest(mf)
+		require.NoError(t, err)
+
+		_, err = UnmarshalManifest(again, 0)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,21 @@
+//go:build linux
+
+package util
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// stRdOnly is the ST_RDONLY mount flag, as returned by statfs(2).
+const stRdOnly = 0x1
+
+// isReadOnlyMount returns if the filesystem containing the path is mounted read-only.
+func isReadOnlyMount(path string) (bool, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return false, fmt.Errorf("statfs: %w", err)
+	}
+
+	return st.Flags&stRdOnly != 0, nil
+}
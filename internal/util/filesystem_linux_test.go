@@ -0,0 +1,48 @@
+//go:build linux
+
+package util
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: A writable temporary directory should not be reported as read-only.
+func Test_OSReadOnlyChecker_IsReadOnly_Success(t *testing.T) {
+	t.Parallel()
+
+	ro, err := OSReadOnlyChecker{}.IsReadOnly(t.TempDir())
+	require.NoError(t, err)
+	require.False(t, ro)
+}
+
+// Expectation: A non-existing path should return an error.
+func Test_OSReadOnlyChecker_IsReadOnly_Error(t *testing.T) {
+	t.Parallel()
+
+	_, err := OSReadOnlyChecker{}.IsReadOnly("/nonexistent/par2cron")
+	require.ErrorContains(t, err, "statfs")
+}
+
+// Expectation: The page cache of an existing file should be dropped without error.
+func Test_OSCacheDropper_DropCache_Success(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "test.txt")
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0o644))
+
+	require.True(t, CacheDropSupported)
+	require.NoError(t, OSCacheDropper{}.DropCache(path))
+}
+
+// Expectation: A non-existing file should return an error.
+func Test_OSCacheDropper_DropCache_Error(t *testing.T) {
+	t.Parallel()
+
+	err := OSCacheDropper{}.DropCache("/nonexistent/par2cron")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package util
+
+// isReadOnlyMount is only supported on Linux, never reporting a read-only
+// mount otherwise (so that the operation proceeds as it normally would).
+func isReadOnlyMount(_ string) (bool, error) {
+	return false, nil
+}
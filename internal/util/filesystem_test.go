@@ -9,7 +9,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/desertwitch/par2cron/internal/flags"
 	"github.com/desertwitch/par2cron/internal/schema"
 	"github.com/desertwitch/par2cron/internal/testutil"
 	"github.com/spf13/afero"
@@ -186,6 +188,24 @@ func Test_WriteManifest_UpdatesProgramVersion_Success(t *testing.T) {
 	require.Equal(t, schema.ProgramVersion, written.ProgramVersion)
 }
 
+// Expectation: The manifest should be stamped with the current versions when marshalled.
+func Test_MarshalManifest_Success(t *testing.T) {
+	t.Parallel()
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.ProgramVersion = "0.0.0"
+	mf.ManifestVersion = "0"
+
+	by, err := MarshalManifest(mf)
+	require.NoError(t, err)
+
+	var written schema.Manifest
+	require.NoError(t, json.Unmarshal(by, &written))
+	require.Equal(t, schema.ProgramVersion, written.ProgramVersion)
+	require.Equal(t, schema.ManifestVersion, written.ManifestVersion)
+	require.Equal(t, schema.ProgramVersion, mf.ProgramVersion)
+}
+
 // Expectation: A write failure should fail the function and return an error.
 func Test_WriteManifest_WriteFails_Error(t *testing.T) {
 	t.Parallel()
@@ -426,6 +446,96 @@ func Test_WriteManifest_Bundle_NoStandaloneFile_Success(t *testing.T) {
 	require.Empty(t, entries)
 }
 
+// Expectation: A manifest should only be orphaned when its PAR2 set no longer exists.
+func Test_IsOrphanedManifest_Success(t *testing.T) {
+	t.Parallel()
+
+	fsys := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fsys, "/data/set.par2", []byte("par2"), 0o644))
+	require.NoError(t, afero.WriteFile(fsys, "/data/set.par2.json", []byte("{}"), 0o644))
+	require.NoError(t, afero.WriteFile(fsys, "/data/gone.par2.json", []byte("{}"), 0o644))
+
+	orphaned, err := IsOrphanedManifest(fsys, "/data/set.par2.json", SidecarNames{})
+	require.NoError(t, err)
+	require.False(t, orphaned)
+
+	orphaned, err = IsOrphanedManifest(fsys, "/data/gone.par2.json", SidecarNames{})
+	require.NoError(t, err)
+	require.True(t, orphaned)
+}
+
+// Expectation: A manifest recording its PAR2 set as stored elsewhere should never be orphaned.
+func Test_IsOrphanedManifest_Par2Location_Success(t *testing.T) {
+	t.Parallel()
+
+	fsys := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fsys, "/data/set.par2.json", []byte(`{"par2_location":"/parity/set.par2"}`), 0o644))
+
+	orphaned, err := IsOrphanedManifest(fsys, "/data/set.par2.json", SidecarNames{})
+	require.NoError(t, err)
+	require.False(t, orphaned)
+}
+
+// Expectation: An orphaned manifest and its lock file should be removed, other manifests not.
+func Test_RemoveOrphanedManifest_Success(t *testing.T) {
+	t.Parallel()
+
+	fsys := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fsys, "/data/set.par2", []byte("par2"), 0o644))
+	require.NoError(t, afero.WriteFile(fsys, "/data/set.par2.json", []byte("{}"), 0o644))
+	require.NoError(t, afero.WriteFile(fsys, "/data/gone.par2.json", []byte("{}"), 0o644))
+	require.NoError(t, afero.WriteFile(fsys, "/data/gone.par2.lock", nil, 0o644))
+
+	removed, err := RemoveOrphanedManifest(fsys, "/data/set.par2.json", SidecarNames{})
+	require.NoError(t, err)
+	require.False(t, removed)
+	_, err = fsys.Stat("/data/set.par2.json")
+	require.NoError(t, err)
+
+	removed, err = RemoveOrphanedManifest(fsys, "/data/gone.par2.json", SidecarNames{})
+	require.NoError(t, err)
+	require.True(t, removed)
+	_, err = fsys.Stat("/data/gone.par2.json")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	_, err = fsys.Stat("/data/gone.par2.lock")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// Expectation: The name of a manifest should be corrected, keeping the rest of the manifest.
+func Test_CorrectManifestName_Success(t *testing.T) {
+	t.Parallel()
+
+	fsys := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fsys, "/data/renamed.par2", []byte("par2"), 0o644))
+
+	mf := schema.NewManifest("test.par2")
+	mf.Creation = schema.NewCreationManifest()
+	mf.Creation.Args = []string{"-r10"}
+	data, err := json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fsys, "/data/renamed.par2.json", data, 0o644))
+
+	require.NoError(t, CorrectManifestName(t.Context(), fsys, &BundleHandler{}, "/data/renamed.par2", "renamed.par2", false, SidecarNames{}))
+
+	data, err = afero.ReadFile(fsys, "/data/renamed.par2.json")
+	require.NoError(t, err)
+	corrected, err := UnmarshalManifest(data, 0)
+	require.NoError(t, err)
+	require.Equal(t, "renamed.par2", corrected.Name)
+	require.Equal(t, []string{"-r10"}, corrected.Creation.Args)
+}
+
+// Expectation: An error should be returned when the manifest cannot be read.
+func Test_CorrectManifestName_NoManifest_Error(t *testing.T) {
+	t.Parallel()
+
+	fsys := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fsys, "/data/renamed.par2", []byte("par2"), 0o644))
+
+	err := CorrectManifestName(t.Context(), fsys, &BundleHandler{}, "/data/renamed.par2", "renamed.par2", false, SidecarNames{})
+	require.ErrorContains(t, err, "failed to read")
+}
+
 // Expectation: The walker should visit all files and directories.
 func Test_AferoWalker_WalkDir_Success(t *testing.T) {
 	t.Parallel()
@@ -516,7 +626,7 @@ func Test_IgnoreChecker_ShouldIgnore_CacheOverflow_ResetsCache_Success(t *testin
 	fsys := afero.NewMemMapFs()
 	require.NoError(t, fsys.MkdirAll("/root", 0o755))
 
-	checker := NewIgnoreChecker(fsys, "/root")
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
 
 	// Seed the cache beyond the threshold.
 	for i := range 100001 {
@@ -536,7 +646,7 @@ func Test_IgnoreChecker_ShouldIgnore_NoIgnoreFiles_Success(t *testing.T) {
 	fsys := afero.NewMemMapFs()
 	require.NoError(t, fsys.MkdirAll("/root/subdir", 0o755))
 
-	checker := NewIgnoreChecker(fsys, "/root")
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
 
 	require.False(t, checker.ShouldIgnore("/root/subdir/file.txt"))
 }
@@ -549,7 +659,7 @@ func Test_IgnoreChecker_ShouldIgnore_IgnoreFile_SkipsPath_Success(t *testing.T)
 	require.NoError(t, fsys.MkdirAll("/root/subdir", 0o755))
 	require.NoError(t, afero.WriteFile(fsys, "/root/subdir/"+schema.IgnoreFile, []byte{}, 0o644))
 
-	checker := NewIgnoreChecker(fsys, "/root")
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
 
 	require.True(t, checker.ShouldIgnore("/root/subdir/file.txt"))
 }
@@ -562,7 +672,7 @@ func Test_IgnoreChecker_ShouldIgnore_IgnoreFile_InParent_DoesNotSkip_Success(t *
 	require.NoError(t, fsys.MkdirAll("/root/subdir", 0o755))
 	require.NoError(t, afero.WriteFile(fsys, "/root/"+schema.IgnoreFile, []byte{}, 0o644))
 
-	checker := NewIgnoreChecker(fsys, "/root")
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
 
 	require.False(t, checker.ShouldIgnore("/root/subdir/file.txt"))
 }
@@ -575,7 +685,7 @@ func Test_IgnoreChecker_ShouldIgnore_IgnoreAllFile_SameDir_SkipsPath_Success(t *
 	require.NoError(t, fsys.MkdirAll("/root/subdir", 0o755))
 	require.NoError(t, afero.WriteFile(fsys, "/root/subdir/"+schema.IgnoreAllFile, []byte{}, 0o644))
 
-	checker := NewIgnoreChecker(fsys, "/root")
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
 
 	require.True(t, checker.ShouldIgnore("/root/subdir/file.txt"))
 }
@@ -588,7 +698,7 @@ func Test_IgnoreChecker_ShouldIgnore_IgnoreAllFile_InParent_SkipsPath_Success(t
 	require.NoError(t, fsys.MkdirAll("/root/subdir/deep", 0o755))
 	require.NoError(t, afero.WriteFile(fsys, "/root/"+schema.IgnoreAllFile, []byte{}, 0o644))
 
-	checker := NewIgnoreChecker(fsys, "/root")
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
 
 	require.True(t, checker.ShouldIgnore("/root/subdir/deep/file.txt"))
 }
@@ -601,7 +711,7 @@ func Test_IgnoreChecker_ShouldIgnore_IgnoreAllFile_InIntermediate_SkipsPath_Succ
 	require.NoError(t, fsys.MkdirAll("/root/mid/subdir", 0o755))
 	require.NoError(t, afero.WriteFile(fsys, "/root/mid/"+schema.IgnoreAllFile, []byte{}, 0o644))
 
-	checker := NewIgnoreChecker(fsys, "/root")
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
 
 	require.True(t, checker.ShouldIgnore("/root/mid/subdir/file.txt"))
 }
@@ -614,7 +724,7 @@ func Test_IgnoreChecker_ShouldIgnore_StopsAtRoot_Success(t *testing.T) {
 	require.NoError(t, fsys.MkdirAll("/root/subdir", 0o755))
 	require.NoError(t, afero.WriteFile(fsys, "/"+schema.IgnoreAllFile, []byte{}, 0o644))
 
-	checker := NewIgnoreChecker(fsys, "/root")
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
 
 	require.False(t, checker.ShouldIgnore("/root/subdir/file.txt"))
 }
@@ -627,7 +737,7 @@ func Test_IgnoreChecker_ShouldIgnore_PathInRoot_IgnoreAll_Success(t *testing.T)
 	require.NoError(t, fsys.MkdirAll("/root", 0o755))
 	require.NoError(t, afero.WriteFile(fsys, "/root/"+schema.IgnoreAllFile, []byte{}, 0o644))
 
-	checker := NewIgnoreChecker(fsys, "/root")
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
 
 	require.True(t, checker.ShouldIgnore("/root/file.txt"))
 }
@@ -640,7 +750,7 @@ func Test_IgnoreChecker_ShouldIgnore_PathInRoot_IgnoreFile_Success(t *testing.T)
 	require.NoError(t, fsys.MkdirAll("/root", 0o755))
 	require.NoError(t, afero.WriteFile(fsys, "/root/"+schema.IgnoreFile, []byte{}, 0o644))
 
-	checker := NewIgnoreChecker(fsys, "/root")
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
 
 	require.True(t, checker.ShouldIgnore("/root/file.txt"))
 }
@@ -654,7 +764,7 @@ func Test_IgnoreChecker_ShouldIgnore_CachesIgnoreFile_Success(t *testing.T) {
 	require.NoError(t, fsys.MkdirAll("/root/dir2", 0o755))
 	require.NoError(t, afero.WriteFile(fsys, "/root/dir1/"+schema.IgnoreFile, []byte{}, 0o644))
 
-	checker := NewIgnoreChecker(fsys, "/root")
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
 
 	require.True(t, checker.ShouldIgnore("/root/dir1/file.txt"))
 	require.False(t, checker.ShouldIgnore("/root/dir2/file.txt"))
@@ -675,7 +785,7 @@ func Test_IgnoreChecker_ShouldIgnore_CachesIgnoreAllFile_Success(t *testing.T) {
 	require.NoError(t, fsys.MkdirAll("/root/other", 0o755))
 	require.NoError(t, afero.WriteFile(fsys, "/root/mid/"+schema.IgnoreAllFile, []byte{}, 0o644))
 
-	checker := NewIgnoreChecker(fsys, "/root")
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
 
 	require.True(t, checker.ShouldIgnore("/root/mid/deep/file.txt"))
 	require.False(t, checker.ShouldIgnore("/root/other/file.txt"))
@@ -703,7 +813,7 @@ func Test_IgnoreChecker_ShouldIgnore_CachedIgnoreFile_Success(t *testing.T) {
 	require.NoError(t, fsys.MkdirAll("/root/dir", 0o755))
 	require.NoError(t, afero.WriteFile(fsys, "/root/dir/"+schema.IgnoreFile, []byte{}, 0o644))
 
-	checker := NewIgnoreChecker(fsys, "/root")
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
 
 	require.True(t, checker.ShouldIgnore("/root/dir/file1.txt"))
 
@@ -721,7 +831,7 @@ func Test_IgnoreChecker_ShouldIgnore_CachedIgnoreAllFile_Success(t *testing.T) {
 	require.NoError(t, fsys.MkdirAll("/root/mid/deep", 0o755))
 	require.NoError(t, afero.WriteFile(fsys, "/root/mid/"+schema.IgnoreAllFile, []byte{}, 0o644))
 
-	checker := NewIgnoreChecker(fsys, "/root")
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
 
 	require.True(t, checker.ShouldIgnore("/root/mid/deep/file1.txt"))
 
@@ -731,6 +841,277 @@ func Test_IgnoreChecker_ShouldIgnore_CachedIgnoreAllFile_Success(t *testing.T) {
 	require.True(t, checker.ShouldIgnore("/root/mid/deep/file2.txt"))
 }
 
+// Expectation: The checker should honor renamed ignore files and no longer the default names.
+func Test_IgnoreChecker_ShouldIgnore_RenamedIgnoreFiles_Success(t *testing.T) {
+	t.Parallel()
+
+	fsys := afero.NewMemMapFs()
+	require.NoError(t, fsys.MkdirAll("/root/one", 0o755))
+	require.NoError(t, fsys.MkdirAll("/root/two/deep", 0o755))
+	require.NoError(t, fsys.MkdirAll("/root/three", 0o755))
+	require.NoError(t, afero.WriteFile(fsys, "/root/one/.par2cronignore", []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fsys, "/root/two/.par2cronignoreall", []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fsys, "/root/three/"+schema.IgnoreFile, []byte{}, 0o644))
+
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{File: ".par2cronignore", AllFile: ".par2cronignoreall"})
+
+	require.True(t, checker.ShouldIgnore("/root/one/file.txt"))
+	require.True(t, checker.ShouldIgnore("/root/two/deep/file.txt"))
+	require.False(t, checker.ShouldIgnore("/root/three/file.txt"))
+}
+
+// Expectation: The checker should return the responsible ignore (or ignore-all) file.
+func Test_IgnoreChecker_IgnoredBy_Success(t *testing.T) {
+	t.Parallel()
+
+	fsys := afero.NewMemMapFs()
+	require.NoError(t, fsys.MkdirAll("/root/a/deep", 0o755))
+	require.NoError(t, fsys.MkdirAll("/root/b", 0o755))
+	require.NoError(t, afero.WriteFile(fsys, "/root/a/"+schema.IgnoreAllFile, []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fsys, "/root/b/"+schema.IgnoreFile, []byte{}, 0o644))
+
+	checker := NewIgnoreChecker(fsys, "/root", IgnoreNames{})
+
+	path, all, ignored := checker.IgnoredBy("/root/a/deep/file.par2")
+	require.True(t, ignored)
+	require.True(t, all)
+	require.Equal(t, "/root/a/"+schema.IgnoreAllFile, path)
+
+	path, all, ignored = checker.IgnoredBy("/root/b/file.par2")
+	require.True(t, ignored)
+	require.False(t, all)
+	require.Equal(t, "/root/b/"+schema.IgnoreFile, path)
+
+	path, _, ignored = checker.IgnoredBy("/root/file.par2")
+	require.False(t, ignored)
+	require.Empty(t, path)
+}
+
+// Expectation: The hits should be counted per ignore file, with distinct directories.
+func Test_IgnoreHits_Add_Success(t *testing.T) {
+	t.Parallel()
+
+	hits := NewIgnoreHits()
+	hits.Add("/root/b/"+schema.IgnoreFile, false, "/root/b/x.par2")
+	hits.Add("/root/a/"+schema.IgnoreAllFile, true, "/root/a/x.par2")
+	hits.Add("/root/a/"+schema.IgnoreAllFile, true, "/root/a/deep/x.par2")
+	hits.Add("/root/a/"+schema.IgnoreAllFile, true, "/root/a/deep/y.par2")
+
+	require.Equal(t, []IgnoreHit{
+		{Path: "/root/a/" + schema.IgnoreAllFile, All: true, Sets: 3, Dirs: 2},
+		{Path: "/root/b/" + schema.IgnoreFile, All: false, Sets: 1, Dirs: 1},
+	}, hits.List())
+}
+
+// Expectation: A nil recorder should record nothing and not panic.
+func Test_IgnoreHits_Nil_Success(t *testing.T) {
+	t.Parallel()
+
+	var hits *IgnoreHits
+	hits.Add("/root/"+schema.IgnoreFile, false, "/root/x.par2")
+
+	require.Empty(t, hits.List())
+}
+
+// Expectation: Empty ignore names should fall back to the default names.
+func Test_IgnoreNames_Defaults_Success(t *testing.T) {
+	t.Parallel()
+
+	names := IgnoreNames{}
+
+	require.Equal(t, schema.IgnoreFile, names.FileName())
+	require.Equal(t, schema.IgnoreAllFile, names.AllFileName())
+}
+
+// Expectation: An existing and writable directory should pass, leaving no probe file behind.
+func Test_CheckTempDir_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/tmp/scratch", 0o755))
+
+	require.NoError(t, CheckTempDir(fs, "/tmp/scratch"))
+
+	entries, err := afero.ReadDir(fs, "/tmp/scratch")
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+// Expectation: A missing, non-directory or non-writable path should fail.
+func Test_CheckTempDir_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/tmp/scratch", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/tmp/file", []byte("content"), 0o644))
+
+	require.ErrorContains(t, CheckTempDir(fs, "/nonexistent"), "temp-dir")
+	require.ErrorContains(t, CheckTempDir(fs, "/tmp/file"), "not a directory")
+	require.ErrorContains(t, CheckTempDir(afero.NewReadOnlyFs(fs), "/tmp/scratch"), "not writable")
+}
+
+// Expectation: The file should be replaced with the new content, leaving no temporary file behind.
+func Test_WriteFileAtomic_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/status", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/status/summary.txt", []byte("old"), 0o644))
+
+	require.NoError(t, WriteFileAtomic(fs, "/status/summary.txt", []byte("new")))
+
+	data, err := afero.ReadFile(fs, "/status/summary.txt")
+	require.NoError(t, err)
+	require.Equal(t, "new", string(data))
+
+	entries, err := afero.ReadDir(fs, "/status")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+// Expectation: A failed rename should keep the previous file and remove the temporary file.
+func Test_WriteFileAtomic_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := &testutil.FailingRenameFs{Fs: afero.NewMemMapFs(), FailPattern: "summary.txt"}
+	require.NoError(t, fs.MkdirAll("/status", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/status/summary.txt", []byte("old"), 0o644))
+
+	require.ErrorContains(t, WriteFileAtomic(fs, "/status/summary.txt", []byte("new")), "failed to rename")
+
+	data, err := afero.ReadFile(fs, "/status/summary.txt")
+	require.NoError(t, err)
+	require.Equal(t, "old", string(data))
+
+	entries, err := afero.ReadDir(fs, "/status")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	require.ErrorContains(t, WriteFileAtomic(afero.NewReadOnlyFs(fs), "/status/summary.txt", []byte("new")), "failed to create")
+}
+
+// Expectation: Only an afero read-only filesystem should be reported as read-only.
+func Test_AferoReadOnlyChecker_IsReadOnly_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+
+	ro, err := AferoReadOnlyChecker{Fs: fs}.IsReadOnly("/data")
+	require.NoError(t, err)
+	require.False(t, ro)
+
+	ro, err = AferoReadOnlyChecker{Fs: afero.NewReadOnlyFs(fs)}.IsReadOnly("/data")
+	require.NoError(t, err)
+	require.True(t, ro)
+}
+
+// Expectation: Valid ignore names should pass validation.
+func Test_IgnoreNames_Validate_Success(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, IgnoreNames{File: schema.IgnoreFile, AllFile: schema.IgnoreAllFile}.Validate())
+	require.NoError(t, IgnoreNames{File: ".par2cronignore", AllFile: ".par2cronignoreall"}.Validate())
+}
+
+// Expectation: Invalid ignore names should fail validation.
+func Test_IgnoreNames_Validate_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		names IgnoreNames
+	}{
+		{"empty file", IgnoreNames{File: "", AllFile: ".all"}},
+		{"empty all file", IgnoreNames{File: ".one", AllFile: ""}},
+		{"whitespace file", IgnoreNames{File: "  ", AllFile: ".all"}},
+		{"dot file", IgnoreNames{File: ".", AllFile: ".all"}},
+		{"dotdot all file", IgnoreNames{File: ".one", AllFile: ".."}},
+		{"slash in file", IgnoreNames{File: "a/b", AllFile: ".all"}},
+		{"backslash in all file", IgnoreNames{File: ".one", AllFile: `a\b`}},
+		{"same names", IgnoreNames{File: ".same", AllFile: ".same"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Error(t, tt.names.Validate())
+		})
+	}
+}
+
+// Expectation: A missing root, a file as root, or an empty root where entries are wanted should be missing data.
+func Test_CheckDataRoot_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		rootDir     string
+		wantEntries bool
+		wantMissing bool
+	}{
+		{"missing", "/missing", false, true},
+		{"file", "/file", false, true},
+		{"empty", "/empty", false, false},
+		{"empty wanting entries", "/empty", true, true},
+		{"populated wanting entries", "/data", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			require.NoError(t, fs.MkdirAll("/empty", 0o755))
+			require.NoError(t, fs.MkdirAll("/data", 0o755))
+			require.NoError(t, afero.WriteFile(fs, "/data/a.txt", []byte("a"), 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/file", []byte("f"), 0o644))
+
+			err := CheckDataRoot(fs, tt.rootDir, tt.wantEntries)
+			if tt.wantMissing {
+				require.ErrorIs(t, err, schema.ErrExitMissingData)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+// Expectation: Directories deeper than the maximum depth below the root should be reported.
+func Test_ExceedsMaxDepth_Table(t *testing.T) {
+	t.Parallel()
+
+	depth := func(s string) flags.MaxDepth {
+		var d flags.MaxDepth
+		require.NoError(t, d.Set(s))
+
+		return d
+	}
+
+	tests := []struct {
+		name     string
+		rootDir  string
+		dir      string
+		maxDepth flags.MaxDepth
+		want     bool
+	}{
+		{"unlimited deep", "/data", "/data/a/b/c/d", flags.MaxDepth{}, false},
+		{"zero root", "/data", "/data", depth("0"), false},
+		{"zero child", "/data", "/data/a", depth("0"), true},
+		{"one child", "/data", "/data/a", depth("1"), false},
+		{"one grandchild", "/data", "/data/a/b", depth("1"), true},
+		{"two grandchild", "/data", "/data/a/b", depth("2"), false},
+		{"trailing slash root", "/data/", "/data/a/b", depth("1"), true},
+		{"nested root", "/data/a", "/data/a/b", depth("1"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, ExceedsMaxDepth(tt.rootDir, tt.dir, tt.maxDepth))
+		})
+	}
+}
+
 // Expectation: No available lstat should pass all table tests.
 func Test_HasGlobSymlinks_MemMapFs_NoLstat_Table(t *testing.T) {
 	t.Parallel()
@@ -1230,3 +1611,197 @@ func Test_FindBundleableFiles_ReadDirFails_Error(t *testing.T) {
 	require.ErrorContains(t, err, "failed to read directory")
 	require.Nil(t, files)
 }
+
+// Expectation: MissingElements should return only the named elements absent relative to the directory.
+func Test_MissingElements_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/sub", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/a.txt", []byte("a"), 0o644))
+
+	elements := []schema.FsElement{
+		{Name: "a.txt"},
+		{Name: "sub", IsDir: true},
+		{Name: "gone.txt"},
+		{Name: "sub/gone.txt"},
+		{Name: ""},
+	}
+
+	require.Equal(t, []string{"/data/gone.txt", "/data/sub/gone.txt"}, MissingElements(fs, "/data", elements))
+	require.Empty(t, MissingElements(fs, "/data", elements[:2]))
+}
+
+// Expectation: Stamp filenames should be refused where unusable or taken for marker files.
+func Test_ValidateStampFile_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    string
+		wantErr bool
+	}{
+		{"default", schema.StampFile, false},
+		{"visible", "par2cron.done", false},
+		{"empty", "", true},
+		{"dot", ".", true},
+		{"path separator", "sub/done", true},
+		{"marker prefix", "_par2cron.done", true},
+		{"par2 extension", "done.PAR2", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.wantErr {
+				require.Error(t, ValidateStampFile(tt.file))
+			} else {
+				require.NoError(t, ValidateStampFile(tt.file))
+			}
+		})
+	}
+}
+
+// Expectation: A written stamp file should be read back the same.
+func Test_WriteStamp_ReadStamp_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	stamp := schema.NewStamp("test"+schema.Par2Extension, "create")
+	stamp.Time = time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC)
+	require.NoError(t, WriteStamp(fs, "/data/"+schema.StampFile, stamp))
+
+	got, err := ReadStamp(fs, "/data/"+schema.StampFile)
+	require.NoError(t, err)
+	require.Equal(t, stamp, got)
+
+	_, err = ReadStamp(fs, "/data/missing")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// Expectation: The file list should be written as JSON with the names and sizes of the elements.
+func Test_WriteFileList_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+
+	list := schema.NewFileList("test"+schema.Par2Extension, []schema.FsElement{
+		{Path: "/data/a.txt", Name: "a.txt", Size: 7},
+		{Path: "/data/sub", Name: "sub", IsDir: true},
+	})
+	require.NoError(t, WriteFileList(fs, "/data/test.par2.files", list))
+
+	data, err := afero.ReadFile(fs, "/data/test.par2.files")
+	require.NoError(t, err)
+
+	var got schema.FileList
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Equal(t, *list, got)
+	require.NotContains(t, string(data), "/data/a.txt")
+}
+
+// Expectation: UnmarshalManifest should refuse invalid manifests and bound the rolling window of durations.
+func Test_UnmarshalManifest_Success(t *testing.T) {
+	t.Parallel()
+
+	mf, err := UnmarshalManifest([]byte(`{"name":"test.par2","verification":{"count":1,"recent_durations_ns":[1,2,3,4,5,6,7]}}`), 0)
+	require.NoError(t, err)
+	require.Equal(t, "test.par2", mf.Name)
+	require.Equal(t, []time.Duration{3, 4, 5, 6, 7}, mf.Verification.RecentDurations)
+
+	_, err = UnmarshalManifest([]byte(`{"creation":{"elements":[{"name":"../../etc/shadow"}]}}`), 0)
+	require.ErrorIs(t, err, schema.ErrInvalidManifest)
+
+	_, err = UnmarshalManifest([]byte(`{"verification":{"count":99999999999999999999}}`), 0)
+	require.Error(t, err)
+
+	_, err = UnmarshalManifest([]byte(`{"name":`), 0)
+	require.Error(t, err)
+}
+
+// Expectation: A manifest larger than the maximum size should be refused before parsing,
+// while one within it (or without a maximum size) should be unmarshaled as usual.
+func Test_UnmarshalManifest_MaxManifestSize_Success(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"name":"test.par2","last_error":"` + strings.Repeat("x", 2048) + `"}`)
+
+	_, err := UnmarshalManifest(data, 1024)
+	require.ErrorIs(t, err, schema.ErrManifestTooLarge)
+	require.ErrorContains(t, err, "--max-manifest-size of 1.0 KiB")
+	require.Equal(t, schema.ReasonManifestTooLarge, ManifestInvalidReason(err))
+
+	mf, err := UnmarshalManifest([]byte(`{"name":"test.par2"}`), 1024)
+	require.NoError(t, err)
+	require.Equal(t, "test.par2", mf.Name)
+
+	_, err = UnmarshalManifest(data, 0)
+	require.NotErrorIs(t, err, schema.ErrManifestTooLarge)
+
+	_, err = UnmarshalManifest([]byte(`{"name":`), 0)
+	require.Equal(t, schema.ReasonManifestInvalid, ManifestInvalidReason(err))
+}
+
+// Expectation: Only an existing pause file should pause, while an empty path never does.
+func Test_IsPaused_Table(t *testing.T) {
+	t.Parallel()
+
+	fsys := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fsys, "/run/par2cron.pause", []byte(""), 0o644))
+	require.NoError(t, fsys.MkdirAll("/run/paused", 0o755))
+
+	tests := []struct {
+		name      string
+		pauseFile string
+		want      bool
+	}{
+		{"empty path", "", false},
+		{"file exists", "/run/par2cron.pause", true},
+		{"directory exists", "/run/paused", true},
+		{"file missing", "/run/missing.pause", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, IsPaused(fsys, tt.pauseFile))
+		})
+	}
+}
+
+// Expectation: Only a filesystem resolving the swapped case of an unlisted name should be case-insensitive.
+func Test_IsCaseInsensitive_Table(t *testing.T) {
+	t.Parallel()
+
+	sensitive := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(sensitive, "/data/test.par2", []byte("par2data"), 0o644))
+	require.NoError(t, afero.WriteFile(sensitive, "/both/test.par2", []byte("par2data"), 0o644))
+	require.NoError(t, afero.WriteFile(sensitive, "/both/TEST.PAR2", []byte("par2data"), 0o644))
+	require.NoError(t, afero.WriteFile(sensitive, "/data/1234", []byte("par2data"), 0o644))
+	insensitive := &testutil.CaseInsensitiveFs{Fs: sensitive}
+
+	tests := []struct {
+		name string
+		fsys afero.Fs
+		path string
+		want bool
+	}{
+		{"case-sensitive", sensitive, "/data/test.par2", false},
+		{"case-insensitive", insensitive, "/data/test.par2", true},
+		{"both cases listed", insensitive, "/both/test.par2", false},
+		{"no cased letters", insensitive, "/data/1234", false},
+		{"missing path", insensitive, "/data/missing.par2", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, IsCaseInsensitive(tt.fsys, tt.path))
+		})
+	}
+}
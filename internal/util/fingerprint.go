@@ -0,0 +1,99 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/spf13/afero"
+)
+
+// markerFilePrefix is the prefix of the names of marker files.
+const markerFilePrefix = "_par2cron"
+
+// FolderFingerprint returns the fingerprint of the composition of a folder
+// mode PAR2 set, a hash over the sorted names, sizes and modification times
+// of the protected files (not their content). Directories and empty files
+// are left out, as these are not protected in all configurations.
+func FolderFingerprint(elements []schema.FsElement) string {
+	tuples := make([]string, 0, len(elements))
+	for _, el := range elements {
+		if el.IsDir || el.Size == 0 || el.Name == "" {
+			continue
+		}
+		tuples = append(tuples, strings.Join([]string{
+			filepath.ToSlash(el.Name),
+			strconv.FormatInt(el.Size, 10),
+			strconv.FormatInt(el.ModTime.UnixNano(), 10),
+		}, "\x00"))
+	}
+	slices.Sort(tuples)
+
+	h := sha256.New()
+	for _, tuple := range tuples {
+		h.Write([]byte(tuple))
+		h.Write([]byte{'\n'})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ListFolderElements returns the regular files below the working directory
+// matched by the glob (less the excluded patterns), as a folder mode PAR2 set
+// created now would protect them, for recomputing its [FolderFingerprint].
+// The files of par2cron itself (PAR2, sidecar, marker and stamp files) are
+// left out, as are hidden files and symbolic links (as with the creation).
+func ListFolderElements(fsys afero.Fs, workingDir string, glob string, excludes []string, names SidecarNames, stampFile string) ([]schema.FsElement, error) {
+	pattern := filepath.Join(EscapeGlob(workingDir), glob)
+
+	paths, err := doublestar.Glob(afero.NewIOFS(fsys), pattern, doublestar.WithNoHidden(), doublestar.WithNoFollow())
+	if err != nil {
+		return nil, fmt.Errorf("failed to glob: %w", err)
+	}
+
+	elements := []schema.FsElement{}
+	for _, path := range paths {
+		name := filepath.Base(path)
+		if strings.HasPrefix(name, markerFilePrefix) || (stampFile != "" && name == stampFile) {
+			continue
+		}
+		if EndsWithFold(path, schema.Par2Extension) ||
+			EndsWithFold(path, schema.Par2Extension+schema.CreationManifestExtension) ||
+			EndsWithFold(path, schema.Par2Extension+schema.FileListExtension) ||
+			names.IsLock(path) || names.IsManifest(path) {
+			continue
+		}
+		if _, excluded := GlobExcludedBy(workingDir, path, excludes); excluded {
+			continue
+		}
+
+		fi, err := LstatIfPossible(fsys, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lstat: %w", err)
+		}
+		if !fi.Mode().IsRegular() {
+			continue
+		}
+
+		rel, err := filepath.Rel(workingDir, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive relative path: %w", err)
+		}
+
+		elements = append(elements, schema.FsElement{
+			Path:    path,
+			Name:    rel,
+			Size:    fi.Size(),
+			Mode:    fi.Mode(),
+			ModTime: fi.ModTime(),
+		})
+	}
+
+	return elements, nil
+}
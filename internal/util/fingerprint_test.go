@@ -0,0 +1,70 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: The fingerprint should not depend on the order of the elements or on directories and
+// empty files, but on the names, sizes and modification times of the files.
+func Test_FolderFingerprint_Table(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	base := []schema.FsElement{
+		{Name: "a.txt", Size: 1, ModTime: now},
+		{Name: "sub/b.txt", Size: 2, ModTime: now},
+	}
+	want := FolderFingerprint(base)
+
+	tests := []struct {
+		name     string
+		elements []schema.FsElement
+		same     bool
+	}{
+		{"reordered", []schema.FsElement{base[1], base[0]}, true},
+		{"with directory", append([]schema.FsElement{{Name: "sub", IsDir: true, ModTime: now}}, base...), true},
+		{"with empty file", append([]schema.FsElement{{Name: "empty.txt", ModTime: now}}, base...), true},
+		{"added", append([]schema.FsElement{{Name: "c.txt", Size: 3, ModTime: now}}, base...), false},
+		{"removed", base[:1], false},
+		{"renamed", []schema.FsElement{base[0], {Name: "sub/c.txt", Size: 2, ModTime: now}}, false},
+		{"resized", []schema.FsElement{base[0], {Name: "sub/b.txt", Size: 3, ModTime: now}}, false},
+		{"touched", []schema.FsElement{base[0], {Name: "sub/b.txt", Size: 2, ModTime: now.Add(time.Second)}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.same, FolderFingerprint(tt.elements) == want)
+		})
+	}
+}
+
+// Expectation: The files of par2cron itself, hidden and excluded files should not be listed.
+func Test_ListFolderElements_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	for _, name := range []string{
+		"a.txt", "sub/b.txt", "sub/excluded.tmp", ".hidden", "_par2cron", "done.stamp",
+		"set" + schema.Par2Extension, "set.vol0+1" + schema.Par2Extension,
+		"set" + schema.Par2Extension + schema.ManifestExtension,
+		"set" + schema.Par2Extension + schema.LockExtension,
+		"set" + schema.Par2Extension + schema.FileListExtension,
+	} {
+		require.NoError(t, afero.WriteFile(fs, "/data/"+name, []byte("x"), 0o644))
+	}
+
+	elements, err := ListFolderElements(fs, "/data", "**", []string{"**/*.tmp"}, SidecarNames{}, "done.stamp")
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(elements))
+	for _, el := range elements {
+		names = append(names, el.Name)
+	}
+	require.ElementsMatch(t, []string{"a.txt", "sub/b.txt"}, names)
+}
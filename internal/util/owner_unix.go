@@ -0,0 +1,21 @@
+//go:build !windows
+
+package util
+
+import (
+	"io/fs"
+	"syscall"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+)
+
+// FileOwner returns the owner of the file described by the file information,
+// or nil if it does not carry one (such as that of an in-memory filesystem).
+func FileOwner(fi fs.FileInfo) *schema.FsOwner {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+
+	return &schema.FsOwner{UID: st.Uid, GID: st.Gid}
+}
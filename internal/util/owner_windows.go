@@ -0,0 +1,15 @@
+//go:build windows
+
+package util
+
+import (
+	"io/fs"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+)
+
+// FileOwner is not supported on Windows, never returning an owner (so that
+// no owner is recorded, and no ownership is restored for the files).
+func FileOwner(_ fs.FileInfo) *schema.FsOwner {
+	return nil
+}
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// ReadQueue reads a newline-delimited list of paths (e.g. from standard input)
+// and returns them as cleaned absolute paths, in order and without duplicates.
+// Empty lines and lines starting with "#" are skipped over.
+func ReadQueue(ctx context.Context, r io.Reader) ([]string, error) {
+	paths := []string{}
+	seen := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("context error: %w", err)
+		}
+
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		path, err := filepath.Abs(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert path to absolute: %w", err)
+		}
+
+		if _, ok := seen[path]; ok {
+			continue
+		}
+		seen[path] = struct{}{}
+
+		paths = append(paths, path)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read: %w", err)
+	}
+
+	return paths, nil
+}
+
+// FindRoot returns the innermost of the root directories containing the path,
+// or false if the path is not within any of the root directories.
+func FindRoot(path string, rootDirs []string) (string, bool) {
+	var found string
+
+	for _, rootDir := range rootDirs {
+		rel, err := filepath.Rel(rootDir, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if len(rootDir) > len(found) {
+			found = rootDir
+		}
+	}
+
+	return found, found != ""
+}
@@ -0,0 +1,70 @@
+package util
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: The paths should be read in order, skipping empty lines, comments and duplicates.
+func Test_ReadQueue_Success(t *testing.T) {
+	t.Parallel()
+
+	input := "/data/a.par2\n\n# comment\n/data/sub/../b.par2\r\n   \n/data/a.par2\n/data/dir"
+
+	paths, err := ReadQueue(t.Context(), strings.NewReader(input))
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"/data/a.par2", "/data/b.par2", "/data/dir"}, paths)
+}
+
+// Expectation: An empty queue should return no paths.
+func Test_ReadQueue_Empty_Success(t *testing.T) {
+	t.Parallel()
+
+	paths, err := ReadQueue(t.Context(), strings.NewReader(""))
+
+	require.NoError(t, err)
+	require.Empty(t, paths)
+}
+
+// Expectation: A cancelled context should return an error.
+func Test_ReadQueue_CtxCancel_Error(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := ReadQueue(ctx, strings.NewReader("/data/a.par2\n"))
+
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// Expectation: The function should meet the table's expectations.
+func Test_FindRoot_Table(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		path   string
+		expect string
+		found  bool
+	}{
+		{"within root", "/data/a.par2", "/data", true},
+		{"root itself", "/data", "/data", true},
+		{"innermost root", "/data/nested/sub/a.par2", "/data/nested", true},
+		{"outside roots", "/other/a.par2", "", false},
+		{"prefix but not within", "/database/a.par2", "", false},
+		{"dotdot-prefixed name within", "/data/..hidden/a.par2", "/data", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			root, found := FindRoot(tt.path, []string{"/data", "/data/nested"})
+			require.Equal(t, tt.found, found)
+			require.Equal(t, tt.expect, root)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package util
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+)
+
+// SidecarNames holds the naming scheme of the par2cron manifest and lock file
+// next to a PAR2 set (<par2name><suffix>), where any empty suffix falls back to
+// the respective default ([schema.ManifestExtension] and [schema.LockExtension]).
+// With Hidden, both are always named as hidden (dotfiles), also for PAR2 sets
+// that are not hidden themselves. Bundles contain their manifest, so that the
+// scheme never applies to them.
+type SidecarNames struct {
+	ManifestSuffix string
+	LockSuffix     string
+	Hidden         bool
+}
+
+func (n SidecarNames) ManifestSuffixName() string {
+	if n.ManifestSuffix == "" {
+		return schema.ManifestExtension
+	}
+
+	return n.ManifestSuffix
+}
+
+func (n SidecarNames) LockSuffixName() string {
+	if n.LockSuffix == "" {
+		return schema.LockExtension
+	}
+
+	return n.LockSuffix
+}
+
+// ManifestName returns the filename of the manifest for a PAR2 filename.
+func (n SidecarNames) ManifestName(par2Name string) string {
+	return n.sidecarName(par2Name, n.ManifestSuffixName())
+}
+
+// ManifestPath returns the path of the manifest for a PAR2 path.
+func (n SidecarNames) ManifestPath(par2Path string) string {
+	return filepath.Join(filepath.Dir(par2Path), n.ManifestName(filepath.Base(par2Path)))
+}
+
+// LockPath returns the path of the lock file for a PAR2 path.
+func (n SidecarNames) LockPath(par2Path string) string {
+	return filepath.Join(filepath.Dir(par2Path), n.sidecarName(filepath.Base(par2Path), n.LockSuffixName()))
+}
+
+func (n SidecarNames) sidecarName(par2Name string, suffix string) string {
+	if n.Hidden && !strings.HasPrefix(par2Name, ".") {
+		return "." + par2Name + suffix
+	}
+
+	return par2Name + suffix
+}
+
+// IsManifest returns if the filename is that of a manifest (of any PAR2 set).
+func (n SidecarNames) IsManifest(name string) bool {
+	return EndsWithFold(name, schema.Par2Extension+n.ManifestSuffixName())
+}
+
+// IsLock returns if the filename is that of a lock file (of any PAR2 set).
+func (n SidecarNames) IsLock(name string) bool {
+	return EndsWithFold(name, schema.Par2Extension+n.LockSuffixName())
+}
+
+// Par2Paths returns the possible PAR2 paths for the path of a manifest, which
+// can be two with Hidden (of both a hidden and a non-hidden PAR2 set).
+func (n SidecarNames) Par2Paths(manifestPath string) []string {
+	par2Path := manifestPath[:len(manifestPath)-len(n.ManifestSuffixName())]
+
+	name := filepath.Base(par2Path)
+	if n.Hidden && strings.HasPrefix(name, ".") && len(name) > 1 {
+		return []string{par2Path, filepath.Join(filepath.Dir(par2Path), name[1:])}
+	}
+
+	return []string{par2Path}
+}
+
+// ManifestNameMismatch returns the name the manifest of the PAR2 set at par2Path
+// (an index file or a bundle) is expected to hold, and if its stored name differs,
+// such as after renaming the PAR2 set on disk. A manifest holding no name at all
+// is never considered a mismatch. The name in the manifest of a bundle is that of
+// its index file (but that of the bundle itself is also accepted), so only the
+// names without their extensions are compared.
+func ManifestNameMismatch(par2Path string, mf *schema.Manifest, isBundle bool) (string, bool) {
+	name := filepath.Base(par2Path)
+	root := TrimSuffixFold(name, schema.Par2Extension)
+
+	if isBundle {
+		root = strings.TrimSuffix(root, schema.BundleExtension)
+		name = root + schema.Par2Extension
+	}
+
+	if mf == nil || mf.Name == "" {
+		return name, false
+	}
+
+	stored := TrimSuffixFold(mf.Name, schema.Par2Extension)
+	if isBundle && stored == root+schema.BundleExtension {
+		return name, false
+	}
+
+	return name, stored != root
+}
+
+func (n SidecarNames) Validate() error {
+	for _, entry := range [][2]string{{"manifest-suffix", n.ManifestSuffix}, {"lock-suffix", n.LockSuffix}} {
+		key, suffix := entry[0], entry[1]
+
+		if !strings.HasPrefix(suffix, ".") || len(suffix) < 2 || strings.TrimSpace(suffix) != suffix { //nolint:mnd
+			return fmt.Errorf("%s: suffix %q must be a dot followed by at least one character", key, suffix)
+		}
+		if strings.ContainsAny(suffix, `/\`) {
+			return fmt.Errorf("%s: suffix %q must not contain path separators", key, suffix)
+		}
+		if EndsWithFold(suffix, schema.Par2Extension) {
+			return fmt.Errorf("%s: suffix %q must not end with %q", key, suffix, schema.Par2Extension)
+		}
+	}
+
+	if strings.EqualFold(n.ManifestSuffix, n.LockSuffix) {
+		return fmt.Errorf("manifest-suffix: suffix %q must differ from lock-suffix", n.ManifestSuffix)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: The zero value should fall back to the default naming scheme.
+func Test_SidecarNames_Default_Success(t *testing.T) {
+	t.Parallel()
+
+	names := SidecarNames{}
+
+	require.Equal(t, "test.par2"+schema.ManifestExtension, names.ManifestName("test.par2"))
+	require.Equal(t, "/data/test.par2"+schema.ManifestExtension, names.ManifestPath("/data/test.par2"))
+	require.Equal(t, "/data/test.par2"+schema.LockExtension, names.LockPath("/data/test.par2"))
+	require.True(t, names.IsManifest("test.PAR2.json"))
+	require.False(t, names.IsManifest("test.json"))
+	require.True(t, names.IsLock("test.par2.lock"))
+	require.Equal(t, []string{"/data/test.par2"}, names.Par2Paths("/data/test.par2.json"))
+}
+
+// Expectation: Custom suffixes should be used, with hidden sidecars also for non-hidden PAR2 sets.
+func Test_SidecarNames_CustomHidden_Success(t *testing.T) {
+	t.Parallel()
+
+	names := SidecarNames{ManifestSuffix: ".meta", LockSuffix: ".lck", Hidden: true}
+
+	require.Equal(t, ".test.par2.meta", names.ManifestName("test.par2"))
+	require.Equal(t, ".test.par2.meta", names.ManifestName(".test.par2"))
+	require.Equal(t, "/data/.test.par2.meta", names.ManifestPath("/data/test.par2"))
+	require.Equal(t, "/data/.test.par2.lck", names.LockPath("/data/test.par2"))
+	require.True(t, names.IsManifest(".test.par2.meta"))
+	require.False(t, names.IsManifest("test.par2.json"))
+	require.True(t, names.IsLock(".test.par2.lck"))
+	require.Equal(t, []string{"/data/.test.par2", "/data/test.par2"}, names.Par2Paths("/data/.test.par2.meta"))
+}
+
+// Expectation: Valid suffixes should pass validation.
+func Test_SidecarNames_Validate_Success(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, SidecarNames{ManifestSuffix: schema.ManifestExtension, LockSuffix: schema.LockExtension}.Validate())
+	require.NoError(t, SidecarNames{ManifestSuffix: ".meta", LockSuffix: ".lck", Hidden: true}.Validate())
+}
+
+// Expectation: Invalid suffixes should fail validation.
+func Test_SidecarNames_Validate_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		names SidecarNames
+	}{
+		{"empty manifest suffix", SidecarNames{ManifestSuffix: "", LockSuffix: ".lock"}},
+		{"empty lock suffix", SidecarNames{ManifestSuffix: ".json", LockSuffix: ""}},
+		{"dot only", SidecarNames{ManifestSuffix: ".", LockSuffix: ".lock"}},
+		{"no leading dot", SidecarNames{ManifestSuffix: "json", LockSuffix: ".lock"}},
+		{"whitespace", SidecarNames{ManifestSuffix: ".json ", LockSuffix: ".lock"}},
+		{"slash", SidecarNames{ManifestSuffix: ".a/b", LockSuffix: ".lock"}},
+		{"backslash", SidecarNames{ManifestSuffix: ".json", LockSuffix: `.a\b`}},
+		{"par2 suffix", SidecarNames{ManifestSuffix: ".json", LockSuffix: ".lock.PAR2"}},
+		{"same suffixes", SidecarNames{ManifestSuffix: ".meta", LockSuffix: ".META"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Error(t, tt.names.Validate())
+		})
+	}
+}
+
+// Expectation: The function should meet the table's expectations.
+func Test_ManifestNameMismatch_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		par2Path string
+		mfName   string
+		isBundle bool
+		expected string
+		mismatch bool
+	}{
+		{"matching", "/data/test.par2", "test.par2", false, "test.par2", false},
+		{"matching extension case", "/data/test.PAR2", "test.par2", false, "test.PAR2", false},
+		{"renamed set", "/data/renamed.par2", "test.par2", false, "renamed.par2", true},
+		{"renamed case", "/data/Test.par2", "test.par2", false, "Test.par2", true},
+		{"empty name", "/data/test.par2", "", false, "test.par2", false},
+		{"matching bundle", "/data/test.p2c.par2", "test.par2", true, "test.par2", false},
+		{"bundle named as bundle", "/data/test.p2c.par2", "test.p2c.par2", true, "test.par2", false},
+		{"renamed bundle", "/data/renamed.p2c.par2", "test.par2", true, "renamed.par2", true},
+		{"renamed bundle named as bundle", "/data/renamed.p2c.par2", "test.p2c.par2", true, "renamed.par2", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expected, mismatch := ManifestNameMismatch(tt.par2Path, schema.NewManifest(tt.mfName), tt.isBundle)
+			require.Equal(t, tt.expected, expected)
+			require.Equal(t, tt.mismatch, mismatch)
+		})
+	}
+
+	_, mismatch := ManifestNameMismatch("/data/test.par2", nil, false)
+	require.False(t, mismatch)
+}
@@ -1,10 +1,14 @@
 package util
 
 import (
+	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/desertwitch/par2cron/internal/flags"
+	"github.com/desertwitch/par2cron/internal/par2"
 	"github.com/desertwitch/par2cron/internal/schema"
 	"github.com/hako/durafmt"
 )
@@ -30,7 +34,13 @@ func IsPar2Volume(path string) bool {
 	}
 	root := stem[:vol]
 
-	return isVolumeNameForRoot(name, root, '+') || isVolumeNameForRoot(name, root, '-')
+	return par2.IsVolumeOf(root+schema.Par2Extension, name)
+}
+
+// IsPar2Manifest returns if the path is that of a manifest in the default
+// naming scheme (see [SidecarNames.IsManifest] for any other scheme).
+func IsPar2Manifest(path string) bool {
+	return SidecarNames{}.IsManifest(path)
 }
 
 func IsPar2Bundle(path string) bool {
@@ -43,7 +53,7 @@ func IsPar2Bundle(path string) bool {
 // Canonical members are:
 //   - <root>.par2
 //   - <root>.p2c.par2
-//   - <root>.vol<start><sep><count>.par2 (strict numeric form, <sep> is '+' or '-')
+//   - <root>.vol<start><sep><count>.par2 (see [par2.VolumeFiles])
 //
 // If par2Name is a bundle (<root>.p2c.par2), matching is normalized to <root>.
 func IsPar2SetMember(par2Name, candidate string) bool {
@@ -65,22 +75,7 @@ func IsPar2SetMember(par2Name, candidate string) bool {
 		return true
 	}
 
-	return isVolumeNameForRoot(name, root, '+') || isVolumeNameForRoot(name, root, '-')
-}
-
-func isVolumeNameForRoot(name, root string, sep byte) bool {
-	prefix := root + schema.Par2VolPrefix // <root>.vol
-	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, schema.Par2Extension) {
-		return false
-	}
-
-	tail := name[len(prefix) : len(name)-len(schema.Par2Extension)] // <start><sep><count>
-	pos := strings.IndexByte(tail, sep)
-	if pos <= 0 || pos >= len(tail)-1 || strings.IndexByte(tail[pos+1:], sep) != -1 {
-		return false
-	}
-
-	return isDigits(tail[:pos]) && isDigits(tail[pos+1:])
+	return par2.IsVolumeOf(root+schema.Par2Extension, name)
 }
 
 func isDigits(s string) bool {
@@ -116,6 +111,23 @@ func FmtDur(d time.Duration) string {
 	return durafmt.Parse(d.Round(time.Second)).String()
 }
 
+// FmtBytes returns the size as string in binary (IEC) units, e.g. "1.5 GiB".
+func FmtBytes(n int64) string {
+	const unit = 1024
+
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 func IsGlobRecursive(pattern string) bool {
 	for _, n := range []string{"/", "**"} {
 		if strings.Contains(pattern, n) {
@@ -125,3 +137,225 @@ func IsGlobRecursive(pattern string) bool {
 
 	return false
 }
+
+// DetectPar2Flavor returns the "par2" flavor from its version output.
+func DetectPar2Flavor(version string) string {
+	if strings.Contains(strings.ToLower(version), "turbo") {
+		return schema.Par2FlavorTurbo
+	}
+
+	return schema.Par2FlavorClassic
+}
+
+// par2VersionPattern matches the version number in the "par2 -V" output.
+var par2VersionPattern = regexp.MustCompile(`\d+\.\d+(?:\.\d+)?`)
+
+// ParsePar2Version returns the version number from the "par2 -V" output (such
+// as "par2cmdline version 0.8.1"), which is its first number in dotted form.
+func ParsePar2Version(version string) ([3]int, bool) {
+	match := par2VersionPattern.FindString(version)
+	if match == "" {
+		return [3]int{}, false
+	}
+
+	var v flags.Version
+	if err := v.Set(match); err != nil {
+		return [3]int{}, false
+	}
+
+	return v.Value, true
+}
+
+// HasPar2BasePath returns if the par2 arguments already set a basepath (-B),
+// in which case no basepath should be added to them by the program.
+func HasPar2BasePath(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-B") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithoutPar2BasePath returns a copy of the par2 arguments without any
+// basepath (-B), for when the program must set a basepath of its own.
+func WithoutPar2BasePath(args []string) []string {
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			out = append(out, args[i:]...)
+
+			break
+		}
+		if arg == "-B" {
+			i++
+
+			continue
+		}
+		if strings.HasPrefix(arg, "-B") {
+			continue
+		}
+		out = append(out, arg)
+	}
+
+	return out
+}
+
+// CheckPar2BasePath returns an error if the par2 arguments set a basepath
+// (-B) while the basepath mode pins it, as the two would contradict.
+func CheckPar2BasePath(args []string, mode string) error {
+	if mode != schema.BasePathSetDir && mode != schema.BasePathScanRoot {
+		return nil
+	}
+
+	if HasPar2BasePath(args) {
+		return fmt.Errorf("%s cannot be combined with a par2 basepath (-B) argument", mode)
+	}
+
+	return nil
+}
+
+// Par2SingleThreadedArgs returns the par2 command arguments with the thread
+// count set to one (-t1, as understood by par2cmdline and par2cmdline-turbo),
+// in place of any thread count given before the "--" separator. For the
+// par2cmdline flavor, which also processes files in parallel, the file thread
+// count is set to one as well (-T1).
+func Par2SingleThreadedArgs(cmdArgs []string, flavor string) []string {
+	threadArgs := []string{"-t1"}
+	if flavor == schema.Par2FlavorClassic {
+		threadArgs = append(threadArgs, "-T1")
+	}
+
+	out := make([]string, 0, len(cmdArgs)+len(threadArgs))
+
+	for i, arg := range cmdArgs {
+		if arg == "--" {
+			out = append(out, threadArgs...)
+			out = append(out, cmdArgs[i:]...)
+
+			return out
+		}
+		if strings.HasPrefix(arg, "-t") {
+			continue
+		}
+		if flavor == schema.Par2FlavorClassic && strings.HasPrefix(arg, "-T") {
+			continue
+		}
+		out = append(out, arg)
+	}
+
+	return append(out, threadArgs...)
+}
+
+// HasPar2BlockArg returns if the par2 arguments already set a block count (-b)
+// or a block size (-s), in which case no block count should be added to them.
+func HasPar2BlockArg(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		arg = strings.TrimSpace(arg)
+		if strings.HasPrefix(arg, "-b") || strings.HasPrefix(arg, "-s") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Par2VerbosityArg returns the first par2 argument setting the verbosity of
+// par2 (-q, -qq, -v or -vv), or an empty string if there is no such argument.
+func Par2VerbosityArg(args []string) string {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "-q" || arg == "-qq" || arg == "-v" || arg == "-vv" {
+			return arg
+		}
+	}
+
+	return ""
+}
+
+// CheckPar2Verbosity returns an error if a verbosity managed by the program
+// (quiet or verbose) is requested twice, or the par2 arguments already set a
+// verbosity of their own (which would conflict with the managed one).
+func CheckPar2Verbosity(args []string, quiet bool, verbose bool) error {
+	if quiet && verbose {
+		return fmt.Errorf("%w: --par2-quiet and --par2-verbose are mutually exclusive", schema.ErrPar2Verbosity)
+	}
+	if !quiet && !verbose {
+		return nil
+	}
+
+	if arg := Par2VerbosityArg(args); arg != "" {
+		return fmt.Errorf("%w: %q in par2 arguments (remove it or --par2-quiet/--par2-verbose)", schema.ErrPar2Verbosity, arg)
+	}
+
+	return nil
+}
+
+// Par2VerbosityArgs returns the par2 arguments for a verbosity managed by
+// the program, where quiet takes precedence (see [CheckPar2Verbosity]).
+func Par2VerbosityArgs(quiet bool, verbose bool) []string {
+	switch {
+	case quiet:
+		return []string{"-q"}
+	case verbose:
+		return []string{"-v"}
+	default:
+		return nil
+	}
+}
+
+// DefaultAllowedPar2Args are the par2 options permitted when an allowlist of
+// par2 arguments is in effect, only tuning redundancy, performance and output.
+// Options pointing par2 at other paths (such as -B, -a or -R) are not included.
+var DefaultAllowedPar2Args = []string{
+	"-r", "-rk", "-rm", "-rg", "-n", "-b", "-s", "-c", "-f", "-u", "-l",
+	"-m", "-t", "-T", "-q", "-qq", "-v", "-vv",
+}
+
+// CheckPar2Args returns an error for the first of the par2 arguments that is
+// not allowed. An argument is allowed if it equals an allowed one, or consists
+// of an allowed one followed by a numeric value (e.g. "-r10" for "-r").
+func CheckPar2Args(args []string, allowed []string) error {
+	for _, arg := range args {
+		if !isAllowedPar2Arg(arg, allowed) {
+			return fmt.Errorf("%w: %q", schema.ErrPar2ArgNotAllowed, arg)
+		}
+	}
+
+	return nil
+}
+
+func isAllowedPar2Arg(arg string, allowed []string) bool {
+	for _, a := range allowed {
+		if arg == a {
+			return true
+		}
+		if value, ok := strings.CutPrefix(arg, a); ok && isDigits(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateAllowedPar2Args returns an error if any of the allowed par2
+// arguments is not an option (not starting with a "-"), or the bare "--".
+func ValidateAllowedPar2Args(allowed []string) error {
+	for _, a := range allowed {
+		if len(a) < 2 || !strings.HasPrefix(a, "-") || a == "--" { //nolint:mnd
+			return fmt.Errorf("%w: %q", schema.ErrInvalidAllowedArgs, a)
+		}
+	}
+
+	return nil
+}
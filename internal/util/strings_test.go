@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/desertwitch/par2cron/internal/schema"
 	"github.com/stretchr/testify/require"
 )
 
@@ -136,6 +137,35 @@ func Test_IsPar2Bundle_Table(t *testing.T) {
 	}
 }
 
+// Expectation: The function should meet the table's expectations.
+func Test_IsPar2Manifest_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		input  string
+		expect bool
+	}{
+		{"lowercase manifest", "test.par2.json", true},
+		{"uppercase manifest", "test.PAR2.JSON", true},
+		{"with directory", "/data/folder/test.par2.json", true},
+		{"hidden file", ".test.par2.json", true},
+
+		{"plain par2 index", "test.par2", false},
+		{"plain json file", "test.json", false},
+		{"par2 lock file", "test.par2.lock", false},
+		{"no extension", "test", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expect, IsPar2Manifest(tt.input))
+		})
+	}
+}
+
 // Expectation: IsPar2SetMember should match only canonical members of one PAR2
 // set (index, bundle, strict volumes), case-insensitively.
 func Test_IsPar2SetMember_Table(t *testing.T) {
@@ -191,64 +221,6 @@ func Test_IsPar2SetMember_Table(t *testing.T) {
 	}
 }
 
-// Expectation: The function should meet the table's expectations.
-func Test_isVolumeNameForRoot_Table(t *testing.T) {
-	t.Parallel()
-
-	tests := []struct {
-		name   string
-		fname  string
-		root   string
-		sep    byte
-		expect bool
-	}{
-		// valid plus-separated volumes
-		{"basic plus", "test.vol00+01.par2", "test", '+', true},
-		{"large numbers plus", "test.vol999+100.par2", "test", '+', true},
-		{"single digits plus", "test.vol0+1.par2", "test", '+', true},
-		{"leading zeros plus", "test.vol007+010.par2", "test", '+', true},
-		{"dotted root plus", "test.backup.vol00+01.par2", "test.backup", '+', true},
-
-		// valid minus-separated volumes
-		{"basic minus", "test.vol00-01.par2", "test", '-', true},
-		{"large numbers minus", "test.vol999-100.par2", "test", '-', true},
-		{"dotted root minus", "test.backup.vol05-10.par2", "test.backup", '-', true},
-
-		// separator mismatch
-		{"plus name minus sep", "test.vol00+01.par2", "test", '-', false},
-		{"minus name plus sep", "test.vol00-01.par2", "test", '+', false},
-
-		// wrong root
-		{"different root", "other.vol00+01.par2", "test", '+', false},
-		{"longer root", "testing.vol00+01.par2", "test", '+', false},
-		{"shorter root", "tes.vol00+01.par2", "test", '+', false},
-
-		// malformed mid section
-		{"no separator", "test.vol01.par2", "test", '+', false},
-		{"double separator", "test.vol01+02+03.par2", "test", '+', false},
-		{"leading separator", "test.vol+01.par2", "test", '+', false},
-		{"trailing separator", "test.vol01+.par2", "test", '+', false},
-		{"non-digit lhs", "test.volab+01.par2", "test", '+', false},
-		{"non-digit rhs", "test.vol01+ab.par2", "test", '+', false},
-		{"empty mid", "test.vol.par2", "test", '+', false},
-
-		// wrong extension
-		{"txt extension", "test.vol00+01.txt", "test", '+', false},
-		{"no extension", "test.vol00+01", "test", '+', false},
-
-		// not a volume at all
-		{"index file", "test.par2", "test", '+', false},
-		{"no vol prefix", "test.00+01.par2", "test", '+', false},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			require.Equal(t, tt.expect, isVolumeNameForRoot(tt.fname, tt.root, tt.sep))
-		})
-	}
-}
-
 // Expectation: The function should meet the table's expectations.
 func Test_isDigits_Table(t *testing.T) {
 	t.Parallel()
@@ -360,6 +332,17 @@ func Test_TrimSuffixFold_Table(t *testing.T) {
 	}
 }
 
+// Expectation: The sizes should be formatted to strings in binary units.
+func Test_FmtBytes_Success(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "0 B", FmtBytes(0))
+	require.Equal(t, "1023 B", FmtBytes(1023))
+	require.Equal(t, "1.0 KiB", FmtBytes(1024))
+	require.Equal(t, "1.5 MiB", FmtBytes(3*512*1024))
+	require.Equal(t, "2.0 TiB", FmtBytes(2<<40))
+}
+
 // Expectation: The duration should be formatted to string with success.
 func Test_FmtDur_Success(t *testing.T) {
 	t.Parallel()
@@ -425,3 +408,307 @@ func Test_IsGlobRecursive_Table(t *testing.T) {
 		})
 	}
 }
+
+// Expectation: The function should meet the table's expectations.
+func Test_DetectPar2Flavor_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		version string
+		expect  string
+	}{
+		{"classic", "par2cmdline version 0.8.1", schema.Par2FlavorClassic},
+		{"turbo", "par2cmdline-turbo version 1.1.1", schema.Par2FlavorTurbo},
+		{"turbo uppercase", "PAR2CMDLINE-TURBO VERSION 1.1.1", schema.Par2FlavorTurbo},
+		{"unknown output", "something else", schema.Par2FlavorClassic},
+		{"empty output", "", schema.Par2FlavorClassic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expect, DetectPar2Flavor(tt.version))
+		})
+	}
+}
+
+// Expectation: ParsePar2Version should find the version number in the various "par2 -V" outputs.
+func Test_ParsePar2Version_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		version string
+		expect  [3]int
+		ok      bool
+	}{
+		{"classic", "par2cmdline version 0.8.1", [3]int{0, 8, 1}, true},
+		{"turbo", "par2cmdline-turbo version 1.1.1", [3]int{1, 1, 1}, true},
+		{"old classic", "par2cmdline version 0.4, Copyright (C) 2003 Peter Brian Clements.", [3]int{0, 4, 0}, true},
+		{"prerelease", "par2cmdline version 1.0.0-rc1", [3]int{1, 0, 0}, true},
+		{"prefixed", "par2 v0.8.0", [3]int{0, 8, 0}, true},
+		{"first number", "par2cmdline version 0.6.14 (built with gcc 4.9)", [3]int{0, 6, 14}, true},
+		{"no dotted number", "par2cmdline version 1", [3]int{}, false},
+		{"unknown output", "something else", [3]int{}, false},
+		{"empty output", "", [3]int{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := ParsePar2Version(tt.version)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.expect, got)
+		})
+	}
+}
+
+// Expectation: HasPar2BasePath should detect a basepath only before the "--" separator.
+func Test_HasPar2BasePath_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		expect bool
+	}{
+		{"no arguments", nil, false},
+		{"other arguments", []string{"-v", "-q"}, false},
+		{"attached basepath", []string{"-v", "-B/data"}, true},
+		{"basepath after separator", []string{"-v", "--", "-B/data"}, false},
+		{"lowercase option", []string{"-b/data"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expect, HasPar2BasePath(tt.args))
+		})
+	}
+}
+
+// Expectation: Any basepath arguments should be removed, but none after the separator.
+func Test_WithoutPar2BasePath_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		expect []string
+	}{
+		{"no arguments", nil, []string{}},
+		{"other arguments", []string{"-v", "-q"}, []string{"-v", "-q"}},
+		{"attached basepath", []string{"-v", "-B/data", "-q"}, []string{"-v", "-q"}},
+		{"separate basepath", []string{"-B", "/data", "-q"}, []string{"-q"}},
+		{"basepath after separator", []string{"-v", "--", "-B/data"}, []string{"-v", "--", "-B/data"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expect, WithoutPar2BasePath(tt.args))
+		})
+	}
+}
+
+// Expectation: A basepath argument should only be refused where the basepath mode pins it.
+func Test_CheckPar2BasePath_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    []string
+		mode    string
+		wantErr bool
+	}{
+		{"unset with basepath", []string{"-B/data"}, "", false},
+		{"auto with basepath", []string{"-B/data"}, schema.BasePathAuto, false},
+		{"set dir without basepath", []string{"-q"}, schema.BasePathSetDir, false},
+		{"set dir with basepath", []string{"-B/data"}, schema.BasePathSetDir, true},
+		{"scan root with basepath", []string{"-q", "-B/data"}, schema.BasePathScanRoot, true},
+		{"scan root with basepath after separator", []string{"--", "-B/data"}, schema.BasePathScanRoot, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.wantErr {
+				require.Error(t, CheckPar2BasePath(tt.args, tt.mode))
+			} else {
+				require.NoError(t, CheckPar2BasePath(tt.args, tt.mode))
+			}
+		})
+	}
+}
+
+// Expectation: The thread count should be set to one in place of any given before the "--" separator.
+func Test_Par2SingleThreadedArgs_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		flavor string
+		expect []string
+	}{
+		{"no thread count", []string{"verify", "-q", "--", "/data/a.par2"}, schema.Par2FlavorTurbo, []string{"verify", "-q", "-t1", "--", "/data/a.par2"}},
+		{"thread count", []string{"verify", "-t16", "-T2", "--", "/data/a.par2"}, schema.Par2FlavorTurbo, []string{"verify", "-T2", "-t1", "--", "/data/a.par2"}},
+		{"thread count after separator", []string{"create", "--", "/data/a.par2", "-t16"}, schema.Par2FlavorTurbo, []string{"create", "-t1", "--", "/data/a.par2", "-t16"}},
+		{"no separator", []string{"verify", "-t4"}, schema.Par2FlavorTurbo, []string{"verify", "-t1"}},
+		{"unknown flavor", []string{"verify", "-t16", "-T2", "--", "/data/a.par2"}, "", []string{"verify", "-T2", "-t1", "--", "/data/a.par2"}},
+		{"classic no thread count", []string{"verify", "-q", "--", "/data/a.par2"}, schema.Par2FlavorClassic, []string{"verify", "-q", "-t1", "-T1", "--", "/data/a.par2"}},
+		{"classic thread count", []string{"verify", "-t16", "-T2", "--", "/data/a.par2"}, schema.Par2FlavorClassic, []string{"verify", "-t1", "-T1", "--", "/data/a.par2"}},
+		{"classic no separator", []string{"verify", "-T4"}, schema.Par2FlavorClassic, []string{"verify", "-t1", "-T1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expect, Par2SingleThreadedArgs(tt.args, tt.flavor))
+		})
+	}
+}
+
+// Expectation: HasPar2BlockArg should find a block count or size argument only before the "--" separator.
+func Test_HasPar2BlockArg_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		expect bool
+	}{
+		{"no arguments", nil, false},
+		{"other arguments", []string{"-r10", "-n4"}, false},
+		{"attached block count", []string{"-r10", "-b2000"}, true},
+		{"separate block size", []string{"-s", "1048576"}, true},
+		{"spaced block count", []string{" -b 2000"}, true},
+		{"block count after separator", []string{"-r10", "--", "-b2000"}, false},
+		{"uppercase basepath", []string{"-B/data"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expect, HasPar2BlockArg(tt.args))
+		})
+	}
+}
+
+// Expectation: Par2VerbosityArg should find a verbosity argument only before the "--" separator.
+func Test_Par2VerbosityArg_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		args   []string
+		expect string
+	}{
+		{"no arguments", nil, ""},
+		{"other arguments", []string{"-r10", "-t4"}, ""},
+		{"quiet", []string{"-r10", "-q"}, "-q"},
+		{"very verbose", []string{"-vv"}, "-vv"},
+		{"first wins", []string{"-qq", "-v"}, "-qq"},
+		{"after separator", []string{"-r10", "--", "-q"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expect, Par2VerbosityArg(tt.args))
+		})
+	}
+}
+
+// Expectation: CheckPar2Verbosity should refuse conflicting managed and manual verbosities.
+func Test_CheckPar2Verbosity_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    []string
+		quiet   bool
+		verbose bool
+		wantErr bool
+	}{
+		{"nothing managed", []string{"-q"}, false, false, false},
+		{"quiet", []string{"-r10"}, true, false, false},
+		{"verbose", nil, false, true, false},
+		{"quiet and verbose", nil, true, true, true},
+		{"quiet with manual verbose", []string{"-v"}, true, false, true},
+		{"verbose with manual quiet", []string{"-qq"}, false, true, true},
+		{"manual after separator", []string{"--", "-q"}, true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := CheckPar2Verbosity(tt.args, tt.quiet, tt.verbose)
+			if tt.wantErr {
+				require.ErrorIs(t, err, schema.ErrPar2Verbosity)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+// Expectation: Par2VerbosityArgs should return the argument of the managed verbosity.
+func Test_Par2VerbosityArgs_Success(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, Par2VerbosityArgs(false, false))
+	require.Equal(t, []string{"-q"}, Par2VerbosityArgs(true, false))
+	require.Equal(t, []string{"-v"}, Par2VerbosityArgs(false, true))
+}
+
+// Expectation: CheckPar2Args should permit only allowed options, optionally followed by a numeric value.
+func Test_CheckPar2Args_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    []string
+		allowed []string
+		wantErr bool
+	}{
+		{"no arguments", nil, DefaultAllowedPar2Args, false},
+		{"default options", []string{"-r10", "-n2", "-q", "-t4", "-m512"}, DefaultAllowedPar2Args, false},
+		{"size-based redundancy", []string{"-rk100", "-rm10"}, DefaultAllowedPar2Args, false},
+		{"basepath not allowed", []string{"-q", "-B/etc"}, DefaultAllowedPar2Args, true},
+		{"recursion not allowed", []string{"-R"}, DefaultAllowedPar2Args, true},
+		{"non-numeric value", []string{"-r10B/etc"}, DefaultAllowedPar2Args, true},
+		{"combined options", []string{"-qB/etc"}, DefaultAllowedPar2Args, true},
+		{"separator not allowed", []string{"--"}, DefaultAllowedPar2Args, true},
+		{"path argument", []string{"/etc/passwd"}, DefaultAllowedPar2Args, true},
+		{"exact extension", []string{"-B/data"}, []string{"-B/data"}, false},
+		{"empty allowlist", []string{"-q"}, []string{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := CheckPar2Args(tt.args, tt.allowed)
+			if tt.wantErr {
+				require.ErrorIs(t, err, schema.ErrPar2ArgNotAllowed)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+// Expectation: ValidateAllowedPar2Args should reject entries that are not options.
+func Test_ValidateAllowedPar2Args_Table(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateAllowedPar2Args(DefaultAllowedPar2Args))
+	require.NoError(t, ValidateAllowedPar2Args([]string{"-B/data", "--long"}))
+
+	for _, bad := range []string{"", "-", "--", "r10", "/data"} {
+		require.ErrorIs(t, ValidateAllowedPar2Args([]string{bad}), schema.ErrInvalidAllowedArgs, bad)
+	}
+}
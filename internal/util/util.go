@@ -2,8 +2,14 @@ package util
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"os"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/desertwitch/par2cron/internal/bundle"
@@ -55,8 +61,237 @@ type ResultTracker struct {
 	Success  int
 	Skipped  int
 	Error    int
+
+	// RemainingDuration is the estimated duration of the jobs left unfinished
+	// by an interruption, as far as known from their manifests, with the jobs
+	// of unknown duration counted in RemainingUnknown (see AddRemaining).
+	RemainingDuration time.Duration
+	RemainingUnknown  int
+
+	// Timings is the elapsed time of the run broken down into its phases,
+	// which is nil for the operations that do not track these (see [Timings]).
+	Timings *Timings
+
+	// Filtered is the candidates dropped by the filters of the run per reason,
+	// which is nil for the operations that do not track these (see [Filtered]).
+	Filtered *Filtered
 }
 
 func NewResultTracker() ResultTracker {
 	return ResultTracker{}
 }
+
+// AddRemaining adds a job left unfinished by an interruption with its
+// estimated duration, where a zero duration is an unknown duration.
+func (r *ResultTracker) AddRemaining(est time.Duration) {
+	if est > 0 {
+		r.RemainingDuration += est
+	} else {
+		r.RemainingUnknown++
+	}
+}
+
+// TimingPhase is a phase of a run as accumulated by [Timings].
+type TimingPhase int
+
+const (
+	// PhaseWalk is walking the filesystem for jobs.
+	PhaseWalk TimingPhase = iota
+
+	// PhaseParse is reading and parsing manifests.
+	PhaseParse
+
+	// PhasePar2 is running par2.
+	PhasePar2
+)
+
+// Timings accumulates the elapsed time of a run per phase (see [TimingPhase]).
+// Parsing and par2 are summed over all concurrent work (--io-concurrency,
+// --jobs), so that the phases can then add up to more than the elapsed time.
+// A nil *Timings accumulates nothing, so that it is safe to use as such.
+type Timings struct {
+	mu     sync.Mutex
+	start  time.Time
+	phases [PhasePar2 + 1]time.Duration
+}
+
+// TimingPhases is a snapshot of the phases accumulated by [Timings], with
+// Total being the time elapsed since the creation of the [Timings].
+type TimingPhases struct {
+	Walk  time.Duration
+	Parse time.Duration
+	Par2  time.Duration
+	Total time.Duration
+}
+
+func NewTimings() *Timings {
+	return &Timings{start: time.Now()}
+}
+
+func (t *Timings) Add(phase TimingPhase, d time.Duration) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.phases[phase] += d
+}
+
+// Since adds the time elapsed since start, as to be deferred at the start.
+func (t *Timings) Since(phase TimingPhase, start time.Time) {
+	t.Add(phase, time.Since(start))
+}
+
+// StartWalk starts a scan and returns the function to end it, adding its
+// elapsed time as walking, without the parsing done meanwhile (as that is
+// already accumulated), so that no other parsing may happen during a scan.
+func (t *Timings) StartWalk() func() {
+	start, parsed := time.Now(), t.Phases().Parse
+
+	return func() {
+		elapsed := time.Since(start)
+		t.Add(PhaseWalk, max(elapsed-(t.Phases().Parse-parsed), 0))
+	}
+}
+
+func (t *Timings) Phases() TimingPhases {
+	if t == nil {
+		return TimingPhases{}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return TimingPhases{
+		Walk:  t.phases[PhaseWalk],
+		Parse: t.phases[PhaseParse],
+		Par2:  t.phases[PhasePar2],
+		Total: time.Since(t.start),
+	}
+}
+
+// Filtered counts the candidates dropped by the filters of a run per reason
+// (see the reason codes of [schema]), so that a run with nothing to do can
+// tell a tree filtered entirely apart from an empty one. A nil *Filtered
+// counts nothing, so that it is safe to use as such.
+type Filtered struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func NewFiltered() *Filtered {
+	return &Filtered{counts: make(map[string]int)}
+}
+
+func (f *Filtered) Add(reason string) {
+	f.AddN(reason, 1)
+}
+
+func (f *Filtered) AddN(reason string, n int) {
+	if f == nil || n <= 0 {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.counts[reason] += n
+}
+
+func (f *Filtered) Total() int {
+	if f == nil {
+		return 0
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var total int
+	for _, n := range f.counts {
+		total += n
+	}
+
+	return total
+}
+
+// Reasons returns the reasons counted, sorted by their name.
+func (f *Filtered) Reasons() []string {
+	if f == nil {
+		return []string{}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Sorted(maps.Keys(f.counts))
+}
+
+func (f *Filtered) Count(reason string) int {
+	if f == nil {
+		return 0
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.counts[reason]
+}
+
+// NothingToDo returns the message of a run with nothing to do, telling a tree
+// filtered entirely (such as by a misconfiguration) apart from an empty one.
+func (f *Filtered) NothingToDo() string {
+	if total := f.Total(); total > 0 {
+		return fmt.Sprintf("Nothing to do (all %d candidates filtered; will check again next run)", total)
+	}
+
+	return "Nothing to do (no PAR2 sets found; will check again next run)"
+}
+
+// Attr returns the counts as group of log attributes (by their reason).
+func (f *Filtered) Attr() slog.Attr {
+	reasons := f.Reasons()
+
+	args := make([]any, 0, 2*len(reasons)) //nolint:mnd
+	for _, reason := range reasons {
+		args = append(args, reason, f.Count(reason))
+	}
+
+	return slog.Group("filtered", args...)
+}
+
+// String returns the counts as comma-separated "reason count" pairs.
+func (f *Filtered) String() string {
+	reasons := f.Reasons()
+
+	pairs := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		pairs = append(pairs, fmt.Sprintf("%s %d", reason, f.Count(reason)))
+	}
+
+	return strings.Join(pairs, ", ")
+}
+
+// Semaphore bounds the number of concurrent operations, where a limit below
+// one is treated as one (so that the zero value of options runs sequentially).
+type Semaphore chan struct{}
+
+func NewSemaphore(limit int) Semaphore {
+	return make(Semaphore, max(limit, 1))
+}
+
+// Acquire blocks until a slot is free, or returns an error if the context is
+// done before that (in which case no slot is held and Release must not be called).
+func (s Semaphore) Acquire(ctx context.Context) error {
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("context error: %w", ctx.Err())
+	}
+}
+
+func (s Semaphore) Release() {
+	<-s
+}
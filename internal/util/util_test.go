@@ -1,8 +1,11 @@
 package util
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/desertwitch/par2cron/internal/schema"
 	"github.com/stretchr/testify/require"
 )
 
@@ -16,3 +19,105 @@ func Test_NewResultTracker_ZeroValues_Success(t *testing.T) {
 	require.Equal(t, 0, tracker.Skipped)
 	require.Equal(t, 0, tracker.Error)
 }
+
+// Expectation: The semaphore should hold up to its limit of slots, with a limit below one treated as one.
+func Test_Semaphore_Success(t *testing.T) {
+	t.Parallel()
+
+	sem := NewSemaphore(2)
+	require.Equal(t, 2, cap(sem))
+	require.NoError(t, sem.Acquire(t.Context()))
+	require.NoError(t, sem.Acquire(t.Context()))
+	require.Len(t, sem, 2)
+
+	sem.Release()
+	require.Len(t, sem, 1)
+
+	require.Equal(t, 1, cap(NewSemaphore(0)))
+}
+
+// Expectation: Acquiring a full semaphore should return an error once the context is done.
+func Test_Semaphore_Acquire_Canceled_Error(t *testing.T) {
+	t.Parallel()
+
+	sem := NewSemaphore(1)
+	require.NoError(t, sem.Acquire(t.Context()))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	require.ErrorIs(t, sem.Acquire(ctx), context.Canceled)
+	require.Len(t, sem, 1)
+}
+
+// Expectation: The phases should be accumulated, with the parsing during a scan deducted from walking.
+func Test_Timings_Success(t *testing.T) {
+	t.Parallel()
+
+	timings := NewTimings()
+
+	endWalk := timings.StartWalk()
+	timings.Add(PhaseParse, time.Hour)
+	time.Sleep(10 * time.Millisecond)
+	endWalk()
+
+	timings.Add(PhasePar2, 2*time.Second)
+	timings.Since(PhasePar2, time.Now().Add(-time.Second))
+
+	phases := timings.Phases()
+	require.Zero(t, phases.Walk) // the parsing exceeded the scan
+	require.Equal(t, time.Hour, phases.Parse)
+	require.GreaterOrEqual(t, phases.Par2, 3*time.Second)
+	require.Positive(t, phases.Total)
+
+	endWalk = timings.StartWalk()
+	time.Sleep(10 * time.Millisecond)
+	endWalk()
+	require.GreaterOrEqual(t, timings.Phases().Walk, 10*time.Millisecond)
+}
+
+// Expectation: A nil *Timings should accumulate nothing, without panicking.
+func Test_Timings_Nil_Success(t *testing.T) {
+	t.Parallel()
+
+	var timings *Timings
+
+	endWalk := timings.StartWalk()
+	timings.Add(PhaseParse, time.Second)
+	timings.Since(PhasePar2, time.Now())
+	endWalk()
+
+	require.Equal(t, TimingPhases{}, timings.Phases())
+}
+
+// Expectation: The candidates should be counted per reason, with the message telling an empty tree apart.
+func Test_Filtered_Success(t *testing.T) {
+	t.Parallel()
+
+	filtered := NewFiltered()
+	require.Equal(t, "Nothing to do (no PAR2 sets found; will check again next run)", filtered.NothingToDo())
+
+	filtered.Add(schema.ReasonTagMismatch)
+	filtered.AddN(schema.ReasonNotDue, 2)
+	filtered.AddN(schema.ReasonIgnoreFile, 0)
+
+	require.Equal(t, 3, filtered.Total())
+	require.Equal(t, []string{schema.ReasonNotDue, schema.ReasonTagMismatch}, filtered.Reasons())
+	require.Equal(t, 2, filtered.Count(schema.ReasonNotDue))
+	require.Equal(t, "not_due 2, tag_mismatch 1", filtered.String())
+	require.Equal(t, "Nothing to do (all 3 candidates filtered; will check again next run)", filtered.NothingToDo())
+	require.Equal(t, "filtered", filtered.Attr().Key)
+}
+
+// Expectation: A nil *Filtered should count nothing, without panicking.
+func Test_Filtered_Nil_Success(t *testing.T) {
+	t.Parallel()
+
+	var filtered *Filtered
+
+	filtered.Add(schema.ReasonNotDue)
+
+	require.Zero(t, filtered.Total())
+	require.Empty(t, filtered.Reasons())
+	require.Empty(t, filtered.String())
+}
@@ -0,0 +1,62 @@
+package verify
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/util"
+)
+
+// considerCaseCollisions warns of protected files of the set whose names only
+// differ by case, if the filesystem holding the set is case-insensitive (such
+// as after moving the set from a case-sensitive one), as these then resolve to
+// the same file and cannot be verified or repaired correctly. The set is only
+// parsed on a case-insensitive filesystem, so that this costs a single stat
+// otherwise. It never fails the job, leaving par2 to report on the set.
+func (prog *Service) considerCaseCollisions(ctx context.Context, job *Job) {
+	if !util.IsCaseInsensitive(prog.fsys, job.par2Path) {
+		return
+	}
+
+	sets, err := prog.parseJobSets(ctx, job)
+	if err != nil {
+		return
+	}
+
+	for _, names := range caseCollisions(sets) {
+		logger := prog.verificationLogger(ctx, job, job.par2Path)
+		logger.Warn("Protected files differ only by case on a case-insensitive filesystem (cannot be told apart)",
+			"names", names)
+	}
+}
+
+// caseCollisions returns the groups of distinct protected names within the
+// sets that only differ by case, each sorted, in the order of their first name.
+func caseCollisions(sets []par2.Set) [][]string {
+	byFold := make(map[string][]string)
+	var folds []string
+
+	for _, set := range sets {
+		for _, fp := range set.RecoverySet {
+			fold := strings.ToLower(fp.Name)
+			if !slices.Contains(byFold[fold], fp.Name) {
+				if byFold[fold] == nil {
+					folds = append(folds, fold)
+				}
+				byFold[fold] = append(byFold[fold], fp.Name)
+			}
+		}
+	}
+
+	var collisions [][]string
+	for _, fold := range folds {
+		if names := byFold[fold]; len(names) > 1 {
+			slices.Sort(names)
+			collisions = append(collisions, names)
+		}
+	}
+
+	return collisions
+}
@@ -0,0 +1,117 @@
+package verify
+
+import (
+	"io"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: Distinct names only differing by case should be grouped, repeated names not.
+func Test_caseCollisions_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		names []string
+		want  [][]string
+	}{
+		{"none", []string{"a.txt", "b.txt"}, nil},
+		{"repeated name", []string{"a.txt", "a.txt"}, nil},
+		{"collision", []string{"file.txt", "other.txt", "File.txt"}, [][]string{{"File.txt", "file.txt"}}},
+		{"multiple", []string{"B", "a", "b", "A", "FILE"}, [][]string{{"B", "b"}, {"A", "a"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			set := par2.Set{}
+			for _, name := range tt.names {
+				set.RecoverySet = append(set.RecoverySet, par2.FilePacket{Name: name})
+			}
+
+			require.Equal(t, tt.want, caseCollisions([]par2.Set{set}))
+		})
+	}
+}
+
+// Expectation: Protected names only differing by case should be warned about on a case-insensitive
+// filesystem, without parsing the set (or warning) on a case-sensitive one.
+func Test_Service_Verify_CaseCollisions_Success(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		insensitive bool
+	}{
+		{"case-sensitive", false},
+		{"case-insensitive", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var fs afero.Fs = afero.NewMemMapFs()
+			createWithManifest(t, fs, "/data/test")
+			require.NoError(t, afero.WriteFile(fs, "/data/file.txt", []byte("data"), 0o644))
+			if tt.insensitive {
+				fs = &testutil.CaseInsensitiveFs{Fs: fs}
+			} else {
+				require.NoError(t, afero.WriteFile(fs, "/data/File.txt", []byte("data"), 0o644))
+			}
+
+			var logBuf testutil.SafeBuffer
+			ls := logging.Options{
+				Logout: &logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+			_ = ls.LogLevel.Set("info")
+
+			var parsed int
+			prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+			prog.par2er = &testutil.MockPar2Handler{
+				ParseFileFunc: func(_ afero.Fs, _ string, _ bool) (*par2.File, error) {
+					parsed++
+
+					return &par2.File{Sets: []par2.Set{{
+						MainPacket: &par2.MainPacket{SliceSize: 4},
+						RecoverySet: []par2.FilePacket{
+							{FileID: par2.Hash{1}, Name: "File.txt", Size: 4},
+							{FileID: par2.Hash{2}, Name: "file.txt", Size: 4},
+						},
+					}}}, nil
+				},
+			}
+
+			results, err := prog.Verify(t.Context(), []string{"/data"}, Options{})
+			require.NoError(t, err)
+			require.Equal(t, 1, results.Success)
+
+			if tt.insensitive {
+				require.Equal(t, 2, parsed) // Geometry is recorded with the first verification.
+				require.Contains(t, logBuf.String(), "Protected files differ only by case on a case-insensitive filesystem")
+				require.Contains(t, logBuf.String(), "File.txt")
+			} else {
+				require.Equal(t, 1, parsed)
+				require.NotContains(t, logBuf.String(), "differ only by case")
+			}
+
+			_, err = prog.Verify(t.Context(), []string{"/data"}, Options{Quick: true})
+			if tt.insensitive {
+				require.ErrorIs(t, err, errQuickProblems)
+				require.Contains(t, readTestManifest(t, fs, "/data/test.par2").QuickVerification.Problems,
+					"File.txt, file.txt: protected files differ only by case on a case-insensitive filesystem")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
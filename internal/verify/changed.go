@@ -0,0 +1,105 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+)
+
+// referenceTime returns the modification time of the reference file given
+// with --changed-since, which a missing file makes a bad invocation of.
+func (prog *Service) referenceTime(path string) (time.Time, error) {
+	fi, err := prog.fsys.Stat(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: changed-since: %w", schema.ErrExitBadInvocation, err)
+	}
+
+	return fi.ModTime(), nil
+}
+
+// filterChangedSince selects the jobs whose PAR2 set or protected files (as
+// recorded at creation) were modified after the reference time, so that the
+// untouched ones are skipped by stat alone. Jobs whose protected files are not
+// known (no creation record) and forced ones are always selected.
+func (prog *Service) filterChangedSince(ctx context.Context, metas []*JobMeta, ref time.Time, opts Options) []*JobMeta {
+	filtered := make([]*JobMeta, 0, len(metas))
+
+	for _, meta := range metas {
+		if !meta.HasCreation || isForced(meta.Par2Path, opts.Force) {
+			filtered = append(filtered, meta)
+
+			continue
+		}
+
+		changed, err := prog.changedSince(ctx, meta, ref, opts)
+		if err != nil {
+			logger := prog.verificationLogger(ctx, meta, nil)
+			logger.Warn("Failed to check for changes since the reference file (verifying)", "error", err)
+
+			filtered = append(filtered, meta)
+
+			continue
+		}
+
+		if !changed {
+			logger := prog.verificationLogger(ctx, meta, nil)
+			logger.Debug("Not modified since the reference file (skipping; --changed-since)", "reason", schema.ReasonUnchanged)
+			prog.filtered.Add(schema.ReasonUnchanged)
+
+			continue
+		}
+
+		filtered = append(filtered, meta)
+	}
+
+	return filtered
+}
+
+// changedSince returns if any file of the PAR2 set or any of its protected
+// files was modified after the reference time, where a protected file that
+// can no longer be found counts as a change. A bundle is rewritten with each
+// update of its embedded manifest, so only its protected files are compared.
+func (prog *Service) changedSince(ctx context.Context, meta *JobMeta, ref time.Time, opts Options) (bool, error) {
+	if !meta.IsBundle {
+		entries, err := afero.ReadDir(prog.fsys, filepath.Dir(meta.Par2Path))
+		if err != nil {
+			return false, fmt.Errorf("failed to read directory: %w", err)
+		}
+		for _, fi := range entries {
+			if util.IsPar2SetMember(meta.Par2Path, fi.Name()) && fi.ModTime().After(ref) {
+				return true, nil
+			}
+		}
+	}
+
+	mf, err := prog.loadManifest(ctx, meta, opts)
+	if err != nil {
+		return false, fmt.Errorf("failed to load manifest: %w", err)
+	}
+	if mf == nil || mf.Creation == nil {
+		return true, nil
+	}
+
+	workingDir := filepath.Dir(meta.Par2Path)
+	if meta.ManifestPath != "" {
+		workingDir = filepath.Dir(meta.ManifestPath)
+	}
+
+	for _, el := range mf.Creation.Elements {
+		if el.Name == "" || el.IsDir {
+			continue
+		}
+
+		fi, err := util.LstatIfPossible(prog.fsys, filepath.Join(workingDir, el.Name))
+		if err != nil || fi.ModTime().After(ref) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,111 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: Only the PAR2 sets whose PAR2 files or protected files were modified after the
+// reference file should be verified (regardless of --age), along with those that cannot be told.
+func Test_Service_Verify_ChangedSince_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	ref := time.Now().Add(-time.Hour)
+	older, newer := ref.Add(-time.Hour), ref.Add(time.Minute)
+
+	require.NoError(t, afero.WriteFile(fs, "/ref", nil, 0o644))
+	require.NoError(t, fs.Chtimes("/ref", ref, ref))
+
+	for _, dir := range []string{"untouched", "file", "volume", "missing", "external"} {
+		path := filepath.Join("/data", dir, "test")
+		createWithManifest(t, fs, path)
+		require.NoError(t, afero.WriteFile(fs, filepath.Join("/data", dir, "a.txt"), []byte("content a"), 0o644))
+
+		if dir != "external" {
+			mfPath := path + schema.Par2Extension + schema.ManifestExtension
+			data, err := afero.ReadFile(fs, mfPath)
+			require.NoError(t, err)
+
+			var mf schema.Manifest
+			require.NoError(t, json.Unmarshal(data, &mf))
+			mf.Creation.Elements = []schema.FsElement{{Name: "a.txt", Size: 9}}
+			mf.Verification = schema.NewVerificationManifest()
+			mf.Verification.Time = time.Now()
+
+			data, err = json.Marshal(mf)
+			require.NoError(t, err)
+			require.NoError(t, afero.WriteFile(fs, mfPath, data, 0o644))
+		}
+
+		for _, name := range []string{"test" + schema.Par2Extension, "a.txt"} {
+			require.NoError(t, fs.Chtimes(filepath.Join("/data", dir, name), older, older))
+		}
+	}
+
+	require.NoError(t, fs.Chtimes("/data/file/a.txt", newer, newer))
+	require.NoError(t, afero.WriteFile(fs, "/data/volume/test.vol0+1"+schema.Par2Extension, []byte("par2data"), 0o644))
+	require.NoError(t, fs.Remove("/data/missing/a.txt"))
+
+	// An external set has no creation record telling its protected files.
+	data, err := json.Marshal(schema.NewManifest("test" + schema.Par2Extension))
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "/data/external/test"+schema.Par2Extension+schema.ManifestExtension, data, 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	var verified []string
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			verified = append(verified, workingDir)
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	opts := Options{ChangedSince: "/ref"}
+	require.NoError(t, opts.MinAge.Set("24h"))
+	res, err := prog.Verify(t.Context(), []string{"/data"}, opts)
+
+	require.NoError(t, err)
+	require.Equal(t, 4, res.Selected)
+
+	slices.Sort(verified)
+	require.Equal(t, []string{"/data/external", "/data/file", "/data/missing", "/data/volume"}, verified)
+}
+
+// Expectation: A missing reference file should fail the run as a bad invocation.
+func Test_Service_Verify_ChangedSince_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	_, err := prog.Verify(t.Context(), []string{"/data"}, Options{ChangedSince: "/missing"})
+	require.ErrorIs(t, err, schema.ErrExitBadInvocation)
+}
@@ -0,0 +1,198 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"sync"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+)
+
+// checkpointState is the content of a checkpoint file (--checkpoint).
+type checkpointState struct {
+	// CycleID identifies the pass (over the scanned roots) of the checkpoint.
+	CycleID string `json:"cycle_id"`
+
+	// Started is when the pass of the checkpoint was started.
+	Started time.Time `json:"started"`
+
+	// Seed is the seed of the random order of the pass (--order random), so
+	// that a resumed pass continues in its original order (on another day).
+	Seed uint64 `json:"seed"`
+
+	// Done are the PAR2 sets that were processed within the pass.
+	Done []string `json:"done"`
+}
+
+// checkpoint records the PAR2 sets processed within a pass over the scanned
+// roots, so that a restarted run (--resume) continues with the remainder of
+// the pass instead of starting over. It is nil without a --checkpoint, with
+// all methods then no-ops.
+type checkpoint struct {
+	mu    sync.Mutex
+	fsys  afero.Fs
+	path  string
+	state checkpointState
+	done  map[string]struct{}
+}
+
+// checkpointCycleID returns the cycle ID of a pass over the scanned roots,
+// so that a checkpoint is only resumed by a run over the same roots.
+func checkpointCycleID(rootDirs []string) string {
+	h := fnv.New64a()
+
+	for _, rootDir := range rootDirs {
+		_, _ = h.Write([]byte(rootDir))
+		_, _ = h.Write([]byte{0})
+	}
+
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// openCheckpoint returns the checkpoint of the pass to resume (with --resume),
+// or one of a new pass, which is written right away (so that no checkpoint of
+// an earlier pass can be resumed anymore). It returns nil without --checkpoint.
+func (prog *Service) openCheckpoint(ctx context.Context, rootDirs []string, opts Options, now time.Time) *checkpoint {
+	if opts.Checkpoint == "" {
+		return nil
+	}
+
+	logger := prog.verificationLogger(ctx, nil, opts.Checkpoint)
+	cycleID := checkpointCycleID(rootDirs)
+
+	if opts.Resume {
+		state, err := readCheckpoint(prog.fsys, opts.Checkpoint)
+		switch {
+		case err == nil && state.CycleID == cycleID:
+			cp := &checkpoint{fsys: prog.fsys, path: opts.Checkpoint, state: *state, done: make(map[string]struct{}, len(state.Done))}
+			for _, path := range state.Done {
+				cp.done[path] = struct{}{}
+			}
+			logger.Info("Resuming from checkpoint (--resume)",
+				"cycleID", cycleID, "started", state.Started.Format(time.RFC3339), "doneJobs", len(cp.done))
+
+			return cp
+
+		case err == nil:
+			logger.Info("Checkpoint is of a pass over other directories (starting a new pass)",
+				"cycleID", cycleID, "checkpointCycleID", state.CycleID)
+
+		case errors.Is(err, fs.ErrNotExist):
+			logger.Info("No checkpoint to resume (starting a new pass)", "cycleID", cycleID)
+
+		default:
+			logger.Warn("Failed to read checkpoint (starting a new pass)", "cycleID", cycleID, "error", err)
+		}
+	}
+
+	cp := &checkpoint{
+		fsys:  prog.fsys,
+		path:  opts.Checkpoint,
+		state: checkpointState{CycleID: cycleID, Started: now, Seed: dailySeed(now, rootDirs), Done: []string{}},
+		done:  make(map[string]struct{}),
+	}
+	if err := cp.save(); err != nil {
+		logger.Warn("Failed to write checkpoint (cannot --resume this pass)", "error", err)
+	}
+
+	return cp
+}
+
+func readCheckpoint(fsys afero.Fs, path string) (*checkpointState, error) {
+	data, err := afero.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read: %w", err)
+	}
+
+	var state checkpointState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal: %w", err)
+	}
+
+	return &state, nil
+}
+
+// seed returns the seed of the random order of the pass, or the daily seed.
+func (cp *checkpoint) seed(now time.Time, rootDirs []string) uint64 {
+	if cp == nil {
+		return dailySeed(now, rootDirs)
+	}
+
+	return cp.state.Seed
+}
+
+// pending returns the jobs not yet processed within the pass, in their order.
+func (cp *checkpoint) pending(metas []*JobMeta) []*JobMeta {
+	if cp == nil || len(cp.done) == 0 {
+		return metas
+	}
+
+	pending := make([]*JobMeta, 0, len(metas))
+	for _, meta := range metas {
+		if _, ok := cp.done[meta.Par2Path]; !ok {
+			pending = append(pending, meta)
+		}
+	}
+
+	return pending
+}
+
+// markDone records the job as processed within the pass.
+func (cp *checkpoint) markDone(path string) error {
+	if cp == nil {
+		return nil
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if _, ok := cp.done[path]; ok {
+		return nil
+	}
+	cp.done[path] = struct{}{}
+	cp.state.Done = append(cp.state.Done, path)
+
+	return cp.saveLocked()
+}
+
+// clear removes the checkpoint, once the pass was completed.
+func (cp *checkpoint) clear() error {
+	if cp == nil {
+		return nil
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if err := cp.fsys.Remove(cp.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove: %w", err)
+	}
+
+	return nil
+}
+
+func (cp *checkpoint) save() error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	return cp.saveLocked()
+}
+
+func (cp *checkpoint) saveLocked() error {
+	data, err := json.MarshalIndent(cp.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal: %w", err)
+	}
+
+	if err := util.WriteFileAtomic(cp.fsys, cp.path, data); err != nil {
+		return fmt.Errorf("failed to write: %w", err)
+	}
+
+	return nil
+}
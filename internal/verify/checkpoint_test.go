@@ -0,0 +1,262 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+const testCheckpoint = "/state/verify.checkpoint"
+
+// newCheckpointService returns a service whose runner records the working
+// directories of its verifications, calling the hook (if any) before each.
+func newCheckpointService(t *testing.T, fs afero.Fs, logBuf *testutil.SafeBuffer, dirs *[]string, hook func(n int) error) *Service {
+	t.Helper()
+
+	ls := logging.Options{
+		Logout: logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var mu sync.Mutex
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			*dirs = append(*dirs, workingDir)
+			if hook != nil {
+				return hook(len(*dirs))
+			}
+
+			return nil
+		},
+	}
+
+	return NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+}
+
+func readTestCheckpoint(t *testing.T, fs afero.Fs) *checkpointState {
+	t.Helper()
+
+	state, err := readCheckpoint(fs, testCheckpoint)
+	require.NoError(t, err)
+
+	return state
+}
+
+// Expectation: An interrupted run should leave a checkpoint of its completed jobs, with a resumed
+// run then only processing the remainder in the original (random) order and clearing the checkpoint.
+func Test_Service_Verify_Checkpoint_InterruptResume_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/a/test")
+	createWithManifest(t, fs, "/data/b/test")
+	createWithManifest(t, fs, "/data/c/test")
+	createWithManifest(t, fs, "/data/d/test")
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var logBuf testutil.SafeBuffer
+	var firstDirs []string
+	prog := newCheckpointService(t, fs, &logBuf, &firstDirs, func(n int) error {
+		if n == 2 {
+			cancel()
+
+			return context.Canceled
+		}
+
+		return nil
+	})
+
+	args := Options{Checkpoint: testCheckpoint}
+	require.NoError(t, args.Order.Set("random"))
+
+	_, err := prog.Verify(ctx, []string{"/data"}, args)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, firstDirs, 2)
+
+	state := readTestCheckpoint(t, fs)
+	require.Equal(t, checkpointCycleID([]string{"/data"}), state.CycleID)
+	require.Equal(t, []string{firstDirs[0] + "/test.par2"}, state.Done)
+
+	// The full (original) order of the pass, as the interrupted run would have processed it.
+	var fullDirs []string
+	ordered := newCheckpointService(t, afero.NewCopyOnWriteFs(fs, afero.NewMemMapFs()), &testutil.SafeBuffer{}, &fullDirs, nil)
+	_, err = ordered.Verify(t.Context(), []string{"/data"}, Options{Order: args.Order})
+	require.NoError(t, err)
+	require.Len(t, fullDirs, 4)
+	require.Equal(t, firstDirs, fullDirs[:2])
+
+	logBuf.Reset()
+	var secondDirs []string
+	prog = newCheckpointService(t, fs, &logBuf, &secondDirs, nil)
+	args.Resume = true
+
+	results, err := prog.Verify(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	require.Equal(t, fullDirs[1:], secondDirs)
+	require.Equal(t, 3, results.Success)
+	require.Contains(t, logBuf.String(), "Resuming from checkpoint")
+	require.Contains(t, logBuf.String(), "doneJobs=1")
+
+	exists, err := afero.Exists(fs, testCheckpoint)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+// Expectation: A run ended by --limit should keep its checkpoint, which is only cleared
+// by the (resumed) run completing the pass.
+func Test_Service_Verify_Checkpoint_Limit_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/a/test")
+	createWithManifest(t, fs, "/data/b/test")
+	createWithManifest(t, fs, "/data/c/test")
+
+	var logBuf testutil.SafeBuffer
+	var dirs []string
+	prog := newCheckpointService(t, fs, &logBuf, &dirs, nil)
+
+	args := Options{Checkpoint: testCheckpoint, Resume: true, Limit: 2}
+
+	_, err := prog.Verify(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+	require.Equal(t, []string{"/data/a", "/data/b"}, dirs)
+	require.Contains(t, logBuf.String(), "No checkpoint to resume")
+	require.Equal(t, []string{"/data/a/test.par2", "/data/b/test.par2"}, readTestCheckpoint(t, fs).Done)
+
+	dirs = nil
+	_, err = prog.Verify(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+	require.Equal(t, []string{"/data/c"}, dirs)
+
+	exists, err := afero.Exists(fs, testCheckpoint)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+// Expectation: Failed jobs should not be recorded as done, so that they are retried on --resume.
+func Test_Service_Verify_Checkpoint_FailedJob_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/a/test")
+	createWithManifest(t, fs, "/data/b/test")
+	createWithManifest(t, fs, "/data/c/test")
+
+	var dirs []string
+	prog := newCheckpointService(t, fs, &testutil.SafeBuffer{}, &dirs, func(n int) error {
+		if n == 1 {
+			return errors.New("disk I/O error")
+		}
+
+		return nil
+	})
+
+	args := Options{Checkpoint: testCheckpoint, Limit: 2}
+
+	_, err := prog.Verify(t.Context(), []string{"/data"}, args)
+	require.Error(t, err)
+	require.Equal(t, []string{"/data/b/test.par2"}, readTestCheckpoint(t, fs).Done)
+}
+
+// Expectation: Without --resume, or with a checkpoint of other directories, a new pass
+// should be started (including the jobs done before) and the checkpoint be replaced.
+func Test_Service_Verify_Checkpoint_NewPass_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cycleID string
+		resume  bool
+		log     string
+	}{
+		{"without resume", checkpointCycleID([]string{"/data"}), false, ""},
+		{"other directories", checkpointCycleID([]string{"/other"}), true, "Checkpoint is of a pass over other directories"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			createWithManifest(t, fs, "/data/a/test")
+			createWithManifest(t, fs, "/data/b/test")
+
+			by, err := json.Marshal(checkpointState{CycleID: tt.cycleID, Done: []string{"/data/a/test.par2"}})
+			require.NoError(t, err)
+			require.NoError(t, afero.WriteFile(fs, testCheckpoint, by, 0o644))
+
+			var logBuf testutil.SafeBuffer
+			var dirs []string
+			prog := newCheckpointService(t, fs, &logBuf, &dirs, nil)
+
+			args := Options{Checkpoint: testCheckpoint, Resume: tt.resume, Limit: 1}
+
+			_, err = prog.Verify(t.Context(), []string{"/data"}, args)
+			require.NoError(t, err)
+			require.Equal(t, []string{"/data/a"}, dirs)
+			require.NotContains(t, logBuf.String(), "Resuming from checkpoint")
+			if tt.log != "" {
+				require.Contains(t, logBuf.String(), tt.log)
+			}
+
+			state := readTestCheckpoint(t, fs)
+			require.Equal(t, checkpointCycleID([]string{"/data"}), state.CycleID)
+			require.Equal(t, []string{"/data/a/test.par2"}, state.Done)
+		})
+	}
+}
+
+// Expectation: A resumed checkpoint should keep the seed of its pass (for --order random).
+func Test_Service_openCheckpoint_Seed_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	roots := []string{"/data"}
+	started := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := started.Add(72 * time.Hour)
+
+	prog := newCheckpointService(t, fs, &testutil.SafeBuffer{}, &[]string{}, nil)
+
+	cp := prog.openCheckpoint(t.Context(), roots, Options{Checkpoint: testCheckpoint}, started)
+	require.Equal(t, dailySeed(started, roots), cp.seed(now, roots))
+
+	cp = prog.openCheckpoint(t.Context(), roots, Options{Checkpoint: testCheckpoint, Resume: true}, now)
+	require.Equal(t, dailySeed(started, roots), cp.seed(now, roots))
+	require.Equal(t, started, cp.state.Started)
+
+	cp = prog.openCheckpoint(t.Context(), roots, Options{}, now)
+	require.Nil(t, cp)
+	require.Equal(t, dailySeed(now, roots), cp.seed(now, roots))
+	require.NoError(t, cp.markDone("/data/a/test.par2"))
+	require.NoError(t, cp.clear())
+}
+
+// Expectation: The options should be refused with --resume but no --checkpoint.
+func Test_Options_Validate_Resume_Error(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{Resume: true}
+	require.ErrorContains(t, opts.Validate(), "resume: requires --checkpoint")
+
+	opts = Options{Resume: true, Checkpoint: testCheckpoint}
+	require.NoError(t, opts.Validate())
+}
@@ -0,0 +1,161 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/spf13/afero"
+)
+
+const (
+	// maxClassifierOutput is the length of the par2 output (its tail) that is
+	// passed to the classifier, bounding the memory held for long outputs.
+	maxClassifierOutput = 1 << 20
+
+	// maxclassifierResult is the length of the classifier output that is read.
+	maxclassifierResult = 64 << 10
+)
+
+// The classes a classifier (--classifier) returns for a par2 verification,
+// where an error is no verification result (failing the job).
+const (
+	classifierClean        = "clean"
+	classifierRepairable   = "repairable"
+	classifierUnrepairable = "unrepairable"
+	classifierError        = "error"
+)
+
+// par2ExitClassError is the class of a verification the classifier found to
+// be no verification result, next to the built-in classes (of exit codes).
+const par2ExitClassError = "error"
+
+var errClassifiedError = errors.New("par2 result classified as error by classifier")
+
+// classifierInput is the JSON document passed to the classifier, as the file
+// whose path is its only argument: the par2 exit code and output (the tail of
+// its standard output and error) of the verification of the PAR2 set.
+type classifierInput struct {
+	Operation string `json:"operation"`
+	Par2Path  string `json:"par2_path"`
+	ExitCode  int    `json:"exit_code"`
+	Output    string `json:"output"`
+}
+
+// classifierResult is the JSON document the classifier writes to its standard
+// output, with one of the classes (and an optional reason for the logs).
+type classifierResult struct {
+	Class  string `json:"class"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	buf []byte
+	max int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.max; over > 0 {
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+	}
+
+	return len(p), nil
+}
+
+// limitedBuffer keeps the first max bytes written to it, discarding the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+
+	return len(p), nil
+}
+
+// classify returns the class of a par2 verification with its exit code and
+// output, as returned by the classifier (--classifier). A classifier that fails
+// or returns no known class is warned about, returning an empty class for the
+// built-in classification by the exit code to be used instead.
+func (prog *Service) classify(ctx context.Context, job *Job, basePath string, exitCode int, output []byte) string {
+	logger := prog.verificationLogger(ctx, job, job.par2Path)
+
+	res, err := prog.runClassifier(ctx, job, basePath, classifierInput{
+		Operation: "verify",
+		Par2Path:  job.par2Path,
+		ExitCode:  exitCode,
+		Output:    string(output),
+	})
+	if err != nil {
+		logger.Warn("Failed to classify par2 result (falling back to built-in classification)",
+			"classifier", job.classifier, "error", err)
+
+		return ""
+	}
+
+	var class string
+	switch res.Class {
+	case classifierClean:
+		class = schema.Par2ExitClassSuccess
+	case classifierRepairable:
+		class = schema.Par2ExitClassRepairable
+	case classifierUnrepairable:
+		class = schema.Par2ExitClassUnrepairable
+	case classifierError:
+		class = par2ExitClassError
+	default:
+		logger.Warn("Classifier returned an unknown class (falling back to built-in classification)",
+			"classifier", job.classifier, "class", res.Class)
+
+		return ""
+	}
+
+	logger.Debug("Classified par2 result by classifier", "class", res.Class, "reason", res.Reason, "exitCode", exitCode)
+
+	return class
+}
+
+// runClassifier runs the classifier with the input written into a temporary
+// file, returning its result as decoded from its standard output.
+func (prog *Service) runClassifier(ctx context.Context, job *Job, basePath string, in classifierInput) (*classifierResult, error) {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal input: %w", err)
+	}
+
+	f, err := afero.TempFile(prog.fsys, "", "par2cron-classifier-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create input file: %w", err)
+	}
+	defer prog.fsys.Remove(f.Name()) //nolint:errcheck
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+
+		return nil, fmt.Errorf("failed to write input file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close input file: %w", err)
+	}
+
+	out := &limitedBuffer{max: maxclassifierResult}
+	if err := prog.runner.Run(ctx, job.classifier, []string{f.Name()}, basePath, out, prog.log.Options.Stderr); err != nil {
+		return nil, fmt.Errorf("failed to run: %w", err)
+	}
+
+	res := &classifierResult{}
+	if err := json.NewDecoder(&out.Buffer).Decode(res); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,133 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: The classifier should be passed the par2 exit code and output, with its class taking
+// precedence over the exit code, and any failure of it falling back to the built-in classification.
+func Test_Service_Verify_Classifier_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		classifier       string
+		par2Code         int
+		result           string
+		resultErr        error
+		wantCalls        int
+		wantErr          error
+		wantRepairNeeded bool
+		wantFallback     bool
+	}{
+		{"unset", "", schema.Par2ExitCodeRepairPossible, `{"class":"clean"}`, nil, 0, schema.ErrExitRepairable, true, false},
+		{"clean over corruption", "classify", schema.Par2ExitCodeRepairPossible, `{"class":"clean"}`, nil, 1, nil, false, false},
+		{"repairable over success", "classify", schema.Par2ExitCodeSuccess, `{"class":"repairable","reason":"odd fork"}`, nil, 1, schema.ErrExitRepairable, true, false},
+		{"unrepairable", "classify", schema.Par2ExitCodeRepairPossible, `{"class":"unrepairable"}`, nil, 1, schema.ErrExitUnrepairable, true, false},
+		{"error", "classify", schema.Par2ExitCodeSuccess, `{"class":"error"}`, nil, 1, errClassifiedError, false, false},
+		{"failing classifier", "classify", schema.Par2ExitCodeRepairPossible, "", errors.New("exit status 3"), 1, schema.ErrExitRepairable, true, true},
+		{"invalid result", "classify", schema.Par2ExitCodeRepairPossible, "not json", nil, 1, schema.ErrExitRepairable, true, true},
+		{"unknown class", "classify", schema.Par2ExitCodeSuccess, `{"class":"maybe"}`, nil, 1, nil, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			createWithManifest(t, fs, "/data/test")
+
+			logBuf := &testutil.SafeBuffer{}
+			ls := logging.Options{
+				Logout: logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+
+			var calls int
+			var input classifierInput
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					if cmd == "par2" {
+						fmt.Fprintln(stdout, "Target: \"a.txt\" - found.")
+						if tt.par2Code == schema.Par2ExitCodeSuccess {
+							return nil
+						}
+
+						return testutil.CreateExitError(t, ctx, tt.par2Code)
+					}
+
+					calls++
+					require.Equal(t, tt.classifier, cmd)
+					require.Equal(t, "/data", workingDir)
+					require.Len(t, args, 1)
+
+					data, err := afero.ReadFile(fs, args[0])
+					require.NoError(t, err)
+					require.NoError(t, json.Unmarshal(data, &input))
+
+					fmt.Fprint(stdout, tt.result)
+
+					return tt.resultErr
+				},
+			}
+
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+			_, err := prog.Verify(t.Context(), []string{"/data"}, Options{Classifier: tt.classifier})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantCalls, calls)
+			require.Equal(t, tt.wantFallback, strings.Contains(logBuf.String(), "falling back to built-in classification"))
+
+			if tt.wantCalls > 0 {
+				require.Equal(t, classifierInput{
+					Operation: "verify",
+					Par2Path:  "/data/test" + schema.Par2Extension,
+					ExitCode:  tt.par2Code,
+					Output:    "Target: \"a.txt\" - found.\n",
+				}, input)
+
+				files, err := afero.Glob(fs, filepath.Join(os.TempDir(), "par2cron-classifier-*"))
+				require.NoError(t, err)
+				require.Empty(t, files)
+			}
+
+			data, err := afero.ReadFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension)
+			require.NoError(t, err)
+
+			var mf schema.Manifest
+			require.NoError(t, json.Unmarshal(data, &mf))
+			require.Equal(t, tt.wantRepairNeeded, mf.Verification != nil && mf.Verification.RepairNeeded)
+		})
+	}
+}
+
+// Expectation: Only the tail of the output should be kept.
+func Test_tailBuffer_Success(t *testing.T) {
+	t.Parallel()
+
+	b := &tailBuffer{max: 4}
+	_, _ = b.Write([]byte("abc"))
+	_, _ = b.Write([]byte("def"))
+
+	require.Equal(t, "cdef", string(b.buf))
+}
@@ -0,0 +1,55 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+)
+
+var errCrossCheckFailed = errors.New("cross-check failed despite clean par2 verification")
+
+// crossCheckFailed returns if the cross-check (--cross-check) of the job ran
+// and failed, so that the PAR2 set is not clean despite the verdict of par2.
+func (job *Job) crossCheckFailed() bool {
+	return job.crossCheck != "" && job.manifest.CrossCheck != nil && job.manifest.CrossCheck.Failed
+}
+
+// runCrossCheck runs the cross-check (--cross-check) of a PAR2 set that par2
+// found clean, with the path of the PAR2 set as its only argument, in the
+// directory the protected files are resolved against. Its exit code not being
+// zero fails the cross-check, while a failure to run it at all is only warned
+// about (leaving the PAR2 set not cross-checked). Only an interruption of the
+// cross-check is returned as error.
+func (prog *Service) runCrossCheck(ctx context.Context, job *Job, basePath string) error {
+	cc := schema.NewCrossCheckManifest(job.crossCheck)
+
+	cc.Time = time.Now()
+	err := prog.runner.Run(ctx, job.crossCheck, []string{job.par2Path}, basePath, prog.log.Options.Stdout, prog.log.Options.Stderr)
+	cc.Duration = time.Since(cc.Time)
+	prog.timings.Add(util.PhasePar2, cc.Duration)
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("context error: %w", ctxErr)
+		}
+
+		c := util.AsExitCode(err)
+		if c == nil {
+			logger := prog.verificationLogger(ctx, job, job.par2Path)
+			logger.Warn("Failed to run cross-check (not cross-checked)", "crossCheck", job.crossCheck, "error", err)
+
+			return nil
+		}
+
+		cc.ExitCode = *c
+		cc.Failed = true
+	}
+
+	job.manifest.CrossCheck = cc
+
+	return nil
+}
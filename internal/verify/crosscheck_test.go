@@ -0,0 +1,123 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: The cross-check should only be run over a clean par2 verification, with only both agreeing
+// making the PAR2 set clean, and a cross-check that cannot be run at all only being warned about.
+func Test_Service_Verify_CrossCheck_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		crossCheck   string
+		par2Code     int
+		checkErr     func(t *testing.T, ctx context.Context) error
+		wantCalls    int
+		wantErr      error
+		wantRecorded bool
+		wantFailed   bool
+		wantClean    int
+	}{
+		{"unset", "", schema.Par2ExitCodeSuccess, nil, 0, nil, false, false, 1},
+		{"agreeing", "check", schema.Par2ExitCodeSuccess, nil, 1, nil, true, false, 1},
+		{"disagreeing", "check", schema.Par2ExitCodeSuccess, func(t *testing.T, ctx context.Context) error {
+			t.Helper()
+
+			return testutil.CreateExitError(t, ctx, 1)
+		}, 1, errCrossCheckFailed, true, true, 0},
+		{"corrupted by par2", "check", schema.Par2ExitCodeRepairPossible, nil, 0, schema.ErrExitRepairable, false, false, 0},
+		{"not runnable", "check", schema.Par2ExitCodeSuccess, func(t *testing.T, ctx context.Context) error {
+			t.Helper()
+
+			return errors.New("executable file not found")
+		}, 1, nil, false, false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			createWithManifest(t, fs, "/data/test")
+
+			logBuf := &testutil.SafeBuffer{}
+			ls := logging.Options{
+				Logout: logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+
+			var calls int
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					if cmd == "par2" {
+						if tt.par2Code == schema.Par2ExitCodeSuccess {
+							return nil
+						}
+
+						return testutil.CreateExitError(t, ctx, tt.par2Code)
+					}
+
+					calls++
+					require.Equal(t, tt.crossCheck, cmd)
+					require.Equal(t, []string{"/data/test" + schema.Par2Extension}, args)
+					require.Equal(t, "/data", workingDir)
+
+					if tt.checkErr != nil {
+						return tt.checkErr(t, ctx)
+					}
+
+					return nil
+				},
+			}
+
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+			res, err := prog.Verify(t.Context(), []string{"/data"}, Options{CrossCheck: tt.crossCheck})
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Equal(t, 1, res.Error)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, 1, res.Success)
+			}
+			require.Equal(t, tt.wantCalls, calls)
+			require.Equal(t, tt.name == "not runnable", strings.Contains(logBuf.String(), "Failed to run cross-check"))
+
+			data, err := afero.ReadFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension)
+			require.NoError(t, err)
+
+			var mf schema.Manifest
+			require.NoError(t, json.Unmarshal(data, &mf))
+			require.Equal(t, tt.wantRecorded, mf.CrossCheck != nil)
+			if tt.wantRecorded {
+				require.Equal(t, tt.crossCheck, mf.CrossCheck.Command)
+				require.Equal(t, tt.wantFailed, mf.CrossCheck.Failed)
+			}
+			require.NotNil(t, mf.Health)
+			require.Equal(t, tt.wantClean, mf.Health.CountClean)
+		})
+	}
+}
+
+// Expectation: A cross-check command that cannot be found should fail the validation of the options.
+func Test_Options_Validate_CrossCheck_Error(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{CrossCheck: "/nonexistent/par2cron-cross-check"}
+	require.ErrorContains(t, opts.Validate(), "cross-check")
+}
@@ -0,0 +1,170 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// createDereferencedSet writes a PAR2 set into the parity tree, with its
+// manifest (recording the PAR2 set as relative par2_location) into the
+// data tree, next to the protected file.
+func createDereferencedSet(t *testing.T, fs afero.Fs) {
+	t.Helper()
+
+	require.NoError(t, fs.MkdirAll("/parity/movies", 0o755))
+	require.NoError(t, fs.MkdirAll("/data/movies", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/parity/movies/movies"+schema.Par2Extension, []byte("par2data"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/movies/a.mkv", []byte("content"), 0o644))
+
+	hash, err := util.HashFile(fs, "/parity/movies/movies"+schema.Par2Extension)
+	require.NoError(t, err)
+
+	mf := schema.NewManifest("movies" + schema.Par2Extension)
+	mf.SHA256 = hash
+	mf.Par2Location = "../../parity/movies/movies" + schema.Par2Extension
+	mf.Creation = schema.NewCreationManifest()
+	mf.Creation.Elements = []schema.FsElement{{Name: "a.mkv", Size: 7, Mode: 0o644}}
+
+	data, err := json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "/data/movies/movies"+schema.Par2Extension+schema.ManifestExtension, data, 0o644))
+}
+
+// Expectation: A manifest without its PAR2 set should be dereferenced to the PAR2 set in the
+// sibling parity tree with the option, running par2 against it with the data as basepath.
+func Test_Service_Verify_DereferenceManifest_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createDereferencedSet(t, fs)
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	var runArgs []string
+	var runDir string
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			runArgs = args
+			runDir = workingDir
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	res, err := prog.Verify(t.Context(), []string{"/data"}, Options{DereferenceManifest: true, CleanOrphans: true})
+
+	require.NoError(t, err)
+	require.Equal(t, 1, res.Success)
+	require.Equal(t, []string{"verify", "-B/data/movies", "--", "/parity/movies/movies" + schema.Par2Extension}, runArgs)
+	require.Equal(t, "/data/movies", runDir)
+
+	data, err := afero.ReadFile(fs, "/data/movies/movies"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+
+	var mf schema.Manifest
+	require.NoError(t, json.Unmarshal(data, &mf))
+	require.NotNil(t, mf.Verification)
+	require.Equal(t, 1, mf.Verification.Count)
+	require.Equal(t, "../../parity/movies/movies"+schema.Par2Extension, mf.Par2Location)
+
+	exists, err := afero.Exists(fs, "/parity/movies/movies"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+// Expectation: A manifest of a PAR2 set stored elsewhere should be neither a job
+// without the option, nor removed as an orphaned manifest with --clean-orphans.
+func Test_Service_Verify_DereferenceManifest_Off_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createDereferencedSet(t, fs)
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	var calls int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			calls++
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	_, err := prog.Verify(t.Context(), []string{"/data"}, Options{CleanOrphans: true})
+
+	require.NoError(t, err)
+	require.Zero(t, calls)
+
+	exists, err := afero.Exists(fs, "/data/movies/movies"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+// Expectation: A PAR2 set stored elsewhere that no longer exists should fail as a partial failure.
+func Test_Service_Verify_DereferenceManifest_Missing_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createDereferencedSet(t, fs)
+	require.NoError(t, fs.Remove("/parity/movies/movies"+schema.Par2Extension))
+
+	logBuf := &testutil.SafeBuffer{}
+	ls := logging.Options{
+		Logout: logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	_, err := prog.Verify(t.Context(), []string{"/data"}, Options{DereferenceManifest: true})
+
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+	require.Contains(t, logBuf.String(), schema.ReasonDereference)
+}
+
+// Expectation: A manifest reset for a changed PAR2 set stored elsewhere should keep its par2_location.
+func Test_Service_Verify_DereferenceManifest_Reset_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createDereferencedSet(t, fs)
+	require.NoError(t, afero.WriteFile(fs, "/parity/movies/movies"+schema.Par2Extension, []byte("changed"), 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	_, err := prog.Verify(t.Context(), []string{"/data"}, Options{DereferenceManifest: true})
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, "/data/movies/movies"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+
+	var mf schema.Manifest
+	require.NoError(t, json.Unmarshal(data, &mf))
+	require.Nil(t, mf.Creation)
+	require.Equal(t, "../../parity/movies/movies"+schema.Par2Extension, mf.Par2Location)
+}
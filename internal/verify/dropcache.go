@@ -0,0 +1,62 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/desertwitch/par2cron/internal/par2"
+)
+
+// dropCaches drops the page cache of the PAR2 set (--drop-caches), being its
+// PAR2 files and protected files, so that par2 reads these from the storage
+// medium and the verification is not satisfied by cached (intact) pages of
+// files that have since rotted on disk. Files that cannot be dropped are only
+// warned of, as the verification is still meaningful (if less so) without.
+func (prog *Service) dropCaches(ctx context.Context, job *Job) {
+	if !job.dropCaches {
+		return
+	}
+
+	logger := prog.verificationLogger(ctx, job, job.par2Path)
+
+	paths := []string{job.par2Path}
+	if !job.isBundle {
+		volumes, err := par2.VolumeFiles(prog.fsys, job.par2Path)
+		if err != nil {
+			logger.Warn("Failed to list PAR2 volumes for dropping their page cache", "error", err)
+		}
+		paths = append(paths, volumes...)
+	}
+
+	sets, err := prog.parseJobSets(ctx, job)
+	if err != nil {
+		logger.Warn("Failed to parse PAR2 for dropping the page cache of its protected files", "error", err)
+	}
+	seen := make(map[string]struct{})
+	for _, set := range sets {
+		for _, fp := range set.RecoverySet {
+			if _, ok := seen[fp.Name]; ok || !filepath.IsLocal(fp.Name) {
+				continue
+			}
+			seen[fp.Name] = struct{}{}
+			paths = append(paths, filepath.Join(job.workingDir, fp.Name))
+		}
+	}
+
+	dropped := 0
+	for _, path := range paths {
+		if err := prog.dropper.DropCache(path); err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				logger := prog.verificationLogger(ctx, job, path)
+				logger.Warn("Failed to drop page cache (file may be verified from cache)", "error", err)
+			}
+
+			continue
+		}
+		dropped++
+	}
+
+	logger.Debug("Dropped page cache of the PAR2 set (--drop-caches)", "files", dropped)
+}
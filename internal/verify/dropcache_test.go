@@ -0,0 +1,109 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// newDropCacheService returns a service over a known PAR2 set (with a volume),
+// recording the paths of dropped page caches and the drops done before par2 ran.
+func newDropCacheService(t *testing.T, logBuf *testutil.SafeBuffer, dropped *[]string, beforePar2 *int, dropErr error) *Service {
+	t.Helper()
+
+	data, err := os.ReadFile("../par2/testdata/simple_par2cmdline.par2")
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/simple.par2", data, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/simple.vol0+1.par2", []byte("volume"), 0o644))
+
+	ls := logging.Options{Logout: logBuf, Stdout: io.Discard, Stderr: io.Discard}
+	_ = ls.LogLevel.Set("debug")
+
+	var mu sync.Mutex
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			*beforePar2 = len(*dropped)
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	prog.dropper = &testutil.MockCacheDropper{
+		DropCacheFunc: func(path string) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			*dropped = append(*dropped, path)
+
+			return dropErr
+		},
+	}
+
+	return prog
+}
+
+// Expectation: With --drop-caches, the page cache of the PAR2 files and the protected files
+// should be dropped before par2 runs, and without it not at all.
+func Test_Service_Verify_DropCaches_Success(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := par2.ParseFile(t.Context(), afero.NewOsFs(), "../par2/testdata/simple_par2cmdline.par2", true)
+	require.NoError(t, err)
+	require.NotEmpty(t, parsed.Sets)
+
+	want := []string{"/data/simple.par2", "/data/simple.vol0+1.par2"}
+	for _, fp := range parsed.Sets[0].RecoverySet {
+		want = append(want, "/data/"+fp.Name)
+	}
+
+	var logBuf testutil.SafeBuffer
+	var dropped []string
+	var beforePar2 int
+	prog := newDropCacheService(t, &logBuf, &dropped, &beforePar2, nil)
+
+	_, err = prog.Verify(t.Context(), []string{"/data"}, Options{IncludeExternal: true, DropCaches: true})
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, want, dropped)
+	require.Equal(t, len(want), beforePar2)
+	require.Contains(t, logBuf.String(), "Dropped page cache of the PAR2 set")
+
+	dropped = nil
+	_, err = prog.Verify(t.Context(), []string{"/data"}, Options{IncludeExternal: true})
+	require.NoError(t, err)
+	require.Empty(t, dropped)
+}
+
+// Expectation: Files whose page cache cannot be dropped should be warned of, without failing the job.
+func Test_Service_Verify_DropCaches_Error(t *testing.T) {
+	t.Parallel()
+
+	var logBuf testutil.SafeBuffer
+	var dropped []string
+	var beforePar2 int
+	prog := newDropCacheService(t, &logBuf, &dropped, &beforePar2, errors.New("permission denied"))
+
+	results, err := prog.Verify(t.Context(), []string{"/data"}, Options{IncludeExternal: true, DropCaches: true})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, results.Success)
+	require.NotEmpty(t, dropped)
+	require.Contains(t, logBuf.String(), "Failed to drop page cache")
+}
@@ -0,0 +1,125 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strconv"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+)
+
+// maxFileStatusLine is the length of an unterminated line after which it is
+// discarded, bounding the memory of the parser to misbehaving par2 output.
+const maxFileStatusLine = 64 * 1024
+
+// par2TargetLine matches the per-file lines of the par2 verify output, e.g.:
+//
+//	Target: "a.bin" - found.
+//	Target: "b.bin" - damaged. Found 95 of 100 data blocks.
+//	Target: "c.bin" - no data found.
+//	Target: "d.bin" - missing.
+var par2TargetLine = regexp.MustCompile(`^Target: "(.+)" - (found|damaged|no data found|missing)\.(?: Found (\d+) of (\d+) data blocks\.)?`)
+
+// fileStatusWriter parses the per-file status of the protected files from
+// the par2 verify output written to it, line by line (including progress
+// lines terminated by carriage returns), without buffering the output.
+type fileStatusWriter struct {
+	partial []byte
+	files   []schema.FileStatus
+	seen    map[string]int
+}
+
+func newFileStatusWriter() *fileStatusWriter {
+	return &fileStatusWriter{seen: make(map[string]int)}
+}
+
+func (w *fileStatusWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+
+	for {
+		i := bytes.IndexAny(w.partial, "\r\n")
+		if i < 0 {
+			break
+		}
+		w.parseLine(w.partial[:i])
+		w.partial = append(w.partial[:0], w.partial[i+1:]...)
+	}
+
+	if len(w.partial) > maxFileStatusLine {
+		w.partial = w.partial[:0]
+	}
+
+	return len(p), nil
+}
+
+// Files returns the parsed per-file status, including a final line without
+// a line terminator, in the order reported by par2.
+func (w *fileStatusWriter) Files() []schema.FileStatus {
+	if len(w.partial) > 0 {
+		w.parseLine(w.partial)
+		w.partial = w.partial[:0]
+	}
+
+	return w.files
+}
+
+func (w *fileStatusWriter) parseLine(line []byte) {
+	m := par2TargetLine.FindSubmatch(bytes.TrimSpace(line))
+	if m == nil {
+		return
+	}
+
+	fs := schema.FileStatus{Name: string(m[1])}
+	switch string(m[2]) {
+	case "found":
+		fs.Status = schema.FileStatusIntact
+	case "missing":
+		fs.Status = schema.FileStatusMissing
+	default:
+		fs.Status = schema.FileStatusDamaged
+		fs.FoundBlocks, _ = strconv.Atoi(string(m[3]))
+		fs.TotalBlocks, _ = strconv.Atoi(string(m[4]))
+	}
+
+	// A file reported again (e.g. by a retry) replaces its earlier status.
+	if i, ok := w.seen[fs.Name]; ok {
+		w.files[i] = fs
+
+		return
+	}
+	w.seen[fs.Name] = len(w.files)
+	w.files = append(w.files, fs)
+}
+
+// recordFileStatus records the per-file status parsed from the par2 output
+// of a corrupted verification into the verification manifest, or clears it
+// for a healthy one (so that it never outlives the corruption it describes).
+func (prog *Service) recordFileStatus(ctx context.Context, job *Job, fsw *fileStatusWriter) {
+	if !job.manifest.Verification.RepairNeeded {
+		job.manifest.Verification.Files = nil
+
+		return
+	}
+
+	files := fsw.Files()
+	logger := prog.verificationLogger(ctx, job, job.par2Path)
+	if len(files) == 0 {
+		logger.Warn("Failed to parse per-file status from par2 output (not recording it)")
+		job.manifest.Verification.Files = nil
+
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, f := range files {
+		counts[f.Status]++
+	}
+	logger.Info("Recorded per-file status of corrupted PAR2 set",
+		"intact", counts[schema.FileStatusIntact],
+		"damaged", counts[schema.FileStatusDamaged],
+		"missing", counts[schema.FileStatusMissing],
+	)
+
+	job.manifest.Verification.Files = files
+}
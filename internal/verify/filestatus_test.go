@@ -0,0 +1,174 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// par2DamagedOutput is the captured output of a par2cmdline verification
+// of a PAR2 set with one damaged, one emptied and one missing file.
+const par2DamagedOutput = "par2cmdline version 1.0.0, Copyright (C) 2003-2023 Peter Brian Clements.\n" +
+	"\n" +
+	"Loading \"test.par2\".\n" +
+	"Loaded 8 new packets\n" +
+	"There are 4 recoverable files and 0 other files.\n" +
+	"The block size used was 4096 bytes.\n" +
+	"There are a total of 215 data blocks.\n" +
+	"The total size of the data files is 876544 bytes.\n" +
+	"\n" +
+	"Verifying source files:\n" +
+	"\n" +
+	"Opening: \"a.bin\"\rScanning: \"a.bin\": 42.1%\rScanning: \"a.bin\": 100.0%\r" +
+	"Target: \"a.bin\" - found.\n" +
+	"Opening: \"dir/b c.bin\"\r" +
+	"Target: \"dir/b c.bin\" - damaged. Found 95 of 100 data blocks.\n" +
+	"Opening: \"e.bin\"\r" +
+	"Target: \"e.bin\" - no data found.\n" +
+	"Target: \"d.bin\" - missing.\n" +
+	"\n" +
+	"Scanning extra files:\n" +
+	"\n" +
+	"\n" +
+	"Repair is required.\n" +
+	"1 file(s) exist but are damaged.\n" +
+	"1 file(s) are missing.\n" +
+	"You have 195 out of 215 data blocks available.\n" +
+	"You have 20 recovery blocks available.\n" +
+	"Repair is possible.\n"
+
+var par2DamagedFiles = []schema.FileStatus{
+	{Name: "a.bin", Status: schema.FileStatusIntact},
+	{Name: "dir/b c.bin", Status: schema.FileStatusDamaged, FoundBlocks: 95, TotalBlocks: 100},
+	{Name: "e.bin", Status: schema.FileStatusDamaged},
+	{Name: "d.bin", Status: schema.FileStatusMissing},
+}
+
+// Expectation: The per-file status should be parsed from the captured par2 output.
+func Test_fileStatusWriter_Success(t *testing.T) {
+	t.Parallel()
+
+	w := newFileStatusWriter()
+	n, err := io.WriteString(w, par2DamagedOutput)
+
+	require.NoError(t, err)
+	require.Len(t, par2DamagedOutput, n)
+	require.Equal(t, par2DamagedFiles, w.Files())
+}
+
+// Expectation: The per-file status should be parsed regardless of how the output is chunked.
+func Test_fileStatusWriter_Chunked_Success(t *testing.T) {
+	t.Parallel()
+
+	w := newFileStatusWriter()
+	for i := range len(par2DamagedOutput) {
+		_, err := w.Write([]byte{par2DamagedOutput[i]})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, par2DamagedFiles, w.Files())
+}
+
+// Expectation: A file reported again should replace its earlier status, and unterminated lines be parsed.
+func Test_fileStatusWriter_Repeated_Success(t *testing.T) {
+	t.Parallel()
+
+	w := newFileStatusWriter()
+	_, _ = io.WriteString(w, "Target: \"a.bin\" - missing.\nTarget: \"b.bin\" - found.\n")
+	_, _ = io.WriteString(w, "Target: \"a.bin\" - found.")
+
+	require.Equal(t, []schema.FileStatus{
+		{Name: "a.bin", Status: schema.FileStatusIntact},
+		{Name: "b.bin", Status: schema.FileStatusIntact},
+	}, w.Files())
+}
+
+// Expectation: Overlong unterminated lines should be discarded instead of growing the buffer.
+func Test_fileStatusWriter_Overlong_Success(t *testing.T) {
+	t.Parallel()
+
+	w := newFileStatusWriter()
+	_, _ = w.Write(make([]byte, maxFileStatusLine+1))
+
+	require.Empty(t, w.partial)
+	require.Empty(t, w.Files())
+}
+
+// Expectation: The per-file status should only be recorded with the option and for a corrupted set.
+func Test_Service_Verify_FileStatus_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		fileStatus bool
+		code       int
+		output     string
+		want       []schema.FileStatus
+	}{
+		{"corrupted with option", true, schema.Par2ExitCodeRepairPossible, par2DamagedOutput, par2DamagedFiles},
+		{"corrupted without option", false, schema.Par2ExitCodeRepairPossible, par2DamagedOutput, nil},
+		{"healthy with option", true, schema.Par2ExitCodeSuccess, "Target: \"a.bin\" - found.\n", nil},
+		{"corrupted without parseable output", true, schema.Par2ExitCodeRepairPossible, "Repair is required.\n", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			createWithManifest(t, fs, "/data/test")
+
+			ls := logging.Options{
+				Logout: io.Discard,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					fmt.Fprint(stdout, tt.output)
+					if tt.code != schema.Par2ExitCodeSuccess {
+						return testutil.CreateExitError(t, ctx, tt.code)
+					}
+
+					return nil
+				},
+			}
+
+			opts := Options{FileStatus: tt.fileStatus}
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+			_, _ = prog.Verify(t.Context(), []string{"/data"}, opts)
+
+			data, err := afero.ReadFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension)
+			require.NoError(t, err)
+
+			var mf schema.Manifest
+			require.NoError(t, json.Unmarshal(data, &mf))
+			require.NotNil(t, mf.Verification)
+			require.Equal(t, tt.want, mf.Verification.Files)
+		})
+	}
+}
+
+// Expectation: The option should be refused with a quiet par2 verbosity.
+func Test_Options_Validate_FileStatus_Error(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{FileStatus: true, Par2Quiet: true}
+	require.ErrorContains(t, opts.Validate(), "file-status")
+
+	opts = Options{FileStatus: true, Par2Args: []string{"-qq"}}
+	require.ErrorContains(t, opts.Validate(), "file-status")
+
+	opts = Options{FileStatus: true, Par2Verbose: true}
+	require.NoError(t, opts.Validate())
+}
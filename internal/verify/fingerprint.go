@@ -0,0 +1,37 @@
+package verify
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+)
+
+// considerFingerprint recomputes the folder fingerprint of a PAR2 set created
+// with --folder-fingerprint from a listing of its folder, logging a drift in
+// the composition of its protected files (added, removed or renamed files).
+// It is best-effort, neither hashing contents nor running par2.
+func (prog *Service) considerFingerprint(ctx context.Context, meta *schema.JobMeta, opts Options) {
+	if meta.FolderFingerprint == "" {
+		return
+	}
+
+	workingDir := filepath.Dir(meta.Par2Path)
+	if meta.ManifestPath != "" {
+		workingDir = filepath.Dir(meta.ManifestPath)
+	}
+
+	logger := prog.verificationLogger(ctx, nil, meta.Par2Path)
+
+	elements, err := util.ListFolderElements(prog.fsys, workingDir, meta.Glob, meta.GlobExclude, opts.SidecarNames, opts.StampFile)
+	if err != nil {
+		logger.Warn("Failed to list folder for its fingerprint (not checking composition)", "error", err)
+
+		return
+	}
+
+	if util.FolderFingerprint(elements) != meta.FolderFingerprint {
+		logger.Warn("Protected files changed in composition since creation (folder fingerprint differs; consider re-creating)")
+	}
+}
@@ -0,0 +1,89 @@
+package verify
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: A drift in the composition of the protected files (added, removed or renamed files)
+// should be logged at enumeration, but not an unchanged folder or one only gaining par2cron files.
+func Test_Service_Enumerate_FolderFingerprint_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		change    func(t *testing.T, fs afero.Fs)
+		wantDrift bool
+	}{
+		{"unchanged", func(t *testing.T, fs afero.Fs) { t.Helper() }, false},
+		{"par2cron files", func(t *testing.T, fs afero.Fs) {
+			t.Helper()
+			require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension+schema.LockExtension, nil, 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/data/test.vol0+1"+schema.Par2Extension, []byte("par2data"), 0o644))
+		}, false},
+		{"added", func(t *testing.T, fs afero.Fs) {
+			t.Helper()
+			require.NoError(t, afero.WriteFile(fs, "/data/c.txt", []byte("content c"), 0o644))
+		}, true},
+		{"removed", func(t *testing.T, fs afero.Fs) {
+			t.Helper()
+			require.NoError(t, fs.Remove("/data/b.txt"))
+		}, true},
+		{"renamed", func(t *testing.T, fs afero.Fs) {
+			t.Helper()
+			require.NoError(t, fs.Rename("/data/b.txt", "/data/c.txt"))
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			require.NoError(t, fs.MkdirAll("/data", 0o755))
+			require.NoError(t, afero.WriteFile(fs, "/data/a.txt", []byte("content a"), 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/data/b.txt", []byte("content b"), 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+			elements, err := util.ListFolderElements(fs, "/data", "*", nil, util.SidecarNames{}, "")
+			require.NoError(t, err)
+			require.Len(t, elements, 2)
+
+			mf := schema.NewManifest("test" + schema.Par2Extension)
+			mf.Creation = schema.NewCreationManifest()
+			mf.Creation.Mode = schema.CreateFolderMode
+			mf.Creation.Glob = "*"
+			mf.Creation.Elements = elements
+			mf.Creation.FolderFingerprint = util.FolderFingerprint(elements)
+			data, err := json.Marshal(mf)
+			require.NoError(t, err)
+			require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension, data, 0o644))
+
+			tt.change(t, fs)
+
+			var logBuf testutil.SafeBuffer
+			ls := logging.Options{
+				Logout: &logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+			_ = ls.LogLevel.Set("info")
+
+			prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+			metas, err := prog.Enumerate(t.Context(), "/data", Options{}, &testutil.MockCache{})
+			require.NoError(t, err)
+			require.Len(t, metas, 1)
+			require.Equal(t, tt.wantDrift, strings.Contains(logBuf.String(), "folder fingerprint differs"))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+)
+
+var errNoGeometry = errors.New("not a single PAR2 set with a main packet")
+
+// recordGeometry records the block geometry of the PAR2 set into the
+// verification manifest. As the geometry cannot change without the PAR2
+// changing (which resets the manifest), the set is only parsed as long as
+// no geometry was recorded yet. Failing to parse the set is not an error of
+// the verification, so that par2 remains the authority on damaged sets.
+func (prog *Service) recordGeometry(ctx context.Context, job *Job) {
+	if job.manifest.Verification.Geometry != nil {
+		return
+	}
+
+	geo, err := prog.parseGeometry(ctx, job)
+	if err != nil {
+		logger := prog.verificationLogger(ctx, job, job.par2Path)
+		logger.Debug("Failed to parse PAR2 for its geometry (will retry next run)", "error", err)
+
+		return
+	}
+
+	job.manifest.Verification.Geometry = geo
+}
+
+// parseJobSets parses the PAR2 sets of the job, from within a bundle or
+// from its PAR2 index file.
+func (prog *Service) parseJobSets(ctx context.Context, job *Job) ([]par2.Set, error) {
+	if job.isBundle {
+		sets, err := util.ParseBundlePar2Index(ctx, prog.fsys, job.par2Path, prog.par2er, prog.bundler)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bundle: %w", err)
+		}
+
+		return sets, nil
+	}
+
+	f, err := prog.par2er.ParseFile(ctx, prog.fsys, job.par2Path, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse: %w", err)
+	}
+
+	return f.Sets, nil
+}
+
+func (prog *Service) parseGeometry(ctx context.Context, job *Job) (*schema.GeometryManifest, error) {
+	sets, err := prog.parseJobSets(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sets) != 1 || sets[0].MainPacket == nil || sets[0].MainPacket.SliceSize == 0 {
+		return nil, errNoGeometry
+	}
+	set := sets[0]
+
+	geo := &schema.GeometryManifest{
+		SliceSize:     set.MainPacket.SliceSize,
+		RecoveryFiles: len(set.MainPacket.RecoveryIDs),
+	}
+
+	sliceSize := int64(set.MainPacket.SliceSize) //nolint:gosec
+	seen := make(map[par2.Hash]struct{})
+	for _, fp := range set.RecoverySet {
+		if _, ok := seen[fp.FileID]; ok {
+			continue
+		}
+		seen[fp.FileID] = struct{}{}
+
+		geo.SourceSlices += (fp.Size + sliceSize - 1) / sliceSize
+	}
+
+	return geo, nil
+}
@@ -0,0 +1,105 @@
+package verify
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestManifest(t *testing.T, fs afero.Fs, path string) *schema.Manifest {
+	t.Helper()
+
+	by, err := afero.ReadFile(fs, path+schema.ManifestExtension)
+	require.NoError(t, err)
+
+	var mf schema.Manifest
+	require.NoError(t, json.Unmarshal(by, &mf))
+
+	return &mf
+}
+
+// Expectation: The geometry of a known PAR2 set should be recorded into the verification manifest,
+// with the slice size matching the one parsed from the set.
+func Test_Service_Verify_Geometry_Success(t *testing.T) {
+	t.Parallel()
+
+	const testdata = "../par2/testdata/simple_par2cmdline.par2"
+
+	data, err := os.ReadFile(testdata)
+	require.NoError(t, err)
+
+	parsed, err := par2.ParseFile(t.Context(), afero.NewOsFs(), testdata, true)
+	require.NoError(t, err)
+	require.Len(t, parsed.Sets, 1)
+	require.NotNil(t, parsed.Sets[0].MainPacket)
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/simple.par2", data, 0o644))
+
+	ls := logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	_, err = prog.Verify(t.Context(), []string{"/data"}, Options{IncludeExternal: true})
+	require.NoError(t, err)
+
+	geo := readTestManifest(t, fs, "/data/simple.par2").Verification.Geometry
+	require.NotNil(t, geo)
+	require.Positive(t, geo.SliceSize)
+	require.Equal(t, parsed.Sets[0].MainPacket.SliceSize, geo.SliceSize)
+	require.Equal(t, len(parsed.Sets[0].MainPacket.RecoveryIDs), geo.RecoveryFiles)
+	require.Positive(t, geo.SourceSlices)
+}
+
+// Expectation: The geometry should only be parsed until it was recorded, and a set
+// that cannot be parsed should still be verified (without a geometry).
+func Test_Service_Verify_Geometry_ParseOnce_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/a/test")
+	createWithManifest(t, fs, "/data/b/test")
+
+	ls := logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	parsed := make(map[string]int)
+	prog.par2er = &testutil.MockPar2Handler{
+		ParseFileFunc: func(_ afero.Fs, path string, _ bool) (*par2.File, error) {
+			parsed[path]++
+			if path == "/data/b/test.par2" {
+				return nil, errors.New("parse failure")
+			}
+
+			return &par2.File{Sets: []par2.Set{{
+				MainPacket: &par2.MainPacket{SliceSize: 100, RecoveryIDs: []par2.Hash{{1}, {2}}},
+				RecoverySet: []par2.FilePacket{
+					{FileID: par2.Hash{1}, Size: 250},
+					{FileID: par2.Hash{2}, Size: 100},
+					{FileID: par2.Hash{1}, Size: 250},
+				},
+			}}}, nil
+		},
+	}
+
+	for range 2 {
+		results, err := prog.Verify(t.Context(), []string{"/data"}, Options{})
+		require.NoError(t, err)
+		require.Equal(t, 2, results.Success)
+	}
+
+	require.Equal(t, map[string]int{"/data/a/test.par2": 1, "/data/b/test.par2": 2}, parsed)
+	require.Equal(t, &schema.GeometryManifest{SliceSize: 100, SourceSlices: 4, RecoveryFiles: 2},
+		readTestManifest(t, fs, "/data/a/test.par2").Verification.Geometry)
+	require.Nil(t, readTestManifest(t, fs, "/data/b/test.par2").Verification.Geometry)
+}
@@ -0,0 +1,76 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/spf13/afero"
+)
+
+// filterRecentRoots returns the root directories whose previous verification
+// run finished at least minInterval ago (or which have no previous run), as
+// read from the state file in each root directory. Unreadable state files do
+// not prevent a run, as the guard must never stop the verification for good.
+func (prog *Service) filterRecentRoots(ctx context.Context, rootDirs []string, minInterval time.Duration) []string {
+	filtered := make([]string, 0, len(rootDirs))
+
+	for _, rootDir := range rootDirs {
+		statePath := filepath.Join(rootDir, schema.LastRunFile)
+
+		lastRun, err := readLastRun(prog.fsys, statePath)
+		if err != nil {
+			logger := prog.verificationLogger(ctx, nil, statePath)
+			logger.Debug("No previous run found for --min-run-interval", "error", err)
+
+			filtered = append(filtered, rootDir)
+
+			continue
+		}
+
+		if since := time.Since(lastRun); since >= 0 && since < minInterval {
+			logger := prog.verificationLogger(ctx, nil, rootDir)
+			logger.Info("Skipping directory, as previous run finished within --min-run-interval",
+				"lastRun", lastRun.Format(time.RFC3339),
+				"minRunInterval", minInterval.String())
+
+			continue
+		}
+
+		filtered = append(filtered, rootDir)
+	}
+
+	return filtered
+}
+
+// markRootsRun records the current time as finish time of a verification run
+// in the state file of each root directory (on a best-effort basis).
+func (prog *Service) markRootsRun(ctx context.Context, rootDirs []string) {
+	now := time.Now()
+
+	for _, rootDir := range rootDirs {
+		statePath := filepath.Join(rootDir, schema.LastRunFile)
+
+		if err := afero.WriteFile(prog.fsys, statePath, []byte(now.Format(time.RFC3339Nano)+"\n"), 0o644); err != nil { //nolint:mnd
+			logger := prog.verificationLogger(ctx, nil, statePath)
+			logger.Warn("Failed to write state file for --min-run-interval", "error", err)
+		}
+	}
+}
+
+func readLastRun(fsys afero.Fs, statePath string) (time.Time, error) {
+	data, err := afero.ReadFile(fsys, statePath)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read: %w", err)
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse: %w", err)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,142 @@
+package verify
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func newLastRunService(t *testing.T, fs afero.Fs, logout io.Writer, called *int) *Service {
+	t.Helper()
+
+	ls := logging.Options{
+		Logout: logout,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			*called++
+
+			return nil
+		},
+	}
+
+	return NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+}
+
+// Expectation: A run within --min-run-interval of the previous run should be skipped with success.
+func Test_Service_Verify_MinRunInterval_Skip_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+
+	lastRun := time.Now().Add(-10 * time.Minute).Format(time.RFC3339Nano)
+	require.NoError(t, afero.WriteFile(fs, "/data/"+schema.LastRunFile, []byte(lastRun+"\n"), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	var called int
+	prog := newLastRunService(t, fs, &logBuf, &called)
+
+	args := Options{}
+	require.NoError(t, args.MinRunInterval.Set("1h"))
+
+	results, err := prog.Verify(t.Context(), []string{"/data"}, args)
+
+	require.NoError(t, err)
+	require.Zero(t, called)
+	require.Zero(t, results.Selected)
+	require.Contains(t, logBuf.String(), "previous run finished within --min-run-interval")
+
+	data, err := afero.ReadFile(fs, "/data/"+schema.LastRunFile)
+	require.NoError(t, err)
+	require.Equal(t, lastRun+"\n", string(data))
+}
+
+// Expectation: A run should proceed once --min-run-interval has elapsed, recording its finish time.
+func Test_Service_Verify_MinRunInterval_Elapsed_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+
+	lastRun := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, afero.WriteFile(fs, "/data/"+schema.LastRunFile, []byte(lastRun.Format(time.RFC3339Nano)), 0o644))
+
+	var called int
+	prog := newLastRunService(t, fs, io.Discard, &called)
+
+	args := Options{}
+	require.NoError(t, args.MinRunInterval.Set("1h"))
+
+	_, err := prog.Verify(t.Context(), []string{"/data"}, args)
+
+	require.NoError(t, err)
+	require.Equal(t, 1, called)
+
+	newRun, err := readLastRun(fs, "/data/"+schema.LastRunFile)
+	require.NoError(t, err)
+	require.True(t, newRun.After(lastRun.Add(time.Hour)))
+}
+
+// Expectation: Without or with an unparsable state file, the run should proceed.
+func Test_Service_Verify_MinRunInterval_NoState_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/one/test")
+	createWithManifest(t, fs, "/data/two/test")
+	require.NoError(t, afero.WriteFile(fs, "/data/two/"+schema.LastRunFile, []byte("garbage"), 0o644))
+
+	var called int
+	prog := newLastRunService(t, fs, io.Discard, &called)
+
+	args := Options{}
+	require.NoError(t, args.MinRunInterval.Set("1h"))
+
+	_, err := prog.Verify(t.Context(), []string{"/data/one", "/data/two"}, args)
+
+	require.NoError(t, err)
+	require.Equal(t, 2, called)
+
+	for _, root := range []string{"/data/one", "/data/two"} {
+		_, err := readLastRun(fs, root+"/"+schema.LastRunFile)
+		require.NoError(t, err)
+	}
+}
+
+// Expectation: Neither a run without --min-run-interval nor a report-only run should write the state file.
+func Test_Service_Verify_MinRunInterval_NoStateWritten_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+
+	var called int
+	prog := newLastRunService(t, fs, io.Discard, &called)
+
+	_, err := prog.Verify(t.Context(), []string{"/data"}, Options{})
+	require.NoError(t, err)
+
+	args := Options{NoManifestUpdate: true}
+	require.NoError(t, args.MinRunInterval.Set("1h"))
+	_, err = prog.Verify(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, called)
+
+	exists, err := afero.Exists(fs, "/data/"+schema.LastRunFile)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
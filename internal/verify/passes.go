@@ -0,0 +1,68 @@
+package verify
+
+import (
+	"context"
+	"io"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+)
+
+// runPasses runs the par2 verification of a PAR2 set for the number of passes
+// (--verify-passes), returning the outcome of the first pass not finding it
+// clean (so it is only clean if all passes are), and if the exit codes of the
+// passes disagreed. An interruption or a failure to run par2 (not an exit
+// code) ends the passes early, returning that error.
+func (prog *Service) runPasses(ctx context.Context, job *Job, cmdArgs []string, basePath string, stdout io.Writer) (bool, error) {
+	var (
+		outcome   error
+		firstCode int
+		disagreed bool
+	)
+
+	for pass := range max(job.verifyPasses, 1) {
+		err := prog.runPass(ctx, job, cmdArgs, basePath, stdout)
+		if ctx.Err() != nil {
+			return disagreed, err
+		}
+
+		code := 0
+		if err != nil {
+			c := util.AsExitCode(err)
+			if c == nil {
+				return disagreed, err
+			}
+			code = *c
+		}
+
+		if pass == 0 {
+			firstCode = code
+		} else if code != firstCode && !disagreed {
+			disagreed = true
+
+			logger := prog.verificationLogger(ctx, job, job.par2Path)
+			logger.Warn("Verification passes disagreed (possibly flaky hardware)",
+				"pass", pass+1, "exitCode", code, "firstExitCode", firstCode)
+		}
+
+		if outcome == nil {
+			outcome = err
+		}
+	}
+
+	return disagreed, outcome
+}
+
+// runPass runs one par2 verification pass of a PAR2 set, retrying a crash of
+// par2 single-threaded (--retry-single-threaded), and re-running it with the
+// relocated protected files (--relocate).
+func (prog *Service) runPass(ctx context.Context, job *Job, cmdArgs []string, basePath string, stdout io.Writer) error {
+	err := prog.runner.Run(ctx, "par2", cmdArgs, basePath, stdout, stdout)
+	if job.retrySingleThreaded && ctx.Err() == nil && util.IsPar2Crash(err) {
+		logger := prog.verificationLogger(ctx, job, job.par2Path)
+		logger.Warn("Failed to verify PAR2 (retrying single-threaded)", "error", err)
+		err = prog.runner.Run(ctx, "par2", util.Par2SingleThreadedArgs(cmdArgs, schema.Par2Flavor), basePath, stdout, stdout)
+	}
+
+	return prog.considerRelocations(ctx, job, cmdArgs, basePath, stdout, err)
+}
@@ -0,0 +1,90 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: par2 should be run once per pass, with a set only found clean if all passes are,
+// and passes disagreeing on the outcome (alternating verdicts) warned about and recorded.
+func Test_Service_Verify_Passes_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		passes           int
+		verdicts         []int
+		wantCalls        int
+		wantRepairNeeded bool
+		wantDisagreed    bool
+	}{
+		{"default single pass", 0, []int{0, schema.Par2ExitCodeRepairPossible}, 1, false, false},
+		{"agreeing clean passes", 3, []int{0}, 3, false, false},
+		{"agreeing corrupted passes", 2, []int{schema.Par2ExitCodeRepairPossible}, 2, true, false},
+		{"alternating from clean", 3, []int{0, schema.Par2ExitCodeRepairPossible}, 3, true, true},
+		{"alternating from corrupted", 2, []int{schema.Par2ExitCodeRepairPossible, 0}, 2, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			createWithManifest(t, fs, "/data/test")
+
+			logBuf := &testutil.SafeBuffer{}
+			ls := logging.Options{
+				Logout: logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+			_ = ls.LogLevel.Set("info")
+
+			var calls int
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					code := tt.verdicts[calls%len(tt.verdicts)]
+					calls++
+					if code == 0 {
+						return nil
+					}
+
+					return testutil.CreateExitError(t, ctx, code)
+				},
+			}
+
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+			_, _ = prog.Verify(t.Context(), []string{"/data"}, Options{VerifyPasses: tt.passes})
+
+			require.Equal(t, tt.wantCalls, calls)
+			require.Equal(t, tt.wantDisagreed, bytes.Contains(logBuf.Bytes(), []byte("Verification passes disagreed")))
+
+			data, err := afero.ReadFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension)
+			require.NoError(t, err)
+
+			var mf schema.Manifest
+			require.NoError(t, json.Unmarshal(data, &mf))
+			require.Equal(t, tt.wantRepairNeeded, mf.Verification.RepairNeeded)
+			require.Equal(t, tt.wantDisagreed, mf.Verification.PassesDisagreed)
+		})
+	}
+}
+
+// Expectation: A negative number of passes should be refused.
+func Test_Options_Validate_VerifyPasses_Error(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{VerifyPasses: -1}
+
+	require.ErrorContains(t, opts.Validate(), "verify-passes")
+}
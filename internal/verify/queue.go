@@ -0,0 +1,123 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+)
+
+// EnumerateQueue returns the jobs for the newline-delimited paths read from
+// the queue (--from-stdin), instead of walking the root directories. Each
+// path is either a PAR2 index file (or bundle), or a directory that is then
+// walked as if it were a root directory. Paths need to be within one of the
+// root directories (of the caches), invalid paths are logged and skipped,
+// returning a non-fatal error with their count.
+func (prog *Service) EnumerateQueue(ctx context.Context, queue io.Reader, caches map[string]schema.Cache, opts Options) ([]*JobMeta, error) {
+	paths, err := util.ReadQueue(ctx, queue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue: %w", err)
+	}
+
+	rootDirs := slices.Sorted(maps.Keys(caches))
+	checkers := make(map[string]*util.IgnoreChecker, len(rootDirs))
+	for _, rootDir := range rootDirs {
+		checkers[rootDir] = util.NewIgnoreChecker(prog.fsys, rootDir, opts.IgnoreNames)
+	}
+
+	metas := []*JobMeta{}
+	seen := make(map[string]struct{})
+	add := func(meta *JobMeta) {
+		if _, ok := seen[meta.Par2Path]; !ok {
+			seen[meta.Par2Path] = struct{}{}
+			metas = append(metas, meta)
+		}
+	}
+
+	var invalidPaths, partialErrors int
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("context error: %w", err)
+		}
+
+		logger := prog.verificationLogger(ctx, nil, path)
+
+		rootDir, ok := util.FindRoot(path, rootDirs)
+		if !ok {
+			logger.Warn("A queued path was skipped as not within any <dir>", "reason", schema.ReasonQueueInvalid)
+			prog.filtered.Add(schema.ReasonQueueInvalid)
+			invalidPaths++
+
+			continue
+		}
+		if checkers[rootDir].ShouldIgnore(path) {
+			logger.Debug("A path was skipped due to a present ignore-file", "reason", schema.ReasonIgnoreFile)
+			prog.filtered.Add(schema.ReasonIgnoreFile)
+
+			continue
+		}
+
+		fi, err := util.LstatIfPossible(prog.fsys, path)
+		if err != nil {
+			logger.Warn("A queued path was skipped due to FS error", "reason", schema.ReasonQueueInvalid, "error", err)
+			prog.filtered.Add(schema.ReasonQueueInvalid)
+			invalidPaths++
+
+			continue
+		}
+
+		if fi.IsDir() {
+			ms, err := prog.Enumerate(ctx, path, opts, caches[rootDir])
+			if err != nil {
+				if !errors.Is(err, schema.ErrNonFatal) {
+					return nil, fmt.Errorf("%s: failed to enumerate jobs: %w", path, err)
+				}
+				partialErrors++
+			}
+			for _, meta := range ms {
+				add(meta)
+			}
+
+			continue
+		}
+
+		if !fi.Mode().IsRegular() || !util.IsPar2Index(fi.Name()) {
+			logger.Warn("A queued path was skipped as not a PAR2 index file or directory", "reason", schema.ReasonQueueInvalid)
+			prog.filtered.Add(schema.ReasonQueueInvalid)
+			invalidPaths++
+
+			continue
+		}
+
+		// Queued sets are always read from their manifest, as an external
+		// scheduler queueing them likely knows of a change to their state.
+		meta, err := prog.processManifest(ctx, path, opts)
+		if err != nil {
+			if !errors.Is(err, schema.ErrNonFatal) && !errors.Is(err, schema.ErrSilentSkip) {
+				return nil, fmt.Errorf("failed to process manifest: %w", err)
+			}
+			if errors.Is(err, schema.ErrNonFatal) {
+				partialErrors++
+			}
+
+			continue
+		}
+		caches[rootDir].Set(path, meta.JobMeta)
+
+		if prog.isVerificationCandidate(ctx, meta.JobMeta, opts) {
+			add(meta)
+		}
+	}
+
+	if invalidPaths > 0 || partialErrors > 0 {
+		return metas, fmt.Errorf("%w: %d queued paths invalid, %d failed to read",
+			schema.ErrNonFatal, invalidPaths, partialErrors)
+	}
+
+	return metas, nil
+}
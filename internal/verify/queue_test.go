@@ -0,0 +1,110 @@
+package verify
+
+import (
+	"context"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: Only the queued sets (and sets below queued directories) should be verified,
+// with invalid and missing paths logged and resulting in a partial failure.
+func Test_Service_Verify_Queue_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/a/one")
+	createWithManifest(t, fs, "/data/b/two")
+	createWithManifest(t, fs, "/data/c/three")
+	createWithManifest(t, fs, "/data/c/sub/four")
+	require.NoError(t, afero.WriteFile(fs, "/data/a/file.txt", []byte("data"), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var mu sync.Mutex
+	var verified []string
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			mu.Lock()
+			defer mu.Unlock()
+			verified = append(verified, args[len(args)-1])
+
+			return nil
+		},
+	}
+
+	queue := strings.Join([]string{
+		"/data/a/one.par2",
+		"/data/c",
+		"/data/a/one.par2",
+		"/data/a/missing.par2",
+		"/data/a/file.txt",
+		"/other/set.par2",
+		"",
+	}, "\n")
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	results, err := prog.Verify(t.Context(), []string{"/data"}, Options{Queue: strings.NewReader(queue)})
+
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+	require.ErrorContains(t, err, "3 queued paths invalid")
+	require.Equal(t, 3, results.Selected)
+	require.Equal(t, 3, results.Success)
+
+	slices.Sort(verified)
+	require.Equal(t, []string{"/data/a/one.par2", "/data/c/sub/four.par2", "/data/c/three.par2"}, verified)
+
+	logs := logBuf.String()
+	require.Contains(t, logs, "Reading queued paths for jobs (--from-stdin)")
+	require.NotContains(t, logs, "Scanning filesystem for jobs")
+	require.Contains(t, logs, "A queued path was skipped due to FS error")
+	require.Contains(t, logs, "A queued path was skipped as not a PAR2 index file or directory")
+	require.Contains(t, logs, "A queued path was skipped as not within any <dir>")
+	require.Contains(t, logs, schema.ReasonQueueInvalid)
+}
+
+// Expectation: A queue with only valid paths should be enumerated without errors, updating the cache.
+func Test_Service_EnumerateQueue_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/a/one")
+	createWithManifest(t, fs, "/data/b/two")
+
+	prog := NewService(fs, logging.NewLogger(logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}),
+		&testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	var cached []string
+	cache := &testutil.MockCache{
+		SetFunc: func(key string, meta *schema.JobMeta) {
+			cached = append(cached, key)
+		},
+		PruneUnwalkedFunc: func() int {
+			require.Fail(t, "cache should not be pruned for a queue")
+
+			return 0
+		},
+	}
+	metas, err := prog.EnumerateQueue(t.Context(), strings.NewReader("/data/b/two.par2\n"),
+		map[string]schema.Cache{"/data": cache}, Options{})
+
+	require.NoError(t, err)
+	require.Len(t, metas, 1)
+	require.Equal(t, "/data/b/two.par2", metas[0].Par2Path)
+	require.Equal(t, []string{"/data/b/two.par2"}, cached)
+}
@@ -0,0 +1,147 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+)
+
+var errQuickProblems = errors.New("quick verification found problems")
+
+// RunQuickVerify checks the integrity of the PAR2 index and the presence and
+// sizes of the protected files (--quick), without running par2. This does not
+// catch any corruption within the protected files, so that it never replaces
+// the (full) verification, but has problems found followed up by one in the
+// next normal run. The problems are returned as errQuickProblems.
+func (prog *Service) RunQuickVerify(ctx context.Context, job *Job) error {
+	unlock, err := util.AcquireLock(prog.fsys, job.lockPath, false)
+	if err != nil {
+		return fmt.Errorf("failed to lock: %w", err)
+	}
+	defer unlock()
+
+	startTime := time.Now()
+	problems := prog.quickProblems(ctx, job)
+	duration := time.Since(startTime)
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("context error: %w", err)
+	}
+
+	if job.manifest == nil {
+		job.manifest = schema.NewManifest(job.par2Name)
+		if !job.isBundle {
+			if hash, err := util.HashFile(prog.fsys, job.par2Path); err == nil {
+				job.manifest.SHA256 = hash
+			}
+		}
+	}
+	if job.manifest.QuickVerification == nil {
+		job.manifest.QuickVerification = schema.NewQuickVerificationManifest()
+	}
+	qv := job.manifest.QuickVerification
+	qv.ProgramVersion = schema.ProgramVersion
+	qv.Count++
+	qv.Time = startTime
+	qv.Duration = duration
+	qv.Healthy = len(problems) == 0
+	qv.Problems = problems
+
+	if !job.noManifestUpdate {
+		if err := util.WriteManifest(ctx, prog.fsys, prog.bundler, job.manifestPath, job.manifest, job.isBundle); err != nil {
+			logger := prog.verificationLogger(ctx, job, job.manifestPath)
+			logger.Error("Failed to write par2cron manifest", "error", err)
+
+			return fmt.Errorf("failed to write manifest: %w", err)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %d problems (first: %s)", errQuickProblems, len(problems), problems[0])
+	}
+
+	return nil
+}
+
+// quickProblems returns the problems found with the PAR2 index of the job
+// and the protected files described in it, or none if all were found intact.
+func (prog *Service) quickProblems(ctx context.Context, job *Job) []string {
+	var problems []string
+
+	if !job.isBundle && job.manifest != nil && job.manifest.SHA256 != "" {
+		hash, err := util.HashFile(prog.fsys, job.par2Path)
+		if err != nil {
+			return []string{fmt.Sprintf("failed to hash par2: %v", err)}
+		}
+		if hash != job.manifest.SHA256 {
+			problems = append(problems, errPar2Changed.Error())
+		}
+	}
+
+	sets, err := prog.parseJobSets(ctx, job)
+	if err != nil {
+		return append(problems, err.Error())
+	}
+
+	if len(sets) == 0 {
+		return append(problems, "no PAR2 set found in index")
+	}
+
+	if util.IsCaseInsensitive(prog.fsys, job.par2Path) {
+		for _, names := range caseCollisions(sets) {
+			problems = append(problems, fmt.Sprintf("%s: protected files differ only by case on a case-insensitive filesystem",
+				strings.Join(names, ", ")))
+		}
+	}
+
+	for _, set := range sets {
+		if set.MainPacket == nil {
+			problems = append(problems, "PAR2 set without main packet")
+		}
+		if n := len(set.MissingRecoveryPackets); n > 0 {
+			problems = append(problems, fmt.Sprintf("%d protected files not described in index", n))
+		}
+
+		seen := make(map[par2.Hash]struct{})
+		for _, fp := range set.RecoverySet {
+			if _, ok := seen[fp.FileID]; ok {
+				continue
+			}
+			seen[fp.FileID] = struct{}{}
+
+			problems = append(problems, prog.quickFileProblems(job.workingDir, fp)...)
+		}
+	}
+
+	return problems
+}
+
+// quickFileProblems returns the problems found with a protected file, being
+// its absence or a size differing from the one described in the PAR2 index.
+func (prog *Service) quickFileProblems(workingDir string, fp par2.FilePacket) []string {
+	name := filepath.FromSlash(fp.Name)
+	if !filepath.IsLocal(name) {
+		return []string{fmt.Sprintf("%s: protected file outside the directory", fp.Name)}
+	}
+
+	fi, err := prog.fsys.Stat(filepath.Join(workingDir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return []string{fmt.Sprintf("%s: protected file not found", fp.Name)}
+	} else if err != nil {
+		return []string{fmt.Sprintf("%s: failed to stat protected file: %v", fp.Name, err)}
+	}
+
+	if fi.Size() != fp.Size {
+		return []string{fmt.Sprintf("%s: protected file has size %d (expected %d)", fp.Name, fi.Size(), fp.Size)}
+	}
+
+	return nil
+}
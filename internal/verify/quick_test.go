@@ -0,0 +1,193 @@
+package verify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// newQuickService returns a service whose PAR2 sets each protect a.bin (5 bytes)
+// and sub/b.bin (3 bytes), counting the par2 invocations of the runner.
+func newQuickService(t *testing.T, fs afero.Fs, logBuf *testutil.SafeBuffer, called *int) *Service {
+	t.Helper()
+
+	ls := logging.Options{
+		Logout: logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			*called++
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	prog.par2er = &testutil.MockPar2Handler{
+		ParseFileFunc: func(_ afero.Fs, _ string, _ bool) (*par2.File, error) {
+			return &par2.File{Sets: []par2.Set{{
+				MainPacket: &par2.MainPacket{SliceSize: 4},
+				RecoverySet: []par2.FilePacket{
+					{FileID: par2.Hash{1}, Name: "a.bin", Size: 5},
+					{FileID: par2.Hash{2}, Name: "sub/b.bin", Size: 3},
+				},
+			}}}, nil
+		},
+	}
+
+	return prog
+}
+
+// createQuickSet creates a verified PAR2 set with its protected files below dir.
+func createQuickSet(t *testing.T, fs afero.Fs, dir string) {
+	t.Helper()
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.SHA256 = fmt.Sprintf("%x", sha256.Sum256([]byte("par2data")))
+	mf.Creation = &schema.CreationManifest{Time: time.Now()}
+	mf.Verification = &schema.VerificationManifest{Time: time.Now().Add(-time.Hour), Count: 1}
+	by, err := json.Marshal(mf)
+	require.NoError(t, err)
+
+	require.NoError(t, fs.MkdirAll(dir+"/sub", 0o755))
+	require.NoError(t, afero.WriteFile(fs, dir+"/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, dir+"/test"+schema.Par2Extension+schema.ManifestExtension, by, 0o644))
+	require.NoError(t, afero.WriteFile(fs, dir+"/a.bin", []byte("aaaaa"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, dir+"/sub/b.bin", []byte("bbb"), 0o644))
+}
+
+// Expectation: A quick verification should check all sets (regardless of --age) without
+// running par2, recording its outcome apart from the (full) verification.
+func Test_Service_Verify_Quick_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createQuickSet(t, fs, "/data/a")
+	createQuickSet(t, fs, "/data/b")
+
+	var logBuf testutil.SafeBuffer
+	var called int
+	prog := newQuickService(t, fs, &logBuf, &called)
+
+	args := Options{Quick: true}
+	_ = args.MinAge.Set("7d")
+
+	results, err := prog.Verify(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	require.Zero(t, called)
+	require.Equal(t, 2, results.Success)
+	require.Contains(t, logBuf.String(), "Running in quick mode")
+	require.Contains(t, logBuf.String(), "Job completed quick verification with success")
+
+	mf := readTestManifest(t, fs, "/data/a/test.par2")
+	require.NotNil(t, mf.QuickVerification)
+	require.True(t, mf.QuickVerification.Healthy)
+	require.Equal(t, 1, mf.QuickVerification.Count)
+	require.Empty(t, mf.QuickVerification.Problems)
+	require.Equal(t, 1, mf.Verification.Count)
+}
+
+// Expectation: Problems found by a quick verification should fail the run and have the set
+// fully verified by the next normal run, regardless of --age and before all other sets.
+func Test_Service_Verify_Quick_Problems_Error(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		modify  func(t *testing.T, fs afero.Fs)
+		problem string
+	}{
+		{"missing file", func(t *testing.T, fs afero.Fs) {
+			t.Helper()
+			require.NoError(t, fs.Remove("/data/b/sub/b.bin"))
+		}, "sub/b.bin: protected file not found"},
+		{"size mismatch", func(t *testing.T, fs afero.Fs) {
+			t.Helper()
+			require.NoError(t, afero.WriteFile(fs, "/data/b/a.bin", []byte("aa"), 0o644))
+		}, "a.bin: protected file has size 2 (expected 5)"},
+		{"par2 changed", func(t *testing.T, fs afero.Fs) {
+			t.Helper()
+			require.NoError(t, afero.WriteFile(fs, "/data/b/test.par2", []byte("changed"), 0o644))
+		}, errPar2Changed.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			createQuickSet(t, fs, "/data/a")
+			createQuickSet(t, fs, "/data/b")
+			tt.modify(t, fs)
+
+			var logBuf testutil.SafeBuffer
+			var called int
+			prog := newQuickService(t, fs, &logBuf, &called)
+
+			results, err := prog.Verify(t.Context(), []string{"/data"}, Options{Quick: true})
+			require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+			require.ErrorIs(t, err, errQuickProblems)
+			require.Equal(t, 1, results.Success)
+			require.Equal(t, 1, results.Error)
+			require.Contains(t, logBuf.String(), "will fully verify next run")
+
+			mf := readTestManifest(t, fs, "/data/b/test.par2")
+			require.False(t, mf.QuickVerification.Healthy)
+			require.Contains(t, mf.QuickVerification.Problems, tt.problem)
+			require.True(t, mf.QuickVerification.NeedsFull(mf.Verification))
+
+			args := Options{Limit: 1}
+			_ = args.MinAge.Set("7d")
+
+			results, err = prog.Verify(t.Context(), []string{"/data"}, args)
+			require.NoError(t, err)
+			require.Equal(t, 1, called)
+			require.Equal(t, 1, results.Selected)
+
+			mf = readTestManifest(t, fs, "/data/b/test.par2")
+			require.False(t, mf.QuickVerification.NeedsFull(mf.Verification))
+		})
+	}
+}
+
+// Expectation: A PAR2 index without any PAR2 set should be found as a problem.
+func Test_Service_RunQuickVerify_InvalidIndex_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createQuickSet(t, fs, "/data/a")
+
+	ls := logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	job := NewJob("/data/a/test.par2", Options{}, nil, false)
+	err := prog.RunQuickVerify(t.Context(), job)
+	require.ErrorIs(t, err, errQuickProblems)
+	require.Equal(t, []string{"no PAR2 set found in index"}, job.manifest.QuickVerification.Problems)
+	require.Nil(t, job.manifest.Verification)
+}
+
+// Expectation: The options should be refused with --quick and --mirror.
+func Test_Options_Validate_Quick_Error(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{Quick: true, MirrorDir: "/mnt/mirror"}
+	require.ErrorContains(t, opts.Validate(), "quick: cannot be combined with --mirror")
+}
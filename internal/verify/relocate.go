@@ -0,0 +1,197 @@
+package verify
+
+import (
+	"cmp"
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"slices"
+
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
+)
+
+// par2Hash16kSize is the length of the file prefix hashed by par2 (Hash16k).
+const par2Hash16kSize = 16 * 1024
+
+// relocation is a protected file missing at its name within the PAR2 set,
+// which was found (by its size and hashes) elsewhere below the base path.
+type relocation struct {
+	name string
+	path string
+}
+
+// considerRelocations searches for the missing protected files of a PAR2 set
+// found corrupted (--find-relocated), logging the found candidates. With
+// --relocate, par2 is re-run with these passed as extra files, returning
+// the outcome of that verification in place of the given one (err).
+func (prog *Service) considerRelocations(ctx context.Context, job *Job, cmdArgs []string, basePath string, stdout io.Writer, err error) error {
+	if !job.findRelocated || ctx.Err() != nil {
+		return err
+	}
+
+	c := util.AsExitCode(err)
+	if c == nil {
+		return err
+	}
+	switch util.Par2ExitClass(*c, job.par2ExitCodes) {
+	case schema.Par2ExitClassRepairable, schema.Par2ExitClassUnrepairable:
+	default:
+		return err
+	}
+
+	logger := prog.verificationLogger(ctx, job, job.par2Path)
+
+	rels, rerr := prog.findRelocations(ctx, job, basePath)
+	if rerr != nil {
+		logger.Warn("Failed to search for relocated protected files", "error", rerr)
+
+		return err
+	}
+	for _, rel := range rels {
+		logger.Warn("Missing protected file found relocated (candidate)", "name", rel.name, "candidate", rel.path)
+	}
+
+	if !job.relocate || len(rels) == 0 {
+		return err
+	}
+
+	relArgs := slices.Clone(cmdArgs)
+	for _, rel := range rels {
+		relArgs = append(relArgs, rel.path)
+	}
+
+	logger.Info("Re-verifying PAR2 with the relocated protected files", "count", len(rels))
+
+	return prog.runner.Run(ctx, "par2", relArgs, basePath, stdout, stdout) //nolint:wrapcheck
+}
+
+// findRelocations returns the protected files missing at their names below
+// the base path, which were found elsewhere below the base path having the
+// same size and hashes (of the first 16 KiB, and of the entire file). The
+// ignore files and --max-depth (from the scan root) are honored as in the
+// enumeration of the PAR2 sets.
+func (prog *Service) findRelocations(ctx context.Context, job *Job, basePath string) ([]relocation, error) {
+	sets, err := prog.parseJobSets(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make(map[int64][]par2.FilePacket)
+	seen := make(map[par2.Hash]struct{})
+	for _, set := range sets {
+		for _, fp := range set.RecoverySet {
+			if _, ok := seen[fp.FileID]; ok {
+				continue
+			}
+			seen[fp.FileID] = struct{}{}
+
+			name := filepath.FromSlash(fp.Name)
+			if fp.Size <= 0 || !filepath.IsLocal(name) {
+				continue
+			}
+			if _, err := prog.fsys.Stat(filepath.Join(basePath, name)); !errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			missing[fp.Size] = append(missing[fp.Size], fp)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	var rels []relocation
+	found := make(map[par2.Hash]struct{})
+
+	rootDir := cmp.Or(job.rootDir, basePath)
+	checker := util.NewIgnoreChecker(prog.fsys, rootDir, job.ignoreNames)
+
+	werr := prog.walker.WalkDir(basePath, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			return nil //nolint:nilerr
+		}
+		if d.IsDir() {
+			if util.ExceedsMaxDepth(rootDir, path, job.maxDepth) {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil //nolint:nilerr
+		}
+
+		candidates := slices.DeleteFunc(slices.Clone(missing[info.Size()]), func(fp par2.FilePacket) bool {
+			_, ok := found[fp.FileID]
+
+			return ok
+		})
+		if len(candidates) == 0 || checker.ShouldIgnore(path) {
+			return nil
+		}
+
+		fp, ok := prog.matchRelocation(path, candidates)
+		if ok {
+			found[fp.FileID] = struct{}{}
+			rels = append(rels, relocation{name: fp.Name, path: path})
+		}
+
+		return nil
+	})
+	if werr != nil {
+		return rels, fmt.Errorf("failed to walk: %w", werr)
+	}
+
+	return rels, nil
+}
+
+// matchRelocation returns the candidate having the same hashes as the file,
+// hashing the entire file only if the hash of its first 16 KiB matches any.
+func (prog *Service) matchRelocation(path string, candidates []par2.FilePacket) (par2.FilePacket, bool) {
+	f, err := prog.fsys.Open(path)
+	if err != nil {
+		return par2.FilePacket{}, false
+	}
+	defer f.Close()
+
+	h := md5.New()
+	if _, err := io.CopyN(h, f, par2Hash16kSize); err != nil && !errors.Is(err, io.EOF) {
+		return par2.FilePacket{}, false
+	}
+	hash16k := par2.Hash(h.Sum(nil))
+
+	candidates = slices.DeleteFunc(candidates, func(fp par2.FilePacket) bool {
+		return fp.Hash16k != hash16k
+	})
+	if len(candidates) == 0 {
+		return par2.FilePacket{}, false
+	}
+
+	// The hashing continues after the first 16 KiB (as Sum keeps the state).
+	if _, err := io.Copy(h, f); err != nil {
+		return par2.FilePacket{}, false
+	}
+	hash := par2.Hash(h.Sum(nil))
+
+	for _, fp := range candidates {
+		if fp.Hash == hash {
+			return fp, true
+		}
+	}
+
+	return par2.FilePacket{}, false
+}
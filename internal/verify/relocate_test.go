@@ -0,0 +1,178 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// relocationPacket returns the file packet of a protected file with the content.
+func relocationPacket(id byte, name string, content []byte) par2.FilePacket {
+	return par2.FilePacket{
+		FileID:  par2.Hash{id},
+		Name:    name,
+		Size:    int64(len(content)),
+		Hash:    md5.Sum(content),
+		Hash16k: md5.Sum(content[:min(len(content), par2Hash16kSize)]),
+	}
+}
+
+// newRelocationService returns a service whose PAR2 sets protect the packets.
+func newRelocationService(t *testing.T, fs afero.Fs, logBuf io.Writer, runner schema.CommandRunner, fps ...par2.FilePacket) *Service {
+	t.Helper()
+
+	ls := logging.Options{
+		Logout: logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	prog.par2er = &testutil.MockPar2Handler{
+		ParseFileFunc: func(_ afero.Fs, _ string, _ bool) (*par2.File, error) {
+			return &par2.File{Sets: []par2.Set{{
+				MainPacket:  &par2.MainPacket{SliceSize: 4},
+				RecoverySet: fps,
+			}}}, nil
+		},
+	}
+
+	return prog
+}
+
+// Expectation: A protected file moved into a subfolder should be found by its size and hashes,
+// neither confusing it with files of the same size (or same first 16 KiB) nor present files.
+func Test_Service_findRelocations_MovedFile_Success(t *testing.T) {
+	t.Parallel()
+
+	moved := bytes.Repeat([]byte("m"), par2Hash16kSize+10)
+	decoy := slices.Concat(moved[:par2Hash16kSize], bytes.Repeat([]byte("x"), 10))
+	present := []byte("present")
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+	require.NoError(t, fs.MkdirAll("/data/sub/deeper", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/decoy.bin", decoy, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/sub/deeper/moved.bin", moved, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/present.bin", present, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/sub/present.bin", present, 0o644))
+
+	prog := newRelocationService(t, fs, io.Discard, &testutil.MockRunner{},
+		relocationPacket(1, "moved.bin", moved),
+		relocationPacket(2, "present.bin", present),
+		relocationPacket(3, "gone.bin", []byte("gone")),
+	)
+	job := NewJob("/data/test"+schema.Par2Extension, Options{}, nil, false)
+
+	rels, err := prog.findRelocations(t.Context(), job, "/data")
+
+	require.NoError(t, err)
+	require.Equal(t, []relocation{{name: "moved.bin", path: "/data/sub/deeper/moved.bin"}}, rels)
+}
+
+// Expectation: Protected files moved below an ignore file or beyond --max-depth should not be found,
+// as these are not walked in the enumeration of the PAR2 sets either.
+func Test_Service_findRelocations_IgnoredAndMaxDepth_Success(t *testing.T) {
+	t.Parallel()
+
+	ignored := []byte("ignored content")
+	deep := []byte("deep content")
+	shallow := []byte("shallow content")
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+	require.NoError(t, fs.MkdirAll("/data/skip", 0o755))
+	require.NoError(t, fs.MkdirAll("/data/one/two", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/skip/"+schema.IgnoreFile, []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/skip/ignored.bin", ignored, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/one/two/deep.bin", deep, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/one/shallow.bin", shallow, 0o644))
+
+	prog := newRelocationService(t, fs, io.Discard, &testutil.MockRunner{},
+		relocationPacket(1, "ignored.bin", ignored),
+		relocationPacket(2, "deep.bin", deep),
+		relocationPacket(3, "shallow.bin", shallow),
+	)
+
+	opts := Options{}
+	require.NoError(t, opts.MaxDepth.Set("1"))
+	job := NewJob("/data/test"+schema.Par2Extension, opts, nil, false)
+
+	rels, err := prog.findRelocations(t.Context(), job, "/data")
+
+	require.NoError(t, err)
+	require.Equal(t, []relocation{{name: "shallow.bin", path: "/data/one/shallow.bin"}}, rels)
+}
+
+// Expectation: The relocation candidates should only be searched for with the option, and
+// par2 should only be re-run with these as extra files with --relocate.
+func Test_Service_Verify_Relocate_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		opts             Options
+		wantCandidate    bool
+		wantCalls        int
+		wantRepairNeeded bool
+	}{
+		{"off", Options{}, false, 1, true},
+		{"find relocated", Options{FindRelocated: true}, true, 1, true},
+		{"relocate", Options{Relocate: true}, true, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			content := []byte("content of a")
+
+			fs := afero.NewMemMapFs()
+			createWithManifest(t, fs, "/data/test")
+			require.NoError(t, fs.MkdirAll("/data/sub", 0o755))
+			require.NoError(t, afero.WriteFile(fs, "/data/sub/a.txt", content, 0o644))
+
+			var runArgs [][]string
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					runArgs = append(runArgs, args)
+					if !slices.Contains(args, "/data/sub/a.txt") {
+						return testutil.CreateExitError(t, ctx, schema.Par2ExitCodeRepairImpossible)
+					}
+
+					return nil
+				},
+			}
+
+			logBuf := &testutil.SafeBuffer{}
+			prog := newRelocationService(t, fs, logBuf, runner, relocationPacket(1, "a.txt", content))
+			_, _ = prog.Verify(t.Context(), []string{"/data"}, tt.opts)
+
+			require.Len(t, runArgs, tt.wantCalls)
+			if tt.wantCalls > 1 {
+				require.Equal(t, []string{"verify", "-B/data", "--", "/data/test" + schema.Par2Extension, "/data/sub/a.txt"}, runArgs[1])
+			}
+			require.Equal(t, tt.wantCandidate, bytes.Contains(logBuf.Bytes(), []byte("found relocated")))
+
+			data, err := afero.ReadFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension)
+			require.NoError(t, err)
+
+			var mf schema.Manifest
+			require.NoError(t, json.Unmarshal(data, &mf))
+			require.Equal(t, tt.wantRepairNeeded, mf.Verification.RepairNeeded)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package verify
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+)
+
+// groupSplitSets reduces PAR2 index files sharing a set ID within the same
+// directory (a single logical set split across multiple index files) to one
+// job per set, pointing at its canonical index (see [canonicalSplitIndex]).
+// Only directories with more than one PAR2 index are considered, where set
+// IDs not already known from the cache are parsed (see [Service.splitSetID]),
+// bundles are self-contained and never grouped. Index files that cannot be
+// parsed remain their own jobs, so that par2 can report on them instead.
+func (prog *Service) groupSplitSets(ctx context.Context, metas []*JobMeta) ([]*JobMeta, error) {
+	byDir := make(map[string][]*JobMeta)
+	for _, meta := range metas {
+		if meta.IsBundle {
+			continue
+		}
+		dir := filepath.Dir(meta.Par2Path)
+		byDir[dir] = append(byDir[dir], meta)
+	}
+
+	dropped := make(map[*JobMeta]struct{})
+	for _, dirMetas := range byDir {
+		if len(dirMetas) < 2 { //nolint:mnd
+			continue
+		}
+
+		byID := make(map[par2.Hash][]*JobMeta)
+		for _, meta := range dirMetas {
+			if err := ctx.Err(); err != nil {
+				return nil, fmt.Errorf("context error: %w", err)
+			}
+
+			setID, ok := prog.splitSetID(ctx, meta)
+			if !ok {
+				continue
+			}
+			byID[setID] = append(byID[setID], meta)
+		}
+
+		for _, group := range byID {
+			if len(group) < 2 { //nolint:mnd
+				continue
+			}
+
+			canonical := canonicalSplitIndex(group)
+			for _, meta := range group {
+				if meta == canonical {
+					continue
+				}
+				dropped[meta] = struct{}{}
+
+				logger := prog.verificationLogger(ctx, meta, nil)
+				logger.Info("A PAR2 index shares its set ID with another (verifying the set through its canonical index)",
+					"reason", schema.ReasonSplitSet, "canonical", canonical.Par2Path)
+				prog.filtered.Add(schema.ReasonSplitSet)
+			}
+		}
+	}
+
+	if len(dropped) == 0 {
+		return metas, nil
+	}
+
+	return slices.DeleteFunc(metas, func(meta *JobMeta) bool {
+		_, ok := dropped[meta]
+
+		return ok
+	}), nil
+}
+
+// splitSetID returns the set ID of a PAR2 index, as known from the cache or
+// else parsed from the index (and then recorded into its cached metadata).
+// An index that fails to parse, or holds more than one set, has none.
+func (prog *Service) splitSetID(ctx context.Context, meta *JobMeta) (par2.Hash, bool) {
+	if meta.SetID != (par2.Hash{}) {
+		return meta.SetID, true
+	}
+
+	f, err := prog.par2er.ParseFile(ctx, prog.fsys, meta.Par2Path, true)
+	if err != nil {
+		logger := prog.verificationLogger(ctx, meta, nil)
+		logger.Warn("Failed to parse PAR2 index for its set ID (verifying it as its own set)", "error", err)
+
+		return par2.Hash{}, false
+	}
+	if len(f.Sets) != 1 {
+		return par2.Hash{}, false
+	}
+
+	meta.SetID = f.Sets[0].SetID
+
+	return meta.SetID, true
+}
+
+// canonicalSplitIndex returns the canonical index of a split set, preferring
+// the one having a par2cron manifest, then the shortest and first filename.
+func canonicalSplitIndex(group []*JobMeta) *JobMeta {
+	return slices.MinFunc(group, func(a, b *JobMeta) int {
+		if a.HasManifest != b.HasManifest {
+			if a.HasManifest {
+				return -1
+			}
+
+			return 1
+		}
+
+		aName, bName := filepath.Base(a.Par2Path), filepath.Base(b.Par2Path)
+
+		return cmp.Or(
+			cmp.Compare(len(aName), len(bName)),
+			strings.Compare(aName, bName),
+		)
+	})
+}
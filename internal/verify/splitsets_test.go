@@ -0,0 +1,156 @@
+package verify
+
+import (
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/par2"
+	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/testutil"
+	"github.com/desertwitch/par2cron/internal/util"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+// Expectation: PAR2 index files sharing a set ID in one directory should be enumerated as one job.
+func Test_Service_Enumerate_SplitSet_Success(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile("../par2/testdata/simple_par2cmdline.par2")
+	require.NoError(t, err)
+	other, err := os.ReadFile("../par2/testdata/recursive_par2cmdline.par2")
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data/split", 0o755))
+	require.NoError(t, fs.MkdirAll("/data/elsewhere", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/split/archive.par2", data, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/split/archive.part2.par2", data, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/split/other.par2", other, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/elsewhere/archive.par2", data, 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	args := Options{IncludeExternal: true}
+	jobs, err := prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
+	require.NoError(t, err)
+
+	got := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		got = append(got, job.Par2Path)
+	}
+	slices.Sort(got)
+
+	require.Equal(t, []string{
+		"/data/elsewhere/archive.par2",
+		"/data/split/archive.par2",
+		"/data/split/other.par2",
+	}, got)
+	require.Contains(t, logBuf.String(), "A PAR2 index shares its set ID with another")
+	require.Contains(t, logBuf.String(), schema.ReasonSplitSet)
+}
+
+// Expectation: Index files that cannot be parsed should remain their own jobs.
+func Test_Service_groupSplitSets_ParseError_Success(t *testing.T) {
+	t.Parallel()
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{Logout: &logBuf, Stdout: io.Discard, Stderr: io.Discard}
+	prog := NewService(afero.NewMemMapFs(), logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	prog.par2er = &testutil.MockPar2Handler{
+		ParseFileFunc: func(_ afero.Fs, path string, _ bool) (*par2.File, error) {
+			if strings.HasSuffix(path, "broken.par2") {
+				return nil, errors.New("parse failure")
+			}
+
+			return &par2.File{Sets: []par2.Set{{SetID: par2.Hash{1}}}}, nil
+		},
+	}
+
+	metas := []*JobMeta{
+		NewJobMeta(&schema.JobMeta{Par2Path: "/data/a.par2"}),
+		NewJobMeta(&schema.JobMeta{Par2Path: "/data/broken.par2"}),
+		NewJobMeta(&schema.JobMeta{Par2Path: "/data/b.par2", IsBundle: true}),
+	}
+
+	got, err := prog.groupSplitSets(t.Context(), metas)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	require.Contains(t, logBuf.String(), "Failed to parse PAR2 index for its set ID (verifying it as its own set)")
+	require.Contains(t, logBuf.String(), "parse failure")
+}
+
+// Expectation: Set IDs known from the cache should not be parsed again, others should be recorded.
+func Test_Service_groupSplitSets_CachedSetID_Success(t *testing.T) {
+	t.Parallel()
+
+	ls := logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}
+	prog := NewService(afero.NewMemMapFs(), logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	var parsed []string
+	prog.par2er = &testutil.MockPar2Handler{
+		ParseFileFunc: func(_ afero.Fs, path string, _ bool) (*par2.File, error) {
+			parsed = append(parsed, path)
+
+			return &par2.File{Sets: []par2.Set{{SetID: par2.Hash{1}}}}, nil
+		},
+	}
+
+	cached := &schema.JobMeta{Par2Path: "/data/a.par2", SetID: par2.Hash{1}}
+	uncached := &schema.JobMeta{Par2Path: "/data/a.part2.par2"}
+	metas := []*JobMeta{NewJobMeta(cached), NewJobMeta(uncached)}
+
+	got, err := prog.groupSplitSets(t.Context(), metas)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, "/data/a.par2", got[0].Par2Path)
+
+	require.Equal(t, []string{"/data/a.part2.par2"}, parsed)
+	require.Equal(t, par2.Hash{1}, uncached.SetID)
+}
+
+// Expectation: The canonical index should prefer a manifest, then the shortest and first filename.
+func Test_canonicalSplitIndex_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		metas []*schema.JobMeta
+		want  string
+	}{
+		{
+			"shortest name",
+			[]*schema.JobMeta{{Par2Path: "/d/archive.part2.par2"}, {Par2Path: "/d/archive.par2"}},
+			"/d/archive.par2",
+		},
+		{
+			"first name",
+			[]*schema.JobMeta{{Par2Path: "/d/b.par2"}, {Par2Path: "/d/a.par2"}},
+			"/d/a.par2",
+		},
+		{
+			"manifest",
+			[]*schema.JobMeta{{Par2Path: "/d/a.par2"}, {Par2Path: "/d/archive.part2.par2", HasManifest: true}},
+			"/d/archive.part2.par2",
+		},
+	}
+
+	for _, tt := range tests {
+		group := make([]*JobMeta, 0, len(tt.metas))
+		for _, meta := range tt.metas {
+			group = append(group, NewJobMeta(meta))
+		}
+		require.Equal(t, tt.want, canonicalSplitIndex(group).Par2Path, tt.name)
+	}
+}
@@ -1,10 +1,17 @@
 package verify
 
 import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/desertwitch/par2cron/internal/schema"
+	"github.com/desertwitch/par2cron/internal/util"
 )
 
 type Stats struct {
@@ -28,7 +35,7 @@ func (prog *Service) Stats(metas []*JobMeta) Stats {
 
 	js.JobCount = len(metas)
 	for _, meta := range metas {
-		est := meta.lastDuration()
+		est := meta.EstDuration()
 		if est > 0 {
 			js.TotalDuration += est
 			js.KnownCount++
@@ -78,8 +85,11 @@ func (meta *JobMeta) queuePriority() int {
 	case !meta.HasVerification:
 		return prioNoVerification // Manifest, but no verification.
 
-	case meta.RepairNeeded:
-		return prioNeedsRepair // PAR2 needing repair.
+	case meta.Interrupted:
+		return prioNoVerification // Verification or repair was interrupted.
+
+	case meta.RepairNeeded, meta.QuickProblems:
+		return prioNeedsRepair // PAR2 needing repair (or found with problems by --quick).
 
 	default:
 		return prioOther // Normal, sorted by verification age.
@@ -102,24 +112,41 @@ func (meta *JobMeta) lastVerifiedStr() string {
 	return meta.VerifyTime.String()
 }
 
-func (meta *JobMeta) lastDuration() time.Duration {
+// EstDuration returns the median of the recent verification durations,
+// so that a single outlier does not skew the estimate, falling back to
+// the last verification duration for manifests without a rolling window.
+func (meta *JobMeta) EstDuration() time.Duration {
 	if !meta.HasManifest || !meta.HasVerification {
 		return 0
 	}
+	if len(meta.RecentDurations) == 0 {
+		return meta.VerifyDuration
+	}
 
-	return meta.VerifyDuration
+	sorted := slices.Clone(meta.RecentDurations)
+	slices.Sort(sorted)
+
+	mid := len(sorted) / 2 //nolint:mnd
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2 //nolint:mnd
+	}
+
+	return sorted[mid]
 }
 
-func (meta *JobMeta) lastDurationStr() string {
+func (meta *JobMeta) estDurationStr() string {
 	if !meta.HasManifest || !meta.HasVerification {
 		return ""
 	}
 
-	return meta.VerifyDuration.String()
+	return meta.EstDuration().String()
 }
 
-func filterByAge(metas []*JobMeta, minAge time.Duration) []*JobMeta {
-	if len(metas) == 0 || minAge <= 0 {
+// filterByAge selects the jobs due for verification, where a job's own
+// verification interval (as set at creation) takes precedence over minAge.
+// Jobs of forced PAR2 sets (or below forced directories) are always selected.
+func filterByAge(metas []*JobMeta, minAge time.Duration, forced []string) []*JobMeta {
+	if len(metas) == 0 {
 		return metas
 	}
 
@@ -129,15 +156,29 @@ func filterByAge(metas []*JobMeta, minAge time.Duration) []*JobMeta {
 	for _, meta := range metas {
 		// Always include jobs with no manifest/no verification.
 		// This is to get the first verification as soon as possible.
-		if !meta.HasManifest || !meta.HasVerification {
+		// The same applies to jobs which had been interrupted before,
+		// or which a quick verification (--quick) found problems with.
+		if !meta.HasManifest || !meta.HasVerification || meta.Interrupted || meta.QuickProblems {
+			filtered = append(filtered, meta)
+
+			continue
+		}
+
+		// Forced jobs are included regardless of their last verification.
+		if isForced(meta.Par2Path, forced) {
 			filtered = append(filtered, meta)
 
 			continue
 		}
 
 		// Otherwise include if last verification is older than minAge.
+		interval := minAge
+		if meta.VerifyInterval > 0 {
+			interval = meta.VerifyInterval
+		}
+
 		age := now.Sub(meta.VerifyTime)
-		if age >= minAge {
+		if interval <= 0 || age >= interval {
 			filtered = append(filtered, meta)
 		}
 	}
@@ -145,7 +186,13 @@ func filterByAge(metas []*JobMeta, minAge time.Duration) []*JobMeta {
 	return filtered
 }
 
-func sortJobs(metas []*JobMeta) {
+func sortJobs(metas []*JobMeta, order string) {
+	if order == schema.VerifyOrderNewest {
+		sortJobsNewest(metas)
+
+		return
+	}
+
 	sort.Slice(metas, func(i, j int) bool {
 		pi := metas[i].queuePriority()
 		pj := metas[j].queuePriority()
@@ -165,6 +212,53 @@ func sortJobs(metas []*JobMeta) {
 	})
 }
 
+func sortJobsNewest(metas []*JobMeta) {
+	sort.Slice(metas, func(i, j int) bool {
+		ci := metas[i].HasCreation
+		cj := metas[j].HasCreation
+
+		if ci != cj {
+			return ci // Sort without creation last.
+		}
+
+		ti := metas[i].CreateTime
+		tj := metas[j].CreateTime
+
+		if !ti.Equal(tj) {
+			return ti.After(tj) // Sort by creation time (descending).
+		}
+
+		return metas[i].Par2Path < metas[j].Par2Path // Sort by path (fallback).
+	})
+}
+
+// shuffleJobs shuffles the jobs in a deterministic order for the given seed,
+// regardless of the order in which the jobs were enumerated.
+func shuffleJobs(metas []*JobMeta, seed uint64) {
+	slices.SortFunc(metas, func(a, b *JobMeta) int {
+		return strings.Compare(a.Par2Path, b.Par2Path)
+	})
+
+	rng := rand.New(rand.NewPCG(seed, seed)) //nolint:gosec
+	rng.Shuffle(len(metas), func(i, j int) {
+		metas[i], metas[j] = metas[j], metas[i]
+	})
+}
+
+// dailySeed returns a seed derived from the (local) date of now and the scan
+// roots, so that all runs on the same day (and roots) result in the same order.
+func dailySeed(now time.Time, rootDirs []string) uint64 {
+	h := fnv.New64a()
+
+	_, _ = h.Write([]byte(now.Format(time.DateOnly)))
+	for _, rootDir := range rootDirs {
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(rootDir))
+	}
+
+	return h.Sum64()
+}
+
 func filterByDuration(metas []*JobMeta, maxDuration time.Duration) []*JobMeta {
 	if len(metas) == 0 || maxDuration <= 0 {
 		return metas
@@ -174,11 +268,11 @@ func filterByDuration(metas []*JobMeta, maxDuration time.Duration) []*JobMeta {
 	// This is to ensure there's no starvation in the queue
 	// if any job exceeds maxDuration and never gets picked.
 	selected := []*JobMeta{metas[0]}
-	total := metas[0].lastDuration()
+	total := metas[0].EstDuration()
 
 	// Fit remaining jobs within maxDuration.
 	for _, meta := range metas[1:] {
-		est := meta.lastDuration()
+		est := meta.EstDuration()
 
 		if est == 0 {
 			// Always take jobs with no manifest/unknown duration.
@@ -211,3 +305,43 @@ func knownDuration(metas []*JobMeta) time.Duration {
 
 	return duration
 }
+
+func resolveMirrorRoot(mirrorDir string) (string, error) {
+	if mirrorDir == "" {
+		return "", nil
+	}
+
+	abs, err := filepath.Abs(mirrorDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert path to absolute: %w", err)
+	}
+
+	return abs, nil
+}
+
+// isForced returns if the PAR2 path equals or is below any of the forced paths.
+func isForced(par2Path string, forced []string) bool {
+	for _, path := range forced {
+		rel, err := filepath.Rel(path, par2Path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// mirrorWorkingDir translates a working directory below one of the root
+// directories into the corresponding directory below the mirror root.
+func mirrorWorkingDir(rootDirs []string, mirrorRoot string, workingDir string) string {
+	bestRoot, ok := util.ContainingRoot(rootDirs, workingDir)
+	if !ok {
+		return ""
+	}
+
+	rel, _ := filepath.Rel(bestRoot, workingDir)
+
+	return filepath.Join(mirrorRoot, rel)
+}
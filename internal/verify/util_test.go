@@ -1,7 +1,9 @@
 package verify
 
 import (
+	"fmt"
 	"io"
+	"slices"
 	"testing"
 	"time"
 
@@ -314,18 +316,43 @@ func Test_Job_lastVerifiedStr_WithVerification_Success(t *testing.T) {
 	require.Equal(t, now.String(), result)
 }
 
-// Expectation: A zero duration should be returned when no manifest exists.
-func Test_Job_lastDuration_NoManifest_Success(t *testing.T) {
+// Expectation: The median of the recent durations should be returned, ignoring a single outlier.
+func Test_Job_EstDuration_MedianOutlier_Success(t *testing.T) {
 	t.Parallel()
 
-	meta := &JobMeta{&schema.JobMeta{}}
-	duration := meta.lastDuration()
+	meta := &JobMeta{
+		&schema.JobMeta{
+			HasManifest:     true,
+			HasVerification: true,
+			VerifyDuration:  50 * time.Minute,
+			RecentDurations: []time.Duration{
+				5 * time.Minute, 6 * time.Minute, 4 * time.Minute, 5 * time.Minute, 50 * time.Minute,
+			},
+		},
+	}
+
+	require.Equal(t, 5*time.Minute, meta.EstDuration())
+	require.Equal(t, "5m0s", meta.estDurationStr())
+}
 
-	require.Zero(t, duration)
+// Expectation: The mean of the middle durations should be returned for an even count.
+func Test_Job_EstDuration_EvenCount_Success(t *testing.T) {
+	t.Parallel()
+
+	meta := &JobMeta{
+		&schema.JobMeta{
+			HasManifest:     true,
+			HasVerification: true,
+			VerifyDuration:  6 * time.Minute,
+			RecentDurations: []time.Duration{6 * time.Minute, 4 * time.Minute},
+		},
+	}
+
+	require.Equal(t, 5*time.Minute, meta.EstDuration())
 }
 
-// Expectation: The correct duration should be returned when a manifest exists.
-func Test_Job_lastDuration_WithVerification_Success(t *testing.T) {
+// Expectation: The last duration should be returned for manifests without a window.
+func Test_Job_EstDuration_NoWindow_Success(t *testing.T) {
 	t.Parallel()
 
 	meta := &JobMeta{
@@ -335,23 +362,24 @@ func Test_Job_lastDuration_WithVerification_Success(t *testing.T) {
 			VerifyDuration:  5 * time.Minute,
 		},
 	}
-	duration := meta.lastDuration()
+	require.Equal(t, 5*time.Minute, meta.EstDuration())
 
-	require.Equal(t, 5*time.Minute, duration)
+	meta = &JobMeta{&schema.JobMeta{}}
+	require.Zero(t, meta.EstDuration())
 }
 
 // Expectation: A question mark should be printed if no manifest exists.
-func Test_Job_lastDurationStr_NoManifest_Success(t *testing.T) {
+func Test_Job_estDurationStr_NoManifest_Success(t *testing.T) {
 	t.Parallel()
 
 	meta := &JobMeta{&schema.JobMeta{}}
-	result := meta.lastDurationStr()
+	result := meta.estDurationStr()
 
 	require.Empty(t, result)
 }
 
 // Expectation: A question mark should be printed if no verification exists.
-func Test_Job_lastDurationStr_NoVerification_Success(t *testing.T) {
+func Test_Job_estDurationStr_NoVerification_Success(t *testing.T) {
 	t.Parallel()
 
 	meta := &JobMeta{
@@ -359,13 +387,13 @@ func Test_Job_lastDurationStr_NoVerification_Success(t *testing.T) {
 			HasManifest: true,
 		},
 	}
-	result := meta.lastDurationStr()
+	result := meta.estDurationStr()
 
 	require.Empty(t, result)
 }
 
 // Expectation: The correct duration string should be returned for the duration.
-func Test_Job_lastDurationStr_WithVerification_Success(t *testing.T) {
+func Test_Job_estDurationStr_WithVerification_Success(t *testing.T) {
 	t.Parallel()
 
 	meta := &JobMeta{
@@ -375,7 +403,7 @@ func Test_Job_lastDurationStr_WithVerification_Success(t *testing.T) {
 			VerifyDuration:  5 * time.Minute,
 		},
 	}
-	result := meta.lastDurationStr()
+	result := meta.estDurationStr()
 
 	require.NotEqual(t, "?", result)
 	require.Equal(t, "5m0s", result)
@@ -389,7 +417,7 @@ func Test_filterByAge_NoMinAge_Success(t *testing.T) {
 		{&schema.JobMeta{Par2Path: "/data/test1" + schema.Par2Extension}},
 		{&schema.JobMeta{Par2Path: "/data/test2" + schema.Par2Extension}},
 	}
-	filtered := filterByAge(metas, 0)
+	filtered := filterByAge(metas, 0, nil)
 
 	require.Len(t, filtered, 2)
 }
@@ -419,12 +447,44 @@ func Test_filterByAge_WithMinAge_Success(t *testing.T) {
 			},
 		},
 	}
-	filtered := filterByAge(metas, 24*time.Hour)
+	filtered := filterByAge(metas, 24*time.Hour, nil)
 
 	require.Len(t, filtered, 1)
 	require.Equal(t, "/data/old"+schema.Par2Extension, filtered[0].Par2Path)
 }
 
+// Expectation: A job's own verification interval should take precedence over --age.
+func Test_filterByAge_VerifyInterval_Success(t *testing.T) {
+	t.Parallel()
+
+	verified := func(name string, ago time.Duration, interval time.Duration) *JobMeta {
+		return &JobMeta{
+			&schema.JobMeta{
+				Par2Path:        "/data/" + name + schema.Par2Extension,
+				HasManifest:     true,
+				HasVerification: true,
+				VerifyTime:      time.Now().Add(-ago),
+				VerifyInterval:  interval,
+			},
+		}
+	}
+
+	metas := []*JobMeta{
+		verified("critical", 2*time.Hour, time.Hour),
+		verified("regular", 2*time.Hour, 0),
+		verified("relaxed", 30*time.Hour, 48*time.Hour),
+	}
+
+	filtered := filterByAge(metas, 24*time.Hour, nil)
+	require.Len(t, filtered, 1)
+	require.Equal(t, "/data/critical"+schema.Par2Extension, filtered[0].Par2Path)
+
+	filtered = filterByAge(metas, 0, nil)
+	require.Len(t, filtered, 2)
+	require.Equal(t, "/data/critical"+schema.Par2Extension, filtered[0].Par2Path)
+	require.Equal(t, "/data/regular"+schema.Par2Extension, filtered[1].Par2Path)
+}
+
 // Expectation: Jobs without manifest should always be returned.
 func Test_filterByAge_NoVerification_Success(t *testing.T) {
 	t.Parallel()
@@ -436,7 +496,7 @@ func Test_filterByAge_NoVerification_Success(t *testing.T) {
 			},
 		},
 	}
-	filtered := filterByAge(metas, 24*time.Hour)
+	filtered := filterByAge(metas, 24*time.Hour, nil)
 
 	require.Len(t, filtered, 1)
 }
@@ -508,6 +568,35 @@ func Test_filterByDuration_FitsMultiple_Success(t *testing.T) {
 	require.Len(t, filtered, 2)
 }
 
+// Expectation: Jobs should be fit by their median duration, not by an outlier of their last duration.
+func Test_filterByDuration_MedianDuration_Success(t *testing.T) {
+	t.Parallel()
+
+	metas := []*JobMeta{
+		{
+			&schema.JobMeta{
+				Par2Path:        "/data/job1" + schema.Par2Extension,
+				HasManifest:     true,
+				HasVerification: true,
+				VerifyDuration:  50 * time.Minute,
+				RecentDurations: []time.Duration{10 * time.Minute, 10 * time.Minute, 50 * time.Minute},
+			},
+		},
+		{
+			&schema.JobMeta{
+				Par2Path:        "/data/job2" + schema.Par2Extension,
+				HasManifest:     true,
+				HasVerification: true,
+				VerifyDuration:  30 * time.Minute,
+				RecentDurations: []time.Duration{30 * time.Minute},
+			},
+		},
+	}
+	filtered := filterByDuration(metas, 1*time.Hour)
+
+	require.Len(t, filtered, 2)
+}
+
 // Expectation: Jobs with unknown duration should always be included regardless of max duration.
 func Test_filterByDuration_UnknownDurationAlwaysIncluded_Success(t *testing.T) {
 	t.Parallel()
@@ -615,7 +704,7 @@ func Test_sortJobs_Success(t *testing.T) {
 			},
 		},
 	}
-	sortJobs(metas)
+	sortJobs(metas, schema.VerifyOrderOldest)
 
 	require.Equal(t, "/data/no-manifest"+schema.Par2Extension, metas[0].Par2Path)
 	require.Equal(t, "/data/needs-repair"+schema.Par2Extension, metas[1].Par2Path)
@@ -649,7 +738,7 @@ func Test_sortJobs_SamePriority_SortByTime_Success(t *testing.T) {
 			},
 		},
 	}
-	sortJobs(metas)
+	sortJobs(metas, schema.VerifyOrderOldest)
 
 	require.Equal(t, "/data/old"+schema.Par2Extension, metas[0].Par2Path)
 	require.Equal(t, "/data/recent"+schema.Par2Extension, metas[1].Par2Path)
@@ -682,7 +771,7 @@ func Test_sortJobs_SamePriorityAndTime_SortByPath_Success(t *testing.T) {
 		},
 	}
 
-	sortJobs(metas)
+	sortJobs(metas, schema.VerifyOrderOldest)
 
 	require.Equal(t, "/data/apple"+schema.Par2Extension, metas[0].Par2Path)
 	require.Equal(t, "/data/zebra"+schema.Par2Extension, metas[1].Par2Path)
@@ -738,7 +827,7 @@ func Test_sortJobs_ComplexSorting_Success(t *testing.T) {
 			},
 		},
 	}
-	sortJobs(metas)
+	sortJobs(metas, schema.VerifyOrderOldest)
 
 	// Priority order: no manifest, needs repair (by time), normal (by time)
 	require.Equal(t, "/data/no-manifest"+schema.Par2Extension, metas[0].Par2Path)
@@ -748,6 +837,116 @@ func Test_sortJobs_ComplexSorting_Success(t *testing.T) {
 	require.Equal(t, "/data/normal-recent"+schema.Par2Extension, metas[4].Par2Path)
 }
 
+// Expectation: The newest order should sort by creation time descending, with sets lacking creation last.
+func Test_sortJobs_OrderNewest_Success(t *testing.T) {
+	t.Parallel()
+
+	oldTime := time.Now().Add(-48 * time.Hour)
+	recentTime := time.Now().Add(-24 * time.Hour)
+
+	metas := []*JobMeta{
+		{
+			&schema.JobMeta{
+				Par2Path: "/data/no-manifest" + schema.Par2Extension,
+			},
+		},
+		{
+			&schema.JobMeta{
+				Par2Path:    "/data/created-old" + schema.Par2Extension,
+				HasManifest: true,
+				HasCreation: true,
+				CreateTime:  oldTime,
+			},
+		},
+		{
+			&schema.JobMeta{
+				Par2Path:        "/data/no-creation" + schema.Par2Extension,
+				HasManifest:     true,
+				HasVerification: true,
+				RepairNeeded:    true,
+			},
+		},
+		{
+			&schema.JobMeta{
+				Par2Path:        "/data/created-recent-b" + schema.Par2Extension,
+				HasManifest:     true,
+				HasCreation:     true,
+				CreateTime:      recentTime,
+				HasVerification: true,
+			},
+		},
+		{
+			&schema.JobMeta{
+				Par2Path:    "/data/created-recent-a" + schema.Par2Extension,
+				HasManifest: true,
+				HasCreation: true,
+				CreateTime:  recentTime,
+			},
+		},
+	}
+	sortJobs(metas, schema.VerifyOrderNewest)
+
+	require.Equal(t, "/data/created-recent-a"+schema.Par2Extension, metas[0].Par2Path)
+	require.Equal(t, "/data/created-recent-b"+schema.Par2Extension, metas[1].Par2Path)
+	require.Equal(t, "/data/created-old"+schema.Par2Extension, metas[2].Par2Path)
+	require.Equal(t, "/data/no-creation"+schema.Par2Extension, metas[3].Par2Path)
+	require.Equal(t, "/data/no-manifest"+schema.Par2Extension, metas[4].Par2Path)
+}
+
+func newShuffleTestJobs(n int) []*JobMeta {
+	metas := make([]*JobMeta, n)
+	for i := range metas {
+		metas[i] = &JobMeta{&schema.JobMeta{Par2Path: fmt.Sprintf("/data/set-%02d%s", i, schema.Par2Extension)}}
+	}
+
+	return metas
+}
+
+func jobPaths(metas []*JobMeta) []string {
+	paths := make([]string, len(metas))
+	for i, meta := range metas {
+		paths[i] = meta.Par2Path
+	}
+
+	return paths
+}
+
+// Expectation: Runs on the same day should produce the same order, runs on different days not.
+func Test_shuffleJobs_DailySeed_Success(t *testing.T) {
+	t.Parallel()
+
+	roots := []string{"/data"}
+	morning := time.Date(2026, 3, 14, 1, 0, 0, 0, time.Local)
+	evening := time.Date(2026, 3, 14, 23, 0, 0, 0, time.Local)
+	nextDay := time.Date(2026, 3, 15, 1, 0, 0, 0, time.Local)
+
+	first := newShuffleTestJobs(20)
+	shuffleJobs(first, dailySeed(morning, roots))
+
+	second := newShuffleTestJobs(20)
+	slices.Reverse(second) // Order of enumeration should not matter.
+	shuffleJobs(second, dailySeed(evening, roots))
+
+	third := newShuffleTestJobs(20)
+	shuffleJobs(third, dailySeed(nextDay, roots))
+
+	require.Equal(t, jobPaths(first), jobPaths(second))
+	require.NotEqual(t, jobPaths(first), jobPaths(third))
+	require.NotEqual(t, jobPaths(newShuffleTestJobs(20)), jobPaths(first))
+	require.ElementsMatch(t, jobPaths(first), jobPaths(third))
+}
+
+// Expectation: The seed should differ between different scan roots on the same day.
+func Test_dailySeed_Roots_Success(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.Local)
+
+	require.Equal(t, dailySeed(now, []string{"/data"}), dailySeed(now, []string{"/data"}))
+	require.NotEqual(t, dailySeed(now, []string{"/data"}), dailySeed(now, []string{"/media"}))
+	require.NotEqual(t, dailySeed(now, []string{"/data", "/media"}), dailySeed(now, []string{"/data/media"}))
+}
+
 // Expectation: All known durations should be added together correctly.
 func Test_knownDuration_Success(t *testing.T) {
 	t.Parallel()
@@ -827,3 +1026,96 @@ func Test_knownDuration_IgnoresZeroAndNegativeDurations_Success(t *testing.T) {
 
 	require.Equal(t, 5*time.Minute, duration)
 }
+
+// Expectation: The working directory should be translated below the mirror root.
+func Test_mirrorWorkingDir_Success(t *testing.T) {
+	t.Parallel()
+
+	roots := []string{"/data", "/data/nested", "/other"}
+
+	require.Equal(t, "/backup", mirrorWorkingDir(roots, "/backup", "/data"))
+	require.Equal(t, "/backup/a/b", mirrorWorkingDir(roots, "/backup", "/data/a/b"))
+	require.Equal(t, "/backup/c", mirrorWorkingDir(roots, "/backup", "/data/nested/c"))
+	require.Equal(t, "/backup/d", mirrorWorkingDir(roots, "/backup", "/other/d"))
+}
+
+// Expectation: A working directory outside of all root directories should not be translated.
+func Test_mirrorWorkingDir_OutsideRoots_Success(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, mirrorWorkingDir([]string{"/data"}, "/backup", "/database"))
+	require.Empty(t, mirrorWorkingDir([]string{"/data"}, "/backup", "/elsewhere"))
+}
+
+// Expectation: An interrupted job should be prioritized like an unverified job.
+func Test_queuePriority_Interrupted_Success(t *testing.T) {
+	t.Parallel()
+
+	meta := NewJobMeta(&schema.JobMeta{
+		HasManifest:     true,
+		HasVerification: true,
+		Interrupted:     true,
+	})
+
+	require.Equal(t, prioNoVerification, meta.queuePriority())
+}
+
+// Expectation: An interrupted job should not be filtered by age.
+func Test_filterByAge_Interrupted_Success(t *testing.T) {
+	t.Parallel()
+
+	metas := []*JobMeta{
+		NewJobMeta(&schema.JobMeta{
+			Par2Path:        "/data/recent.par2",
+			HasManifest:     true,
+			HasVerification: true,
+			VerifyTime:      time.Now(),
+		}),
+		NewJobMeta(&schema.JobMeta{
+			Par2Path:        "/data/interrupted.par2",
+			HasManifest:     true,
+			HasVerification: true,
+			VerifyTime:      time.Now(),
+			Interrupted:     true,
+		}),
+	}
+
+	filtered := filterByAge(metas, 24*time.Hour, nil)
+
+	require.Len(t, filtered, 1)
+	require.Equal(t, "/data/interrupted.par2", filtered[0].Par2Path)
+}
+
+// Expectation: A forced job within the age window should be selected, an unforced one not.
+func Test_filterByAge_Forced_Success(t *testing.T) {
+	t.Parallel()
+
+	verified := func(path string) *JobMeta {
+		return NewJobMeta(&schema.JobMeta{
+			Par2Path:        path,
+			HasManifest:     true,
+			HasVerification: true,
+			VerifyTime:      time.Now().Add(-time.Hour),
+		})
+	}
+
+	metas := []*JobMeta{
+		verified("/data/forced/a.par2"),
+		verified("/data/forced/sub/b.par2"),
+		verified("/data/forcedness/c.par2"),
+		verified("/data/single.par2"),
+		verified("/data/unforced.par2"),
+	}
+
+	filtered := filterByAge(metas, 24*time.Hour, []string{"/data/forced", "/data/single.par2"})
+
+	got := make([]string, 0, len(filtered))
+	for _, meta := range filtered {
+		got = append(got, meta.Par2Path)
+	}
+	require.Equal(t, []string{
+		"/data/forced/a.par2",
+		"/data/forced/sub/b.par2",
+		"/data/single.par2",
+	}, got)
+}
@@ -1,13 +1,18 @@
 package verify
 
 import (
+	"cmp"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"log/slog"
+	"os/exec"
 	"path/filepath"
 	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/desertwitch/par2cron/internal/flags"
@@ -24,22 +29,147 @@ const (
 	prioOther          = 3
 )
 
-var _ schema.OptionsPar2ArgsSettable = (*Options)(nil)
+const (
+	DefaultJobs          = 1
+	DefaultIOConcurrency = 4
+)
+
+var errPar2Changed = errors.New("par2 changed since manifest was recorded")
+
+var (
+	_ schema.OptionsValidatable       = (*Options)(nil)
+	_ schema.OptionsPar2ArgsSettable  = (*Options)(nil)
+	_ schema.OptionsPar2ArgsAllowable = (*Options)(nil)
+)
 
 type Options struct {
-	Par2Args        []string
-	MinAge          flags.Duration
-	MaxDuration     flags.Duration
-	RunInterval     flags.Duration
-	IncludeExternal bool
-	SkipNotCreated  bool
-	CacheDir        string
+	Par2Args            []string
+	AllowedPar2Args     []string
+	Par2Quiet           bool
+	Par2Verbose         bool
+	MinAge              flags.Duration
+	ChangedSince        string
+	Force               []string
+	MaxDuration         flags.Duration
+	Limit               int
+	MaxErrors           int
+	Jobs                int
+	IOConcurrency       int
+	RunInterval         flags.Duration
+	MinRunInterval      flags.Duration
+	IncludeExternal     bool
+	SkipNotCreated      bool
+	CleanOrphans        bool
+	CacheDir            string
+	MirrorDir           string
+	NoManifestUpdate    bool
+	OnlyNeedingRepair   bool
+	StrictPar2          bool
+	Par2ExitCodes       flags.Par2ExitCodes
+	RefreshStamp        bool
+	StampFile           string
+	Tags                flags.Tags
+	CreatedBefore       flags.Date
+	CreatedAfter        flags.Date
+	IncludeNotCreated   bool
+	Order               flags.VerifyOrder
+	NameMismatch        flags.NameMismatch
+	Queue               io.Reader
+	IgnoreNames         util.IgnoreNames
+	IgnoreHits          *util.IgnoreHits
+	SidecarNames        util.SidecarNames
+	MaxDepth            flags.MaxDepth
+	MaxManifestSize     int64
+	PauseFile           string
+	Checkpoint          string
+	Resume              bool
+	Quick               bool
+	DropCaches          bool
+	BasePath            flags.BasePath
+	RetrySingleThreaded bool
+	FileStatus          bool
+	FindRelocated       bool
+	Relocate            bool
+	VerifyPasses        int
+	DereferenceManifest bool
+	Classifier          string
+	CrossCheck          string
 }
 
 func (o *Options) SetPar2Args(args []string) {
 	o.Par2Args = slices.Clone(args)
 }
 
+func (o *Options) SetAllowedPar2Args(allowed []string) {
+	o.AllowedPar2Args = slices.Clone(allowed)
+}
+
+func (o *Options) Validate() error {
+	if o.AllowedPar2Args != nil {
+		if err := util.CheckPar2Args(o.Par2Args, o.AllowedPar2Args); err != nil {
+			return fmt.Errorf("args: %w", err)
+		}
+	}
+	if o.Jobs < 0 {
+		return fmt.Errorf("jobs: must not be negative, got %d", o.Jobs)
+	}
+	if o.IOConcurrency < 0 {
+		return fmt.Errorf("io-concurrency: must not be negative, got %d", o.IOConcurrency)
+	}
+	if o.VerifyPasses < 0 {
+		return fmt.Errorf("verify-passes: must not be negative, got %d", o.VerifyPasses)
+	}
+
+	if err := util.CheckPar2Verbosity(o.Par2Args, o.Par2Quiet, o.Par2Verbose); err != nil {
+		return fmt.Errorf("par2 verbosity: %w", err)
+	}
+	if err := util.CheckPar2BasePath(o.Par2Args, o.BasePath.Value); err != nil {
+		return fmt.Errorf("basepath: %w", err)
+	}
+	if o.FileStatus && (o.Par2Quiet || strings.HasPrefix(util.Par2VerbosityArg(o.Par2Args), "-q")) {
+		return errors.New("file-status: needs the par2 output of the protected files (not with quiet verbosity)")
+	}
+
+	if o.RefreshStamp {
+		if err := util.ValidateStampFile(o.StampFile); err != nil {
+			return fmt.Errorf("stamp-file: %w", err)
+		}
+	}
+
+	if !o.CreatedBefore.Value.IsZero() && !o.CreatedAfter.Value.IsZero() && !o.CreatedAfter.Value.Before(o.CreatedBefore.Value) {
+		return fmt.Errorf("created-after: must be before --created-before, got %s and %s", o.CreatedAfter.Raw, o.CreatedBefore.Raw)
+	}
+	if o.IncludeNotCreated && !o.filtersCreated() {
+		return errors.New("include-not-created: requires --created-before or --created-after")
+	}
+	if o.Resume && o.Checkpoint == "" {
+		return errors.New("resume: requires --checkpoint")
+	}
+	if o.Quick && o.MirrorDir != "" {
+		return errors.New("quick: cannot be combined with --mirror")
+	}
+	if o.Classifier != "" {
+		if _, err := exec.LookPath(o.Classifier); err != nil {
+			return fmt.Errorf("classifier: %w", err)
+		}
+	}
+	if o.CrossCheck != "" {
+		if _, err := exec.LookPath(o.CrossCheck); err != nil {
+			return fmt.Errorf("cross-check: %w", err)
+		}
+	}
+	if o.DereferenceManifest && o.MirrorDir != "" {
+		return errors.New("dereference-manifest: cannot be combined with --mirror")
+	}
+
+	return nil
+}
+
+// filtersCreated returns if the PAR2 sets are filtered by their creation time.
+func (o *Options) filtersCreated() bool {
+	return !o.CreatedBefore.Value.IsZero() || !o.CreatedAfter.Value.IsZero()
+}
+
 type JobMeta struct {
 	*schema.JobMeta
 }
@@ -49,13 +179,33 @@ func NewJobMeta(meta *schema.JobMeta) *JobMeta {
 }
 
 type Job struct {
-	workingDir   string
-	par2Name     string
-	par2Path     string
-	par2Args     []string
-	manifestName string
-	manifestPath string
-	lockPath     string
+	rootDir             string
+	workingDir          string
+	basePath            string
+	par2Name            string
+	par2Path            string
+	par2Args            []string
+	par2Verbosity       []string
+	par2ExitCodes       flags.Par2ExitCodes
+	manifestName        string
+	manifestPath        string
+	lockPath            string
+	stampPath           string
+	mirrorDir           string
+	mirrorBasePath      string
+	noManifestUpdate    bool
+	strictPar2          bool
+	dropCaches          bool
+	retrySingleThreaded bool
+	fileStatus          bool
+	findRelocated       bool
+	relocate            bool
+	ignoreNames         util.IgnoreNames
+	maxDepth            flags.MaxDepth
+	verifyPasses        int
+	par2Location        string
+	classifier          string
+	crossCheck          string
 
 	isBundle bool
 	manifest *schema.Manifest
@@ -65,14 +215,32 @@ func NewJob(par2Path string, opts Options, mf *schema.Manifest, isBundle bool) *
 	vj := &Job{}
 
 	vj.workingDir = filepath.Dir(par2Path)
+	vj.basePath = vj.workingDir
 	vj.par2Name = filepath.Base(par2Path)
 	vj.par2Path = par2Path
 	vj.par2Args = slices.Clone(opts.Par2Args)
+	vj.par2Verbosity = util.Par2VerbosityArgs(opts.Par2Quiet, opts.Par2Verbose)
+	vj.par2ExitCodes = opts.Par2ExitCodes
+	vj.noManifestUpdate = opts.NoManifestUpdate
+	vj.strictPar2 = opts.StrictPar2
+	vj.dropCaches = opts.DropCaches
+	vj.retrySingleThreaded = opts.RetrySingleThreaded
+	vj.fileStatus = opts.FileStatus
+	vj.findRelocated = opts.FindRelocated || opts.Relocate
+	vj.relocate = opts.Relocate
+	vj.ignoreNames = opts.IgnoreNames
+	vj.maxDepth = opts.MaxDepth
+	vj.verifyPasses = opts.VerifyPasses
+	vj.classifier = opts.Classifier
+	vj.crossCheck = opts.CrossCheck
+	if opts.RefreshStamp {
+		vj.stampPath = filepath.Join(vj.workingDir, opts.StampFile)
+	}
 
 	if !isBundle {
-		vj.manifestName = vj.par2Name + schema.ManifestExtension
-		vj.manifestPath = vj.par2Path + schema.ManifestExtension
-		vj.lockPath = vj.par2Path + schema.LockExtension
+		vj.manifestName = opts.SidecarNames.ManifestName(vj.par2Name)
+		vj.manifestPath = opts.SidecarNames.ManifestPath(vj.par2Path)
+		vj.lockPath = opts.SidecarNames.LockPath(vj.par2Path)
 	} else {
 		vj.manifestName = vj.par2Name
 		vj.manifestPath = vj.par2Path
@@ -85,22 +253,45 @@ func NewJob(par2Path string, opts Options, mf *schema.Manifest, isBundle bool) *
 	return vj
 }
 
+// SetBasePath sets the directory that par2 resolves the protected files
+// against, in place of the directory of the PAR2 set.
+func (job *Job) SetBasePath(dir string) {
+	job.basePath = dir
+}
+
+// Manifest returns the manifest of the job, holding the outcome of its
+// verification (also in report-only mode, where it is not written).
+func (job *Job) Manifest() *schema.Manifest {
+	return job.manifest
+}
+
 type Service struct {
 	fsys afero.Fs
 
 	log     *logging.Logger
 	runner  schema.CommandRunner
 	walker  schema.FilesystemWalker
+	dropper schema.CacheDropper
 	bundler schema.BundleHandler
+	par2er  schema.Par2Handler
 	cacher  schema.CacheHandler
+
+	// timings are the phases of the current run, nil outside of a run.
+	timings *util.Timings
+
+	// filtered are the candidates dropped in the current run, nil outside of a run.
+	filtered *util.Filtered
 }
 
 func NewService(fsys afero.Fs, log *logging.Logger, runner schema.CommandRunner, bundler schema.BundleHandler, cacher schema.CacheHandler) *Service {
 	var walker schema.FilesystemWalker
+	var dropper schema.CacheDropper
 	if _, ok := fsys.(*afero.OsFs); ok {
 		walker = util.OSWalker{}
+		dropper = util.OSCacheDropper{}
 	} else {
 		walker = util.AferoWalker{Fs: fsys}
+		dropper = util.AferoCacheDropper{}
 	}
 
 	return &Service{
@@ -108,7 +299,9 @@ func NewService(fsys afero.Fs, log *logging.Logger, runner schema.CommandRunner,
 		log:     log.With("op", "verify"),
 		runner:  runner,
 		walker:  walker,
+		dropper: dropper,
 		bundler: bundler,
+		par2er:  &util.Par2Handler{},
 		cacher:  cacher,
 	}
 }
@@ -145,10 +338,72 @@ func (prog *Service) Verify(ctx context.Context, rootDirs []string, opts Options
 	results := util.NewResultTracker()
 	logger := prog.verificationLogger(ctx, nil, nil)
 
+	prog.timings = util.NewTimings()
+	results.Timings = prog.timings
+	prog.filtered = util.NewFiltered()
+	results.Filtered = prog.filtered
+
+	if util.IsPaused(prog.fsys, opts.PauseFile) {
+		logger.Info("Paused by sentinel file (nothing done; will check again next run)", "pauseFile", opts.PauseFile)
+
+		return results, nil
+	}
+
+	mirrorRoot, err := resolveMirrorRoot(opts.MirrorDir)
+	if err != nil {
+		return results, fmt.Errorf("failed to resolve mirror: %w", err)
+	}
+
+	if opts.NoManifestUpdate {
+		logger.Info("Running in report-only mode (par2cron manifests will not be updated)")
+	}
+	if opts.Quick {
+		logger.Info("Running in quick mode (checking PAR2 indexes and file sizes only, not running par2)")
+	}
+	var changedSince time.Time
+	if opts.ChangedSince != "" {
+		changedSince, err = prog.referenceTime(opts.ChangedSince)
+		if err != nil {
+			return results, err
+		}
+		logger.Info("Verifying only PAR2 sets modified since the reference file",
+			"changedSince", opts.ChangedSince, "modTime", changedSince.Format(time.RFC3339))
+	}
+	if opts.DropCaches && !util.CacheDropSupported {
+		logger.Warn("Dropping page caches is not supported on this platform (ignoring --drop-caches)")
+		opts.DropCaches = false
+	}
+
+	if opts.MinRunInterval.Value > 0 {
+		rootDirs = prog.filterRecentRoots(ctx, rootDirs, opts.MinRunInterval.Value)
+		if len(rootDirs) == 0 {
+			return results, nil
+		}
+	}
+
 	metas := []*JobMeta{}
+	caches := make(map[string]schema.Cache, len(rootDirs))
 	for _, rootDir := range rootDirs {
 		cache := prog.openCache(ctx, rootDir, opts)
 
+		// An empty root is only missing its data (such as a volume not being
+		// mounted) if PAR2 sets below it are known from an earlier run (cache).
+		if err := util.CheckDataRoot(prog.fsys, rootDir, cache.Len() > 0); err != nil {
+			logger.Error("Failed to access the protected data (skipping; unmounted volume?)", "path", rootDir, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", rootDir, err))
+
+			continue
+		}
+
+		// The queued paths are only known after opening all caches, which
+		// are then not pruned (being only partially walked for the queue).
+		if opts.Queue != nil {
+			caches[rootDir] = cache
+			defer prog.saveCache(ctx, cache, opts, rootDir)
+
+			continue
+		}
+
 		logger.Info("Scanning filesystem for jobs...",
 			"walker", prog.walker.Name(), "path", rootDir, "cached", cache.Len())
 
@@ -167,10 +422,52 @@ func (prog *Service) Verify(ctx context.Context, rootDirs []string, opts Options
 		metas = append(metas, ms...)
 	}
 
-	metas = filterByAge(metas, opts.MinAge.Value)
-	sortJobs(metas)
-	prog.considerBacklog(metas, opts)
-	metas = filterByDuration(metas, opts.MaxDuration.Value)
+	if opts.Queue != nil {
+		logger.Info("Reading queued paths for jobs (--from-stdin)...")
+
+		ms, err := prog.EnumerateQueue(ctx, opts.Queue, caches, opts)
+		if err != nil {
+			if !errors.Is(err, schema.ErrNonFatal) {
+				return results, fmt.Errorf("failed to enumerate queued jobs: %w", err)
+			}
+
+			errs = append(errs, fmt.Errorf("failed to enumerate some queued jobs: %w", err))
+		}
+
+		metas = append(metas, ms...)
+	}
+
+	cp := prog.openCheckpoint(ctx, rootDirs, opts, time.Now())
+
+	// Quick verifications are cheap and do not count as verifications,
+	// so all sets are checked regardless of their last verification.
+	// Sets modified since the reference file are so regardless as well.
+	switch {
+	case opts.ChangedSince != "":
+		metas = prog.filterChangedSince(ctx, metas, changedSince, opts)
+	case !opts.Quick:
+		due := filterByAge(metas, opts.MinAge.Value, opts.Force)
+		prog.filtered.AddN(schema.ReasonNotDue, len(metas)-len(due))
+		metas = due
+	}
+	if opts.Order.Value == schema.VerifyOrderRandom {
+		shuffleJobs(metas, cp.seed(time.Now(), rootDirs))
+	} else {
+		sortJobs(metas, opts.Order.Value)
+	}
+	if pending := cp.pending(metas); len(pending) < len(metas) {
+		logger.Info("Skipping jobs already processed within the pass (--resume)",
+			"doneJobs", len(metas)-len(pending), "pendingJobs", len(pending))
+		prog.filtered.AddN(schema.ReasonCheckpointed, len(metas)-len(pending))
+		metas = pending
+	}
+	passJobs := len(metas)
+	// The estimated durations (and backlog) are of full verifications, not of quick ones.
+	if !opts.Quick {
+		prog.considerBacklog(metas, opts)
+		// With concurrent jobs (--jobs), the estimated durations add up to that many times the budget.
+		metas = filterByDuration(metas, opts.MaxDuration.Value*time.Duration(max(opts.Jobs, 1)))
+	}
 
 	if len(metas) > 0 {
 		logger.Info(fmt.Sprintf("Starting to process %d jobs...", len(metas)),
@@ -178,11 +475,20 @@ func (prog *Service) Verify(ctx context.Context, rootDirs []string, opts Options
 			"maxDuration", opts.MaxDuration.Value.String())
 		results.Selected = len(metas)
 	} else {
-		logger.Info("Nothing to do (will check again next run)",
-			"minAge", opts.MinAge.Value.String())
+		logger.Info(prog.filtered.NothingToDo(),
+			"minAge", opts.MinAge.Value.String(), prog.filtered.Attr())
 	}
 
-	prog.considerDurations(metas, opts)
+	if !opts.Quick {
+		prog.considerDurations(metas, opts)
+	}
+
+	// Lines of healthy or skipped jobs are not actionable with --only-needing-repair,
+	// so they are emitted at debug level (leaving only corrupted or failed jobs).
+	jobInfoLevel, jobSkipLevel := slog.LevelInfo, slog.LevelWarn
+	if opts.OnlyNeedingRepair {
+		jobInfoLevel, jobSkipLevel = slog.LevelDebug, slog.LevelDebug
+	}
 
 	var deadlineCtx context.Context //nolint:contextcheck
 	var deadlineCancel context.CancelFunc
@@ -191,65 +497,120 @@ func (prog *Service) Verify(ctx context.Context, rootDirs []string, opts Options
 		defer deadlineCancel()
 	}
 
-	for i, meta := range metas {
-		if err := ctx.Err(); err != nil {
-			return results, fmt.Errorf("context error: %w", err)
-		}
+	// The results and errors are shared between the concurrent jobs (--jobs).
+	var mu sync.Mutex
+	record := func(counter *int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
 
-		if i > 0 && deadlineCtx != nil {
-			if err := deadlineCtx.Err(); errors.Is(err, context.DeadlineExceeded) {
-				logger := prog.verificationLogger(ctx, nil, nil)
-				logger.Warn("Exceeded the --duration budget (will continue next run)",
-					"unprocessedJobs", len(metas)-i, "totalJobs", len(metas),
-					"maxDuration", opts.MaxDuration.Value.String())
-
-				break
-			}
+		if counter != nil {
+			*counter++
 		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
 
-		pos := fmt.Sprintf("%d/%d", i+1, len(metas))
-		prio := meta.queuePriority()
+	recordRemaining := func(meta *JobMeta) {
+		mu.Lock()
+		defer mu.Unlock()
 
-		ctx := context.WithValue(ctx, schema.PosKey, pos)
-		ctx = context.WithValue(ctx, schema.PrioKey, prio)
+		results.AddRemaining(meta.EstDuration())
+	}
+
+	process := func(ctx context.Context, meta *JobMeta) {
+		logger := prog.verificationLogger(ctx, meta, nil)
 
 		var job *Job
 		if !meta.HasManifest {
 			job = NewJob(meta.Par2Path, opts, nil, meta.IsBundle)
 		} else {
-			mf, err := prog.loadManifest(ctx, meta)
+			mf, err := prog.loadManifest(ctx, meta, opts)
 			if err != nil {
 				if errors.Is(err, schema.ErrFileIsLocked) {
-					logger.Warn("Manifest unavailable (will retry next run)", "error", err)
-					results.Skipped++
+					logger.Log(ctx, jobSkipLevel, "Manifest unavailable (will retry next run)", "error", err)
+					record(&results.Skipped, nil)
 
-					continue
+					return
 				}
 
 				logger.Error("Manifest failure (will retry next run)", "error", err)
-				errs = append(errs, fmt.Errorf("%s: failed to load manifest: %w", meta.Par2Path, err))
-				results.Error++
+				record(&results.Error, fmt.Errorf("%s: failed to load manifest: %w", meta.Par2Path, err))
 
-				continue
+				return
 			}
 			job = NewJob(meta.Par2Path, opts, mf, meta.IsBundle)
 		}
+		if meta.ManifestPath != "" {
+			job.Dereference(meta.ManifestPath, opts.SidecarNames)
+		}
+
+		job.basePath = util.Par2BasePath(opts.BasePath.Value, rootDirs, job.workingDir)
+		if rootDir, ok := util.ContainingRoot(rootDirs, job.workingDir); ok {
+			job.rootDir = rootDir
+		}
+		if mirrorRoot != "" {
+			job.mirrorDir = mirrorWorkingDir(rootDirs, mirrorRoot, job.workingDir)
+			job.mirrorBasePath = mirrorWorkingDir(rootDirs, mirrorRoot, job.basePath)
+		}
 
 		logger = prog.verificationLogger(ctx, job, nil)
-		logger.Info("Job started",
-			"estDuration", meta.lastDurationStr(),
+		logger.Log(ctx, jobInfoLevel, "Job started",
+			"estDuration", meta.estDurationStr(),
 			"lastVerified", meta.lastVerifiedStr(),
 		)
 
+		if opts.Quick {
+			err := prog.RunQuickVerify(ctx, job)
+			switch {
+			case err == nil:
+				logger.Log(ctx, jobInfoLevel, "Job completed quick verification with success",
+					"runDuration", job.manifest.QuickVerification.Duration.String())
+				record(&results.Success, nil)
+			case errors.Is(err, errQuickProblems):
+				logger.Error("Job completed quick verification with problems (will fully verify next run)",
+					"problems", job.manifest.QuickVerification.Problems)
+				record(&results.Error, fmt.Errorf("%s: %w", job.par2Path, err))
+			case errors.Is(err, schema.ErrFileIsLocked):
+				logger.Log(ctx, jobSkipLevel, "Job unavailable (will retry next run)", "error", err)
+				record(&results.Skipped, nil)
+			case ctx.Err() != nil:
+				logger.Warn("Job interrupted (will continue next run)", "error", err)
+				recordRemaining(meta)
+			default:
+				logger.Error("Job failure (will retry next run)", "error", err)
+				record(&results.Error, fmt.Errorf("%s: %w", job.par2Path, err))
+			}
+
+			if err == nil || errors.Is(err, errQuickProblems) {
+				if !job.noManifestUpdate {
+					*meta.JobMeta = *(schema.NewJobMeta(job.par2Path, job.manifest, job.isBundle))
+				}
+				if err := cp.markDone(job.par2Path); err != nil {
+					logger.Warn("Failed to write checkpoint (job may be repeated on --resume)", "error", err)
+				}
+			}
+
+			return
+		}
+
 		if err := prog.RunVerify(ctx, job, false); err == nil {
-			if job.manifest.Verification.ExitCode == schema.Par2ExitCodeSuccess {
-				logger.Info("Job completed with success",
+			if job.crossCheckFailed() {
+				cc := job.manifest.CrossCheck
+				logger.Error("Job completed with cross-check failed (clean by par2, but needs attention)",
+					"runDuration", job.manifest.Verification.Duration.String(),
+					"crossCheck", cc.Command,
+					"crossCheckExitCode", cc.ExitCode,
+				)
+				record(&results.Error, fmt.Errorf("%s: %w (exit code %d)", job.par2Path, errCrossCheckFailed, cc.ExitCode))
+			} else if !job.manifest.Verification.RepairNeeded {
+				logger.Log(ctx, jobInfoLevel, "Job completed with success",
 					"runDuration", job.manifest.Verification.Duration.String(),
 					"exitCode", job.manifest.Verification.ExitCode,
 					"repairNeeded", job.manifest.Verification.RepairNeeded,
 					"repairPossible", job.manifest.Verification.RepairPossible,
 				)
-				results.Success++
+				record(&results.Success, nil)
 			} else {
 				logger.Error("Job completed with corruption detected",
 					"runDuration", job.manifest.Verification.Duration.String(),
@@ -259,24 +620,97 @@ func (prog *Service) Verify(ctx context.Context, rootDirs []string, opts Options
 				)
 
 				if job.manifest.Verification.RepairPossible {
-					errs = append(errs, fmt.Errorf("%s: %w", job.par2Path, schema.ErrExitRepairable))
+					record(&results.Error, fmt.Errorf("%s: %w", job.par2Path, schema.ErrExitRepairable))
 				} else {
-					errs = append(errs, fmt.Errorf("%s: %w", job.par2Path, schema.ErrExitUnrepairable))
+					record(&results.Error, fmt.Errorf("%s: %w", job.par2Path, schema.ErrExitUnrepairable))
 				}
-				results.Error++
+			}
+
+			if mv := job.manifest.MirrorVerification; job.mirrorDir != "" && mv != nil && mv.Diverged {
+				logger.Error("Mirror verification diverged from primary verification",
+					"mirror", job.mirrorDir,
+					"exitCode", job.manifest.Verification.ExitCode,
+					"mirrorExitCode", mv.ExitCode,
+				)
+				record(nil, fmt.Errorf("%s: mirror diverged (exit code %d, primary %d)",
+					job.par2Path, mv.ExitCode, job.manifest.Verification.ExitCode))
 			}
 
 			// Write back to cache only on success, otherwise verification time or other
 			// not finalized (pre-verificational) changes will taint the cached metadata.
 			// Keeping this consistent with only paths that call to util.WriteManifest().
-			*meta.JobMeta = *(schema.NewJobMeta(job.par2Path, job.manifest, job.isBundle))
+			// The set ID is kept unless the PAR2 index was replaced since it was parsed.
+			if !job.noManifestUpdate {
+				prev := *meta.JobMeta
+				*meta.JobMeta = *(schema.NewJobMeta(job.par2Path, job.manifest, job.isBundle))
+				if prev.IndexSHA256 == meta.IndexSHA256 {
+					meta.SetID = prev.SetID
+				}
+			}
+
+			if err := cp.markDone(job.par2Path); err != nil {
+				logger.Warn("Failed to write checkpoint (job may be repeated on --resume)", "error", err)
+			}
 		} else if errors.Is(err, schema.ErrFileIsLocked) {
-			logger.Warn("Job unavailable (will retry next run)", "error", err)
-			results.Skipped++
+			logger.Log(ctx, jobSkipLevel, "Job unavailable (will retry next run)", "error", err)
+			record(&results.Skipped, nil)
+		} else if ctx.Err() != nil {
+			logger.Warn("Job interrupted (will prioritize next run)", "error", err)
+			recordRemaining(meta)
+
+			if job.manifest != nil && job.manifest.Interruption != nil {
+				*meta.JobMeta = *(schema.NewJobMeta(job.par2Path, job.manifest, job.isBundle))
+			}
 		} else {
 			logger.Error("Job failure (will retry next run)", "error", err)
-			errs = append(errs, fmt.Errorf("%s: %w", job.par2Path, err))
-			results.Error++
+			record(&results.Error, fmt.Errorf("%s: %w", job.par2Path, err))
+		}
+	}
+
+	progress := prog.log.NewProgress("verifying", len(metas))
+	defer progress.Close()
+
+	var wg sync.WaitGroup
+	var started int
+	sem := util.NewSemaphore(opts.Jobs)
+
+	for i, meta := range metas {
+		// A slot is awaited first, so that the checks below also account
+		// for all the concurrent jobs (--jobs) having finished meanwhile.
+		if err := sem.Acquire(ctx); err != nil {
+			break
+		}
+		if !prog.shouldStartJob(ctx, deadlineCtx, i, len(metas), opts, func() int {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return results.Error
+		}) {
+			sem.Release()
+
+			break
+		}
+
+		pos := fmt.Sprintf("%d/%d", i+1, len(metas))
+		prio := meta.queuePriority()
+
+		ctx := context.WithValue(ctx, schema.PosKey, pos)
+		ctx = context.WithValue(ctx, schema.PrioKey, prio)
+
+		started++
+		wg.Go(func() {
+			defer sem.Release()
+			defer progress.Finished()
+
+			progress.Started(meta.Par2Path)
+			process(ctx, meta)
+		})
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		for _, meta := range metas[started:] {
+			results.AddRemaining(meta.EstDuration())
 		}
 	}
 
@@ -284,6 +718,19 @@ func (prog *Service) Verify(ctx context.Context, rootDirs []string, opts Options
 		return results, fmt.Errorf("context error: %w", err)
 	}
 
+	// The checkpoint is only cleared once all jobs of the pass were started,
+	// and not when the run ended early (--duration, --limit or --max-errors).
+	if started == passJobs {
+		if err := cp.clear(); err != nil {
+			logger.Warn("Failed to remove checkpoint of completed pass", "error", err)
+		}
+	}
+
+	// A report-only (or quick) run must not hold off the next managed verification run.
+	if opts.MinRunInterval.Value > 0 && !opts.NoManifestUpdate && !opts.Quick {
+		prog.markRootsRun(ctx, rootDirs)
+	}
+
 	if len(errs) > 0 {
 		return results, fmt.Errorf("%w: %w",
 			schema.ErrExitPartialFailure, errors.Join(errs...))
@@ -292,60 +739,180 @@ func (prog *Service) Verify(ctx context.Context, rootDirs []string, opts Options
 	return results, nil
 }
 
+// shouldStartJob returns if the i-th of n jobs is to be started, or if the
+// run ends before it (interruption, --duration, --limit or --max-errors).
+func (prog *Service) shouldStartJob(ctx context.Context, deadlineCtx context.Context, i int, n int, opts Options, failed func() int) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if i > 0 && deadlineCtx != nil {
+		if err := deadlineCtx.Err(); errors.Is(err, context.DeadlineExceeded) {
+			logger := prog.verificationLogger(ctx, nil, nil)
+			logger.Warn("Exceeded the --duration budget (will continue next run)",
+				"unprocessedJobs", n-i, "totalJobs", n,
+				"maxDuration", opts.MaxDuration.Value.String())
+
+			return false
+		}
+	}
+
+	if opts.Limit > 0 && i >= opts.Limit {
+		logger := prog.verificationLogger(ctx, nil, nil)
+		logger.Warn("Reached the --limit of jobs per run (will continue next run)",
+			"unprocessedJobs", n-i, "totalJobs", n,
+			"limit", opts.Limit)
+
+		return false
+	}
+
+	if opts.MaxErrors > 0 && failed() >= opts.MaxErrors {
+		logger := prog.verificationLogger(ctx, nil, nil)
+		logger.Error("Reached the --max-errors threshold of failed jobs (aborting the run)",
+			"unprocessedJobs", n-i, "totalJobs", n,
+			"maxErrors", opts.MaxErrors)
+
+		return false
+	}
+
+	return true
+}
+
+// Enumerate walks the root directory for the jobs to verify. The manifests
+// not yet in the cache are read by up to opts.IOConcurrency goroutines at a
+// time, while the jobs are still returned in the order they were walked.
+// The ignore files honored for PAR2 sets are recorded into opts.IgnoreHits.
 func (prog *Service) Enumerate(ctx context.Context, rootDir string, opts Options, cache schema.Cache) ([]*JobMeta, error) {
-	metas := []*JobMeta{}
-	checker := util.NewIgnoreChecker(prog.fsys, rootDir)
+	type entry struct {
+		par2path string
+		cached   *schema.JobMeta
+		meta     *JobMeta
+		err      error
+		uncached bool
+	}
+
+	entries := []*entry{}
+	checker := util.NewIgnoreChecker(prog.fsys, rootDir, opts.IgnoreNames)
+
+	endWalk := prog.timings.StartWalk()
+	defer endWalk()
+
+	var wg sync.WaitGroup
+	sem := util.NewSemaphore(opts.IOConcurrency)
 
-	var partialErrors int
 	err := prog.walker.WalkDir(rootDir, func(par2path string, d fs.DirEntry, err error) error {
 		if err := ctx.Err(); err != nil {
 			return fmt.Errorf("context error: %w", err)
 		}
 		if err != nil {
 			logger := prog.verificationLogger(ctx, nil, par2path)
-			logger.Warn("A path was skipped due to FS error (will retry next run)", "error", err)
+			logger.Warn("A path was skipped due to FS error (will retry next run)", "reason", schema.ReasonFSError, "error", err)
 
 			return nil
 		}
 
+		if d.IsDir() && util.ExceedsMaxDepth(rootDir, par2path, opts.MaxDepth) {
+			logger := prog.verificationLogger(ctx, nil, par2path)
+			logger.Debug("A directory was skipped due to the maximum depth", "reason", schema.ReasonMaxDepth)
+			prog.filtered.Add(schema.ReasonMaxDepth)
+
+			return fs.SkipDir
+		}
+		if !d.IsDir() && opts.SidecarNames.IsManifest(d.Name()) {
+			prog.considerOrphanedManifest(ctx, par2path, checker, opts)
+			if !opts.DereferenceManifest || checker.ShouldIgnore(par2path) {
+				return nil
+			}
+
+			// A manifest of a PAR2 set stored elsewhere is never cached,
+			// as the cache is keyed by the PAR2 sets found in the walk.
+			e := &entry{par2path: par2path, uncached: true}
+			entries = append(entries, e)
+
+			if err := sem.Acquire(ctx); err != nil {
+				return err
+			}
+			wg.Go(func() {
+				defer sem.Release()
+				e.meta, e.err = prog.processDereferencedManifest(ctx, par2path, opts)
+			})
+
+			return nil
+		}
 		if d.IsDir() || !util.IsPar2Index(d.Name()) {
 			return nil
 		} // --- End of Hot Path ---
-		if checker.ShouldIgnore(par2path) {
+		if ignorePath, all, ignored := checker.IgnoredBy(par2path); ignored {
+			opts.IgnoreHits.Add(ignorePath, all, par2path)
+
 			logger := prog.verificationLogger(ctx, nil, par2path)
-			logger.Debug("A path was skipped due to a present ignore-file")
+			logger.Debug("A path was skipped due to a present ignore-file", "reason", schema.ReasonIgnoreFile)
+			prog.filtered.Add(schema.ReasonIgnoreFile)
 
 			return nil
 		}
 
+		e := &entry{par2path: par2path}
+		entries = append(entries, e)
+
 		if meta, cached := cache.Get(par2path); cached {
-			if prog.isVerificationCandidate(ctx, meta, opts) {
-				metas = append(metas, NewJobMeta(meta))
-			}
-		} else {
-			meta, err := prog.processManifest(ctx, par2path, opts)
-			if err != nil {
-				if !errors.Is(err, schema.ErrNonFatal) && !errors.Is(err, schema.ErrSilentSkip) {
-					return fmt.Errorf("failed to process manifest: %w", err)
-				}
-				if errors.Is(err, schema.ErrNonFatal) {
-					partialErrors++
-				}
+			e.cached = meta
 
-				return nil
-			}
-			cache.Set(par2path, meta.JobMeta)
+			return nil
+		}
 
-			if prog.isVerificationCandidate(ctx, meta.JobMeta, opts) {
-				metas = append(metas, meta)
-			}
+		if err := sem.Acquire(ctx); err != nil {
+			return err
 		}
+		wg.Go(func() {
+			defer sem.Release()
+			e.meta, e.err = prog.processManifest(ctx, par2path, opts)
+		})
 
 		return nil
 	})
+	wg.Wait()
 	if err != nil {
 		return nil, fmt.Errorf("failed to walk FS: %w", err)
 	}
+
+	var partialErrors int
+	metas := []*JobMeta{}
+	for _, e := range entries {
+		if e.cached != nil {
+			prog.considerFingerprint(ctx, e.cached, opts)
+			if prog.isVerificationCandidate(ctx, e.cached, opts) {
+				metas = append(metas, NewJobMeta(e.cached))
+			}
+
+			continue
+		}
+
+		if e.err != nil {
+			if !errors.Is(e.err, schema.ErrNonFatal) && !errors.Is(e.err, schema.ErrSilentSkip) {
+				return nil, fmt.Errorf("failed to walk FS: failed to process manifest: %w", e.err)
+			}
+			if errors.Is(e.err, schema.ErrNonFatal) {
+				partialErrors++
+			}
+
+			continue
+		}
+		if !e.uncached {
+			cache.Set(e.par2path, e.meta.JobMeta)
+		}
+		prog.considerFingerprint(ctx, e.meta.JobMeta, opts)
+
+		if prog.isVerificationCandidate(ctx, e.meta.JobMeta, opts) {
+			metas = append(metas, e.meta)
+		}
+	}
+
+	metas, err = prog.groupSplitSets(ctx, metas)
+	if err != nil {
+		return nil, err
+	}
+
 	if partialErrors > 0 {
 		return metas, fmt.Errorf("%w: %d manifests failed to read", schema.ErrNonFatal, partialErrors)
 	}
@@ -353,28 +920,151 @@ func (prog *Service) Enumerate(ctx context.Context, rootDir string, opts Options
 	return metas, nil
 }
 
+// considerOrphanedManifest warns about a par2cron manifest without its PAR2
+// set, which is never a job, and removes it if requested (--clean-orphans),
+// unless in report-only mode (--no-manifest-update).
+func (prog *Service) considerOrphanedManifest(ctx context.Context, manifestPath string, checker *util.IgnoreChecker, opts Options) {
+	if checker.ShouldIgnore(manifestPath) {
+		return
+	}
+
+	logger := prog.verificationLogger(ctx, nil, manifestPath)
+
+	if orphaned, err := util.IsOrphanedManifest(prog.fsys, manifestPath, opts.SidecarNames); err != nil || !orphaned {
+		if err != nil {
+			logger.Debug("Failed to check manifest for a PAR2 set (will retry next run)", "error", err)
+		}
+
+		return
+	}
+
+	if !opts.CleanOrphans || opts.NoManifestUpdate {
+		logger.Warn("Orphaned manifest without PAR2 set (remove it or use --clean-orphans)",
+			"reason", schema.ReasonOrphanedManifest)
+
+		return
+	}
+
+	removed, err := util.RemoveOrphanedManifest(prog.fsys, manifestPath, opts.SidecarNames)
+	if err != nil {
+		if errors.Is(err, schema.ErrFileIsLocked) {
+			logger.Debug("Orphaned manifest is locked by another instance (will retry next run)", "reason", schema.ReasonLocked)
+		} else {
+			logger.Warn("Failed to remove orphaned manifest (will retry next run)",
+				"reason", schema.ReasonOrphanedManifest, "error", err)
+		}
+
+		return
+	}
+	if removed {
+		logger.Info("Removed orphaned manifest without PAR2 set (--clean-orphans)",
+			"reason", schema.ReasonOrphanedManifest)
+	}
+}
+
+// considerManifestName checks the name stored in a manifest against the PAR2
+// set it was found for (--name-mismatch), such as after renaming the PAR2 set
+// on disk. On a mismatch, the manifest is either corrected (fix), unless in
+// report-only mode (--no-manifest-update), or the job is skipped (skip).
+func (prog *Service) considerManifestName(ctx context.Context, par2path string, mf *schema.Manifest, isBundle bool, opts Options) error {
+	if opts.NameMismatch.Value == "" {
+		return nil
+	}
+
+	name, mismatch := util.ManifestNameMismatch(par2path, mf, isBundle)
+	if !mismatch {
+		return nil
+	}
+
+	logger := prog.verificationLogger(ctx, nil, par2path)
+
+	if opts.NameMismatch.Value == schema.NameMismatchSkip {
+		logger.Warn("Manifest name does not match its PAR2 set (skipping; --name-mismatch skip)",
+			"reason", schema.ReasonNameMismatch, "name", mf.Name, "expected", name)
+		prog.filtered.Add(schema.ReasonNameMismatch)
+
+		return schema.ErrSilentSkip
+	}
+
+	if opts.NoManifestUpdate {
+		logger.Warn("Manifest name does not match its PAR2 set (not correcting; --no-manifest-update)",
+			"reason", schema.ReasonNameMismatch, "name", mf.Name, "expected", name)
+
+		return nil
+	}
+
+	if err := util.CorrectManifestName(ctx, prog.fsys, prog.bundler, par2path, name, isBundle, opts.SidecarNames); err != nil {
+		logger.Warn("Failed to correct manifest name to match its PAR2 set (will retry next run)",
+			"reason", schema.ReasonNameMismatch, "name", mf.Name, "expected", name, "error", err)
+
+		return nil
+	}
+
+	logger.Info("Corrected manifest name to match its PAR2 set (--name-mismatch fix)",
+		"reason", schema.ReasonNameMismatch, "before", mf.Name, "after", name)
+	mf.Name = name
+
+	return nil
+}
+
 func (prog *Service) isVerificationCandidate(ctx context.Context, meta *schema.JobMeta, opts Options) bool {
 	if opts.SkipNotCreated && !meta.HasCreation {
 		logger := prog.verificationLogger(ctx, meta, nil)
-		logger.Debug("No creation manifest (skipping; --skip-not-created)")
+		logger.Debug("No creation manifest (skipping; --skip-not-created)", "reason", schema.ReasonSkipNotCreated)
+		prog.filtered.Add(schema.ReasonSkipNotCreated)
 
 		return false
 	}
 
+	if !schema.HasTags(meta.Tags, opts.Tags.Value) {
+		logger := prog.verificationLogger(ctx, meta, nil)
+		logger.Debug("Missing required tags (skipping; --tag)", "reason", schema.ReasonTagMismatch, "tags", meta.Tags)
+		prog.filtered.Add(schema.ReasonTagMismatch)
+
+		return false
+	}
+
+	if opts.filtersCreated() {
+		if !meta.HasCreation {
+			if opts.IncludeNotCreated {
+				return true
+			}
+
+			logger := prog.verificationLogger(ctx, meta, nil)
+			logger.Debug("No creation manifest (skipping; --created-before/--created-after)", "reason", schema.ReasonSkipNotCreated)
+			prog.filtered.Add(schema.ReasonSkipNotCreated)
+
+			return false
+		}
+
+		before, after := opts.CreatedBefore.Value, opts.CreatedAfter.Value
+		if (!before.IsZero() && !meta.CreateTime.Before(before)) || (!after.IsZero() && meta.CreateTime.Before(after)) {
+			logger := prog.verificationLogger(ctx, meta, nil)
+			logger.Debug("Created outside of the date range (skipping; --created-before/--created-after)",
+				"reason", schema.ReasonCreatedRange, "created", meta.CreateTime)
+			prog.filtered.Add(schema.ReasonCreatedRange)
+
+			return false
+		}
+	}
+
 	return true
 }
 
 func (prog *Service) processManifest(ctx context.Context, par2path string, opts Options) (*JobMeta, error) {
+	defer prog.timings.Since(util.PhaseParse, time.Now())
+
 	if util.IsPar2Bundle(par2path) {
 		return prog.processBundleManifest(ctx, par2path, opts)
 	}
 
-	manifestPath := par2path + schema.ManifestExtension
+	manifestPath := opts.SidecarNames.ManifestPath(par2path)
 
 	if _, err := util.LstatIfPossible(prog.fsys, manifestPath); err != nil {
 		if !opts.IncludeExternal {
 			logger := prog.verificationLogger(ctx, nil, manifestPath)
-			logger.Debug("No manifest found (skipping)")
+			logger.Debug("No manifest found (skipping)", "reason", schema.ReasonNoManifest)
+			prog.filtered.Add(schema.ReasonNoManifest)
 
 			return nil, schema.ErrSilentSkip
 		}
@@ -387,11 +1077,12 @@ func (prog *Service) processManifest(ctx context.Context, par2path string, opts
 		return meta, nil
 	}
 
-	unlock, err := util.AcquireLock(prog.fsys, par2path+schema.LockExtension, false)
+	unlock, err := util.AcquireLock(prog.fsys, opts.SidecarNames.LockPath(par2path), false)
 	if err != nil {
 		if errors.Is(err, schema.ErrFileIsLocked) {
 			logger := prog.verificationLogger(ctx, nil, manifestPath)
-			logger.Debug("Manifest is locked by another instance (will retry next run)")
+			logger.Debug("Manifest is locked by another instance (will retry next run)", "reason", schema.ReasonLocked)
+			prog.filtered.Add(schema.ReasonLocked)
 
 			return nil, schema.ErrSilentSkip
 		}
@@ -402,17 +1093,18 @@ func (prog *Service) processManifest(ctx context.Context, par2path string, opts
 	if err != nil {
 		unlock()
 		logger := prog.verificationLogger(ctx, nil, manifestPath)
-		logger.Error("Failed to read par2cron manifest (will retry next run)", "error", err)
+		logger.Error("Failed to read par2cron manifest (will retry next run)", "reason", schema.ReasonManifestRead, "error", err)
 
 		return nil, schema.ErrNonFatal
 	}
 	unlock()
 
-	mf := &schema.Manifest{}
-	if err := json.Unmarshal(data, mf); err != nil {
+	mf, err := util.UnmarshalManifest(data, opts.MaxManifestSize)
+	if err != nil {
 		if opts.SkipNotCreated {
 			logger := prog.verificationLogger(ctx, nil, manifestPath)
-			logger.Debug("No unmarshalable manifest (skipping; --skip-not-created)")
+			logger.Debug("No unmarshalable manifest (skipping; --skip-not-created)", "reason", schema.ReasonSkipNotCreated)
+			prog.filtered.Add(schema.ReasonSkipNotCreated)
 
 			return nil, schema.ErrSilentSkip
 		}
@@ -420,11 +1112,15 @@ func (prog *Service) processManifest(ctx context.Context, par2path string, opts
 		meta := NewJobMeta(schema.NewJobMeta(par2path, nil, false))
 
 		logger := prog.verificationLogger(ctx, meta, manifestPath)
-		logger.Warn("Failed to unmarshal par2cron manifest (resetting manifest)", "error", err)
+		logger.Warn("Failed to unmarshal par2cron manifest (resetting manifest)", "reason", util.ManifestInvalidReason(err), "error", err)
 
 		return meta, nil
 	}
 
+	if err := prog.considerManifestName(ctx, par2path, mf, false, opts); err != nil {
+		return nil, err
+	}
+
 	return NewJobMeta(schema.NewJobMeta(par2path, mf, false)), nil
 }
 
@@ -433,7 +1129,8 @@ func (prog *Service) processBundleManifest(ctx context.Context, bundlePath strin
 	if err != nil {
 		if errors.Is(err, schema.ErrFileIsLocked) {
 			logger := prog.verificationLogger(ctx, nil, bundlePath)
-			logger.Debug("Bundle is locked by another instance (will retry next run)")
+			logger.Debug("Bundle is locked by another instance (will retry next run)", "reason", schema.ReasonLocked)
+			prog.filtered.Add(schema.ReasonLocked)
 
 			return nil, schema.ErrSilentSkip
 		}
@@ -444,7 +1141,7 @@ func (prog *Service) processBundleManifest(ctx context.Context, bundlePath strin
 	if err != nil {
 		unlock()
 		logger := prog.verificationLogger(ctx, nil, bundlePath)
-		logger.Error("Failed to open bundle (will retry next run)", "error", err)
+		logger.Error("Failed to open bundle (will retry next run)", "reason", schema.ReasonBundleOpen, "error", err)
 
 		return nil, schema.ErrNonFatal
 	}
@@ -460,18 +1157,19 @@ func (prog *Service) processBundleManifest(ctx context.Context, bundlePath strin
 		meta := NewJobMeta(schema.NewJobMeta(bundlePath, nil, true))
 
 		logger := prog.verificationLogger(ctx, meta, bundlePath)
-		logger.Warn("Failed to read par2cron manifest (resetting manifest)", "error", err)
+		logger.Warn("Failed to read par2cron manifest (resetting manifest)", "reason", schema.ReasonManifestRead, "error", err)
 
 		return meta, nil
 	}
 	_ = bun.Close()
 	unlock()
 
-	mf := &schema.Manifest{}
-	if err := json.Unmarshal(by, mf); err != nil {
+	mf, err := util.UnmarshalManifest(by, opts.MaxManifestSize)
+	if err != nil {
 		if opts.SkipNotCreated {
 			logger := prog.verificationLogger(ctx, nil, bundlePath)
-			logger.Debug("No unmarshalable manifest (skipping; --skip-not-created)")
+			logger.Debug("No unmarshalable manifest (skipping; --skip-not-created)", "reason", schema.ReasonSkipNotCreated)
+			prog.filtered.Add(schema.ReasonSkipNotCreated)
 
 			return nil, schema.ErrSilentSkip
 		}
@@ -479,22 +1177,34 @@ func (prog *Service) processBundleManifest(ctx context.Context, bundlePath strin
 		meta := NewJobMeta(schema.NewJobMeta(bundlePath, nil, true))
 
 		logger := prog.verificationLogger(ctx, meta, bundlePath)
-		logger.Warn("Failed to unmarshal par2cron manifest (resetting manifest)", "error", err)
+		logger.Warn("Failed to unmarshal par2cron manifest (resetting manifest)", "reason", util.ManifestInvalidReason(err), "error", err)
 
 		return meta, nil
 	}
 
+	if err := prog.considerManifestName(ctx, bundlePath, mf, true, opts); err != nil {
+		return nil, err
+	}
+
 	return NewJobMeta(schema.NewJobMeta(bundlePath, mf, true)), nil
 }
 
-func (prog *Service) loadManifest(ctx context.Context, meta *JobMeta) (*schema.Manifest, error) {
+// LoadManifest loads the full manifest of a job (as returned from [Service.Enumerate]),
+// returning a nil manifest (without error) when it is missing or not unmarshalable.
+func (prog *Service) LoadManifest(ctx context.Context, meta *JobMeta, opts Options) (*schema.Manifest, error) {
+	return prog.loadManifest(ctx, meta, opts)
+}
+
+func (prog *Service) loadManifest(ctx context.Context, meta *JobMeta, opts Options) (*schema.Manifest, error) {
+	defer prog.timings.Since(util.PhaseParse, time.Now())
+
 	if meta.IsBundle {
-		return prog.loadBundleManifest(ctx, meta)
+		return prog.loadBundleManifest(ctx, meta, opts)
 	}
 
-	manifestPath := meta.Par2Path + schema.ManifestExtension
+	manifestPath, lockPath := opts.SidecarNames.SidecarPaths(meta.JobMeta)
 
-	unlock, err := util.AcquireLock(prog.fsys, meta.Par2Path+schema.LockExtension, false)
+	unlock, err := util.AcquireLock(prog.fsys, lockPath, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to lock: %w", err)
 	}
@@ -513,8 +1223,8 @@ func (prog *Service) loadManifest(ctx context.Context, meta *JobMeta) (*schema.M
 	}
 	unlock()
 
-	mf := &schema.Manifest{}
-	if err := json.Unmarshal(data, mf); err != nil {
+	mf, err := util.UnmarshalManifest(data, opts.MaxManifestSize)
+	if err != nil {
 		logger := prog.verificationLogger(ctx, meta, manifestPath)
 		logger.Warn("Failed to unmarshal par2cron manifest (resetting manifest)", "error", err)
 
@@ -524,7 +1234,7 @@ func (prog *Service) loadManifest(ctx context.Context, meta *JobMeta) (*schema.M
 	return mf, nil
 }
 
-func (prog *Service) loadBundleManifest(ctx context.Context, meta *JobMeta) (*schema.Manifest, error) {
+func (prog *Service) loadBundleManifest(ctx context.Context, meta *JobMeta, opts Options) (*schema.Manifest, error) {
 	bundlePath := meta.Par2Path
 
 	unlock, err := util.AcquireLock(prog.fsys, bundlePath, false)
@@ -554,8 +1264,8 @@ func (prog *Service) loadBundleManifest(ctx context.Context, meta *JobMeta) (*sc
 	_ = bun.Close()
 	unlock()
 
-	mf := &schema.Manifest{}
-	if err := json.Unmarshal(by, mf); err != nil {
+	mf, err := util.UnmarshalManifest(by, opts.MaxManifestSize)
+	if err != nil {
 		logger := prog.verificationLogger(ctx, meta, bundlePath)
 		logger.Warn("Failed to unmarshal par2cron manifest (resetting manifest)", "error", err)
 
@@ -574,6 +1284,9 @@ func (prog *Service) RunVerify(ctx context.Context, job *Job, isPreLocked bool)
 		defer unlock()
 	}
 
+	// The PAR2 is hashed against the manifest before running par2, so that a
+	// changed PAR2 is caught (and with --strict-par2 not verified at all),
+	// without spending the time of running par2 over possibly bogus parity.
 	var sha256hash string
 	if !job.isBundle {
 		hash, err := util.HashFile(prog.fsys, job.par2Path)
@@ -581,10 +1294,23 @@ func (prog *Service) RunVerify(ctx context.Context, job *Job, isPreLocked bool)
 			logger := prog.verificationLogger(ctx, job, job.manifestPath)
 			logger.Error("Failed to hash PAR2 against par2cron manifest", "error", err)
 
-			return fmt.Errorf("failed to hash par2: %w", err)
+			err = fmt.Errorf("failed to hash par2: %w", err)
+			prog.recordLastError(ctx, job, err)
+
+			return err
 		}
 		sha256hash = hash
 
+		if job.manifest != nil && sha256hash != job.manifest.SHA256 && job.strictPar2 {
+			logger := prog.verificationLogger(ctx, job, job.manifestPath)
+			logger.Error("PAR2 has changed (manifest out of date; not verifying with --strict-par2)",
+				"currentHash", sha256hash,
+				"manifestHash", job.manifest.SHA256,
+			)
+
+			return fmt.Errorf("%w: %s", errPar2Changed, sha256hash)
+		}
+
 		if job.manifest != nil && sha256hash != job.manifest.SHA256 {
 			logger := prog.verificationLogger(ctx, job, job.manifestPath)
 			logger.Warn("PAR2 has changed (manifest out of date; resetting manifest)",
@@ -596,38 +1322,151 @@ func (prog *Service) RunVerify(ctx context.Context, job *Job, isPreLocked bool)
 		}
 	}
 
+	// The error of a failed verification is only recorded into an existing
+	// manifest, never creating (or replacing a reset) manifest just for it.
+	recordsError := job.manifest != nil
+
 	if job.manifest == nil {
 		job.manifest = schema.NewManifest(job.par2Name)
 		job.manifest.SHA256 = sha256hash
+		job.manifest.Par2Location = job.par2Location
 	}
 
-	if job.manifest.Verification == nil {
-		job.manifest.Verification = schema.NewVerificationManifest()
+	if job.manifest.Creation != nil {
+		for _, path := range util.MissingElements(prog.fsys, job.workingDir, job.manifest.Creation.Elements) {
+			logger := prog.verificationLogger(ctx, job, path)
+			logger.Error("Protected file not found relative to the PAR2 set (moved without its PAR2 set?)")
+		}
+	}
+
+	prog.considerCaseCollisions(ctx, job)
+	prog.dropCaches(ctx, job)
+
+	// The basepath is pinned (by default) to the directory of the PAR2 set, so
+	// that the relative names within the set always resolve against that
+	// directory, including after the tree (with the set) was moved elsewhere.
+	basePath := job.basePath
+	if basePath == "" {
+		basePath = job.workingDir
 	}
-	job.manifest.Verification.ProgramVersion = schema.ProgramVersion
-	job.manifest.Verification.Par2Version = schema.Par2Version
-	job.manifest.Verification.Args = slices.Clone(job.par2Args)
 
-	cmdArgs := make([]string, 0, 1+len(job.par2Args)+1+1)
+	cmdArgs := make([]string, 0, 1+len(job.par2Args)+len(job.par2Verbosity)+1+1+1)
 	cmdArgs = append(cmdArgs, "verify")
 	cmdArgs = append(cmdArgs, job.par2Args...)
+	cmdArgs = append(cmdArgs, job.par2Verbosity...)
+	if !util.HasPar2BasePath(job.par2Args) {
+		cmdArgs = append(cmdArgs, "-B"+basePath)
+	}
 	cmdArgs = append(cmdArgs, "--")
 	cmdArgs = append(cmdArgs, job.par2Path)
 
-	job.manifest.Verification.Time = time.Now()
-	err := prog.runner.Run(ctx, "par2", cmdArgs, job.workingDir, prog.log.Options.Stdout, prog.log.Options.Stdout)
-	job.manifest.Verification.Duration = time.Since(job.manifest.Verification.Time)
+	// The par2 output is also parsed for the per-file status, if requested.
+	var fsw *fileStatusWriter
+	stdout := prog.log.Options.Stdout
+	if job.fileStatus {
+		fsw = newFileStatusWriter()
+		stdout = io.MultiWriter(prog.log.Options.Stdout, fsw)
+	}
 
-	if err := prog.parseExitCode(job, err); err != nil {
+	// The par2 output is also kept for the classifier, if requested.
+	var output *tailBuffer
+	if job.classifier != "" {
+		output = &tailBuffer{max: maxClassifierOutput}
+		stdout = io.MultiWriter(stdout, output)
+	}
+
+	startTime := time.Now()
+	passesDisagreed, err := prog.runPasses(ctx, job, cmdArgs, basePath, stdout)
+	duration := time.Since(startTime)
+	prog.timings.Add(util.PhasePar2, duration)
+
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		prog.markInterrupted(ctx, job)
+
+		return fmt.Errorf("context error: %w", ctxErr)
+	}
+
+	// A failed verification is not recorded, so the previous one is kept
+	// for recording only its error (a shallow copy, as slices are replaced).
+	var prevVerification *schema.VerificationManifest
+	if v := job.manifest.Verification; v != nil {
+		prev := *v
+		prevVerification = &prev
+	}
+
+	if job.manifest.Verification == nil {
+		job.manifest.Verification = schema.NewVerificationManifest()
+	}
+	job.manifest.Verification.ProgramVersion = schema.ProgramVersion
+	job.manifest.Verification.Par2Version = schema.Par2Version
+	job.manifest.Verification.Args = slices.Clone(job.par2Args)
+	job.manifest.Verification.Time = startTime
+	job.manifest.Verification.CompletedAt = startTime.Add(duration)
+	job.manifest.Verification.AddDuration(duration)
+	job.manifest.Verification.PassesDisagreed = passesDisagreed
+
+	var class string
+	if output != nil {
+		switch c := util.AsExitCode(err); {
+		case err == nil:
+			class = prog.classify(ctx, job, basePath, schema.Par2ExitCodeSuccess, output.buf)
+		case c != nil:
+			class = prog.classify(ctx, job, basePath, *c, output.buf)
+		}
+	}
+
+	if err := prog.parseExitClass(job, err, class); err != nil {
 		err = fmt.Errorf("par2cmdline: %w", err)
 
 		logger := prog.verificationLogger(ctx, job, job.par2Path)
 		logger.Error("Failed to verify PAR2", "error", err)
 
+		if recordsError {
+			job.manifest.Verification = prevVerification
+			prog.recordLastError(ctx, job, err)
+		}
+
 		return err
 	}
 
+	// A cross-check is only run over a clean verification, as a corrupted
+	// one already needs attention (and is not to be cross-checked as such).
+	if job.crossCheck != "" {
+		job.manifest.CrossCheck = nil
+		if !job.manifest.Verification.RepairNeeded {
+			if err := prog.runCrossCheck(ctx, job, basePath); err != nil {
+				job.manifest.Verification = prevVerification
+				prog.markInterrupted(ctx, job)
+
+				return err
+			}
+		}
+	}
+
 	job.manifest.Verification.Count++
+	job.manifest.Interruption = nil
+	job.manifest.ClearLastError()
+	prog.recordGeometry(ctx, job)
+
+	job.manifest.Verification.Files = nil
+	if fsw != nil {
+		prog.recordFileStatus(ctx, job, fsw)
+	}
+
+	if job.manifest.Health == nil {
+		job.manifest.Health = schema.NewHealthManifest()
+	}
+	job.manifest.Health.AddVerification(job.manifest.Verification.RepairNeeded || job.crossCheckFailed())
+
+	job.manifest.MirrorVerification = nil
+	if job.mirrorDir != "" {
+		prog.runMirrorVerify(ctx, job)
+	}
+
+	// In report-only mode, the outcome is only held in memory for the caller.
+	if job.noManifestUpdate {
+		return nil
+	}
 
 	if err := util.WriteManifest(ctx, prog.fsys, prog.bundler, job.manifestPath, job.manifest, job.isBundle); err != nil {
 		logger := prog.verificationLogger(ctx, job, job.manifestPath)
@@ -636,10 +1475,127 @@ func (prog *Service) RunVerify(ctx context.Context, job *Job, isPreLocked bool)
 		return fmt.Errorf("failed to write manifest: %w", err)
 	}
 
+	if job.stampPath != "" && !job.manifest.Verification.RepairNeeded && !job.crossCheckFailed() {
+		prog.refreshStamp(ctx, job)
+	}
+
 	return nil
 }
 
+// refreshStamp refreshes the time of an existing stamp file written for the
+// PAR2 set at creation, leaving missing and other PAR2 sets' stamp files be.
+// A failure to do so is only warned about, not failing the verification.
+func (prog *Service) refreshStamp(ctx context.Context, job *Job) {
+	stamp, err := util.ReadStamp(prog.fsys, job.stampPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+
+	logger := prog.verificationLogger(ctx, job, job.stampPath)
+	if err != nil {
+		logger.Warn("Failed to read stamp file (not refreshing it)", "error", err)
+
+		return
+	}
+	if stamp.Name != job.par2Name {
+		logger.Debug("Stamp file is of another PAR2 set (not refreshing it)", "stampName", stamp.Name)
+
+		return
+	}
+
+	if err := util.WriteStamp(prog.fsys, job.stampPath, schema.NewStamp(job.par2Name, "verify")); err != nil {
+		logger.Warn("Failed to refresh stamp file", "error", err)
+	}
+}
+
+// markInterrupted records an interruption marker in the manifest of a job
+// that was cancelled while in-flight, so the next run can prioritize it.
+// The write is best-effort, and must not be cancelled by the same context.
+// Nothing is recorded in report-only mode, which never writes manifests.
+func (prog *Service) markInterrupted(ctx context.Context, job *Job) {
+	if job.noManifestUpdate {
+		return
+	}
+
+	job.manifest.Interruption = schema.NewInterruptionManifest("verify")
+
+	if err := util.WriteManifest(context.WithoutCancel(ctx), prog.fsys, prog.bundler, job.manifestPath, job.manifest, job.isBundle); err != nil {
+		logger := prog.verificationLogger(ctx, job, job.manifestPath)
+		logger.Warn("Failed to write interruption marker to par2cron manifest", "error", err)
+	}
+}
+
+// recordLastError records the error of a failed job in the manifest, so that
+// it can be told without the logs why the PAR2 set is unhealthy. The write is
+// best-effort, and must not be cancelled by the same context. Nothing is
+// recorded in report-only mode, which never writes manifests.
+func (prog *Service) recordLastError(ctx context.Context, job *Job, err error) {
+	if job.noManifestUpdate || job.manifest == nil {
+		return
+	}
+
+	job.manifest.SetLastError("verify", err)
+
+	if err := util.WriteManifest(context.WithoutCancel(ctx), prog.fsys, prog.bundler, job.manifestPath, job.manifest, job.isBundle); err != nil {
+		logger := prog.verificationLogger(ctx, job, job.manifestPath)
+		logger.Warn("Failed to write last error to par2cron manifest", "error", err)
+	}
+}
+
+func (prog *Service) runMirrorVerify(ctx context.Context, job *Job) {
+	logger := prog.verificationLogger(ctx, job, job.mirrorDir)
+
+	if fi, err := prog.fsys.Stat(job.mirrorDir); err != nil || !fi.IsDir() {
+		logger.Warn("Mirror directory not found (skipping mirror verification)", "error", err)
+		job.mirrorDir = ""
+
+		return
+	}
+
+	mv := schema.NewMirrorVerificationManifest()
+	mv.Args = slices.Clone(job.par2Args)
+	mv.PrimaryCode = job.manifest.Verification.ExitCode
+
+	// The basepath must point into the mirror, so any of the user's is replaced
+	// with the mirrored one of the primary verification (as with --basepath).
+	par2Args := util.WithoutPar2BasePath(job.par2Args)
+	basePath := cmp.Or(job.mirrorBasePath, job.mirrorDir)
+
+	cmdArgs := make([]string, 0, 1+len(par2Args)+len(job.par2Verbosity)+1+1+1)
+	cmdArgs = append(cmdArgs, "verify")
+	cmdArgs = append(cmdArgs, par2Args...)
+	cmdArgs = append(cmdArgs, job.par2Verbosity...)
+	cmdArgs = append(cmdArgs, "-B"+basePath)
+	cmdArgs = append(cmdArgs, "--")
+	cmdArgs = append(cmdArgs, job.par2Path)
+
+	mv.Time = time.Now()
+	err := prog.runner.Run(ctx, "par2", cmdArgs, basePath, prog.log.Options.Stdout, prog.log.Options.Stdout)
+	mv.Duration = time.Since(mv.Time)
+	prog.timings.Add(util.PhasePar2, mv.Duration)
+
+	if err != nil {
+		c := util.AsExitCode(err)
+		if c == nil {
+			logger.Warn("Failed to verify PAR2 against mirror (skipping mirror verification)", "error", err)
+			job.mirrorDir = ""
+
+			return
+		}
+		mv.ExitCode = *c
+	}
+
+	mv.Diverged = (mv.ExitCode != mv.PrimaryCode)
+	job.manifest.MirrorVerification = mv
+}
+
 func (prog *Service) parseExitCode(job *Job, err error) error {
+	return prog.parseExitClass(job, err, "")
+}
+
+// parseExitClass records the outcome of a par2 verification as of the class
+// (returned by the classifier), or else as classified by its exit code.
+func (prog *Service) parseExitClass(job *Job, err error, class string) error {
 	if err == nil {
 		job.manifest.Verification.ExitCode = 0
 	} else {
@@ -652,29 +1608,43 @@ func (prog *Service) parseExitCode(job *Job, err error) error {
 		err = fmt.Errorf("%w (%d)", err, *c)
 	}
 
-	switch job.manifest.Verification.ExitCode {
-	case schema.Par2ExitCodeSuccess:
+	if class == "" {
+		class = util.Par2ExitClass(job.manifest.Verification.ExitCode, job.par2ExitCodes)
+	}
+
+	switch class {
+	case schema.Par2ExitClassSuccess:
 		job.manifest.Verification.RepairNeeded = false
 		job.manifest.Verification.RepairPossible = true
 		job.manifest.Verification.CountCorrupted = 0
 
 		return nil
 
-	case schema.Par2ExitCodeRepairPossible:
+	case schema.Par2ExitClassRepairable:
 		job.manifest.Verification.RepairNeeded = true
 		job.manifest.Verification.RepairPossible = true
 		job.manifest.Verification.CountCorrupted++
 
 		return nil
 
-	case schema.Par2ExitCodeRepairImpossible:
+	case schema.Par2ExitClassUnrepairable:
 		job.manifest.Verification.RepairNeeded = true
 		job.manifest.Verification.RepairPossible = false
 		job.manifest.Verification.CountCorrupted++
 
 		return nil
 
+	case schema.Par2ExitClassUsageError:
+		return fmt.Errorf("%w: %w: %w", schema.ErrExitBadInvocation, schema.ErrPar2Usage, err)
+
+	case par2ExitClassError:
+		return fmt.Errorf("%w (%d)", errClassifiedError, job.manifest.Verification.ExitCode)
+
 	default:
+		if class := util.Par2ExitError(job.manifest.Verification.ExitCode); class != nil {
+			return fmt.Errorf("%w: %w", class, err)
+		}
+
 		return err // Unhandled exit code, return the error.
 	}
 }
@@ -690,7 +1660,7 @@ func (prog *Service) considerBacklog(metas []*JobMeta, opts Options) {
 	}
 
 	runsPerCycle := max(int(opts.MinAge.Value/opts.RunInterval.Value), 1)
-	capacity := time.Duration(runsPerCycle) * opts.MaxDuration.Value
+	capacity := time.Duration(runsPerCycle) * opts.MaxDuration.Value * time.Duration(max(opts.Jobs, 1))
 
 	if js.TotalDuration > capacity {
 		prog.log.Warn("Backlog is growing indefinitely (increase --age, increase --duration, "+
@@ -708,7 +1678,7 @@ func (prog *Service) considerDurations(metas []*JobMeta, opts Options) {
 	}
 
 	if opts.MaxDuration.Value > 0 {
-		est := metas[0].lastDuration()
+		est := metas[0].EstDuration()
 		switch {
 		case est == 0:
 			prog.log.Warn("First job has (still) unknown duration, may exceed --duration",
@@ -724,7 +1694,7 @@ func (prog *Service) considerDurations(metas []*JobMeta, opts Options) {
 		}
 
 		for _, meta := range metas[1:] {
-			if meta.lastDuration() == 0 {
+			if meta.EstDuration() == 0 {
 				prog.log.Warn("Some jobs have a (still) unknown duration, may exceed --duration",
 					"maxDuration", opts.MaxDuration.Value.String(),
 				)
@@ -8,12 +8,17 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/desertwitch/par2cron/internal/flags"
 	"github.com/desertwitch/par2cron/internal/logging"
+	"github.com/desertwitch/par2cron/internal/par2"
 	"github.com/desertwitch/par2cron/internal/schema"
 	"github.com/desertwitch/par2cron/internal/testutil"
 	"github.com/desertwitch/par2cron/internal/util"
@@ -74,7 +79,8 @@ func Test_NewJob_Success(t *testing.T) {
 	t.Parallel()
 
 	args := Options{
-		Par2Args: []string{"-v"},
+		Par2Args:   []string{"-v"},
+		DropCaches: true,
 	}
 
 	mf := schema.NewManifest("test" + schema.Par2Extension)
@@ -87,6 +93,7 @@ func Test_NewJob_Success(t *testing.T) {
 	require.Equal(t, "test"+schema.Par2Extension+schema.ManifestExtension, job.manifestName)
 	require.Equal(t, "/data/test"+schema.Par2Extension+schema.ManifestExtension, job.manifestPath)
 	require.Equal(t, "/data/test"+schema.Par2Extension+schema.LockExtension, job.lockPath)
+	require.True(t, job.dropCaches)
 
 	require.Equal(t, mf, job.manifest)
 }
@@ -375,6 +382,241 @@ func Test_Service_Verify_Success(t *testing.T) {
 	require.Contains(t, logBuf.String(), "Job completed with success")
 }
 
+// Expectation: An existing pause file should skip all work, and its absence should not.
+func Test_Service_Verify_PauseFile_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		paused bool
+	}{
+		{"pause file exists", true},
+		{"pause file missing", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			createWithManifest(t, fs, "/data/test")
+
+			if tt.paused {
+				require.NoError(t, afero.WriteFile(fs, "/run/par2cron.pause", []byte(""), 0o644))
+			}
+
+			var logBuf testutil.SafeBuffer
+			ls := logging.Options{
+				Logout: &logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+			_ = ls.LogLevel.Set("info")
+
+			var called bool
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					called = true
+
+					return nil
+				},
+			}
+
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+			args := Options{Par2Args: []string{"-v"}, PauseFile: "/run/par2cron.pause"}
+			results, err := prog.Verify(t.Context(), []string{"/data"}, args)
+			require.NoError(t, err)
+
+			require.Equal(t, !tt.paused, called)
+			if tt.paused {
+				require.Zero(t, results.Selected)
+				require.Contains(t, logBuf.String(), "Paused by sentinel file")
+			} else {
+				require.Equal(t, 1, results.Success)
+				require.NotContains(t, logBuf.String(), "Paused by sentinel file")
+			}
+		})
+	}
+}
+
+// Expectation: The phases of a sequential run should be tracked and sum up to roughly its total.
+func Test_Service_Verify_Timings_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/a/test")
+	createWithManifest(t, fs, "/data/b/test")
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			time.Sleep(50 * time.Millisecond)
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	args := Options{Par2Args: []string{"-v"}, Jobs: 1, IOConcurrency: 1}
+	results, err := prog.Verify(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+	require.Equal(t, 2, results.Success)
+	require.NotNil(t, results.Timings)
+
+	phases := results.Timings.Phases()
+	sum := phases.Walk + phases.Parse + phases.Par2
+
+	require.GreaterOrEqual(t, phases.Par2, 100*time.Millisecond)
+	require.Positive(t, phases.Parse)
+	require.LessOrEqual(t, sum, phases.Total)
+	require.InDelta(t, phases.Total.Seconds(), sum.Seconds(), phases.Total.Seconds()*0.5)
+}
+
+// Expectation: The job loop should stop after the --limit of jobs, not counting sets
+// filtered out before (here by --age) toward the limit.
+func Test_Service_Verify_Limit_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/a/test")
+	createWithManifest(t, fs, "/data/b/test")
+	createWithManifest(t, fs, "/data/c/test")
+	createWithManifest(t, fs, "/data/d/test")
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.SHA256 = fmt.Sprintf("%x", sha256.Sum256([]byte("par2data")))
+	mf.Creation = &schema.CreationManifest{Time: time.Now()}
+	mf.Verification = &schema.VerificationManifest{Time: time.Now()}
+	by, err := json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "/data/a/test"+schema.Par2Extension+schema.ManifestExtension, by, 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	args := Options{Limit: 2}
+	_ = args.MinAge.Set("7d")
+
+	results, err := prog.Verify(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, called)
+	require.Equal(t, 3, results.Selected)
+	require.Equal(t, 2, results.Success)
+	require.Contains(t, logBuf.String(), "Reached the --limit of jobs per run")
+	require.Contains(t, logBuf.String(), "unprocessedJobs=1")
+}
+
+// Expectation: The job loop should abort once --max-errors jobs have failed, returning a partial failure.
+func Test_Service_Verify_MaxErrors_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/a/test")
+	createWithManifest(t, fs, "/data/b/test")
+	createWithManifest(t, fs, "/data/c/test")
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+
+			return errors.New("disk I/O error")
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	results, err := prog.Verify(t.Context(), []string{"/data"}, Options{MaxErrors: 1})
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+
+	require.Equal(t, 1, called)
+	require.Equal(t, 3, results.Selected)
+	require.Equal(t, 1, results.Error)
+	require.Contains(t, logBuf.String(), "Reached the --max-errors threshold of failed jobs")
+	require.Contains(t, logBuf.String(), "unprocessedJobs=2")
+}
+
+// Expectation: A set with a tighter verification interval should be selected,
+// while another set still within the --age window should be skipped.
+func Test_Service_Verify_VerifyInterval_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+
+	for path, interval := range map[string]time.Duration{"/data/critical/set": time.Hour, "/data/regular/set": 0} {
+		createWithManifest(t, fs, path)
+
+		mfPath := path + schema.Par2Extension + schema.ManifestExtension
+		data, err := afero.ReadFile(fs, mfPath)
+		require.NoError(t, err)
+
+		var mf schema.Manifest
+		require.NoError(t, json.Unmarshal(data, &mf))
+		mf.Creation.VerifyInterval = interval
+		mf.Verification = schema.NewVerificationManifest()
+		mf.Verification.Time = time.Now().Add(-2 * time.Hour)
+
+		data, err = json.Marshal(mf)
+		require.NoError(t, err)
+		require.NoError(t, afero.WriteFile(fs, mfPath, data, 0o644))
+	}
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	var verified []string
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			verified = append(verified, workingDir)
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	opts := Options{}
+	require.NoError(t, opts.MinAge.Set("24h"))
+	res, err := prog.Verify(t.Context(), []string{"/data"}, opts)
+
+	require.NoError(t, err)
+	require.Equal(t, 1, res.Selected)
+	require.Equal(t, []string{"/data/critical"}, verified)
+}
+
 // Expectation: The program should handle multiple provided root directories.
 func Test_Service_Verify_MultiRoot_Success(t *testing.T) {
 	t.Parallel()
@@ -409,6 +651,49 @@ func Test_Service_Verify_MultiRoot_Success(t *testing.T) {
 	require.Contains(t, logBuf.String(), "Job completed with success")
 }
 
+// Expectation: The jobs of all root directories should be processed with aggregated totals,
+// where a failure in one of them classifies the outcome of the entire run.
+func Test_Service_Verify_MultiRoot_PartialFailure_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+	createWithManifest(t, fs, "/data2/test")
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+			if workingDir == "/data2" {
+				return testutil.CreateExitError(t, ctx, schema.Par2ExitCodeRepairPossible)
+			}
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	args := Options{Par2Args: []string{"-v"}}
+	res, err := prog.Verify(t.Context(), []string{"/data", "/data2"}, args)
+	require.ErrorIs(t, err, schema.ErrExitRepairable)
+	require.Equal(t, schema.ExitCodeRepairable, schema.ExitCodeFor(err))
+
+	require.Equal(t, 2, called)
+	require.Equal(t, 2, res.Selected)
+	require.Equal(t, 1, res.Success)
+	require.Equal(t, 1, res.Error)
+	require.Contains(t, logBuf.String(), "Job completed with success")
+	require.Contains(t, logBuf.String(), "Job completed with corruption detected")
+}
+
 // Expectation: The program should skip remaining jobs when --duration is exceeded.
 func Test_Service_Verify_DurationExceeded_Success(t *testing.T) {
 	t.Parallel()
@@ -542,6 +827,169 @@ func Test_Service_Verify_CorruptionDetected_Repairable_Error(t *testing.T) {
 	require.Contains(t, logBuf.String(), "Job completed with corruption detected")
 }
 
+// Expectation: With NoManifestUpdate, the outcome should be reported and the manifest left byte-identical.
+func Test_Service_Verify_NoManifestUpdate_Success(t *testing.T) {
+	t.Parallel()
+
+	for _, code := range []int{schema.Par2ExitCodeSuccess, schema.Par2ExitCodeRepairPossible, schema.Par2ExitCodeRepairImpossible} {
+		fs := afero.NewMemMapFs()
+		createWithManifest(t, fs, "/data/test")
+
+		manifestPath := "/data/test" + schema.Par2Extension + schema.ManifestExtension
+		before, err := afero.ReadFile(fs, manifestPath)
+		require.NoError(t, err)
+
+		var logBuf testutil.SafeBuffer
+		ls := logging.Options{
+			Logout: &logBuf,
+			Stdout: io.Discard,
+			Stderr: io.Discard,
+		}
+		_ = ls.LogLevel.Set("info")
+
+		var called int
+		runner := &testutil.MockRunner{
+			RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+				called++
+
+				return testutil.CreateExitError(t, ctx, code)
+			},
+		}
+
+		prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+		args := Options{Par2Args: []string{"-v"}, NoManifestUpdate: true}
+		results, err := prog.Verify(t.Context(), []string{"/data"}, args)
+
+		switch code {
+		case schema.Par2ExitCodeRepairPossible:
+			require.ErrorIs(t, err, schema.ErrExitRepairable)
+			require.Equal(t, 1, results.Error)
+		case schema.Par2ExitCodeRepairImpossible:
+			require.ErrorIs(t, err, schema.ErrExitUnrepairable)
+			require.Equal(t, 1, results.Error)
+		default:
+			require.NoError(t, err)
+			require.Equal(t, 1, results.Success)
+		}
+		require.Equal(t, 1, called)
+		require.Contains(t, logBuf.String(), "report-only mode")
+
+		after, err := afero.ReadFile(fs, manifestPath)
+		require.NoError(t, err)
+		require.Equal(t, before, after)
+	}
+}
+
+// Expectation: With OnlyNeedingRepair, only jobs needing repair should be logged, all jobs still verified.
+func Test_Service_Verify_OnlyNeedingRepair_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/healthy/test")
+	createWithManifest(t, fs, "/data/corrupt/test")
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+			if workingDir == "/data/corrupt" {
+				return testutil.CreateExitError(t, ctx, schema.Par2ExitCodeRepairPossible)
+			}
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	args := Options{OnlyNeedingRepair: true}
+	results, err := prog.Verify(t.Context(), []string{"/data"}, args)
+
+	require.ErrorIs(t, err, schema.ErrExitRepairable)
+	require.Equal(t, 2, called)
+	require.Equal(t, 1, results.Success)
+	require.Equal(t, 1, results.Error)
+
+	out := logBuf.String()
+	require.NotContains(t, out, "Job started")
+	require.NotContains(t, out, "Job completed with success")
+	require.Equal(t, 1, strings.Count(out, "Job completed with corruption detected"))
+	require.Contains(t, out, "/data/corrupt/test"+schema.Par2Extension)
+	require.NotContains(t, out, "/data/healthy/test"+schema.Par2Extension)
+}
+
+// Expectation: With OnlyNeedingRepair, the filtered lines should still be emitted at debug level.
+func Test_Service_Verify_OnlyNeedingRepair_Debug_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/healthy/test")
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("debug")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	args := Options{OnlyNeedingRepair: true}
+	results, err := prog.Verify(t.Context(), []string{"/data"}, args)
+
+	require.NoError(t, err)
+	require.Equal(t, 1, results.Success)
+	require.Contains(t, logBuf.String(), "Job started")
+	require.Contains(t, logBuf.String(), "Job completed with success")
+}
+
+// Expectation: With NoManifestUpdate, an interruption should not be recorded in the manifest.
+func Test_Service_RunVerify_NoManifestUpdate_Interrupted_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+
+	manifestPath := "/data/test" + schema.Par2Extension + schema.ManifestExtension
+	before, err := afero.ReadFile(fs, manifestPath)
+	require.NoError(t, err)
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			cancel()
+
+			return context.Canceled
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	job := NewJob("/data/test"+schema.Par2Extension, Options{NoManifestUpdate: true}, nil, false)
+	err = prog.RunVerify(ctx, job, false)
+	require.ErrorIs(t, err, context.Canceled)
+
+	after, err := afero.ReadFile(fs, manifestPath)
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+}
+
 // Expectation: The program should run the verification with the correct outcome.
 func Test_Service_Verify_CorruptionDetected_Unrepairable_Error(t *testing.T) {
 	t.Parallel()
@@ -767,8 +1215,42 @@ func Test_Service_Verify_MultipleJobs_ErrorOrdering_Error(t *testing.T) {
 	require.Equal(t, 2, called)
 }
 
-// Expectation: The program should recognize when there's nothing to do.
-func Test_Service_Verify_NoJobs_Success(t *testing.T) {
+// Expectation: A missing root, or an empty root with PAR2 sets known from the cache, should be missing data,
+// not stopping the other roots.
+func Test_Service_Verify_MissingData_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/empty", 0o755))
+	createWithManifest(t, fs, "/data/test")
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	cacher := &testutil.MockCacheHandler{
+		NewCacheFunc: func(_ afero.Fs, _ string, _ string) schema.Cache {
+			return &testutil.MockCache{LenFunc: func() int { return 1 }}
+		},
+	}
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, cacher)
+
+	res, err := prog.Verify(t.Context(), []string{"/missing", "/empty", "/data"}, Options{})
+
+	require.ErrorIs(t, err, schema.ErrExitMissingData)
+	require.Equal(t, schema.ExitCodeMissingData, schema.ExitCodeFor(err))
+	require.ErrorContains(t, err, "/missing")
+	require.ErrorContains(t, err, "/empty")
+	require.Equal(t, 1, res.Selected)
+	require.Contains(t, logBuf.String(), "Failed to access the protected data")
+}
+
+// Expectation: The program should recognize when there's nothing to do.
+func Test_Service_Verify_NoJobs_Success(t *testing.T) {
 	t.Parallel()
 
 	fs := afero.NewMemMapFs()
@@ -817,6 +1299,82 @@ func Test_Service_Verify_CtxCancel_Error(t *testing.T) {
 	require.ErrorIs(t, err, context.Canceled)
 }
 
+// Expectation: A verification cancelled mid-job should record an interruption marker in the manifest.
+func Test_Service_Verify_CtxCancelMidJob_WritesInterruption_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			cancel()
+
+			return errors.New("signal: killed")
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	_, err := prog.Verify(ctx, []string{"/data"}, Options{})
+	require.ErrorIs(t, err, context.Canceled)
+
+	data, err := afero.ReadFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+
+	mf := &schema.Manifest{}
+	require.NoError(t, json.Unmarshal(data, mf))
+	require.NotNil(t, mf.Interruption)
+	require.Equal(t, "verify", mf.Interruption.Operation)
+	require.False(t, mf.Interruption.Time.IsZero())
+	require.Nil(t, mf.Verification)
+	require.NotNil(t, mf.Creation)
+	require.Contains(t, logBuf.String(), "Job interrupted (will prioritize next run)")
+}
+
+// Expectation: A completed verification should clear a previous interruption marker.
+func Test_Service_RunVerify_ClearsInterruption_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.SHA256 = fmt.Sprintf("%x", sha256.Sum256([]byte("par2data")))
+	mf.Interruption = schema.NewInterruptionManifest("verify")
+
+	job := NewJob("/data/test"+schema.Par2Extension, Options{}, mf, false)
+	require.NoError(t, prog.RunVerify(t.Context(), job, false))
+
+	data, err := afero.ReadFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+
+	written := &schema.Manifest{}
+	require.NoError(t, json.Unmarshal(data, written))
+	require.Nil(t, written.Interruption)
+	require.NotNil(t, written.Verification)
+}
+
 // Expectation: Verify should call PruneUnwalked on the cache after enumeration.
 func Test_Service_Verify_PrunesCache_Success(t *testing.T) {
 	t.Parallel()
@@ -909,6 +1467,63 @@ func Test_Service_Verify_UpdatesCache_Success(t *testing.T) {
 	require.True(t, cacheMeta.HasCreation)
 }
 
+// Expectation: Verify should keep the cached set ID in the cache only while the PAR2 index is unchanged.
+func Test_Service_Verify_CachedSetID_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		par2Data  string
+		wantSetID par2.Hash
+	}{
+		{"index unchanged", "par2data", par2.Hash{1}},
+		{"index replaced", "replaced par2data", par2.Hash{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			createWithManifest(t, fs, "/data/test")
+			require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte(tt.par2Data), 0o644))
+
+			ls := logging.Options{
+				Logout: io.Discard,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+
+			// Must be mutated in place in the cache:
+			cacheMeta := &schema.JobMeta{
+				Par2Path:    "/data/test" + schema.Par2Extension,
+				HasManifest: true,
+				HasCreation: true,
+				SetID:       par2.Hash{1},
+				IndexSHA256: fmt.Sprintf("%x", sha256.Sum256([]byte("par2data"))),
+			}
+
+			cacher := &testutil.MockCacheHandler{
+				NewCacheFunc: func(fsys afero.Fs, cacheDir string, cacheName string) schema.Cache {
+					return &testutil.MockCache{
+						GetFunc: func(key string) (*schema.JobMeta, bool) {
+							return cacheMeta, true
+						},
+					}
+				},
+			}
+
+			prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, cacher)
+			args := Options{Par2Args: []string{"-v"}, CacheDir: "/cache"}
+			_, err := prog.Verify(t.Context(), []string{"/data"}, args)
+			require.NoError(t, err)
+
+			require.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte(tt.par2Data))), cacheMeta.IndexSHA256)
+			require.Equal(t, tt.wantSetID, cacheMeta.SetID)
+		})
+	}
+}
+
 // Expectation: Verify should save the cache after processing when CacheDir is set.
 func Test_Service_Verify_SavesCache_Success(t *testing.T) {
 	t.Parallel()
@@ -1224,6 +1839,206 @@ func Test_Service_Verify_UnknownExitCode_CacheNotUpdated_Error(t *testing.T) {
 	require.Contains(t, logBuf.String(), "Job failure (will retry next run)")
 }
 
+// Expectation: The program should verify the PAR2 set also against the mirror.
+func Test_Service_Verify_Mirror_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/sub/test")
+	require.NoError(t, fs.MkdirAll("/backup/sub", 0o755))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	workingDirs := []string{}
+	runArgs := [][]string{}
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			workingDirs = append(workingDirs, workingDir)
+			runArgs = append(runArgs, args)
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	args := Options{Par2Args: []string{"-v"}, MirrorDir: "/backup"}
+	_, err := prog.Verify(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"/data/sub", "/backup/sub"}, workingDirs)
+	require.Equal(t, []string{"verify", "-v", "-B/backup/sub", "--", "/data/sub/test" + schema.Par2Extension}, runArgs[1])
+
+	data, err := afero.ReadFile(fs, "/data/sub/test"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+
+	mf := &schema.Manifest{}
+	require.NoError(t, json.Unmarshal(data, mf))
+	require.NotNil(t, mf.MirrorVerification)
+	require.False(t, mf.MirrorVerification.Diverged)
+	require.Equal(t, schema.Par2ExitCodeSuccess, mf.MirrorVerification.ExitCode)
+	require.Contains(t, logBuf.String(), "Job completed with success")
+}
+
+// Expectation: The program should report an error when the mirror verdict differs.
+func Test_Service_Verify_Mirror_Diverged_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+	require.NoError(t, fs.MkdirAll("/backup", 0o755))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			if workingDir == "/backup" {
+				return testutil.CreateExitError(t, ctx, schema.Par2ExitCodeRepairPossible)
+			}
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	args := Options{MirrorDir: "/backup"}
+	res, err := prog.Verify(t.Context(), []string{"/data"}, args)
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+	require.ErrorContains(t, err, "mirror diverged")
+	require.Equal(t, 1, res.Success)
+
+	data, err := afero.ReadFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+
+	mf := &schema.Manifest{}
+	require.NoError(t, json.Unmarshal(data, mf))
+	require.NotNil(t, mf.MirrorVerification)
+	require.True(t, mf.MirrorVerification.Diverged)
+	require.Equal(t, schema.Par2ExitCodeRepairPossible, mf.MirrorVerification.ExitCode)
+	require.Equal(t, schema.Par2ExitCodeSuccess, mf.MirrorVerification.PrimaryCode)
+	require.Contains(t, logBuf.String(), "Mirror verification diverged from primary verification")
+}
+
+// Expectation: The program should replace a basepath of the par2 arguments with the mirror.
+func Test_Service_Verify_Mirror_UserBasePath_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+	require.NoError(t, fs.MkdirAll("/backup", 0o755))
+
+	runArgs := [][]string{}
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			runArgs = append(runArgs, args)
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	args := Options{Par2Args: []string{"-q", "-B/data"}, MirrorDir: "/backup"}
+	_, err := prog.Verify(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	require.Len(t, runArgs, 2)
+	require.Equal(t, []string{"verify", "-q", "-B/data", "--", "/data/test" + schema.Par2Extension}, runArgs[0])
+	require.Equal(t, []string{"verify", "-q", "-B/backup", "--", "/data/test" + schema.Par2Extension}, runArgs[1])
+}
+
+// Expectation: The program should verify against the mirrored scan root with --basepath scan-root,
+// so that the protected names resolve against the mirror as they do against the primary.
+func Test_Service_Verify_Mirror_ScanRootBasePath_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/sub/test")
+	require.NoError(t, fs.MkdirAll("/backup/sub", 0o755))
+
+	workingDirs := []string{}
+	runArgs := [][]string{}
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			workingDirs = append(workingDirs, workingDir)
+			runArgs = append(runArgs, args)
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	args := Options{Par2Args: []string{"-q"}, MirrorDir: "/backup"}
+	require.NoError(t, args.BasePath.Set(schema.BasePathScanRoot))
+	_, err := prog.Verify(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	require.Len(t, runArgs, 2)
+	require.Equal(t, []string{"/data", "/backup"}, workingDirs)
+	require.Equal(t, []string{"verify", "-q", "-B/data", "--", "/data/sub/test" + schema.Par2Extension}, runArgs[0])
+	require.Equal(t, []string{"verify", "-q", "-B/backup", "--", "/data/sub/test" + schema.Par2Extension}, runArgs[1])
+}
+
+// Expectation: The program should skip the mirror verification when the mirror does not exist.
+func Test_Service_Verify_Mirror_NotExist_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+
+	manifestPath := "/data/test" + schema.Par2Extension + schema.ManifestExtension
+	data, err := afero.ReadFile(fs, manifestPath)
+	require.NoError(t, err)
+	mf := &schema.Manifest{}
+	require.NoError(t, json.Unmarshal(data, mf))
+	mf.MirrorVerification = schema.NewMirrorVerificationManifest()
+	data, err = json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, manifestPath, data, 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var calls int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			calls++
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	args := Options{MirrorDir: "/backup"}
+	_, err = prog.Verify(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, calls)
+
+	data, err = afero.ReadFile(fs, manifestPath)
+	require.NoError(t, err)
+	mf = &schema.Manifest{}
+	require.NoError(t, json.Unmarshal(data, mf))
+	require.Nil(t, mf.MirrorVerification)
+	require.Contains(t, logBuf.String(), "Mirror directory not found (skipping mirror verification)")
+	require.Contains(t, logBuf.String(), "Job completed with success")
+}
+
 // Expectation: The correct job and its manifest should be returned.
 func Test_Service_Enumerate_Success(t *testing.T) {
 	t.Parallel()
@@ -1391,15 +2206,18 @@ func Test_Service_Enumerate_IgnoreFile_Success(t *testing.T) {
 	require.Equal(t, "/data/subdir/notignored"+schema.Par2Extension, jobs[0].Par2Path)
 }
 
-// Expectation: Elements in directories with an ignore-all file should be skipped recursively.
-func Test_Service_Enumerate_IgnoreAllFile_Success(t *testing.T) {
+// Expectation: Renamed ignore files should prune the right directories.
+func Test_Service_Enumerate_RenamedIgnoreFiles_Success(t *testing.T) {
 	t.Parallel()
 
 	fs := afero.NewMemMapFs()
-	createWithManifest(t, fs, "/data/normal/test")
-	createWithManifest(t, fs, "/data/ignored/sub/test")
+	createWithManifest(t, fs, "/data/ignored")
+	createWithManifest(t, fs, "/data/all/deep/ignored")
+	createWithManifest(t, fs, "/data/default/notignored")
 
-	require.NoError(t, afero.WriteFile(fs, "/data/ignored/"+schema.IgnoreAllFile, []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/.par2cronignore", []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/all/.par2cronignoreall", []byte(""), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/default/"+schema.IgnoreFile, []byte(""), 0o644))
 
 	var logBuf testutil.SafeBuffer
 	ls := logging.Options{
@@ -1411,23 +2229,24 @@ func Test_Service_Enumerate_IgnoreAllFile_Success(t *testing.T) {
 
 	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
 
-	args := Options{Par2Args: []string{"-v"}}
+	args := Options{IgnoreNames: util.IgnoreNames{File: ".par2cronignore", AllFile: ".par2cronignoreall"}}
 	jobs, err := prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
 
 	require.NoError(t, err)
 	require.Len(t, jobs, 1)
-	require.Equal(t, "/data/normal/test"+schema.Par2Extension, jobs[0].Par2Path)
+	require.Equal(t, "/data/default/notignored"+schema.Par2Extension, jobs[0].Par2Path)
 }
 
-// Expectation: Subdirectories with an ignore-all file should skip the entire directory tree.
-func Test_Service_Enumerate_IgnoreAllFile_SkipsRecursive_Success(t *testing.T) {
+// Expectation: Directories beyond the maximum depth should be pruned, while ignore-all files within the limit still apply.
+func Test_Service_Enumerate_MaxDepth_Success(t *testing.T) {
 	t.Parallel()
 
 	fs := afero.NewMemMapFs()
-	createWithManifest(t, fs, "/data/test")
-	createWithManifest(t, fs, "/data/subdir/test")
+	createWithManifest(t, fs, "/data/root")
+	createWithManifest(t, fs, "/data/one/test")
+	createWithManifest(t, fs, "/data/one/two/test")
 	createWithManifest(t, fs, "/data/ignored/test")
-	createWithManifest(t, fs, "/data/ignored/subdir/nested")
+	createWithManifest(t, fs, "/data/ignored/two/test")
 
 	require.NoError(t, afero.WriteFile(fs, "/data/ignored/"+schema.IgnoreAllFile, []byte(""), 0o644))
 
@@ -1441,34 +2260,143 @@ func Test_Service_Enumerate_IgnoreAllFile_SkipsRecursive_Success(t *testing.T) {
 
 	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
 
-	args := Options{Par2Args: []string{"-v"}}
-	jobs, err := prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
+	tests := []struct {
+		depth string
+		want  []string
+	}{
+		{"0", []string{"/data/root"}},
+		{"1", []string{"/data/one/test", "/data/root"}},
+		{"", []string{"/data/one/test", "/data/one/two/test", "/data/root"}},
+	}
 
-	require.NoError(t, err)
-	require.Len(t, jobs, 2)
-	require.Equal(t, "/data/subdir/test"+schema.Par2Extension, jobs[0].Par2Path)
-	require.Equal(t, "/data/test"+schema.Par2Extension, jobs[1].Par2Path)
+	for _, tt := range tests {
+		args := Options{}
+		require.NoError(t, args.MaxDepth.Set(tt.depth))
+
+		jobs, err := prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
+		require.NoError(t, err)
+
+		got := make([]string, 0, len(jobs))
+		for _, job := range jobs {
+			got = append(got, strings.TrimSuffix(job.Par2Path, schema.Par2Extension))
+		}
+		slices.Sort(got)
+
+		require.Equal(t, tt.want, got, "max depth %q", tt.depth)
+	}
+
+	require.Contains(t, logBuf.String(), "A directory was skipped due to the maximum depth")
 }
 
-// Expectation: PAR2 files without manifest should be included when --include-external is set.
-func Test_Service_Enumerate_IncludeExternal_Success(t *testing.T) {
+// Expectation: A maximum depth of 0 (--no-recurse) should not enumerate subdirectories, while ignore files still apply.
+func Test_Service_Enumerate_NoRecurse_Success(t *testing.T) {
 	t.Parallel()
 
 	fs := afero.NewMemMapFs()
-	require.NoError(t, afero.WriteFile(fs, "/data/no-manifest"+schema.Par2Extension, []byte("par2"), 0o644))
+	createWithManifest(t, fs, "/data/test")
+	createWithManifest(t, fs, "/data/sub/test")
+	createWithManifest(t, fs, "/other/ignored")
+
+	require.NoError(t, afero.WriteFile(fs, "/other/"+schema.IgnoreFile, []byte(""), 0o644))
 
-	var logBuf testutil.SafeBuffer
 	ls := logging.Options{
-		Logout: &logBuf,
+		Logout: io.Discard,
 		Stdout: io.Discard,
 		Stderr: io.Discard,
 	}
-	_ = ls.LogLevel.Set("debug")
-
 	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
 
-	args := Options{
-		Par2Args:        []string{"-v"},
+	args := Options{}
+	require.NoError(t, args.MaxDepth.Set("0"))
+
+	jobs, err := prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, "/data/test"+schema.Par2Extension, jobs[0].Par2Path)
+
+	jobs, err = prog.Enumerate(t.Context(), "/other", args, &testutil.MockCache{})
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+}
+
+// Expectation: Elements in directories with an ignore-all file should be skipped recursively.
+func Test_Service_Enumerate_IgnoreAllFile_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/normal/test")
+	createWithManifest(t, fs, "/data/ignored/sub/test")
+
+	require.NoError(t, afero.WriteFile(fs, "/data/ignored/"+schema.IgnoreAllFile, []byte(""), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("debug")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	args := Options{Par2Args: []string{"-v"}}
+	jobs, err := prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, "/data/normal/test"+schema.Par2Extension, jobs[0].Par2Path)
+}
+
+// Expectation: Subdirectories with an ignore-all file should skip the entire directory tree.
+func Test_Service_Enumerate_IgnoreAllFile_SkipsRecursive_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+	createWithManifest(t, fs, "/data/subdir/test")
+	createWithManifest(t, fs, "/data/ignored/test")
+	createWithManifest(t, fs, "/data/ignored/subdir/nested")
+
+	require.NoError(t, afero.WriteFile(fs, "/data/ignored/"+schema.IgnoreAllFile, []byte(""), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("debug")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	args := Options{Par2Args: []string{"-v"}}
+	jobs, err := prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
+
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	require.Equal(t, "/data/subdir/test"+schema.Par2Extension, jobs[0].Par2Path)
+	require.Equal(t, "/data/test"+schema.Par2Extension, jobs[1].Par2Path)
+}
+
+// Expectation: PAR2 files without manifest should be included when --include-external is set.
+func Test_Service_Enumerate_IncludeExternal_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data/no-manifest"+schema.Par2Extension, []byte("par2"), 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("debug")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	args := Options{
+		Par2Args:        []string{"-v"},
 		IncludeExternal: true,
 	}
 	jobs, err := prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
@@ -1542,6 +2470,199 @@ func Test_Service_Enumerate_SkipNotCreated_Success(t *testing.T) {
 	require.Len(t, jobs, 1)
 	require.Equal(t, "/data/with-creation"+schema.Par2Extension, jobs[0].Par2Path)
 	require.Contains(t, logBuf.String(), "skipping; --skip-not-created")
+	require.Contains(t, logBuf.String(), schema.ReasonSkipNotCreated)
+}
+
+// Expectation: Only PAR2 sets having all the required tags should be enumerated.
+func Test_Service_Enumerate_Tags_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data/external"+schema.Par2Extension, []byte("par2"), 0o644))
+	for name, tags := range map[string][]string{
+		"critical-video": {"media:video", "tier:critical"},
+		"critical":       {"tier:critical"},
+		"untagged":       nil,
+	} {
+		mf := schema.NewManifest(name + schema.Par2Extension)
+		mf.Creation = schema.NewCreationManifest()
+		mf.Creation.Tags = tags
+
+		data, err := json.Marshal(mf)
+		require.NoError(t, err)
+		require.NoError(t, afero.WriteFile(fs, "/data/"+name+schema.Par2Extension, []byte("par2"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "/data/"+name+schema.Par2Extension+schema.ManifestExtension, data, 0o644))
+	}
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("debug")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	args := Options{IncludeExternal: true}
+	require.NoError(t, args.Tags.Set("tier:critical"))
+	jobs, err := prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	require.Contains(t, logBuf.String(), schema.ReasonTagMismatch)
+
+	require.NoError(t, args.Tags.Set("media:video"))
+	jobs, err = prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, "/data/critical-video"+schema.Par2Extension, jobs[0].Par2Path)
+
+	jobs, err = prog.Enumerate(t.Context(), "/data", Options{IncludeExternal: true}, &testutil.MockCache{})
+	require.NoError(t, err)
+	require.Len(t, jobs, 4)
+}
+
+// Expectation: Only PAR2 sets created within the date range should be enumerated,
+// with PAR2 sets without a creation record only under --include-not-created.
+func Test_Service_Enumerate_CreatedRange_Table(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data/external"+schema.Par2Extension, []byte("par2"), 0o644))
+	for name, created := range map[string]time.Time{
+		"old": time.Date(2023, 1, 15, 12, 0, 0, 0, time.Local),
+		"mid": time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local),
+		"new": time.Date(2025, 6, 30, 23, 59, 0, 0, time.Local),
+	} {
+		mf := schema.NewManifest(name + schema.Par2Extension)
+		mf.Creation = schema.NewCreationManifest()
+		mf.Creation.Time = created
+
+		data, err := json.Marshal(mf)
+		require.NoError(t, err)
+		require.NoError(t, afero.WriteFile(fs, "/data/"+name+schema.Par2Extension, []byte("par2"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "/data/"+name+schema.Par2Extension+schema.ManifestExtension, data, 0o644))
+	}
+
+	tests := []struct {
+		name       string
+		before     string
+		after      string
+		notCreated bool
+		want       []string
+	}{
+		{"no filter", "", "", false, []string{"external", "mid", "new", "old"}},
+		{"after", "", "2024-01-01", false, []string{"mid", "new"}},
+		{"after is inclusive", "", "2024-03-01", false, []string{"mid", "new"}},
+		{"before", "2024-03-01", "", false, []string{"old"}},
+		{"range", "2025-01-01", "2024-01-01", false, []string{"mid"}},
+		{"timestamp", "", "2025-06-30T23:00:00Z", false, []string{"new"}},
+		{"include not created", "2024-01-01", "", true, []string{"external", "old"}},
+		{"empty range", "2026-01-01", "2025-07-01", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var logBuf testutil.SafeBuffer
+			ls := logging.Options{
+				Logout: &logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+			_ = ls.LogLevel.Set("debug")
+
+			prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+			args := Options{IncludeExternal: true, IncludeNotCreated: tt.notCreated}
+			require.NoError(t, args.CreatedBefore.Set(tt.before))
+			require.NoError(t, args.CreatedAfter.Set(tt.after))
+			require.NoError(t, args.Validate())
+
+			jobs, err := prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
+			require.NoError(t, err)
+
+			var got []string
+			for _, job := range jobs {
+				got = append(got, strings.TrimSuffix(filepath.Base(job.Par2Path), schema.Par2Extension))
+			}
+			slices.Sort(got)
+			require.Equal(t, tt.want, got)
+
+			if len(got) < 4 && !tt.notCreated {
+				require.Contains(t, logBuf.String(), schema.ReasonSkipNotCreated)
+			}
+		})
+	}
+}
+
+// Expectation: The options should be refused with an empty creation date range,
+// or with --include-not-created but no creation date filter.
+func Test_Options_Validate_CreatedRange_Error(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{}
+	require.NoError(t, opts.CreatedBefore.Set("2024-01-01"))
+	require.NoError(t, opts.CreatedAfter.Set("2024-01-01"))
+	require.ErrorContains(t, opts.Validate(), "created-after")
+
+	opts = Options{IncludeNotCreated: true}
+	require.ErrorContains(t, opts.Validate(), "include-not-created")
+}
+
+// Expectation: An orphaned manifest should be warned about and never become a job,
+// but only be removed with --clean-orphans (and not in report-only mode).
+func Test_Service_Enumerate_OrphanedManifest_Success(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    Options
+		removed bool
+		log     string
+	}{
+		{"warn only", Options{}, false, "Orphaned manifest without PAR2 set"},
+		{"clean orphans", Options{CleanOrphans: true}, true, "Removed orphaned manifest without PAR2 set"},
+		{"clean orphans report-only", Options{CleanOrphans: true, NoManifestUpdate: true}, false, "Orphaned manifest without PAR2 set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "/data/gone"+schema.Par2Extension+schema.ManifestExtension, []byte("{}"), 0o644))
+			createWithManifest(t, fs, "/data/kept/test")
+
+			var logBuf testutil.SafeBuffer
+			ls := logging.Options{
+				Logout: &logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+			_ = ls.LogLevel.Set("info")
+
+			prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+			jobs, err := prog.Enumerate(t.Context(), "/data", tt.opts, &testutil.MockCache{})
+			require.NoError(t, err)
+			require.Len(t, jobs, 1)
+			require.Equal(t, "/data/kept/test"+schema.Par2Extension, jobs[0].Par2Path)
+			require.Contains(t, logBuf.String(), tt.log)
+			require.Contains(t, logBuf.String(), schema.ReasonOrphanedManifest)
+
+			_, err = fs.Stat("/data/gone" + schema.Par2Extension + schema.ManifestExtension)
+			if tt.removed {
+				require.ErrorIs(t, err, os.ErrNotExist)
+			} else {
+				require.NoError(t, err)
+			}
+
+			_, err = fs.Stat("/data/kept/test" + schema.Par2Extension + schema.ManifestExtension)
+			require.NoError(t, err)
+		})
+	}
 }
 
 // Expectation: PAR2 files with invalid manifest should be skipped when --skip-not-created is set.
@@ -1571,6 +2692,7 @@ func Test_Service_Enumerate_SkipNotCreated_InvalidManifest_Success(t *testing.T)
 	require.NoError(t, err)
 	require.Empty(t, jobs)
 	require.Contains(t, logBuf.String(), "skipping; --skip-not-created")
+	require.Contains(t, logBuf.String(), schema.ReasonSkipNotCreated)
 }
 
 // Expectation: PAR2 files without creation manifest should be included when --skip-not-created is not set.
@@ -2250,7 +3372,7 @@ func Test_Service_loadManifest_FileNotFound_ReturnsNilManifest_Success(t *testin
 		},
 	}
 
-	mf, err := prog.loadManifest(t.Context(), meta)
+	mf, err := prog.loadManifest(t.Context(), meta, Options{})
 
 	require.NoError(t, err)
 	require.Nil(t, mf)
@@ -2282,13 +3404,50 @@ func Test_Service_loadManifest_InvalidJSON_ReturnsNilManifest_Success(t *testing
 		},
 	}
 
-	mf, err := prog.loadManifest(t.Context(), meta)
+	mf, err := prog.loadManifest(t.Context(), meta, Options{})
 
 	require.NoError(t, err)
 	require.Nil(t, mf)
 	require.Contains(t, logBuf.String(), "Failed to unmarshal par2cron manifest")
 }
 
+// Expectation: A manifest exceeding --max-manifest-size should be treated as invalid (resetting it) at enumeration.
+func Test_Service_Enumerate_MaxManifestSize_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2"), 0o644))
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.LastError = strings.Repeat("x", 2048)
+	data, err := json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension, data, 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	jobs, err := prog.Enumerate(t.Context(), "/data", Options{}, &testutil.MockCache{})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.True(t, jobs[0].HasManifest)
+	require.NotContains(t, logBuf.String(), "reason="+schema.ReasonManifestTooLarge)
+
+	jobs, err = prog.Enumerate(t.Context(), "/data", Options{MaxManifestSize: 1024}, &testutil.MockCache{})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.False(t, jobs[0].HasManifest)
+	require.Contains(t, logBuf.String(), "reason="+schema.ReasonManifestTooLarge)
+	require.Contains(t, logBuf.String(), "exceeds --max-manifest-size")
+}
+
 // Expectation: loadManifest should return an error when the manifest file cannot be read due to a non-NotExist error.
 func Test_Service_loadManifest_ReadError_Error(t *testing.T) {
 	t.Parallel()
@@ -2319,7 +3478,7 @@ func Test_Service_loadManifest_ReadError_Error(t *testing.T) {
 		},
 	}
 
-	mf, err := prog.loadManifest(t.Context(), meta)
+	mf, err := prog.loadManifest(t.Context(), meta, Options{})
 
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "failed to read")
@@ -2350,7 +3509,7 @@ func Test_Service_loadManifest_ValidManifest_Success(t *testing.T) {
 		},
 	}
 
-	mf, err := prog.loadManifest(t.Context(), meta)
+	mf, err := prog.loadManifest(t.Context(), meta, Options{})
 
 	require.NoError(t, err)
 	require.NotNil(t, mf)
@@ -2397,7 +3556,7 @@ func Test_Service_loadBundleManifest_ManifestReadFails_ReturnsNilManifest_Succes
 		},
 	}
 
-	mf, err := prog.loadBundleManifest(t.Context(), meta)
+	mf, err := prog.loadBundleManifest(t.Context(), meta, Options{})
 
 	require.NoError(t, err)
 	require.Nil(t, mf)
@@ -2444,7 +3603,7 @@ func Test_Service_loadBundleManifest_InvalidJSON_ReturnsNilManifest_Success(t *t
 		},
 	}
 
-	mf, err := prog.loadBundleManifest(t.Context(), meta)
+	mf, err := prog.loadBundleManifest(t.Context(), meta, Options{})
 
 	require.NoError(t, err)
 	require.Nil(t, mf)
@@ -2482,7 +3641,7 @@ func Test_Service_loadBundleManifest_OpenFails_Error(t *testing.T) {
 		},
 	}
 
-	mf, err := prog.loadBundleManifest(t.Context(), meta)
+	mf, err := prog.loadBundleManifest(t.Context(), meta, Options{})
 
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "failed to open")
@@ -2534,7 +3693,7 @@ func Test_Service_loadBundleManifest_ValidManifest_Success(t *testing.T) {
 		},
 	}
 
-	result, err := prog.loadBundleManifest(t.Context(), meta)
+	result, err := prog.loadBundleManifest(t.Context(), meta, Options{})
 
 	require.NoError(t, err)
 	require.NotNil(t, result)
@@ -2585,6 +3744,129 @@ func Test_Service_RunVerify_Success(t *testing.T) {
 	require.True(t, manifestExists)
 }
 
+// Expectation: RunVerify should refresh an existing stamp file of the PAR2 set after a healthy verification.
+func Test_Service_RunVerify_RefreshStamp_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/other/other"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	old := schema.NewStamp("test"+schema.Par2Extension, "create")
+	old.Time = time.Now().Add(-time.Hour)
+	require.NoError(t, util.WriteStamp(fs, "/data/"+schema.StampFile, old))
+
+	foreign := schema.NewStamp("foreign"+schema.Par2Extension, "create")
+	foreign.Time = old.Time
+	require.NoError(t, util.WriteStamp(fs, "/other/"+schema.StampFile, foreign))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	opts := Options{RefreshStamp: true, StampFile: schema.StampFile}
+
+	require.NoError(t, prog.RunVerify(t.Context(), NewJob("/data/test"+schema.Par2Extension, opts, nil, false), false))
+	require.NoError(t, prog.RunVerify(t.Context(), NewJob("/other/other"+schema.Par2Extension, opts, nil, false), false))
+
+	got, err := util.ReadStamp(fs, "/data/"+schema.StampFile)
+	require.NoError(t, err)
+	require.Equal(t, "verify", got.Operation)
+	require.True(t, got.Time.After(old.Time))
+
+	got, err = util.ReadStamp(fs, "/other/"+schema.StampFile)
+	require.NoError(t, err)
+	require.Equal(t, "create", got.Operation)
+	require.True(t, got.Time.Equal(old.Time))
+}
+
+// Expectation: RunVerify should not create a stamp file, nor refresh it when the PAR2 set needs repair.
+func Test_Service_RunVerify_RefreshStamp_NotHealthy_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/new/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	old := schema.NewStamp("test"+schema.Par2Extension, "create")
+	require.NoError(t, util.WriteStamp(fs, "/data/"+schema.StampFile, old))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			if workingDir == "/data" {
+				return testutil.CreateExitError(t, ctx, schema.Par2ExitCodeRepairPossible)
+			}
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	opts := Options{RefreshStamp: true, StampFile: schema.StampFile}
+
+	require.NoError(t, prog.RunVerify(t.Context(), NewJob("/data/test"+schema.Par2Extension, opts, nil, false), false))
+	require.NoError(t, prog.RunVerify(t.Context(), NewJob("/new/test"+schema.Par2Extension, opts, nil, false), false))
+
+	got, err := util.ReadStamp(fs, "/data/"+schema.StampFile)
+	require.NoError(t, err)
+	require.Equal(t, "create", got.Operation)
+
+	exists, _ := afero.Exists(fs, "/new/"+schema.StampFile)
+	require.False(t, exists)
+}
+
+// Expectation: RunVerify should record the completion of par2 as its start (the time) plus the duration.
+func Test_Service_RunVerify_CompletedAt_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	ls := logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			time.Sleep(5 * time.Millisecond)
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	job := NewJob("/data/test"+schema.Par2Extension, Options{}, nil, false)
+	before := time.Now()
+	require.NoError(t, prog.RunVerify(t.Context(), job, false))
+
+	data, err := afero.ReadFile(fs, job.manifestPath)
+	require.NoError(t, err)
+
+	var written schema.Manifest
+	require.NoError(t, json.Unmarshal(data, &written))
+
+	v := written.Verification
+	require.NotNil(t, v)
+	require.False(t, v.Time.Before(before.Truncate(time.Second)))
+	require.False(t, v.CompletedAt.Before(v.Time))
+	require.Equal(t, v.Duration, v.CompletedAt.Sub(v.Time))
+	require.GreaterOrEqual(t, v.Duration, 5*time.Millisecond)
+}
+
 // Expectation: RunVerify should return an error when the PAR2 file cannot be hashed.
 func Test_Service_RunVerify_HashFileFails_Error(t *testing.T) {
 	t.Parallel()
@@ -2665,38 +3947,349 @@ func Test_Service_RunVerify_CorrectArgs_Success(t *testing.T) {
 		"verify",
 		"-v",
 		"-q",
+		"-B" + job.workingDir,
 		"--",
 		job.par2Path,
 	}, runArgs)
 }
 
-// Expectation: The verification should update verification-specific fields
-// (time, duration, count, args, versions) rather than keeping stale values.
-func Test_Service_RunVerify_UpdatesVerificationFields_Success(t *testing.T) {
+// Expectation: The verbosity managed through the options should be passed after the user's arguments.
+func Test_Service_RunVerify_Par2Quiet_Success(t *testing.T) {
 	t.Parallel()
 
 	fs := afero.NewMemMapFs()
-	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
 
-	var logBuf testutil.SafeBuffer
 	ls := logging.Options{
-		Logout: &logBuf,
+		Logout: io.Discard,
 		Stdout: io.Discard,
 		Stderr: io.Discard,
 	}
-	_ = ls.LogLevel.Set("info")
 
+	runArgs := []string{}
 	runner := &testutil.MockRunner{
 		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			runArgs = append(runArgs, args...)
+
 			return nil
 		},
 	}
 
 	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
 
-	oldTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
-	job := &Job{
-		workingDir:   "/data",
+	opts := Options{Par2Args: []string{"-t4"}, Par2Quiet: true}
+	require.NoError(t, opts.Validate())
+
+	job := NewJob("/data/test"+schema.Par2Extension, opts, nil, false)
+	require.NoError(t, prog.RunVerify(t.Context(), job, false))
+
+	require.Equal(t, []string{
+		"verify",
+		"-t4",
+		"-q",
+		"-B/data",
+		"--",
+		job.par2Path,
+	}, runArgs)
+}
+
+// Expectation: The options should be refused with conflicting verbosities.
+func Test_Options_Validate_Par2Verbosity_Error(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{Par2Quiet: true, Par2Verbose: true}
+	require.ErrorIs(t, opts.Validate(), schema.ErrPar2Verbosity)
+
+	opts = Options{Par2Args: []string{"-vv"}, Par2Quiet: true}
+	require.ErrorIs(t, opts.Validate(), schema.ErrPar2Verbosity)
+}
+
+// Expectation: A basepath from the user's arguments should be kept and not be added twice.
+func Test_Service_RunVerify_UserBasePath_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	runArgs := []string{}
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			runArgs = append(runArgs, args...)
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	job := &Job{
+		workingDir:   "/data",
+		par2Name:     "test" + schema.Par2Extension,
+		par2Path:     "/data/test" + schema.Par2Extension,
+		par2Args:     []string{"-B/elsewhere"},
+		manifestName: "test" + schema.Par2Extension + schema.ManifestExtension,
+		manifestPath: "/data/test" + schema.Par2Extension + schema.ManifestExtension,
+	}
+
+	require.NoError(t, prog.RunVerify(t.Context(), job, false))
+	require.Equal(t, []string{"verify", "-B/elsewhere", "--", job.par2Path}, runArgs)
+}
+
+// Expectation: Each basepath mode should run par2 in and pass as basepath the expected directory.
+func Test_Service_Verify_BasePath_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mode     string
+		par2Args []string
+		wantDir  string
+		wantArgs []string
+	}{
+		{"unset", "", nil, "/data/a/b", []string{"verify", "-B/data/a/b", "--", "/data/a/b/test.par2"}},
+		{"auto", schema.BasePathAuto, nil, "/data/a/b", []string{"verify", "-B/data/a/b", "--", "/data/a/b/test.par2"}},
+		{"auto with user basepath", schema.BasePathAuto, []string{"-B/elsewhere"}, "/data/a/b", []string{"verify", "-B/elsewhere", "--", "/data/a/b/test.par2"}},
+		{"set dir", schema.BasePathSetDir, nil, "/data/a/b", []string{"verify", "-B/data/a/b", "--", "/data/a/b/test.par2"}},
+		{"scan root", schema.BasePathScanRoot, nil, "/data", []string{"verify", "-B/data", "--", "/data/a/b/test.par2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			createWithManifest(t, fs, "/data/a/b/test")
+
+			ls := logging.Options{
+				Logout: io.Discard,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+
+			var runArgs []string
+			var runDir string
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					runArgs = args
+					runDir = workingDir
+
+					return nil
+				},
+			}
+
+			opts := Options{Par2Args: tt.par2Args}
+			if tt.mode != "" {
+				require.NoError(t, opts.BasePath.Set(tt.mode))
+			}
+			require.NoError(t, opts.Validate())
+
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+			res, err := prog.Verify(t.Context(), []string{"/data"}, opts)
+
+			require.NoError(t, err)
+			require.Equal(t, 1, res.Success)
+			require.Equal(t, tt.wantDir, runDir)
+			require.Equal(t, tt.wantArgs, runArgs)
+		})
+	}
+}
+
+// Expectation: The options should be refused with a user basepath where the basepath mode pins it.
+func Test_Options_Validate_BasePath_Error(t *testing.T) {
+	t.Parallel()
+
+	for _, mode := range []string{schema.BasePathSetDir, schema.BasePathScanRoot} {
+		opts := Options{Par2Args: []string{"-B/elsewhere"}}
+		require.NoError(t, opts.BasePath.Set(mode))
+		require.ErrorContains(t, opts.Validate(), "basepath", mode)
+	}
+}
+
+// Expectation: A par2 crash should be retried once single-threaded only with the option,
+// while a verification result (such as corruption) should not be retried.
+func Test_Service_Verify_RetrySingleThreaded_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		retry       bool
+		code        int
+		wantCalls   int
+		wantSuccess int
+	}{
+		{"crash retried", true, schema.Par2ExitCodeLogicError, 2, 1},
+		{"crash without option", false, schema.Par2ExitCodeLogicError, 1, 0},
+		{"corruption not retried", true, schema.Par2ExitCodeRepairPossible, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			createWithManifest(t, fs, "/data/test")
+
+			ls := logging.Options{
+				Logout: io.Discard,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+
+			var runArgs [][]string
+			runner := &testutil.MockRunner{
+				RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+					runArgs = append(runArgs, args)
+					if !slices.Contains(args, "-t1") {
+						return testutil.CreateExitError(t, ctx, tt.code)
+					}
+
+					return nil
+				},
+			}
+
+			opts := Options{Par2Args: []string{"-t8"}, RetrySingleThreaded: tt.retry}
+			prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+			res, _ := prog.Verify(t.Context(), []string{"/data"}, opts)
+
+			require.Equal(t, tt.wantSuccess, res.Success)
+			require.Len(t, runArgs, tt.wantCalls)
+			if tt.wantCalls > 1 {
+				require.Equal(t, []string{"verify", "-B/data", "-t1", "--", "/data/test" + schema.Par2Extension}, runArgs[1])
+			}
+		})
+	}
+}
+
+// createRecursiveSet writes a recursive mode PAR2 set with a manifest into
+// dir, protecting the "sub" directory and the "a.txt" file (as relative names).
+func createRecursiveSet(t *testing.T, fs afero.Fs, dir string) {
+	t.Helper()
+
+	require.NoError(t, fs.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, "sub", "deep.txt"), []byte("deep"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+
+	createWithManifest(t, fs, filepath.Join(dir, "set"))
+
+	mfPath := filepath.Join(dir, "set"+schema.Par2Extension+schema.ManifestExtension)
+	data, err := afero.ReadFile(fs, mfPath)
+	require.NoError(t, err)
+
+	var mf schema.Manifest
+	require.NoError(t, json.Unmarshal(data, &mf))
+	mf.Creation.Mode = schema.CreateRecursiveMode
+	mf.Creation.Elements = []schema.FsElement{
+		{Name: "sub", IsDir: true},
+		{Name: "a.txt", Size: 1},
+	}
+
+	data, err = json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, mfPath, data, 0o644))
+}
+
+// Expectation: A set relocated with its tree should verify against its new directory as basepath.
+func Test_Service_Verify_RelocatedTree_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createRecursiveSet(t, fs, "/old/library")
+	require.NoError(t, fs.Rename("/old/library", "/new/library"))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var runArgs []string
+	var runDir string
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			runArgs = args
+			runDir = workingDir
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	res, err := prog.Verify(t.Context(), []string{"/new"}, Options{})
+
+	require.NoError(t, err)
+	require.Equal(t, 1, res.Success)
+	require.Equal(t, "/new/library", runDir)
+	require.Contains(t, runArgs, "-B/new/library")
+	require.NotContains(t, logBuf.String(), "Protected file not found")
+}
+
+// Expectation: A referenced relative file absent from the relocated tree should be logged as an error.
+func Test_Service_Verify_RelocatedTree_MissingElement_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createRecursiveSet(t, fs, "/old/library")
+	require.NoError(t, fs.Rename("/old/library", "/new/library"))
+	require.NoError(t, fs.RemoveAll("/new/library/sub"))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			return testutil.CreateExitError(t, ctx, schema.Par2ExitCodeRepairPossible)
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	_, err := prog.Verify(t.Context(), []string{"/new"}, Options{})
+
+	require.ErrorIs(t, err, schema.ErrExitRepairable)
+	require.Contains(t, logBuf.String(), "Protected file not found relative to the PAR2 set")
+	require.Contains(t, logBuf.String(), "/new/library/sub")
+	require.NotContains(t, logBuf.String(), "/new/library/a.txt")
+}
+
+// Expectation: The verification should update verification-specific fields
+// (time, duration, count, args, versions) rather than keeping stale values.
+func Test_Service_RunVerify_UpdatesVerificationFields_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte{}, 0o644))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	oldTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	job := &Job{
+		workingDir:   "/data",
 		par2Name:     "test" + schema.Par2Extension,
 		par2Path:     "/data/test" + schema.Par2Extension,
 		par2Args:     []string{"-v", "-q"},
@@ -2728,12 +4321,201 @@ func Test_Service_RunVerify_UpdatesVerificationFields_Success(t *testing.T) {
 	require.True(t, mf.Verification.Time.After(oldTime))
 	require.NotEqual(t, 999*time.Second, mf.Verification.Duration)
 	require.Equal(t, 6, mf.Verification.Count)
+	require.Equal(t, 1, mf.Health.CountClean)
+	require.Equal(t, schema.MaxHealthScore, mf.Health.Score)
 	require.Equal(t, []string{"-v", "-q"}, mf.Verification.Args)
 	require.Equal(t, schema.ProgramVersion, mf.Verification.ProgramVersion)
 	require.Equal(t, schema.Par2Version, mf.Verification.Par2Version)
 	require.Equal(t, 0, mf.Verification.ExitCode)
 }
 
+// Expectation: A verify run should stamp the manifest with the program version writing it.
+func Test_Service_Verify_StampsProgramVersion_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+
+	manifestPath := "/data/test" + schema.Par2Extension + schema.ManifestExtension
+
+	data, err := afero.ReadFile(fs, manifestPath)
+	require.NoError(t, err)
+	mf := &schema.Manifest{}
+	require.NoError(t, json.Unmarshal(data, mf))
+	mf.ProgramVersion = "v0.0.1-old"
+	data, err = json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, manifestPath, data, 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	_, err = prog.Verify(t.Context(), []string{"/data"}, Options{})
+	require.NoError(t, err)
+
+	data, err = afero.ReadFile(fs, manifestPath)
+	require.NoError(t, err)
+	mf = &schema.Manifest{}
+	require.NoError(t, json.Unmarshal(data, mf))
+	require.Equal(t, schema.ProgramVersion, mf.ProgramVersion)
+	require.NotNil(t, mf.Verification)
+}
+
+// Expectation: A changed PAR2 should reset the manifest and still be verified without --strict-par2.
+func Test_Service_Verify_Par2Changed_Lenient_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("tampered"), 0o644))
+
+	manifestPath := "/data/test" + schema.Par2Extension + schema.ManifestExtension
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	results, err := prog.Verify(t.Context(), []string{"/data"}, Options{})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, called)
+	require.Equal(t, 1, results.Success)
+	require.Contains(t, logBuf.String(), "resetting manifest")
+
+	hash, err := util.HashFile(fs, "/data/test"+schema.Par2Extension)
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, manifestPath)
+	require.NoError(t, err)
+	mf := &schema.Manifest{}
+	require.NoError(t, json.Unmarshal(data, mf))
+	require.Equal(t, hash, mf.SHA256)
+	require.NotNil(t, mf.Verification)
+	require.Equal(t, 1, mf.Verification.Count)
+}
+
+// Expectation: A changed PAR2 should fail the job without running par2 with --strict-par2.
+func Test_Service_Verify_Par2Changed_Strict_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("tampered"), 0o644))
+
+	manifestPath := "/data/test" + schema.Par2Extension + schema.ManifestExtension
+	before, err := afero.ReadFile(fs, manifestPath)
+	require.NoError(t, err)
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	results, err := prog.Verify(t.Context(), []string{"/data"}, Options{StrictPar2: true})
+	require.ErrorIs(t, err, schema.ErrExitPartialFailure)
+	require.ErrorIs(t, err, errPar2Changed)
+
+	require.Zero(t, called)
+	require.Equal(t, 1, results.Error)
+	require.Contains(t, logBuf.String(), "not verifying with --strict-par2")
+
+	after, err := afero.ReadFile(fs, manifestPath)
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+}
+
+// Expectation: An unchanged PAR2 should be verified as usual with --strict-par2.
+func Test_Service_Verify_Par2Unchanged_Strict_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	results, err := prog.Verify(t.Context(), []string{"/data"}, Options{StrictPar2: true})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, called)
+	require.Equal(t, 1, results.Success)
+}
+
+// Expectation: Each verification should append to the bounded window of recent durations.
+func Test_Service_Verify_RecentDurations_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+
+	manifestPath := "/data/test" + schema.Par2Extension + schema.ManifestExtension
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	for range schema.MaxRecentDurations + 1 {
+		_, err := prog.Verify(t.Context(), []string{"/data"}, Options{})
+		require.NoError(t, err)
+	}
+
+	data, err := afero.ReadFile(fs, manifestPath)
+	require.NoError(t, err)
+	mf := &schema.Manifest{}
+	require.NoError(t, json.Unmarshal(data, mf))
+	require.NotNil(t, mf.Verification)
+	require.Equal(t, schema.MaxRecentDurations+1, mf.Verification.Count)
+	require.Len(t, mf.Verification.RecentDurations, schema.MaxRecentDurations)
+	require.Equal(t, mf.Verification.Duration, mf.Verification.RecentDurations[schema.MaxRecentDurations-1])
+}
+
 // Expectation: The verification should not overwrite the creation manifest values.
 func Test_Service_RunVerify_KeepCreateManifest_Success(t *testing.T) {
 	t.Parallel()
@@ -3164,6 +4946,81 @@ func Test_Service_parseExitCode_UnhandledCode_Error(t *testing.T) {
 	require.Equal(t, 99, job.manifest.Verification.ExitCode)
 }
 
+// Expectation: The non-result exit codes should be returned with their classification.
+func Test_Service_parseExitCode_ClassifiedCodes_Error(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code     int
+		expected error
+	}{
+		{schema.Par2ExitCodeInvalidArguments, schema.ErrPar2Usage},
+		{schema.Par2ExitCodeCriticalData, schema.ErrPar2CriticalData},
+		{schema.Par2ExitCodeRepairFailed, schema.ErrPar2RepairFailed},
+		{schema.Par2ExitCodeFileIOError, schema.ErrPar2FileIO},
+		{schema.Par2ExitCodeLogicError, schema.ErrPar2Internal},
+		{schema.Par2ExitCodeMemoryError, schema.ErrPar2Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("code %d", tt.code), func(t *testing.T) {
+			t.Parallel()
+
+			ls := logging.Options{
+				Logout: io.Discard,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+
+			prog := NewService(afero.NewMemMapFs(), logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+			job := &Job{
+				manifest: &schema.Manifest{
+					Verification: &schema.VerificationManifest{},
+				},
+			}
+
+			exitErr := testutil.CreateExitError(t, t.Context(), tt.code)
+			err := prog.parseExitCode(job, exitErr)
+
+			require.ErrorIs(t, err, tt.expected)
+			require.ErrorIs(t, err, exitErr)
+			require.Equal(t, tt.code, job.manifest.Verification.ExitCode)
+			require.False(t, job.manifest.Verification.RepairNeeded)
+			require.Zero(t, job.manifest.Verification.CountCorrupted)
+		})
+	}
+}
+
+// Expectation: A par2 usage error should fail the run as a bad invocation.
+func Test_Service_Verify_Par2UsageError_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			return testutil.CreateExitError(t, ctx, schema.Par2ExitCodeInvalidArguments)
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+	results, err := prog.Verify(t.Context(), []string{"/data"}, Options{})
+	require.ErrorIs(t, err, schema.ErrPar2Usage)
+	require.Equal(t, schema.ExitCodeBadInvocation, schema.ExitCodeFor(err))
+
+	require.Equal(t, 1, results.Error)
+	require.Contains(t, logBuf.String(), "par2 usage error")
+}
+
 // Expectation: A backlog warning should be thrown when the backlog is growing.
 func Test_Service_considerBacklog_InsufficientCapacity_Success(t *testing.T) {
 	t.Parallel()
@@ -3238,11 +5095,45 @@ func Test_Service_considerBacklog_NoDuration_Success(t *testing.T) {
 
 	prog.considerBacklog(metas, args)
 
-	require.NotContains(t, logBuf.String(), "Backlog is growing indefinitely")
+	require.NotContains(t, logBuf.String(), "Backlog is growing indefinitely")
+}
+
+// Expectation: A warning should be logged when the first job has unknown duration.
+func Test_Service_considerDurations_FirstJobUnknownDuration_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("info")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	metas := []*JobMeta{
+		{
+			&schema.JobMeta{
+				HasManifest:     true,
+				HasVerification: true,
+				VerifyDuration:  0,
+			},
+		},
+	}
+
+	args := Options{}
+	_ = args.MaxDuration.Set("1h")
+
+	prog.considerDurations(metas, args)
+
+	require.Contains(t, logBuf.String(), "First job has (still) unknown duration")
 }
 
-// Expectation: A warning should be logged when the first job has unknown duration.
-func Test_Service_considerDurations_FirstJobUnknownDuration_Success(t *testing.T) {
+// Expectation: A warning should be logged when the first job exceeds max duration.
+func Test_Service_considerDurations_FirstJobExceedsDuration_Success(t *testing.T) {
 	t.Parallel()
 
 	fs := afero.NewMemMapFs()
@@ -3262,7 +5153,7 @@ func Test_Service_considerDurations_FirstJobUnknownDuration_Success(t *testing.T
 			&schema.JobMeta{
 				HasManifest:     true,
 				HasVerification: true,
-				VerifyDuration:  0,
+				VerifyDuration:  2 * time.Hour,
 			},
 		},
 	}
@@ -3272,11 +5163,11 @@ func Test_Service_considerDurations_FirstJobUnknownDuration_Success(t *testing.T
 
 	prog.considerDurations(metas, args)
 
-	require.Contains(t, logBuf.String(), "First job has (still) unknown duration")
+	require.Contains(t, logBuf.String(), "First job is estimated to exceed --duration")
 }
 
-// Expectation: A warning should be logged when the first job exceeds max duration.
-func Test_Service_considerDurations_FirstJobExceedsDuration_Success(t *testing.T) {
+// Expectation: No warning should be logged when only the last duration of the first job exceeds --duration.
+func Test_Service_considerDurations_FirstJobMedianDuration_Success(t *testing.T) {
 	t.Parallel()
 
 	fs := afero.NewMemMapFs()
@@ -3297,6 +5188,7 @@ func Test_Service_considerDurations_FirstJobExceedsDuration_Success(t *testing.T
 				HasManifest:     true,
 				HasVerification: true,
 				VerifyDuration:  2 * time.Hour,
+				RecentDurations: []time.Duration{10 * time.Minute, 10 * time.Minute, 2 * time.Hour},
 			},
 		},
 	}
@@ -3306,7 +5198,7 @@ func Test_Service_considerDurations_FirstJobExceedsDuration_Success(t *testing.T
 
 	prog.considerDurations(metas, args)
 
-	require.Contains(t, logBuf.String(), "First job is estimated to exceed --duration")
+	require.NotContains(t, logBuf.String(), "First job is estimated to exceed --duration")
 }
 
 // Expectation: A warning should be logged when subsequent jobs have unknown duration.
@@ -3448,3 +5340,535 @@ func Test_Service_considerDurations_NoJobs_Success(t *testing.T) {
 	prog.considerDurations(metas, args)
 	require.Empty(t, logBuf.String())
 }
+
+// Expectation: The exit codes should be classified as mapped, taking precedence over par2cmdline.
+func Test_Service_parseExitCode_Par2ExitCodes_Success(t *testing.T) {
+	t.Parallel()
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	prog := NewService(afero.NewMemMapFs(), logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	var mapped flags.Par2ExitCodes
+	require.NoError(t, mapped.Set("4=unrepairable"))
+	require.NoError(t, mapped.Set("1=usage-error"))
+
+	job := &Job{
+		manifest:      &schema.Manifest{Verification: &schema.VerificationManifest{}},
+		par2ExitCodes: mapped,
+	}
+
+	err := testutil.CreateExitError(t, t.Context(), schema.Par2ExitCodeCriticalData)
+	require.NoError(t, prog.parseExitCode(job, err))
+	require.Equal(t, schema.Par2ExitCodeCriticalData, job.manifest.Verification.ExitCode)
+	require.True(t, job.manifest.Verification.RepairNeeded)
+	require.False(t, job.manifest.Verification.RepairPossible)
+
+	err = testutil.CreateExitError(t, t.Context(), schema.Par2ExitCodeRepairPossible)
+	err = prog.parseExitCode(job, err)
+	require.ErrorIs(t, err, schema.ErrPar2Usage)
+	require.ErrorIs(t, err, schema.ErrExitBadInvocation)
+
+	job.par2ExitCodes = flags.Par2ExitCodes{}
+	err = testutil.CreateExitError(t, t.Context(), schema.Par2ExitCodeCriticalData)
+	require.ErrorIs(t, prog.parseExitCode(job, err), schema.ErrPar2CriticalData)
+}
+
+// Expectation: Up to --jobs verifications should run concurrently, with all of them accounted for.
+func Test_Service_Verify_Jobs_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	for _, name := range []string{"a", "b", "c"} {
+		createWithManifest(t, fs, "/data/"+name+"/test")
+	}
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	var calls, running, maxRunning atomic.Int32
+	bothStarted := make(chan struct{})
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				if m := maxRunning.Load(); n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+
+			// The first two jobs only finish once both are running concurrently.
+			switch calls.Add(1) {
+			case 1:
+				select {
+				case <-bothStarted:
+				case <-time.After(5 * time.Second):
+					return errors.New("jobs did not run concurrently")
+				}
+			case 2:
+				close(bothStarted)
+			}
+
+			return nil
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	args := Options{Jobs: 2}
+	res, err := prog.Verify(t.Context(), []string{"/data"}, args)
+	require.NoError(t, err)
+
+	require.Equal(t, int32(3), calls.Load())
+	require.Equal(t, int32(2), maxRunning.Load())
+	require.Equal(t, 3, res.Success)
+}
+
+// Expectation: The manifests read concurrently (--io-concurrency) should result in the same jobs in the same order.
+func Test_Service_Enumerate_IOConcurrency_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		createWithManifest(t, fs, "/data/"+name+"/test")
+	}
+	require.NoError(t, afero.WriteFile(fs, "/data/g/external"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	paths := func(opts Options) []string {
+		metas, err := prog.Enumerate(t.Context(), "/data", opts, &testutil.MockCache{})
+		require.NoError(t, err)
+
+		got := make([]string, 0, len(metas))
+		for _, meta := range metas {
+			got = append(got, meta.Par2Path)
+		}
+
+		return got
+	}
+
+	sequential := paths(Options{IncludeExternal: true})
+	require.Len(t, sequential, 7)
+	require.Equal(t, sequential, paths(Options{IncludeExternal: true, IOConcurrency: 4}))
+}
+
+// Expectation: The options should be refused with a negative concurrency.
+func Test_Options_Validate_Concurrency_Error(t *testing.T) {
+	t.Parallel()
+
+	opts := Options{Jobs: -1}
+	require.ErrorContains(t, opts.Validate(), "jobs")
+
+	opts = Options{IOConcurrency: -1}
+	require.ErrorContains(t, opts.Validate(), "io-concurrency")
+}
+
+// Expectation: An interruption mid-run should leave the unfinished jobs with their estimated durations in the results.
+func Test_Service_Verify_Interrupted_Remaining_Error(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	for i, name := range []string{"a", "b", "c"} {
+		mf := schema.NewManifest("test" + schema.Par2Extension)
+		mf.SHA256 = fmt.Sprintf("%x", sha256.Sum256([]byte("par2data")))
+		mf.Creation = &schema.CreationManifest{Time: time.Now()}
+		if i > 0 {
+			mf.Verification = &schema.VerificationManifest{
+				Time:     time.Now().Add(-time.Duration(i) * time.Hour),
+				Duration: time.Duration(i) * time.Minute,
+			}
+		}
+
+		by, err := json.Marshal(mf)
+		require.NoError(t, err)
+		require.NoError(t, fs.MkdirAll("/data/"+name, 0o755))
+		require.NoError(t, afero.WriteFile(fs, "/data/"+name+"/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+		require.NoError(t, afero.WriteFile(fs, "/data/"+name+"/test"+schema.Par2Extension+schema.ManifestExtension, by, 0o644))
+	}
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var called int
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			called++
+			cancel()
+
+			return ctx.Err()
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	res, err := prog.Verify(ctx, []string{"/data"}, Options{})
+	require.ErrorIs(t, err, context.Canceled)
+
+	require.Equal(t, 1, called)
+	require.Equal(t, 3, res.Selected)
+	require.Zero(t, res.Success+res.Error+res.Skipped)
+	require.Equal(t, 3*time.Minute, res.RemainingDuration)
+	require.Equal(t, 1, res.RemainingUnknown)
+}
+
+// Expectation: A corrupted verification should be accumulated into the health history, lowering the score.
+func Test_Service_RunVerify_Health_Corrupted_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte{}, 0o644))
+
+	ls := logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			return testutil.CreateExitError(t, ctx, schema.Par2ExitCodeRepairPossible)
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	job := &Job{
+		workingDir:   "/data",
+		par2Name:     "test" + schema.Par2Extension,
+		par2Path:     "/data/test" + schema.Par2Extension,
+		manifestName: "test" + schema.Par2Extension + schema.ManifestExtension,
+		manifestPath: "/data/test" + schema.Par2Extension + schema.ManifestExtension,
+		manifest:     schema.NewManifest("test" + schema.Par2Extension),
+	}
+	job.manifest.SHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	job.manifest.Health = &schema.HealthManifest{CountClean: 4, Score: schema.MaxHealthScore}
+
+	require.NoError(t, prog.RunVerify(t.Context(), job, false))
+
+	require.Equal(t, 4, job.manifest.Health.CountClean)
+	require.Equal(t, 1, job.manifest.Health.CountCorrupted)
+	require.Equal(t, schema.HealthScore(4, 1, 0), job.manifest.Health.Score)
+	require.Less(t, job.manifest.Health.Score, schema.MaxHealthScore)
+}
+
+// Expectation: A manifest name not matching its PAR2 set should be handled according to the policy.
+func Test_Service_Enumerate_NameMismatch_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		policy           string
+		noManifestUpdate bool
+		wantJobs         int
+		wantName         string
+		wantLog          string
+	}{
+		{"fix", schema.NameMismatchFix, false, 1, "renamed.par2", "Corrected manifest name"},
+		{"fix report-only", schema.NameMismatchFix, true, 1, "test", "not correcting; --no-manifest-update"},
+		{"skip", schema.NameMismatchSkip, false, 0, "test", "skipping; --name-mismatch skip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			createWithManifest(t, fs, "/data/test")
+			require.NoError(t, fs.Rename("/data/test"+schema.Par2Extension, "/data/renamed"+schema.Par2Extension))
+			require.NoError(t, fs.Rename("/data/test"+schema.Par2Extension+schema.ManifestExtension, "/data/renamed"+schema.Par2Extension+schema.ManifestExtension))
+
+			var logBuf testutil.SafeBuffer
+			ls := logging.Options{
+				Logout: &logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+			_ = ls.LogLevel.Set("debug")
+
+			prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+			args := Options{Par2Args: []string{"-v"}, NoManifestUpdate: tt.noManifestUpdate}
+			require.NoError(t, args.NameMismatch.Set(tt.policy))
+
+			jobs, err := prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
+			require.NoError(t, err)
+			require.Len(t, jobs, tt.wantJobs)
+			require.Contains(t, logBuf.String(), tt.wantLog)
+			require.Contains(t, logBuf.String(), schema.ReasonNameMismatch)
+
+			data, err := afero.ReadFile(fs, "/data/renamed"+schema.Par2Extension+schema.ManifestExtension)
+			require.NoError(t, err)
+			mf, err := util.UnmarshalManifest(data, 0)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantName, mf.Name)
+		})
+	}
+}
+
+// Expectation: A matching manifest name, or no policy at all, should not be acted upon.
+func Test_Service_Enumerate_NameMismatch_NotApplied_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+	createWithManifest(t, fs, "/other/test")
+	require.NoError(t, fs.Rename("/other/test"+schema.Par2Extension, "/other/renamed"+schema.Par2Extension))
+	require.NoError(t, fs.Rename("/other/test"+schema.Par2Extension+schema.ManifestExtension, "/other/renamed"+schema.Par2Extension+schema.ManifestExtension))
+
+	var logBuf testutil.SafeBuffer
+	ls := logging.Options{
+		Logout: &logBuf,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+	_ = ls.LogLevel.Set("debug")
+
+	prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	args := Options{Par2Args: []string{"-v"}}
+	require.NoError(t, args.NameMismatch.Set(schema.NameMismatchSkip))
+	jobs, err := prog.Enumerate(t.Context(), "/data", args, &testutil.MockCache{})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	jobs, err = prog.Enumerate(t.Context(), "/other", Options{Par2Args: []string{"-v"}}, &testutil.MockCache{})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.NotContains(t, logBuf.String(), schema.ReasonNameMismatch)
+}
+
+// Expectation: A set marked with no-auto-repair at creation is still verified.
+func Test_Service_Enumerate_NoAutoRepair_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	mf := schema.NewManifest("test" + schema.Par2Extension)
+	mf.SHA256 = fmt.Sprintf("%x", sha256.Sum256([]byte("par2data")))
+	mf.Creation = &schema.CreationManifest{Time: time.Now(), NoAutoRepair: true}
+	mf.Verification = &schema.VerificationManifest{
+		Time:           time.Now().Add(-48 * time.Hour),
+		RepairNeeded:   true,
+		RepairPossible: true,
+	}
+
+	by, err := json.Marshal(mf)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension, by, 0o644))
+
+	prog := NewService(fs, logging.NewLogger(logging.Options{Logout: io.Discard, Stdout: io.Discard, Stderr: io.Discard}), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	jobs, err := prog.Enumerate(t.Context(), "/data", Options{Par2Args: []string{"-v"}}, &testutil.MockCache{})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+}
+
+// Expectation: A failed verification should record its error (but not itself), and a later success should clear it.
+func Test_Service_Verify_LastError_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	createWithManifest(t, fs, "/data/test")
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	fail := true
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			if fail {
+				return testutil.CreateExitError(t, ctx, schema.Par2ExitCodeFileIOError)
+			}
+
+			return nil
+		},
+	}
+
+	readManifest := func() *schema.Manifest {
+		data, err := afero.ReadFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension)
+		require.NoError(t, err)
+
+		var mf schema.Manifest
+		require.NoError(t, json.Unmarshal(data, &mf))
+
+		return &mf
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	_, err := prog.Verify(t.Context(), []string{"/data"}, Options{})
+	require.ErrorIs(t, err, schema.ErrPar2FileIO)
+
+	mf := readManifest()
+	require.Contains(t, mf.LastError, schema.ErrPar2FileIO.Error())
+	require.Equal(t, "verify", mf.LastErrorOp)
+	require.False(t, mf.LastErrorAt.IsZero())
+	require.Nil(t, mf.Verification)
+	require.NotNil(t, mf.Creation)
+
+	fail = false
+	_, err = prog.Verify(t.Context(), []string{"/data"}, Options{})
+	require.NoError(t, err)
+
+	mf = readManifest()
+	require.Empty(t, mf.LastError)
+	require.Empty(t, mf.LastErrorOp)
+	require.True(t, mf.LastErrorAt.IsZero())
+	require.NotNil(t, mf.Verification)
+	require.Equal(t, 1, mf.Verification.Count)
+}
+
+// Expectation: A failed verification of an external PAR2 set should not create a manifest just for its error.
+func Test_Service_Verify_LastError_External_Success(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/data", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+
+	ls := logging.Options{
+		Logout: io.Discard,
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+	}
+
+	runner := &testutil.MockRunner{
+		RunFunc: func(ctx context.Context, cmd string, args []string, workingDir string, stdout io.Writer, stderr io.Writer) error {
+			return testutil.CreateExitError(t, ctx, schema.Par2ExitCodeFileIOError)
+		},
+	}
+
+	prog := NewService(fs, logging.NewLogger(ls), runner, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+	_, err := prog.Verify(t.Context(), []string{"/data"}, Options{IncludeExternal: true})
+	require.ErrorIs(t, err, schema.ErrPar2FileIO)
+
+	exists, err := afero.Exists(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+// Expectation: A run with nothing to do should tell an empty tree apart from an entirely filtered one,
+// counting the candidates dropped per reason of the filter.
+func Test_Service_Verify_NothingToDo_Table(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		setup  func(t *testing.T, fs afero.Fs, opts *Options)
+		reason string
+	}{
+		{"empty tree", func(t *testing.T, fs afero.Fs, opts *Options) {
+			t.Helper()
+			require.NoError(t, fs.MkdirAll("/data", 0o755))
+		}, ""},
+		{"no manifest", func(t *testing.T, fs afero.Fs, opts *Options) {
+			t.Helper()
+			require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+		}, schema.ReasonNoManifest},
+		{"ignore file", func(t *testing.T, fs afero.Fs, opts *Options) {
+			t.Helper()
+			createWithManifest(t, fs, "/data/test")
+			require.NoError(t, afero.WriteFile(fs, "/data/"+schema.IgnoreFile, nil, 0o644))
+		}, schema.ReasonIgnoreFile},
+		{"max depth", func(t *testing.T, fs afero.Fs, opts *Options) {
+			t.Helper()
+			createWithManifest(t, fs, "/data/a/b/test")
+			require.NoError(t, opts.MaxDepth.Set("1"))
+		}, schema.ReasonMaxDepth},
+		{"skip not created", func(t *testing.T, fs afero.Fs, opts *Options) {
+			t.Helper()
+			data, err := json.Marshal(schema.NewManifest("test" + schema.Par2Extension))
+			require.NoError(t, err)
+			require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension, data, 0o644))
+			opts.SkipNotCreated = true
+		}, schema.ReasonSkipNotCreated},
+		{"tag mismatch", func(t *testing.T, fs afero.Fs, opts *Options) {
+			t.Helper()
+			createWithManifest(t, fs, "/data/test")
+			require.NoError(t, opts.Tags.Set("critical"))
+		}, schema.ReasonTagMismatch},
+		{"created out of range", func(t *testing.T, fs afero.Fs, opts *Options) {
+			t.Helper()
+			createWithManifest(t, fs, "/data/test")
+			require.NoError(t, opts.CreatedBefore.Set("2000-01-01"))
+		}, schema.ReasonCreatedRange},
+		{"not due", func(t *testing.T, fs afero.Fs, opts *Options) {
+			t.Helper()
+			mf := schema.NewManifest("test" + schema.Par2Extension)
+			mf.Creation = schema.NewCreationManifest()
+			mf.Verification = schema.NewVerificationManifest()
+			mf.Verification.Time = time.Now()
+			data, err := json.Marshal(mf)
+			require.NoError(t, err)
+			require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension, []byte("par2data"), 0o644))
+			require.NoError(t, afero.WriteFile(fs, "/data/test"+schema.Par2Extension+schema.ManifestExtension, data, 0o644))
+			require.NoError(t, opts.MinAge.Set("24h"))
+		}, schema.ReasonNotDue},
+		{"unchanged since", func(t *testing.T, fs afero.Fs, opts *Options) {
+			t.Helper()
+			createWithManifest(t, fs, "/data/test")
+			past := time.Now().Add(-time.Hour)
+			require.NoError(t, fs.Chtimes("/data/test"+schema.Par2Extension, past, past))
+			require.NoError(t, afero.WriteFile(fs, "/ref", nil, 0o644))
+			opts.ChangedSince = "/ref"
+		}, schema.ReasonUnchanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			opts := Options{}
+			tt.setup(t, fs, &opts)
+
+			var logBuf testutil.SafeBuffer
+			ls := logging.Options{
+				Logout: &logBuf,
+				Stdout: io.Discard,
+				Stderr: io.Discard,
+			}
+			_ = ls.LogLevel.Set("info")
+
+			prog := NewService(fs, logging.NewLogger(ls), &testutil.MockRunner{}, &util.BundleHandler{}, &testutil.MockCacheHandler{})
+
+			res, err := prog.Verify(t.Context(), []string{"/data"}, opts)
+			require.NoError(t, err)
+			require.Zero(t, res.Selected)
+
+			if tt.reason == "" {
+				require.Zero(t, res.Filtered.Total())
+				require.Contains(t, logBuf.String(), "no PAR2 sets found")
+
+				return
+			}
+			require.Equal(t, 1, res.Filtered.Total())
+			require.Equal(t, 1, res.Filtered.Count(tt.reason))
+			require.Contains(t, logBuf.String(), "all 1 candidates filtered")
+			require.Contains(t, logBuf.String(), "filtered."+tt.reason+"=1")
+		})
+	}
+}